@@ -7,16 +7,19 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/grocery-service/internal/api"
 	handler "github.com/grocery-service/internal/api/handlers"
+	customMiddleware "github.com/grocery-service/internal/api/middleware"
 	"github.com/grocery-service/internal/config"
 	"github.com/grocery-service/internal/repository/db"
 	"github.com/grocery-service/internal/repository/postgres"
 	"github.com/grocery-service/internal/service"
 	"github.com/grocery-service/internal/service/notification"
+	"github.com/grocery-service/utils/ratelimit"
 )
 
 // @title           Grocery Service API
@@ -46,11 +49,41 @@ func main() {
 	productRepo := postgres.NewProductRepository(database)
 	categoryRepo := postgres.NewCategoryRepository(database)
 	orderRepo := postgres.NewOrderRepository(database)
-	tokenRepo := postgres.NewTokenRepository(database)
+	tokenRepo := postgres.NewTokenRepository(
+		database,
+		cfg.JWT.TokenHashKey,
+	)
+	cartRepo := postgres.NewCartRepository(database)
+	outboxRepo := postgres.NewOutboxRepository(database)
+	stockMovementRepo := postgres.NewStockMovementRepository(database)
+	lockRepo := postgres.NewLockRepository(database)
+	roleRepo := postgres.NewRoleRepository(database)
+	mfaRepo := postgres.NewMFARepository(database, cfg.JWT.TokenHashKey)
+	apiKeyRepo := postgres.NewAPIKeyRepository(
+		database,
+		cfg.JWT.TokenHashKey,
+	)
+	idempotencyRepo := postgres.NewIdempotencyRepository(database)
+
+	orderStateMachine, err := service.LoadOrderStateMachine(cfg.Order)
+	if err != nil {
+		log.Fatalf("Failed to load order state machine: %v", err)
+	}
 
 	// Initialize services
 	notificationService := initializeNotificationService(cfg)
-	authService := service.NewAuthService(*cfg, userRepo, tokenRepo, []string{})
+	authService, err := service.NewAuthService(
+		*cfg,
+		userRepo,
+		tokenRepo,
+		roleRepo,
+		mfaRepo,
+		notification.NewEmailService(cfg.SMTP, cfg.Alert.AdminEmails),
+		[]string{},
+	)
+	if err != nil {
+		log.Fatalf("Failed to initialize auth service: %v", err)
+	}
 	customerService := service.NewCustomerService(customerRepo, userRepo)
 	productService := service.NewProductService(productRepo, categoryRepo)
 	categoryService := service.NewCategoryService(categoryRepo)
@@ -58,8 +91,76 @@ func main() {
 		orderRepo,
 		productRepo,
 		customerRepo,
-		notificationService,
+		orderStateMachine,
+	)
+	cartService := service.NewCartService(
+		cartRepo,
+		productRepo,
+		customerRepo,
+		orderService,
+	)
+	outboxService := service.NewOutboxService(outboxRepo)
+	inventoryService := service.NewInventoryService(
+		stockMovementRepo,
+		productRepo,
+	)
+	roleService := service.NewRoleService(roleRepo)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, userRepo, roleRepo)
+	idempotencyService := service.NewIdempotencyService(
+		idempotencyRepo,
+		cfg.Idempotency,
+	)
+
+	// Start the background workers
+	ctx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	if cfg.Outbox.Enabled {
+		dispatcher := service.NewOutboxDispatcher(
+			outboxRepo,
+			orderRepo,
+			customerRepo,
+			notificationService,
+			cfg.Outbox,
+		)
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			dispatcher.Run(ctx)
+		}()
+	}
+	rateLimits := initializeRateLimits(cfg.RateLimit, database)
+	if rateLimits.Store != nil {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			ratelimit.RunPruner(
+				ctx,
+				rateLimits.Store,
+				cfg.RateLimit.PruneInterval,
+			)
+		}()
+	}
+	if cfg.TokenCleanup.Enabled {
+		tokenCleanup := service.NewTokenCleanup(
+			tokenRepo,
+			lockRepo,
+			cfg.TokenCleanup,
+		)
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			tokenCleanup.Run(ctx)
+		}()
+	}
+	idempotencyCleanup := service.NewIdempotencyCleanup(
+		idempotencyRepo,
+		cfg.Idempotency,
 	)
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		idempotencyCleanup.Run(ctx)
+	}()
 
 	// Initialize API handlers
 	handlers := initializeHandlers(
@@ -68,7 +169,19 @@ func main() {
 		productService,
 		categoryService,
 		orderService,
+		cartService,
+		outboxService,
+		inventoryService,
+		roleService,
+		apiKeyService,
+	)
+
+	trustedProxies, err := customMiddleware.ParseTrustedProxies(
+		cfg.Server.TrustedProxies,
 	)
+	if err != nil {
+		log.Fatalf("Failed to load trusted proxies: %v", err)
+	}
 
 	// Initialize router with middleware
 	router := api.NewRouter(
@@ -77,29 +190,89 @@ func main() {
 		handlers.productHandler,
 		handlers.categoryHandler,
 		handlers.orderHandler,
+		handlers.cartHandler,
+		handlers.outboxHandler,
+		handlers.inventoryHandler,
+		handlers.roleHandler,
+		handlers.apiKeyHandler,
 		authService,
+		apiKeyService,
+		idempotencyService,
+		cfg.MFA.StepUpMaxAge,
+		rateLimits,
+		trustedProxies,
 	)
 
 	startServer(router, cfg.Server.Port)
+
+	stopWorkers()
+	workers.Wait()
 }
 
 type handlers struct {
-	authHandler     *handler.AuthHandler
-	customerHandler *handler.CustomerHandler
-	productHandler  *handler.ProductHandler
-	categoryHandler *handler.CategoryHandler
-	orderHandler    *handler.OrderHandler
+	authHandler      *handler.AuthHandler
+	customerHandler  *handler.CustomerHandler
+	productHandler   *handler.ProductHandler
+	categoryHandler  *handler.CategoryHandler
+	orderHandler     *handler.OrderHandler
+	cartHandler      *handler.CartHandler
+	outboxHandler    *handler.OutboxHandler
+	inventoryHandler *handler.InventoryHandler
+	roleHandler      *handler.RoleHandler
+	apiKeyHandler    *handler.APIKeyHandler
 }
 
 func initializeNotificationService(
 	cfg *config.Config,
 ) notification.NotificationService {
-	smsService := notification.NewSMSService(cfg.SMS)
-	emailService := notification.NewEmailService(cfg.SMTP)
-	return notification.NewCompositeNotificationService(
-		smsService,
-		emailService,
-	)
+	services := []notification.NotificationService{
+		notification.NewSMSService(cfg.SMS),
+		notification.NewEmailService(cfg.SMTP, cfg.Alert.AdminEmails),
+	}
+	if cfg.Alert.WebhookURL != "" {
+		services = append(
+			services,
+			notification.NewWebhookService(cfg.Alert.WebhookURL),
+		)
+	}
+	return notification.NewCompositeNotificationService(services...)
+}
+
+// initializeRateLimits builds the rate limit policies and their store.
+// Without a store rate limiting is off.
+func initializeRateLimits(
+	cfg config.RateLimitConfig,
+	database *db.PostgresDB,
+) customMiddleware.RateLimits {
+	rateLimits := customMiddleware.RateLimits{
+		IP: ratelimit.Policy{
+			Name:   "ip",
+			Limit:  cfg.IPRequests,
+			Window: cfg.IPWindow,
+		},
+		Default: ratelimit.Policy{
+			Name:   "default",
+			Limit:  cfg.Requests,
+			Window: cfg.Window,
+		},
+		Auth: ratelimit.Policy{
+			Name:   "auth",
+			Limit:  cfg.AuthRequests,
+			Window: cfg.AuthWindow,
+		},
+	}
+
+	if !cfg.Enabled {
+		return rateLimits
+	}
+
+	if cfg.Store == "postgres" {
+		rateLimits.Store = postgres.NewRateLimitRepository(database)
+	} else {
+		rateLimits.Store = ratelimit.NewMemoryStore()
+	}
+
+	return rateLimits
 }
 
 func initializeHandlers(
@@ -108,13 +281,23 @@ func initializeHandlers(
 	productService service.ProductService,
 	categoryService service.CategoryService,
 	orderService service.OrderService,
+	cartService service.CartService,
+	outboxService service.OutboxService,
+	inventoryService service.InventoryService,
+	roleService service.RoleService,
+	apiKeyService service.APIKeyService,
 ) *handlers {
 	return &handlers{
-		authHandler:     handler.NewAuthHandler(authService),
-		customerHandler: handler.NewCustomerHandler(customerService),
-		productHandler:  handler.NewProductHandler(productService),
-		categoryHandler: handler.NewCategoryHandler(categoryService),
-		orderHandler:    handler.NewOrderHandler(orderService),
+		authHandler:      handler.NewAuthHandler(authService),
+		customerHandler:  handler.NewCustomerHandler(customerService),
+		productHandler:   handler.NewProductHandler(productService),
+		categoryHandler:  handler.NewCategoryHandler(categoryService),
+		orderHandler:     handler.NewOrderHandler(orderService, customerService),
+		cartHandler:      handler.NewCartHandler(cartService),
+		outboxHandler:    handler.NewOutboxHandler(outboxService),
+		inventoryHandler: handler.NewInventoryHandler(inventoryService),
+		roleHandler:      handler.NewRoleHandler(roleService),
+		apiKeyHandler:    handler.NewAPIKeyHandler(apiKeyService),
 	}
 }
 
@@ -123,7 +306,7 @@ func startServer(handler http.Handler, port int) {
 		Addr:         fmt.Sprintf(":%d", port),
 		Handler:      handler,
 		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
+		WriteTimeout: config.WriteTimeout,
 		IdleTimeout:  60 * time.Second,
 	}
 
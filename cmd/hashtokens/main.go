@@ -0,0 +1,58 @@
+// Command hashtokens hashes the tokens stored in plaintext before tokens
+// were hashed at rest, so existing sessions survive the upgrade. Run it
+// after migration 14 and before migration 15, which drops any token left
+// unhashed.
+//
+//	go run ./cmd/hashtokens
+//	go run ./cmd/hashtokens -batch 500
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/grocery-service/internal/config"
+	"github.com/grocery-service/internal/repository/db"
+	"github.com/grocery-service/internal/repository/postgres"
+)
+
+func main() {
+	batchSize := flag.Int(
+		"batch",
+		1000,
+		"number of tokens hashed per transaction",
+	)
+	flag.Parse()
+
+	if *batchSize <= 0 {
+		log.Fatal("-batch must be positive")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	database, err := db.NewPostgresDB(&cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer database.Close()
+
+	tokenRepo := postgres.NewTokenRepository(
+		database,
+		cfg.JWT.TokenHashKey,
+	)
+
+	hashed, err := tokenRepo.HashLegacyTokens(
+		context.Background(),
+		*batchSize,
+	)
+	if err != nil {
+		log.Fatalf("Failed to hash tokens after %d token(s): %v", hashed, err)
+	}
+
+	fmt.Printf("Hashed %d token(s)\n", hashed)
+}
@@ -0,0 +1,43 @@
+// Command orderstates prints the order status state machine as a
+// Mermaid or Graphviz diagram. It reads ORDER_TRANSITIONS_FILE like the
+// API does, so the diagram matches the deployed configuration.
+//
+//	go run ./cmd/orderstates -format mermaid > docs/order-states.mmd
+//	go run ./cmd/orderstates -format dot | dot -Tsvg > order-states.svg
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/grocery-service/internal/config"
+	"github.com/grocery-service/internal/service"
+)
+
+func main() {
+	format := flag.String("format", "mermaid", "diagram format: mermaid or dot")
+	file := flag.String(
+		"transitions",
+		os.Getenv("ORDER_TRANSITIONS_FILE"),
+		"JSON transitions file (defaults to the built-in lifecycle)",
+	)
+	flag.Parse()
+
+	machine, err := service.LoadOrderStateMachine(
+		config.OrderConfig{TransitionsFile: *file},
+	)
+	if err != nil {
+		log.Fatalf("Failed to load order state machine: %v", err)
+	}
+
+	switch *format {
+	case "mermaid":
+		fmt.Print(machine.Mermaid())
+	case "dot":
+		fmt.Print(machine.Graphviz())
+	default:
+		log.Fatalf("Unknown format %q, expected mermaid or dot", *format)
+	}
+}
@@ -0,0 +1,74 @@
+// Command reconcilestock compares the cached stock of every product with
+// the sum of its stock movements and reports any drift. It exits with
+// status 1 when drift is found, so it can run as a scheduled check. With
+// -fix the cached stock is reset to the ledger instead.
+//
+//	go run ./cmd/reconcilestock
+//	go run ./cmd/reconcilestock -fix
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/grocery-service/internal/config"
+	"github.com/grocery-service/internal/repository/db"
+	"github.com/grocery-service/internal/repository/postgres"
+	"github.com/grocery-service/internal/service"
+)
+
+func main() {
+	fix := flag.Bool(
+		"fix",
+		false,
+		"reset drifted products to the stock implied by the ledger",
+	)
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	database, err := db.NewPostgresDB(&cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer database.Close()
+
+	inventoryService := service.NewInventoryService(
+		postgres.NewStockMovementRepository(database),
+		postgres.NewProductRepository(database),
+	)
+
+	drift, err := inventoryService.Reconcile(context.Background(), *fix)
+	if err != nil {
+		log.Fatalf("Failed to reconcile stock: %v", err)
+	}
+
+	if len(drift) == 0 {
+		fmt.Println("Stock matches the ledger for every product")
+		return
+	}
+
+	for _, d := range drift {
+		fmt.Printf(
+			"%s\t%s\tcached=%d\tledger=%d\n",
+			d.ProductID,
+			d.Name,
+			d.CachedStock,
+			d.LedgerStock,
+		)
+	}
+
+	if *fix {
+		fmt.Printf("Reconciled %d product(s)\n", len(drift))
+		return
+	}
+
+	fmt.Printf("%d product(s) drifted from the ledger\n", len(drift))
+	os.Exit(1)
+}
@@ -207,49 +207,49 @@ func seedProducts(ctx context.Context, repo postgres.ProductRepository) error {
 		id          uuid.UUID
 		name        string
 		description string
-		price       float64
+		price       int64 // cents
 		stock       int
 		categoryID  uuid.UUID
 	}{
 		// Fruits
-		{bananaID, "Banana", "Fresh bananas from Ecuador", 0.99, 100, fruitsID},
-		{appleID, "Apple", "Red delicious apples", 0.75, 150, fruitsID},
-		{orangeID, "Orange", "Sweet navel oranges", 1.29, 120, fruitsID},
+		{bananaID, "Banana", "Fresh bananas from Ecuador", 99, 100, fruitsID},
+		{appleID, "Apple", "Red delicious apples", 75, 150, fruitsID},
+		{orangeID, "Orange", "Sweet navel oranges", 129, 120, fruitsID},
 
 		// Vegetables
-		{carrotID, "Carrot", "Organic carrots", 1.99, 80, vegetablesID},
-		{tomatoID, "Tomato", "Vine-ripened tomatoes", 2.49, 90, vegetablesID},
-		{spinachID, "Spinach", "Fresh baby spinach", 3.99, 50, vegetablesID},
+		{carrotID, "Carrot", "Organic carrots", 199, 80, vegetablesID},
+		{tomatoID, "Tomato", "Vine-ripened tomatoes", 249, 90, vegetablesID},
+		{spinachID, "Spinach", "Fresh baby spinach", 399, 50, vegetablesID},
 
 		// Herbs
-		{basilID, "Basil", "Fresh basil leaves", 2.99, 40, herbsID},
-		{cilantroID, "Cilantro", "Fresh cilantro bunch", 1.99, 45, herbsID},
-		{mintID, "Mint", "Fresh mint leaves", 2.49, 35, herbsID},
+		{basilID, "Basil", "Fresh basil leaves", 299, 40, herbsID},
+		{cilantroID, "Cilantro", "Fresh cilantro bunch", 199, 45, herbsID},
+		{mintID, "Mint", "Fresh mint leaves", 249, 35, herbsID},
 
 		// Milk
-		{wholeMilkID, "Whole Milk", "Fresh whole milk, 1 gallon", 3.99, 40, milkID},
-		{heavyCreamID, "Heavy Cream", "Fresh heavy cream", 4.29, 25, milkID},
-		{halfAndHalfID, "Half & Half", "Fresh half & half", 3.49, 30, milkID},
+		{wholeMilkID, "Whole Milk", "Fresh whole milk, 1 gallon", 399, 40, milkID},
+		{heavyCreamID, "Heavy Cream", "Fresh heavy cream", 429, 25, milkID},
+		{halfAndHalfID, "Half & Half", "Fresh half & half", 349, 30, milkID},
 
 		// Cheese
-		{cheddarID, "Cheddar", "Sharp cheddar cheese", 5.99, 30, cheeseID},
-		{mozzarellaID, "Mozzarella", "Fresh mozzarella", 4.99, 35, cheeseID},
-		{swissID, "Swiss", "Swiss cheese", 6.99, 25, cheeseID},
+		{cheddarID, "Cheddar", "Sharp cheddar cheese", 599, 30, cheeseID},
+		{mozzarellaID, "Mozzarella", "Fresh mozzarella", 499, 35, cheeseID},
+		{swissID, "Swiss", "Swiss cheese", 699, 25, cheeseID},
 
 		// Yogurt
-		{greekYogurtID, "Greek Yogurt", "Plain greek yogurt", 1.99, 45, yogurtID},
-		{vanillaYogurtID, "Vanilla Yogurt", "Vanilla flavored yogurt", 2.49, 40, yogurtID},
-		{strawberryYogurtID, "Strawberry Yogurt", "Strawberry yogurt", 2.49, 40, yogurtID},
+		{greekYogurtID, "Greek Yogurt", "Plain greek yogurt", 199, 45, yogurtID},
+		{vanillaYogurtID, "Vanilla Yogurt", "Vanilla flavored yogurt", 249, 40, yogurtID},
+		{strawberryYogurtID, "Strawberry Yogurt", "Strawberry yogurt", 249, 40, yogurtID},
 
 		// Bread
-		{wholeWheatID, "Whole Wheat", "Whole wheat bread", 3.49, 40, breadID},
-		{sourdoughID, "Sourdough", "Fresh sourdough loaf", 4.99, 30, breadID},
-		{ryeBreadID, "Rye Bread", "Fresh rye bread", 4.49, 25, breadID},
+		{wholeWheatID, "Whole Wheat", "Whole wheat bread", 349, 40, breadID},
+		{sourdoughID, "Sourdough", "Fresh sourdough loaf", 499, 30, breadID},
+		{ryeBreadID, "Rye Bread", "Fresh rye bread", 449, 25, breadID},
 
 		// Pastry
-		{croissantsID, "Croissants", "Butter croissants, 4 pack", 6.99, 20, pastryID},
-		{danishID, "Danish", "Assorted danish pastries", 5.99, 25, pastryID},
-		{muffinsID, "Muffins", "Blueberry muffins, 4 pack", 5.99, 30, pastryID},
+		{croissantsID, "Croissants", "Butter croissants, 4 pack", 699, 20, pastryID},
+		{danishID, "Danish", "Assorted danish pastries", 599, 25, pastryID},
+		{muffinsID, "Muffins", "Blueberry muffins, 4 pack", 599, 30, pastryID},
 	}
 
 	for _, p := range products {
@@ -257,7 +257,7 @@ func seedProducts(ctx context.Context, repo postgres.ProductRepository) error {
 			ID:          p.id,
 			Name:        p.name,
 			Description: p.description,
-			Price:       p.price,
+			Price:       domain.NewMoney(p.price, domain.DefaultCurrency),
 			Stock:       p.stock,
 			CategoryID:  p.categoryID,
 		}
@@ -0,0 +1,55 @@
+// Command setrole gives the user with an email address a role. Use it to
+// promote the first admin, who can then manage roles through the admin
+// API. The user must have signed in once.
+//
+//	go run ./cmd/setrole -email owner@example.com -role admin
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/grocery-service/internal/config"
+	"github.com/grocery-service/internal/domain"
+	"github.com/grocery-service/internal/repository/db"
+	"github.com/grocery-service/internal/repository/postgres"
+)
+
+func main() {
+	email := flag.String("email", "", "email address of the user")
+	role := flag.String("role", string(domain.AdminRole), "role to assign")
+	flag.Parse()
+
+	if *email == "" {
+		log.Fatal("-email is required")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	database, err := db.NewPostgresDB(&cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer database.Close()
+
+	ctx := context.Background()
+	user, err := postgres.NewUserRepository(database).GetByEmail(ctx, *email)
+	if err != nil {
+		log.Fatalf("Failed to find user %s: %v", *email, err)
+	}
+
+	if err := postgres.NewRoleRepository(database).AssignRole(
+		ctx,
+		user.ID.String(),
+		domain.UserRole(*role),
+	); err != nil {
+		log.Fatalf("Failed to assign role: %v", err)
+	}
+
+	fmt.Printf("%s now has the %s role\n", *email, *role)
+}
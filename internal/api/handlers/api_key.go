@@ -0,0 +1,245 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/grocery-service/internal/api/middleware"
+	"github.com/grocery-service/internal/domain"
+	"github.com/grocery-service/internal/service"
+	"github.com/grocery-service/utils/api"
+	customErrors "github.com/grocery-service/utils/errors"
+)
+
+type APIKeyHandler struct {
+	service service.APIKeyService
+}
+
+func NewAPIKeyHandler(service service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{service: service}
+}
+
+// @Summary List API keys
+// @Description List API keys with their permissions, expiry and last use. The keys themselves are never shown again after creation.
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} api.Response{data=[]domain.APIKey}
+// @Failure 401 {object} api.Response
+// @Failure 403 {object} api.Response
+// @Failure 500 {object} api.Response
+// @Router /admin/api-keys [get]
+func (h *APIKeyHandler) List(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	keys, err := h.service.List(r.Context())
+	if err != nil {
+		h.handleError(w, err, "Failed to list API keys")
+		return
+	}
+
+	h.respond(w, keys, http.StatusOK)
+}
+
+// @Summary Get API key
+// @Description Get an API key with its permissions, expiry and last use
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Param id path string true "API key ID" format(uuid)
+// @Success 200 {object} api.Response{data=domain.APIKey}
+// @Failure 401 {object} api.Response
+// @Failure 403 {object} api.Response
+// @Failure 404 {object} api.Response
+// @Failure 500 {object} api.Response
+// @Router /admin/api-keys/{id} [get]
+func (h *APIKeyHandler) GetByID(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	key, err := h.service.GetByID(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		h.handleError(w, err, "Failed to get API key")
+		return
+	}
+
+	h.respond(w, key, http.StatusOK)
+}
+
+// @Summary Create API key
+// @Description Issue an API key for a machine client, sent in the X-API-Key header. The key is shown only in this response. It can be granted only permissions the caller holds, and never users:manage, roles:manage or api_keys:manage.
+// @Tags admin
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param api_key body domain.APIKeyRequest true "API key"
+// @Success 201 {object} api.Response{data=domain.NewAPIKey}
+// @Failure 400 {object} api.Response
+// @Failure 401 {object} api.Response
+// @Failure 403 {object} api.Response
+// @Failure 500 {object} api.Response
+// @Router /admin/api-keys [post]
+func (h *APIKeyHandler) Create(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	var request domain.APIKeyRequest
+	if !h.decode(w, r, &request) {
+		return
+	}
+
+	createdBy, _ := r.Context().Value(middleware.UserIDKey).(string)
+	key, err := h.service.Create(r.Context(), createdBy, request)
+	if err != nil {
+		h.handleError(w, err, "Failed to create API key")
+		return
+	}
+
+	h.respond(w, key, http.StatusCreated)
+}
+
+// @Summary Update API key
+// @Description Replace an API key's name, expiry and permissions. The caller must hold every permission the key has before and after the change, and the key can never hold users:manage, roles:manage or api_keys:manage.
+// @Tags admin
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path string true "API key ID" format(uuid)
+// @Param api_key body domain.APIKeyRequest true "API key"
+// @Success 200 {object} api.Response{data=domain.APIKey}
+// @Failure 400 {object} api.Response
+// @Failure 401 {object} api.Response
+// @Failure 403 {object} api.Response
+// @Failure 404 {object} api.Response
+// @Failure 500 {object} api.Response
+// @Router /admin/api-keys/{id} [put]
+func (h *APIKeyHandler) Update(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	var request domain.APIKeyRequest
+	if !h.decode(w, r, &request) {
+		return
+	}
+
+	updatedBy, _ := r.Context().Value(middleware.UserIDKey).(string)
+	key, err := h.service.Update(
+		r.Context(),
+		updatedBy,
+		chi.URLParam(r, "id"),
+		request,
+	)
+	if err != nil {
+		h.handleError(w, err, "Failed to update API key")
+		return
+	}
+
+	h.respond(w, key, http.StatusOK)
+}
+
+// @Summary Revoke API key
+// @Description Delete an API key. Requests with it fail from then on.
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Param id path string true "API key ID" format(uuid)
+// @Success 200 {object} api.Response
+// @Failure 401 {object} api.Response
+// @Failure 403 {object} api.Response
+// @Failure 404 {object} api.Response
+// @Failure 500 {object} api.Response
+// @Router /admin/api-keys/{id} [delete]
+func (h *APIKeyHandler) Delete(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	if err := h.service.Delete(
+		r.Context(),
+		chi.URLParam(r, "id"),
+	); err != nil {
+		h.handleError(w, err, "Failed to revoke API key")
+		return
+	}
+
+	h.respond(w, nil, http.StatusOK)
+}
+
+func (h *APIKeyHandler) decode(
+	w http.ResponseWriter,
+	r *http.Request,
+	v interface{},
+) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		if err := api.ErrorResponse(
+			w,
+			"Invalid request body",
+			http.StatusBadRequest,
+		); err != nil {
+			http.Error(
+				w,
+				"Failed to send error response",
+				http.StatusInternalServerError,
+			)
+		}
+		return false
+	}
+	return true
+}
+
+func (h *APIKeyHandler) handleError(
+	w http.ResponseWriter,
+	err error,
+	fallback string,
+) {
+	var sendErr error
+
+	switch {
+	case errors.Is(err, customErrors.ErrInvalidAPIKeyData):
+		sendErr = api.ErrorResponse(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, customErrors.ErrAPIKeyPermissionDenied):
+		sendErr = api.ErrorResponse(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, customErrors.ErrAPIKeyNotFound):
+		sendErr = api.ErrorResponse(
+			w,
+			"API key not found",
+			http.StatusNotFound,
+		)
+	default:
+		sendErr = api.ErrorResponse(
+			w,
+			fallback,
+			http.StatusInternalServerError,
+		)
+	}
+
+	if sendErr != nil {
+		http.Error(
+			w,
+			"Failed to send error response",
+			http.StatusInternalServerError,
+		)
+	}
+}
+
+func (h *APIKeyHandler) respond(
+	w http.ResponseWriter,
+	data interface{},
+	status int,
+) {
+	if err := api.SuccessResponse(w, data, status); err != nil {
+		if err := api.ErrorResponse(
+			w,
+			"Failed to send response",
+			http.StatusInternalServerError,
+		); err != nil {
+			http.Error(
+				w,
+				"Failed to send error response",
+				http.StatusInternalServerError,
+			)
+		}
+	}
+}
@@ -2,14 +2,17 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/grocery-service/internal/domain"
 	"github.com/grocery-service/internal/service"
 	"github.com/grocery-service/utils/api"
+	customErrors "github.com/grocery-service/utils/errors"
 )
 
 type AuthHandler struct {
@@ -28,16 +31,42 @@ func (h *AuthHandler) Routes() chi.Router {
 	r.Get("/login", h.Login)
 	r.Get("/callback", h.Callback)
 	r.Post("/refresh", h.RefreshToken)
+	r.Post("/register", h.Register)
+	r.Post("/login/password", h.PasswordLogin)
+	r.Post("/verify-email", h.VerifyEmail)
+	r.Post("/verify-email/resend", h.ResendVerification)
+	r.Post("/password/forgot", h.ForgotPassword)
+	r.Post("/password/reset", h.ResetPassword)
+	r.Post("/mfa/verify", h.VerifyMFA)
 	r.Post("/revoke", h.RevokeToken)
+	r.Get("/sessions", h.ListSessions)
+	r.Delete("/sessions/{id}", h.RevokeSession)
+	r.Post("/logout-all", h.LogoutAll)
+	r.Get("/mfa", h.GetMFAStatus)
+	r.Post("/mfa/enroll", h.EnrollMFA)
+	r.Post("/mfa/enroll/confirm", h.ConfirmMFA)
+	r.Post("/mfa/recovery-codes", h.RegenerateRecoveryCodes)
+	r.Post("/mfa/disable", h.DisableMFA)
+	r.Post("/mfa/step-up", h.StepUp)
 
 	return r
 }
 
+// The login session cookie carries a login from /auth/login to
+// /auth/callback.
+const (
+	loginCookie     = "oidc_login"
+	loginCookiePath = "/api/v1/auth"
+)
+
 // @Summary OpenID Connect login
-// @Description Redirect to OpenID provider login page
+// @Description Redirect to the OpenID provider login page
 // @Tags auth
 // @Produce json
+// @Param provider query string false "Provider name (default provider when omitted)"
+// @Param redirect_to query string false "Where to resume after login: a relative path or an allowed URL"
 // @Success 302
+// @Failure 400 {object} api.Response
 // @Router /auth/login [get]
 func (h *AuthHandler) Login(
 	w http.ResponseWriter,
@@ -46,14 +75,51 @@ func (h *AuthHandler) Login(
 	// Set content negotiation headers
 	w.Header().Set("Accept", "text/html,application/json")
 
-	// Get the auth URL
-	authURL := h.service.GetAuthURL()
+	authRequest, err := h.service.GetAuthURL(
+		r.Context(),
+		domain.LoginRequest{
+			Provider:   r.URL.Query().Get("provider"),
+			RedirectTo: r.URL.Query().Get("redirect_to"),
+		},
+	)
+	if err != nil {
+		statusCode := http.StatusBadGateway
+		message := "Failed to start login"
+		switch {
+		case errors.Is(err, customErrors.ErrUnknownProvider):
+			statusCode = http.StatusBadRequest
+			message = "Unknown identity provider"
+		case errors.Is(err, customErrors.ErrInvalidRedirect):
+			statusCode = http.StatusBadRequest
+			message = "redirect_to is not an allowed URL"
+		}
+
+		if err := api.ErrorResponse(w, message, statusCode); err != nil {
+			http.Error(
+				w,
+				"Failed to send error response",
+				http.StatusInternalServerError,
+			)
+		}
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     loginCookie,
+		Value:    authRequest.Session,
+		Path:     loginCookiePath,
+		Expires:  authRequest.ExpiresAt,
+		MaxAge:   int(time.Until(authRequest.ExpiresAt).Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
 
 	// Check if it's an API request
 	if r.Header.Get("Accept") == "application/json" {
 		w.Header().Set("Content-Type", "application/json")
 		if err := json.NewEncoder(w).Encode(map[string]string{
-			"auth_url": authURL,
+			"auth_url": authRequest.URL,
 		}); err != nil {
 			if err := api.ErrorResponse(
 				w,
@@ -71,15 +137,16 @@ func (h *AuthHandler) Login(
 		return
 	}
 
-	// Otherwise redirect to Auth0
-	http.Redirect(w, r, authURL, http.StatusTemporaryRedirect)
+	// Otherwise redirect to the provider
+	http.Redirect(w, r, authRequest.URL, http.StatusTemporaryRedirect)
 }
 
 // @Summary OpenID Connect callback
-// @Description Handle OpenID callback and create session
+// @Description Handle OpenID callback and create session. A user with MFA gets an mfa_token for POST /auth/mfa/verify instead of tokens.
 // @Tags auth
 // @Produce json
 // @Param code query string true "Authorization code"
+// @Param state query string true "Login state"
 // @Success 200 {object} api.Response{data=domain.AuthResponse}
 // @Failure 400 {object} api.Response
 // @Failure 401 {object} api.Response
@@ -104,10 +171,41 @@ func (h *AuthHandler) Callback(
 		return
 	}
 
-	// Log the incoming code
-	fmt.Printf("Received authorization code: %s\n", code)
+	session, err := r.Cookie(loginCookie)
+	if err != nil {
+		if err := api.ErrorResponse(
+			w,
+			"Login session not found or expired",
+			http.StatusBadRequest,
+		); err != nil {
+			http.Error(
+				w,
+				"Failed to send error response",
+				http.StatusInternalServerError,
+			)
+		}
+		return
+	}
 
-	authResponse, err := h.service.HandleCallback(r.Context(), code)
+	// The login session is single use
+	http.SetCookie(w, &http.Cookie{
+		Name:     loginCookie,
+		Path:     loginCookiePath,
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	authResponse, err := h.service.HandleCallback(
+		r.Context(),
+		domain.AuthCallback{
+			Code:    code,
+			State:   r.URL.Query().Get("state"),
+			Session: session.Value,
+			Client:  clientInfo(r),
+		},
+	)
 	if err != nil {
 		// Log the specific error
 		fmt.Printf("Auth callback error: %v\n", err)
@@ -116,9 +214,18 @@ func (h *AuthHandler) Callback(
 		message := "Authentication failed"
 
 		// Provide more specific error messages based on the error type
-		if strings.Contains(err.Error(), "failed to exchange token") {
+		switch {
+		case errors.Is(err, customErrors.ErrInvalidLoginState):
+			statusCode = http.StatusBadRequest
+			message = "Login session not found or expired"
+		case errors.Is(err, customErrors.ErrUnknownProvider):
+			statusCode = http.StatusBadRequest
+			message = "Unknown identity provider"
+		case errors.Is(err, customErrors.ErrInvalidIDToken):
+			message = "Invalid ID token"
+		case strings.Contains(err.Error(), "failed to exchange token"):
 			message = "Failed to exchange authorization code for token"
-		} else if strings.Contains(err.Error(), "failed to fetch user info") {
+		case strings.Contains(err.Error(), "failed to fetch user info"):
 			message = "Failed to fetch user information"
 		}
 
@@ -151,7 +258,7 @@ func (h *AuthHandler) Callback(
 }
 
 // @Summary Refresh token
-// @Description Get new access token using refresh token
+// @Description Get a new access token and a new refresh token. The refresh token presented is revoked; presenting it again signs the session out.
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -183,6 +290,7 @@ func (h *AuthHandler) RefreshToken(
 	authResponse, err := h.service.RefreshToken(
 		r.Context(),
 		refresh.RefreshToken,
+		clientInfo(r),
 	)
 	if err != nil {
 		if err := api.ErrorResponse(
@@ -278,3 +386,24 @@ func (h *AuthHandler) RevokeToken(
 		return
 	}
 }
+
+// @Summary JSON Web Key Set
+// @Description Public keys that verify access tokens issued by this service, for use by other services
+// @Tags auth
+// @Produce json
+// @Success 200 {object} jwt.JWKSet
+// @Router /.well-known/jwks.json [get]
+func (h *AuthHandler) JWKS(
+	w http.ResponseWriter,
+	_ *http.Request,
+) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	if err := json.NewEncoder(w).Encode(h.service.GetJWKS()); err != nil {
+		http.Error(
+			w,
+			"Failed to encode response",
+			http.StatusInternalServerError,
+		)
+	}
+}
@@ -0,0 +1,385 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/grocery-service/internal/api/middleware"
+	"github.com/grocery-service/internal/domain"
+	"github.com/grocery-service/internal/service"
+	"github.com/grocery-service/utils/api"
+	customErrors "github.com/grocery-service/utils/errors"
+)
+
+type CartHandler struct {
+	service service.CartService
+}
+
+func NewCartHandler(
+	service service.CartService,
+) *CartHandler {
+	return &CartHandler{service: service}
+}
+
+func (h *CartHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Use(middleware.RequireAuth)
+
+	r.Get("/", h.GetCart)
+	r.Delete("/", h.Clear)
+	r.Post("/items", h.AddItem)
+	r.Put("/items/{itemID}", h.UpdateItem)
+	r.Delete("/items/{itemID}", h.RemoveItem)
+	r.Post("/checkout", h.Checkout)
+
+	return r
+}
+
+// @Summary Get cart
+// @Description Get the authenticated customer's cart priced at current product prices
+// @Tags cart
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} api.Response{data=domain.Cart}
+// @Failure 401 {object} api.Response
+// @Failure 404 {object} api.Response
+// @Failure 500 {object} api.Response
+// @Router /cart [get]
+func (h *CartHandler) GetCart(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	cart, err := h.service.GetCart(r.Context(), userID)
+	if err != nil {
+		h.handleError(w, err, "Failed to get cart")
+		return
+	}
+
+	h.respond(w, cart, http.StatusOK)
+}
+
+// @Summary Add cart item
+// @Description Add a product to the cart, summing quantities if it is already present
+// @Tags cart
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param item body domain.AddCartItemRequest true "Cart item"
+// @Success 201 {object} api.Response{data=domain.Cart}
+// @Failure 400 {object} api.Response
+// @Failure 404 {object} api.Response
+// @Failure 409 {object} api.Response{data=[]errors.StockShortfall}
+// @Failure 500 {object} api.Response
+// @Router /cart/items [post]
+func (h *CartHandler) AddItem(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var request domain.AddCartItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		if err := api.ErrorResponse(
+			w,
+			"Invalid request body",
+			http.StatusBadRequest,
+		); err != nil {
+			http.Error(
+				w,
+				"Failed to send error response",
+				http.StatusInternalServerError,
+			)
+		}
+		return
+	}
+
+	cart, err := h.service.AddItem(
+		r.Context(),
+		userID,
+		request.ProductID.String(),
+		request.Quantity,
+	)
+	if err != nil {
+		h.handleError(w, err, "Failed to add cart item")
+		return
+	}
+
+	h.respond(w, cart, http.StatusCreated)
+}
+
+// @Summary Update cart item
+// @Description Set the quantity of an item in the cart
+// @Tags cart
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param itemID path string true "Cart item ID" format(uuid)
+// @Param item body domain.UpdateCartItemRequest true "New quantity"
+// @Success 200 {object} api.Response{data=domain.Cart}
+// @Failure 400 {object} api.Response
+// @Failure 404 {object} api.Response
+// @Failure 409 {object} api.Response{data=[]errors.StockShortfall}
+// @Failure 500 {object} api.Response
+// @Router /cart/items/{itemID} [put]
+func (h *CartHandler) UpdateItem(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	itemID := chi.URLParam(r, "itemID")
+	if _, err := uuid.Parse(itemID); err != nil {
+		if err := api.ErrorResponse(
+			w,
+			"Invalid item ID",
+			http.StatusBadRequest,
+		); err != nil {
+			http.Error(
+				w,
+				"Failed to send error response",
+				http.StatusInternalServerError,
+			)
+		}
+		return
+	}
+
+	var request domain.UpdateCartItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		if err := api.ErrorResponse(
+			w,
+			"Invalid request body",
+			http.StatusBadRequest,
+		); err != nil {
+			http.Error(
+				w,
+				"Failed to send error response",
+				http.StatusInternalServerError,
+			)
+		}
+		return
+	}
+
+	cart, err := h.service.UpdateItem(
+		r.Context(),
+		userID,
+		itemID,
+		request.Quantity,
+	)
+	if err != nil {
+		h.handleError(w, err, "Failed to update cart item")
+		return
+	}
+
+	h.respond(w, cart, http.StatusOK)
+}
+
+// @Summary Remove cart item
+// @Description Remove an item from the cart
+// @Tags cart
+// @Security Bearer
+// @Produce json
+// @Param itemID path string true "Cart item ID" format(uuid)
+// @Success 200 {object} api.Response{data=domain.Cart}
+// @Failure 400 {object} api.Response
+// @Failure 404 {object} api.Response
+// @Failure 500 {object} api.Response
+// @Router /cart/items/{itemID} [delete]
+func (h *CartHandler) RemoveItem(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	itemID := chi.URLParam(r, "itemID")
+	if _, err := uuid.Parse(itemID); err != nil {
+		if err := api.ErrorResponse(
+			w,
+			"Invalid item ID",
+			http.StatusBadRequest,
+		); err != nil {
+			http.Error(
+				w,
+				"Failed to send error response",
+				http.StatusInternalServerError,
+			)
+		}
+		return
+	}
+
+	cart, err := h.service.RemoveItem(r.Context(), userID, itemID)
+	if err != nil {
+		h.handleError(w, err, "Failed to remove cart item")
+		return
+	}
+
+	h.respond(w, cart, http.StatusOK)
+}
+
+// @Summary Clear cart
+// @Description Remove every item from the cart
+// @Tags cart
+// @Security Bearer
+// @Produce json
+// @Success 204
+// @Failure 404 {object} api.Response
+// @Failure 500 {object} api.Response
+// @Router /cart [delete]
+func (h *CartHandler) Clear(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	if err := h.service.Clear(r.Context(), userID); err != nil {
+		h.handleError(w, err, "Failed to clear cart")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Checkout cart
+// @Description Create an order from the cart. Price changes since items were added are reported and must be accepted; stock shortfalls are returned per item.
+// @Tags cart
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param checkout body domain.CheckoutRequest false "Checkout options"
+// @Param Idempotency-Key header string false "Unique key that makes retries of this request safe"
+// @Success 201 {object} api.Response{data=domain.CheckoutResult}
+// @Failure 400 {object} api.Response
+// @Failure 404 {object} api.Response
+// @Failure 409 {object} api.Response{data=domain.CheckoutResult}
+// @Failure 500 {object} api.Response
+// @Router /cart/checkout [post]
+func (h *CartHandler) Checkout(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var request domain.CheckoutRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			if err := api.ErrorResponse(
+				w,
+				"Invalid request body",
+				http.StatusBadRequest,
+			); err != nil {
+				http.Error(
+					w,
+					"Failed to send error response",
+					http.StatusInternalServerError,
+				)
+			}
+			return
+		}
+	}
+
+	result, err := h.service.Checkout(r.Context(), userID, request)
+	if err != nil {
+		if errors.Is(err, customErrors.ErrCartPriceChanged) {
+			if err := api.ErrorResponseWithData(
+				w,
+				err.Error(),
+				result,
+				http.StatusConflict,
+			); err != nil {
+				http.Error(
+					w,
+					"Failed to send error response",
+					http.StatusInternalServerError,
+				)
+			}
+			return
+		}
+
+		h.handleError(w, err, "Failed to checkout cart")
+		return
+	}
+
+	h.respond(w, result, http.StatusCreated)
+}
+
+func (h *CartHandler) handleError(
+	w http.ResponseWriter,
+	err error,
+	fallback string,
+) {
+	var (
+		stockErr *customErrors.InsufficientStockError
+		sendErr  error
+	)
+
+	switch {
+	case errors.As(err, &stockErr):
+		sendErr = api.ErrorResponseWithData(
+			w,
+			customErrors.ErrInsufficientStock.Error(),
+			stockErr.Shortfalls,
+			http.StatusConflict,
+		)
+	case errors.Is(err, customErrors.ErrInvalidCartData),
+		errors.Is(err, customErrors.ErrEmptyCart):
+		sendErr = api.ErrorResponse(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, customErrors.ErrCartItemNotFound):
+		sendErr = api.ErrorResponse(
+			w,
+			"Cart item not found",
+			http.StatusNotFound,
+		)
+	case errors.Is(err, customErrors.ErrProductNotFound):
+		sendErr = api.ErrorResponse(
+			w,
+			"Product not found",
+			http.StatusNotFound,
+		)
+	case errors.Is(err, customErrors.ErrCustomerNotFound),
+		errors.Is(err, customErrors.ErrUserNotFound):
+		sendErr = api.ErrorResponse(
+			w,
+			"Customer profile not found",
+			http.StatusNotFound,
+		)
+	default:
+		sendErr = api.ErrorResponse(
+			w,
+			fallback,
+			http.StatusInternalServerError,
+		)
+	}
+
+	if sendErr != nil {
+		http.Error(
+			w,
+			"Failed to send error response",
+			http.StatusInternalServerError,
+		)
+	}
+}
+
+func (h *CartHandler) respond(
+	w http.ResponseWriter,
+	data interface{},
+	status int,
+) {
+	if err := api.SuccessResponse(w, data, status); err != nil {
+		if err := api.ErrorResponse(
+			w,
+			"Failed to send response",
+			http.StatusInternalServerError,
+		); err != nil {
+			http.Error(
+				w,
+				"Failed to send error response",
+				http.StatusInternalServerError,
+			)
+		}
+	}
+}
@@ -188,24 +188,33 @@ func (h *CategoryHandler) GetByID(
 	}
 }
 
-// @Summary List all categories
-// @Description Get a list of all categories
+// @Summary List categories
+// @Description Get a page of categories, ordered by path unless another sort is given
 // @Tags categories
 // @Accept json
 // @Produce json
-// @Success 200 {object} api.Response{data=[]domain.Category}
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param page query int false "Page number, ignored when cursor is set"
+// @Param cursor query string false "Opaque cursor taken from meta.next_cursor"
+// @Param sort query string false "Sort field" Enums(path, name, level, created_at)
+// @Param order query string false "Sort direction" Enums(asc, desc)
+// @Param created_from query string false "Created on or after (RFC 3339 or YYYY-MM-DD)"
+// @Param created_to query string false "Created on or before (RFC 3339 or YYYY-MM-DD)"
+// @Success 200 {object} api.Response{data=[]domain.Category,meta=api.ListMeta}
+// @Header 200 {string} Link "Links to the next, previous, first and last pages"
+// @Failure 400 {object} api.Response
 // @Failure 500 {object} api.Response
 // @Router /categories [get]
 func (h *CategoryHandler) List(
 	w http.ResponseWriter,
 	r *http.Request,
 ) {
-	categories, err := h.service.List(r.Context())
+	query, err := parseListQuery(r)
 	if err != nil {
 		if err := api.ErrorResponse(
 			w,
-			"Failed to list categories",
-			http.StatusInternalServerError,
+			err.Error(),
+			http.StatusBadRequest,
 		); err != nil {
 			http.Error(
 				w,
@@ -216,7 +225,44 @@ func (h *CategoryHandler) List(
 		return
 	}
 
-	if err := api.SuccessResponse(w, categories, http.StatusOK); err != nil {
+	categories, err := h.service.List(r.Context(), query)
+	if err != nil {
+		switch {
+		case errors.Is(err, customErrors.ErrInvalidListQuery):
+			if err := api.ErrorResponse(
+				w,
+				err.Error(),
+				http.StatusBadRequest,
+			); err != nil {
+				http.Error(
+					w,
+					"Failed to send error response",
+					http.StatusInternalServerError,
+				)
+			}
+		default:
+			if err := api.ErrorResponse(
+				w,
+				"Failed to list categories",
+				http.StatusInternalServerError,
+			); err != nil {
+				http.Error(
+					w,
+					"Failed to send error response",
+					http.StatusInternalServerError,
+				)
+			}
+		}
+		return
+	}
+
+	if err := api.ListResponse(
+		w,
+		r,
+		categories.Items,
+		listMeta(categories),
+		http.StatusOK,
+	); err != nil {
 		if err := api.ErrorResponse(
 			w,
 			"Failed to send response",
@@ -2,12 +2,13 @@ package handler
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/grocery-service/internal/api/middleware"
-	_ "github.com/grocery-service/internal/domain" // we need this
+	"github.com/grocery-service/internal/domain"
 	"github.com/grocery-service/internal/service"
 	"github.com/grocery-service/utils/api"
 	customErrors "github.com/grocery-service/utils/errors"
@@ -29,15 +30,20 @@ func (h *CustomerHandler) Routes() chi.Router {
 	r.Use(middleware.RequireAuth)
 
 	// Regular user routes
-	r.Post("/", h.Create)
-	r.Get("/me", h.GetCurrentCustomer)
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequireUser)
+		r.Post("/", h.Create)
+		r.Get("/me", h.GetCurrentCustomer)
+	})
+
+	// Owner or staff routes, checked per customer
+	r.Get("/{id}", h.GetByID)
+	r.Delete("/{id}", h.Delete)
 
-	// Admin only routes
+	// Staff routes
 	r.Group(func(r chi.Router) {
-		r.Use(middleware.RequireAdmin)
+		r.Use(middleware.RequirePermission(domain.PermissionCustomersRead))
 		r.Get("/", h.List)
-		r.Get("/{id}", h.GetByID)
-		r.Delete("/{id}", h.Delete)
 	})
 
 	return r
@@ -183,7 +189,7 @@ func (h *CustomerHandler) GetCurrentCustomer(
 }
 
 // @Summary Get customer by ID
-// @Description Get a customer profile by ID (admin only)
+// @Description Get a customer profile by ID. Callers without customers:read may only get their own.
 // @Tags customers
 // @Security Bearer
 // @Accept json
@@ -201,18 +207,7 @@ func (h *CustomerHandler) GetByID(
 	r *http.Request,
 ) {
 	id := chi.URLParam(r, "id")
-	if _, err := uuid.Parse(id); err != nil {
-		if err := api.ErrorResponse(
-			w,
-			"Invalid customer ID",
-			http.StatusBadRequest,
-		); err != nil {
-			http.Error(
-				w,
-				"Failed to send error response",
-				http.StatusInternalServerError,
-			)
-		}
+	if !h.authorize(w, r, id, domain.PermissionCustomersRead, "Failed to get customer") {
 		return
 	}
 
@@ -262,13 +257,22 @@ func (h *CustomerHandler) GetByID(
 	}
 }
 
-// @Summary List all customers
-// @Description Get a list of all customers (admin only)
+// @Summary List customers
+// @Description Get a page of customers (admin only)
 // @Tags customers
 // @Security Bearer
 // @Accept json
 // @Produce json
-// @Success 200 {object} api.Response{data=[]domain.Customer}
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param page query int false "Page number, ignored when cursor is set"
+// @Param cursor query string false "Opaque cursor taken from meta.next_cursor"
+// @Param sort query string false "Sort field" Enums(created_at)
+// @Param order query string false "Sort direction" Enums(asc, desc)
+// @Param created_from query string false "Created on or after (RFC 3339 or YYYY-MM-DD)"
+// @Param created_to query string false "Created on or before (RFC 3339 or YYYY-MM-DD)"
+// @Success 200 {object} api.Response{data=[]domain.Customer,meta=api.ListMeta}
+// @Header 200 {string} Link "Links to the next, previous, first and last pages"
+// @Failure 400 {object} api.Response
 // @Failure 401 {object} api.Response
 // @Failure 403 {object} api.Response
 // @Failure 500 {object} api.Response
@@ -277,12 +281,12 @@ func (h *CustomerHandler) List(
 	w http.ResponseWriter,
 	r *http.Request,
 ) {
-	customers, err := h.service.List(r.Context())
+	query, err := parseListQuery(r)
 	if err != nil {
 		if err := api.ErrorResponse(
 			w,
-			"Failed to list customers",
-			http.StatusInternalServerError,
+			err.Error(),
+			http.StatusBadRequest,
 		); err != nil {
 			http.Error(
 				w,
@@ -293,7 +297,44 @@ func (h *CustomerHandler) List(
 		return
 	}
 
-	if err := api.SuccessResponse(w, customers, http.StatusOK); err != nil {
+	customers, err := h.service.List(r.Context(), query)
+	if err != nil {
+		switch {
+		case errors.Is(err, customErrors.ErrInvalidListQuery):
+			if err := api.ErrorResponse(
+				w,
+				err.Error(),
+				http.StatusBadRequest,
+			); err != nil {
+				http.Error(
+					w,
+					"Failed to send error response",
+					http.StatusInternalServerError,
+				)
+			}
+		default:
+			if err := api.ErrorResponse(
+				w,
+				"Failed to list customers",
+				http.StatusInternalServerError,
+			); err != nil {
+				http.Error(
+					w,
+					"Failed to send error response",
+					http.StatusInternalServerError,
+				)
+			}
+		}
+		return
+	}
+
+	if err := api.ListResponse(
+		w,
+		r,
+		customers.Items,
+		listMeta(customers),
+		http.StatusOK,
+	); err != nil {
 		if err := api.ErrorResponse(
 			w,
 			"Failed to send response",
@@ -309,7 +350,7 @@ func (h *CustomerHandler) List(
 }
 
 // @Summary Delete a customer
-// @Description Delete a customer profile by ID (admin only)
+// @Description Delete a customer profile by ID. Callers without customers:delete may only delete their own.
 // @Tags customers
 // @Security Bearer
 // @Accept json
@@ -327,18 +368,7 @@ func (h *CustomerHandler) Delete(
 	r *http.Request,
 ) {
 	id := chi.URLParam(r, "id")
-	if _, err := uuid.Parse(id); err != nil {
-		if err := api.ErrorResponse(
-			w,
-			"Invalid customer ID",
-			http.StatusBadRequest,
-		); err != nil {
-			http.Error(
-				w,
-				"Failed to send error response",
-				http.StatusInternalServerError,
-			)
-		}
+	if !h.authorize(w, r, id, domain.PermissionCustomersDelete, "Failed to delete customer") {
 		return
 	}
 
@@ -386,3 +416,49 @@ func (h *CustomerHandler) Delete(
 		}
 	}
 }
+
+// authorize checks that id is a customer ID the caller may act on with
+// permission, answering the request itself when not. Customers other
+// than the caller's own are forbidden without the permission.
+func (h *CustomerHandler) authorize(
+	w http.ResponseWriter,
+	r *http.Request,
+	id string,
+	permission domain.Permission,
+	failure string,
+) bool {
+	customerID, err := uuid.Parse(id)
+	if err != nil {
+		h.errorResponse(w, "Invalid customer ID", http.StatusBadRequest)
+		return false
+	}
+
+	allowed, err := canAccessCustomer(r, h.service, permission, customerID)
+	if err != nil {
+		h.errorResponse(w, failure, http.StatusInternalServerError)
+		return false
+	}
+	if !allowed {
+		h.errorResponse(
+			w,
+			fmt.Sprintf("forbidden: %s permission required", permission),
+			http.StatusForbidden,
+		)
+		return false
+	}
+	return true
+}
+
+func (h *CustomerHandler) errorResponse(
+	w http.ResponseWriter,
+	message string,
+	status int,
+) {
+	if err := api.ErrorResponse(w, message, status); err != nil {
+		http.Error(
+			w,
+			"Failed to send error response",
+			http.StatusInternalServerError,
+		)
+	}
+}
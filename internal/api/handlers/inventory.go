@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/grocery-service/internal/service"
+	"github.com/grocery-service/utils/api"
+	customErrors "github.com/grocery-service/utils/errors"
+)
+
+type InventoryHandler struct {
+	service service.InventoryService
+}
+
+func NewInventoryHandler(
+	service service.InventoryService,
+) *InventoryHandler {
+	return &InventoryHandler{service: service}
+}
+
+func (h *InventoryHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/low-stock", h.ListLowStock)
+	r.Get("/{id}/stock/history", h.GetStockHistory)
+
+	return r
+}
+
+// @Summary Get product stock history
+// @Description List the stock movements of a product, oldest first by default
+// @Tags products
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Product ID" format(uuid)
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param page query int false "Page number"
+// @Param cursor query string false "Keyset cursor from meta.next_cursor"
+// @Param sort query string false "Sort field" Enums(created_at, quantity)
+// @Param order query string false "Sort direction" Enums(asc, desc)
+// @Param created_from query string false "Created at or after (RFC3339 or YYYY-MM-DD)"
+// @Param created_to query string false "Created at or before (RFC3339 or YYYY-MM-DD)"
+// @Success 200 {object} api.Response{data=[]domain.StockMovement,meta=api.ListMeta}
+// @Failure 400 {object} api.Response
+// @Failure 401 {object} api.Response
+// @Failure 403 {object} api.Response
+// @Failure 404 {object} api.Response
+// @Failure 500 {object} api.Response
+// @Router /products/{id}/stock/history [get]
+func (h *InventoryHandler) GetStockHistory(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	id := chi.URLParam(r, "id")
+	if _, err := uuid.Parse(id); err != nil {
+		if err := api.ErrorResponse(
+			w,
+			"Invalid product ID",
+			http.StatusBadRequest,
+		); err != nil {
+			http.Error(
+				w,
+				"Failed to send error response",
+				http.StatusInternalServerError,
+			)
+		}
+		return
+	}
+
+	query, err := parseListQuery(r)
+	if err != nil {
+		if err := api.ErrorResponse(
+			w,
+			err.Error(),
+			http.StatusBadRequest,
+		); err != nil {
+			http.Error(
+				w,
+				"Failed to send error response",
+				http.StatusInternalServerError,
+			)
+		}
+		return
+	}
+
+	movements, err := h.service.GetStockHistory(r.Context(), id, query)
+	if err != nil {
+		h.handleError(w, err, "Failed to get stock history")
+		return
+	}
+
+	if err := api.ListResponse(
+		w,
+		r,
+		movements.Items,
+		listMeta(movements),
+		http.StatusOK,
+	); err != nil {
+		if err := api.ErrorResponse(
+			w,
+			"Failed to send response",
+			http.StatusInternalServerError,
+		); err != nil {
+			http.Error(
+				w,
+				"Failed to send error response",
+				http.StatusInternalServerError,
+			)
+		}
+	}
+}
+
+// @Summary List low-stock products
+// @Description Report products at or below their reorder point with the quantity to reorder, lowest stock first by default
+// @Tags products
+// @Security Bearer
+// @Produce json
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param page query int false "Page number"
+// @Param cursor query string false "Keyset cursor from meta.next_cursor"
+// @Param sort query string false "Sort field" Enums(name, price, stock, created_at)
+// @Param order query string false "Sort direction" Enums(asc, desc)
+// @Param category_id query string false "Category ID" format(uuid)
+// @Success 200 {object} api.Response{data=[]domain.Product,meta=api.ListMeta}
+// @Failure 400 {object} api.Response
+// @Failure 401 {object} api.Response
+// @Failure 403 {object} api.Response
+// @Failure 500 {object} api.Response
+// @Router /products/low-stock [get]
+func (h *InventoryHandler) ListLowStock(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	query, err := parseListQuery(r)
+	if err != nil {
+		if err := api.ErrorResponse(
+			w,
+			err.Error(),
+			http.StatusBadRequest,
+		); err != nil {
+			http.Error(
+				w,
+				"Failed to send error response",
+				http.StatusInternalServerError,
+			)
+		}
+		return
+	}
+
+	products, err := h.service.ListLowStock(r.Context(), query)
+	if err != nil {
+		h.handleError(w, err, "Failed to list low-stock products")
+		return
+	}
+
+	if err := api.ListResponse(
+		w,
+		r,
+		products.Items,
+		listMeta(products),
+		http.StatusOK,
+	); err != nil {
+		if err := api.ErrorResponse(
+			w,
+			"Failed to send response",
+			http.StatusInternalServerError,
+		); err != nil {
+			http.Error(
+				w,
+				"Failed to send error response",
+				http.StatusInternalServerError,
+			)
+		}
+	}
+}
+
+func (h *InventoryHandler) handleError(
+	w http.ResponseWriter,
+	err error,
+	fallback string,
+) {
+	var sendErr error
+
+	switch {
+	case errors.Is(err, customErrors.ErrInvalidListQuery):
+		sendErr = api.ErrorResponse(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, customErrors.ErrProductNotFound):
+		sendErr = api.ErrorResponse(
+			w,
+			"Product not found",
+			http.StatusNotFound,
+		)
+	default:
+		sendErr = api.ErrorResponse(
+			w,
+			fallback,
+			http.StatusInternalServerError,
+		)
+	}
+
+	if sendErr != nil {
+		http.Error(
+			w,
+			"Failed to send error response",
+			http.StatusInternalServerError,
+		)
+	}
+}
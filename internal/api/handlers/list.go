@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grocery-service/internal/domain"
+	"github.com/grocery-service/utils/api"
+	customErrors "github.com/grocery-service/utils/errors"
+)
+
+// parseListQuery reads the shared pagination, sorting and filtering
+// query parameters accepted by every list endpoint.
+func parseListQuery(r *http.Request) (domain.ListQuery, error) {
+	return parseListValues(r.URL.Query())
+}
+
+// parseListValues is parseListQuery for query parameters an endpoint has
+// already read some of its own from.
+func parseListValues(values url.Values) (domain.ListQuery, error) {
+	query := domain.ListQuery{
+		Cursor:  values.Get("cursor"),
+		SortBy:  values.Get("sort"),
+		SortDir: domain.SortDirection(strings.ToLower(values.Get("order"))),
+	}
+
+	var err error
+	if query.Limit, err = parseInt(values.Get("limit"), "limit"); err != nil {
+		return query, err
+	}
+	if query.Page, err = parseInt(values.Get("page"), "page"); err != nil {
+		return query, err
+	}
+
+	filter := &query.Filter
+	if filter.MinPrice, err = parseMoney(values.Get("min_price"), "min_price"); err != nil {
+		return query, err
+	}
+	if filter.MaxPrice, err = parseMoney(values.Get("max_price"), "max_price"); err != nil {
+		return query, err
+	}
+	if filter.CategoryID, err = parseUUID(values.Get("category_id"), "category_id"); err != nil {
+		return query, err
+	}
+	if filter.CustomerID, err = parseUUID(values.Get("customer_id"), "customer_id"); err != nil {
+		return query, err
+	}
+	if filter.CreatedFrom, err = parseTime(values.Get("created_from"), "created_from", false); err != nil {
+		return query, err
+	}
+	if filter.CreatedTo, err = parseTime(values.Get("created_to"), "created_to", true); err != nil {
+		return query, err
+	}
+	if status := values.Get("status"); status != "" {
+		filter.Status = domain.OrderStatus(strings.ToUpper(status))
+		if err := domain.ValidateOrderStatus(filter.Status); err != nil {
+			return query, invalidParam("status")
+		}
+	}
+
+	if err := query.Normalize(); err != nil {
+		return query, fmt.Errorf(
+			"%w: %v",
+			customErrors.ErrInvalidListQuery,
+			err,
+		)
+	}
+
+	return query, nil
+}
+
+func parseInt(value, name string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, invalidParam(name)
+	}
+	return n, nil
+}
+
+func parseMoney(value, name string) (*domain.Money, error) {
+	if value == "" {
+		return nil, nil
+	}
+	m, err := domain.ParseMoney(value, domain.DefaultCurrency)
+	if err != nil {
+		return nil, invalidParam(name)
+	}
+	return &m, nil
+}
+
+func parseUUID(value, name string) (*uuid.UUID, error) {
+	if value == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return nil, invalidParam(name)
+	}
+	return &id, nil
+}
+
+// parseTime accepts RFC 3339 timestamps or plain dates. A plain date
+// used as an upper bound covers the whole day.
+func parseTime(value, name string, endOfDay bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, invalidParam(name)
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return &t, nil
+}
+
+func invalidParam(name string) error {
+	return fmt.Errorf(
+		"%w: invalid %s",
+		customErrors.ErrInvalidListQuery,
+		name,
+	)
+}
+
+func listMeta[T any](page *domain.Page[T]) api.ListMeta {
+	return api.ListMeta{
+		Total:      page.Total,
+		Limit:      page.Limit,
+		Page:       page.Page,
+		NextCursor: page.NextCursor,
+	}
+}
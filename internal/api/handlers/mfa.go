@@ -0,0 +1,349 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/grocery-service/internal/api/middleware"
+	"github.com/grocery-service/internal/domain"
+	"github.com/grocery-service/utils/api"
+	customErrors "github.com/grocery-service/utils/errors"
+)
+
+// @Summary Verify MFA
+// @Description Complete a login that returned mfa_required with a TOTP or recovery code. A login that enrolled the user also returns their recovery codes, which are shown only once.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param verification body domain.MFAVerifyRequest true "MFA token and code"
+// @Success 200 {object} api.Response{data=domain.AuthResponse}
+// @Failure 400 {object} api.Response
+// @Failure 401 {object} api.Response
+// @Failure 429 {object} api.Response
+// @Header 429 {integer} Retry-After "Seconds until the account unlocks"
+// @Router /auth/mfa/verify [post]
+func (h *AuthHandler) VerifyMFA(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	var request domain.MFAVerifyRequest
+	if !h.decode(w, r, &request) {
+		return
+	}
+
+	authResponse, err := h.service.VerifyMFA(
+		r.Context(),
+		request,
+		clientInfo(r),
+	)
+	if err != nil {
+		h.mfaError(w, err, "Authentication failed")
+		return
+	}
+
+	h.respond(w, authResponse, http.StatusOK)
+}
+
+// @Summary MFA status
+// @Description Show whether the caller has MFA enabled or required, and how many recovery codes are left
+// @Tags auth
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} api.Response{data=domain.MFAStatus}
+// @Failure 401 {object} api.Response
+// @Failure 500 {object} api.Response
+// @Router /auth/mfa [get]
+func (h *AuthHandler) GetMFAStatus(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	status, err := h.service.GetMFAStatus(r.Context(), userID)
+	if err != nil {
+		h.mfaError(w, err, "Failed to get MFA status")
+		return
+	}
+
+	h.respond(w, status, http.StatusOK)
+}
+
+// @Summary Enroll in MFA
+// @Description Generate a TOTP secret for the caller. Show the provisioning URI as a QR code, then confirm with the enrollment token and a code from the authenticator.
+// @Tags auth
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} api.Response{data=domain.MFAEnrollment}
+// @Failure 401 {object} api.Response
+// @Failure 409 {object} api.Response
+// @Failure 500 {object} api.Response
+// @Router /auth/mfa/enroll [post]
+func (h *AuthHandler) EnrollMFA(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	enrollment, err := h.service.EnrollMFA(r.Context(), userID)
+	if err != nil {
+		h.mfaError(w, err, "Failed to enroll in MFA")
+		return
+	}
+
+	h.respond(w, enrollment, http.StatusOK)
+}
+
+// @Summary Confirm MFA enrollment
+// @Description Enable MFA with the first code from the authenticator. The recovery codes returned are shown only once.
+// @Tags auth
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param confirmation body domain.ConfirmMFARequest true "Enrollment token and code"
+// @Success 200 {object} api.Response{data=domain.MFARecoveryCodes}
+// @Failure 400 {object} api.Response
+// @Failure 401 {object} api.Response
+// @Failure 409 {object} api.Response
+// @Failure 429 {object} api.Response
+// @Router /auth/mfa/enroll/confirm [post]
+func (h *AuthHandler) ConfirmMFA(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	var request domain.ConfirmMFARequest
+	if !h.decode(w, r, &request) {
+		return
+	}
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	codes, err := h.service.ConfirmMFA(r.Context(), userID, request)
+	if err != nil {
+		h.mfaError(w, err, "Failed to enable MFA")
+		return
+	}
+
+	h.respond(w, codes, http.StatusOK)
+}
+
+// @Summary Regenerate recovery codes
+// @Description Replace the caller's recovery codes after checking a TOTP or recovery code. The old codes stop working.
+// @Tags auth
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param code body domain.MFACodeRequest true "TOTP or recovery code"
+// @Success 200 {object} api.Response{data=domain.MFARecoveryCodes}
+// @Failure 400 {object} api.Response
+// @Failure 401 {object} api.Response
+// @Failure 409 {object} api.Response
+// @Failure 429 {object} api.Response
+// @Router /auth/mfa/recovery-codes [post]
+func (h *AuthHandler) RegenerateRecoveryCodes(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	var request domain.MFACodeRequest
+	if !h.decode(w, r, &request) {
+		return
+	}
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	codes, err := h.service.RegenerateRecoveryCodes(
+		r.Context(),
+		userID,
+		request.Code,
+	)
+	if err != nil {
+		h.mfaError(w, err, "Failed to regenerate recovery codes")
+		return
+	}
+
+	h.respond(w, codes, http.StatusOK)
+}
+
+// @Summary Disable MFA
+// @Description Turn MFA off after checking a TOTP or recovery code. Users required to use MFA cannot.
+// @Tags auth
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param code body domain.MFACodeRequest true "TOTP or recovery code"
+// @Success 200 {object} api.Response
+// @Failure 400 {object} api.Response
+// @Failure 401 {object} api.Response
+// @Failure 403 {object} api.Response
+// @Failure 409 {object} api.Response
+// @Failure 429 {object} api.Response
+// @Router /auth/mfa/disable [post]
+func (h *AuthHandler) DisableMFA(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	var request domain.MFACodeRequest
+	if !h.decode(w, r, &request) {
+		return
+	}
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	if err := h.service.DisableMFA(
+		r.Context(),
+		userID,
+		request.Code,
+	); err != nil {
+		h.mfaError(w, err, "Failed to disable MFA")
+		return
+	}
+
+	h.respond(w, nil, http.StatusOK)
+}
+
+// @Summary Step up
+// @Description Check a TOTP or recovery code and issue a new access token for the current session that records the check. Sensitive routes reject access tokens without a recent one.
+// @Tags auth
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param code body domain.MFACodeRequest true "TOTP or recovery code"
+// @Success 200 {object} api.Response{data=domain.AuthResponse}
+// @Failure 400 {object} api.Response
+// @Failure 401 {object} api.Response
+// @Failure 404 {object} api.Response
+// @Failure 409 {object} api.Response
+// @Failure 429 {object} api.Response
+// @Router /auth/mfa/step-up [post]
+func (h *AuthHandler) StepUp(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	var request domain.MFACodeRequest
+	if !h.decode(w, r, &request) {
+		return
+	}
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+	sessionID, _ := r.Context().Value(middleware.SessionIDKey).(string)
+
+	authResponse, err := h.service.StepUp(
+		r.Context(),
+		userID,
+		sessionID,
+		request.Code,
+		clientInfo(r),
+	)
+	if err != nil {
+		h.mfaError(w, err, "Failed to verify MFA")
+		return
+	}
+
+	h.respond(w, authResponse, http.StatusOK)
+}
+
+// @Summary Require MFA
+// @Description Set whether a user must sign in with MFA. Users without MFA enroll at their next login.
+// @Tags admin
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID" format(uuid)
+// @Param requirement body domain.MFARequirementRequest true "Whether MFA is required"
+// @Success 200 {object} api.Response
+// @Failure 400 {object} api.Response
+// @Failure 401 {object} api.Response
+// @Failure 403 {object} api.Response
+// @Failure 404 {object} api.Response
+// @Failure 500 {object} api.Response
+// @Router /admin/users/{id}/mfa [put]
+func (h *AuthHandler) SetMFARequired(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	userID, ok := h.pathID(w, r, "Invalid user ID")
+	if !ok {
+		return
+	}
+	var request domain.MFARequirementRequest
+	if !h.decode(w, r, &request) {
+		return
+	}
+
+	if err := h.service.SetMFARequired(
+		r.Context(),
+		userID,
+		request.Required,
+	); err != nil {
+		h.mfaError(w, err, "Failed to update MFA requirement")
+		return
+	}
+
+	h.respond(w, nil, http.StatusOK)
+}
+
+// @Summary Reset MFA
+// @Description Disable a user's MFA and delete their recovery codes, for a user who has lost both. A user required to use MFA enrolls again at their next login.
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Param id path string true "User ID" format(uuid)
+// @Success 200 {object} api.Response
+// @Failure 400 {object} api.Response
+// @Failure 401 {object} api.Response
+// @Failure 403 {object} api.Response
+// @Failure 404 {object} api.Response
+// @Failure 500 {object} api.Response
+// @Router /admin/users/{id}/mfa [delete]
+func (h *AuthHandler) ResetMFA(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	userID, ok := h.pathID(w, r, "Invalid user ID")
+	if !ok {
+		return
+	}
+
+	if err := h.service.ResetMFA(r.Context(), userID); err != nil {
+		h.mfaError(w, err, "Failed to reset MFA")
+		return
+	}
+
+	h.respond(w, nil, http.StatusOK)
+}
+
+func (h *AuthHandler) mfaError(
+	w http.ResponseWriter,
+	err error,
+	fallback string,
+) {
+	var (
+		message string
+		status  int
+	)
+
+	switch {
+	case errors.Is(err, customErrors.ErrInvalidMFACode):
+		message, status = "Invalid MFA code", http.StatusUnauthorized
+	case errors.Is(err, customErrors.ErrInvalidMFAChallenge):
+		message = "MFA session not found or expired"
+		status = http.StatusBadRequest
+	case errors.Is(err, customErrors.ErrMFAAlreadyEnabled):
+		message, status = "MFA is already enabled", http.StatusConflict
+	case errors.Is(err, customErrors.ErrMFANotEnabled):
+		message, status = "MFA is not enabled", http.StatusConflict
+	case errors.Is(err, customErrors.ErrMFARequired):
+		message = "MFA is required for this account"
+		status = http.StatusForbidden
+	case errors.Is(err, customErrors.ErrUserNotFound):
+		message, status = "User not found", http.StatusNotFound
+	case errors.Is(err, customErrors.ErrSessionNotFound):
+		message, status = "Session not found", http.StatusNotFound
+	default:
+		h.passwordError(w, err, fallback)
+		return
+	}
+
+	if err := api.ErrorResponse(w, message, status); err != nil {
+		http.Error(
+			w,
+			"Failed to send error response",
+			http.StatusInternalServerError,
+		)
+	}
+}
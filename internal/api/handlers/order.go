@@ -7,44 +7,39 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/grocery-service/internal/api/middleware"
 	"github.com/grocery-service/internal/domain"
 	"github.com/grocery-service/internal/service"
 	"github.com/grocery-service/utils/api"
 	customErrors "github.com/grocery-service/utils/errors"
 )
 
+// OrderHandler serves orders. Callers holding orders:read see every
+// customer's orders and those holding orders:manage place and change
+// them; anyone else only the orders of their own customer profile.
 type OrderHandler struct {
-	service service.OrderService
+	service   service.OrderService
+	customers service.CustomerService
 }
 
 func NewOrderHandler(
 	service service.OrderService,
+	customers service.CustomerService,
 ) *OrderHandler {
-	return &OrderHandler{service: service}
-}
-
-func (h *OrderHandler) Routes() chi.Router {
-	r := chi.NewRouter()
-
-	r.Get("/", h.List)
-	r.Post("/", h.Create)
-	r.Get("/customer/{customerID}", h.ListByCustomerID)
-	r.Get("/{id}", h.GetByID)
-	r.Put("/{id}/status", h.UpdateStatus)
-	r.Post("/{id}/items", h.AddOrderItem)
-	r.Delete("/{id}/items/{itemID}", h.RemoveOrderItem)
-
-	return r
+	return &OrderHandler{service: service, customers: customers}
 }
 
 // @Summary Create a new order
-// @Description Create a new order with the provided data
+// @Description Create a new order with the provided data. Callers without orders:manage may only order for their own customer profile.
 // @Tags orders
 // @Accept json
 // @Produce json
 // @Param order body domain.Order true "Order object"
+// @Param Idempotency-Key header string false "Unique key that makes retries of this request safe"
 // @Success 201 {object} api.Response{data=domain.Order}
 // @Failure 400 {object} api.Response
+// @Failure 403 {object} api.Response
+// @Failure 409 {object} api.Response{data=[]errors.StockShortfall}
 // @Failure 500 {object} api.Response
 // @Router /orders [post]
 func (h *OrderHandler) Create(
@@ -53,84 +48,113 @@ func (h *OrderHandler) Create(
 ) {
 	var order domain.Order
 	if err := json.NewDecoder(r.Body).Decode(&order); err != nil {
-		if err := api.ErrorResponse(
+		h.errorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	allowed, err := canAccessCustomer(
+		r,
+		h.customers,
+		domain.PermissionOrdersManage,
+		order.CustomerID,
+	)
+	if err != nil {
+		h.errorResponse(
 			w,
-			"Invalid request body",
-			http.StatusBadRequest,
-		); err != nil {
-			http.Error(
-				w,
-				"Failed to send error response",
-				http.StatusInternalServerError,
-			)
-		}
+			"Failed to create order",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+	if !allowed {
+		h.errorResponse(
+			w,
+			"forbidden: orders can only be placed for your own customer profile",
+			http.StatusForbidden,
+		)
+		return
+	}
+
+	h.create(w, r, &order)
+}
+
+// @Summary Place an order for the current customer
+// @Description Create a new order for the authenticated user's customer profile. Any customer_id in the body is ignored.
+// @Tags orders
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param order body domain.Order true "Order object"
+// @Param Idempotency-Key header string false "Unique key that makes retries of this request safe"
+// @Success 201 {object} api.Response{data=domain.Order}
+// @Failure 400 {object} api.Response
+// @Failure 401 {object} api.Response
+// @Failure 404 {object} api.Response
+// @Failure 409 {object} api.Response{data=[]errors.StockShortfall}
+// @Failure 500 {object} api.Response
+// @Router /orders/me [post]
+func (h *OrderHandler) CreateMine(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	var order domain.Order
+	if err := json.NewDecoder(r.Body).Decode(&order); err != nil {
+		h.errorResponse(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	if err := h.service.Create(r.Context(), &order); err != nil {
+	customer, ok := h.currentCustomer(w, r)
+	if !ok {
+		return
+	}
+	order.CustomerID = customer.ID
+
+	h.create(w, r, &order)
+}
+
+func (h *OrderHandler) create(
+	w http.ResponseWriter,
+	r *http.Request,
+	order *domain.Order,
+) {
+	if err := h.service.Create(r.Context(), order); err != nil {
+		var stockErr *customErrors.InsufficientStockError
 		switch {
-		case errors.Is(err, customErrors.ErrInvalidOrderData):
-			if err := api.ErrorResponse(
+		case errors.As(err, &stockErr):
+			h.errorResponseWithData(
 				w,
-				err.Error(),
-				http.StatusBadRequest,
-			); err != nil {
-				http.Error(
-					w,
-					"Failed to send error response",
-					http.StatusInternalServerError,
-				)
-			}
+				customErrors.ErrInsufficientStock.Error(),
+				stockErr.Shortfalls,
+				http.StatusConflict,
+			)
+		case errors.Is(err, customErrors.ErrInvalidOrderData):
+			h.errorResponse(w, err.Error(), http.StatusBadRequest)
 		case errors.Is(err, customErrors.ErrInsufficientStock):
-			if err := api.ErrorResponse(
-				w,
-				err.Error(),
-				http.StatusBadRequest,
-			); err != nil {
-				http.Error(
-					w,
-					"Failed to send error response",
-					http.StatusInternalServerError,
-				)
-			}
+			h.errorResponse(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, customErrors.ErrOrderPriceChanged):
+			h.errorResponse(w, err.Error(), http.StatusConflict)
 		default:
-			if err := api.ErrorResponse(
+			h.errorResponse(
 				w,
 				"Failed to create order",
 				http.StatusInternalServerError,
-			); err != nil {
-				http.Error(
-					w,
-					"Failed to send error response",
-					http.StatusInternalServerError,
-				)
-			}
-		}
-		return
-	}
-	if err := api.SuccessResponse(w, order, http.StatusCreated); err != nil {
-		if err := api.ErrorResponse(
-			w,
-			"Failed to send response",
-			http.StatusInternalServerError,
-		); err != nil {
-			http.Error(
-				w,
-				"Failed to send error response",
-				http.StatusInternalServerError,
 			)
 		}
+		return
 	}
+	h.respond(w, order, http.StatusCreated)
 }
 
 // @Summary Get an order by ID
-// @Description Get an order's details by its ID
+// @Description Get an order's details by its ID. Orders of other customers are reported as not found unless the caller holds orders:read.
 // @Tags orders
+// @Security Bearer
 // @Accept json
 // @Produce json
 // @Param id path string true "Order ID" format(uuid)
 // @Success 200 {object} api.Response{data=domain.Order}
 // @Failure 400 {object} api.Response
+// @Failure 401 {object} api.Response
 // @Failure 404 {object} api.Response
 // @Failure 500 {object} api.Response
 // @Router /orders/{id} [get]
@@ -140,17 +164,7 @@ func (h *OrderHandler) GetByID(
 ) {
 	id := chi.URLParam(r, "id")
 	if _, err := uuid.Parse(id); err != nil {
-		if err := api.ErrorResponse(
-			w,
-			"Invalid order ID",
-			http.StatusBadRequest,
-		); err != nil {
-			http.Error(
-				w,
-				"Failed to send error response",
-				http.StatusInternalServerError,
-			)
-		}
+		h.errorResponse(w, "Invalid order ID", http.StatusBadRequest)
 		return
 	}
 
@@ -158,98 +172,106 @@ func (h *OrderHandler) GetByID(
 	if err != nil {
 		switch {
 		case errors.Is(err, customErrors.ErrOrderNotFound):
-			if err := api.ErrorResponse(
-				w,
-				"Order not found",
-				http.StatusNotFound,
-			); err != nil {
-				http.Error(
-					w,
-					"Failed to send error response",
-					http.StatusInternalServerError,
-				)
-			}
+			h.errorResponse(w, "Order not found", http.StatusNotFound)
 		default:
-			if err := api.ErrorResponse(
+			h.errorResponse(
 				w,
 				"Failed to get order",
 				http.StatusInternalServerError,
-			); err != nil {
-				http.Error(
-					w,
-					"Failed to send error response",
-					http.StatusInternalServerError,
-				)
-			}
+			)
 		}
 		return
 	}
 
-	if err := api.SuccessResponse(w, order, http.StatusOK); err != nil {
-		if err := api.ErrorResponse(
-			w,
-			"Failed to send response",
-			http.StatusInternalServerError,
-		); err != nil {
-			http.Error(
-				w,
-				"Failed to send error response",
-				http.StatusInternalServerError,
-			)
-		}
+	if !h.ownsOrder(w, r, order, domain.PermissionOrdersRead) {
+		return
 	}
+
+	h.respond(w, order, http.StatusOK)
 }
 
-// @Summary List all orders
-// @Description Get a list of all orders
+// @Summary List orders
+// @Description Get a page of orders with optional sorting and filtering
 // @Tags orders
 // @Accept json
 // @Produce json
-// @Success 200 {object} api.Response{data=[]domain.Order}
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param page query int false "Page number, ignored when cursor is set"
+// @Param cursor query string false "Opaque cursor taken from meta.next_cursor"
+// @Param sort query string false "Sort field" Enums(created_at, updated_at, total_price, status)
+// @Param order query string false "Sort direction" Enums(asc, desc)
+// @Param status query string false "Order status" Enums(PENDING, CONFIRMED, PREPARING, READY, SHIPPED, DELIVERED, CANCELLED, REFUNDED, FAILED)
+// @Param min_price query number false "Minimum order total"
+// @Param max_price query number false "Maximum order total"
+// @Param created_from query string false "Created on or after (RFC 3339 or YYYY-MM-DD)"
+// @Param created_to query string false "Created on or before (RFC 3339 or YYYY-MM-DD)"
+// @Param customer_id query string false "Customer ID" format(uuid)
+// @Success 200 {object} api.Response{data=[]domain.Order,meta=api.ListMeta}
+// @Header 200 {string} Link "Links to the next, previous, first and last pages"
+// @Failure 400 {object} api.Response
 // @Failure 500 {object} api.Response
 // @Router /orders [get]
 func (h *OrderHandler) List(
 	w http.ResponseWriter,
 	r *http.Request,
 ) {
-	orders, err := h.service.List(r.Context())
+	query, err := parseListQuery(r)
 	if err != nil {
-		if err := api.ErrorResponse(
-			w,
-			"Failed to list orders",
-			http.StatusInternalServerError,
-		); err != nil {
-			http.Error(
+		h.errorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	orders, err := h.service.List(r.Context(), query)
+	if err != nil {
+		switch {
+		case errors.Is(err, customErrors.ErrInvalidListQuery):
+			h.errorResponse(w, err.Error(), http.StatusBadRequest)
+		default:
+			h.errorResponse(
 				w,
-				"Failed to send error response",
+				"Failed to list orders",
 				http.StatusInternalServerError,
 			)
 		}
 		return
 	}
-	if err := api.SuccessResponse(w, orders, http.StatusOK); err != nil {
-		if err := api.ErrorResponse(
+	if err := api.ListResponse(
+		w,
+		r,
+		orders.Items,
+		listMeta(orders),
+		http.StatusOK,
+	); err != nil {
+		h.errorResponse(
 			w,
 			"Failed to send response",
 			http.StatusInternalServerError,
-		); err != nil {
-			http.Error(
-				w,
-				"Failed to send error response",
-				http.StatusInternalServerError,
-			)
-		}
+		)
 	}
 }
 
 // @Summary List customer orders
-// @Description Get all orders for a specific customer
+// @Description Get a page of orders for a specific customer. Callers without orders:read may only list their own.
 // @Tags orders
+// @Security Bearer
 // @Accept json
 // @Produce json
 // @Param customerID path string true "Customer ID" format(uuid)
-// @Success 200 {object} api.Response{data=[]domain.Order}
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param page query int false "Page number, ignored when cursor is set"
+// @Param cursor query string false "Opaque cursor taken from meta.next_cursor"
+// @Param sort query string false "Sort field" Enums(created_at, updated_at, total_price, status)
+// @Param order query string false "Sort direction" Enums(asc, desc)
+// @Param status query string false "Order status" Enums(PENDING, CONFIRMED, PREPARING, READY, SHIPPED, DELIVERED, CANCELLED, REFUNDED, FAILED)
+// @Param min_price query number false "Minimum order total"
+// @Param max_price query number false "Maximum order total"
+// @Param created_from query string false "Created on or after (RFC 3339 or YYYY-MM-DD)"
+// @Param created_to query string false "Created on or before (RFC 3339 or YYYY-MM-DD)"
+// @Success 200 {object} api.Response{data=[]domain.Order,meta=api.ListMeta}
+// @Header 200 {string} Link "Links to the next, previous, first and last pages"
 // @Failure 400 {object} api.Response
+// @Failure 401 {object} api.Response
+// @Failure 403 {object} api.Response
 // @Failure 404 {object} api.Response
 // @Failure 500 {object} api.Response
 // @Router /orders/customer/{customerID} [get]
@@ -257,76 +279,125 @@ func (h *OrderHandler) ListByCustomerID(
 	w http.ResponseWriter,
 	r *http.Request,
 ) {
-	customerID := chi.URLParam(r, "customerID")
-	if _, err := uuid.Parse(customerID); err != nil {
-		if err := api.ErrorResponse(
+	customerID, err := uuid.Parse(chi.URLParam(r, "customerID"))
+	if err != nil {
+		h.errorResponse(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	allowed, err := canAccessCustomer(
+		r,
+		h.customers,
+		domain.PermissionOrdersRead,
+		customerID,
+	)
+	if err != nil {
+		h.errorResponse(
 			w,
-			"Invalid customer ID",
-			http.StatusBadRequest,
-		); err != nil {
-			http.Error(
-				w,
-				"Failed to send error response",
-				http.StatusInternalServerError,
-			)
-		}
+			"Failed to list customer orders",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+	if !allowed {
+		h.errorResponse(
+			w,
+			"forbidden: you can only list your own orders",
+			http.StatusForbidden,
+		)
+		return
+	}
+
+	h.listByCustomer(w, r, customerID.String())
+}
+
+// @Summary List the current customer's orders
+// @Description Get a page of orders placed by the authenticated user's customer profile
+// @Tags orders
+// @Security Bearer
+// @Produce json
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param page query int false "Page number, ignored when cursor is set"
+// @Param cursor query string false "Opaque cursor taken from meta.next_cursor"
+// @Param sort query string false "Sort field" Enums(created_at, updated_at, total_price, status)
+// @Param order query string false "Sort direction" Enums(asc, desc)
+// @Param status query string false "Order status" Enums(PENDING, CONFIRMED, PREPARING, READY, SHIPPED, DELIVERED, CANCELLED, REFUNDED, FAILED)
+// @Param min_price query number false "Minimum order total"
+// @Param max_price query number false "Maximum order total"
+// @Param created_from query string false "Created on or after (RFC 3339 or YYYY-MM-DD)"
+// @Param created_to query string false "Created on or before (RFC 3339 or YYYY-MM-DD)"
+// @Success 200 {object} api.Response{data=[]domain.Order,meta=api.ListMeta}
+// @Header 200 {string} Link "Links to the next, previous, first and last pages"
+// @Failure 400 {object} api.Response
+// @Failure 401 {object} api.Response
+// @Failure 404 {object} api.Response
+// @Failure 500 {object} api.Response
+// @Router /orders/me [get]
+func (h *OrderHandler) ListMine(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	customer, ok := h.currentCustomer(w, r)
+	if !ok {
+		return
+	}
+
+	h.listByCustomer(w, r, customer.ID.String())
+}
+
+func (h *OrderHandler) listByCustomer(
+	w http.ResponseWriter,
+	r *http.Request,
+	customerID string,
+) {
+	query, err := parseListQuery(r)
+	if err != nil {
+		h.errorResponse(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+
 	orders, err := h.service.ListByCustomerID(
 		r.Context(),
 		customerID,
+		query,
 	)
 	if err != nil {
 		switch {
+		case errors.Is(err, customErrors.ErrInvalidListQuery):
+			h.errorResponse(w, err.Error(), http.StatusBadRequest)
 		case errors.Is(err, customErrors.ErrCustomerNotFound):
-			if err := api.ErrorResponse(
-				w,
-				"Customer not found",
-				http.StatusNotFound,
-			); err != nil {
-				http.Error(
-					w,
-					"Failed to send error response",
-					http.StatusInternalServerError,
-				)
-			}
+			h.errorResponse(w, "Customer not found", http.StatusNotFound)
 		default:
-			if err := api.ErrorResponse(
+			h.errorResponse(
 				w,
 				"Failed to list customer orders",
 				http.StatusInternalServerError,
-			); err != nil {
-				http.Error(
-					w,
-					"Failed to send error response",
-					http.StatusInternalServerError,
-				)
-			}
+			)
 		}
 		return
 	}
-	if err := api.SuccessResponse(w, orders, http.StatusOK); err != nil {
-		if err := api.ErrorResponse(
+	if err := api.ListResponse(
+		w,
+		r,
+		orders.Items,
+		listMeta(orders),
+		http.StatusOK,
+	); err != nil {
+		h.errorResponse(
 			w,
 			"Failed to send response",
 			http.StatusInternalServerError,
-		); err != nil {
-			http.Error(
-				w,
-				"Failed to send error response",
-				http.StatusInternalServerError,
-			)
-		}
+		)
 	}
 }
 
 // @Summary Update order status
-// @Description Update the status of an existing order
+// @Description Update the status of an existing order. The change is recorded in the order's status history with the acting user and an optional reason. Use the refund endpoint to move an order to REFUNDED.
 // @Tags orders
 // @Accept json
 // @Produce json
 // @Param id path string true "Order ID" format(uuid)
-// @Param status body object true "Status object" schema(properties(status=string))
+// @Param status body object true "Status object" schema(properties(status=string,reason=string))
 // @Success 200 {object} api.Response
 // @Failure 400 {object} api.Response
 // @Failure 404 {object} api.Response
@@ -338,105 +409,159 @@ func (h *OrderHandler) UpdateStatus(
 ) {
 	id := chi.URLParam(r, "id")
 	if _, err := uuid.Parse(id); err != nil {
-		if err := api.ErrorResponse(
-			w,
-			"Invalid order ID",
-			http.StatusBadRequest,
-		); err != nil {
-			http.Error(
-				w,
-				"Failed to send error response",
-				http.StatusInternalServerError,
-			)
-		}
+		h.errorResponse(w, "Invalid order ID", http.StatusBadRequest)
 		return
 	}
 
 	var request struct {
 		Status domain.OrderStatus `json:"status"`
+		Reason string             `json:"reason"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		if err := api.ErrorResponse(
-			w,
-			"Invalid request body",
-			http.StatusBadRequest,
-		); err != nil {
-			http.Error(
+		h.errorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	actorID, _ := r.Context().Value(middleware.UserIDKey).(string)
+	if err := h.service.UpdateStatus(
+		r.Context(),
+		id,
+		request.Status,
+		actorID,
+		request.Reason,
+	); err != nil {
+		switch {
+		case errors.Is(err, customErrors.ErrOrderNotFound):
+			h.errorResponse(w, "Order not found", http.StatusNotFound)
+		case errors.Is(err, customErrors.ErrOrderStatusInvalid):
+			h.errorResponse(w, err.Error(), http.StatusBadRequest)
+		default:
+			h.errorResponse(
 				w,
-				"Failed to send error response",
+				"Failed to update order status",
 				http.StatusInternalServerError,
 			)
 		}
 		return
 	}
 
-	if err := h.service.UpdateStatus(r.Context(), id, request.Status); err != nil {
+	h.respond(w, nil, http.StatusOK)
+}
+
+// @Summary Refund an order
+// @Description Move an order to REFUNDED. For delivered orders each item may be restocked or discarded; items not listed are discarded.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param id path string true "Order ID" format(uuid)
+// @Param refund body object true "Refund object" schema(properties(reason=string,items=[]domain.RefundLine))
+// @Success 200 {object} api.Response
+// @Failure 400 {object} api.Response
+// @Failure 404 {object} api.Response
+// @Failure 500 {object} api.Response
+// @Router /orders/{id}/refund [post]
+func (h *OrderHandler) Refund(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	id := chi.URLParam(r, "id")
+	if _, err := uuid.Parse(id); err != nil {
+		h.errorResponse(w, "Invalid order ID", http.StatusBadRequest)
+		return
+	}
+
+	var request struct {
+		Reason string              `json:"reason"`
+		Items  []domain.RefundLine `json:"items"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.errorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	actorID, _ := r.Context().Value(middleware.UserIDKey).(string)
+	if err := h.service.Refund(
+		r.Context(),
+		id,
+		actorID,
+		request.Reason,
+		request.Items,
+	); err != nil {
 		switch {
 		case errors.Is(err, customErrors.ErrOrderNotFound):
-			if err := api.ErrorResponse(
-				w,
-				"Order not found",
-				http.StatusNotFound,
-			); err != nil {
-				http.Error(
-					w,
-					"Failed to send error response",
-					http.StatusInternalServerError,
-				)
-			}
-		case errors.Is(err, customErrors.ErrOrderStatusInvalid):
-			if err := api.ErrorResponse(
-				w,
-				err.Error(),
-				http.StatusBadRequest,
-			); err != nil {
-				http.Error(
-					w,
-					"Failed to send error response",
-					http.StatusInternalServerError,
-				)
-			}
+			h.errorResponse(w, "Order not found", http.StatusNotFound)
+		case errors.Is(err, customErrors.ErrInvalidOrderData),
+			errors.Is(err, customErrors.ErrOrderStatusInvalid):
+			h.errorResponse(w, err.Error(), http.StatusBadRequest)
 		default:
-			if err := api.ErrorResponse(
+			h.errorResponse(
 				w,
-				"Failed to update order status",
+				"Failed to refund order",
 				http.StatusInternalServerError,
-			); err != nil {
-				http.Error(
-					w,
-					"Failed to send error response",
-					http.StatusInternalServerError,
-				)
-			}
+			)
 		}
 		return
 	}
 
-	if err := api.SuccessResponse(w, nil, http.StatusOK); err != nil {
-		if err := api.ErrorResponse(
-			w,
-			"Failed to send response",
-			http.StatusInternalServerError,
-		); err != nil {
-			http.Error(
+	h.respond(w, nil, http.StatusOK)
+}
+
+// @Summary Get order status history
+// @Description Get every status change of an order, oldest first, with the acting user and reason. Orders of other customers are reported as not found unless the caller holds orders:read.
+// @Tags orders
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Order ID" format(uuid)
+// @Success 200 {object} api.Response{data=[]domain.OrderStatusChange}
+// @Failure 400 {object} api.Response
+// @Failure 404 {object} api.Response
+// @Failure 500 {object} api.Response
+// @Router /orders/{id}/history [get]
+func (h *OrderHandler) GetStatusHistory(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	id := chi.URLParam(r, "id")
+	if _, err := uuid.Parse(id); err != nil {
+		h.errorResponse(w, "Invalid order ID", http.StatusBadRequest)
+		return
+	}
+
+	if !h.authorizeOrder(w, r, id, domain.PermissionOrdersRead) {
+		return
+	}
+
+	history, err := h.service.GetStatusHistory(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, customErrors.ErrOrderNotFound):
+			h.errorResponse(w, "Order not found", http.StatusNotFound)
+		default:
+			h.errorResponse(
 				w,
-				"Failed to send error response",
+				"Failed to get order status history",
 				http.StatusInternalServerError,
 			)
 		}
+		return
 	}
+
+	h.respond(w, history, http.StatusOK)
 }
 
 // @Summary Add order item
-// @Description Add a new item to an existing order
+// @Description Add a new item to a pending order. Callers without orders:manage may only change their own orders.
 // @Tags orders
+// @Security Bearer
 // @Accept json
 // @Produce json
 // @Param id path string true "Order ID" format(uuid)
 // @Param item body domain.OrderItem true "Order item object"
 // @Success 201 {object} api.Response{data=domain.OrderItem}
 // @Failure 400 {object} api.Response
+// @Failure 401 {object} api.Response
 // @Failure 404 {object} api.Response
 // @Failure 500 {object} api.Response
 // @Router /orders/{id}/items [post]
@@ -446,126 +571,59 @@ func (h *OrderHandler) AddOrderItem(
 ) {
 	orderID := chi.URLParam(r, "id")
 	if _, err := uuid.Parse(orderID); err != nil {
-		if err := api.ErrorResponse(
-			w,
-			"Invalid order ID",
-			http.StatusBadRequest,
-		); err != nil {
-			http.Error(
-				w,
-				"Failed to send error response",
-				http.StatusInternalServerError,
-			)
-		}
+		h.errorResponse(w, "Invalid order ID", http.StatusBadRequest)
 		return
 	}
 
 	var item domain.OrderItem
 	if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
-		if err := api.ErrorResponse(
-			w,
-			"Invalid request body",
-			http.StatusBadRequest,
-		); err != nil {
-			http.Error(
-				w,
-				"Failed to send error response",
-				http.StatusInternalServerError,
-			)
-		}
+		h.errorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if !h.authorizeOrder(
+		w,
+		r,
+		orderID,
+		domain.PermissionOrdersManage,
+	) {
 		return
 	}
 
 	if err := h.service.AddOrderItem(r.Context(), orderID, &item); err != nil {
 		switch {
 		case errors.Is(err, customErrors.ErrOrderNotFound):
-			if err := api.ErrorResponse(
-				w,
-				"Order not found",
-				http.StatusNotFound,
-			); err != nil {
-				http.Error(
-					w,
-					"Failed to send error response",
-					http.StatusInternalServerError,
-				)
-			}
+			h.errorResponse(w, "Order not found", http.StatusNotFound)
 		case errors.Is(err, customErrors.ErrInvalidOrderItemData):
-			if err := api.ErrorResponse(
-				w,
-				err.Error(),
-				http.StatusBadRequest,
-			); err != nil {
-				http.Error(
-					w,
-					"Failed to send error response",
-					http.StatusInternalServerError,
-				)
-			}
+			h.errorResponse(w, err.Error(), http.StatusBadRequest)
 		case errors.Is(err, customErrors.ErrInsufficientStock):
-			if err := api.ErrorResponse(
-				w,
-				err.Error(),
-				http.StatusBadRequest,
-			); err != nil {
-				http.Error(
-					w,
-					"Failed to send error response",
-					http.StatusInternalServerError,
-				)
-			}
+			h.errorResponse(w, err.Error(), http.StatusBadRequest)
 		case errors.Is(err, customErrors.ErrOrderStatusInvalid):
-			if err := api.ErrorResponse(
-				w,
-				err.Error(),
-				http.StatusBadRequest,
-			); err != nil {
-				http.Error(
-					w,
-					"Failed to send error response",
-					http.StatusInternalServerError,
-				)
-			}
+			h.errorResponse(w, err.Error(), http.StatusBadRequest)
 		default:
-			if err := api.ErrorResponse(
+			h.errorResponse(
 				w,
 				"Failed to add order item",
 				http.StatusInternalServerError,
-			); err != nil {
-				http.Error(
-					w,
-					"Failed to send error response",
-					http.StatusInternalServerError,
-				)
-			}
+			)
 		}
 		return
 	}
 
-	if err := api.SuccessResponse(w, item, http.StatusCreated); err != nil {
-		if err := api.ErrorResponse(
-			w,
-			"Failed to send response",
-			http.StatusInternalServerError,
-		); err != nil {
-			http.Error(
-				w,
-				"Failed to send error response",
-				http.StatusInternalServerError,
-			)
-		}
-	}
+	h.respond(w, item, http.StatusCreated)
 }
 
 // @Summary Remove order item
-// @Description Remove an item from an existing order
+// @Description Remove an item from a pending order. Callers without orders:manage may only change their own orders.
 // @Tags orders
+// @Security Bearer
 // @Accept json
 // @Produce json
 // @Param id path string true "Order ID" format(uuid)
 // @Param itemID path string true "Item ID" format(uuid)
 // @Success 204 {object} api.Response
 // @Failure 400 {object} api.Response
+// @Failure 401 {object} api.Response
 // @Failure 404 {object} api.Response
 // @Failure 500 {object} api.Response
 // @Router /orders/{id}/items/{itemID} [delete]
@@ -575,33 +633,22 @@ func (h *OrderHandler) RemoveOrderItem(
 ) {
 	orderID := chi.URLParam(r, "id")
 	if _, err := uuid.Parse(orderID); err != nil {
-		if err := api.ErrorResponse(
-			w,
-			"Invalid order ID",
-			http.StatusBadRequest,
-		); err != nil {
-			http.Error(
-				w,
-				"Failed to send error response",
-				http.StatusInternalServerError,
-			)
-		}
+		h.errorResponse(w, "Invalid order ID", http.StatusBadRequest)
 		return
 	}
 
 	itemID := chi.URLParam(r, "itemID")
 	if _, err := uuid.Parse(itemID); err != nil {
-		if err := api.ErrorResponse(
-			w,
-			"Invalid item ID",
-			http.StatusBadRequest,
-		); err != nil {
-			http.Error(
-				w,
-				"Failed to send error response",
-				http.StatusInternalServerError,
-			)
-		}
+		h.errorResponse(w, "Invalid item ID", http.StatusBadRequest)
+		return
+	}
+
+	if !h.authorizeOrder(
+		w,
+		r,
+		orderID,
+		domain.PermissionOrdersManage,
+	) {
 		return
 	}
 
@@ -613,56 +660,148 @@ func (h *OrderHandler) RemoveOrderItem(
 	if err != nil {
 		switch {
 		case errors.Is(err, customErrors.ErrOrderNotFound):
-			if err := api.ErrorResponse(
-				w,
-				"Order not found",
-				http.StatusNotFound,
-			); err != nil {
-				http.Error(
-					w,
-					"Failed to send error response",
-					http.StatusInternalServerError,
-				)
-			}
+			h.errorResponse(w, "Order not found", http.StatusNotFound)
 		case errors.Is(err, customErrors.ErrOrderItemNotFound):
-			if err := api.ErrorResponse(
-				w,
-				"Order item not found",
-				http.StatusNotFound,
-			); err != nil {
-				http.Error(
-					w,
-					"Failed to send error response",
-					http.StatusInternalServerError,
-				)
-			}
+			h.errorResponse(w, "Order item not found", http.StatusNotFound)
 		case errors.Is(err, customErrors.ErrOrderStatusInvalid):
-			if err := api.ErrorResponse(
-				w,
-				err.Error(),
-				http.StatusBadRequest,
-			); err != nil {
-				http.Error(
-					w,
-					"Failed to send error response",
-					http.StatusInternalServerError,
-				)
-			}
+			h.errorResponse(w, err.Error(), http.StatusBadRequest)
 		default:
-			if err := api.ErrorResponse(
+			h.errorResponse(
 				w,
 				"Failed to remove order item",
 				http.StatusInternalServerError,
-			); err != nil {
-				http.Error(
-					w,
-					"Failed to send error response",
-					http.StatusInternalServerError,
-				)
-			}
+			)
 		}
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// currentCustomer returns the caller's customer profile, answering the
+// request itself when there is none.
+func (h *OrderHandler) currentCustomer(
+	w http.ResponseWriter,
+	r *http.Request,
+) (*domain.Customer, bool) {
+	customer, err := callerCustomer(r, h.customers)
+	if err != nil {
+		if errors.Is(err, customErrors.ErrCustomerNotFound) {
+			h.errorResponse(
+				w,
+				"Customer profile not found",
+				http.StatusNotFound,
+			)
+		} else {
+			h.errorResponse(
+				w,
+				"Failed to get customer profile",
+				http.StatusInternalServerError,
+			)
+		}
+		return nil, false
+	}
+	return customer, true
+}
+
+// authorizeOrder reports whether the caller may access the order with
+// id, answering the request itself when not. Callers holding permission
+// access every order.
+func (h *OrderHandler) authorizeOrder(
+	w http.ResponseWriter,
+	r *http.Request,
+	id string,
+	permission domain.Permission,
+) bool {
+	if middleware.HasPermission(r.Context(), permission) {
+		return true
+	}
+
+	order, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, customErrors.ErrOrderNotFound) {
+			h.errorResponse(w, "Order not found", http.StatusNotFound)
+		} else {
+			h.errorResponse(
+				w,
+				"Failed to get order",
+				http.StatusInternalServerError,
+			)
+		}
+		return false
+	}
+	return h.ownsOrder(w, r, order, permission)
+}
+
+// ownsOrder reports whether the caller may access order. Orders of
+// other customers are answered as not found, unless the caller holds
+// permission, so their IDs reveal nothing.
+func (h *OrderHandler) ownsOrder(
+	w http.ResponseWriter,
+	r *http.Request,
+	order *domain.Order,
+	permission domain.Permission,
+) bool {
+	allowed, err := canAccessCustomer(
+		r,
+		h.customers,
+		permission,
+		order.CustomerID,
+	)
+	if err != nil {
+		h.errorResponse(
+			w,
+			"Failed to get order",
+			http.StatusInternalServerError,
+		)
+		return false
+	}
+	if !allowed {
+		h.errorResponse(w, "Order not found", http.StatusNotFound)
+		return false
+	}
+	return true
+}
+
+func (h *OrderHandler) errorResponse(
+	w http.ResponseWriter,
+	message string,
+	status int,
+) {
+	if err := api.ErrorResponse(w, message, status); err != nil {
+		http.Error(
+			w,
+			"Failed to send error response",
+			http.StatusInternalServerError,
+		)
+	}
+}
+
+func (h *OrderHandler) errorResponseWithData(
+	w http.ResponseWriter,
+	message string,
+	data interface{},
+	status int,
+) {
+	if err := api.ErrorResponseWithData(w, message, data, status); err != nil {
+		http.Error(
+			w,
+			"Failed to send error response",
+			http.StatusInternalServerError,
+		)
+	}
+}
+
+func (h *OrderHandler) respond(
+	w http.ResponseWriter,
+	data interface{},
+	status int,
+) {
+	if err := api.SuccessResponse(w, data, status); err != nil {
+		h.errorResponse(
+			w,
+			"Failed to send response",
+			http.StatusInternalServerError,
+		)
+	}
+}
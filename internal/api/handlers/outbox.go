@@ -0,0 +1,242 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/grocery-service/internal/domain"
+	"github.com/grocery-service/internal/service"
+	"github.com/grocery-service/utils/api"
+	customErrors "github.com/grocery-service/utils/errors"
+)
+
+type OutboxHandler struct {
+	service service.OutboxService
+}
+
+func NewOutboxHandler(
+	service service.OutboxService,
+) *OutboxHandler {
+	return &OutboxHandler{service: service}
+}
+
+func (h *OutboxHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.List)
+	r.Get("/{id}", h.GetByID)
+	r.Post("/{id}/replay", h.Replay)
+
+	return r
+}
+
+// @Summary List outbox events
+// @Description List outbox events, oldest first by default. Use status=DEAD to inspect dead-lettered events.
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Param status query string false "Event status" Enums(PENDING, DELIVERED, DEAD)
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param page query int false "Page number"
+// @Param cursor query string false "Keyset cursor from meta.next_cursor"
+// @Param sort query string false "Sort field" Enums(created_at, next_attempt_at, attempts)
+// @Param order query string false "Sort direction" Enums(asc, desc)
+// @Param created_from query string false "Created at or after (RFC3339 or YYYY-MM-DD)"
+// @Param created_to query string false "Created at or before (RFC3339 or YYYY-MM-DD)"
+// @Success 200 {object} api.Response{data=[]domain.OutboxEvent,meta=api.ListMeta}
+// @Failure 400 {object} api.Response
+// @Failure 401 {object} api.Response
+// @Failure 403 {object} api.Response
+// @Failure 500 {object} api.Response
+// @Router /admin/outbox [get]
+func (h *OutboxHandler) List(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	// The shared status parameter filters by order status; here it
+	// names an outbox status instead.
+	values := r.URL.Query()
+	status := domain.OutboxStatus(strings.ToUpper(values.Get("status")))
+	values.Del("status")
+
+	query, err := parseListValues(values)
+	if err != nil {
+		if err := api.ErrorResponse(
+			w,
+			err.Error(),
+			http.StatusBadRequest,
+		); err != nil {
+			http.Error(
+				w,
+				"Failed to send error response",
+				http.StatusInternalServerError,
+			)
+		}
+		return
+	}
+
+	events, err := h.service.List(r.Context(), status, query)
+	if err != nil {
+		h.handleError(w, err, "Failed to list outbox events")
+		return
+	}
+
+	if err := api.ListResponse(
+		w,
+		r,
+		events.Items,
+		listMeta(events),
+		http.StatusOK,
+	); err != nil {
+		if err := api.ErrorResponse(
+			w,
+			"Failed to send response",
+			http.StatusInternalServerError,
+		); err != nil {
+			http.Error(
+				w,
+				"Failed to send error response",
+				http.StatusInternalServerError,
+			)
+		}
+	}
+}
+
+// @Summary Get outbox event
+// @Description Get an outbox event with its delivery attempts and last error
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Event ID" format(uuid)
+// @Success 200 {object} api.Response{data=domain.OutboxEvent}
+// @Failure 400 {object} api.Response
+// @Failure 404 {object} api.Response
+// @Failure 500 {object} api.Response
+// @Router /admin/outbox/{id} [get]
+func (h *OutboxHandler) GetByID(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	id, ok := h.eventID(w, r)
+	if !ok {
+		return
+	}
+
+	event, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err, "Failed to get outbox event")
+		return
+	}
+
+	h.respond(w, event, http.StatusOK)
+}
+
+// @Summary Replay outbox event
+// @Description Move a dead-lettered event back to pending so the dispatcher delivers it again
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Event ID" format(uuid)
+// @Success 200 {object} api.Response{data=domain.OutboxEvent}
+// @Failure 400 {object} api.Response
+// @Failure 404 {object} api.Response
+// @Failure 409 {object} api.Response
+// @Failure 500 {object} api.Response
+// @Router /admin/outbox/{id}/replay [post]
+func (h *OutboxHandler) Replay(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	id, ok := h.eventID(w, r)
+	if !ok {
+		return
+	}
+
+	event, err := h.service.Replay(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err, "Failed to replay outbox event")
+		return
+	}
+
+	h.respond(w, event, http.StatusOK)
+}
+
+func (h *OutboxHandler) eventID(
+	w http.ResponseWriter,
+	r *http.Request,
+) (string, bool) {
+	id := chi.URLParam(r, "id")
+	if _, err := uuid.Parse(id); err != nil {
+		if err := api.ErrorResponse(
+			w,
+			"Invalid event ID",
+			http.StatusBadRequest,
+		); err != nil {
+			http.Error(
+				w,
+				"Failed to send error response",
+				http.StatusInternalServerError,
+			)
+		}
+		return "", false
+	}
+	return id, true
+}
+
+func (h *OutboxHandler) handleError(
+	w http.ResponseWriter,
+	err error,
+	fallback string,
+) {
+	var sendErr error
+
+	switch {
+	case errors.Is(err, customErrors.ErrInvalidListQuery):
+		sendErr = api.ErrorResponse(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, customErrors.ErrOutboxEventNotFound):
+		sendErr = api.ErrorResponse(
+			w,
+			"Outbox event not found",
+			http.StatusNotFound,
+		)
+	case errors.Is(err, customErrors.ErrOutboxEventNotReplayable):
+		sendErr = api.ErrorResponse(w, err.Error(), http.StatusConflict)
+	default:
+		sendErr = api.ErrorResponse(
+			w,
+			fallback,
+			http.StatusInternalServerError,
+		)
+	}
+
+	if sendErr != nil {
+		http.Error(
+			w,
+			"Failed to send error response",
+			http.StatusInternalServerError,
+		)
+	}
+}
+
+func (h *OutboxHandler) respond(
+	w http.ResponseWriter,
+	data interface{},
+	status int,
+) {
+	if err := api.SuccessResponse(w, data, status); err != nil {
+		if err := api.ErrorResponse(
+			w,
+			"Failed to send response",
+			http.StatusInternalServerError,
+		); err != nil {
+			http.Error(
+				w,
+				"Failed to send error response",
+				http.StatusInternalServerError,
+			)
+		}
+	}
+}
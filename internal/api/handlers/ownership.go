@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/grocery-service/internal/api/middleware"
+	"github.com/grocery-service/internal/domain"
+	"github.com/grocery-service/internal/service"
+	customErrors "github.com/grocery-service/utils/errors"
+)
+
+// callerCustomer returns the customer profile of the authenticated
+// caller, or ErrCustomerNotFound when they have none.
+func callerCustomer(
+	r *http.Request,
+	customers service.CustomerService,
+) (*domain.Customer, error) {
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+	if userID == "" {
+		return nil, customErrors.ErrCustomerNotFound
+	}
+	return customers.GetByUserID(r.Context(), userID)
+}
+
+// canAccessCustomer reports whether the caller may read or change the
+// records of customerID. Callers holding permission act on every
+// customer; anyone else only on their own customer profile.
+func canAccessCustomer(
+	r *http.Request,
+	customers service.CustomerService,
+	permission domain.Permission,
+	customerID uuid.UUID,
+) (bool, error) {
+	if middleware.HasPermission(r.Context(), permission) {
+		return true, nil
+	}
+
+	customer, err := callerCustomer(r, customers)
+	if err != nil {
+		if errors.Is(err, customErrors.ErrCustomerNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return customer.ID == customerID, nil
+}
@@ -0,0 +1,285 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grocery-service/internal/domain"
+	"github.com/grocery-service/utils/api"
+	customErrors "github.com/grocery-service/utils/errors"
+)
+
+// @Summary Register
+// @Description Create an account signed in with email and password. A link to verify the address is emailed; the account cannot sign in until it is followed. The response is the same whether or not the address already has an account; its owner is emailed instead.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param account body domain.RegisterRequest true "New account"
+// @Success 202 {object} api.Response
+// @Failure 400 {object} api.Response
+// @Failure 401 {object} api.Response
+// @Failure 500 {object} api.Response
+// @Router /auth/register [post]
+func (h *AuthHandler) Register(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	var request domain.RegisterRequest
+	if !h.decode(w, r, &request) {
+		return
+	}
+
+	if err := h.service.Register(r.Context(), request); err != nil {
+		h.passwordError(w, err, "Failed to register")
+		return
+	}
+
+	h.respond(w, nil, http.StatusAccepted)
+}
+
+// @Summary Log in with password
+// @Description Sign a verified user in with email and password. Repeated failures lock the account for a while. A user with MFA gets an mfa_token for POST /auth/mfa/verify instead of tokens.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param credentials body domain.PasswordLoginRequest true "Credentials"
+// @Success 200 {object} api.Response{data=domain.AuthResponse}
+// @Failure 400 {object} api.Response
+// @Failure 401 {object} api.Response
+// @Failure 403 {object} api.Response
+// @Failure 429 {object} api.Response
+// @Header 429 {integer} Retry-After "Seconds until the account unlocks"
+// @Router /auth/login/password [post]
+func (h *AuthHandler) PasswordLogin(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	var request domain.PasswordLoginRequest
+	if !h.decode(w, r, &request) {
+		return
+	}
+
+	authResponse, err := h.service.LoginWithPassword(
+		r.Context(),
+		request,
+		clientInfo(r),
+	)
+	if err != nil {
+		h.passwordError(w, err, "Authentication failed")
+		return
+	}
+
+	h.respond(w, authResponse, http.StatusOK)
+}
+
+// @Summary Verify email
+// @Description Confirm an email address with the token from the verification link
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param token body domain.VerifyEmailRequest true "Verification token"
+// @Success 200 {object} api.Response
+// @Failure 400 {object} api.Response
+// @Failure 500 {object} api.Response
+// @Router /auth/verify-email [post]
+func (h *AuthHandler) VerifyEmail(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	var request domain.VerifyEmailRequest
+	if !h.decode(w, r, &request) {
+		return
+	}
+
+	if err := h.service.VerifyEmail(r.Context(), request.Token); err != nil {
+		h.passwordError(w, err, "Failed to verify email")
+		return
+	}
+
+	h.respond(w, nil, http.StatusOK)
+}
+
+// @Summary Resend verification email
+// @Description Email a new verification link. The response is the same whether or not the address has an unverified account.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param email body domain.EmailRequest true "Email address"
+// @Success 202 {object} api.Response
+// @Failure 400 {object} api.Response
+// @Failure 500 {object} api.Response
+// @Router /auth/verify-email/resend [post]
+func (h *AuthHandler) ResendVerification(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	var request domain.EmailRequest
+	if !h.decode(w, r, &request) {
+		return
+	}
+
+	if err := h.service.ResendVerification(
+		r.Context(),
+		request.Email,
+	); err != nil {
+		h.passwordError(w, err, "Failed to send verification email")
+		return
+	}
+
+	h.respond(w, nil, http.StatusAccepted)
+}
+
+// @Summary Forgot password
+// @Description Email a one-time link to reset the password. The response is the same whether or not the address has an account.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param email body domain.EmailRequest true "Email address"
+// @Success 202 {object} api.Response
+// @Failure 400 {object} api.Response
+// @Failure 500 {object} api.Response
+// @Router /auth/password/forgot [post]
+func (h *AuthHandler) ForgotPassword(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	var request domain.EmailRequest
+	if !h.decode(w, r, &request) {
+		return
+	}
+
+	if err := h.service.RequestPasswordReset(
+		r.Context(),
+		request.Email,
+	); err != nil {
+		h.passwordError(w, err, "Failed to send password reset email")
+		return
+	}
+
+	h.respond(w, nil, http.StatusAccepted)
+}
+
+// @Summary Reset password
+// @Description Set a new password with the token from a reset link. Every session of the user is signed out.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param reset body domain.ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {object} api.Response
+// @Failure 400 {object} api.Response
+// @Failure 500 {object} api.Response
+// @Router /auth/password/reset [post]
+func (h *AuthHandler) ResetPassword(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	var request domain.ResetPasswordRequest
+	if !h.decode(w, r, &request) {
+		return
+	}
+
+	if err := h.service.ResetPassword(r.Context(), request); err != nil {
+		h.passwordError(w, err, "Failed to reset password")
+		return
+	}
+
+	h.respond(w, nil, http.StatusOK)
+}
+
+func (h *AuthHandler) decode(
+	w http.ResponseWriter,
+	r *http.Request,
+	v interface{},
+) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		if err := api.ErrorResponse(
+			w,
+			"Invalid request body",
+			http.StatusBadRequest,
+		); err != nil {
+			http.Error(
+				w,
+				"Failed to send error response",
+				http.StatusInternalServerError,
+			)
+		}
+		return false
+	}
+	return true
+}
+
+func (h *AuthHandler) passwordError(
+	w http.ResponseWriter,
+	err error,
+	fallback string,
+) {
+	var (
+		locked  *customErrors.AccountLockedError
+		sendErr error
+	)
+
+	switch {
+	case errors.As(err, &locked):
+		retryAfter := math.Ceil(time.Until(locked.Until).Seconds())
+		w.Header().Set(
+			"Retry-After",
+			strconv.Itoa(int(math.Max(retryAfter, 1))),
+		)
+		sendErr = api.ErrorResponse(
+			w,
+			"Too many failed login attempts, try again later",
+			http.StatusTooManyRequests,
+		)
+	case errors.Is(err, customErrors.ErrInvalidUserData):
+		// Only validation failures name the offending fields first
+		message := "Invalid user data"
+		prefix := customErrors.ErrInvalidUserData.Error() + ": "
+		if strings.HasPrefix(err.Error(), prefix) {
+			message = strings.TrimPrefix(err.Error(), prefix)
+		}
+		sendErr = api.ErrorResponse(w, message, http.StatusBadRequest)
+	case errors.Is(err, customErrors.ErrInvalidToken):
+		sendErr = api.ErrorResponse(
+			w,
+			"Link is invalid or has expired",
+			http.StatusBadRequest,
+		)
+	case errors.Is(err, customErrors.ErrEmailNotVerified):
+		sendErr = api.ErrorResponse(
+			w,
+			"Email address is not verified",
+			http.StatusForbidden,
+		)
+	case errors.Is(err, customErrors.ErrInvalidCredentials):
+		sendErr = api.ErrorResponse(
+			w,
+			"Invalid email or password",
+			http.StatusUnauthorized,
+		)
+	case errors.Is(err, customErrors.ErrUnauthorized):
+		sendErr = api.ErrorResponse(
+			w,
+			"Authentication failed",
+			http.StatusUnauthorized,
+		)
+	default:
+		sendErr = api.ErrorResponse(
+			w,
+			fallback,
+			http.StatusInternalServerError,
+		)
+	}
+
+	if sendErr != nil {
+		http.Error(
+			w,
+			"Failed to send error response",
+			http.StatusInternalServerError,
+		)
+	}
+}
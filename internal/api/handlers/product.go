@@ -7,6 +7,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/grocery-service/internal/api/middleware"
 	"github.com/grocery-service/internal/domain"
 	"github.com/grocery-service/internal/service"
 	"github.com/grocery-service/utils/api"
@@ -28,6 +29,7 @@ func (h *ProductHandler) Routes() chi.Router {
 
 	r.Get("/", h.List)
 	r.Post("/", h.Create)
+	r.Get("/search", h.Search)
 	r.Get("/category/{categoryID}", h.ListByCategoryID)
 	r.Get("/{id}", h.GetByID)
 	r.Put("/{id}", h.Update)
@@ -201,24 +203,36 @@ func (h *ProductHandler) GetByID(
 	}
 }
 
-// @Summary List all products
-// @Description Get a list of all products
+// @Summary List products
+// @Description Get a page of products with optional sorting and filtering
 // @Tags products
 // @Accept json
 // @Produce json
-// @Success 200 {object} api.Response{data=[]domain.Product}
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param page query int false "Page number, ignored when cursor is set"
+// @Param cursor query string false "Opaque cursor taken from meta.next_cursor"
+// @Param sort query string false "Sort field" Enums(name, price, stock, created_at)
+// @Param order query string false "Sort direction" Enums(asc, desc)
+// @Param min_price query number false "Minimum price"
+// @Param max_price query number false "Maximum price"
+// @Param created_from query string false "Created on or after (RFC 3339 or YYYY-MM-DD)"
+// @Param created_to query string false "Created on or before (RFC 3339 or YYYY-MM-DD)"
+// @Param category_id query string false "Category ID" format(uuid)
+// @Success 200 {object} api.Response{data=[]domain.Product,meta=api.ListMeta}
+// @Header 200 {string} Link "Links to the next, previous, first and last pages"
+// @Failure 400 {object} api.Response
 // @Failure 500 {object} api.Response
 // @Router /products [get]
 func (h *ProductHandler) List(
 	w http.ResponseWriter,
 	r *http.Request,
 ) {
-	products, err := h.service.List(r.Context())
+	query, err := parseListQuery(r)
 	if err != nil {
 		if err := api.ErrorResponse(
 			w,
-			"Failed to list products",
-			http.StatusInternalServerError,
+			err.Error(),
+			http.StatusBadRequest,
 		); err != nil {
 			http.Error(
 				w,
@@ -229,7 +243,44 @@ func (h *ProductHandler) List(
 		return
 	}
 
-	if err := api.SuccessResponse(w, products, http.StatusOK); err != nil {
+	products, err := h.service.List(r.Context(), query)
+	if err != nil {
+		switch {
+		case errors.Is(err, customErrors.ErrInvalidListQuery):
+			if err := api.ErrorResponse(
+				w,
+				err.Error(),
+				http.StatusBadRequest,
+			); err != nil {
+				http.Error(
+					w,
+					"Failed to send error response",
+					http.StatusInternalServerError,
+				)
+			}
+		default:
+			if err := api.ErrorResponse(
+				w,
+				"Failed to list products",
+				http.StatusInternalServerError,
+			); err != nil {
+				http.Error(
+					w,
+					"Failed to send error response",
+					http.StatusInternalServerError,
+				)
+			}
+		}
+		return
+	}
+
+	if err := api.ListResponse(
+		w,
+		r,
+		products.Items,
+		listMeta(products),
+		http.StatusOK,
+	); err != nil {
 		if err := api.ErrorResponse(
 			w,
 			"Failed to send response",
@@ -245,12 +296,22 @@ func (h *ProductHandler) List(
 }
 
 // @Summary List products by category
-// @Description Get all products in a specific category
+// @Description Get a page of products in a specific category
 // @Tags products
 // @Accept json
 // @Produce json
 // @Param categoryID path string true "Category ID" format(uuid)
-// @Success 200 {object} api.Response{data=[]domain.Product}
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param page query int false "Page number, ignored when cursor is set"
+// @Param cursor query string false "Opaque cursor taken from meta.next_cursor"
+// @Param sort query string false "Sort field" Enums(name, price, stock, created_at)
+// @Param order query string false "Sort direction" Enums(asc, desc)
+// @Param min_price query number false "Minimum price"
+// @Param max_price query number false "Maximum price"
+// @Param created_from query string false "Created on or after (RFC 3339 or YYYY-MM-DD)"
+// @Param created_to query string false "Created on or before (RFC 3339 or YYYY-MM-DD)"
+// @Success 200 {object} api.Response{data=[]domain.Product,meta=api.ListMeta}
+// @Header 200 {string} Link "Links to the next, previous, first and last pages"
 // @Failure 400 {object} api.Response
 // @Failure 404 {object} api.Response
 // @Failure 500 {object} api.Response
@@ -275,12 +336,41 @@ func (h *ProductHandler) ListByCategoryID(
 		return
 	}
 
+	query, err := parseListQuery(r)
+	if err != nil {
+		if err := api.ErrorResponse(
+			w,
+			err.Error(),
+			http.StatusBadRequest,
+		); err != nil {
+			http.Error(
+				w,
+				"Failed to send error response",
+				http.StatusInternalServerError,
+			)
+		}
+		return
+	}
+
 	products, err := h.service.ListByCategoryID(
 		r.Context(),
 		categoryID,
+		query,
 	)
 	if err != nil {
 		switch {
+		case errors.Is(err, customErrors.ErrInvalidListQuery):
+			if err := api.ErrorResponse(
+				w,
+				err.Error(),
+				http.StatusBadRequest,
+			); err != nil {
+				http.Error(
+					w,
+					"Failed to send error response",
+					http.StatusInternalServerError,
+				)
+			}
 		case errors.Is(err, customErrors.ErrCategoryNotFound):
 			if err := api.ErrorResponse(
 				w,
@@ -309,7 +399,118 @@ func (h *ProductHandler) ListByCategoryID(
 		return
 	}
 
-	if err := api.SuccessResponse(w, products, http.StatusOK); err != nil {
+	if err := api.ListResponse(
+		w,
+		r,
+		products.Items,
+		listMeta(products),
+		http.StatusOK,
+	); err != nil {
+		if err := api.ErrorResponse(
+			w,
+			"Failed to send response",
+			http.StatusInternalServerError,
+		); err != nil {
+			http.Error(
+				w,
+				"Failed to send error response",
+				http.StatusInternalServerError,
+			)
+		}
+	}
+}
+
+// @Summary Search products
+// @Description Full-text search over product names and descriptions. Words match as prefixes; when nothing matches, similar product names are returned instead. Highlights are HTML-escaped, with matches wrapped in <mark> tags.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param q query string true "Search text"
+// @Param category_id query string false "Restrict to this category and its subcategories" format(uuid)
+// @Param min_price query number false "Minimum price"
+// @Param max_price query number false "Maximum price"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param page query int false "Page number"
+// @Success 200 {object} api.Response{data=[]domain.ProductSearchResult,meta=api.ListMeta}
+// @Header 200 {string} Link "Links to the next, previous, first and last pages"
+// @Failure 400 {object} api.Response
+// @Failure 404 {object} api.Response
+// @Failure 500 {object} api.Response
+// @Router /products/search [get]
+func (h *ProductHandler) Search(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	query, err := parseListQuery(r)
+	if err != nil {
+		if err := api.ErrorResponse(
+			w,
+			err.Error(),
+			http.StatusBadRequest,
+		); err != nil {
+			http.Error(
+				w,
+				"Failed to send error response",
+				http.StatusInternalServerError,
+			)
+		}
+		return
+	}
+
+	results, err := h.service.Search(
+		r.Context(),
+		r.URL.Query().Get("q"),
+		query,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, customErrors.ErrInvalidListQuery):
+			if err := api.ErrorResponse(
+				w,
+				err.Error(),
+				http.StatusBadRequest,
+			); err != nil {
+				http.Error(
+					w,
+					"Failed to send error response",
+					http.StatusInternalServerError,
+				)
+			}
+		case errors.Is(err, customErrors.ErrCategoryNotFound):
+			if err := api.ErrorResponse(
+				w,
+				"Category not found",
+				http.StatusNotFound,
+			); err != nil {
+				http.Error(
+					w,
+					"Failed to send error response",
+					http.StatusInternalServerError,
+				)
+			}
+		default:
+			if err := api.ErrorResponse(
+				w,
+				"Failed to search products",
+				http.StatusInternalServerError,
+			); err != nil {
+				http.Error(
+					w,
+					"Failed to send error response",
+					http.StatusInternalServerError,
+				)
+			}
+		}
+		return
+	}
+
+	if err := api.ListResponse(
+		w,
+		r,
+		results.Items,
+		listMeta(results),
+		http.StatusOK,
+	); err != nil {
 		if err := api.ErrorResponse(
 			w,
 			"Failed to send response",
@@ -431,15 +632,17 @@ func (h *ProductHandler) Update(
 }
 
 // @Summary Update product stock
-// @Description Update the stock quantity of a product
+// @Description Record a stock movement for a product. type is one of SALE, RESTOCK, ADJUSTMENT, RETURN or SPOILAGE and defaults to RESTOCK; quantity is signed only for adjustments.
 // @Tags products
+// @Security Bearer
 // @Accept json
 // @Produce json
 // @Param id path string true "Product ID" format(uuid)
-// @Param request body object true "Stock update request" schema(properties(quantity=integer))
+// @Param request body object true "Stock update request" schema(properties(type=string,quantity=integer,note=string))
 // @Success 200 {object} api.Response
 // @Failure 400 {object} api.Response
 // @Failure 404 {object} api.Response
+// @Failure 409 {object} api.Response
 // @Failure 500 {object} api.Response
 // @Router /products/{id}/stock [put]
 func (h *ProductHandler) UpdateStock(
@@ -463,7 +666,9 @@ func (h *ProductHandler) UpdateStock(
 	}
 
 	var request struct {
-		Quantity int `json:"quantity"`
+		Type     domain.StockMovementType `json:"type"`
+		Quantity int                      `json:"quantity"`
+		Note     string                   `json:"note"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
@@ -481,9 +686,37 @@ func (h *ProductHandler) UpdateStock(
 		return
 	}
 
-	err := h.service.UpdateStock(r.Context(), id, request.Quantity)
+	if request.Type == "" {
+		request.Type = domain.StockMovementRestock
+	}
+
+	movement := domain.NewStockMovement(
+		uuid.MustParse(id),
+		request.Type,
+		request.Quantity,
+	)
+	movement.Note = request.Note
+	if userID, ok := r.Context().Value(middleware.UserIDKey).(string); ok {
+		if actorID, err := uuid.Parse(userID); err == nil {
+			movement.ActorID = &actorID
+		}
+	}
+
+	err := h.service.UpdateStock(r.Context(), movement)
 	if err != nil {
 		switch {
+		case errors.Is(err, customErrors.ErrInsufficientStock):
+			if err := api.ErrorResponse(
+				w,
+				err.Error(),
+				http.StatusConflict,
+			); err != nil {
+				http.Error(
+					w,
+					"Failed to send error response",
+					http.StatusInternalServerError,
+				)
+			}
 		case errors.Is(err, customErrors.ErrProductNotFound):
 			if err := api.ErrorResponse(
 				w,
@@ -0,0 +1,312 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/grocery-service/internal/api/middleware"
+	"github.com/grocery-service/internal/domain"
+	"github.com/grocery-service/internal/service"
+	"github.com/grocery-service/utils/api"
+	customErrors "github.com/grocery-service/utils/errors"
+)
+
+type RoleHandler struct {
+	service service.RoleService
+}
+
+func NewRoleHandler(service service.RoleService) *RoleHandler {
+	return &RoleHandler{service: service}
+}
+
+// @Summary List permissions
+// @Description List every permission a role can be granted
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} api.Response{data=[]domain.PermissionInfo}
+// @Failure 401 {object} api.Response
+// @Failure 403 {object} api.Response
+// @Router /admin/permissions [get]
+func (h *RoleHandler) ListPermissions(
+	w http.ResponseWriter,
+	_ *http.Request,
+) {
+	h.respond(w, h.service.ListPermissions(), http.StatusOK)
+}
+
+// @Summary List roles
+// @Description List roles with the permissions they grant
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} api.Response{data=[]domain.Role}
+// @Failure 401 {object} api.Response
+// @Failure 403 {object} api.Response
+// @Failure 500 {object} api.Response
+// @Router /admin/roles [get]
+func (h *RoleHandler) List(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	roles, err := h.service.List(r.Context())
+	if err != nil {
+		h.handleError(w, err, "Failed to list roles")
+		return
+	}
+
+	h.respond(w, roles, http.StatusOK)
+}
+
+// @Summary Get role
+// @Description Get a role with the permissions it grants
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Param name path string true "Role name"
+// @Success 200 {object} api.Response{data=domain.Role}
+// @Failure 401 {object} api.Response
+// @Failure 403 {object} api.Response
+// @Failure 404 {object} api.Response
+// @Failure 500 {object} api.Response
+// @Router /admin/roles/{name} [get]
+func (h *RoleHandler) GetByName(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	role, err := h.service.GetByName(r.Context(), chi.URLParam(r, "name"))
+	if err != nil {
+		h.handleError(w, err, "Failed to get role")
+		return
+	}
+
+	h.respond(w, role, http.StatusOK)
+}
+
+// @Summary Create role
+// @Description Define a new role and the permissions it grants
+// @Tags admin
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param role body domain.RoleRequest true "Role"
+// @Success 201 {object} api.Response{data=domain.Role}
+// @Failure 400 {object} api.Response
+// @Failure 401 {object} api.Response
+// @Failure 403 {object} api.Response
+// @Failure 409 {object} api.Response
+// @Failure 500 {object} api.Response
+// @Router /admin/roles [post]
+func (h *RoleHandler) Create(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	var request domain.RoleRequest
+	if !h.decode(w, r, &request) {
+		return
+	}
+
+	role, err := h.service.Create(r.Context(), request)
+	if err != nil {
+		h.handleError(w, err, "Failed to create role")
+		return
+	}
+
+	h.respond(w, role, http.StatusCreated)
+}
+
+// @Summary Update role
+// @Description Replace a role's description and permissions. Users holding the role get the new permissions with their next access token. The admin role cannot be changed.
+// @Tags admin
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param name path string true "Role name"
+// @Param role body domain.RoleRequest true "Role"
+// @Success 200 {object} api.Response{data=domain.Role}
+// @Failure 400 {object} api.Response
+// @Failure 401 {object} api.Response
+// @Failure 403 {object} api.Response
+// @Failure 404 {object} api.Response
+// @Failure 409 {object} api.Response
+// @Failure 500 {object} api.Response
+// @Router /admin/roles/{name} [put]
+func (h *RoleHandler) Update(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	var request domain.RoleRequest
+	if !h.decode(w, r, &request) {
+		return
+	}
+
+	role, err := h.service.Update(
+		r.Context(),
+		chi.URLParam(r, "name"),
+		request,
+	)
+	if err != nil {
+		h.handleError(w, err, "Failed to update role")
+		return
+	}
+
+	h.respond(w, role, http.StatusOK)
+}
+
+// @Summary Delete role
+// @Description Delete a role that no user holds. Built-in roles cannot be deleted.
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Param name path string true "Role name"
+// @Success 200 {object} api.Response
+// @Failure 401 {object} api.Response
+// @Failure 403 {object} api.Response
+// @Failure 404 {object} api.Response
+// @Failure 409 {object} api.Response
+// @Failure 500 {object} api.Response
+// @Router /admin/roles/{name} [delete]
+func (h *RoleHandler) Delete(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	if err := h.service.Delete(
+		r.Context(),
+		chi.URLParam(r, "name"),
+	); err != nil {
+		h.handleError(w, err, "Failed to delete role")
+		return
+	}
+
+	h.respond(w, nil, http.StatusOK)
+}
+
+// @Summary Assign role
+// @Description Change a user's role. It takes effect with the user's next access token. Admins cannot change their own role.
+// @Tags admin
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID" format(uuid)
+// @Param role body domain.AssignRoleRequest true "Role"
+// @Success 200 {object} api.Response
+// @Failure 400 {object} api.Response
+// @Failure 401 {object} api.Response
+// @Failure 403 {object} api.Response
+// @Failure 404 {object} api.Response
+// @Failure 500 {object} api.Response
+// @Router /admin/users/{id}/role [put]
+func (h *RoleHandler) AssignRole(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	userID := chi.URLParam(r, "id")
+	if _, err := uuid.Parse(userID); err != nil {
+		h.badRequest(w, "Invalid user ID")
+		return
+	}
+
+	var request domain.AssignRoleRequest
+	if !h.decode(w, r, &request) {
+		return
+	}
+
+	actorID, _ := r.Context().Value(middleware.UserIDKey).(string)
+	if err := h.service.AssignRole(
+		r.Context(),
+		actorID,
+		userID,
+		request.Role,
+	); err != nil {
+		h.handleError(w, err, "Failed to assign role")
+		return
+	}
+
+	h.respond(w, nil, http.StatusOK)
+}
+
+func (h *RoleHandler) decode(
+	w http.ResponseWriter,
+	r *http.Request,
+	v interface{},
+) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		h.badRequest(w, "Invalid request body")
+		return false
+	}
+	return true
+}
+
+func (h *RoleHandler) badRequest(w http.ResponseWriter, message string) {
+	if err := api.ErrorResponse(
+		w,
+		message,
+		http.StatusBadRequest,
+	); err != nil {
+		http.Error(
+			w,
+			"Failed to send error response",
+			http.StatusInternalServerError,
+		)
+	}
+}
+
+func (h *RoleHandler) handleError(
+	w http.ResponseWriter,
+	err error,
+	fallback string,
+) {
+	var sendErr error
+
+	switch {
+	case errors.Is(err, customErrors.ErrInvalidRoleData):
+		sendErr = api.ErrorResponse(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, customErrors.ErrRoleNotFound):
+		sendErr = api.ErrorResponse(w, "Role not found", http.StatusNotFound)
+	case errors.Is(err, customErrors.ErrUserNotFound):
+		sendErr = api.ErrorResponse(w, "User not found", http.StatusNotFound)
+	case errors.Is(err, customErrors.ErrRoleExists),
+		errors.Is(err, customErrors.ErrRoleInUse),
+		errors.Is(err, customErrors.ErrBuiltInRole):
+		sendErr = api.ErrorResponse(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, customErrors.ErrForbidden):
+		sendErr = api.ErrorResponse(w, err.Error(), http.StatusForbidden)
+	default:
+		sendErr = api.ErrorResponse(
+			w,
+			fallback,
+			http.StatusInternalServerError,
+		)
+	}
+
+	if sendErr != nil {
+		http.Error(
+			w,
+			"Failed to send error response",
+			http.StatusInternalServerError,
+		)
+	}
+}
+
+func (h *RoleHandler) respond(
+	w http.ResponseWriter,
+	data interface{},
+	status int,
+) {
+	if err := api.SuccessResponse(w, data, status); err != nil {
+		if err := api.ErrorResponse(
+			w,
+			"Failed to send response",
+			http.StatusInternalServerError,
+		); err != nil {
+			http.Error(
+				w,
+				"Failed to send error response",
+				http.StatusInternalServerError,
+			)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package handler
+
+import (
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/grocery-service/internal/api/middleware"
+	"github.com/grocery-service/internal/domain"
+	"github.com/grocery-service/utils/api"
+	customErrors "github.com/grocery-service/utils/errors"
+)
+
+// @Summary List sessions
+// @Description List the devices the caller is signed in on, most recently used first. The caller's own session is marked current.
+// @Tags auth
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} api.Response{data=[]domain.Session}
+// @Failure 401 {object} api.Response
+// @Failure 500 {object} api.Response
+// @Router /auth/sessions [get]
+func (h *AuthHandler) ListSessions(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	sessions, err := h.service.ListSessions(r.Context(), userID)
+	if err != nil {
+		h.sessionError(w, err, "Failed to list sessions")
+		return
+	}
+
+	current, _ := r.Context().Value(middleware.SessionIDKey).(string)
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID.String() == current
+	}
+
+	h.respond(w, sessions, http.StatusOK)
+}
+
+// @Summary Revoke session
+// @Description Sign one of the caller's sessions out. Access tokens already issued to it stay valid until they expire.
+// @Tags auth
+// @Security Bearer
+// @Produce json
+// @Param id path string true "Session ID" format(uuid)
+// @Success 200 {object} api.Response
+// @Failure 400 {object} api.Response
+// @Failure 401 {object} api.Response
+// @Failure 404 {object} api.Response
+// @Failure 500 {object} api.Response
+// @Router /auth/sessions/{id} [delete]
+func (h *AuthHandler) RevokeSession(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	sessionID, ok := h.pathID(w, r, "Invalid session ID")
+	if !ok {
+		return
+	}
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	if err := h.service.RevokeSession(
+		r.Context(),
+		userID,
+		sessionID,
+	); err != nil {
+		h.sessionError(w, err, "Failed to revoke session")
+		return
+	}
+
+	h.respond(w, nil, http.StatusOK)
+}
+
+// @Summary Log out everywhere
+// @Description Sign the caller out of every session, including the current one
+// @Tags auth
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} api.Response{data=domain.LogoutResult}
+// @Failure 401 {object} api.Response
+// @Failure 500 {object} api.Response
+// @Router /auth/logout-all [post]
+func (h *AuthHandler) LogoutAll(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+	h.logoutUser(w, r, userID)
+}
+
+// @Summary List a user's sessions
+// @Description List the devices a user is signed in on
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Param id path string true "User ID" format(uuid)
+// @Success 200 {object} api.Response{data=[]domain.Session}
+// @Failure 400 {object} api.Response
+// @Failure 401 {object} api.Response
+// @Failure 403 {object} api.Response
+// @Failure 500 {object} api.Response
+// @Router /admin/users/{id}/sessions [get]
+func (h *AuthHandler) ListUserSessions(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	userID, ok := h.pathID(w, r, "Invalid user ID")
+	if !ok {
+		return
+	}
+
+	sessions, err := h.service.ListSessions(r.Context(), userID)
+	if err != nil {
+		h.sessionError(w, err, "Failed to list sessions")
+		return
+	}
+
+	h.respond(w, sessions, http.StatusOK)
+}
+
+// @Summary Force logout
+// @Description Sign a user out of every session
+// @Tags admin
+// @Security Bearer
+// @Produce json
+// @Param id path string true "User ID" format(uuid)
+// @Success 200 {object} api.Response{data=domain.LogoutResult}
+// @Failure 400 {object} api.Response
+// @Failure 401 {object} api.Response
+// @Failure 403 {object} api.Response
+// @Failure 500 {object} api.Response
+// @Router /admin/users/{id}/logout [post]
+func (h *AuthHandler) ForceLogout(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	userID, ok := h.pathID(w, r, "Invalid user ID")
+	if !ok {
+		return
+	}
+	h.logoutUser(w, r, userID)
+}
+
+func (h *AuthHandler) logoutUser(
+	w http.ResponseWriter,
+	r *http.Request,
+	userID string,
+) {
+	revoked, err := h.service.RevokeAllSessions(r.Context(), userID)
+	if err != nil {
+		h.sessionError(w, err, "Failed to log out")
+		return
+	}
+
+	h.respond(
+		w,
+		domain.LogoutResult{RevokedTokens: revoked},
+		http.StatusOK,
+	)
+}
+
+func (h *AuthHandler) pathID(
+	w http.ResponseWriter,
+	r *http.Request,
+	message string,
+) (string, bool) {
+	id := chi.URLParam(r, "id")
+	if _, err := uuid.Parse(id); err != nil {
+		if err := api.ErrorResponse(
+			w,
+			message,
+			http.StatusBadRequest,
+		); err != nil {
+			http.Error(
+				w,
+				"Failed to send error response",
+				http.StatusInternalServerError,
+			)
+		}
+		return "", false
+	}
+	return id, true
+}
+
+func (h *AuthHandler) sessionError(
+	w http.ResponseWriter,
+	err error,
+	fallback string,
+) {
+	var sendErr error
+
+	if errors.Is(err, customErrors.ErrSessionNotFound) {
+		sendErr = api.ErrorResponse(
+			w,
+			"Session not found",
+			http.StatusNotFound,
+		)
+	} else {
+		sendErr = api.ErrorResponse(
+			w,
+			fallback,
+			http.StatusInternalServerError,
+		)
+	}
+
+	if sendErr != nil {
+		http.Error(
+			w,
+			"Failed to send error response",
+			http.StatusInternalServerError,
+		)
+	}
+}
+
+func (h *AuthHandler) respond(
+	w http.ResponseWriter,
+	data interface{},
+	status int,
+) {
+	if err := api.SuccessResponse(w, data, status); err != nil {
+		if err := api.ErrorResponse(
+			w,
+			"Failed to send response",
+			http.StatusInternalServerError,
+		); err != nil {
+			http.Error(
+				w,
+				"Failed to send error response",
+				http.StatusInternalServerError,
+			)
+		}
+	}
+}
+
+// clientInfo describes the client of a request for the session list.
+// The router's RealIP middleware has already resolved proxy headers.
+func clientInfo(r *http.Request) domain.ClientInfo {
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+
+	return domain.ClientInfo{
+		IPAddress: ip,
+		UserAgent: r.UserAgent(),
+	}
+}
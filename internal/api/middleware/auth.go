@@ -3,9 +3,12 @@ package middleware
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/grocery-service/internal/domain"
 	"github.com/grocery-service/internal/service"
 )
 
@@ -22,12 +25,31 @@ const (
 	UserIDKey    contextKey = "user_id"
 	UserEmailKey contextKey = "user_email"
 	UserRoleKey  contextKey = "user_role"
+	SessionIDKey contextKey = "session_id"
+	// PermissionsKey holds the caller's permissions as a
+	// map[domain.Permission]bool.
+	PermissionsKey contextKey = "permissions"
+	// MFAVerifiedAtKey holds when the caller last passed MFA in their
+	// session as a time.Time, zero if never.
+	MFAVerifiedAtKey contextKey = "mfa_verified_at"
+	// APIKeyIDKey holds the ID of the API key that authenticated the
+	// request, unset for users.
+	APIKeyIDKey  contextKey = "api_key_id"
 	AdminRole    string     = "admin"
 	CustomerRole string     = "customer"
 )
 
+// APIKeyHeader carries the API key of machine clients that cannot sign
+// in as a user.
+const APIKeyHeader = "X-API-Key"
+
+// Authentication accepts either a bearer access token or an API key in
+// the X-API-Key header. An API key acts with its own permissions and no
+// user: the user ID, email and role are empty and it never passes
+// RequireStepUp.
 func Authentication(
 	authService service.AuthService,
+	apiKeyService service.APIKeyService,
 ) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -38,6 +60,22 @@ func Authentication(
 				return
 			}
 
+			if key := r.Header.Get(APIKeyHeader); key != "" {
+				apiKey, err := apiKeyService.Authenticate(r.Context(), key)
+				if err != nil {
+					http.Error(
+						w,
+						"unauthorized: invalid API key",
+						http.StatusUnauthorized,
+					)
+					return
+				}
+
+				ctx := withAPIKey(r.Context(), apiKey)
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+
 			token, err := extractBearerToken(r)
 			if err != nil {
 				http.Error(
@@ -48,7 +86,7 @@ func Authentication(
 				return
 			}
 
-			userInfo, err := authService.GetUserInfo(r.Context(), token)
+			claims, err := authService.VerifyAccessToken(r.Context(), token)
 			if err != nil {
 				http.Error(
 					w,
@@ -59,15 +97,55 @@ func Authentication(
 			}
 
 			// Add user information to the request context
-			ctx := context.WithValue(r.Context(), UserIDKey, userInfo.ID)
-			ctx = context.WithValue(ctx, UserEmailKey, userInfo.Email)
-			ctx = context.WithValue(ctx, UserRoleKey, userInfo.Role)
+			ctx := context.WithValue(
+				r.Context(),
+				UserIDKey,
+				claims.UserID.String(),
+			)
+			ctx = context.WithValue(ctx, UserEmailKey, claims.Email)
+			ctx = context.WithValue(ctx, UserRoleKey, string(claims.Role))
+			ctx = context.WithValue(
+				ctx,
+				SessionIDKey,
+				claims.SessionID.String(),
+			)
+
+			permissions := make(
+				map[domain.Permission]bool,
+				len(claims.Permissions),
+			)
+			for _, p := range claims.Permissions {
+				permissions[p] = true
+			}
+			ctx = context.WithValue(ctx, PermissionsKey, permissions)
+			ctx = context.WithValue(
+				ctx,
+				MFAVerifiedAtKey,
+				claims.MFAVerifiedAt,
+			)
 
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
+// withAPIKey adds the caller information of an API key to ctx. The
+// user keys are set but empty, as handlers expect them after
+// Authentication.
+func withAPIKey(ctx context.Context, apiKey *domain.APIKey) context.Context {
+	ctx = context.WithValue(ctx, UserIDKey, "")
+	ctx = context.WithValue(ctx, UserEmailKey, "")
+	ctx = context.WithValue(ctx, UserRoleKey, "")
+	ctx = context.WithValue(ctx, SessionIDKey, "")
+	ctx = context.WithValue(ctx, APIKeyIDKey, apiKey.ID.String())
+
+	permissions := make(map[domain.Permission]bool, len(apiKey.Permissions))
+	for _, p := range apiKey.Permissions {
+		permissions[p] = true
+	}
+	return context.WithValue(ctx, PermissionsKey, permissions)
+}
+
 func extractBearerToken(r *http.Request) (string, error) {
 	authHeader := r.Header.Get("Authorization")
 	if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
@@ -92,6 +170,22 @@ func RequireAuth(next http.Handler) http.Handler {
 	})
 }
 
+// RequireUser rejects API keys on routes that act on the caller's own
+// account, such as their sessions, cart and customer profile.
+func RequireUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Context().Value(APIKeyIDKey).(string); ok {
+			http.Error(
+				w,
+				"forbidden: API keys cannot use this route",
+				http.StatusForbidden,
+			)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 func RequireAdmin(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		role, ok := r.Context().Value(UserRoleKey).(string)
@@ -107,17 +201,61 @@ func RequireAdmin(next http.Handler) http.Handler {
 	})
 }
 
-// func RequireCustomer(next http.Handler) http.Handler {
-// 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-// 		role, ok := r.Context().Value(UserRoleKey).(string)
-// 		if !ok || (role != CustomerRole && role != AdminRole) {
-// 			http.Error(
-// 				w,
-// 				"forbidden: customer access required",
-// 				http.StatusForbidden,
-// 			)
-// 			return
-// 		}
-// 		next.ServeHTTP(w, r)
-// 	})
-// }
+// RequirePermission lets the request through only when the caller's
+// role grants permission. Admins hold every permission.
+func RequirePermission(
+	permission domain.Permission,
+) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !HasPermission(r.Context(), permission) {
+				http.Error(
+					w,
+					"forbidden: "+string(permission)+" permission required",
+					http.StatusForbidden,
+				)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// HasPermission reports whether the authenticated caller holds
+// permission.
+func HasPermission(ctx context.Context, permission domain.Permission) bool {
+	if role, _ := ctx.Value(UserRoleKey).(string); role == AdminRole {
+		return true
+	}
+	permissions, _ := ctx.Value(PermissionsKey).(map[domain.Permission]bool)
+	return permissions[permission]
+}
+
+// RequireStepUp lets the request through only when the caller passed MFA
+// within maxAge, for sensitive actions. Callers without MFA must enroll
+// before they can use these routes. The WWW-Authenticate challenge tells
+// clients to step up with POST /auth/mfa/step-up and retry.
+func RequireStepUp(maxAge time.Duration) func(http.Handler) http.Handler {
+	challenge := fmt.Sprintf(
+		`Bearer error="insufficient_user_authentication", `+
+			`error_description="A recent MFA verification is required", `+
+			`max_age=%d`,
+		int(maxAge.Seconds()),
+	)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			verifiedAt, _ := r.Context().Value(MFAVerifiedAtKey).(time.Time)
+			if verifiedAt.IsZero() || time.Since(verifiedAt) > maxAge {
+				w.Header().Set("WWW-Authenticate", challenge)
+				http.Error(
+					w,
+					"unauthorized: recent MFA verification required",
+					http.StatusUnauthorized,
+				)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
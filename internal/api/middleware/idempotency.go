@@ -0,0 +1,174 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/grocery-service/internal/domain"
+	"github.com/grocery-service/internal/service"
+	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/grocery-service/utils/logger"
+)
+
+const (
+	// IdempotencyKeyHeader carries the key that makes a request safe
+	// to retry.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed for a
+	// retried request.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// Idempotency makes requests sent with an Idempotency-Key header safe
+// to retry. The first request with a key runs and its response is
+// recorded; a retry with the same key and body gets that response back
+// with Idempotent-Replayed set, without running again. Reusing a key
+// for a different request gets 422, and a retry while the first request
+// is still running gets 409. Server errors are not recorded, so that the
+// request can be retried with the same key. Keys belong to the client
+// that sent them, so Idempotency must run after Authentication. Only
+// POST and PATCH requests, which are not idempotent already, use keys;
+// other requests, and requests without the header, run as usual.
+func Idempotency(
+	idempotencyService service.IdempotencyService,
+) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get(IdempotencyKeyHeader)
+			if header == "" ||
+				(r.Method != http.MethodPost && r.Method != http.MethodPatch) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(
+					w,
+					"failed to read request body",
+					http.StatusBadRequest,
+				)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			key, err := idempotencyService.Begin(
+				r.Context(),
+				clientKey(r),
+				header,
+				requestFingerprint(r, body),
+			)
+			if err != nil {
+				idempotencyError(w, err)
+				return
+			}
+			if key.Completed() {
+				replay(w, key)
+				return
+			}
+
+			// The outcome is stored even if the client has gone away
+			ctx := context.WithoutCancel(r.Context())
+			rec := &recordingWriter{
+				responseWriter: responseWriter{w, http.StatusOK},
+			}
+			completed := false
+			defer func() {
+				if !completed {
+					releaseIdempotencyKey(ctx, idempotencyService, key)
+				}
+			}()
+
+			next.ServeHTTP(rec, r)
+
+			if rec.status >= http.StatusInternalServerError {
+				return
+			}
+			key.StatusCode = rec.status
+			key.ContentType = rec.Header().Get("Content-Type")
+			key.Body = rec.body.Bytes()
+			if err := idempotencyService.Complete(ctx, key); err != nil {
+				logger.Warn(
+					"failed to record idempotent response",
+					logger.Error64("error", err),
+				)
+				return
+			}
+			completed = true
+		})
+	}
+}
+
+// requestFingerprint hashes what makes a request the same request: its
+// method, path and query, and body.
+func requestFingerprint(r *http.Request, body []byte) string {
+	request := append([]byte(r.Method+" "+r.URL.RequestURI()+"\n"), body...)
+	sum := sha256.Sum256(request)
+	return hex.EncodeToString(sum[:])
+}
+
+func replay(w http.ResponseWriter, key *domain.IdempotencyKey) {
+	if key.ContentType != "" {
+		w.Header().Set("Content-Type", key.ContentType)
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(key.StatusCode)
+	if _, err := w.Write(key.Body); err != nil {
+		logger.Warn(
+			"failed to replay idempotent response",
+			logger.Error64("error", err),
+		)
+	}
+}
+
+func releaseIdempotencyKey(
+	ctx context.Context,
+	idempotencyService service.IdempotencyService,
+	key *domain.IdempotencyKey,
+) {
+	if err := idempotencyService.Release(ctx, key); err != nil {
+		logger.Warn(
+			"failed to release idempotency key",
+			logger.Error64("error", err),
+		)
+	}
+}
+
+func idempotencyError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, customErrors.ErrInvalidIdempotencyKey):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, customErrors.ErrIdempotencyKeyMismatch):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	case errors.Is(err, customErrors.ErrIdempotencyKeyInUse):
+		w.Header().Set("Retry-After", "1")
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		logger.Error(
+			"idempotency key check failed",
+			logger.Error64("error", err),
+		)
+		http.Error(
+			w,
+			"failed to check idempotency key",
+			http.StatusInternalServerError,
+		)
+	}
+}
+
+// recordingWriter keeps a copy of the response body alongside the
+// status.
+type recordingWriter struct {
+	responseWriter
+	body bytes.Buffer
+}
+
+func (rw *recordingWriter) Write(b []byte) (int, error) {
+	rw.body.Write(b)
+	return rw.responseWriter.Write(b)
+}
@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/grocery-service/utils/logger"
+	"github.com/grocery-service/utils/ratelimit"
+)
+
+// RateLimits are the rate limit policies the router applies: IP on
+// every API request before authentication, Auth on the /auth routes and
+// Default everywhere else. Rate limiting is off when Store is nil.
+type RateLimits struct {
+	Store   ratelimit.Store
+	IP      ratelimit.Policy
+	Default ratelimit.Policy
+	Auth    ratelimit.Policy
+}
+
+// RateLimit limits each client to policy. Clients are told their limit
+// in the RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset and
+// RateLimit-Policy headers, and a request over the limit gets 429 with
+// Retry-After. A client is its API key or user after Authentication,
+// and its IP address before. When the store fails the request is let
+// through, so that an outage of the store does not take the API down.
+func RateLimit(
+	store ratelimit.Store,
+	policy ratelimit.Policy,
+) func(http.Handler) http.Handler {
+	if store == nil {
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	limitPolicy := fmt.Sprintf(
+		"%d;w=%d",
+		policy.Limit,
+		int(policy.Window.Seconds()),
+	)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			result, err := store.Take(
+				r.Context(),
+				policy.Name+":"+clientKey(r),
+				policy,
+				time.Now(),
+			)
+			if err != nil {
+				logger.Warn(
+					"rate limit check failed",
+					logger.String("policy", policy.Name),
+					logger.Error64("error", err),
+				)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h := w.Header()
+			h.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+			h.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			h.Set("RateLimit-Reset", ceilSeconds(result.ResetAfter))
+			h.Set("RateLimit-Policy", limitPolicy)
+
+			if !result.Allowed {
+				h.Set("Retry-After", ceilSeconds(result.RetryAfter))
+				http.Error(
+					w,
+					"too many requests: retry later",
+					http.StatusTooManyRequests,
+				)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientKey identifies the caller of r: its API key or user after
+// Authentication, and its IP address before. The IP address is the one
+// RealIP found.
+func clientKey(r *http.Request) string {
+	if keyID, _ := r.Context().Value(APIKeyIDKey).(string); keyID != "" {
+		return "key:" + keyID
+	}
+	if userID, _ := r.Context().Value(UserIDKey).(string); userID != "" {
+		return "user:" + userID
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -2,6 +2,8 @@ package api
 
 import (
 	"encoding/json"
+	"expvar"
+	"net"
 	"net/http"
 	"time"
 
@@ -10,6 +12,8 @@ import (
 	"github.com/go-chi/cors"
 	handler "github.com/grocery-service/internal/api/handlers"
 	customMiddleware "github.com/grocery-service/internal/api/middleware"
+	"github.com/grocery-service/internal/config"
+	"github.com/grocery-service/internal/domain"
 	"github.com/grocery-service/internal/service"
 	httpSwagger "github.com/swaggo/http-swagger"
 )
@@ -20,24 +24,69 @@ func NewRouter(
 	productHandler *handler.ProductHandler,
 	categoryHandler *handler.CategoryHandler,
 	orderHandler *handler.OrderHandler,
+	cartHandler *handler.CartHandler,
+	outboxHandler *handler.OutboxHandler,
+	inventoryHandler *handler.InventoryHandler,
+	roleHandler *handler.RoleHandler,
+	apiKeyHandler *handler.APIKeyHandler,
 	authService service.AuthService,
+	apiKeyService service.APIKeyService,
+	idempotencyService service.IdempotencyService,
+	stepUpMaxAge time.Duration,
+	rateLimits customMiddleware.RateLimits,
+	trustedProxies []*net.IPNet,
 ) *chi.Mux {
+	// Every client is limited by IP address before its credentials are
+	// checked, so that guessing them is limited too
+	limitIP := customMiddleware.RateLimit(rateLimits.Store, rateLimits.IP)
+
+	// Clients are limited by IP address until they authenticate
+	limit := customMiddleware.RateLimit(rateLimits.Store, rateLimits.Default)
+	limitAuth := customMiddleware.RateLimit(rateLimits.Store, rateLimits.Auth)
+
+	// Callers sign in with an access token or an API key, then are
+	// limited per user or key
+	authenticate := chi.Chain(
+		customMiddleware.Authentication(authService, apiKeyService),
+		limit,
+	).Handler
+
+	// Orders can be retried safely with an Idempotency-Key
+	idempotent := customMiddleware.Idempotency(idempotencyService)
+
+	// Sensitive actions need MFA passed within stepUpMaxAge
+	stepUp := customMiddleware.RequireStepUp(stepUpMaxAge)
+
 	r := chi.NewRouter()
 
 	// Basic middleware
 	r.Use(middleware.RequestID)
-	r.Use(middleware.RealIP)
+	r.Use(customMiddleware.RealIP(trustedProxies))
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
-	r.Use(middleware.Timeout(60 * time.Second))
+	r.Use(middleware.Timeout(config.RequestTimeout))
 	r.Use(customMiddleware.Logging)
 
 	// CORS middleware
 	r.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   []string{"*"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedHeaders: []string{
+			"Accept",
+			"Authorization",
+			"Content-Type",
+			customMiddleware.APIKeyHeader,
+			customMiddleware.IdempotencyKeyHeader,
+		},
+		ExposedHeaders: []string{
+			"Link",
+			"RateLimit-Limit",
+			"RateLimit-Remaining",
+			"RateLimit-Reset",
+			"RateLimit-Policy",
+			"Retry-After",
+			customMiddleware.IdempotentReplayedHeader,
+		},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
@@ -61,6 +110,9 @@ func NewRouter(
 		},
 	)
 
+	// Public keys for verifying access tokens
+	r.Get("/.well-known/jwks.json", authHandler.JWKS)
+
 	// Swagger endpoint
 	r.Get("/swagger/*", httpSwagger.Handler(
 		httpSwagger.URL("swagger/doc.json"),
@@ -71,68 +123,234 @@ func NewRouter(
 
 	// API routes
 	r.Route("/api/v1", func(r chi.Router) {
+		r.Use(limitIP)
+
 		// Auth routes (OpenID Connect endpoints)
 		r.Route("/auth", func(r chi.Router) {
+			r.Use(limitAuth)
 			r.Get("/login", authHandler.Login)
 			r.Get("/callback", authHandler.Callback)
 			r.Post("/refresh", authHandler.RefreshToken)
-			r.With(customMiddleware.Authentication(authService)).
-				Post("/revoke", authHandler.RevokeToken)
+			r.Post("/register", authHandler.Register)
+			r.Post("/login/password", authHandler.PasswordLogin)
+			r.Post("/verify-email", authHandler.VerifyEmail)
+			r.Post("/verify-email/resend", authHandler.ResendVerification)
+			r.Post("/password/forgot", authHandler.ForgotPassword)
+			r.Post("/password/reset", authHandler.ResetPassword)
+			r.Post("/mfa/verify", authHandler.VerifyMFA)
+			r.Group(func(r chi.Router) {
+				r.Use(authenticate)
+				r.Use(customMiddleware.RequireUser)
+				r.Post("/revoke", authHandler.RevokeToken)
+				r.Get("/sessions", authHandler.ListSessions)
+				r.Delete("/sessions/{id}", authHandler.RevokeSession)
+				r.Post("/logout-all", authHandler.LogoutAll)
+				r.Get("/mfa", authHandler.GetMFAStatus)
+				r.Post("/mfa/enroll", authHandler.EnrollMFA)
+				r.Post("/mfa/enroll/confirm", authHandler.ConfirmMFA)
+				r.Post(
+					"/mfa/recovery-codes",
+					authHandler.RegenerateRecoveryCodes,
+				)
+				r.Post("/mfa/disable", authHandler.DisableMFA)
+				r.Post("/mfa/step-up", authHandler.StepUp)
+			})
 		})
 
-		// Public routes
-		r.Group(func(r chi.Router) {
-			r.Mount("/categories", categoryHandler.Routes())
+		// Category routes - public reads, protected writes
+		r.Route("/categories", func(r chi.Router) {
+			r.Group(func(r chi.Router) {
+				r.Use(limit)
+				r.Get("/", categoryHandler.List)
+				r.Get("/{id}", categoryHandler.GetByID)
+				r.Get(
+					"/{id}/subcategories",
+					categoryHandler.ListByParentID,
+				)
+			})
+
+			r.Group(func(r chi.Router) {
+				r.Use(authenticate)
+				r.Use(customMiddleware.RequirePermission(
+					domain.PermissionCategoriesWrite,
+				))
+				r.Post("/", categoryHandler.Create)
+				r.Put("/{id}", categoryHandler.Update)
+				r.Delete("/{id}", categoryHandler.Delete)
+			})
 		})
 
 		// Product routes - combining public and protected endpoints
 		r.Route("/products", func(r chi.Router) {
 			// Public endpoints
-			r.Get("/", productHandler.List)
+			r.Group(func(r chi.Router) {
+				r.Use(limit)
+				r.Get("/", productHandler.List)
+				r.Get("/search", productHandler.Search)
+			})
 
-			// Admin only routes - apply auth first, then admin check
+			// Staff routes - apply auth first, then the permission check
 			r.Group(func(r chi.Router) {
-				r.Use(customMiddleware.Authentication(authService))
-				r.Use(customMiddleware.RequireAdmin)
+				r.Use(authenticate)
+
+				r.Group(func(r chi.Router) {
+					r.Use(customMiddleware.RequirePermission(
+						domain.PermissionProductsWrite,
+					))
+					r.Post("/", productHandler.Create)
+					r.Put("/{id}", productHandler.Update)
+					r.With(stepUp).Delete("/{id}", productHandler.Delete)
+				})
 
-				r.Post("/", productHandler.Create)
-				r.Put("/{id}", productHandler.Update)
-				r.Delete("/{id}", productHandler.Delete)
+				r.Group(func(r chi.Router) {
+					r.Use(customMiddleware.RequirePermission(
+						domain.PermissionInventoryRead,
+					))
+					r.Get("/low-stock", inventoryHandler.ListLowStock)
+					r.Get(
+						"/{id}/stock/history",
+						inventoryHandler.GetStockHistory,
+					)
+				})
+
+				r.Group(func(r chi.Router) {
+					r.Use(customMiddleware.RequirePermission(
+						domain.PermissionInventoryWrite,
+					))
+					r.Put("/{id}/stock", productHandler.UpdateStock)
+				})
 			})
 
 			// Protected endpoints
 			r.Group(func(r chi.Router) {
-				r.Use(customMiddleware.Authentication(authService))
+				r.Use(authenticate)
 				r.Get("/{id}", productHandler.GetByID)
 			})
 		})
 
 		// Protected routes
 		r.Group(func(r chi.Router) {
-			r.Use(customMiddleware.Authentication(authService))
+			r.Use(authenticate)
 
 			// Customer routes
 			r.Mount("/customers", customerHandler.Routes())
 
+			// Cart routes
+			r.Group(func(r chi.Router) {
+				r.Use(customMiddleware.RequireUser)
+				r.Use(idempotent)
+				r.Mount("/cart", cartHandler.Routes())
+			})
+
 			// Order routes
 			r.Route("/orders", func(r chi.Router) {
+				r.Use(idempotent)
+
 				// Customer routes
 				r.Group(func(r chi.Router) {
-					// r.Use(customMiddleware.RequireCustomer)
+					r.Use(customMiddleware.RequirePermission(
+						domain.PermissionOrdersCreate,
+					))
 					r.Post("/", orderHandler.Create)
-					r.Get(
-						"/customer/{customer_id}",
-						orderHandler.ListByCustomerID,
+					r.With(customMiddleware.RequireUser).
+						Post("/me", orderHandler.CreateMine)
+					r.Post("/{id}/items", orderHandler.AddOrderItem)
+					r.Delete(
+						"/{id}/items/{itemID}",
+						orderHandler.RemoveOrderItem,
 					)
 				})
+				r.With(customMiddleware.RequireUser).
+					Get("/me", orderHandler.ListMine)
+
+				// Owner or staff routes, checked per order
+				r.Get(
+					"/customer/{customerID}",
+					orderHandler.ListByCustomerID,
+				)
+				r.Get("/{id}", orderHandler.GetByID)
+				r.Get("/{id}/history", orderHandler.GetStatusHistory)
 
-				// Admin routes
+				// Staff routes
 				r.Group(func(r chi.Router) {
-					r.Use(customMiddleware.RequireAdmin)
+					r.Use(customMiddleware.RequirePermission(
+						domain.PermissionOrdersRead,
+					))
 					r.Get("/all", orderHandler.List)
-					r.Get("/{id}", orderHandler.GetByID)
+				})
+
+				r.Group(func(r chi.Router) {
+					r.Use(customMiddleware.RequirePermission(
+						domain.PermissionOrdersUpdateStatus,
+					))
 					r.Put("/{id}/status", orderHandler.UpdateStatus)
 				})
+
+				r.Group(func(r chi.Router) {
+					r.Use(customMiddleware.RequirePermission(
+						domain.PermissionOrdersRefund,
+					))
+					r.With(stepUp).Post("/{id}/refund", orderHandler.Refund)
+				})
+			})
+
+			// Admin routes
+			r.Route("/admin", func(r chi.Router) {
+				r.Group(func(r chi.Router) {
+					r.Use(customMiddleware.RequirePermission(
+						domain.PermissionOutboxManage,
+					))
+					r.Mount("/outbox", outboxHandler.Routes())
+				})
+
+				r.Group(func(r chi.Router) {
+					r.Use(customMiddleware.RequirePermission(
+						domain.PermissionMetricsRead,
+					))
+					r.Get("/metrics", expvar.Handler().ServeHTTP)
+				})
+
+				r.Group(func(r chi.Router) {
+					r.Use(customMiddleware.RequirePermission(
+						domain.PermissionRolesManage,
+					))
+					r.Get("/permissions", roleHandler.ListPermissions)
+					r.Route("/roles", func(r chi.Router) {
+						r.Get("/", roleHandler.List)
+						r.With(stepUp).Post("/", roleHandler.Create)
+						r.Get("/{name}", roleHandler.GetByName)
+						r.With(stepUp).Put("/{name}", roleHandler.Update)
+						r.With(stepUp).Delete("/{name}", roleHandler.Delete)
+					})
+				})
+
+				r.Group(func(r chi.Router) {
+					r.Use(customMiddleware.RequirePermission(
+						domain.PermissionAPIKeysManage,
+					))
+					r.Route("/api-keys", func(r chi.Router) {
+						r.Get("/", apiKeyHandler.List)
+						r.With(stepUp).Post("/", apiKeyHandler.Create)
+						r.Get("/{id}", apiKeyHandler.GetByID)
+						r.With(stepUp).Put("/{id}", apiKeyHandler.Update)
+						r.With(stepUp).Delete("/{id}", apiKeyHandler.Delete)
+					})
+				})
+
+				r.Group(func(r chi.Router) {
+					r.Use(customMiddleware.RequirePermission(
+						domain.PermissionUsersManage,
+					))
+					r.Get("/users/{id}/sessions", authHandler.ListUserSessions)
+					r.Post("/users/{id}/logout", authHandler.ForceLogout)
+
+					r.Group(func(r chi.Router) {
+						r.Use(stepUp)
+						r.Put("/users/{id}/role", roleHandler.AssignRole)
+						r.Put("/users/{id}/mfa", authHandler.SetMFARequired)
+						r.Delete("/users/{id}/mfa", authHandler.ResetMFA)
+					})
+				})
 			})
 		})
 	})
@@ -1,18 +1,24 @@
 package api
 
 import (
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	handler "github.com/grocery-service/internal/api/handlers"
+	customMiddleware "github.com/grocery-service/internal/api/middleware"
 	"github.com/grocery-service/internal/domain"
 	serviceMock "github.com/grocery-service/tests/mocks/service"
+	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/grocery-service/utils/jwt"
+	"github.com/grocery-service/utils/ratelimit"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -24,6 +30,11 @@ func TestNewRouter(t *testing.T) {
 	productService := serviceMock.NewProductService(t)
 	categoryService := serviceMock.NewCategoryService(t)
 	orderService := serviceMock.NewOrderService(t)
+	cartService := serviceMock.NewCartService(t)
+	outboxService := serviceMock.NewOutboxService(t)
+	inventoryService := serviceMock.NewInventoryService(t)
+	roleService := serviceMock.NewRoleService(t)
+	apiKeyService := serviceMock.NewAPIKeyService(t)
 
 	// Setup handlers with mock services
 	authHandler := handler.NewAuthHandler(authService)
@@ -36,7 +47,12 @@ func TestNewRouter(t *testing.T) {
 	categoryHandler := handler.NewCategoryHandler(
 		categoryService,
 	)
-	orderHandler := handler.NewOrderHandler(orderService)
+	orderHandler := handler.NewOrderHandler(orderService, customerService)
+	cartHandler := handler.NewCartHandler(cartService)
+	outboxHandler := handler.NewOutboxHandler(outboxService)
+	inventoryHandler := handler.NewInventoryHandler(inventoryService)
+	roleHandler := handler.NewRoleHandler(roleService)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
 
 	// Initialize router
 	router := NewRouter(
@@ -45,17 +61,78 @@ func TestNewRouter(t *testing.T) {
 		productHandler,
 		categoryHandler,
 		orderHandler,
+		cartHandler,
+		outboxHandler,
+		inventoryHandler,
+		roleHandler,
+		apiKeyHandler,
 		authService,
+		apiKeyService,
+		serviceMock.NewIdempotencyService(t),
+		10*time.Minute,
+		customMiddleware.RateLimits{},
+		nil,
 	)
 
+	// signedIn makes the test token verify as a caller with role and
+	// permissions.
+	signedIn := func(
+		role domain.UserRole,
+		permissions ...domain.Permission,
+	) func(*testing.T, *serviceMock.AuthService) {
+		return func(_ *testing.T, service *serviceMock.AuthService) {
+			service.On("VerifyAccessToken", mock.Anything, "test-token").
+				Return(&domain.AccessTokenClaims{
+					UserID:      uuid.New(),
+					Role:        role,
+					Permissions: permissions,
+				}, nil)
+		}
+	}
+
+	// steppedUp is signedIn for a caller who passed MFA at verifiedAt.
+	steppedUp := func(
+		verifiedAt time.Time,
+		role domain.UserRole,
+	) func(*testing.T, *serviceMock.AuthService) {
+		return func(_ *testing.T, service *serviceMock.AuthService) {
+			service.On("VerifyAccessToken", mock.Anything, "test-token").
+				Return(&domain.AccessTokenClaims{
+					UserID:        uuid.New(),
+					Role:          role,
+					MFAVerifiedAt: verifiedAt,
+				}, nil)
+		}
+	}
+
+	// scopedKey makes the test API key authenticate with permissions.
+	scopedKey := func(
+		permissions ...domain.Permission,
+	) func(*testing.T, *serviceMock.APIKeyService) {
+		return func(_ *testing.T, service *serviceMock.APIKeyService) {
+			service.On("Authenticate", mock.Anything, "gsk_test-key").
+				Return(&domain.APIKey{
+					ID:          uuid.New(),
+					Permissions: permissions,
+				}, nil)
+		}
+	}
+
 	// Test cases for routes
 	tests := []struct {
 		name           string
 		method         string
 		path           string
+		cookies        []*http.Cookie
 		setupAuth      func(t *testing.T, service *serviceMock.AuthService)
 		setupCategory  func(t *testing.T, service *serviceMock.CategoryService)
+		setupCustomer  func(t *testing.T, service *serviceMock.CustomerService)
 		setupProduct   func(t *testing.T, service *serviceMock.ProductService)
+		setupOrder     func(t *testing.T, service *serviceMock.OrderService)
+		setupRole      func(t *testing.T, service *serviceMock.RoleService)
+		setupAPIKey    func(t *testing.T, service *serviceMock.APIKeyService)
+		bearer         bool
+		apiKey         bool
 		expectedStatus int
 	}{
 		{
@@ -63,17 +140,29 @@ func TestNewRouter(t *testing.T) {
 			method: http.MethodGet,
 			path:   "/api/v1/auth/login",
 			setupAuth: func(_ *testing.T, service *serviceMock.AuthService) {
-				service.On("GetAuthURL").
-					Return("https://accounts.google.com/o/oauth2/auth")
+				service.On("GetAuthURL", mock.Anything, domain.LoginRequest{}).
+					Return(&domain.AuthRequest{
+						URL:       "https://accounts.google.com/o/oauth2/auth",
+						Session:   "test-session",
+						ExpiresAt: time.Now().Add(10 * time.Minute),
+					}, nil)
 			},
 			expectedStatus: http.StatusTemporaryRedirect,
 		},
 		{
 			name:   "Auth - Callback Success",
 			method: http.MethodGet,
-			path:   "/api/v1/auth/callback?code=test-code",
+			path:   "/api/v1/auth/callback?code=test-code&state=test-state",
+			cookies: []*http.Cookie{
+				{Name: "oidc_login", Value: "test-session"},
+			},
 			setupAuth: func(_ *testing.T, service *serviceMock.AuthService) {
-				service.On("HandleCallback", mock.Anything, "test-code").
+				service.On("HandleCallback", mock.Anything, domain.AuthCallback{
+					Code:    "test-code",
+					State:   "test-state",
+					Session: "test-session",
+					Client:  domain.ClientInfo{IPAddress: "192.0.2.1"},
+				}).
 					Return(&domain.AuthResponse{
 						AccessToken: "test-access-token",
 						User: &domain.User{
@@ -86,14 +175,127 @@ func TestNewRouter(t *testing.T) {
 			},
 			expectedStatus: http.StatusOK,
 		},
+		{
+			name:           "Auth - Callback Without Login Session",
+			method:         http.MethodGet,
+			path:           "/api/v1/auth/callback?code=test-code&state=test-state",
+			setupAuth:      func(_ *testing.T, _ *serviceMock.AuthService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Auth - Password Login Is Public",
+			method:         http.MethodPost,
+			path:           "/api/v1/auth/login/password",
+			setupAuth:      func(_ *testing.T, _ *serviceMock.AuthService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Auth - MFA Verify Is Public",
+			method:         http.MethodPost,
+			path:           "/api/v1/auth/mfa/verify",
+			setupAuth:      func(_ *testing.T, _ *serviceMock.AuthService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Auth - MFA Enrollment Requires Authentication",
+			method:         http.MethodPost,
+			path:           "/api/v1/auth/mfa/enroll",
+			setupAuth:      func(_ *testing.T, _ *serviceMock.AuthService) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Auth - Sessions Require Authentication",
+			method:         http.MethodGet,
+			path:           "/api/v1/auth/sessions",
+			setupAuth:      func(_ *testing.T, _ *serviceMock.AuthService) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Admin - Force Logout Requires Authentication",
+			method:         http.MethodPost,
+			path:           "/api/v1/admin/users/" + uuid.NewString() + "/logout",
+			setupAuth:      func(_ *testing.T, _ *serviceMock.AuthService) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Admin - Metrics Require Authentication",
+			method:         http.MethodGet,
+			path:           "/api/v1/admin/metrics",
+			setupAuth:      func(_ *testing.T, _ *serviceMock.AuthService) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Categories - Create Requires Authentication",
+			method:         http.MethodPost,
+			path:           "/api/v1/categories",
+			setupAuth:      func(_ *testing.T, _ *serviceMock.AuthService) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "Permissions - Picker Cannot Create Products",
+			method: http.MethodPost,
+			path:   "/api/v1/products",
+			setupAuth: signedIn(
+				domain.PickerRole,
+				domain.PermissionOrdersRead,
+			),
+			bearer:         true,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Permissions - Customer Cannot List Roles",
+			method:         http.MethodGet,
+			path:           "/api/v1/admin/roles",
+			setupAuth:      signedIn(domain.CustomerRole),
+			bearer:         true,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "Permissions - Granted Permission Lists Permissions",
+			method: http.MethodGet,
+			path:   "/api/v1/admin/permissions",
+			setupAuth: signedIn(
+				domain.StoreManagerRole,
+				domain.PermissionRolesManage,
+			),
+			setupRole: func(_ *testing.T, service *serviceMock.RoleService) {
+				service.On("ListPermissions").
+					Return(domain.AllPermissions())
+			},
+			bearer:         true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:      "Permissions - Admin Holds Every Permission",
+			method:    http.MethodGet,
+			path:      "/api/v1/admin/roles",
+			setupAuth: signedIn(domain.AdminRole),
+			setupRole: func(_ *testing.T, service *serviceMock.RoleService) {
+				service.On("List", mock.Anything).
+					Return([]domain.Role{{Name: domain.AdminRole}}, nil)
+			},
+			bearer:         true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Auth - JWKS",
+			method: http.MethodGet,
+			path:   "/.well-known/jwks.json",
+			setupAuth: func(_ *testing.T, service *serviceMock.AuthService) {
+				service.On("GetJWKS").Return(jwt.JWKSet{
+					Keys: []jwt.JWK{{KeyType: "OKP", KeyID: "1"}},
+				})
+			},
+			expectedStatus: http.StatusOK,
+		},
 		{
 			name:      "Public - Get Categories",
 			method:    http.MethodGet,
 			path:      "/api/v1/categories",
 			setupAuth: func(_ *testing.T, _ *serviceMock.AuthService) {},
 			setupCategory: func(_ *testing.T, service *serviceMock.CategoryService) {
-				service.On("List", mock.Anything).
-					Return([]domain.Category{}, nil)
+				service.On("List", mock.Anything, mock.Anything).
+					Return(&domain.Page[domain.Category]{Items: []domain.Category{}}, nil)
 			},
 			expectedStatus: http.StatusOK,
 		},
@@ -103,11 +305,210 @@ func TestNewRouter(t *testing.T) {
 			path:      "/api/v1/products",
 			setupAuth: func(_ *testing.T, _ *serviceMock.AuthService) {},
 			setupProduct: func(_ *testing.T, service *serviceMock.ProductService) {
+				service.On("List", mock.Anything, mock.Anything).
+					Return(&domain.Page[domain.Product]{Items: []domain.Product{}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Orders - Customer Cannot List All",
+			method:         http.MethodGet,
+			path:           "/api/v1/orders/all",
+			setupAuth:      signedIn(domain.CustomerRole, domain.PermissionOrdersCreate),
+			bearer:         true,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:      "Orders - Customer Cannot List Another Customer's",
+			method:    http.MethodGet,
+			path:      "/api/v1/orders/customer/" + uuid.NewString(),
+			setupAuth: signedIn(domain.CustomerRole, domain.PermissionOrdersCreate),
+			setupCustomer: func(_ *testing.T, service *serviceMock.CustomerService) {
+				service.On("GetByUserID", mock.Anything, mock.Anything).
+					Return(&domain.Customer{ID: uuid.New()}, nil)
+			},
+			bearer:         true,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Orders - Driver Cannot Add Items",
+			method:         http.MethodPost,
+			path:           "/api/v1/orders/" + uuid.NewString() + "/items",
+			setupAuth:      signedIn(domain.DriverRole, domain.PermissionOrdersRead),
+			bearer:         true,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "Orders - Customer Cannot Remove Another Customer's Item",
+			method: http.MethodDelete,
+			path: "/api/v1/orders/" + uuid.NewString() +
+				"/items/" + uuid.NewString(),
+			setupAuth: signedIn(domain.CustomerRole, domain.PermissionOrdersCreate),
+			setupCustomer: func(_ *testing.T, service *serviceMock.CustomerService) {
+				service.On("GetByUserID", mock.Anything, mock.Anything).
+					Return(&domain.Customer{ID: uuid.New()}, nil)
+			},
+			setupOrder: func(_ *testing.T, service *serviceMock.OrderService) {
+				service.On("GetByID", mock.Anything, mock.Anything).
+					Return(&domain.Order{CustomerID: uuid.New()}, nil)
+			},
+			bearer:         true,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Step-Up - Delete Product Without MFA",
+			method:         http.MethodDelete,
+			path:           "/api/v1/products/" + uuid.NewString(),
+			setupAuth:      signedIn(domain.AdminRole),
+			bearer:         true,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:      "Step-Up - Delete Product After Recent MFA",
+			method:    http.MethodDelete,
+			path:      "/api/v1/products/" + uuid.NewString(),
+			setupAuth: steppedUp(time.Now().Add(-time.Minute), domain.AdminRole),
+			setupProduct: func(_ *testing.T, service *serviceMock.ProductService) {
+				service.On("Delete", mock.Anything, mock.Anything).Return(nil)
+			},
+			bearer:         true,
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "Step-Up - Refund After Stale MFA",
+			method:         http.MethodPost,
+			path:           "/api/v1/orders/" + uuid.NewString() + "/refund",
+			setupAuth:      steppedUp(time.Now().Add(-time.Hour), domain.AdminRole),
+			bearer:         true,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Step-Up - Create Role Without MFA",
+			method:         http.MethodPost,
+			path:           "/api/v1/admin/roles",
+			setupAuth:      signedIn(domain.AdminRole),
+			bearer:         true,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Step-Up - Update Role After Stale MFA",
+			method:         http.MethodPut,
+			path:           "/api/v1/admin/roles/picker",
+			setupAuth:      steppedUp(time.Now().Add(-time.Hour), domain.AdminRole),
+			bearer:         true,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:      "Step-Up - Delete Role After Recent MFA",
+			method:    http.MethodDelete,
+			path:      "/api/v1/admin/roles/picker",
+			setupAuth: steppedUp(time.Now().Add(-time.Minute), domain.AdminRole),
+			setupRole: func(_ *testing.T, service *serviceMock.RoleService) {
+				service.On("Delete", mock.Anything, "picker").Return(nil)
+			},
+			bearer:         true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Step-Up - Reset User MFA Without MFA",
+			method:         http.MethodDelete,
+			path:           "/api/v1/admin/users/" + uuid.NewString() + "/mfa",
+			setupAuth:      signedIn(domain.AdminRole),
+			bearer:         true,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "API Key - Route In Scope",
+			method:         http.MethodGet,
+			path:           "/api/v1/admin/metrics",
+			setupAuth:      func(_ *testing.T, _ *serviceMock.AuthService) {},
+			setupAPIKey:    scopedKey(domain.PermissionMetricsRead),
+			apiKey:         true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "API Key - Route Out Of Scope",
+			method:         http.MethodGet,
+			path:           "/api/v1/admin/metrics",
+			setupAuth:      func(_ *testing.T, _ *serviceMock.AuthService) {},
+			setupAPIKey:    scopedKey(domain.PermissionInventoryRead),
+			apiKey:         true,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:      "API Key - Invalid Key",
+			method:    http.MethodGet,
+			path:      "/api/v1/admin/metrics",
+			setupAuth: func(_ *testing.T, _ *serviceMock.AuthService) {},
+			setupAPIKey: func(_ *testing.T, service *serviceMock.APIKeyService) {
+				service.On("Authenticate", mock.Anything, "gsk_test-key").
+					Return(nil, customErrors.ErrInvalidAPIKey)
+			},
+			apiKey:         true,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "API Key - Step-Up Route Rejected",
+			method:         http.MethodDelete,
+			path:           "/api/v1/products/" + uuid.NewString(),
+			setupAuth:      func(_ *testing.T, _ *serviceMock.AuthService) {},
+			setupAPIKey:    scopedKey(domain.PermissionProductsWrite),
+			apiKey:         true,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "API Key - User Route Rejected",
+			method:         http.MethodGet,
+			path:           "/api/v1/auth/sessions",
+			setupAuth:      func(_ *testing.T, _ *serviceMock.AuthService) {},
+			setupAPIKey:    scopedKey(domain.PermissionUsersManage),
+			apiKey:         true,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Admin - API Keys Require Permission",
+			method:         http.MethodGet,
+			path:           "/api/v1/admin/api-keys",
+			setupAuth:      signedIn(domain.CustomerRole),
+			bearer:         true,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:      "Admin - List API Keys",
+			method:    http.MethodGet,
+			path:      "/api/v1/admin/api-keys",
+			setupAuth: signedIn(domain.CustomerRole, domain.PermissionAPIKeysManage),
+			setupAPIKey: func(_ *testing.T, service *serviceMock.APIKeyService) {
 				service.On("List", mock.Anything).
-					Return([]domain.Product{}, nil)
+					Return([]domain.APIKey{}, nil)
 			},
+			bearer:         true,
 			expectedStatus: http.StatusOK,
 		},
+		{
+			name:           "Step-Up - Create API Key Without MFA",
+			method:         http.MethodPost,
+			path:           "/api/v1/admin/api-keys",
+			setupAuth:      signedIn(domain.AdminRole),
+			bearer:         true,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Step-Up - Update API Key After Stale MFA",
+			method:         http.MethodPut,
+			path:           "/api/v1/admin/api-keys/" + uuid.NewString(),
+			setupAuth:      steppedUp(time.Now().Add(-time.Hour), domain.AdminRole),
+			bearer:         true,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Step-Up - Revoke API Key Without MFA",
+			method:         http.MethodDelete,
+			path:           "/api/v1/admin/api-keys/" + uuid.NewString(),
+			setupAuth:      signedIn(domain.AdminRole),
+			bearer:         true,
+			expectedStatus: http.StatusUnauthorized,
+		},
 	}
 
 	for _, tt := range tests {
@@ -117,6 +518,8 @@ func TestNewRouter(t *testing.T) {
 			productService.ExpectedCalls = nil
 			categoryService.ExpectedCalls = nil
 			orderService.ExpectedCalls = nil
+			roleService.ExpectedCalls = nil
+			apiKeyService.ExpectedCalls = nil
 
 			tt.setupAuth(t, authService)
 
@@ -124,16 +527,36 @@ func TestNewRouter(t *testing.T) {
 				tt.setupCategory(t, categoryService)
 			}
 
+			if tt.setupCustomer != nil {
+				tt.setupCustomer(t, customerService)
+			}
+
 			if tt.setupProduct != nil {
 				tt.setupProduct(t, productService)
 			}
 
+			if tt.setupOrder != nil {
+				tt.setupOrder(t, orderService)
+			}
+
+			if tt.setupRole != nil {
+				tt.setupRole(t, roleService)
+			}
+
+			if tt.setupAPIKey != nil {
+				tt.setupAPIKey(t, apiKeyService)
+			}
+
 			req := httptest.NewRequest(
 				tt.method,
 				tt.path,
 				nil,
 			)
 
+			for _, cookie := range tt.cookies {
+				req.AddCookie(cookie)
+			}
+
 			if tt.method == http.MethodPost ||
 				strings.Contains(tt.path, "/orders/") {
 				req.Header.Set(
@@ -142,6 +565,14 @@ func TestNewRouter(t *testing.T) {
 				)
 			}
 
+			if tt.bearer {
+				req.Header.Set("Authorization", "Bearer test-token")
+			}
+
+			if tt.apiKey {
+				req.Header.Set("X-API-Key", "gsk_test-key")
+			}
+
 			w := httptest.NewRecorder()
 			router.ServeHTTP(w, req)
 
@@ -161,25 +592,177 @@ func TestRouterMiddleware(t *testing.T) {
 	productService := serviceMock.NewProductService(t)
 	categoryService := serviceMock.NewCategoryService(t)
 	orderService := serviceMock.NewOrderService(t)
+	cartService := serviceMock.NewCartService(t)
+	outboxService := serviceMock.NewOutboxService(t)
+	inventoryService := serviceMock.NewInventoryService(t)
 
 	router := NewRouter(
 		handler.NewAuthHandler(authService),
 		handler.NewCustomerHandler(customerService),
 		handler.NewProductHandler(productService),
 		handler.NewCategoryHandler(categoryService),
-		handler.NewOrderHandler(orderService),
+		handler.NewOrderHandler(orderService, customerService),
+		handler.NewCartHandler(cartService),
+		handler.NewOutboxHandler(outboxService),
+		handler.NewInventoryHandler(inventoryService),
+		handler.NewRoleHandler(serviceMock.NewRoleService(t)),
+		handler.NewAPIKeyHandler(serviceMock.NewAPIKeyService(t)),
 		authService,
+		serviceMock.NewAPIKeyService(t),
+		serviceMock.NewIdempotencyService(t),
+		10*time.Minute,
+		customMiddleware.RateLimits{},
+		nil,
 	)
 
 	middlewares := getMiddlewareStack(router)
 
 	assert.Contains(t, middlewares, "RequestID")
-	assert.Contains(t, middlewares, "RealIP")
 	assert.Contains(t, middlewares, "Logger")
 	assert.Contains(t, middlewares, "Recoverer")
 	assert.Contains(t, middlewares, "Logging")
 }
 
+func TestRouterRateLimit(t *testing.T) {
+	authService := serviceMock.NewAuthService(t)
+	customerService := serviceMock.NewCustomerService(t)
+	productService := serviceMock.NewProductService(t)
+
+	router := NewRouter(
+		handler.NewAuthHandler(authService),
+		handler.NewCustomerHandler(customerService),
+		handler.NewProductHandler(productService),
+		handler.NewCategoryHandler(serviceMock.NewCategoryService(t)),
+		handler.NewOrderHandler(
+			serviceMock.NewOrderService(t),
+			customerService,
+		),
+		handler.NewCartHandler(serviceMock.NewCartService(t)),
+		handler.NewOutboxHandler(serviceMock.NewOutboxService(t)),
+		handler.NewInventoryHandler(serviceMock.NewInventoryService(t)),
+		handler.NewRoleHandler(serviceMock.NewRoleService(t)),
+		handler.NewAPIKeyHandler(serviceMock.NewAPIKeyService(t)),
+		authService,
+		serviceMock.NewAPIKeyService(t),
+		serviceMock.NewIdempotencyService(t),
+		10*time.Minute,
+		customMiddleware.RateLimits{
+			Store: ratelimit.NewMemoryStore(),
+			IP: ratelimit.Policy{
+				Name:   "ip",
+				Limit:  5,
+				Window: time.Minute,
+			},
+			Default: ratelimit.Policy{
+				Name:   "default",
+				Limit:  100,
+				Window: time.Minute,
+			},
+			Auth: ratelimit.Policy{
+				Name:   "auth",
+				Limit:  2,
+				Window: time.Minute,
+			},
+		},
+		[]*net.IPNet{{
+			IP:   net.IPv4(10, 0, 0, 0),
+			Mask: net.CIDRMask(8, 32),
+		}},
+	)
+
+	// refresh sends a request through the trusted proxy 10.0.0.1 on
+	// behalf of ip.
+	refresh := func(ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(
+			http.MethodPost,
+			"/api/v1/auth/refresh",
+			strings.NewReader("{"),
+		)
+		req.RemoteAddr = "10.0.0.1:4000"
+		req.Header.Set("X-Forwarded-For", ip)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Auth Routes Use The Auth Policy", func(t *testing.T) {
+		w := refresh("192.0.2.10")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "2;w=60", w.Header().Get("RateLimit-Policy"))
+
+		assert.Equal(t, http.StatusBadRequest, refresh("192.0.2.10").Code)
+
+		w = refresh("192.0.2.10")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "30", w.Header().Get("Retry-After"))
+	})
+
+	t.Run("Clients Are Limited Separately", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, refresh("192.0.2.11").Code)
+	})
+
+	t.Run("Forwarded Address Ignored From Untrusted Peers", func(t *testing.T) {
+		req := httptest.NewRequest(
+			http.MethodPost,
+			"/api/v1/auth/refresh",
+			strings.NewReader("{"),
+		)
+		req.RemoteAddr = "192.0.2.10:4000"
+		req.Header.Set("X-Forwarded-For", "198.51.100.7")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	})
+
+	t.Run("Credentials Are Checked Within The IP Limit", func(t *testing.T) {
+		authService.On("VerifyAccessToken", mock.Anything, "guessed-token").
+			Return(nil, customErrors.ErrInvalidToken).Times(5)
+
+		send := func() int {
+			req := httptest.NewRequest(
+				http.MethodGet,
+				"/api/v1/customers/me",
+				nil,
+			)
+			req.RemoteAddr = "192.0.2.12:4000"
+			req.Header.Set("Authorization", "Bearer guessed-token")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w.Code
+		}
+
+		for i := 0; i < 5; i++ {
+			assert.Equal(t, http.StatusUnauthorized, send())
+		}
+		assert.Equal(t, http.StatusTooManyRequests, send())
+	})
+
+	t.Run("Authenticated Callers Are Limited Per User", func(t *testing.T) {
+		userID := uuid.New()
+		authService.On("VerifyAccessToken", mock.Anything, "test-token").
+			Return(&domain.AccessTokenClaims{UserID: userID}, nil)
+		customerService.On("GetByUserID", mock.Anything, userID.String()).
+			Return(nil, customErrors.ErrCustomerNotFound)
+
+		req := httptest.NewRequest(
+			http.MethodGet,
+			"/api/v1/customers/me",
+			nil,
+		)
+		req.Header.Set("Authorization", "Bearer test-token")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "100", w.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "99", w.Header().Get("RateLimit-Remaining"))
+	})
+}
+
 func getMiddlewareStack(router *chi.Mux) []string {
 	var middlewareList []string
 	walkFn := func(_, _ string, _ http.Handler, middlewares ...func(http.Handler) http.Handler) error {
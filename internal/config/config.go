@@ -2,10 +2,20 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/grocery-service/utils/jwt"
+)
+
+const (
+	// RequestTimeout is how long the router lets a request run.
+	RequestTimeout = 60 * time.Second
+	// WriteTimeout is how long the server takes to write a response.
+	WriteTimeout = 15 * time.Second
 )
 
 type Config struct {
@@ -14,13 +24,26 @@ type Config struct {
 	TestDatabase TestDatabaseConfig
 	JWT          JWTConfig
 	OAuth        OAuthConfig
+	PasswordAuth PasswordAuthConfig
+	MFA          MFAConfig
+	RateLimit    RateLimitConfig
+	Idempotency  IdempotencyConfig
 	SMTP         SMTPConfig
 	SMS          SMSConfig
+	Outbox       OutboxConfig
+	TokenCleanup TokenCleanupConfig
+	Order        OrderConfig
+	Alert        AlertConfig
 }
 
+// ServerConfig configures the HTTP server. TrustedProxies lists the
+// reverse proxies, as CIDR ranges or IP addresses, whose
+// X-Forwarded-For header is believed. Without them clients are known by
+// the address they connect from.
 type ServerConfig struct {
-	Port    int    `env:"SERVER_PORT"     default:"8080"`
-	BaseURL string `env:"SERVER_BASE_URL" default:"http://localhost:8080"`
+	Port           int      `env:"SERVER_PORT"     default:"8080"`
+	BaseURL        string   `env:"SERVER_BASE_URL" default:"http://localhost:8080"`
+	TrustedProxies []string `env:"TRUSTED_PROXIES"`
 }
 
 type DatabaseConfig struct {
@@ -43,8 +66,15 @@ type TestDatabaseConfig struct {
 
 type JWTConfig struct {
 	Secret        string        `env:"JWT_SECRET"         required:"true"`
+	KeyID         string        `env:"JWT_KEY_ID"                         default:"1"`
 	Issuer        string        `env:"JWT_ISSUER"                         default:"grocery-service"`
-	TokenDuration time.Duration `env:"JWT_TOKEN_DURATION"                 default:"24h"`
+	TokenDuration time.Duration `env:"JWT_TOKEN_DURATION"                 default:"15m"`
+	// RetiredKeys are previous signing keys, written as "kid:secret",
+	// that still verify tokens issued before a key rotation.
+	RetiredKeys []string `env:"JWT_RETIRED_KEYS"`
+	// TokenHashKey keys the HMAC under which issued tokens are stored.
+	// Changing it invalidates every stored token.
+	TokenHashKey string `env:"TOKEN_HASH_KEY" required:"true"`
 }
 
 type SMTPConfig struct {
@@ -64,15 +94,126 @@ type SMSConfig struct {
 	BaseURL     string `env:"SMS_BASE_URL"`
 }
 
+// OutboxConfig controls the background dispatcher that delivers
+// outbox events. Failed deliveries are retried with exponential backoff
+// starting at BaseBackoff and capped at MaxBackoff; after MaxAttempts
+// the event is dead-lettered.
+type OutboxConfig struct {
+	Enabled      bool          `env:"OUTBOX_ENABLED"       default:"true"`
+	PollInterval time.Duration `env:"OUTBOX_POLL_INTERVAL" default:"5s"`
+	BatchSize    int           `env:"OUTBOX_BATCH_SIZE"    default:"20"`
+	MaxAttempts  int           `env:"OUTBOX_MAX_ATTEMPTS"  default:"8"`
+	BaseBackoff  time.Duration `env:"OUTBOX_BASE_BACKOFF"  default:"10s"`
+	MaxBackoff   time.Duration `env:"OUTBOX_MAX_BACKOFF"   default:"1h"`
+	Lease        time.Duration `env:"OUTBOX_LEASE"         default:"2m"`
+}
+
+// TokenCleanupConfig controls the background job that deletes stored
+// tokens. Expired tokens are deleted on the next run; revoked tokens are
+// kept for RevokedRetention so that reuse of a rotated refresh token is
+// still detected. Rows are deleted BatchSize at a time.
+type TokenCleanupConfig struct {
+	Enabled          bool          `env:"TOKEN_CLEANUP_ENABLED"           default:"true"`
+	Interval         time.Duration `env:"TOKEN_CLEANUP_INTERVAL"          default:"1h"`
+	BatchSize        int           `env:"TOKEN_CLEANUP_BATCH_SIZE"        default:"1000"`
+	RevokedRetention time.Duration `env:"TOKEN_CLEANUP_REVOKED_RETENTION" default:"168h"`
+}
+
+// OrderConfig controls order processing. TransitionsFile points to a
+// JSON order status transitions definition; when empty the built-in
+// lifecycle is used.
+type OrderConfig struct {
+	TransitionsFile string `env:"ORDER_TRANSITIONS_FILE"`
+}
+
+// AlertConfig controls admin alerts such as low-stock digests. Alerts
+// are emailed to AdminEmails and, when WebhookURL is set, posted to it
+// as JSON.
+type AlertConfig struct {
+	AdminEmails []string `env:"ALERT_ADMIN_EMAILS"`
+	WebhookURL  string   `env:"ALERT_WEBHOOK_URL"`
+}
+
+// PasswordAuthConfig controls email and password sign-in. After
+// MaxFailedLogins wrong passwords in a row an account is locked for
+// LockoutDuration. Verification and password reset emails link to
+// LinkBaseURL, the client app, which posts the token back to the API.
+type PasswordAuthConfig struct {
+	MaxFailedLogins int           `env:"AUTH_MAX_FAILED_LOGINS"      default:"5"`
+	LockoutDuration time.Duration `env:"AUTH_LOCKOUT_DURATION"       default:"15m"`
+	VerificationTTL time.Duration `env:"AUTH_EMAIL_VERIFICATION_TTL" default:"48h"`
+	ResetTTL        time.Duration `env:"AUTH_PASSWORD_RESET_TTL"     default:"1h"`
+	LinkBaseURL     string        `env:"AUTH_LINK_BASE_URL"          default:"http://localhost:3000"`
+}
+
+// MFAConfig controls TOTP multi-factor authentication. Issuer names the
+// service in authenticator apps. A login waiting for its second factor
+// expires after ChallengeTTL, and sensitive actions need an MFA check
+// no older than StepUpMaxAge.
+type MFAConfig struct {
+	Issuer       string        `env:"MFA_ISSUER"          default:"Grocery Service"`
+	ChallengeTTL time.Duration `env:"MFA_CHALLENGE_TTL"   default:"5m"`
+	StepUpMaxAge time.Duration `env:"MFA_STEP_UP_MAX_AGE" default:"10m"`
+}
+
+// RateLimitConfig controls per-client rate limiting. Each client may
+// make Requests requests per Window, in bursts of up to Requests, and
+// AuthRequests per AuthWindow on the /auth routes. Before any of these,
+// each IP address may make IPRequests API requests per IPWindow
+// whoever it signs in as. Store is "memory",
+// which limits clients per replica, or "postgres", which shares the
+// limits between replicas. Idle clients are forgotten every
+// PruneInterval.
+type RateLimitConfig struct {
+	Enabled       bool          `env:"RATE_LIMIT_ENABLED"        default:"true"`
+	Store         string        `env:"RATE_LIMIT_STORE"          default:"memory"`
+	Requests      int           `env:"RATE_LIMIT_REQUESTS"       default:"300"`
+	Window        time.Duration `env:"RATE_LIMIT_WINDOW"         default:"1m"`
+	IPRequests    int           `env:"RATE_LIMIT_IP_REQUESTS"    default:"600"`
+	IPWindow      time.Duration `env:"RATE_LIMIT_IP_WINDOW"      default:"1m"`
+	AuthRequests  int           `env:"RATE_LIMIT_AUTH_REQUESTS"  default:"20"`
+	AuthWindow    time.Duration `env:"RATE_LIMIT_AUTH_WINDOW"    default:"1m"`
+	PruneInterval time.Duration `env:"RATE_LIMIT_PRUNE_INTERVAL" default:"5m"`
+}
+
+// IdempotencyConfig controls Idempotency-Key support. A key replays its
+// first response for TTL, after which it can be used again; expired
+// keys are deleted every CleanupInterval. A key whose first request has
+// not finished within LockTimeout can be claimed by a retry, so it must
+// be longer than any request can run.
+type IdempotencyConfig struct {
+	TTL             time.Duration `env:"IDEMPOTENCY_KEY_TTL"          default:"24h"`
+	CleanupInterval time.Duration `env:"IDEMPOTENCY_CLEANUP_INTERVAL" default:"1h"`
+	LockTimeout     time.Duration `env:"IDEMPOTENCY_LOCK_TIMEOUT"     default:"2m"`
+}
+
 type OAuthConfig struct {
-	ClientID          string   `env:"OAUTH_CLIENT_ID"          required:"true"`
-	ClientSecret      string   `env:"OAUTH_CLIENT_SECRET"      required:"true"`
-	RedirectURL       string   `env:"OAUTH_REDIRECT_URL"       required:"true"`
-	Scopes            []string `env:"OAUTH_SCOPES"`
-	ProviderURL       string   `env:"OAUTH_PROVIDER_URL"       required:"true"`
-	AuthorizeEndpoint string   `env:"OAUTH_AUTHORIZE_ENDPOINT"                 default:"/authorize"`
-	TokenEndpoint     string   `env:"OAUTH_TOKEN_ENDPOINT"                     default:"/oauth/token"`
-	UserInfoEndpoint  string   `env:"OAUTH_USERINFO_ENDPOINT"                  default:"/userinfo"`
+	RedirectURL string `env:"OAUTH_REDIRECT_URL" required:"true"`
+	// DefaultProvider is used when a login does not name a provider. It
+	// defaults to the first configured provider.
+	DefaultProvider string `env:"OAUTH_DEFAULT_PROVIDER"`
+	// AllowedRedirects are the origins, optionally with a path prefix,
+	// that a login may return the user to with redirect_to. Relative
+	// paths are always allowed.
+	AllowedRedirects []string `env:"OAUTH_ALLOWED_REDIRECTS"`
+	// Providers are listed by name in OAUTH_PROVIDERS; see
+	// OIDCProviderConfig for the variables read for each.
+	Providers []OIDCProviderConfig `env:"OAUTH_PROVIDERS"`
+}
+
+// OIDCProviderConfig configures one OpenID Connect provider. Its
+// endpoints and signing keys are discovered from IssuerURL. Each provider
+// named in OAUTH_PROVIDERS is read from OAUTH_<NAME>_ISSUER_URL,
+// OAUTH_<NAME>_CLIENT_ID, OAUTH_<NAME>_CLIENT_SECRET and
+// OAUTH_<NAME>_SCOPES. Without OAUTH_PROVIDERS a single provider named
+// OAUTH_PROVIDER_NAME is read from OAUTH_PROVIDER_URL, OAUTH_CLIENT_ID,
+// OAUTH_CLIENT_SECRET and OAUTH_SCOPES.
+type OIDCProviderConfig struct {
+	Name         string
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
 }
 
 func Load() (*Config, error) {
@@ -88,6 +229,7 @@ func Load() (*Config, error) {
 				"SERVER_BASE_URL",
 				"http://localhost:8080",
 			),
+			TrustedProxies: getEnvAsStringSlice("TRUSTED_PROXIES", nil),
 		},
 
 		Database: DatabaseConfig{
@@ -101,11 +243,14 @@ func Load() (*Config, error) {
 
 		JWT: JWTConfig{
 			Secret: getEnv("JWT_SECRET", ""),
+			KeyID:  getEnv("JWT_KEY_ID", "1"),
 			Issuer: getEnv("JWT_ISSUER", "grocery-service"),
 			TokenDuration: getEnvAsDuration(
 				"JWT_TOKEN_DURATION",
-				24*time.Hour,
+				15*time.Minute,
 			),
+			RetiredKeys:  getEnvAsStringSlice("JWT_RETIRED_KEYS", nil),
+			TokenHashKey: getEnv("TOKEN_HASH_KEY", ""),
 		},
 
 		SMTP: SMTPConfig{
@@ -128,33 +273,123 @@ func Load() (*Config, error) {
 			),
 		},
 
+		Outbox: OutboxConfig{
+			Enabled: getEnvAsBool("OUTBOX_ENABLED", true),
+			PollInterval: getEnvAsDuration(
+				"OUTBOX_POLL_INTERVAL",
+				5*time.Second,
+			),
+			BatchSize:   getEnvAsInt("OUTBOX_BATCH_SIZE", 20),
+			MaxAttempts: getEnvAsInt("OUTBOX_MAX_ATTEMPTS", 8),
+			BaseBackoff: getEnvAsDuration(
+				"OUTBOX_BASE_BACKOFF",
+				10*time.Second,
+			),
+			MaxBackoff: getEnvAsDuration(
+				"OUTBOX_MAX_BACKOFF",
+				time.Hour,
+			),
+			Lease: getEnvAsDuration("OUTBOX_LEASE", 2*time.Minute),
+		},
+
+		TokenCleanup: TokenCleanupConfig{
+			Enabled: getEnvAsBool("TOKEN_CLEANUP_ENABLED", true),
+			Interval: getEnvAsDuration(
+				"TOKEN_CLEANUP_INTERVAL",
+				time.Hour,
+			),
+			BatchSize: getEnvAsInt("TOKEN_CLEANUP_BATCH_SIZE", 1000),
+			RevokedRetention: getEnvAsDuration(
+				"TOKEN_CLEANUP_REVOKED_RETENTION",
+				7*24*time.Hour,
+			),
+		},
+
+		Order: OrderConfig{
+			TransitionsFile: getEnv("ORDER_TRANSITIONS_FILE", ""),
+		},
+
+		Alert: AlertConfig{
+			AdminEmails: getEnvAsStringSlice("ALERT_ADMIN_EMAILS", nil),
+			WebhookURL:  getEnv("ALERT_WEBHOOK_URL", ""),
+		},
+
+		PasswordAuth: PasswordAuthConfig{
+			MaxFailedLogins: getEnvAsInt("AUTH_MAX_FAILED_LOGINS", 5),
+			LockoutDuration: getEnvAsDuration(
+				"AUTH_LOCKOUT_DURATION",
+				15*time.Minute,
+			),
+			VerificationTTL: getEnvAsDuration(
+				"AUTH_EMAIL_VERIFICATION_TTL",
+				48*time.Hour,
+			),
+			ResetTTL: getEnvAsDuration("AUTH_PASSWORD_RESET_TTL", time.Hour),
+			LinkBaseURL: getEnv(
+				"AUTH_LINK_BASE_URL",
+				"http://localhost:3000",
+			),
+		},
+
+		MFA: MFAConfig{
+			Issuer:       getEnv("MFA_ISSUER", "Grocery Service"),
+			ChallengeTTL: getEnvAsDuration("MFA_CHALLENGE_TTL", 5*time.Minute),
+			StepUpMaxAge: getEnvAsDuration(
+				"MFA_STEP_UP_MAX_AGE",
+				10*time.Minute,
+			),
+		},
+
+		RateLimit: RateLimitConfig{
+			Enabled:  getEnvAsBool("RATE_LIMIT_ENABLED", true),
+			Store:    getEnv("RATE_LIMIT_STORE", "memory"),
+			Requests: getEnvAsInt("RATE_LIMIT_REQUESTS", 300),
+			Window:   getEnvAsDuration("RATE_LIMIT_WINDOW", time.Minute),
+			IPRequests: getEnvAsInt(
+				"RATE_LIMIT_IP_REQUESTS",
+				600,
+			),
+			IPWindow: getEnvAsDuration(
+				"RATE_LIMIT_IP_WINDOW",
+				time.Minute,
+			),
+			AuthRequests: getEnvAsInt(
+				"RATE_LIMIT_AUTH_REQUESTS",
+				20,
+			),
+			AuthWindow: getEnvAsDuration(
+				"RATE_LIMIT_AUTH_WINDOW",
+				time.Minute,
+			),
+			PruneInterval: getEnvAsDuration(
+				"RATE_LIMIT_PRUNE_INTERVAL",
+				5*time.Minute,
+			),
+		},
+
+		Idempotency: IdempotencyConfig{
+			TTL: getEnvAsDuration("IDEMPOTENCY_KEY_TTL", 24*time.Hour),
+			CleanupInterval: getEnvAsDuration(
+				"IDEMPOTENCY_CLEANUP_INTERVAL",
+				time.Hour,
+			),
+			LockTimeout: getEnvAsDuration(
+				"IDEMPOTENCY_LOCK_TIMEOUT",
+				2*time.Minute,
+			),
+		},
+
 		OAuth: OAuthConfig{
-			ClientID:     getEnv("OAUTH_CLIENT_ID", ""),
-			ClientSecret: getEnv("OAUTH_CLIENT_SECRET", ""),
 			RedirectURL: getEnv(
 				"OAUTH_REDIRECT_URL",
 				"http://localhost:8080/api/v1/auth/callback",
 			),
-			Scopes: getEnvAsStringSlice(
-				"OAUTH_SCOPES",
-				[]string{
-					"openid",
-					"profile",
-					"email",
-				},
-			),
-			ProviderURL: getEnv(
-				"OAUTH_PROVIDER_URL",
-				"https://dev-vz8le2ezedv7udpb.us.auth0.com",
-			),
-			AuthorizeEndpoint: getEnv(
-				"OAUTH_AUTHORIZE_ENDPOINT",
-				"/v1/authorize",
-			),
-			TokenEndpoint: getEnv(
-				"OAUTH_TOKEN_ENDPOINT",
-				"/v1/token",
+			DefaultProvider: getEnv("OAUTH_DEFAULT_PROVIDER", ""),
+			AllowedRedirects: getEnvAsStringSlice(
+				"OAUTH_ALLOWED_REDIRECTS",
+				nil,
 			),
+			Providers: loadOIDCProviders(),
 		},
 	}
 
@@ -201,6 +436,114 @@ func (c *Config) Validate() error {
 		errors = append(errors, "JWT secret is required")
 	}
 
+	if c.JWT.TokenHashKey == "" {
+		errors = append(errors, "token hash key is required")
+	}
+
+	for _, entry := range c.JWT.RetiredKeys {
+		key, err := jwt.ParseKey(entry)
+		if err != nil {
+			errors = append(errors, err.Error())
+			break
+		}
+		if key.ID == c.JWT.KeyID {
+			errors = append(
+				errors,
+				"JWT retired keys must not reuse the active key ID",
+			)
+			break
+		}
+	}
+
+	// Token cleanup validation
+	if c.TokenCleanup.Enabled &&
+		(c.TokenCleanup.Interval <= 0 || c.TokenCleanup.BatchSize <= 0) {
+		errors = append(
+			errors,
+			"token cleanup interval and batch size must be positive",
+		)
+	}
+
+	// Password auth validation
+	if c.PasswordAuth.MaxFailedLogins <= 0 ||
+		c.PasswordAuth.LockoutDuration <= 0 {
+		errors = append(
+			errors,
+			"password auth max failed logins and lockout duration must be positive",
+		)
+	}
+
+	if c.PasswordAuth.VerificationTTL <= 0 || c.PasswordAuth.ResetTTL <= 0 {
+		errors = append(
+			errors,
+			"email verification and password reset TTLs must be positive",
+		)
+	}
+
+	if u, err := url.Parse(c.PasswordAuth.LinkBaseURL); err != nil ||
+		(u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errors = append(
+			errors,
+			"auth link base URL must be an http(s) URL",
+		)
+	}
+
+	// MFA validation
+	if c.MFA.Issuer == "" || strings.Contains(c.MFA.Issuer, ":") {
+		errors = append(
+			errors,
+			"MFA issuer is required and must not contain a colon",
+		)
+	}
+
+	if c.MFA.ChallengeTTL <= 0 || c.MFA.StepUpMaxAge <= 0 {
+		errors = append(
+			errors,
+			"MFA challenge TTL and step-up max age must be positive",
+		)
+	}
+
+	// Rate limit validation
+	if c.RateLimit.Enabled {
+		if c.RateLimit.Store != "memory" && c.RateLimit.Store != "postgres" {
+			errors = append(
+				errors,
+				"rate limit store must be memory or postgres",
+			)
+		}
+
+		if c.RateLimit.Requests <= 0 || c.RateLimit.Window <= 0 ||
+			c.RateLimit.IPRequests <= 0 || c.RateLimit.IPWindow <= 0 ||
+			c.RateLimit.AuthRequests <= 0 || c.RateLimit.AuthWindow <= 0 ||
+			c.RateLimit.PruneInterval <= 0 {
+			errors = append(
+				errors,
+				"rate limit requests, windows and prune interval must be positive",
+			)
+		}
+	}
+
+	// Idempotency validation
+	if c.Idempotency.TTL <= 0 || c.Idempotency.CleanupInterval <= 0 {
+		errors = append(
+			errors,
+			"idempotency key TTL and cleanup interval must be positive",
+		)
+	}
+
+	if c.Idempotency.LockTimeout <= RequestTimeout ||
+		c.Idempotency.LockTimeout <= WriteTimeout {
+		errors = append(
+			errors,
+			fmt.Sprintf(
+				"idempotency lock timeout must be longer than the %v "+
+					"request timeout and the %v write timeout",
+				RequestTimeout,
+				WriteTimeout,
+			),
+		)
+	}
+
 	// SMTP validation
 	if c.SMTP.Username == "" {
 		errors = append(errors, "SMTP username is required")
@@ -227,20 +570,78 @@ func (c *Config) Validate() error {
 	}
 
 	// OAuth validation
-	if c.OAuth.ClientID == "" {
+	if len(c.OAuth.Providers) == 0 {
 		errors = append(
 			errors,
-			"OAuth client ID is required",
+			"at least one OAuth provider is required",
 		)
 	}
 
-	if c.OAuth.ClientSecret == "" {
+	names := make(map[string]bool, len(c.OAuth.Providers))
+	for _, p := range c.OAuth.Providers {
+		switch {
+		case p.Name == "" || len(p.Name) > 50:
+			errors = append(
+				errors,
+				"OAuth provider names must be 1 to 50 characters",
+			)
+		case names[p.Name]:
+			errors = append(
+				errors,
+				fmt.Sprintf("OAuth provider %q is configured twice", p.Name),
+			)
+		}
+		names[p.Name] = true
+
+		if p.IssuerURL == "" {
+			errors = append(
+				errors,
+				fmt.Sprintf("OAuth provider %q issuer URL is required", p.Name),
+			)
+		}
+
+		if p.ClientID == "" {
+			errors = append(
+				errors,
+				fmt.Sprintf("OAuth provider %q client ID is required", p.Name),
+			)
+		}
+
+		if p.ClientSecret == "" {
+			errors = append(
+				errors,
+				fmt.Sprintf(
+					"OAuth provider %q client secret is required",
+					p.Name,
+				),
+			)
+		}
+	}
+
+	if c.OAuth.DefaultProvider != "" && !names[c.OAuth.DefaultProvider] {
 		errors = append(
 			errors,
-			"OAuth client secret is required",
+			fmt.Sprintf(
+				"OAuth default provider %q is not configured",
+				c.OAuth.DefaultProvider,
+			),
 		)
 	}
 
+	for _, redirect := range c.OAuth.AllowedRedirects {
+		u, err := url.Parse(strings.TrimSpace(redirect))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") ||
+			u.Host == "" {
+			errors = append(
+				errors,
+				fmt.Sprintf(
+					"OAuth allowed redirect %q must be an http(s) URL",
+					redirect,
+				),
+			)
+		}
+	}
+
 	if c.OAuth.RedirectURL == "" {
 		errors = append(
 			errors,
@@ -258,6 +659,45 @@ func (c *Config) Validate() error {
 	return nil
 }
 
+var defaultOAuthScopes = []string{"openid", "profile", "email"}
+
+func loadOIDCProviders() []OIDCProviderConfig {
+	names := getEnvAsStringSlice("OAUTH_PROVIDERS", nil)
+	if len(names) == 0 {
+		return []OIDCProviderConfig{{
+			Name: getEnv("OAUTH_PROVIDER_NAME", "auth0"),
+			IssuerURL: getEnv(
+				"OAUTH_PROVIDER_URL",
+				"https://dev-vz8le2ezedv7udpb.us.auth0.com/",
+			),
+			ClientID:     getEnv("OAUTH_CLIENT_ID", ""),
+			ClientSecret: getEnv("OAUTH_CLIENT_SECRET", ""),
+			Scopes: getEnvAsStringSlice(
+				"OAUTH_SCOPES",
+				defaultOAuthScopes,
+			),
+		}}
+	}
+
+	providers := make([]OIDCProviderConfig, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		prefix := "OAUTH_" +
+			strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		providers = append(providers, OIDCProviderConfig{
+			Name:         name,
+			IssuerURL:    getEnv(prefix+"ISSUER_URL", ""),
+			ClientID:     getEnv(prefix+"CLIENT_ID", ""),
+			ClientSecret: getEnv(prefix+"CLIENT_SECRET", ""),
+			Scopes: getEnvAsStringSlice(
+				prefix+"SCOPES",
+				defaultOAuthScopes,
+			),
+		})
+	}
+	return providers
+}
+
 func getEnv(key, defaultValue string) string {
 	if value, exists := os.LookupEnv(key); exists &&
 		value != "" {
@@ -276,6 +716,16 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value, exists := os.LookupEnv(key); exists &&
+		value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
 func getEnvAsDuration(
 	key string,
 	defaultValue time.Duration,
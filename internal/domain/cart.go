@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Cart struct {
+	ID         uuid.UUID  `json:"id"          gorm:"type:uuid;primary_key"`
+	CustomerID uuid.UUID  `json:"customer_id" gorm:"type:uuid;not null;unique"`
+	Items      []CartItem `json:"items"       gorm:"foreignKey:CartID"`
+	TotalPrice Money      `json:"total_price" gorm:"-"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// CartItem stores the unit price seen when the item was added so checkout
+// can report price changes. CurrentPrice and LineTotal are filled from the
+// live product price whenever the cart is read.
+type CartItem struct {
+	ID           uuid.UUID `json:"id"                gorm:"type:uuid;primary_key"`
+	CartID       uuid.UUID `json:"cart_id"           gorm:"type:uuid;not null;uniqueIndex:idx_cart_items_cart_product"`
+	ProductID    uuid.UUID `json:"product_id"        gorm:"type:uuid;not null;uniqueIndex:idx_cart_items_cart_product"`
+	Product      *Product  `json:"product,omitempty" gorm:"foreignKey:ProductID"`
+	Quantity     int       `json:"quantity"          gorm:"not null"`
+	UnitPrice    Money     `json:"unit_price"        gorm:"not null"`
+	CurrentPrice Money     `json:"current_price"     gorm:"-"`
+	LineTotal    Money     `json:"line_total"        gorm:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type CartPriceChange struct {
+	ProductID uuid.UUID `json:"product_id"`
+	OldPrice  Money     `json:"old_price"`
+	NewPrice  Money     `json:"new_price"`
+}
+
+type AddCartItemRequest struct {
+	ProductID uuid.UUID `json:"product_id" validate:"required"`
+	Quantity  int       `json:"quantity"   validate:"required,gt=0"`
+}
+
+type UpdateCartItemRequest struct {
+	Quantity int `json:"quantity" validate:"required,gt=0"`
+}
+
+type CheckoutRequest struct {
+	AcceptPriceChanges bool `json:"accept_price_changes"`
+}
+
+// CheckoutResult is returned from a checkout attempt. Order is only set on
+// success; PriceChanges lists every item whose price moved since it was
+// added to the cart.
+type CheckoutResult struct {
+	Order        *Order            `json:"order,omitempty"`
+	PriceChanges []CartPriceChange `json:"price_changes,omitempty"`
+}
+
+func NewCart(customerID uuid.UUID) *Cart {
+	return &Cart{
+		ID:         uuid.New(),
+		CustomerID: customerID,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+}
+
+func NewCartItem(
+	cartID, productID uuid.UUID,
+	quantity int,
+	unitPrice Money,
+) *CartItem {
+	return &CartItem{
+		ID:        uuid.New(),
+		CartID:    cartID,
+		ProductID: productID,
+		Quantity:  quantity,
+		UnitPrice: unitPrice,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+func (i *CartItem) Validate() error {
+	if i.ProductID == uuid.Nil {
+		return fmt.Errorf("product ID is required")
+	}
+	if i.Quantity <= 0 {
+		return fmt.Errorf("quantity must be greater than zero")
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IdempotencyKey records a request sent with an Idempotency-Key header
+// so that a retry of it is answered with the recorded response instead
+// of running again. Keys are scoped to Client, the caller that sent
+// them. Fingerprint is a hash of the request, so that reusing a key for
+// a different request is caught. The response fields are set once the
+// first request completes; until then CompletedAt is nil and the key is
+// held until LockedUntil, after which a retry may claim it again.
+// ClaimID is new on every claim, so that a request whose key was taken
+// over cannot complete or release it for the retry that holds it now.
+type IdempotencyKey struct {
+	Client      string     `json:"client"                 gorm:"type:varchar(255);primaryKey"`
+	Key         string     `json:"key"                    gorm:"type:varchar(255);primaryKey"`
+	Fingerprint string     `json:"fingerprint"            gorm:"type:char(64);not null"`
+	ClaimID     uuid.UUID  `json:"-"                      gorm:"type:uuid;not null"`
+	StatusCode  int        `json:"status_code"            gorm:"not null;default:0"`
+	ContentType string     `json:"content_type"           gorm:"type:varchar(255);not null;default:''"`
+	Body        []byte     `json:"-"                      gorm:"type:bytea"`
+	CreatedAt   time.Time  `json:"created_at"             gorm:"not null;default:current_timestamp"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	LockedUntil time.Time  `json:"locked_until"           gorm:"not null;default:current_timestamp"`
+	ExpiresAt   time.Time  `json:"expires_at"             gorm:"not null;index"`
+}
+
+func (IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
+
+// Completed reports whether the response to the first request has been
+// recorded.
+func (k *IdempotencyKey) Completed() bool {
+	return k.CompletedAt != nil
+}
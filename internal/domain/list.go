@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SortDirection string
+
+const (
+	SortAsc  SortDirection = "asc"
+	SortDesc SortDirection = "desc"
+
+	DefaultListLimit = 20
+	MaxListLimit     = 100
+)
+
+// ListFilter holds the optional filters accepted by list endpoints.
+// Repositories apply the fields that make sense for their entity and
+// ignore the rest.
+type ListFilter struct {
+	MinPrice    *Money      `json:"min_price,omitempty"`
+	MaxPrice    *Money      `json:"max_price,omitempty"`
+	CategoryID  *uuid.UUID  `json:"category_id,omitempty"`
+	CustomerID  *uuid.UUID  `json:"customer_id,omitempty"`
+	Status      OrderStatus `json:"status,omitempty"`
+	CreatedFrom *time.Time  `json:"created_from,omitempty"`
+	CreatedTo   *time.Time  `json:"created_to,omitempty"`
+}
+
+// ListQuery describes a single page request. When Cursor is set the
+// page is resolved with keyset pagination and Page is ignored.
+type ListQuery struct {
+	Limit   int           `json:"limit"`
+	Page    int           `json:"page,omitempty"`
+	Cursor  string        `json:"cursor,omitempty"`
+	SortBy  string        `json:"sort_by,omitempty"`
+	SortDir SortDirection `json:"sort_dir,omitempty"`
+	Filter  ListFilter    `json:"filter"`
+}
+
+// Page is one page of a list result together with the metadata needed
+// to request the next one.
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	Total      int64  `json:"total"`
+	Limit      int    `json:"limit"`
+	Page       int    `json:"page,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// Normalize fills in defaults and validates the query.
+func (q *ListQuery) Normalize() error {
+	if q.Limit < 0 {
+		return fmt.Errorf("limit cannot be negative")
+	}
+	if q.Limit == 0 {
+		q.Limit = DefaultListLimit
+	}
+	if q.Limit > MaxListLimit {
+		q.Limit = MaxListLimit
+	}
+
+	if q.Page < 0 {
+		return fmt.Errorf("page cannot be negative")
+	}
+	if q.Cursor != "" {
+		q.Page = 0
+	} else if q.Page == 0 {
+		q.Page = 1
+	}
+
+	switch q.SortDir {
+	case "":
+		q.SortDir = SortAsc
+	case SortAsc, SortDesc:
+	default:
+		return fmt.Errorf("sort direction must be asc or desc")
+	}
+
+	f := q.Filter
+	if f.MinPrice != nil && f.MaxPrice != nil && f.MinPrice.Cmp(*f.MaxPrice) > 0 {
+		return fmt.Errorf("min_price cannot be greater than max_price")
+	}
+	if f.CreatedFrom != nil && f.CreatedTo != nil &&
+		f.CreatedFrom.After(*f.CreatedTo) {
+		return fmt.Errorf("created_from cannot be after created_to")
+	}
+
+	return nil
+}
+
+// Offset returns the number of rows to skip for page based queries.
+func (q ListQuery) Offset() int {
+	if q.Page <= 1 {
+		return 0
+	}
+	return (q.Page - 1) * q.Limit
+}
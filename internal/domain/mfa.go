@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MFARecoveryCode is a single-use code that passes MFA in place of a
+// TOTP code when the user has lost their authenticator. Only a keyed
+// hash of the code is stored.
+type MFARecoveryCode struct {
+	ID        uuid.UUID  `json:"id"                gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID    uuid.UUID  `json:"user_id"           gorm:"type:uuid;not null;index"`
+	CodeHash  string     `json:"-"                 gorm:"type:char(64);not null"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"        gorm:"not null;default:current_timestamp"`
+}
+
+func (MFARecoveryCode) TableName() string {
+	return "mfa_recovery_codes"
+}
+
+// MFAEnrollment is a TOTP secret offered to a user. ProvisioningURI is
+// the otpauth URI to show as a QR code; Secret is the same secret for
+// manual entry. EnrollmentToken is sent back with the first code to
+// confirm the enrollment.
+type MFAEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+	EnrollmentToken string `json:"enrollment_token,omitempty"`
+}
+
+// MFAStatus describes a user's MFA settings.
+type MFAStatus struct {
+	Enabled                bool       `json:"enabled"`
+	EnabledAt              *time.Time `json:"enabled_at,omitempty"`
+	Required               bool       `json:"required"`
+	RecoveryCodesRemaining int        `json:"recovery_codes_remaining"`
+}
+
+// MFAVerifyRequest completes a login with the MFA token of the login
+// response and a TOTP or recovery code.
+type MFAVerifyRequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code"      validate:"required"`
+}
+
+// ConfirmMFARequest enables MFA with the enrollment token and the first
+// code from the authenticator.
+type ConfirmMFARequest struct {
+	EnrollmentToken string `json:"enrollment_token" validate:"required"`
+	Code            string `json:"code"             validate:"required"`
+}
+
+// MFACodeRequest proves possession of the second factor with a TOTP or
+// recovery code.
+type MFACodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// MFARecoveryCodes are newly generated recovery codes. They are shown
+// once and cannot be retrieved later.
+type MFARecoveryCodes struct {
+	Codes []string `json:"codes"`
+}
+
+// MFARequirementRequest sets whether a user must sign in with MFA.
+type MFARequirementRequest struct {
+	Required bool `json:"required"`
+}
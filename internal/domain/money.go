@@ -0,0 +1,310 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Currency is an ISO 4217 currency code.
+type Currency string
+
+// DefaultCurrency is the currency prices are stored in. Money columns
+// hold only the amount, so every persisted value uses this currency.
+const DefaultCurrency Currency = "USD"
+
+// currencyExponents lists the number of minor unit digits for
+// currencies that do not use the usual two.
+var currencyExponents = map[Currency]int{
+	"JPY": 0,
+	"KRW": 0,
+	"UGX": 0,
+	"BHD": 3,
+	"KWD": 3,
+}
+
+// Exponent returns the number of digits after the decimal point.
+func (c Currency) Exponent() int {
+	if exp, ok := currencyExponents[c]; ok {
+		return exp
+	}
+	return 2
+}
+
+// Money is an amount in integer minor units (cents for USD) of a
+// currency. The zero value is zero in DefaultCurrency.
+//
+// Amounts with more precision than the currency allows are rounded half
+// to even. Arithmetic between different currencies is a programming
+// error and panics.
+//
+// Money is stored in DECIMAL columns and encoded in JSON as a decimal
+// string such as "12.34".
+type Money struct {
+	Amount   int64    `json:"-"`
+	Currency Currency `json:"-"`
+}
+
+// NewMoney returns amount minor units of currency.
+func NewMoney(amount int64, currency Currency) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// ParseMoney parses a decimal string such as "12.34" or "-0.5" in
+// currency, rounding half to even to the currency's minor unit.
+func ParseMoney(s string, currency Currency) (Money, error) {
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+
+	amount, err := parseMinorUnits(s, currency.Exponent())
+	if err != nil {
+		return Money{}, err
+	}
+
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+// MoneyFromFloat converts f to Money using its shortest decimal
+// representation, so 0.1 becomes exactly 10 cents.
+func MoneyFromFloat(f float64, currency Currency) Money {
+	m, err := ParseMoney(strconv.FormatFloat(f, 'f', -1, 64), currency)
+	if err != nil {
+		panic(fmt.Sprintf("money: cannot convert %v: %v", f, err))
+	}
+	return m
+}
+
+func (m Money) currency() Currency {
+	if m.Currency == "" {
+		return DefaultCurrency
+	}
+	return m.Currency
+}
+
+func (m Money) mustMatch(o Money) Currency {
+	if m.currency() != o.currency() {
+		panic(fmt.Sprintf(
+			"money: currency mismatch %s and %s",
+			m.currency(),
+			o.currency(),
+		))
+	}
+	return m.currency()
+}
+
+func (m Money) Add(o Money) Money {
+	return Money{Amount: m.Amount + o.Amount, Currency: m.mustMatch(o)}
+}
+
+func (m Money) Sub(o Money) Money {
+	return Money{Amount: m.Amount - o.Amount, Currency: m.mustMatch(o)}
+}
+
+// Mul multiplies the amount by a quantity.
+func (m Money) Mul(quantity int) Money {
+	return Money{Amount: m.Amount * int64(quantity), Currency: m.currency()}
+}
+
+// Scale multiplies the amount by factor, rounding half to even. It is
+// meant for rates such as discounts and taxes.
+func (m Money) Scale(factor float64) Money {
+	scaled := strconv.FormatFloat(float64(m.Amount)*factor, 'f', -1, 64)
+	amount, err := parseMinorUnits(scaled, 0)
+	if err != nil {
+		panic(fmt.Sprintf("money: cannot scale %s by %v", m, factor))
+	}
+	return Money{Amount: amount, Currency: m.currency()}
+}
+
+func (m Money) Neg() Money {
+	return Money{Amount: -m.Amount, Currency: m.currency()}
+}
+
+// Cmp returns -1, 0 or 1 when m is less than, equal to or greater
+// than o.
+func (m Money) Cmp(o Money) int {
+	m.mustMatch(o)
+	switch {
+	case m.Amount < o.Amount:
+		return -1
+	case m.Amount > o.Amount:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (m Money) Equal(o Money) bool {
+	return m.currency() == o.currency() && m.Amount == o.Amount
+}
+
+func (m Money) IsZero() bool     { return m.Amount == 0 }
+func (m Money) IsPositive() bool { return m.Amount > 0 }
+func (m Money) IsNegative() bool { return m.Amount < 0 }
+
+// Decimal formats the amount without currency, e.g. "12.34".
+func (m Money) Decimal() string {
+	exp := m.currency().Exponent()
+
+	amount := m.Amount
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	digits := strconv.FormatInt(amount, 10)
+	if exp == 0 {
+		return sign + digits
+	}
+	if len(digits) <= exp {
+		digits = strings.Repeat("0", exp-len(digits)+1) + digits
+	}
+	point := len(digits) - exp
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// String formats the amount with its currency, e.g. "USD 12.34".
+func (m Money) String() string {
+	return string(m.currency()) + " " + m.Decimal()
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.Decimal())
+}
+
+// UnmarshalJSON accepts decimal strings and, for older clients, plain
+// JSON numbers.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	value := string(data)
+	if value == "null" {
+		return nil
+	}
+
+	if strings.HasPrefix(value, `"`) {
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+	}
+
+	parsed, err := ParseMoney(value, m.currency())
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Value stores the amount as a decimal string.
+func (m Money) Value() (driver.Value, error) {
+	if m.currency() != DefaultCurrency {
+		return nil, fmt.Errorf(
+			"cannot store %s amount, prices are kept in %s",
+			m.currency(),
+			DefaultCurrency,
+		)
+	}
+	return m.Decimal(), nil
+}
+
+// Scan reads a DECIMAL column into DefaultCurrency.
+func (m *Money) Scan(src interface{}) error {
+	var (
+		parsed Money
+		err    error
+	)
+
+	switch v := src.(type) {
+	case nil:
+		parsed = Money{Currency: DefaultCurrency}
+	case []byte:
+		parsed, err = ParseMoney(string(v), DefaultCurrency)
+	case string:
+		parsed, err = ParseMoney(v, DefaultCurrency)
+	case float64:
+		parsed = MoneyFromFloat(v, DefaultCurrency)
+	case int64:
+		parsed, err = ParseMoney(strconv.FormatInt(v, 10), DefaultCurrency)
+	default:
+		err = fmt.Errorf("cannot scan %T into Money", src)
+	}
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
+
+// GormDataType matches the DECIMAL(10,2) columns used by the migrations.
+func (Money) GormDataType() string {
+	return "decimal(10,2)"
+}
+
+// parseMinorUnits converts a decimal string to an integer count of
+// units with exp digits after the point, rounding half to even.
+func parseMinorUnits(s string, exp int) (int64, error) {
+	value := strings.TrimSpace(s)
+	negative := false
+	switch {
+	case strings.HasPrefix(value, "-"):
+		negative = true
+		value = value[1:]
+	case strings.HasPrefix(value, "+"):
+		value = value[1:]
+	}
+
+	whole, frac, _ := strings.Cut(value, ".")
+	if whole == "" && frac == "" || !isDigits(whole) || !isDigits(frac) {
+		return 0, fmt.Errorf("invalid money amount %q", s)
+	}
+
+	kept, dropped := frac, ""
+	if len(frac) > exp {
+		kept, dropped = frac[:exp], frac[exp:]
+	} else {
+		kept += strings.Repeat("0", exp-len(frac))
+	}
+
+	digits := strings.TrimLeft(whole+kept, "0")
+	if digits == "" {
+		digits = "0"
+	}
+	amount, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("money amount %q out of range", s)
+	}
+
+	if roundUp(dropped, amount%2 != 0) {
+		amount++
+	}
+	if negative {
+		amount = -amount
+	}
+
+	return amount, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// roundUp reports whether dropped digits round the kept amount up under
+// round half to even.
+func roundUp(dropped string, odd bool) bool {
+	if dropped == "" || dropped[0] < '5' {
+		return false
+	}
+	if dropped[0] > '5' || strings.TrimRight(dropped[1:], "0") != "" {
+		return true
+	}
+	return odd
+}
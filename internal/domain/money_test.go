@@ -0,0 +1,140 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		currency Currency
+		expected int64
+		wantErr  bool
+	}{
+		{name: "Whole Amount", input: "12", expected: 1200},
+		{name: "Two Decimals", input: "12.34", expected: 1234},
+		{name: "One Decimal", input: "0.5", expected: 50},
+		{name: "Leading Point", input: ".99", expected: 99},
+		{name: "Negative", input: "-3.10", expected: -310},
+		{name: "Half Rounds To Even Down", input: "0.125", expected: 12},
+		{name: "Half Rounds To Even Up", input: "0.135", expected: 14},
+		{name: "Above Half Rounds Up", input: "0.1251", expected: 13},
+		{name: "Below Half Rounds Down", input: "0.1249", expected: 12},
+		{name: "Zero Exponent Currency", input: "1234.5", currency: "JPY", expected: 1234},
+		{name: "Three Exponent Currency", input: "1.2345", currency: "KWD", expected: 1234},
+		{name: "Error - Empty", input: "", wantErr: true},
+		{name: "Error - Not A Number", input: "12.3a", wantErr: true},
+		{name: "Error - Exponent", input: "1e3", wantErr: true},
+		{name: "Error - Out Of Range", input: "999999999999999999999", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			money, err := ParseMoney(tt.input, tt.currency)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, money.Amount)
+		})
+	}
+}
+
+func TestMoneyFromFloat(t *testing.T) {
+	assert.Equal(t, int64(10), MoneyFromFloat(0.1, DefaultCurrency).Amount)
+	assert.Equal(t, int64(199), MoneyFromFloat(1.99, DefaultCurrency).Amount)
+	assert.Equal(t, int64(-250), MoneyFromFloat(-2.5, DefaultCurrency).Amount)
+}
+
+func TestMoney_Arithmetic(t *testing.T) {
+	price := NewMoney(199, DefaultCurrency)
+
+	assert.Equal(t, NewMoney(597, DefaultCurrency), price.Mul(3))
+	assert.Equal(t, NewMoney(298, DefaultCurrency), price.Add(NewMoney(99, DefaultCurrency)))
+	assert.Equal(t, NewMoney(100, DefaultCurrency), price.Sub(NewMoney(99, DefaultCurrency)))
+	assert.Equal(t, NewMoney(-199, DefaultCurrency), price.Neg())
+	assert.Equal(t, 1, price.Cmp(NewMoney(99, DefaultCurrency)))
+	assert.Equal(t, 0, price.Cmp(NewMoney(199, "")))
+	assert.True(t, price.Equal(NewMoney(199, "")))
+
+	t.Run("Scale Rounds Half To Even", func(t *testing.T) {
+		assert.Equal(t, int64(12), NewMoney(25, DefaultCurrency).Scale(0.5).Amount)
+		assert.Equal(t, int64(18), NewMoney(35, DefaultCurrency).Scale(0.5).Amount)
+		assert.Equal(t, int64(159), price.Scale(0.8).Amount)
+	})
+
+	t.Run("Currency Mismatch Panics", func(t *testing.T) {
+		assert.Panics(t, func() {
+			price.Add(NewMoney(100, "EUR"))
+		})
+	})
+}
+
+func TestMoney_Decimal(t *testing.T) {
+	assert.Equal(t, "12.34", NewMoney(1234, DefaultCurrency).Decimal())
+	assert.Equal(t, "0.05", NewMoney(5, DefaultCurrency).Decimal())
+	assert.Equal(t, "-0.50", NewMoney(-50, DefaultCurrency).Decimal())
+	assert.Equal(t, "0.00", Money{}.Decimal())
+	assert.Equal(t, "1234", NewMoney(1234, "JPY").Decimal())
+	assert.Equal(t, "USD 12.34", NewMoney(1234, DefaultCurrency).String())
+}
+
+func TestMoney_JSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Price Money `json:"price"`
+	}{Price: NewMoney(1099, DefaultCurrency)})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"price":"10.99"}`, string(data))
+
+	var decoded struct {
+		Price Money `json:"price"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"price":"10.99"}`), &decoded))
+	assert.Equal(t, int64(1099), decoded.Price.Amount)
+	assert.Equal(t, DefaultCurrency, decoded.Price.Currency)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"price":2.5}`), &decoded))
+	assert.Equal(t, int64(250), decoded.Price.Amount)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"price":"ten"}`), &decoded))
+}
+
+func TestMoney_ValueAndScan(t *testing.T) {
+	value, err := NewMoney(1099, DefaultCurrency).Value()
+	require.NoError(t, err)
+	assert.Equal(t, "10.99", value)
+
+	_, err = NewMoney(1099, "EUR").Value()
+	assert.Error(t, err)
+
+	sources := map[string]interface{}{
+		"Bytes":   []byte("10.99"),
+		"String":  "10.99",
+		"Float":   10.99,
+		"Integer": int64(10),
+	}
+	expected := map[string]int64{
+		"Bytes":   1099,
+		"String":  1099,
+		"Float":   1099,
+		"Integer": 1000,
+	}
+	for name, src := range sources {
+		t.Run(name, func(t *testing.T) {
+			var m Money
+			require.NoError(t, m.Scan(src))
+			assert.Equal(t, expected[name], m.Amount)
+			assert.Equal(t, DefaultCurrency, m.Currency)
+		})
+	}
+
+	var m Money
+	assert.Error(t, m.Scan(true))
+}
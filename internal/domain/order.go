@@ -27,7 +27,7 @@ type Order struct {
 	Customer   *Customer   `json:"customer,omitempty" gorm:"foreignKey:CustomerID"`
 	Items      []OrderItem `json:"items"              gorm:"foreignKey:OrderID"`
 	Status     OrderStatus `json:"status"             gorm:"not null"`
-	TotalPrice float64     `json:"total_price"        gorm:"not null"`
+	TotalPrice Money       `json:"total_price"        gorm:"not null"`
 	CreatedAt  time.Time   `json:"created_at"`
 	UpdatedAt  time.Time   `json:"updated_at"`
 }
@@ -38,11 +38,32 @@ type OrderItem struct {
 	ProductID uuid.UUID `json:"product_id"        gorm:"type:uuid;not null"`
 	Product   *Product  `json:"product,omitempty" gorm:"foreignKey:ProductID"`
 	Quantity  int       `json:"quantity"          gorm:"not null"`
-	Price     float64   `json:"price"             gorm:"not null"`
+	Price     Money     `json:"price"             gorm:"not null"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// RefundAction decides what happens to the goods of a refunded line.
+type RefundAction string
+
+const (
+	RefundActionRestock RefundAction = "RESTOCK"
+	RefundActionDiscard RefundAction = "DISCARD"
+)
+
+// RefundLine is the restock or discard decision for one order item of a
+// refunded order. Items without a line are discarded.
+type RefundLine struct {
+	ItemID uuid.UUID    `json:"item_id"`
+	Action RefundAction `json:"action"`
+}
+
+// StockAdjustment returns Quantity units of a product to stock.
+type StockAdjustment struct {
+	ProductID uuid.UUID
+	Quantity  int
+}
+
 func NewOrder(customerID uuid.UUID) *Order {
 	return &Order{
 		ID:         uuid.New(),
@@ -56,7 +77,7 @@ func NewOrder(customerID uuid.UUID) *Order {
 func NewOrderItem(
 	orderID, productID uuid.UUID,
 	quantity int,
-	price float64,
+	price Money,
 ) *OrderItem {
 	return &OrderItem{
 		ID:        uuid.New(),
@@ -94,6 +115,40 @@ func (i *OrderItem) Validate() error {
 	return nil
 }
 
+// IsShipped reports whether goods of an order in status s have left the
+// store.
+func (s OrderStatus) IsShipped() bool {
+	return s == OrderStatusShipped ||
+		s == OrderStatusDelivered ||
+		s == OrderStatusRefunded
+}
+
+// ValidateRefundLines checks that every line names a distinct item of o
+// and a known action.
+func (o *Order) ValidateRefundLines(lines []RefundLine) error {
+	items := make(map[uuid.UUID]bool, len(o.Items))
+	for _, item := range o.Items {
+		items[item.ID] = true
+	}
+
+	seen := make(map[uuid.UUID]bool, len(lines))
+	for _, line := range lines {
+		if !items[line.ItemID] {
+			return fmt.Errorf("item %s is not part of the order", line.ItemID)
+		}
+		if seen[line.ItemID] {
+			return fmt.Errorf("item %s is listed more than once", line.ItemID)
+		}
+		seen[line.ItemID] = true
+
+		if line.Action != RefundActionRestock &&
+			line.Action != RefundActionDiscard {
+			return fmt.Errorf("invalid refund action: %s", line.Action)
+		}
+	}
+	return nil
+}
+
 func ValidateOrderStatus(status OrderStatus) error {
 	validStatuses := map[OrderStatus]bool{
 		OrderStatusPending:   true,
@@ -113,51 +168,8 @@ func ValidateOrderStatus(status OrderStatus) error {
 	return nil
 }
 
+// IsValidStatusTransition reports whether DefaultOrderTransitions allows
+// moving from from to to.
 func IsValidStatusTransition(from, to OrderStatus) bool {
-	transitions := map[OrderStatus][]OrderStatus{
-		OrderStatusPending: {
-			OrderStatusConfirmed,
-			OrderStatusCancelled,
-			OrderStatusFailed,
-		},
-		OrderStatusConfirmed: {
-			OrderStatusPreparing,
-			OrderStatusCancelled,
-			OrderStatusFailed,
-		},
-		OrderStatusPreparing: {
-			OrderStatusReady,
-			OrderStatusCancelled,
-			OrderStatusFailed,
-		},
-		OrderStatusReady: {
-			OrderStatusShipped,
-			OrderStatusCancelled,
-			OrderStatusFailed,
-		},
-		OrderStatusShipped: {
-			OrderStatusDelivered,
-			OrderStatusFailed,
-		},
-		OrderStatusDelivered: {
-			OrderStatusRefunded,
-		},
-		OrderStatusCancelled: {
-			OrderStatusRefunded,
-		},
-		OrderStatusRefunded: {}, // No further transitions
-		OrderStatusFailed:   {}, // No further transitions
-	}
-
-	allowedTransitions, exists := transitions[from]
-	if !exists {
-		return false
-	}
-
-	for _, status := range allowedTransitions {
-		if status == to {
-			return true
-		}
-	}
-	return false
+	return DefaultOrderTransitions.Allows(from, to)
 }
@@ -0,0 +1,288 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrTransitionRejected is returned by OrderStateMachine.Transition when
+// the transition is not defined or a guard refuses it.
+var ErrTransitionRejected = errors.New("order status transition rejected")
+
+// OrderStatuses lists every order status in lifecycle order. Diagrams
+// and validation errors use this order.
+var OrderStatuses = []OrderStatus{
+	OrderStatusPending,
+	OrderStatusConfirmed,
+	OrderStatusPreparing,
+	OrderStatusReady,
+	OrderStatusShipped,
+	OrderStatusDelivered,
+	OrderStatusCancelled,
+	OrderStatusRefunded,
+	OrderStatusFailed,
+}
+
+// OrderTransitions maps each status to the statuses an order may move to
+// from it. A status without an entry is terminal. It is the JSON format
+// accepted by ParseOrderTransitions.
+type OrderTransitions map[OrderStatus][]OrderStatus
+
+// DefaultOrderTransitions is the order lifecycle used unless a
+// transitions file is configured.
+var DefaultOrderTransitions = OrderTransitions{
+	OrderStatusPending: {
+		OrderStatusConfirmed,
+		OrderStatusCancelled,
+		OrderStatusFailed,
+	},
+	OrderStatusConfirmed: {
+		OrderStatusPreparing,
+		OrderStatusCancelled,
+		OrderStatusFailed,
+	},
+	OrderStatusPreparing: {
+		OrderStatusReady,
+		OrderStatusCancelled,
+		OrderStatusFailed,
+	},
+	OrderStatusReady: {
+		OrderStatusShipped,
+		OrderStatusCancelled,
+		OrderStatusFailed,
+	},
+	OrderStatusShipped: {
+		OrderStatusDelivered,
+		OrderStatusFailed,
+	},
+	OrderStatusDelivered: {
+		OrderStatusRefunded,
+	},
+	OrderStatusCancelled: {
+		OrderStatusRefunded,
+	},
+}
+
+// ParseOrderTransitions decodes and validates a JSON transitions
+// definition such as {"PENDING": ["CONFIRMED", "CANCELLED"]}.
+func ParseOrderTransitions(data []byte) (OrderTransitions, error) {
+	var transitions OrderTransitions
+	if err := json.Unmarshal(data, &transitions); err != nil {
+		return nil, fmt.Errorf("invalid order transitions: %w", err)
+	}
+	if err := transitions.Validate(); err != nil {
+		return nil, err
+	}
+	return transitions, nil
+}
+
+// Validate checks that every status named in t exists and that no
+// status lists itself as a target.
+func (t OrderTransitions) Validate() error {
+	if len(t) == 0 {
+		return fmt.Errorf("order transitions must not be empty")
+	}
+
+	for from, targets := range t {
+		if err := ValidateOrderStatus(from); err != nil {
+			return err
+		}
+		for _, to := range targets {
+			if err := ValidateOrderStatus(to); err != nil {
+				return err
+			}
+			if to == from {
+				return fmt.Errorf(
+					"order status %s cannot transition to itself",
+					from,
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// Allows reports whether t defines a transition from from to to.
+func (t OrderTransitions) Allows(from, to OrderStatus) bool {
+	return slices.Contains(t[from], to)
+}
+
+// OrderTransition describes one status change while it is being applied.
+// Hooks may add outbox events and stock to return, which are stored in
+// the same transaction as the status change.
+type OrderTransition struct {
+	Order       *Order
+	From        OrderStatus
+	To          OrderStatus
+	Change      *OrderStatusChange
+	RefundLines []RefundLine
+	Restock     []StockAdjustment
+	Events      []*OutboxEvent
+}
+
+// TransitionGuard vetoes a transition by returning an error.
+type TransitionGuard func(ctx context.Context, t *OrderTransition) error
+
+// TransitionHook runs while a transition is applied. An error aborts the
+// transition.
+type TransitionHook func(ctx context.Context, t *OrderTransition) error
+
+// OrderStateMachine validates order status transitions against a
+// declarative definition and runs the guards and hooks registered for
+// them. Register guards and hooks before the machine is shared; it is
+// not safe for concurrent registration.
+type OrderStateMachine struct {
+	transitions OrderTransitions
+	guards      map[OrderStatus][]TransitionGuard
+	onEnter     map[OrderStatus][]TransitionHook
+	onExit      map[OrderStatus][]TransitionHook
+}
+
+func NewOrderStateMachine(
+	transitions OrderTransitions,
+) (*OrderStateMachine, error) {
+	if err := transitions.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &OrderStateMachine{
+		transitions: transitions,
+		guards:      make(map[OrderStatus][]TransitionGuard),
+		onEnter:     make(map[OrderStatus][]TransitionHook),
+		onExit:      make(map[OrderStatus][]TransitionHook),
+	}, nil
+}
+
+// NewDefaultOrderStateMachine returns a machine for
+// DefaultOrderTransitions with no guards or hooks.
+func NewDefaultOrderStateMachine() *OrderStateMachine {
+	m, err := NewOrderStateMachine(DefaultOrderTransitions)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Guard registers a guard checked before any transition into to.
+func (m *OrderStateMachine) Guard(to OrderStatus, guard TransitionGuard) {
+	m.guards[to] = append(m.guards[to], guard)
+}
+
+// OnEnter registers a hook run when an order enters status.
+func (m *OrderStateMachine) OnEnter(status OrderStatus, hook TransitionHook) {
+	m.onEnter[status] = append(m.onEnter[status], hook)
+}
+
+// OnExit registers a hook run when an order leaves status.
+func (m *OrderStateMachine) OnExit(status OrderStatus, hook TransitionHook) {
+	m.onExit[status] = append(m.onExit[status], hook)
+}
+
+// Can reports whether the definition allows moving from from to to.
+// Guards are not consulted.
+func (m *OrderStateMachine) Can(from, to OrderStatus) bool {
+	return m.transitions.Allows(from, to)
+}
+
+// Transition checks that t is allowed, runs its guards, the exit hooks of
+// t.From and the enter hooks of t.To, and sets t.Order.Status to t.To.
+// Rejections wrap ErrTransitionRejected; hook errors are returned as is.
+func (m *OrderStateMachine) Transition(
+	ctx context.Context,
+	t *OrderTransition,
+) error {
+	if !m.Can(t.From, t.To) {
+		return fmt.Errorf(
+			"%w: invalid transition from %s to %s",
+			ErrTransitionRejected,
+			t.From,
+			t.To,
+		)
+	}
+
+	for _, guard := range m.guards[t.To] {
+		if err := guard(ctx, t); err != nil {
+			return fmt.Errorf("%w: %v", ErrTransitionRejected, err)
+		}
+	}
+
+	for _, hook := range m.onExit[t.From] {
+		if err := hook(ctx, t); err != nil {
+			return err
+		}
+	}
+
+	t.Order.Status = t.To
+
+	for _, hook := range m.onEnter[t.To] {
+		if err := hook(ctx, t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Mermaid renders the transitions as a Mermaid state diagram.
+func (m *OrderStateMachine) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("stateDiagram-v2\n")
+	fmt.Fprintf(&b, "    [*] --> %s\n", OrderStatusPending)
+	m.eachTransition(func(from, to OrderStatus) {
+		fmt.Fprintf(&b, "    %s --> %s\n", from, to)
+	})
+	for _, status := range m.terminalStatuses() {
+		fmt.Fprintf(&b, "    %s --> [*]\n", status)
+	}
+	return b.String()
+}
+
+// Graphviz renders the transitions as a Graphviz DOT digraph. Terminal
+// statuses are drawn with a double border.
+func (m *OrderStateMachine) Graphviz() string {
+	var b strings.Builder
+	b.WriteString("digraph order_status {\n")
+	b.WriteString("    rankdir=LR;\n")
+	b.WriteString("    node [shape=box, style=rounded];\n")
+	for _, status := range m.terminalStatuses() {
+		fmt.Fprintf(&b, "    %q [peripheries=2];\n", status)
+	}
+	m.eachTransition(func(from, to OrderStatus) {
+		fmt.Fprintf(&b, "    %q -> %q;\n", from, to)
+	})
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// eachTransition calls fn for every defined transition in lifecycle
+// order, so rendered diagrams are stable.
+func (m *OrderStateMachine) eachTransition(fn func(from, to OrderStatus)) {
+	for _, from := range OrderStatuses {
+		targets := slices.Clone(m.transitions[from])
+		slices.SortFunc(targets, func(a, b OrderStatus) int {
+			return slices.Index(OrderStatuses, a) -
+				slices.Index(OrderStatuses, b)
+		})
+		for _, to := range targets {
+			fn(from, to)
+		}
+	}
+}
+
+// terminalStatuses returns the reachable statuses with no outgoing
+// transitions.
+func (m *OrderStateMachine) terminalStatuses() []OrderStatus {
+	reachable := map[OrderStatus]bool{OrderStatusPending: true}
+	m.eachTransition(func(_, to OrderStatus) { reachable[to] = true })
+
+	var terminal []OrderStatus
+	for _, status := range OrderStatuses {
+		if reachable[status] && len(m.transitions[status]) == 0 {
+			terminal = append(terminal, status)
+		}
+	}
+	return terminal
+}
@@ -0,0 +1,151 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOrderTransitions(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{
+			name: "Valid",
+			data: `{"PENDING": ["CONFIRMED", "CANCELLED"], "CONFIRMED": ["DELIVERED"]}`,
+		},
+		{
+			name:    "Unknown Status",
+			data:    `{"PENDING": ["LOST"]}`,
+			wantErr: true,
+		},
+		{
+			name:    "Self Transition",
+			data:    `{"PENDING": ["PENDING"]}`,
+			wantErr: true,
+		},
+		{
+			name:    "Empty",
+			data:    `{}`,
+			wantErr: true,
+		},
+		{
+			name:    "Malformed",
+			data:    `["PENDING"]`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transitions, err := ParseOrderTransitions([]byte(tt.data))
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, transitions.Allows(OrderStatusPending, OrderStatusCancelled))
+			assert.False(t, transitions.Allows(OrderStatusConfirmed, OrderStatusPreparing))
+		})
+	}
+}
+
+func TestOrderStateMachine_Transition(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Runs Exit Then Enter Hooks", func(t *testing.T) {
+		m := NewDefaultOrderStateMachine()
+		var calls []string
+		m.OnExit(OrderStatusPending, func(_ context.Context, tr *OrderTransition) error {
+			calls = append(calls, "exit "+string(tr.Order.Status))
+			return nil
+		})
+		m.OnEnter(OrderStatusCancelled, func(_ context.Context, tr *OrderTransition) error {
+			calls = append(calls, "enter "+string(tr.Order.Status))
+			return nil
+		})
+		m.OnEnter(OrderStatusConfirmed, func(_ context.Context, _ *OrderTransition) error {
+			calls = append(calls, "unexpected")
+			return nil
+		})
+
+		order := &Order{Status: OrderStatusPending}
+		err := m.Transition(ctx, &OrderTransition{
+			Order: order,
+			From:  OrderStatusPending,
+			To:    OrderStatusCancelled,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, OrderStatusCancelled, order.Status)
+		assert.Equal(t, []string{"exit PENDING", "enter CANCELLED"}, calls)
+	})
+
+	t.Run("Rejects Undefined Transition", func(t *testing.T) {
+		m := NewDefaultOrderStateMachine()
+		order := &Order{Status: OrderStatusPending}
+
+		err := m.Transition(ctx, &OrderTransition{
+			Order: order,
+			From:  OrderStatusPending,
+			To:    OrderStatusDelivered,
+		})
+
+		assert.ErrorIs(t, err, ErrTransitionRejected)
+		assert.Equal(t, OrderStatusPending, order.Status)
+	})
+
+	t.Run("Guard Rejects Before Hooks Run", func(t *testing.T) {
+		m := NewDefaultOrderStateMachine()
+		m.Guard(OrderStatusCancelled, func(_ context.Context, _ *OrderTransition) error {
+			return errors.New("already picked")
+		})
+		m.OnExit(OrderStatusPending, func(_ context.Context, _ *OrderTransition) error {
+			t.Fatal("exit hook must not run after a guard rejects")
+			return nil
+		})
+
+		err := m.Transition(ctx, &OrderTransition{
+			Order: &Order{Status: OrderStatusPending},
+			From:  OrderStatusPending,
+			To:    OrderStatusCancelled,
+		})
+
+		assert.ErrorIs(t, err, ErrTransitionRejected)
+		assert.Contains(t, err.Error(), "already picked")
+	})
+}
+
+func TestOrderStateMachine_Render(t *testing.T) {
+	m, err := NewOrderStateMachine(OrderTransitions{
+		OrderStatusPending:   {OrderStatusCancelled, OrderStatusConfirmed},
+		OrderStatusConfirmed: {OrderStatusDelivered},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, `stateDiagram-v2
+    [*] --> PENDING
+    PENDING --> CONFIRMED
+    PENDING --> CANCELLED
+    CONFIRMED --> DELIVERED
+    DELIVERED --> [*]
+    CANCELLED --> [*]
+`, m.Mermaid())
+
+	assert.Equal(t, `digraph order_status {
+    rankdir=LR;
+    node [shape=box, style=rounded];
+    "DELIVERED" [peripheries=2];
+    "CANCELLED" [peripheries=2];
+    "PENDING" -> "CONFIRMED";
+    "PENDING" -> "CANCELLED";
+    "CONFIRMED" -> "DELIVERED";
+}
+`, m.Graphviz())
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxStatusReasonLength bounds the free-text reason stored with a status
+// change.
+const MaxStatusReasonLength = 500
+
+// OrderStatusChange is one entry in an order's status history. ActorID is
+// nil when the change was made by the system rather than a user.
+type OrderStatusChange struct {
+	ID         uuid.UUID   `json:"id"                 gorm:"type:uuid;primary_key"`
+	OrderID    uuid.UUID   `json:"order_id"           gorm:"type:uuid;not null;index"`
+	FromStatus OrderStatus `json:"from_status"        gorm:"not null"`
+	ToStatus   OrderStatus `json:"to_status"          gorm:"not null"`
+	ActorID    *uuid.UUID  `json:"actor_id,omitempty" gorm:"type:uuid"`
+	Reason     string      `json:"reason,omitempty"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+func (OrderStatusChange) TableName() string {
+	return "order_status_history"
+}
+
+// NewOrderStatusChange records a transition of order from its current
+// status to status. actorID is the acting user's ID and may be empty for
+// system changes.
+func NewOrderStatusChange(
+	order *Order,
+	status OrderStatus,
+	actorID string,
+	reason string,
+) (*OrderStatusChange, error) {
+	if len(reason) > MaxStatusReasonLength {
+		return nil, fmt.Errorf(
+			"reason must be at most %d characters",
+			MaxStatusReasonLength,
+		)
+	}
+
+	change := &OrderStatusChange{
+		ID:         uuid.New(),
+		OrderID:    order.ID,
+		FromStatus: order.Status,
+		ToStatus:   status,
+		Reason:     reason,
+		CreatedAt:  time.Now(),
+	}
+
+	if actorID != "" {
+		actor, err := uuid.Parse(actorID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid actor ID: %s", actorID)
+		}
+		change.ActorID = &actor
+	}
+
+	return change, nil
+}
@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	OutboxEventType string
+	OutboxStatus    string
+)
+
+const (
+	OutboxEventOrderConfirmation OutboxEventType = "order.confirmation"
+	OutboxEventOrderStatusUpdate OutboxEventType = "order.status_update"
+	OutboxEventLowStock          OutboxEventType = "product.low_stock"
+
+	OutboxStatusPending   OutboxStatus = "PENDING"
+	OutboxStatusDelivered OutboxStatus = "DELIVERED"
+	OutboxStatusDead      OutboxStatus = "DEAD"
+)
+
+// OutboxEvent is a side effect recorded in the same transaction as the
+// change that caused it and delivered later by the outbox dispatcher.
+// Pending events are picked up once NextAttemptAt has passed; events
+// that keep failing are moved to DEAD and wait for a manual replay.
+type OutboxEvent struct {
+	ID            uuid.UUID       `json:"id"                     gorm:"type:uuid;primary_key"`
+	AggregateID   uuid.UUID       `json:"aggregate_id"           gorm:"type:uuid;not null;index"`
+	EventType     OutboxEventType `json:"event_type"             gorm:"not null"`
+	Payload       json.RawMessage `json:"payload"                gorm:"type:jsonb;not null"`
+	Status        OutboxStatus    `json:"status"                 gorm:"not null;index"`
+	Attempts      int             `json:"attempts"               gorm:"not null;default:0"`
+	LastError     string          `json:"last_error,omitempty"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"        gorm:"not null"`
+	DeliveredAt   *time.Time      `json:"delivered_at,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+// OrderEventPayload identifies the order an event is about. Status is
+// the order status at the time the event was recorded.
+type OrderEventPayload struct {
+	OrderID uuid.UUID   `json:"order_id"`
+	Status  OrderStatus `json:"status"`
+}
+
+func NewOrderEvent(
+	eventType OutboxEventType,
+	order *Order,
+) (*OutboxEvent, error) {
+	payload, err := json.Marshal(OrderEventPayload{
+		OrderID: order.ID,
+		Status:  order.Status,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event payload: %w", err)
+	}
+
+	now := time.Now()
+	return &OutboxEvent{
+		ID:            uuid.New(),
+		AggregateID:   order.ID,
+		EventType:     eventType,
+		Payload:       payload,
+		Status:        OutboxStatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}, nil
+}
+
+// NewLowStockEvent records that a product fell to its reorder point.
+// The payload is the alert itself, so it reports the stock level at the
+// time of the change.
+func NewLowStockEvent(alert LowStockAlert) (*OutboxEvent, error) {
+	payload, err := json.Marshal(alert)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event payload: %w", err)
+	}
+
+	now := time.Now()
+	return &OutboxEvent{
+		ID:            uuid.New(),
+		AggregateID:   alert.ProductID,
+		EventType:     OutboxEventLowStock,
+		Payload:       payload,
+		Status:        OutboxStatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}, nil
+}
+
+func (e *OutboxEvent) OrderPayload() (OrderEventPayload, error) {
+	var payload OrderEventPayload
+	if err := json.Unmarshal(e.Payload, &payload); err != nil {
+		return payload, fmt.Errorf("invalid order event payload: %w", err)
+	}
+	return payload, nil
+}
+
+func (e *OutboxEvent) LowStockPayload() (LowStockAlert, error) {
+	var alert LowStockAlert
+	if err := json.Unmarshal(e.Payload, &alert); err != nil {
+		return alert, fmt.Errorf("invalid low stock event payload: %w", err)
+	}
+	return alert, nil
+}
+
+func ValidateOutboxStatus(status OutboxStatus) error {
+	switch status {
+	case OutboxStatusPending, OutboxStatusDelivered, OutboxStatusDead:
+		return nil
+	default:
+		return fmt.Errorf("invalid outbox status: %s", status)
+	}
+}
@@ -7,21 +7,47 @@ import (
 	"github.com/google/uuid"
 )
 
+// Product is a sellable item. When Stock falls to ReorderPoint or below
+// the product is low on stock and ReorderQuantity units should be
+// ordered; a ReorderPoint of zero disables low-stock alerts.
 type Product struct {
-	ID          uuid.UUID `json:"id"                 gorm:"type:uuid;primary_key"`
-	Name        string    `json:"name"               gorm:"not null"`
-	Description string    `json:"description"`
-	Price       float64   `json:"price"              gorm:"not null"`
-	Stock       int       `json:"stock"              gorm:"not null"`
-	CategoryID  uuid.UUID `json:"category_id"        gorm:"type:uuid;not null"`
-	Category    *Category `json:"category,omitempty" gorm:"foreignKey:CategoryID"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID              uuid.UUID `json:"id"                 gorm:"type:uuid;primary_key"`
+	Name            string    `json:"name"               gorm:"not null"`
+	Description     string    `json:"description"`
+	Price           Money     `json:"price"              gorm:"not null"`
+	Stock           int       `json:"stock"              gorm:"not null"`
+	ReorderPoint    int       `json:"reorder_point"      gorm:"not null;default:0"`
+	ReorderQuantity int       `json:"reorder_quantity"   gorm:"not null;default:0"`
+	CategoryID      uuid.UUID `json:"category_id"        gorm:"type:uuid;not null"`
+	Category        *Category `json:"category,omitempty" gorm:"foreignKey:CategoryID"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// LowStockAlert reports a product whose stock fell to its reorder point.
+type LowStockAlert struct {
+	ProductID       uuid.UUID `json:"product_id"`
+	Name            string    `json:"name"`
+	Stock           int       `json:"stock"`
+	ReorderPoint    int       `json:"reorder_point"`
+	ReorderQuantity int       `json:"reorder_quantity"`
+}
+
+// ProductSearchResult is a product matched by a search query.
+// NameHighlight and Snippet are HTML-escaped and carry the matched terms
+// wrapped in <mark> tags. Fuzzy is set when the product was only found by trigram
+// similarity.
+type ProductSearchResult struct {
+	Product
+	Rank          float64 `json:"rank"`
+	NameHighlight string  `json:"name_highlight"`
+	Snippet       string  `json:"snippet"`
+	Fuzzy         bool    `json:"fuzzy"          gorm:"-"`
 }
 
 func NewProduct(
 	name, description string,
-	price float64,
+	price Money,
 	stock int,
 	categoryID uuid.UUID,
 ) *Product {
@@ -41,11 +67,43 @@ func (p *Product) Validate() error {
 	if p.Name == "" {
 		return fmt.Errorf("product name is required")
 	}
-	if p.Price <= 0 {
+	if !p.Price.IsPositive() {
 		return fmt.Errorf("product price must be greater than zero")
 	}
 	if p.Stock < 0 {
 		return fmt.Errorf("product stock cannot be negative")
 	}
+	if p.ReorderPoint < 0 {
+		return fmt.Errorf("product reorder point cannot be negative")
+	}
+	if p.ReorderQuantity < 0 {
+		return fmt.Errorf("product reorder quantity cannot be negative")
+	}
 	return nil
 }
+
+// IsLowStock reports whether the product is at or below its reorder
+// point.
+func (p *Product) IsLowStock() bool {
+	return p.ReorderPoint > 0 && p.Stock <= p.ReorderPoint
+}
+
+// ReachesReorderPoint reports whether lowering the product's stock to
+// stock makes it low on stock. Products that were already low do not
+// reach it again, so each drop is alerted once.
+func (p *Product) ReachesReorderPoint(stock int) bool {
+	return p.ReorderPoint > 0 &&
+		p.Stock > p.ReorderPoint &&
+		stock <= p.ReorderPoint
+}
+
+// LowStockAlert describes the product at the given stock level.
+func (p *Product) LowStockAlert(stock int) LowStockAlert {
+	return LowStockAlert{
+		ProductID:       p.ID,
+		Name:            p.Name,
+		Stock:           stock,
+		ReorderPoint:    p.ReorderPoint,
+		ReorderQuantity: p.ReorderQuantity,
+	}
+}
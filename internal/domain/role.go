@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// Permission names one action a role may be granted, as
+// "<resource>:<action>".
+type Permission string
+
+const (
+	PermissionProductsWrite      Permission = "products:write"
+	PermissionCategoriesWrite    Permission = "categories:write"
+	PermissionInventoryRead      Permission = "inventory:read"
+	PermissionInventoryWrite     Permission = "inventory:write"
+	PermissionOrdersCreate       Permission = "orders:create"
+	PermissionOrdersRead         Permission = "orders:read"
+	PermissionOrdersManage       Permission = "orders:manage"
+	PermissionOrdersUpdateStatus Permission = "orders:update_status"
+	PermissionOrdersRefund       Permission = "orders:refund"
+	PermissionCustomersRead      Permission = "customers:read"
+	PermissionCustomersDelete    Permission = "customers:delete"
+	PermissionOutboxManage       Permission = "outbox:manage"
+	PermissionUsersManage        Permission = "users:manage"
+	PermissionRolesManage        Permission = "roles:manage"
+	PermissionMetricsRead        Permission = "metrics:read"
+	PermissionAPIKeysManage      Permission = "api_keys:manage"
+)
+
+// Permissions describes every permission the API checks. Role
+// definitions live in the database, but a permission only means
+// something once a route checks it, so the catalogue lives here.
+var Permissions = map[Permission]string{
+	PermissionProductsWrite:      "Create, update and delete products",
+	PermissionCategoriesWrite:    "Create, update and delete categories",
+	PermissionInventoryRead:      "View stock history and low-stock products",
+	PermissionInventoryWrite:     "Record stock movements",
+	PermissionOrdersCreate:       "Place orders",
+	PermissionOrdersRead:         "View all orders and their history",
+	PermissionOrdersManage:       "Place and change orders for any customer",
+	PermissionOrdersUpdateStatus: "Move orders through their lifecycle",
+	PermissionOrdersRefund:       "Refund orders",
+	PermissionCustomersRead:      "View all customers",
+	PermissionCustomersDelete:    "Delete customers",
+	PermissionOutboxManage:       "Inspect and replay outbox events",
+	PermissionUsersManage:        "Manage users' roles and sessions",
+	PermissionRolesManage:        "Define roles and their permissions",
+	PermissionMetricsRead:        "Read service metrics",
+	PermissionAPIKeysManage:      "Create and revoke API keys",
+}
+
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9-]{1,49}$`)
+
+// Role is a named set of permissions. Built-in roles are created by the
+// migrations and cannot be deleted. The admin role always holds every
+// permission.
+type Role struct {
+	Name        UserRole     `json:"name"        gorm:"type:varchar(50);primaryKey"`
+	Description string       `json:"description" gorm:"type:text"`
+	BuiltIn     bool         `json:"built_in"    gorm:"not null;default:false"`
+	Permissions []Permission `json:"permissions" gorm:"-"`
+	CreatedAt   time.Time    `json:"created_at"  gorm:"not null;default:current_timestamp"`
+	UpdatedAt   time.Time    `json:"updated_at"  gorm:"not null;default:current_timestamp"`
+}
+
+// RolePermission grants a permission to a role.
+type RolePermission struct {
+	Role       UserRole   `gorm:"type:varchar(50);primaryKey"`
+	Permission Permission `gorm:"type:varchar(100);primaryKey"`
+}
+
+// RoleRequest creates or updates a role. Name is ignored on update.
+type RoleRequest struct {
+	Name        UserRole     `json:"name"`
+	Description string       `json:"description"`
+	Permissions []Permission `json:"permissions"`
+}
+
+// AssignRoleRequest changes a user's role.
+type AssignRoleRequest struct {
+	Role UserRole `json:"role"`
+}
+
+// PermissionInfo describes a permission for the admin API.
+type PermissionInfo struct {
+	Name        Permission `json:"name"`
+	Description string     `json:"description"`
+}
+
+// ValidateRoleName checks that a role name is a short lowercase slug.
+func ValidateRoleName(name UserRole) error {
+	if !roleNamePattern.MatchString(string(name)) {
+		return fmt.Errorf(
+			"role name %q must be 2 to 50 lowercase letters, digits or dashes",
+			name,
+		)
+	}
+	return nil
+}
+
+// ValidatePermissions checks that every permission is known.
+func ValidatePermissions(permissions []Permission) error {
+	for _, p := range permissions {
+		if _, ok := Permissions[p]; !ok {
+			return fmt.Errorf("unknown permission %q", p)
+		}
+	}
+	return nil
+}
+
+// AllPermissions lists the permission catalogue sorted by name.
+func AllPermissions() []PermissionInfo {
+	all := make([]PermissionInfo, 0, len(Permissions))
+	for name, description := range Permissions {
+		all = append(all, PermissionInfo{Name: name, Description: description})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name < all[j].Name })
+	return all
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session is a login on one device: the tokens of one token family. Its
+// ID is the family ID.
+type Session struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Provider  string    `json:"provider"`
+	Device    string    `json:"device"`
+	IPAddress string    `json:"ip_address"`
+	UserAgent string    `json:"user_agent"`
+	// LastUsedAt is when the session last refreshed its tokens.
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	// Current marks the session of the caller's own access token.
+	Current bool `json:"current"`
+}
+
+// ClientInfo describes the client tokens are issued to.
+type ClientInfo struct {
+	IPAddress string
+	UserAgent string
+}
+
+// LogoutResult reports how many tokens a logout revoked.
+type LogoutResult struct {
+	RevokedTokens int64 `json:"revoked_tokens"`
+}
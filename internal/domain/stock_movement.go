@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type StockMovementType string
+
+const (
+	StockMovementSale       StockMovementType = "SALE"
+	StockMovementRestock    StockMovementType = "RESTOCK"
+	StockMovementAdjustment StockMovementType = "ADJUSTMENT"
+	StockMovementReturn     StockMovementType = "RETURN"
+	StockMovementSpoilage   StockMovementType = "SPOILAGE"
+)
+
+// StockMovement is one entry in the append-only inventory ledger.
+// Quantity is signed: sales and spoilage remove stock, restocks and
+// returns add it and adjustments may do either. A product's stock is the
+// sum of its movements; products.stock caches that sum.
+type StockMovement struct {
+	ID        uuid.UUID         `json:"id"                 gorm:"type:uuid;primary_key"`
+	ProductID uuid.UUID         `json:"product_id"         gorm:"type:uuid;not null;index"`
+	Type      StockMovementType `json:"type"               gorm:"not null"`
+	Quantity  int               `json:"quantity"           gorm:"not null"`
+	OrderID   *uuid.UUID        `json:"order_id,omitempty" gorm:"type:uuid"`
+	ActorID   *uuid.UUID        `json:"actor_id,omitempty" gorm:"type:uuid"`
+	Note      string            `json:"note,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// StockDrift reports a product whose cached stock disagrees with its
+// ledger.
+type StockDrift struct {
+	ProductID   uuid.UUID `json:"product_id"`
+	Name        string    `json:"name"`
+	CachedStock int       `json:"cached_stock"`
+	LedgerStock int       `json:"ledger_stock"`
+}
+
+func NewStockMovement(
+	productID uuid.UUID,
+	movementType StockMovementType,
+	quantity int,
+) *StockMovement {
+	return &StockMovement{
+		ID:        uuid.New(),
+		ProductID: productID,
+		Type:      movementType,
+		Quantity:  quantity,
+		CreatedAt: time.Now(),
+	}
+}
+
+// NewOrderStockMovement records stock leaving or returning for order.
+func NewOrderStockMovement(
+	productID uuid.UUID,
+	movementType StockMovementType,
+	quantity int,
+	orderID uuid.UUID,
+) *StockMovement {
+	movement := NewStockMovement(productID, movementType, quantity)
+	movement.OrderID = &orderID
+	return movement
+}
+
+// Validate checks the type and that the sign of Quantity matches it.
+func (m *StockMovement) Validate() error {
+	switch m.Type {
+	case StockMovementSale, StockMovementSpoilage:
+		if m.Quantity >= 0 {
+			return fmt.Errorf("%s movements must remove stock", m.Type)
+		}
+	case StockMovementRestock, StockMovementReturn:
+		if m.Quantity <= 0 {
+			return fmt.Errorf("%s movements must add stock", m.Type)
+		}
+	case StockMovementAdjustment:
+		if m.Quantity == 0 {
+			return fmt.Errorf("adjustments must change stock")
+		}
+	default:
+		return fmt.Errorf("invalid stock movement type: %s", m.Type)
+	}
+	return nil
+}
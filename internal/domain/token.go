@@ -6,15 +6,25 @@ import (
 	"github.com/google/uuid"
 )
 
+// Token is an issued access or refresh token. The plaintext Token is
+// never persisted: the repository stores and looks tokens up by
+// TokenHash, a keyed hash of it. Tokens issued for the same login share
+// a FamilyID; refreshing rotates the refresh token within its family.
 type Token struct {
 	ID         uuid.UUID  `json:"id"                   gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
 	UserID     uuid.UUID  `json:"user_id"              gorm:"type:uuid;not null;index"`
-	Token      string     `json:"token"                gorm:"type:text;not null;unique;index"`
+	Token      string     `json:"-"                    gorm:"-"`
+	TokenHash  string     `json:"-"                    gorm:"type:char(64);not null;uniqueIndex"`
 	Type       TokenType  `json:"type"                 gorm:"type:varchar(20);not null"`
+	FamilyID   uuid.UUID  `json:"family_id"            gorm:"type:uuid;not null;index"`
 	ExpiresAt  time.Time  `json:"expires_at"           gorm:"not null;index"`
 	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
 	Provider   string     `json:"provider"             gorm:"type:varchar(50);not null;default:'google'"`
 	ProviderID string     `json:"provider_id"          gorm:"type:varchar(255)"`
+	IPAddress  string     `json:"ip_address"           gorm:"type:varchar(45)"`
+	UserAgent  string     `json:"user_agent"           gorm:"type:text"`
+	Device     string     `json:"device"               gorm:"type:varchar(100)"`
+	LastUsedAt time.Time  `json:"last_used_at"         gorm:"not null;default:current_timestamp"`
 	CreatedAt  time.Time  `json:"created_at"           gorm:"not null;default:current_timestamp"`
 	UpdatedAt  time.Time  `json:"updated_at"           gorm:"not null;default:current_timestamp"`
 	User       *User      `json:"-"                    gorm:"foreignKey:UserID"`
@@ -28,12 +38,29 @@ type TokenPair struct {
 	ExpiresIn    int    `json:"expires_in"`
 }
 
+// AccessTokenClaims identify the caller of an access token issued and
+// signed by this service.
+type AccessTokenClaims struct {
+	UserID      uuid.UUID
+	SessionID   uuid.UUID
+	Email       string
+	Role        UserRole
+	Permissions []Permission
+	ExpiresAt   time.Time
+	// MFAVerifiedAt is when the caller last passed MFA in this session,
+	// zero if the token was not issued right after an MFA check.
+	MFAVerifiedAt time.Time
+}
+
 type TokenType string
 
 const (
 	TokenTypeAccess  TokenType = "access"
 	TokenTypeRefresh TokenType = "refresh"
 	TokenTypeID      TokenType = "id"
+	// Single-use tokens sent in email links
+	TokenTypeEmailVerification TokenType = "email_verification"
+	TokenTypePasswordReset     TokenType = "password_reset"
 )
 
 func (Token) TableName() string {
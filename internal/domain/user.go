@@ -8,23 +8,61 @@ import (
 
 type UserRole string
 
+// Built-in roles. Further roles can be defined through the admin API.
 const (
-	AdminRole    UserRole = "admin"
-	CustomerRole UserRole = "customer"
+	AdminRole        UserRole = "admin"
+	StoreManagerRole UserRole = "store-manager"
+	PickerRole       UserRole = "picker"
+	DriverRole       UserRole = "driver"
+	CustomerRole     UserRole = "customer"
 )
 
+// User is an account. Users signing in through an identity provider have
+// no Password; EmailVerifiedAt is when the user proved they own Email,
+// through the provider or a verification link. MFASecret is the user's
+// TOTP secret, encrypted, once MFA is enabled; MFALastStep is the time
+// step of the last code accepted, so that no code is accepted twice.
 type User struct {
-	ID        uuid.UUID `json:"id"                gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
-	Email     string    `json:"email"             gorm:"type:varchar(255);unique;not null"`
-	Password  string    `json:"-"                 gorm:"type:varchar(255)"`
-	Name      string    `json:"name"              gorm:"type:varchar(255);not null"`
-	Phone     string    `json:"phone"             gorm:"type:varchar(50);not null"`
-	Address   string    `json:"address"           gorm:"type:text"`
-	Picture   string    `json:"picture,omitempty" gorm:"type:text"`
-	Role      UserRole  `json:"role"              gorm:"type:varchar(50);default:'user'"`
-	CreatedAt time.Time `json:"created_at"        gorm:"not null;default:current_timestamp"`
-	UpdatedAt time.Time `json:"updated_at"        gorm:"not null;default:current_timestamp"`
-	Tokens    []Token   `json:"-"                 gorm:"foreignKey:UserID"`
+	ID                  uuid.UUID  `json:"id"                          gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Email               string     `json:"email"                       gorm:"type:varchar(255);unique;not null"`
+	Password            string     `json:"-"                           gorm:"type:varchar(255)"`
+	Name                string     `json:"name"                        gorm:"type:varchar(255);not null"`
+	Phone               string     `json:"phone"                       gorm:"type:varchar(50);not null"`
+	Address             string     `json:"address"                     gorm:"type:text"`
+	Picture             string     `json:"picture,omitempty"           gorm:"type:text"`
+	Role                UserRole   `json:"role"                        gorm:"type:varchar(50);not null;default:'customer'"`
+	EmailVerifiedAt     *time.Time `json:"email_verified_at,omitempty"`
+	FailedLoginAttempts int        `json:"-"                           gorm:"not null;default:0"`
+	LockedUntil         *time.Time `json:"-"`
+	MFASecret           string     `json:"-"                           gorm:"type:text"`
+	MFAEnabledAt        *time.Time `json:"mfa_enabled_at,omitempty"`
+	MFARequired         bool       `json:"mfa_required"                gorm:"not null;default:false"`
+	MFALastStep         *int64     `json:"-"`
+	CreatedAt           time.Time  `json:"created_at"                  gorm:"not null;default:current_timestamp"`
+	UpdatedAt           time.Time  `json:"updated_at"                  gorm:"not null;default:current_timestamp"`
+	Tokens              []Token    `json:"-"                           gorm:"foreignKey:UserID"`
+}
+
+// MFAEnabled reports whether the user signs in with a second factor.
+func (u *User) MFAEnabled() bool {
+	return u.MFAEnabledAt != nil
+}
+
+// IsLocked reports whether password logins to the user's account are
+// refused at now.
+func (u *User) IsLocked(now time.Time) bool {
+	return u.LockedUntil != nil && u.LockedUntil.After(now)
+}
+
+// UserIdentity links a user to the subject an identity provider knows
+// them by. Returning provider logins are matched on Provider and Subject,
+// never on the email address alone.
+type UserIdentity struct {
+	ID        uuid.UUID `json:"id"         gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID    uuid.UUID `json:"user_id"    gorm:"type:uuid;not null;index"`
+	Provider  string    `json:"provider"   gorm:"type:varchar(50);not null;uniqueIndex:uq_user_identities_subject"`
+	Subject   string    `json:"subject"    gorm:"type:varchar(255);not null;uniqueIndex:uq_user_identities_subject"`
+	CreatedAt time.Time `json:"created_at" gorm:"not null;default:current_timestamp"`
 }
 
 type UserInfo struct {
@@ -36,12 +74,79 @@ type UserInfo struct {
 	Role          string `json:"role"`
 }
 
+// LoginRequest starts a login with an identity provider. RedirectTo is
+// where the client wants to resume after signing in.
+type LoginRequest struct {
+	Provider   string
+	RedirectTo string
+}
+
+// AuthRequest is a login started with an identity provider. Session is
+// signed, opaque login state that the caller keeps until ExpiresAt and
+// hands back in the AuthCallback.
+type AuthRequest struct {
+	URL       string
+	Session   string
+	ExpiresAt time.Time
+}
+
+// AuthCallback is the result of a provider login.
+type AuthCallback struct {
+	Code    string
+	State   string
+	Session string
+	Client  ClientInfo
+}
+
+// AuthResponse completes a login. When the user has to pass MFA first it
+// carries no tokens: MFARequired is set and MFAToken is exchanged for
+// them together with a code at POST /auth/mfa/verify. MFAEnrollment is
+// set instead when the user must first enroll, and the recovery codes
+// generated by that enrollment come back with the tokens.
 type AuthResponse struct {
-	AccessToken  string `json:"access_token"`
-	TokenType    string `json:"token_type"`
-	ExpiresIn    int    `json:"expires_in"`
-	RefreshToken string `json:"refresh_token"`
-	User         *User  `json:"user"`
+	AccessToken   string         `json:"access_token,omitempty"`
+	TokenType     string         `json:"token_type,omitempty"`
+	ExpiresIn     int            `json:"expires_in,omitempty"`
+	RefreshToken  string         `json:"refresh_token,omitempty"`
+	User          *User          `json:"user"`
+	RedirectTo    string         `json:"redirect_to,omitempty"`
+	MFARequired   bool           `json:"mfa_required,omitempty"`
+	MFAToken      string         `json:"mfa_token,omitempty"`
+	MFAEnrollment *MFAEnrollment `json:"mfa_enrollment,omitempty"`
+	RecoveryCodes []string       `json:"recovery_codes,omitempty"`
+}
+
+// RegisterRequest signs a user up with an email address and password.
+type RegisterRequest struct {
+	Email    string `json:"email"           validate:"required,email"`
+	Password string `json:"password"        validate:"required,password"`
+	Name     string `json:"name"            validate:"required,max=255"`
+	Phone    string `json:"phone,omitempty" validate:"omitempty,phone"`
+}
+
+// PasswordLoginRequest signs a user in with their email address and
+// password.
+type PasswordLoginRequest struct {
+	Email    string `json:"email"    validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+// EmailRequest names the account a verification or password reset
+// email is sent for.
+type EmailRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// VerifyEmailRequest carries the token of an email verification link.
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// ResetPasswordRequest sets a new password with the token of a password
+// reset link.
+type ResetPasswordRequest struct {
+	Token    string `json:"token"    validate:"required"`
+	Password string `json:"password" validate:"required,password"`
 }
 
 type RefreshTokenRequest struct {
@@ -0,0 +1,241 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grocery-service/internal/domain"
+	"github.com/grocery-service/internal/repository/db"
+	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/grocery-service/utils/hash"
+	"gorm.io/gorm"
+)
+
+type (
+	// APIKeyRepository stores API keys with the permissions granted to
+	// them. Keys are kept only as an HMAC keyed with hashKey, like
+	// tokens, and are returned with Permissions filled in.
+	APIKeyRepository interface {
+		Create(ctx context.Context, key *domain.APIKey) error
+		List(ctx context.Context) ([]domain.APIKey, error)
+		GetByID(ctx context.Context, id string) (*domain.APIKey, error)
+		// GetByKey finds a key by its plaintext, expired or not. It
+		// fails with ErrAPIKeyNotFound when there is none.
+		GetByKey(ctx context.Context, key string) (*domain.APIKey, error)
+		// Update replaces the name, expiry and permissions of a key.
+		Update(ctx context.Context, key *domain.APIKey) error
+		Delete(ctx context.Context, id string) error
+		// Touch records that a key was used at usedAt.
+		Touch(ctx context.Context, id string, usedAt time.Time) error
+	}
+
+	APIKeyRepositoryImpl struct {
+		*db.BaseRepository[domain.APIKey]
+		hashKey []byte
+	}
+)
+
+func NewAPIKeyRepository(
+	postgres *db.PostgresDB,
+	hashKey string,
+) *APIKeyRepositoryImpl {
+	return &APIKeyRepositoryImpl{
+		BaseRepository: db.NewBaseRepository[domain.APIKey](
+			postgres,
+		),
+		hashKey: []byte(hashKey),
+	}
+}
+
+func (r *APIKeyRepositoryImpl) hash(key string) string {
+	return hash.Token(r.hashKey, key)
+}
+
+func (r *APIKeyRepositoryImpl) Create(
+	ctx context.Context,
+	key *domain.APIKey,
+) error {
+	return r.BaseRepository.WithTransaction(
+		ctx,
+		func(txRepo *db.BaseRepository[domain.APIKey]) error {
+			key.KeyHash = r.hash(key.Key)
+			if err := txRepo.GetDB().WithContext(ctx).
+				Create(key).Error; err != nil {
+				return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+			}
+
+			return grantAPIKeyPermissions(ctx, txRepo.GetDB(), key)
+		},
+	)
+}
+
+func (r *APIKeyRepositoryImpl) List(
+	ctx context.Context,
+) ([]domain.APIKey, error) {
+	var keys []domain.APIKey
+	if err := r.BaseRepository.GetDB().WithContext(ctx).
+		Order("created_at DESC").
+		Find(&keys).Error; err != nil {
+		return nil, fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+	}
+
+	var grants []domain.APIKeyPermission
+	if err := r.BaseRepository.GetDB().WithContext(ctx).
+		Order("permission").
+		Find(&grants).Error; err != nil {
+		return nil, fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+	}
+
+	byKey := make(map[uuid.UUID][]domain.Permission, len(keys))
+	for _, g := range grants {
+		byKey[g.APIKeyID] = append(byKey[g.APIKeyID], g.Permission)
+	}
+	for i := range keys {
+		keys[i].Permissions = byKey[keys[i].ID]
+		if keys[i].Permissions == nil {
+			keys[i].Permissions = []domain.Permission{}
+		}
+	}
+
+	return keys, nil
+}
+
+func (r *APIKeyRepositoryImpl) GetByID(
+	ctx context.Context,
+	id string,
+) (*domain.APIKey, error) {
+	return r.get(ctx, "id = ?", id)
+}
+
+func (r *APIKeyRepositoryImpl) GetByKey(
+	ctx context.Context,
+	key string,
+) (*domain.APIKey, error) {
+	return r.get(ctx, "key_hash = ?", r.hash(key))
+}
+
+func (r *APIKeyRepositoryImpl) get(
+	ctx context.Context,
+	query string,
+	arg interface{},
+) (*domain.APIKey, error) {
+	var key domain.APIKey
+	err := r.BaseRepository.GetDB().WithContext(ctx).
+		Where(query, arg).
+		First(&key).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, customErrors.ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+	}
+
+	key.Permissions = []domain.Permission{}
+	if err := r.BaseRepository.GetDB().WithContext(ctx).
+		Model(&domain.APIKeyPermission{}).
+		Where("api_key_id = ?", key.ID).
+		Order("permission").
+		Pluck("permission", &key.Permissions).Error; err != nil {
+		return nil, fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+	}
+
+	return &key, nil
+}
+
+func (r *APIKeyRepositoryImpl) Update(
+	ctx context.Context,
+	key *domain.APIKey,
+) error {
+	return r.BaseRepository.WithTransaction(
+		ctx,
+		func(txRepo *db.BaseRepository[domain.APIKey]) error {
+			result := txRepo.GetDB().WithContext(ctx).
+				Model(&domain.APIKey{}).
+				Where("id = ?", key.ID).
+				Updates(map[string]interface{}{
+					"name":       key.Name,
+					"expires_at": key.ExpiresAt,
+					"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+				})
+			if result.Error != nil {
+				return fmt.Errorf(
+					"%w: %v",
+					customErrors.ErrDBQuery,
+					result.Error,
+				)
+			}
+			if result.RowsAffected == 0 {
+				return customErrors.ErrAPIKeyNotFound
+			}
+
+			if err := txRepo.GetDB().WithContext(ctx).
+				Where("api_key_id = ?", key.ID).
+				Delete(&domain.APIKeyPermission{}).Error; err != nil {
+				return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+			}
+
+			return grantAPIKeyPermissions(ctx, txRepo.GetDB(), key)
+		},
+	)
+}
+
+func (r *APIKeyRepositoryImpl) Delete(
+	ctx context.Context,
+	id string,
+) error {
+	result := r.BaseRepository.GetDB().WithContext(ctx).
+		Delete(&domain.APIKey{}, "id = ?", id)
+	if result.Error != nil {
+		return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return customErrors.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+func (r *APIKeyRepositoryImpl) Touch(
+	ctx context.Context,
+	id string,
+	usedAt time.Time,
+) error {
+	result := r.BaseRepository.GetDB().WithContext(ctx).
+		Model(&domain.APIKey{}).
+		Where("id = ?", id).
+		Update("last_used_at", usedAt)
+	if result.Error != nil {
+		return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return customErrors.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+func grantAPIKeyPermissions(
+	ctx context.Context,
+	tx *gorm.DB,
+	key *domain.APIKey,
+) error {
+	if len(key.Permissions) == 0 {
+		return nil
+	}
+
+	grants := make([]domain.APIKeyPermission, 0, len(key.Permissions))
+	for _, p := range key.Permissions {
+		grants = append(grants, domain.APIKeyPermission{
+			APIKeyID:   key.ID,
+			Permission: p,
+		})
+	}
+
+	if err := tx.WithContext(ctx).Create(&grants).Error; err != nil {
+		return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+	}
+	return nil
+}
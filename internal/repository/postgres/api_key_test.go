@@ -0,0 +1,133 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grocery-service/internal/domain"
+	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyRepositoryImpl(t *testing.T) {
+	postgres := setupTestDB(t, &domain.APIKey{}, &domain.APIKeyPermission{})
+	repo := NewAPIKeyRepository(postgres, "test-hash-key")
+	ctx := context.Background()
+
+	newKey := func(
+		t *testing.T,
+		permissions ...domain.Permission,
+	) *domain.APIKey {
+		id := uuid.New()
+		key := &domain.APIKey{
+			ID:          id,
+			Name:        "Scanner",
+			Prefix:      domain.APIKeyPrefix + id.String()[:8],
+			Key:         domain.APIKeyPrefix + id.String(),
+			Permissions: permissions,
+		}
+		require.NoError(t, repo.Create(ctx, key))
+		return key
+	}
+
+	t.Run("Create stores only a hash", func(t *testing.T) {
+		key := newKey(t, domain.PermissionInventoryWrite)
+
+		var stored domain.APIKey
+		require.NoError(t, postgres.DB.First(&stored, "id = ?", key.ID).Error)
+		assert.NotEmpty(t, stored.KeyHash)
+		assert.NotEqual(t, key.Key, stored.KeyHash)
+	})
+
+	t.Run("GetByKey", func(t *testing.T) {
+		key := newKey(
+			t,
+			domain.PermissionOrdersRead,
+			domain.PermissionInventoryRead,
+		)
+
+		found, err := repo.GetByKey(ctx, key.Key)
+		require.NoError(t, err)
+		assert.Equal(t, key.ID, found.ID)
+		assert.Equal(t, []domain.Permission{
+			domain.PermissionInventoryRead,
+			domain.PermissionOrdersRead,
+		}, found.Permissions)
+
+		_, err = repo.GetByKey(ctx, "gsk_unknown")
+		assert.ErrorIs(t, err, customErrors.ErrAPIKeyNotFound)
+	})
+
+	t.Run("Update replaces permissions", func(t *testing.T) {
+		key := newKey(t, domain.PermissionOrdersRead)
+		expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+		key.Name = "POS terminal"
+		key.ExpiresAt = &expiresAt
+		key.Permissions = []domain.Permission{domain.PermissionOrdersCreate}
+		require.NoError(t, repo.Update(ctx, key))
+
+		found, err := repo.GetByID(ctx, key.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, "POS terminal", found.Name)
+		require.NotNil(t, found.ExpiresAt)
+		assert.True(t, expiresAt.Equal(*found.ExpiresAt))
+		assert.Equal(
+			t,
+			[]domain.Permission{domain.PermissionOrdersCreate},
+			found.Permissions,
+		)
+
+		missing := &domain.APIKey{ID: uuid.New(), Name: "Missing"}
+		assert.ErrorIs(
+			t,
+			repo.Update(ctx, missing),
+			customErrors.ErrAPIKeyNotFound,
+		)
+	})
+
+	t.Run("Touch", func(t *testing.T) {
+		key := newKey(t)
+		usedAt := time.Now().UTC().Truncate(time.Second)
+
+		require.NoError(t, repo.Touch(ctx, key.ID.String(), usedAt))
+
+		found, err := repo.GetByID(ctx, key.ID.String())
+		require.NoError(t, err)
+		require.NotNil(t, found.LastUsedAt)
+		assert.True(t, usedAt.Equal(*found.LastUsedAt))
+		assert.Empty(t, found.Permissions)
+	})
+
+	t.Run("List and Delete", func(t *testing.T) {
+		key := newKey(t, domain.PermissionProductsWrite)
+
+		keys, err := repo.List(ctx)
+		require.NoError(t, err)
+		var listed *domain.APIKey
+		for i := range keys {
+			if keys[i].ID == key.ID {
+				listed = &keys[i]
+			}
+		}
+		require.NotNil(t, listed)
+		assert.Equal(
+			t,
+			[]domain.Permission{domain.PermissionProductsWrite},
+			listed.Permissions,
+		)
+
+		require.NoError(t, repo.Delete(ctx, key.ID.String()))
+
+		_, err = repo.GetByKey(ctx, key.Key)
+		assert.ErrorIs(t, err, customErrors.ErrAPIKeyNotFound)
+		assert.ErrorIs(
+			t,
+			repo.Delete(ctx, key.ID.String()),
+			customErrors.ErrAPIKeyNotFound,
+		)
+	})
+}
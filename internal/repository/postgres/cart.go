@@ -0,0 +1,201 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grocery-service/internal/domain"
+	"github.com/grocery-service/internal/repository/db"
+	customErrors "github.com/grocery-service/utils/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	CartRepository interface {
+		Create(ctx context.Context, cart *domain.Cart) error
+		GetByCustomerID(
+			ctx context.Context,
+			customerID string,
+		) (*domain.Cart, error)
+		AddItem(ctx context.Context, item *domain.CartItem) error
+		UpdateItemQuantity(
+			ctx context.Context,
+			cartID, itemID string,
+			quantity int,
+		) error
+		RemoveItem(ctx context.Context, cartID, itemID string) error
+		Clear(ctx context.Context, cartID string) error
+	}
+
+	CartRepositoryImpl struct {
+		*db.BaseRepository[domain.Cart]
+	}
+)
+
+func NewCartRepository(
+	postgres *db.PostgresDB,
+) *CartRepositoryImpl {
+	return &CartRepositoryImpl{
+		BaseRepository: db.NewBaseRepository[domain.Cart](
+			postgres,
+		),
+	}
+}
+
+func (r *CartRepositoryImpl) Create(
+	ctx context.Context,
+	cart *domain.Cart,
+) error {
+	return r.BaseRepository.WithTransaction(
+		ctx,
+		func(txRepo *db.BaseRepository[domain.Cart]) error {
+			if err := txRepo.GetDB().WithContext(ctx).Create(cart).Error; err != nil {
+				return fmt.Errorf(
+					"%w: %v",
+					customErrors.ErrInvalidCartData,
+					err,
+				)
+			}
+			return nil
+		},
+	)
+}
+
+func (r *CartRepositoryImpl) GetByCustomerID(
+	ctx context.Context,
+	customerID string,
+) (*domain.Cart, error) {
+	var cart domain.Cart
+	err := r.BaseRepository.GetDB().WithContext(ctx).
+		Preload("Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at")
+		}).
+		Preload("Items.Product").
+		First(&cart, "customer_id = ?", customerID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, customErrors.ErrCartNotFound
+		}
+
+		return nil, fmt.Errorf(
+			"%w: %v",
+			customErrors.ErrDBQuery,
+			err,
+		)
+	}
+	return &cart, nil
+}
+
+// AddItem inserts item into its cart. If the product is already in the
+// cart the quantities are summed and the unit price is refreshed.
+func (r *CartRepositoryImpl) AddItem(
+	ctx context.Context,
+	item *domain.CartItem,
+) error {
+	return r.BaseRepository.WithTransaction(
+		ctx,
+		func(txRepo *db.BaseRepository[domain.Cart]) error {
+			err := txRepo.GetDB().WithContext(ctx).
+				Clauses(clause.OnConflict{
+					Columns: []clause.Column{
+						{Name: "cart_id"},
+						{Name: "product_id"},
+					},
+					DoUpdates: clause.Assignments(map[string]interface{}{
+						"quantity": gorm.Expr(
+							"cart_items.quantity + EXCLUDED.quantity",
+						),
+						"unit_price": gorm.Expr("EXCLUDED.unit_price"),
+						"updated_at": time.Now(),
+					}),
+				}).
+				Create(item).Error
+			if err != nil {
+				return fmt.Errorf(
+					"%w: %v",
+					customErrors.ErrInvalidCartData,
+					err,
+				)
+			}
+			return nil
+		},
+	)
+}
+
+func (r *CartRepositoryImpl) UpdateItemQuantity(
+	ctx context.Context,
+	cartID, itemID string,
+	quantity int,
+) error {
+	return r.BaseRepository.WithTransaction(
+		ctx,
+		func(txRepo *db.BaseRepository[domain.Cart]) error {
+			result := txRepo.GetDB().WithContext(ctx).
+				Model(&domain.CartItem{}).
+				Where("id = ? AND cart_id = ?", itemID, cartID).
+				Update("quantity", quantity)
+			if result.Error != nil {
+				return fmt.Errorf(
+					"%w: %v",
+					customErrors.ErrInvalidCartData,
+					result.Error,
+				)
+			}
+
+			if result.RowsAffected == 0 {
+				return customErrors.ErrCartItemNotFound
+			}
+			return nil
+		},
+	)
+}
+
+func (r *CartRepositoryImpl) RemoveItem(
+	ctx context.Context,
+	cartID, itemID string,
+) error {
+	return r.BaseRepository.WithTransaction(
+		ctx,
+		func(txRepo *db.BaseRepository[domain.Cart]) error {
+			result := txRepo.GetDB().WithContext(ctx).
+				Where("id = ? AND cart_id = ?", itemID, cartID).
+				Delete(&domain.CartItem{})
+			if result.Error != nil {
+				return fmt.Errorf(
+					"%w: %v",
+					customErrors.ErrDBQuery,
+					result.Error,
+				)
+			}
+
+			if result.RowsAffected == 0 {
+				return customErrors.ErrCartItemNotFound
+			}
+			return nil
+		},
+	)
+}
+
+func (r *CartRepositoryImpl) Clear(
+	ctx context.Context,
+	cartID string,
+) error {
+	return r.BaseRepository.WithTransaction(
+		ctx,
+		func(txRepo *db.BaseRepository[domain.Cart]) error {
+			if err := txRepo.GetDB().WithContext(ctx).
+				Where("cart_id = ?", cartID).
+				Delete(&domain.CartItem{}).Error; err != nil {
+				return fmt.Errorf(
+					"%w: %v",
+					customErrors.ErrDBQuery,
+					err,
+				)
+			}
+			return nil
+		},
+	)
+}
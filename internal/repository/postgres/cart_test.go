@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/grocery-service/internal/domain"
+	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupCartTestDB(t *testing.T) (*CartRepositoryImpl, *domain.Cart, *domain.Product) {
+	postgres := setupTestDB(
+		t,
+		&domain.CartItem{},
+		&domain.Cart{},
+		&domain.Product{},
+		&domain.Category{},
+		&domain.Customer{},
+		&domain.User{},
+	)
+	repo := NewCartRepository(postgres)
+
+	customer := createTestCustomer(t, postgres.DB)
+	product := createTestProduct(t, postgres.DB)
+	cart := domain.NewCart(customer.ID)
+	require.NoError(t, repo.Create(context.Background(), cart))
+
+	return repo, cart, product
+}
+
+func TestCartRepository_GetByCustomerID(t *testing.T) {
+	repo, cart, _ := setupCartTestDB(t)
+	ctx := context.Background()
+
+	found, err := repo.GetByCustomerID(ctx, cart.CustomerID.String())
+	require.NoError(t, err)
+	assert.Equal(t, cart.ID, found.ID)
+
+	_, err = repo.GetByCustomerID(ctx, uuid.New().String())
+	assert.ErrorIs(t, err, customErrors.ErrCartNotFound)
+}
+
+func TestCartRepository_AddItem(t *testing.T) {
+	repo, cart, product := setupCartTestDB(t)
+	ctx := context.Background()
+
+	first := domain.NewCartItem(cart.ID, product.ID, 2, domain.NewMoney(1000, domain.DefaultCurrency))
+	require.NoError(t, repo.AddItem(ctx, first))
+
+	// Adding the same product again sums the quantity and refreshes the price
+	second := domain.NewCartItem(cart.ID, product.ID, 3, domain.NewMoney(1200, domain.DefaultCurrency))
+	require.NoError(t, repo.AddItem(ctx, second))
+
+	found, err := repo.GetByCustomerID(ctx, cart.CustomerID.String())
+	require.NoError(t, err)
+	require.Len(t, found.Items, 1)
+	assert.Equal(t, 5, found.Items[0].Quantity)
+	assert.Equal(t, domain.NewMoney(1200, domain.DefaultCurrency), found.Items[0].UnitPrice)
+	assert.NotNil(t, found.Items[0].Product)
+}
+
+func TestCartRepository_UpdateItemQuantity(t *testing.T) {
+	repo, cart, product := setupCartTestDB(t)
+	ctx := context.Background()
+
+	item := domain.NewCartItem(cart.ID, product.ID, 2, domain.NewMoney(1000, domain.DefaultCurrency))
+	require.NoError(t, repo.AddItem(ctx, item))
+
+	err := repo.UpdateItemQuantity(ctx, cart.ID.String(), item.ID.String(), 7)
+	require.NoError(t, err)
+
+	found, err := repo.GetByCustomerID(ctx, cart.CustomerID.String())
+	require.NoError(t, err)
+	require.Len(t, found.Items, 1)
+	assert.Equal(t, 7, found.Items[0].Quantity)
+
+	err = repo.UpdateItemQuantity(ctx, uuid.New().String(), item.ID.String(), 1)
+	assert.ErrorIs(t, err, customErrors.ErrCartItemNotFound)
+}
+
+func TestCartRepository_RemoveItemAndClear(t *testing.T) {
+	repo, cart, product := setupCartTestDB(t)
+	ctx := context.Background()
+
+	item := domain.NewCartItem(cart.ID, product.ID, 2, domain.NewMoney(1000, domain.DefaultCurrency))
+	require.NoError(t, repo.AddItem(ctx, item))
+
+	require.NoError(t, repo.RemoveItem(ctx, cart.ID.String(), item.ID.String()))
+	assert.ErrorIs(
+		t,
+		repo.RemoveItem(ctx, cart.ID.String(), item.ID.String()),
+		customErrors.ErrCartItemNotFound,
+	)
+
+	require.NoError(t, repo.AddItem(ctx, domain.NewCartItem(cart.ID, product.ID, 1, domain.NewMoney(1000, domain.DefaultCurrency))))
+	require.NoError(t, repo.Clear(ctx, cart.ID.String()))
+
+	found, err := repo.GetByCustomerID(ctx, cart.CustomerID.String())
+	require.NoError(t, err)
+	assert.Empty(t, found.Items)
+}
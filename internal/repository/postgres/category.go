@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/google/uuid"
 	"github.com/grocery-service/internal/domain"
 	"github.com/grocery-service/internal/repository/db"
 	customErrors "github.com/grocery-service/utils/errors"
@@ -15,7 +16,10 @@ type (
 	CategoryRepository interface {
 		Create(ctx context.Context, category *domain.Category) error
 		GetByID(ctx context.Context, id string) (*domain.Category, error)
-		List(ctx context.Context) ([]domain.Category, error)
+		List(
+			ctx context.Context,
+			query domain.ListQuery,
+		) (*domain.Page[domain.Category], error)
 		ListByParentID(
 			ctx context.Context,
 			parentID string,
@@ -31,6 +35,30 @@ type (
 	}
 )
 
+var categoryListSpec = listSpec[domain.Category]{
+	table:       "categories",
+	defaultSort: "path",
+	sortFields: map[string]sortField[domain.Category]{
+		"name": {
+			column: "categories.name",
+			value:  func(c domain.Category) any { return c.Name },
+		},
+		"path": {
+			column: "categories.path",
+			value:  func(c domain.Category) any { return c.Path },
+		},
+		"level": {
+			column: "categories.level",
+			value:  func(c domain.Category) any { return c.Level },
+		},
+		"created_at": {
+			column: "categories.created_at",
+			value:  func(c domain.Category) any { return c.CreatedAt },
+		},
+	},
+	id: func(c domain.Category) uuid.UUID { return c.ID },
+}
+
 func NewCategoryRepository(
 	postgres *db.PostgresDB,
 ) *CategoryRepositoryImpl {
@@ -136,19 +164,13 @@ func (r *CategoryRepositoryImpl) GetByID(
 
 func (r *CategoryRepositoryImpl) List(
 	ctx context.Context,
-) ([]domain.Category, error) {
-	var categories []domain.Category
-	err := r.BaseRepository.GetDB().
-		WithContext(ctx).
-		Find(&categories).Error
-	if err != nil {
-		return nil, fmt.Errorf(
-			"%w: %v",
-			customErrors.ErrDBQuery,
-			err,
-		)
-	}
-	return categories, nil
+	query domain.ListQuery,
+) (*domain.Page[domain.Category], error) {
+	db := r.BaseRepository.GetDB().WithContext(ctx).
+		Model(&domain.Category{})
+	db = applyCreatedRange(db, "categories", query.Filter)
+
+	return listPage(db, query, categoryListSpec)
 }
 
 func (r *CategoryRepositoryImpl) ListByParentID(
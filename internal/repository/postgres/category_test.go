@@ -274,13 +274,13 @@ func TestCategoryRepository_List(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			found, err := repo.List(ctx)
+			found, err := repo.List(ctx, domain.ListQuery{})
 			if tt.wantErr {
 				assert.Error(t, err)
 				return
 			}
 			assert.NoError(t, err)
-			assert.Len(t, found, tt.expectedCount)
+			assert.Len(t, found.Items, tt.expectedCount)
 		})
 	}
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/google/uuid"
 	"github.com/grocery-service/internal/domain"
 	"github.com/grocery-service/internal/repository/db"
 	customErrors "github.com/grocery-service/utils/errors"
@@ -19,7 +20,10 @@ type (
 			ctx context.Context,
 			userID string,
 		) (*domain.Customer, error)
-		List(ctx context.Context) ([]domain.Customer, error)
+		List(
+			ctx context.Context,
+			query domain.ListQuery,
+		) (*domain.Page[domain.Customer], error)
 		Update(ctx context.Context, customer *domain.Customer) error
 		Delete(ctx context.Context, id string) error
 	}
@@ -29,6 +33,19 @@ type (
 	}
 )
 
+var customerListSpec = listSpec[domain.Customer]{
+	table:       "customers",
+	defaultSort: "created_at",
+	sortFields: map[string]sortField[domain.Customer]{
+		"created_at": {
+			column: "customers.created_at",
+			value:  func(c domain.Customer) any { return c.CreatedAt },
+		},
+	},
+	id:       func(c domain.Customer) uuid.UUID { return c.ID },
+	preloads: []string{"User"},
+}
+
 func NewCustomerRepository(
 	postgres *db.PostgresDB,
 ) *CustomerRepositoryImpl {
@@ -124,19 +141,13 @@ func (r *CustomerRepositoryImpl) GetByUserID(
 
 func (r *CustomerRepositoryImpl) List(
 	ctx context.Context,
-) ([]domain.Customer, error) {
-	var customers []domain.Customer
-	err := r.BaseRepository.GetDB().WithContext(ctx).
-		Preload("User").
-		Find(&customers).Error
-	if err != nil {
-		return nil, fmt.Errorf(
-			"%w: %v",
-			customErrors.ErrDBQuery,
-			err,
-		)
-	}
-	return customers, nil
+	query domain.ListQuery,
+) (*domain.Page[domain.Customer], error) {
+	db := r.BaseRepository.GetDB().WithContext(ctx).
+		Model(&domain.Customer{})
+	db = applyCreatedRange(db, "customers", query.Filter)
+
+	return listPage(db, query, customerListSpec)
 }
 
 func (r *CustomerRepositoryImpl) Update(
@@ -242,12 +242,12 @@ func TestCustomerRepository_List(t *testing.T) {
 			ctx := context.Background()
 
 			_ = tt.setupTest(postgres.DB)
-			found, err := repo.List(ctx)
+			found, err := repo.List(ctx, domain.ListQuery{})
 
 			assert.NoError(t, err)
-			assert.Len(t, found, tt.expectedCount)
+			assert.Len(t, found.Items, tt.expectedCount)
 			if tt.expectedCount > 0 {
-				for _, customer := range found {
+				for _, customer := range found.Items {
 					assert.NotNil(t, customer.User)
 				}
 			}
@@ -0,0 +1,178 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grocery-service/internal/domain"
+	"github.com/grocery-service/internal/repository/db"
+	customErrors "github.com/grocery-service/utils/errors"
+	"gorm.io/gorm"
+)
+
+type (
+	// IdempotencyRepository stores idempotency keys with the responses
+	// recorded for them.
+	IdempotencyRepository interface {
+		// Claim stores key for a new request. When the client already
+		// has a live key of the same name, that one is returned instead
+		// with claimed false. An expired key is replaced, and so is a
+		// key for the same request that is not completed and whose
+		// lock has lapsed.
+		Claim(
+			ctx context.Context,
+			key *domain.IdempotencyKey,
+		) (stored *domain.IdempotencyKey, claimed bool, err error)
+		// Complete records the response of a claimed key. It fails
+		// with ErrIdempotencyKeyNotFound unless the key is still held
+		// by the claim with key.ClaimID and not yet completed.
+		Complete(ctx context.Context, key *domain.IdempotencyKey) error
+		// Release deletes a claimed key that has not completed, so that
+		// the request can be retried. Like Complete, it only acts for
+		// the claim that holds the key.
+		Release(ctx context.Context, key *domain.IdempotencyKey) error
+		// DeleteExpired deletes the keys expired at now and returns how
+		// many there were.
+		DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+	}
+
+	IdempotencyRepositoryImpl struct {
+		*db.BaseRepository[domain.IdempotencyKey]
+	}
+)
+
+func NewIdempotencyRepository(
+	postgres *db.PostgresDB,
+) *IdempotencyRepositoryImpl {
+	return &IdempotencyRepositoryImpl{
+		BaseRepository: db.NewBaseRepository[domain.IdempotencyKey](
+			postgres,
+		),
+	}
+}
+
+func (r *IdempotencyRepositoryImpl) Claim(
+	ctx context.Context,
+	key *domain.IdempotencyKey,
+) (*domain.IdempotencyKey, bool, error) {
+	// Inserts the key, or takes over an expired or abandoned one in
+	// place, in a single statement so that concurrent retries cannot
+	// both claim it.
+	result := r.BaseRepository.GetDB().WithContext(ctx).Exec(
+		`INSERT INTO idempotency_keys
+			(client, key, fingerprint, claim_id, created_at, locked_until,
+			expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (client, key) DO UPDATE SET
+			fingerprint = EXCLUDED.fingerprint,
+			claim_id = EXCLUDED.claim_id,
+			status_code = 0,
+			content_type = '',
+			body = NULL,
+			created_at = EXCLUDED.created_at,
+			completed_at = NULL,
+			locked_until = EXCLUDED.locked_until,
+			expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= EXCLUDED.created_at
+			OR (idempotency_keys.completed_at IS NULL
+				AND idempotency_keys.locked_until <= EXCLUDED.created_at
+				AND idempotency_keys.fingerprint = EXCLUDED.fingerprint)`,
+		key.Client,
+		key.Key,
+		key.Fingerprint,
+		key.ClaimID,
+		key.CreatedAt,
+		key.LockedUntil,
+		key.ExpiresAt,
+	)
+	if result.Error != nil {
+		return nil, false, fmt.Errorf(
+			"%w: %v",
+			customErrors.ErrDBQuery,
+			result.Error,
+		)
+	}
+	if result.RowsAffected > 0 {
+		return key, true, nil
+	}
+
+	var stored domain.IdempotencyKey
+	if err := r.BaseRepository.GetDB().WithContext(ctx).
+		Where("client = ? AND key = ?", key.Client, key.Key).
+		First(&stored).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Deleted by the cleanup since the insert; the key was in
+			// use until a moment ago, so the client retries.
+			return nil, false, customErrors.ErrIdempotencyKeyInUse
+		}
+		return nil, false, fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+	}
+
+	return &stored, false, nil
+}
+
+func (r *IdempotencyRepositoryImpl) Complete(
+	ctx context.Context,
+	key *domain.IdempotencyKey,
+) error {
+	result := r.BaseRepository.GetDB().WithContext(ctx).
+		Model(&domain.IdempotencyKey{}).
+		Where(
+			"client = ? AND key = ? AND claim_id = ? AND completed_at IS NULL",
+			key.Client,
+			key.Key,
+			key.ClaimID,
+		).
+		Updates(map[string]interface{}{
+			"status_code":  key.StatusCode,
+			"content_type": key.ContentType,
+			"body":         key.Body,
+			"completed_at": key.CompletedAt,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return customErrors.ErrIdempotencyKeyNotFound
+	}
+
+	return nil
+}
+
+func (r *IdempotencyRepositoryImpl) Release(
+	ctx context.Context,
+	key *domain.IdempotencyKey,
+) error {
+	result := r.BaseRepository.GetDB().WithContext(ctx).
+		Where(
+			"client = ? AND key = ? AND claim_id = ? AND completed_at IS NULL",
+			key.Client,
+			key.Key,
+			key.ClaimID,
+		).
+		Delete(&domain.IdempotencyKey{})
+	if result.Error != nil {
+		return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return customErrors.ErrIdempotencyKeyNotFound
+	}
+
+	return nil
+}
+
+func (r *IdempotencyRepositoryImpl) DeleteExpired(
+	ctx context.Context,
+	now time.Time,
+) (int64, error) {
+	result := r.BaseRepository.GetDB().WithContext(ctx).
+		Where("expires_at <= ?", now).
+		Delete(&domain.IdempotencyKey{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("%w: %v", customErrors.ErrDBQuery, result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
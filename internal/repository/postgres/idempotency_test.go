@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grocery-service/internal/domain"
+	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyRepositoryImpl(t *testing.T) {
+	postgres := setupTestDB(t, &domain.IdempotencyKey{})
+	repo := NewIdempotencyRepository(postgres)
+	ctx := context.Background()
+
+	newKey := func(
+		key, fingerprint string,
+		now time.Time,
+	) *domain.IdempotencyKey {
+		return &domain.IdempotencyKey{
+			Client:      "user:1",
+			Key:         key,
+			Fingerprint: fingerprint,
+			ClaimID:     uuid.New(),
+			CreatedAt:   now,
+			LockedUntil: now.Add(time.Minute),
+			ExpiresAt:   now.Add(time.Hour),
+		}
+	}
+
+	t.Run("Claim, Complete and replay", func(t *testing.T) {
+		now := time.Now()
+
+		first := newKey("order-1", "a", now)
+		_, claimed, err := repo.Claim(ctx, first)
+		require.NoError(t, err)
+		assert.True(t, claimed)
+
+		stored, claimed, err := repo.Claim(ctx, newKey("order-1", "b", now))
+		require.NoError(t, err)
+		assert.False(t, claimed)
+		assert.Equal(t, "a", stored.Fingerprint)
+		assert.False(t, stored.Completed())
+
+		completedAt := now.Add(time.Second)
+		first.StatusCode = 201
+		first.ContentType = "application/json"
+		first.Body = []byte(`{"success":true}`)
+		first.CompletedAt = &completedAt
+		require.NoError(t, repo.Complete(ctx, first))
+
+		stored, claimed, err = repo.Claim(ctx, newKey("order-1", "a", now))
+		require.NoError(t, err)
+		assert.False(t, claimed)
+		assert.True(t, stored.Completed())
+		assert.Equal(t, 201, stored.StatusCode)
+		assert.Equal(t, "application/json", stored.ContentType)
+		assert.Equal(t, `{"success":true}`, string(stored.Body))
+
+		err = repo.Complete(ctx, stored)
+		assert.ErrorIs(t, err, customErrors.ErrIdempotencyKeyNotFound)
+	})
+
+	t.Run("Expired keys are replaced", func(t *testing.T) {
+		now := time.Now()
+
+		_, claimed, err := repo.Claim(ctx, newKey("order-2", "a", now))
+		require.NoError(t, err)
+		require.True(t, claimed)
+
+		stored, claimed, err := repo.Claim(
+			ctx,
+			newKey("order-2", "b", now.Add(2*time.Hour)),
+		)
+		require.NoError(t, err)
+		assert.True(t, claimed)
+		assert.Equal(t, "b", stored.Fingerprint)
+	})
+
+	t.Run("Abandoned keys are taken over once unlocked", func(t *testing.T) {
+		now := time.Now()
+
+		abandoned := newKey("order-5", "a", now)
+		_, claimed, err := repo.Claim(ctx, abandoned)
+		require.NoError(t, err)
+		require.True(t, claimed)
+
+		_, claimed, err = repo.Claim(
+			ctx,
+			newKey("order-5", "a", now.Add(30*time.Second)),
+		)
+		require.NoError(t, err)
+		assert.False(t, claimed)
+
+		stored, claimed, err := repo.Claim(
+			ctx,
+			newKey("order-5", "b", now.Add(2*time.Minute)),
+		)
+		require.NoError(t, err)
+		assert.False(t, claimed)
+		assert.Equal(t, "a", stored.Fingerprint)
+
+		later := now.Add(2 * time.Minute)
+		stored, claimed, err = repo.Claim(ctx, newKey("order-5", "a", later))
+		require.NoError(t, err)
+		assert.True(t, claimed)
+		assert.WithinDuration(t, later.Add(time.Minute), stored.LockedUntil, 0)
+
+		// The first request finishing late cannot touch the retry's claim
+		completedAt := later
+		abandoned.CompletedAt = &completedAt
+		err = repo.Complete(ctx, abandoned)
+		assert.ErrorIs(t, err, customErrors.ErrIdempotencyKeyNotFound)
+		err = repo.Release(ctx, abandoned)
+		assert.ErrorIs(t, err, customErrors.ErrIdempotencyKeyNotFound)
+
+		require.NoError(t, repo.Release(ctx, stored))
+	})
+
+	t.Run("Release frees a key in progress", func(t *testing.T) {
+		now := time.Now()
+
+		first := newKey("order-3", "a", now)
+		_, _, err := repo.Claim(ctx, first)
+		require.NoError(t, err)
+		require.NoError(t, repo.Release(ctx, first))
+
+		second := newKey("order-3", "b", now)
+		_, claimed, err := repo.Claim(ctx, second)
+		require.NoError(t, err)
+		assert.True(t, claimed)
+
+		other := *second
+		other.Client = "user:2"
+		err = repo.Release(ctx, &other)
+		assert.ErrorIs(t, err, customErrors.ErrIdempotencyKeyNotFound)
+	})
+
+	t.Run("DeleteExpired", func(t *testing.T) {
+		now := time.Now()
+
+		_, _, err := repo.Claim(ctx, newKey("order-4", "a", now))
+		require.NoError(t, err)
+
+		deleted, err := repo.DeleteExpired(ctx, now)
+		require.NoError(t, err)
+		assert.Zero(t, deleted)
+
+		deleted, err = repo.DeleteExpired(ctx, now.Add(3*time.Hour))
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, deleted, int64(1))
+	})
+}
@@ -0,0 +1,200 @@
+package postgres
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/google/uuid"
+	"github.com/grocery-service/internal/domain"
+	customErrors "github.com/grocery-service/utils/errors"
+	"gorm.io/gorm"
+)
+
+type (
+	// sortField maps a public sort key to its column and extracts the
+	// value of that column from a row so it can be encoded in a cursor.
+	sortField[T any] struct {
+		column string
+		value  func(T) any
+	}
+
+	// listSpec describes how an entity is listed.
+	listSpec[T any] struct {
+		table       string
+		defaultSort string
+		sortFields  map[string]sortField[T]
+		id          func(T) uuid.UUID
+		preloads    []string
+	}
+
+	listCursor struct {
+		Value json.RawMessage `json:"v"`
+		ID    uuid.UUID       `json:"id"`
+	}
+)
+
+// listPage runs a filtered query as a single page. The query must
+// already carry its model and filters. Rows are always ordered by the
+// sort column with the primary key as tie breaker, which keeps both
+// offset and keyset pages stable.
+func listPage[T any](
+	query *gorm.DB,
+	q domain.ListQuery,
+	spec listSpec[T],
+) (*domain.Page[T], error) {
+	if err := q.Normalize(); err != nil {
+		return nil, fmt.Errorf(
+			"%w: %v",
+			customErrors.ErrInvalidListQuery,
+			err,
+		)
+	}
+
+	sortKey := q.SortBy
+	if sortKey == "" {
+		sortKey = spec.defaultSort
+	}
+	field, ok := spec.sortFields[sortKey]
+	if !ok {
+		return nil, fmt.Errorf(
+			"%w: unsupported sort field %q",
+			customErrors.ErrInvalidListQuery,
+			sortKey,
+		)
+	}
+
+	var (
+		cursorValue any
+		cursorID    uuid.UUID
+	)
+	if q.Cursor != "" {
+		var err error
+		cursorValue, cursorID, err = decodeCursor(q.Cursor, field)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	base := query.Session(&gorm.Session{})
+
+	var total int64
+	if err := base.Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+	}
+
+	direction, operator := "ASC", ">"
+	if q.SortDir == domain.SortDesc {
+		direction, operator = "DESC", "<"
+	}
+	idColumn := spec.table + ".id"
+
+	find := base.
+		Order(fmt.Sprintf(
+			"%s %s, %s %s",
+			field.column,
+			direction,
+			idColumn,
+			direction,
+		)).
+		Limit(q.Limit + 1)
+
+	if q.Cursor != "" {
+		find = find.Where(
+			fmt.Sprintf("(%s, %s) %s (?, ?)", field.column, idColumn, operator),
+			cursorValue,
+			cursorID,
+		)
+	} else {
+		find = find.Offset(q.Offset())
+	}
+
+	for _, preload := range spec.preloads {
+		find = find.Preload(preload)
+	}
+
+	items := []T{}
+	if err := find.Find(&items).Error; err != nil {
+		return nil, fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+	}
+
+	page := &domain.Page[T]{
+		Total: total,
+		Limit: q.Limit,
+		Page:  q.Page,
+	}
+
+	if len(items) > q.Limit {
+		items = items[:q.Limit]
+		last := items[len(items)-1]
+		cursor, err := encodeCursor(field.value(last), spec.id(last))
+		if err != nil {
+			return nil, err
+		}
+		page.NextCursor = cursor
+	}
+	page.Items = items
+
+	return page, nil
+}
+
+// applyCreatedRange restricts query to rows created inside the filter's
+// date range.
+func applyCreatedRange(
+	query *gorm.DB,
+	table string,
+	filter domain.ListFilter,
+) *gorm.DB {
+	if filter.CreatedFrom != nil {
+		query = query.Where(table+".created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		query = query.Where(table+".created_at <= ?", *filter.CreatedTo)
+	}
+	return query
+}
+
+func encodeCursor(value any, id uuid.UUID) (string, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+
+	data, err := json.Marshal(listCursor{Value: raw, ID: id})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor restores the sort value with the same Go type the sort
+// field produces, so it binds to the column like the original value.
+func decodeCursor[T any](
+	cursor string,
+	field sortField[T],
+) (any, uuid.UUID, error) {
+	invalid := fmt.Errorf(
+		"%w: malformed cursor",
+		customErrors.ErrInvalidListQuery,
+	)
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, uuid.Nil, invalid
+	}
+
+	var c listCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == uuid.Nil {
+		return nil, uuid.Nil, invalid
+	}
+
+	var zero T
+	value := reflect.New(reflect.TypeOf(field.value(zero)))
+	if err := json.Unmarshal(c.Value, value.Interface()); err != nil {
+		return nil, uuid.Nil, invalid
+	}
+
+	return value.Elem().Interface(), c.ID, nil
+}
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/grocery-service/internal/repository/db"
+	customErrors "github.com/grocery-service/utils/errors"
+	"gorm.io/gorm"
+)
+
+type (
+	// LockRepository hands out Postgres advisory locks, which lets one
+	// replica out of many run a job at a time.
+	LockRepository interface {
+		// TryLock takes the session-level advisory lock key without
+		// waiting. It reports false when another session holds it. The
+		// returned unlock func must be called to release the lock.
+		TryLock(ctx context.Context, key int64) (
+			unlock func(),
+			acquired bool,
+			err error,
+		)
+	}
+
+	LockRepositoryImpl struct {
+		db *gorm.DB
+	}
+)
+
+func NewLockRepository(postgres *db.PostgresDB) *LockRepositoryImpl {
+	return &LockRepositoryImpl{db: postgres.DB}
+}
+
+func (r *LockRepositoryImpl) TryLock(
+	ctx context.Context,
+	key int64,
+) (func(), bool, error) {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+	}
+
+	// A session-level lock belongs to one connection, so the connection
+	// is taken out of the pool until the lock is released.
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(
+		ctx,
+		"SELECT pg_try_advisory_lock($1)",
+		key,
+	).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, false, fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+	}
+
+	if !acquired {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	unlock := func() {
+		// The caller's context may already be cancelled at shutdown.
+		if _, err := conn.ExecContext(
+			context.Background(),
+			"SELECT pg_advisory_unlock($1)",
+			key,
+		); err != nil {
+			// Drop the connection instead of pooling it with the lock
+			// still held; closing the session releases the lock.
+			_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+		conn.Close()
+	}
+
+	return unlock, true, nil
+}
@@ -0,0 +1,33 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockRepositoryImpl(t *testing.T) {
+	postgres := setupTestDB(t)
+	repo := NewLockRepository(postgres)
+	ctx := context.Background()
+	const key int64 = 42
+
+	unlock, acquired, err := repo.TryLock(ctx, key)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	// The lock is held on its own connection, so a second attempt runs
+	// in another session and fails.
+	_, acquired, err = repo.TryLock(ctx, key)
+	require.NoError(t, err)
+	assert.False(t, acquired)
+
+	unlock()
+
+	unlock, acquired, err = repo.TryLock(ctx, key)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+	unlock()
+}
@@ -0,0 +1,287 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grocery-service/internal/domain"
+	"github.com/grocery-service/internal/repository/db"
+	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/grocery-service/utils/hash"
+	"gorm.io/gorm"
+)
+
+type (
+	// MFARepository stores the MFA settings of users and their recovery
+	// codes. Recovery codes are kept only as an HMAC keyed with hashKey,
+	// like tokens.
+	MFARepository interface {
+		// Enable stores the encrypted TOTP secret of a user and
+		// replaces their recovery codes. It fails with
+		// ErrMFAAlreadyEnabled when MFA is already enabled.
+		Enable(
+			ctx context.Context,
+			userID string,
+			secret string,
+			recoveryCodes []string,
+		) error
+		// Disable removes the user's secret and recovery codes.
+		Disable(ctx context.Context, userID string) error
+		SetRequired(ctx context.Context, userID string, required bool) error
+		// UseTOTPStep records that a code of time step was accepted. It
+		// fails with ErrInvalidMFACode unless step is later than the
+		// last step recorded, so that a code cannot be replayed.
+		UseTOTPStep(ctx context.Context, userID string, step int64) error
+		ReplaceRecoveryCodes(
+			ctx context.Context,
+			userID string,
+			recoveryCodes []string,
+		) error
+		// UseRecoveryCode marks an unused recovery code of the user as
+		// used. It fails with ErrInvalidMFACode when there is none.
+		UseRecoveryCode(ctx context.Context, userID, code string) error
+		// CountRecoveryCodes counts the user's unused recovery codes.
+		CountRecoveryCodes(ctx context.Context, userID string) (int, error)
+	}
+
+	MFARepositoryImpl struct {
+		*db.BaseRepository[domain.MFARecoveryCode]
+		hashKey []byte
+	}
+)
+
+func NewMFARepository(
+	postgres *db.PostgresDB,
+	hashKey string,
+) *MFARepositoryImpl {
+	return &MFARepositoryImpl{
+		BaseRepository: db.NewBaseRepository[domain.MFARecoveryCode](
+			postgres,
+		),
+		hashKey: []byte(hashKey),
+	}
+}
+
+func (r *MFARepositoryImpl) Enable(
+	ctx context.Context,
+	userID string,
+	secret string,
+	recoveryCodes []string,
+) error {
+	return r.BaseRepository.WithTransaction(
+		ctx,
+		func(txRepo *db.BaseRepository[domain.MFARecoveryCode]) error {
+			tx := txRepo.GetDB().WithContext(ctx)
+
+			result := tx.Model(&domain.User{}).
+				Where("id = ? AND mfa_enabled_at IS NULL", userID).
+				Updates(map[string]interface{}{
+					"mfa_secret":     secret,
+					"mfa_enabled_at": time.Now(),
+					"updated_at":     time.Now(),
+				})
+			if result.Error != nil {
+				return fmt.Errorf(
+					"%w: %v",
+					customErrors.ErrDBQuery,
+					result.Error,
+				)
+			}
+			if result.RowsAffected == 0 {
+				return r.userMissingOr(
+					tx,
+					userID,
+					customErrors.ErrMFAAlreadyEnabled,
+				)
+			}
+
+			return r.replaceCodes(tx, userID, recoveryCodes)
+		},
+	)
+}
+
+func (r *MFARepositoryImpl) Disable(
+	ctx context.Context,
+	userID string,
+) error {
+	return r.BaseRepository.WithTransaction(
+		ctx,
+		func(txRepo *db.BaseRepository[domain.MFARecoveryCode]) error {
+			tx := txRepo.GetDB().WithContext(ctx)
+
+			result := tx.Model(&domain.User{}).
+				Where("id = ?", userID).
+				Updates(map[string]interface{}{
+					"mfa_secret":     nil,
+					"mfa_enabled_at": nil,
+					"mfa_last_step":  nil,
+					"updated_at":     time.Now(),
+				})
+			if result.Error != nil {
+				return fmt.Errorf(
+					"%w: %v",
+					customErrors.ErrDBQuery,
+					result.Error,
+				)
+			}
+			if result.RowsAffected == 0 {
+				return customErrors.ErrUserNotFound
+			}
+
+			return r.replaceCodes(tx, userID, nil)
+		},
+	)
+}
+
+func (r *MFARepositoryImpl) SetRequired(
+	ctx context.Context,
+	userID string,
+	required bool,
+) error {
+	result := r.BaseRepository.GetDB().WithContext(ctx).
+		Model(&domain.User{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"mfa_required": required,
+			"updated_at":   time.Now(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return customErrors.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *MFARepositoryImpl) UseTOTPStep(
+	ctx context.Context,
+	userID string,
+	step int64,
+) error {
+	// A single statement keeps two requests from both accepting a code
+	result := r.BaseRepository.GetDB().WithContext(ctx).
+		Model(&domain.User{}).
+		Where(
+			"id = ? AND (mfa_last_step IS NULL OR mfa_last_step < ?)",
+			userID,
+			step,
+		).
+		Update("mfa_last_step", step)
+	if result.Error != nil {
+		return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return customErrors.ErrInvalidMFACode
+	}
+	return nil
+}
+
+func (r *MFARepositoryImpl) ReplaceRecoveryCodes(
+	ctx context.Context,
+	userID string,
+	recoveryCodes []string,
+) error {
+	return r.BaseRepository.WithTransaction(
+		ctx,
+		func(txRepo *db.BaseRepository[domain.MFARecoveryCode]) error {
+			return r.replaceCodes(
+				txRepo.GetDB().WithContext(ctx),
+				userID,
+				recoveryCodes,
+			)
+		},
+	)
+}
+
+func (r *MFARepositoryImpl) UseRecoveryCode(
+	ctx context.Context,
+	userID, code string,
+) error {
+	result := r.BaseRepository.GetDB().WithContext(ctx).
+		Model(&domain.MFARecoveryCode{}).
+		Where(
+			"user_id = ? AND code_hash = ? AND used_at IS NULL",
+			userID,
+			hash.Token(r.hashKey, code),
+		).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return customErrors.ErrInvalidMFACode
+	}
+	return nil
+}
+
+func (r *MFARepositoryImpl) CountRecoveryCodes(
+	ctx context.Context,
+	userID string,
+) (int, error) {
+	var count int64
+	if err := r.BaseRepository.GetDB().WithContext(ctx).
+		Model(&domain.MFARecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+	}
+	return int(count), nil
+}
+
+// replaceCodes deletes every recovery code of the user and stores the
+// hashes of codes.
+func (r *MFARepositoryImpl) replaceCodes(
+	tx *gorm.DB,
+	userID string,
+	codes []string,
+) error {
+	if err := tx.Where("user_id = ?", userID).
+		Delete(&domain.MFARecoveryCode{}).Error; err != nil {
+		return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+	}
+
+	if len(codes) == 0 {
+		return nil
+	}
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return customErrors.ErrUserNotFound
+	}
+
+	rows := make([]domain.MFARecoveryCode, 0, len(codes))
+	for _, code := range codes {
+		rows = append(rows, domain.MFARecoveryCode{
+			UserID:    id,
+			CodeHash:  hash.Token(r.hashKey, code),
+			CreatedAt: time.Now(),
+		})
+	}
+	if err := tx.Create(&rows).Error; err != nil {
+		return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+	}
+	return nil
+}
+
+// userMissingOr returns ErrUserNotFound when there is no user userID,
+// and err otherwise.
+func (r *MFARepositoryImpl) userMissingOr(
+	tx *gorm.DB,
+	userID string,
+	err error,
+) error {
+	var exists bool
+	if qErr := tx.Model(&domain.User{}).
+		Select("count(*) > 0").
+		Where("id = ?", userID).
+		Find(&exists).Error; qErr != nil {
+		return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, qErr)
+	}
+	if !exists {
+		return customErrors.ErrUserNotFound
+	}
+	return err
+}
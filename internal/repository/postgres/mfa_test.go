@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/grocery-service/internal/domain"
+	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMFARepositoryImpl(t *testing.T) {
+	postgres := setupTestDB(t, &domain.MFARecoveryCode{}, &domain.User{})
+	repo := NewMFARepository(postgres, "test-hash-key")
+	users := NewUserRepository(postgres)
+	ctx := context.Background()
+
+	t.Run("Enable and Disable", func(t *testing.T) {
+		user := createTestUser(t, postgres.DB)
+		userID := user.ID.String()
+
+		require.NoError(t, repo.Enable(
+			ctx,
+			userID,
+			"sealed-secret",
+			[]string{"code1", "code2"},
+		))
+
+		retrieved, err := users.GetByID(ctx, userID)
+		require.NoError(t, err)
+		assert.True(t, retrieved.MFAEnabled())
+		assert.Equal(t, "sealed-secret", retrieved.MFASecret)
+
+		count, err := repo.CountRecoveryCodes(ctx, userID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		err = repo.Enable(ctx, userID, "other-secret", nil)
+		assert.ErrorIs(t, err, customErrors.ErrMFAAlreadyEnabled)
+
+		require.NoError(t, repo.Disable(ctx, userID))
+
+		retrieved, err = users.GetByID(ctx, userID)
+		require.NoError(t, err)
+		assert.False(t, retrieved.MFAEnabled())
+		assert.Empty(t, retrieved.MFASecret)
+
+		count, err = repo.CountRecoveryCodes(ctx, userID)
+		require.NoError(t, err)
+		assert.Zero(t, count)
+
+		err = repo.Enable(ctx, uuid.NewString(), "sealed-secret", nil)
+		assert.ErrorIs(t, err, customErrors.ErrUserNotFound)
+	})
+
+	t.Run("UseTOTPStep refuses replays", func(t *testing.T) {
+		user := createTestUser(t, postgres.DB)
+		userID := user.ID.String()
+
+		require.NoError(t, repo.UseTOTPStep(ctx, userID, 100))
+
+		err := repo.UseTOTPStep(ctx, userID, 100)
+		assert.ErrorIs(t, err, customErrors.ErrInvalidMFACode)
+
+		err = repo.UseTOTPStep(ctx, userID, 99)
+		assert.ErrorIs(t, err, customErrors.ErrInvalidMFACode)
+
+		assert.NoError(t, repo.UseTOTPStep(ctx, userID, 101))
+	})
+
+	t.Run("Recovery codes are single use", func(t *testing.T) {
+		user := createTestUser(t, postgres.DB)
+		userID := user.ID.String()
+
+		require.NoError(t, repo.ReplaceRecoveryCodes(
+			ctx,
+			userID,
+			[]string{"code1", "code2"},
+		))
+
+		require.NoError(t, repo.UseRecoveryCode(ctx, userID, "code1"))
+
+		err := repo.UseRecoveryCode(ctx, userID, "code1")
+		assert.ErrorIs(t, err, customErrors.ErrInvalidMFACode)
+
+		err = repo.UseRecoveryCode(ctx, uuid.NewString(), "code2")
+		assert.ErrorIs(t, err, customErrors.ErrInvalidMFACode)
+
+		count, err := repo.CountRecoveryCodes(ctx, userID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		require.NoError(t, repo.ReplaceRecoveryCodes(
+			ctx,
+			userID,
+			[]string{"code3"},
+		))
+
+		err = repo.UseRecoveryCode(ctx, userID, "code2")
+		assert.ErrorIs(t, err, customErrors.ErrInvalidMFACode)
+		assert.NoError(t, repo.UseRecoveryCode(ctx, userID, "code3"))
+	})
+
+	t.Run("SetRequired", func(t *testing.T) {
+		user := createTestUser(t, postgres.DB)
+
+		require.NoError(t, repo.SetRequired(ctx, user.ID.String(), true))
+
+		retrieved, err := users.GetByID(ctx, user.ID.String())
+		require.NoError(t, err)
+		assert.True(t, retrieved.MFARequired)
+
+		err = repo.SetRequired(ctx, uuid.NewString(), true)
+		assert.ErrorIs(t, err, customErrors.ErrUserNotFound)
+	})
+}
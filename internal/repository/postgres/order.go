@@ -4,44 +4,64 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 
+	"github.com/google/uuid"
 	"github.com/grocery-service/internal/domain"
 	"github.com/grocery-service/internal/repository/db"
 	customErrors "github.com/grocery-service/utils/errors"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type (
 	OrderRepository interface {
+		// Create stores the order and records events in the same
+		// transaction, as does Checkout.
 		Create(
 			ctx context.Context,
 			order *domain.Order,
-			updateStockFunc func(ctx context.Context, productID string, newStock int) error,
+			events ...*domain.OutboxEvent,
+		) error
+		Checkout(
+			ctx context.Context,
+			order *domain.Order,
+			cartID string,
+			events ...*domain.OutboxEvent,
 		) error
 		GetByID(ctx context.Context, id string) (*domain.Order, error)
-		List(ctx context.Context) ([]domain.Order, error)
+		List(
+			ctx context.Context,
+			query domain.ListQuery,
+		) (*domain.Page[domain.Order], error)
 		ListByCustomerID(
 			ctx context.Context,
 			customerID string,
-		) ([]domain.Order, error)
+			query domain.ListQuery,
+		) (*domain.Page[domain.Order], error)
 		Update(ctx context.Context, order *domain.Order) error
+		// UpdateStatus stores a transition in one transaction: the new
+		// order status, its status history entry, the stock it returns
+		// and its outbox events. It fails with ErrOrderStatusInvalid if
+		// the order is no longer in transition.From.
 		UpdateStatus(
 			ctx context.Context,
-			id string,
-			status domain.OrderStatus,
+			transition *domain.OrderTransition,
 		) error
-		AddOrderItem(
+		ListStatusHistory(
 			ctx context.Context,
-			orderID string, item *domain.OrderItem,
-			updateStockFunc func(ctx context.Context, productID string, newStock int) error,
-			updateOrderTotalFunc func(ctx context.Context, order *domain.Order, price float64) error,
-		) error
-		RemoveOrderItem(
+			orderID string,
+		) ([]domain.OrderStatusChange, error)
+		// AddOrderItem reserves stock for item and adds it to the pending
+		// order in one transaction. RemoveOrderItem returns the item's
+		// stock the same way. Both record the stock movement and keep the
+		// order total in step.
+		AddOrderItem(
 			ctx context.Context,
-			orderID, itemID string,
-			restoreStockFunc func(ctx context.Context, productID string, quantity int) error,
-			updateOrderTotalFunc func(ctx context.Context, order *domain.Order, price float64) error,
+			orderID string,
+			item *domain.OrderItem,
 		) error
+		RemoveOrderItem(ctx context.Context, orderID, itemID string) error
 	}
 
 	OrderRepositoryImpl struct {
@@ -49,6 +69,31 @@ type (
 	}
 )
 
+var orderListSpec = listSpec[domain.Order]{
+	table:       "orders",
+	defaultSort: "created_at",
+	sortFields: map[string]sortField[domain.Order]{
+		"created_at": {
+			column: "orders.created_at",
+			value:  func(o domain.Order) any { return o.CreatedAt },
+		},
+		"updated_at": {
+			column: "orders.updated_at",
+			value:  func(o domain.Order) any { return o.UpdatedAt },
+		},
+		"total_price": {
+			column: "orders.total_price",
+			value:  func(o domain.Order) any { return o.TotalPrice },
+		},
+		"status": {
+			column: "orders.status",
+			value:  func(o domain.Order) any { return string(o.Status) },
+		},
+	},
+	id:       func(o domain.Order) uuid.UUID { return o.ID },
+	preloads: []string{"Items", "Items.Product"},
+}
+
 func NewOrderRepository(
 	postgres *db.PostgresDB,
 ) *OrderRepositoryImpl {
@@ -62,32 +107,219 @@ func NewOrderRepository(
 func (r *OrderRepositoryImpl) Create(
 	ctx context.Context,
 	order *domain.Order,
-	updateStockFunc func(ctx context.Context, productID string, newStock int) error,
+	events ...*domain.OutboxEvent,
 ) error {
 	return r.BaseRepository.WithTransaction(
 		ctx,
 		func(txRepo *db.BaseRepository[domain.Order]) error {
-			for _, item := range order.Items {
-				if err := updateStockFunc(ctx, item.ProductID.String(), item.Quantity); err != nil {
-					return fmt.Errorf(
-						"failed to update product stock: %w",
-						err,
-					)
-				}
+			return createOrder(ctx, txRepo.GetDB(), order, events)
+		},
+	)
+}
+
+// Checkout creates order and empties the cart it was built from in one
+// transaction, so a checkout either produces an order and an empty cart
+// or leaves both untouched.
+func (r *OrderRepositoryImpl) Checkout(
+	ctx context.Context,
+	order *domain.Order,
+	cartID string,
+	events ...*domain.OutboxEvent,
+) error {
+	return r.BaseRepository.WithTransaction(
+		ctx,
+		func(txRepo *db.BaseRepository[domain.Order]) error {
+			if err := createOrder(ctx, txRepo.GetDB(), order, events); err != nil {
+				return err
 			}
 
-			if err := txRepo.GetDB().WithContext(ctx).Create(order).Error; err != nil {
-				return fmt.Errorf(
-					"%w: %v",
-					customErrors.ErrInvalidOrderData,
-					err,
-				)
+			if err := txRepo.GetDB().WithContext(ctx).
+				Where("cart_id = ?", cartID).
+				Delete(&domain.CartItem{}).Error; err != nil {
+				return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
 			}
 			return nil
 		},
 	)
 }
 
+func createOrder(
+	ctx context.Context,
+	tx *gorm.DB,
+	order *domain.Order,
+	events []*domain.OutboxEvent,
+) error {
+	sales, alerts, err := reserveStock(ctx, tx, order)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Create(order).Error; err != nil {
+		return fmt.Errorf(
+			"%w: %v",
+			customErrors.ErrInvalidOrderData,
+			err,
+		)
+	}
+
+	if err := recordStockMovements(ctx, tx, sales); err != nil {
+		return err
+	}
+
+	return insertOutboxEvents(ctx, tx, slices.Concat(events, alerts))
+}
+
+// reserveStock locks the products referenced by the order's items and
+// decrements their stock within tx. Rows are locked in ID order so
+// concurrent orders touching the same products cannot deadlock. If any
+// product cannot cover its requested quantity nothing is decremented and
+// an *InsufficientStockError listing every shortfall is returned. Items
+// must carry the locked product's current price; otherwise the order is
+// refused with ErrOrderPriceChanged so the caller is never charged a price
+// it did not see. The SALE movements returned must be recorded once the
+// order exists, along with the low-stock events of products that reached
+// their reorder point.
+func reserveStock(
+	ctx context.Context,
+	tx *gorm.DB,
+	order *domain.Order,
+) ([]*domain.StockMovement, []*domain.OutboxEvent, error) {
+	requested := make(map[uuid.UUID]int, len(order.Items))
+	for _, item := range order.Items {
+		requested[item.ProductID] += item.Quantity
+	}
+
+	ids := make([]uuid.UUID, 0, len(requested))
+	for id := range requested {
+		ids = append(ids, id)
+	}
+
+	var products []domain.Product
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", ids).
+		Order("id").
+		Find(&products).Error; err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+	}
+
+	if len(products) != len(ids) {
+		return nil, nil, customErrors.ErrProductNotFound
+	}
+
+	prices := make(map[uuid.UUID]domain.Money, len(products))
+	for _, product := range products {
+		prices[product.ID] = product.Price
+	}
+
+	for _, item := range order.Items {
+		if !item.Price.Equal(prices[item.ProductID]) {
+			return nil, nil, customErrors.ErrOrderPriceChanged
+		}
+	}
+
+	var shortfalls []customErrors.StockShortfall
+	for _, product := range products {
+		if product.Stock < requested[product.ID] {
+			shortfalls = append(shortfalls, customErrors.StockShortfall{
+				ProductID: product.ID.String(),
+				Requested: requested[product.ID],
+				Available: product.Stock,
+			})
+		}
+	}
+
+	if len(shortfalls) > 0 {
+		return nil, nil, &customErrors.InsufficientStockError{
+			Shortfalls: shortfalls,
+		}
+	}
+
+	sales := make([]*domain.StockMovement, 0, len(products))
+	var alerts []*domain.OutboxEvent
+	for _, product := range products {
+		quantity := requested[product.ID]
+		result := tx.WithContext(ctx).
+			Model(&domain.Product{}).
+			Where("id = ? AND stock >= ?", product.ID, quantity).
+			Update("stock", gorm.Expr("stock - ?", quantity))
+		if result.Error != nil {
+			return nil, nil, fmt.Errorf(
+				"%w: %v",
+				customErrors.ErrDBQuery,
+				result.Error,
+			)
+		}
+
+		if result.RowsAffected == 0 {
+			return nil, nil, &customErrors.InsufficientStockError{
+				Shortfalls: []customErrors.StockShortfall{{
+					ProductID: product.ID.String(),
+					Requested: quantity,
+					Available: product.Stock,
+				}},
+			}
+		}
+
+		sales = append(sales, domain.NewOrderStockMovement(
+			product.ID,
+			domain.StockMovementSale,
+			-quantity,
+			order.ID,
+		))
+
+		events, err := lowStockEvents(&product, product.Stock-quantity)
+		if err != nil {
+			return nil, nil, err
+		}
+		alerts = append(alerts, events...)
+	}
+
+	return sales, alerts, nil
+}
+
+// restoreStock returns the adjusted quantities of the changed order to
+// their products within tx and records them as RETURN movements.
+// Products that no longer exist are skipped; there is nothing left to
+// restock.
+func restoreStock(
+	ctx context.Context,
+	tx *gorm.DB,
+	change *domain.OrderStatusChange,
+	adjustments []domain.StockAdjustment,
+) error {
+	returns := make([]*domain.StockMovement, 0, len(adjustments))
+	for _, adjustment := range adjustments {
+		result := tx.WithContext(ctx).
+			Model(&domain.Product{}).
+			Where("id = ?", adjustment.ProductID).
+			Update("stock", gorm.Expr("stock + ?", adjustment.Quantity))
+		if result.Error != nil {
+			return fmt.Errorf(
+				"%w: failed to restore stock: %v",
+				customErrors.ErrDBQuery,
+				result.Error,
+			)
+		}
+
+		if result.RowsAffected == 0 {
+			continue
+		}
+
+		movement := domain.NewOrderStockMovement(
+			adjustment.ProductID,
+			domain.StockMovementReturn,
+			adjustment.Quantity,
+			change.OrderID,
+		)
+		movement.ActorID = change.ActorID
+		movement.Note = fmt.Sprintf("order %s", change.ToStatus)
+		returns = append(returns, movement)
+	}
+
+	return recordStockMovements(ctx, tx, returns)
+}
+
 func (r *OrderRepositoryImpl) GetByID(
 	ctx context.Context,
 	id string,
@@ -113,41 +345,44 @@ func (r *OrderRepositoryImpl) GetByID(
 
 func (r *OrderRepositoryImpl) List(
 	ctx context.Context,
-) ([]domain.Order, error) {
-	var orders []domain.Order
-	err := r.BaseRepository.GetDB().WithContext(ctx).
-		Preload("Items").
-		Preload("Items.Product").
-		Find(&orders).Error
-	if err != nil {
-		return nil, fmt.Errorf(
-			"%w: %v",
-			customErrors.ErrDBQuery,
-			err,
-		)
+	query domain.ListQuery,
+) (*domain.Page[domain.Order], error) {
+	db := r.BaseRepository.GetDB().WithContext(ctx).
+		Model(&domain.Order{})
+
+	filter := query.Filter
+	if filter.CustomerID != nil {
+		db = db.Where("orders.customer_id = ?", *filter.CustomerID)
+	}
+	if filter.Status != "" {
+		db = db.Where("orders.status = ?", filter.Status)
 	}
-	return orders, nil
+	if filter.MinPrice != nil {
+		db = db.Where("orders.total_price >= ?", *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		db = db.Where("orders.total_price <= ?", *filter.MaxPrice)
+	}
+	db = applyCreatedRange(db, "orders", filter)
+
+	return listPage(db, query, orderListSpec)
 }
 
 func (r *OrderRepositoryImpl) ListByCustomerID(
 	ctx context.Context,
 	customerID string,
-) ([]domain.Order, error) {
-	var orders []domain.Order
-	err := r.BaseRepository.GetDB().WithContext(ctx).
-		Preload("Items").
-		Preload("Items.Product").
-		Where("customer_id = ?", customerID).
-		Find(&orders).Error
+	query domain.ListQuery,
+) (*domain.Page[domain.Order], error) {
+	id, err := uuid.Parse(customerID)
 	if err != nil {
 		return nil, fmt.Errorf(
-			"%w: %v",
-			customErrors.ErrDBQuery,
-			err,
+			"%w: invalid customer ID",
+			customErrors.ErrInvalidOrderData,
 		)
 	}
 
-	return orders, nil
+	query.Filter.CustomerID = &id
+	return r.List(ctx, query)
 }
 
 func (r *OrderRepositoryImpl) Update(
@@ -179,16 +414,19 @@ func (r *OrderRepositoryImpl) Update(
 
 func (r *OrderRepositoryImpl) UpdateStatus(
 	ctx context.Context,
-	id string,
-	status domain.OrderStatus,
+	transition *domain.OrderTransition,
 ) error {
+	change := transition.Change
+
 	return r.BaseRepository.WithTransaction(
 		ctx,
 		func(txRepo *db.BaseRepository[domain.Order]) error {
+			// Matching on the previous status keeps two concurrent
+			// transitions from both being recorded.
 			result := txRepo.GetDB().WithContext(ctx).
 				Model(&domain.Order{}).
-				Where("id = ?", id).
-				Update("status", status)
+				Where("id = ? AND status = ?", change.OrderID, change.FromStatus).
+				Update("status", change.ToStatus)
 			if result.Error != nil {
 				return fmt.Errorf(
 					"%w: %v",
@@ -198,54 +436,108 @@ func (r *OrderRepositoryImpl) UpdateStatus(
 			}
 
 			if result.RowsAffected == 0 {
-				return customErrors.ErrOrderNotFound
+				var count int64
+				if err := txRepo.GetDB().WithContext(ctx).
+					Model(&domain.Order{}).
+					Where("id = ?", change.OrderID).
+					Count(&count).Error; err != nil {
+					return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+				}
+				if count == 0 {
+					return customErrors.ErrOrderNotFound
+				}
+				return fmt.Errorf(
+					"%w: order is no longer %s",
+					customErrors.ErrOrderStatusInvalid,
+					change.FromStatus,
+				)
 			}
-			return nil
+
+			if err := txRepo.GetDB().WithContext(ctx).
+				Create(change).Error; err != nil {
+				return fmt.Errorf(
+					"%w: failed to record status change: %v",
+					customErrors.ErrDBQuery,
+					err,
+				)
+			}
+
+			if err := restoreStock(
+				ctx,
+				txRepo.GetDB(),
+				change,
+				transition.Restock,
+			); err != nil {
+				return err
+			}
+
+			return insertOutboxEvents(
+				ctx,
+				txRepo.GetDB(),
+				transition.Events,
+			)
 		},
 	)
 }
 
+func (r *OrderRepositoryImpl) ListStatusHistory(
+	ctx context.Context,
+	orderID string,
+) ([]domain.OrderStatusChange, error) {
+	var history []domain.OrderStatusChange
+	if err := r.BaseRepository.GetDB().WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Order("created_at ASC").
+		Find(&history).Error; err != nil {
+		return nil, fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+	}
+	return history, nil
+}
+
 func (r *OrderRepositoryImpl) AddOrderItem(
 	ctx context.Context,
 	orderID string,
 	item *domain.OrderItem,
-	updateStockFunc func(ctx context.Context, productID string, newStock int) error,
-	updateOrderTotalFunc func(ctx context.Context, order *domain.Order, price float64) error,
 ) error {
 	return r.BaseRepository.WithTransaction(
 		ctx,
 		func(txRepo *db.BaseRepository[domain.Order]) error {
-			if err := updateStockFunc(ctx, item.ProductID.String(), item.Quantity); err != nil {
-				return fmt.Errorf(
-					"failed to update product stock: %w",
-					err,
-				)
+			order, err := lockPendingOrder(ctx, txRepo.GetDB(), orderID)
+			if err != nil {
+				return err
+			}
+
+			item.OrderID = order.ID
+			sales, alerts, err := reserveStock(ctx, txRepo.GetDB(), &domain.Order{
+				ID:    order.ID,
+				Items: []domain.OrderItem{*item},
+			})
+			if err != nil {
+				return err
 			}
 
 			if err := txRepo.GetDB().WithContext(ctx).Create(item).Error; err != nil {
 				return fmt.Errorf(
-					"failed to add order item: %w",
+					"%w: failed to add order item: %v",
+					customErrors.ErrDBQuery,
 					err,
 				)
 			}
 
-			var order domain.Order
-			if err := txRepo.GetDB().WithContext(ctx).
-				Preload("Items").
-				First(&order, "id = ?", orderID).Error; err != nil {
-				return fmt.Errorf(
-					"failed to get order: %w",
-					err,
-				)
+			if err := recordStockMovements(ctx, txRepo.GetDB(), sales); err != nil {
+				return err
 			}
 
-			if err := updateOrderTotalFunc(ctx, &order, item.Price*float64(item.Quantity)); err != nil {
-				return fmt.Errorf(
-					"failed to update order total: %w",
-					err,
-				)
+			if err := insertOutboxEvents(ctx, txRepo.GetDB(), alerts); err != nil {
+				return err
 			}
-			return nil
+
+			return updateOrderTotal(
+				ctx,
+				txRepo.GetDB(),
+				order,
+				item.Price.Mul(item.Quantity),
+			)
 		},
 	)
 }
@@ -253,70 +545,115 @@ func (r *OrderRepositoryImpl) AddOrderItem(
 func (r *OrderRepositoryImpl) RemoveOrderItem(
 	ctx context.Context,
 	orderID, itemID string,
-	restoreStockFunc func(ctx context.Context, productID string, quantity int) error,
-	updateOrderTotalFunc func(ctx context.Context, order *domain.Order, price float64) error,
 ) error {
 	return r.BaseRepository.WithTransaction(
 		ctx,
 		func(txRepo *db.BaseRepository[domain.Order]) error {
-			var order domain.Order
-			if err := txRepo.GetDB().WithContext(ctx).
-				Preload("Items").
-				First(&order, "id = ?", orderID).Error; err != nil {
-				if errors.Is(err, gorm.ErrRecordNotFound) {
-					return customErrors.ErrOrderNotFound
-				}
-
-				return fmt.Errorf(
-					"failed to get order: %w",
-					err,
-				)
+			order, err := lockPendingOrder(ctx, txRepo.GetDB(), orderID)
+			if err != nil {
+				return err
 			}
 
-			if order.Status != domain.OrderStatusPending {
-				return customErrors.ErrOrderStatusInvalid
-			}
-
-			var itemToRemove *domain.OrderItem
-			for _, item := range order.Items {
-				if item.ID.String() == itemID {
-					itemToRemove = &item
-					break
+			var item domain.OrderItem
+			if err := txRepo.GetDB().WithContext(ctx).
+				First(&item, "id = ? AND order_id = ?", itemID, order.ID).
+				Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return customErrors.ErrOrderItemNotFound
 				}
+				return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
 			}
 
-			if itemToRemove == nil {
-				return customErrors.ErrOrderItemNotFound
-			}
-
-			if err := restoreStockFunc(ctx, itemToRemove.ProductID.String(), itemToRemove.Quantity); err != nil {
+			if err := txRepo.GetDB().WithContext(ctx).
+				Delete(&item).Error; err != nil {
 				return fmt.Errorf(
-					"failed to restore product stock: %w",
+					"%w: failed to remove order item: %v",
+					customErrors.ErrDBQuery,
 					err,
 				)
 			}
 
 			result := txRepo.GetDB().WithContext(ctx).
-				Where("id = ?", itemID).
-				Delete(&domain.OrderItem{})
+				Model(&domain.Product{}).
+				Where("id = ?", item.ProductID).
+				Update("stock", gorm.Expr("stock + ?", item.Quantity))
 			if result.Error != nil {
 				return fmt.Errorf(
-					"failed to remove order item: %w",
+					"%w: failed to restore stock: %v",
+					customErrors.ErrDBQuery,
 					result.Error,
 				)
 			}
 
-			if err := updateOrderTotalFunc(
-				ctx,
-				&order,
-				-(itemToRemove.Price * float64(itemToRemove.Quantity)),
-			); err != nil {
-				return fmt.Errorf(
-					"failed to update order total: %w",
-					err,
-				)
+			// A product that no longer exists has nothing to restock.
+			if result.RowsAffected > 0 {
+				if err := recordStockMovements(
+					ctx,
+					txRepo.GetDB(),
+					[]*domain.StockMovement{domain.NewOrderStockMovement(
+						item.ProductID,
+						domain.StockMovementReturn,
+						item.Quantity,
+						order.ID,
+					)},
+				); err != nil {
+					return err
+				}
 			}
-			return nil
+
+			return updateOrderTotal(
+				ctx,
+				txRepo.GetDB(),
+				order,
+				item.Price.Mul(item.Quantity).Neg(),
+			)
 		},
 	)
 }
+
+// lockPendingOrder locks the order row within tx so concurrent item
+// changes to the same order apply one after another, and refuses orders
+// that are no longer pending.
+func lockPendingOrder(
+	ctx context.Context,
+	tx *gorm.DB,
+	orderID string,
+) (*domain.Order, error) {
+	var order domain.Order
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&order, "id = ?", orderID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, customErrors.ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+	}
+
+	if order.Status != domain.OrderStatusPending {
+		return nil, fmt.Errorf(
+			"%w: items can only change on pending orders",
+			customErrors.ErrOrderStatusInvalid,
+		)
+	}
+	return &order, nil
+}
+
+func updateOrderTotal(
+	ctx context.Context,
+	tx *gorm.DB,
+	order *domain.Order,
+	delta domain.Money,
+) error {
+	order.TotalPrice = order.TotalPrice.Add(delta)
+	if err := tx.WithContext(ctx).
+		Model(&domain.Order{}).
+		Where("id = ?", order.ID).
+		Update("total_price", order.TotalPrice).Error; err != nil {
+		return fmt.Errorf(
+			"%w: failed to update order total: %v",
+			customErrors.ErrDBQuery,
+			err,
+		)
+	}
+	return nil
+}
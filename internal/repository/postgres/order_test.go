@@ -2,6 +2,9 @@ package postgres
 
 import (
 	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/google/uuid"
@@ -15,15 +18,16 @@ import (
 func TestOrderRepository_Create(t *testing.T) {
 	tests := []struct {
 		name          string
-		setupTest     func(*gorm.DB) (*domain.Order, func(context.Context, string, int) error)
+		setupTest     func(*gorm.DB) *domain.Order
+		expectedStock int
 		expectedError error
 	}{
 		{
 			name: "Success - Create Order with Items",
-			setupTest: func(db *gorm.DB) (*domain.Order, func(context.Context, string, int) error) {
+			setupTest: func(db *gorm.DB) *domain.Order {
 				product := createTestProduct(t, db)
 				customer := createTestCustomer(t, db)
-				order := &domain.Order{
+				return &domain.Order{
 					ID:         uuid.New(),
 					CustomerID: customer.ID,
 					Status:     domain.OrderStatusPending,
@@ -36,13 +40,74 @@ func TestOrderRepository_Create(t *testing.T) {
 						},
 					},
 				}
-				updateStockFunc := func(_ context.Context, _ string, _ int) error {
-					return nil
-				}
-				return order, updateStockFunc
 			},
+			expectedStock: 98,
 			expectedError: nil,
 		},
+		{
+			name: "Error - Insufficient Stock",
+			setupTest: func(db *gorm.DB) *domain.Order {
+				product := createTestProduct(t, db)
+				customer := createTestCustomer(t, db)
+				return &domain.Order{
+					ID:         uuid.New(),
+					CustomerID: customer.ID,
+					Status:     domain.OrderStatusPending,
+					Items: []domain.OrderItem{
+						{
+							ID:        uuid.New(),
+							ProductID: product.ID,
+							Quantity:  101,
+							Price:     product.Price,
+						},
+					},
+				}
+			},
+			expectedStock: 100,
+			expectedError: customErrors.ErrInsufficientStock,
+		},
+		{
+			name: "Error - Price Changed",
+			setupTest: func(db *gorm.DB) *domain.Order {
+				product := createTestProduct(t, db)
+				customer := createTestCustomer(t, db)
+				return &domain.Order{
+					ID:         uuid.New(),
+					CustomerID: customer.ID,
+					Status:     domain.OrderStatusPending,
+					Items: []domain.OrderItem{
+						{
+							ID:        uuid.New(),
+							ProductID: product.ID,
+							Quantity:  2,
+							Price:     product.Price.Sub(domain.NewMoney(1, domain.DefaultCurrency)),
+						},
+					},
+				}
+			},
+			expectedStock: 100,
+			expectedError: customErrors.ErrOrderPriceChanged,
+		},
+		{
+			name: "Error - Product Not Found",
+			setupTest: func(db *gorm.DB) *domain.Order {
+				customer := createTestCustomer(t, db)
+				return &domain.Order{
+					ID:         uuid.New(),
+					CustomerID: customer.ID,
+					Status:     domain.OrderStatusPending,
+					Items: []domain.OrderItem{
+						{
+							ID:        uuid.New(),
+							ProductID: uuid.New(),
+							Quantity:  1,
+							Price:     domain.NewMoney(1000, domain.DefaultCurrency),
+						},
+					},
+				}
+			},
+			expectedError: customErrors.ErrProductNotFound,
+		},
 	}
 
 	for _, tt := range tests {
@@ -52,15 +117,21 @@ func TestOrderRepository_Create(t *testing.T) {
 				&domain.OrderItem{},
 				&domain.Order{},
 				&domain.Product{},
+				&domain.StockMovement{},
 			)
 			repo := NewOrderRepository(postgres)
 			ctx := context.Background()
 
-			order, updateStockFunc := tt.setupTest(postgres.DB)
-			err := repo.Create(ctx, order, updateStockFunc)
+			order := tt.setupTest(postgres.DB)
+			err := repo.Create(ctx, order)
 
 			if tt.expectedError != nil {
 				assert.ErrorIs(t, err, tt.expectedError)
+				var count int64
+				postgres.DB.Model(&domain.Order{}).
+					Where("id = ?", order.ID).
+					Count(&count)
+				assert.Zero(t, count)
 			} else {
 				assert.NoError(t, err)
 				var found domain.Order
@@ -70,10 +141,143 @@ func TestOrderRepository_Create(t *testing.T) {
 				assert.Equal(t, order.Status, found.Status)
 				assert.Len(t, found.Items, len(order.Items))
 			}
+
+			if tt.expectedStock != 0 {
+				var product domain.Product
+				err = postgres.DB.First(
+					&product,
+					"id = ?",
+					order.Items[0].ProductID,
+				).Error
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedStock, product.Stock)
+			}
 		})
 	}
 }
 
+func TestOrderRepository_Checkout(t *testing.T) {
+	postgres := setupTestDB(
+		t,
+		&domain.OrderItem{},
+		&domain.Order{},
+		&domain.CartItem{},
+		&domain.Cart{},
+		&domain.Product{},
+		&domain.StockMovement{},
+	)
+	repo := NewOrderRepository(postgres)
+	carts := NewCartRepository(postgres)
+	ctx := context.Background()
+
+	product := createTestProduct(t, postgres.DB)
+	customer := createTestCustomer(t, postgres.DB)
+	cart := domain.NewCart(customer.ID)
+	require.NoError(t, carts.Create(ctx, cart))
+	require.NoError(t, carts.AddItem(
+		ctx,
+		domain.NewCartItem(cart.ID, product.ID, 2, product.Price),
+	))
+
+	newOrder := func(price domain.Money) *domain.Order {
+		order := domain.NewOrder(customer.ID)
+		order.Items = []domain.OrderItem{
+			*domain.NewOrderItem(order.ID, product.ID, 2, price),
+		}
+		return order
+	}
+
+	cartItems := func() int64 {
+		var count int64
+		postgres.DB.Model(&domain.CartItem{}).
+			Where("cart_id = ?", cart.ID).
+			Count(&count)
+		return count
+	}
+
+	// A price that moved since the customer accepted it leaves the cart
+	err := repo.Checkout(ctx, newOrder(product.Price.Sub(domain.NewMoney(1, domain.DefaultCurrency))), cart.ID.String())
+	assert.ErrorIs(t, err, customErrors.ErrOrderPriceChanged)
+	assert.Equal(t, int64(1), cartItems())
+
+	order := newOrder(product.Price)
+	require.NoError(t, repo.Checkout(ctx, order, cart.ID.String()))
+	assert.Zero(t, cartItems())
+
+	var found domain.Order
+	require.NoError(t, postgres.DB.First(&found, "id = ?", order.ID).Error)
+}
+
+func TestOrderRepository_Create_ConcurrentStockReservation(t *testing.T) {
+	postgres := setupTestDB(
+		t,
+		&domain.OrderItem{},
+		&domain.Order{},
+		&domain.Product{},
+		&domain.StockMovement{},
+	)
+	repo := NewOrderRepository(postgres)
+	ctx := context.Background()
+
+	const (
+		initialStock = 10
+		buyers       = 50
+	)
+
+	product := createTestProduct(t, postgres.DB)
+	require.NoError(t, postgres.DB.Model(product).
+		Update("stock", initialStock).Error)
+	customer := createTestCustomer(t, postgres.DB)
+
+	var (
+		wg        sync.WaitGroup
+		succeeded atomic.Int32
+		rejected  atomic.Int32
+	)
+
+	for i := 0; i < buyers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			order := &domain.Order{
+				ID:         uuid.New(),
+				CustomerID: customer.ID,
+				Status:     domain.OrderStatusPending,
+				Items: []domain.OrderItem{
+					{
+						ID:        uuid.New(),
+						ProductID: product.ID,
+						Quantity:  1,
+						Price:     product.Price,
+					},
+				},
+			}
+
+			err := repo.Create(ctx, order)
+			switch {
+			case err == nil:
+				succeeded.Add(1)
+			case errors.Is(err, customErrors.ErrInsufficientStock):
+				rejected.Add(1)
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(initialStock), succeeded.Load())
+	assert.Equal(t, int32(buyers-initialStock), rejected.Load())
+
+	var found domain.Product
+	require.NoError(t, postgres.DB.First(&found, "id = ?", product.ID).Error)
+	assert.Equal(t, 0, found.Stock)
+
+	var orders int64
+	require.NoError(t, postgres.DB.Model(&domain.Order{}).Count(&orders).Error)
+	assert.Equal(t, int64(initialStock), orders)
+}
+
 func TestOrderRepository_GetByID(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -207,10 +411,10 @@ func TestOrderRepository_List(t *testing.T) {
 			ctx := context.Background()
 
 			_ = tt.setupTest(postgres.DB)
-			found, err := repo.List(ctx)
+			found, err := repo.List(ctx, domain.ListQuery{})
 
 			assert.NoError(t, err)
-			assert.Len(t, found, tt.expectedCount)
+			assert.Len(t, found.Items, tt.expectedCount)
 		})
 	}
 }
@@ -280,14 +484,14 @@ func TestOrderRepository_ListByCustomerID(t *testing.T) {
 			ctx := context.Background()
 
 			customerID, _ := tt.setupTest(postgres.DB)
-			found, err := repo.ListByCustomerID(ctx, customerID)
+			found, err := repo.ListByCustomerID(ctx, customerID, domain.ListQuery{})
 
 			if tt.expectedError != nil {
 				assert.ErrorIs(t, err, tt.expectedError)
 			} else {
 				assert.NoError(t, err)
-				assert.Len(t, found, tt.expectedCount)
-				for _, order := range found {
+				assert.Len(t, found.Items, tt.expectedCount)
+				for _, order := range found.Items {
 					assert.Equal(t, customerID, order.CustomerID.String())
 				}
 			}
@@ -365,6 +569,7 @@ func TestOrderRepository_UpdateStatus(t *testing.T) {
 	tests := []struct {
 		name          string
 		setupTest     func(*gorm.DB) *domain.Order
+		fromStatus    domain.OrderStatus
 		newStatus     domain.OrderStatus
 		expectedError error
 	}{
@@ -380,14 +585,32 @@ func TestOrderRepository_UpdateStatus(t *testing.T) {
 				require.NoError(t, db.Create(order).Error)
 				return order
 			},
+			fromStatus:    domain.OrderStatusPending,
 			newStatus:     domain.OrderStatusConfirmed,
 			expectedError: nil,
 		},
+		{
+			name: "Error - Status Changed Concurrently",
+			setupTest: func(db *gorm.DB) *domain.Order {
+				customer := createTestCustomer(t, db)
+				order := &domain.Order{
+					ID:         uuid.New(),
+					CustomerID: customer.ID,
+					Status:     domain.OrderStatusCancelled,
+				}
+				require.NoError(t, db.Create(order).Error)
+				return order
+			},
+			fromStatus:    domain.OrderStatusPending,
+			newStatus:     domain.OrderStatusConfirmed,
+			expectedError: customErrors.ErrOrderStatusInvalid,
+		},
 		{
 			name: "Error - Order Not Found",
 			setupTest: func(_ *gorm.DB) *domain.Order {
 				return &domain.Order{ID: uuid.New()}
 			},
+			fromStatus:    domain.OrderStatusPending,
 			newStatus:     domain.OrderStatusConfirmed,
 			expectedError: customErrors.ErrOrderNotFound,
 		},
@@ -403,54 +626,161 @@ func TestOrderRepository_UpdateStatus(t *testing.T) {
 				&domain.Category{},
 				&domain.Customer{},
 				&domain.User{},
+				&domain.OrderStatusChange{},
+				&domain.OutboxEvent{},
+				&domain.StockMovement{},
 			)
 			repo := NewOrderRepository(postgres)
 			ctx := context.Background()
 
 			order := tt.setupTest(postgres.DB)
-			err := repo.UpdateStatus(ctx, order.ID.String(), tt.newStatus)
+			change, err := domain.NewOrderStatusChange(
+				&domain.Order{ID: order.ID, Status: tt.fromStatus},
+				tt.newStatus,
+				"",
+				"customer confirmed by phone",
+			)
+			require.NoError(t, err)
+
+			err = repo.UpdateStatus(ctx, &domain.OrderTransition{
+				From:   tt.fromStatus,
+				To:     tt.newStatus,
+				Change: change,
+			})
+
+			history, histErr := repo.ListStatusHistory(ctx, order.ID.String())
+			require.NoError(t, histErr)
 
 			if tt.expectedError != nil {
 				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Empty(t, history)
 			} else {
 				assert.NoError(t, err)
 				var found domain.Order
 				err = postgres.DB.First(&found, "id = ?", order.ID).Error
 				assert.NoError(t, err)
 				assert.Equal(t, tt.newStatus, found.Status)
+
+				require.Len(t, history, 1)
+				assert.Equal(t, tt.fromStatus, history[0].FromStatus)
+				assert.Equal(t, tt.newStatus, history[0].ToStatus)
+				assert.Equal(t, "customer confirmed by phone", history[0].Reason)
+				assert.Nil(t, history[0].ActorID)
 			}
 		})
 	}
 }
 
+func TestOrderRepository_UpdateStatusRestoresStock(t *testing.T) {
+	postgres := setupTestDB(
+		t,
+		&domain.OrderItem{},
+		&domain.Order{},
+		&domain.Product{},
+		&domain.Category{},
+		&domain.Customer{},
+		&domain.User{},
+		&domain.OrderStatusChange{},
+		&domain.OutboxEvent{},
+		&domain.StockMovement{},
+	)
+	repo := NewOrderRepository(postgres)
+	ctx := context.Background()
+
+	product := createTestProduct(t, postgres.DB)
+	customer := createTestCustomer(t, postgres.DB)
+	order := &domain.Order{
+		ID:         uuid.New(),
+		CustomerID: customer.ID,
+		Status:     domain.OrderStatusPending,
+		Items: []domain.OrderItem{{
+			ID:        uuid.New(),
+			ProductID: product.ID,
+			Quantity:  5,
+			Price:     product.Price,
+		}},
+	}
+	require.NoError(t, repo.Create(ctx, order))
+
+	transition := func(from, to domain.OrderStatus) *domain.OrderTransition {
+		change, err := domain.NewOrderStatusChange(
+			&domain.Order{ID: order.ID, Status: from},
+			to,
+			"",
+			"",
+		)
+		require.NoError(t, err)
+		return &domain.OrderTransition{
+			From:   from,
+			To:     to,
+			Change: change,
+			Restock: []domain.StockAdjustment{
+				{ProductID: product.ID, Quantity: 5},
+			},
+		}
+	}
+
+	stock := func() int {
+		var found domain.Product
+		require.NoError(t, postgres.DB.First(&found, "id = ?", product.ID).Error)
+		return found.Stock
+	}
+	require.Equal(t, 95, stock())
+
+	// A stale transition is rolled back without touching stock.
+	err := repo.UpdateStatus(
+		ctx,
+		transition(domain.OrderStatusConfirmed, domain.OrderStatusCancelled),
+	)
+	assert.ErrorIs(t, err, customErrors.ErrOrderStatusInvalid)
+	assert.Equal(t, 95, stock())
+
+	err = repo.UpdateStatus(
+		ctx,
+		transition(domain.OrderStatusPending, domain.OrderStatusCancelled),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, 100, stock())
+
+	var movements []domain.StockMovement
+	require.NoError(t, postgres.DB.
+		Where("order_id = ?", order.ID).
+		Order("created_at").
+		Find(&movements).Error)
+	require.Len(t, movements, 2)
+	assert.Equal(t, domain.StockMovementSale, movements[0].Type)
+	assert.Equal(t, -5, movements[0].Quantity)
+	assert.Equal(t, domain.StockMovementReturn, movements[1].Type)
+	assert.Equal(t, 5, movements[1].Quantity)
+}
+
 func TestOrderRepository_AddOrderItem(t *testing.T) {
 	tests := []struct {
 		name          string
-		setupTest     func(*gorm.DB) (*domain.Order, *domain.OrderItem)
+		quantity      int
+		status        domain.OrderStatus
+		expectedStock int
 		expectedError error
 	}{
 		{
-			name: "Success - Add Order Item",
-			setupTest: func(db *gorm.DB) (*domain.Order, *domain.OrderItem) {
-				product := createTestProduct(t, db)
-				customer := createTestCustomer(t, db)
-				order := &domain.Order{
-					ID:         uuid.New(),
-					CustomerID: customer.ID,
-					Status:     domain.OrderStatusPending,
-				}
-				require.NoError(t, db.Create(order).Error)
-
-				item := &domain.OrderItem{
-					ID:        uuid.New(),
-					OrderID:   order.ID,
-					ProductID: product.ID,
-					Quantity:  2,
-					Price:     product.Price,
-				}
-				return order, item
-			},
-			expectedError: nil,
+			name:          "Success - Add Order Item",
+			quantity:      2,
+			status:        domain.OrderStatusPending,
+			expectedStock: 98,
+		},
+		{
+			name:          "Error - Insufficient Stock",
+			quantity:      101,
+			status:        domain.OrderStatusPending,
+			expectedStock: 100,
+			expectedError: customErrors.ErrInsufficientStock,
+		},
+		{
+			name:          "Error - Invalid Order Status",
+			quantity:      2,
+			status:        domain.OrderStatusShipped,
+			expectedStock: 100,
+			expectedError: customErrors.ErrOrderStatusInvalid,
 		},
 	}
 
@@ -460,6 +790,7 @@ func TestOrderRepository_AddOrderItem(t *testing.T) {
 				t,
 				&domain.OrderItem{},
 				&domain.Order{},
+				&domain.StockMovement{},
 				&domain.Product{},
 				&domain.Category{},
 				&domain.Customer{},
@@ -468,34 +799,47 @@ func TestOrderRepository_AddOrderItem(t *testing.T) {
 			repo := NewOrderRepository(postgres)
 			ctx := context.Background()
 
-			order, item := tt.setupTest(postgres.DB)
-
-			updateStockFunc := func(_ context.Context, _ string, _ int) error {
-				return nil
+			product := createTestProduct(t, postgres.DB)
+			customer := createTestCustomer(t, postgres.DB)
+			order := &domain.Order{
+				ID:         uuid.New(),
+				CustomerID: customer.ID,
+				Status:     tt.status,
+				TotalPrice: domain.NewMoney(0, domain.DefaultCurrency),
 			}
+			require.NoError(t, postgres.DB.Create(order).Error)
 
-			updateOrderTotalFunc := func(_ context.Context, _ *domain.Order, _ float64) error {
-				return nil
+			item := &domain.OrderItem{
+				ID:        uuid.New(),
+				ProductID: product.ID,
+				Quantity:  tt.quantity,
+				Price:     product.Price,
 			}
 
-			err := repo.AddOrderItem(
-				ctx,
-				order.ID.String(),
-				item,
-				updateStockFunc,
-				updateOrderTotalFunc,
-			)
+			err := repo.AddOrderItem(ctx, order.ID.String(), item)
+
+			var movements int64
+			postgres.DB.Model(&domain.StockMovement{}).
+				Where("order_id = ?", order.ID).
+				Count(&movements)
 
 			if tt.expectedError != nil {
 				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Zero(t, movements)
 			} else {
-				assert.NoError(t, err)
+				require.NoError(t, err)
 				var found domain.Order
 				err = postgres.DB.Preload("Items").
 					First(&found, "id = ?", order.ID).Error
-				assert.NoError(t, err)
+				require.NoError(t, err)
 				assert.Len(t, found.Items, 1)
+				assert.Equal(t, product.Price.Mul(tt.quantity), found.TotalPrice)
+				assert.Equal(t, int64(1), movements)
 			}
+
+			var stocked domain.Product
+			require.NoError(t, postgres.DB.First(&stocked, "id = ?", product.ID).Error)
+			assert.Equal(t, tt.expectedStock, stocked.Stock)
 		})
 	}
 }
@@ -585,6 +929,7 @@ func TestOrderRepository_RemoveOrderItem(t *testing.T) {
 				t,
 				&domain.OrderItem{},
 				&domain.Order{},
+				&domain.StockMovement{},
 				&domain.Product{},
 				&domain.Category{},
 				&domain.Customer{},
@@ -595,21 +940,7 @@ func TestOrderRepository_RemoveOrderItem(t *testing.T) {
 
 			order, itemID := tt.setupTest(postgres.DB)
 
-			restoreStockFunc := func(_ context.Context, _ string, _ int) error {
-				return nil
-			}
-
-			updateOrderTotalFunc := func(_ context.Context, _ *domain.Order, _ float64) error {
-				return nil
-			}
-
-			err := repo.RemoveOrderItem(
-				ctx,
-				order.ID.String(),
-				itemID,
-				restoreStockFunc,
-				updateOrderTotalFunc,
-			)
+			err := repo.RemoveOrderItem(ctx, order.ID.String(), itemID)
 
 			if tt.expectedError != nil {
 				assert.ErrorIs(t, err, tt.expectedError)
@@ -619,6 +950,11 @@ func TestOrderRepository_RemoveOrderItem(t *testing.T) {
 				err = postgres.DB.Preload("Items").First(&found, "id = ?", order.ID).Error
 				assert.NoError(t, err)
 				assert.Empty(t, found.Items)
+
+				var product domain.Product
+				err = postgres.DB.First(&product, "id = ?", order.Items[0].ProductID).Error
+				require.NoError(t, err)
+				assert.Equal(t, 102, product.Stock)
 			}
 		})
 	}
@@ -0,0 +1,252 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grocery-service/internal/domain"
+	"github.com/grocery-service/internal/repository/db"
+	customErrors "github.com/grocery-service/utils/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	OutboxRepository interface {
+		// ClaimDue returns up to limit pending events that are due and
+		// pushes their next attempt out by lease, so other dispatchers
+		// skip them while they are being delivered. Events whose
+		// dispatcher dies become due again once the lease expires.
+		ClaimDue(
+			ctx context.Context,
+			limit int,
+			lease time.Duration,
+		) ([]domain.OutboxEvent, error)
+		GetByID(ctx context.Context, id string) (*domain.OutboxEvent, error)
+		List(
+			ctx context.Context,
+			status domain.OutboxStatus,
+			query domain.ListQuery,
+		) (*domain.Page[domain.OutboxEvent], error)
+		// SaveAttempt stores the outcome of a delivery attempt.
+		SaveAttempt(ctx context.Context, event *domain.OutboxEvent) error
+		// Replay moves a dead event back to pending with a fresh retry
+		// budget.
+		Replay(ctx context.Context, id string) (*domain.OutboxEvent, error)
+	}
+
+	OutboxRepositoryImpl struct {
+		*db.BaseRepository[domain.OutboxEvent]
+	}
+)
+
+var outboxListSpec = listSpec[domain.OutboxEvent]{
+	table:       "outbox_events",
+	defaultSort: "created_at",
+	sortFields: map[string]sortField[domain.OutboxEvent]{
+		"created_at": {
+			column: "outbox_events.created_at",
+			value:  func(e domain.OutboxEvent) any { return e.CreatedAt },
+		},
+		"next_attempt_at": {
+			column: "outbox_events.next_attempt_at",
+			value:  func(e domain.OutboxEvent) any { return e.NextAttemptAt },
+		},
+		"attempts": {
+			column: "outbox_events.attempts",
+			value:  func(e domain.OutboxEvent) any { return e.Attempts },
+		},
+	},
+	id: func(e domain.OutboxEvent) uuid.UUID { return e.ID },
+}
+
+func NewOutboxRepository(
+	postgres *db.PostgresDB,
+) *OutboxRepositoryImpl {
+	return &OutboxRepositoryImpl{
+		BaseRepository: db.NewBaseRepository[domain.OutboxEvent](
+			postgres,
+		),
+	}
+}
+
+// insertOutboxEvents records events inside the caller's transaction so
+// they are only published if the surrounding change commits.
+func insertOutboxEvents(
+	ctx context.Context,
+	tx *gorm.DB,
+	events []*domain.OutboxEvent,
+) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	if err := tx.WithContext(ctx).Create(events).Error; err != nil {
+		return fmt.Errorf(
+			"%w: failed to record outbox events: %v",
+			customErrors.ErrDBQuery,
+			err,
+		)
+	}
+	return nil
+}
+
+func (r *OutboxRepositoryImpl) ClaimDue(
+	ctx context.Context,
+	limit int,
+	lease time.Duration,
+) ([]domain.OutboxEvent, error) {
+	var events []domain.OutboxEvent
+
+	err := r.BaseRepository.WithTransaction(
+		ctx,
+		func(txRepo *db.BaseRepository[domain.OutboxEvent]) error {
+			now := time.Now()
+			if err := txRepo.GetDB().WithContext(ctx).
+				Clauses(clause.Locking{
+					Strength: "UPDATE",
+					Options:  "SKIP LOCKED",
+				}).
+				Where("status = ? AND next_attempt_at <= ?", domain.OutboxStatusPending, now).
+				Order("next_attempt_at").
+				Limit(limit).
+				Find(&events).Error; err != nil {
+				return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+			}
+
+			if len(events) == 0 {
+				return nil
+			}
+
+			ids := make([]uuid.UUID, len(events))
+			for i, event := range events {
+				ids[i] = event.ID
+			}
+
+			if err := txRepo.GetDB().WithContext(ctx).
+				Model(&domain.OutboxEvent{}).
+				Where("id IN ?", ids).
+				Updates(map[string]interface{}{
+					"next_attempt_at": now.Add(lease),
+					"updated_at":      now,
+				}).Error; err != nil {
+				return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (r *OutboxRepositoryImpl) GetByID(
+	ctx context.Context,
+	id string,
+) (*domain.OutboxEvent, error) {
+	var event domain.OutboxEvent
+	err := r.BaseRepository.GetDB().WithContext(ctx).
+		First(&event, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, customErrors.ErrOutboxEventNotFound
+		}
+		return nil, fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+	}
+	return &event, nil
+}
+
+func (r *OutboxRepositoryImpl) List(
+	ctx context.Context,
+	status domain.OutboxStatus,
+	query domain.ListQuery,
+) (*domain.Page[domain.OutboxEvent], error) {
+	db := r.BaseRepository.GetDB().WithContext(ctx).
+		Model(&domain.OutboxEvent{})
+
+	if status != "" {
+		db = db.Where("outbox_events.status = ?", status)
+	}
+	db = applyCreatedRange(db, "outbox_events", query.Filter)
+
+	return listPage(db, query, outboxListSpec)
+}
+
+func (r *OutboxRepositoryImpl) SaveAttempt(
+	ctx context.Context,
+	event *domain.OutboxEvent,
+) error {
+	result := r.BaseRepository.GetDB().WithContext(ctx).
+		Model(&domain.OutboxEvent{}).
+		Where("id = ?", event.ID).
+		Updates(map[string]interface{}{
+			"status":          event.Status,
+			"attempts":        event.Attempts,
+			"last_error":      event.LastError,
+			"next_attempt_at": event.NextAttemptAt,
+			"delivered_at":    event.DeliveredAt,
+			"updated_at":      time.Now(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return customErrors.ErrOutboxEventNotFound
+	}
+	return nil
+}
+
+func (r *OutboxRepositoryImpl) Replay(
+	ctx context.Context,
+	id string,
+) (*domain.OutboxEvent, error) {
+	var event domain.OutboxEvent
+
+	err := r.BaseRepository.WithTransaction(
+		ctx,
+		func(txRepo *db.BaseRepository[domain.OutboxEvent]) error {
+			if err := txRepo.GetDB().WithContext(ctx).
+				Clauses(clause.Locking{Strength: "UPDATE"}).
+				First(&event, "id = ?", id).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return customErrors.ErrOutboxEventNotFound
+				}
+				return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+			}
+
+			if event.Status != domain.OutboxStatusDead {
+				return customErrors.ErrOutboxEventNotReplayable
+			}
+
+			now := time.Now()
+			event.Status = domain.OutboxStatusPending
+			event.Attempts = 0
+			event.NextAttemptAt = now
+			event.UpdatedAt = now
+
+			if err := txRepo.GetDB().WithContext(ctx).
+				Model(&domain.OutboxEvent{}).
+				Where("id = ?", event.ID).
+				Updates(map[string]interface{}{
+					"status":          event.Status,
+					"attempts":        event.Attempts,
+					"next_attempt_at": event.NextAttemptAt,
+					"updated_at":      event.UpdatedAt,
+				}).Error; err != nil {
+				return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
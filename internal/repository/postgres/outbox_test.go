@@ -0,0 +1,198 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grocery-service/internal/domain"
+	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func createTestOutboxEvent(
+	t *testing.T,
+	db *gorm.DB,
+	status domain.OutboxStatus,
+	nextAttemptAt time.Time,
+) *domain.OutboxEvent {
+	event, err := domain.NewOrderEvent(
+		domain.OutboxEventOrderConfirmation,
+		&domain.Order{ID: uuid.New(), Status: domain.OrderStatusPending},
+	)
+	require.NoError(t, err)
+	event.Status = status
+	event.NextAttemptAt = nextAttemptAt
+
+	require.NoError(t, db.Create(event).Error)
+	return event
+}
+
+func TestOutboxRepository_ClaimDue(t *testing.T) {
+	postgres := setupTestDB(t, &domain.OutboxEvent{})
+	repo := NewOutboxRepository(postgres)
+	ctx := context.Background()
+
+	due := createTestOutboxEvent(
+		t,
+		postgres.DB,
+		domain.OutboxStatusPending,
+		time.Now().Add(-time.Minute),
+	)
+	createTestOutboxEvent(
+		t,
+		postgres.DB,
+		domain.OutboxStatusPending,
+		time.Now().Add(time.Hour),
+	)
+	createTestOutboxEvent(
+		t,
+		postgres.DB,
+		domain.OutboxStatusDead,
+		time.Now().Add(-time.Minute),
+	)
+
+	events, err := repo.ClaimDue(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, due.ID, events[0].ID)
+
+	// The lease hides a claimed event from the next poll.
+	events, err = repo.ClaimDue(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+func TestOutboxRepository_Replay(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        domain.OutboxStatus
+		expectedError error
+	}{
+		{
+			name:   "Success - Dead Event",
+			status: domain.OutboxStatusDead,
+		},
+		{
+			name:          "Error - Delivered Event",
+			status:        domain.OutboxStatusDelivered,
+			expectedError: customErrors.ErrOutboxEventNotReplayable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postgres := setupTestDB(t, &domain.OutboxEvent{})
+			repo := NewOutboxRepository(postgres)
+			ctx := context.Background()
+
+			event := createTestOutboxEvent(
+				t,
+				postgres.DB,
+				tt.status,
+				time.Now().Add(time.Hour),
+			)
+			require.NoError(t, postgres.DB.Model(event).
+				Update("attempts", 8).Error)
+
+			replayed, err := repo.Replay(ctx, event.ID.String())
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, domain.OutboxStatusPending, replayed.Status)
+			assert.Zero(t, replayed.Attempts)
+
+			events, err := repo.ClaimDue(ctx, 10, time.Minute)
+			require.NoError(t, err)
+			require.Len(t, events, 1)
+			assert.Equal(t, event.ID, events[0].ID)
+		})
+	}
+
+	t.Run("Error - Not Found", func(t *testing.T) {
+		postgres := setupTestDB(t, &domain.OutboxEvent{})
+		repo := NewOutboxRepository(postgres)
+
+		_, err := repo.Replay(context.Background(), uuid.New().String())
+
+		assert.ErrorIs(t, err, customErrors.ErrOutboxEventNotFound)
+	})
+}
+
+func TestOrderRepository_CreateRecordsOutboxEvent(t *testing.T) {
+	postgres := setupTestDB(
+		t,
+		&domain.OrderItem{},
+		&domain.Order{},
+		&domain.Product{},
+		&domain.OutboxEvent{},
+		&domain.StockMovement{},
+	)
+	repo := NewOrderRepository(postgres)
+	ctx := context.Background()
+
+	product := createTestProduct(t, postgres.DB)
+	customer := createTestCustomer(t, postgres.DB)
+
+	newOrder := func(quantity int) *domain.Order {
+		return &domain.Order{
+			ID:         uuid.New(),
+			CustomerID: customer.ID,
+			Status:     domain.OrderStatusPending,
+			Items: []domain.OrderItem{{
+				ID:        uuid.New(),
+				ProductID: product.ID,
+				Quantity:  quantity,
+				Price:     product.Price,
+			}},
+		}
+	}
+
+	order := newOrder(2)
+	event, err := domain.NewOrderEvent(
+		domain.OutboxEventOrderConfirmation,
+		order,
+	)
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(ctx, order, event))
+
+	var count int64
+	postgres.DB.Model(&domain.OutboxEvent{}).
+		Where("aggregate_id = ?", order.ID).
+		Count(&count)
+	assert.Equal(t, int64(1), count)
+
+	// A rolled back order must not leave its event behind.
+	failed := newOrder(1000)
+	event, err = domain.NewOrderEvent(
+		domain.OutboxEventOrderConfirmation,
+		failed,
+	)
+	require.NoError(t, err)
+	err = repo.Create(ctx, failed, event)
+	assert.ErrorIs(t, err, customErrors.ErrInsufficientStock)
+
+	postgres.DB.Model(&domain.OutboxEvent{}).
+		Where("aggregate_id = ?", failed.ID).
+		Count(&count)
+	assert.Zero(t, count)
+
+	// An order that takes the product to its reorder point records a
+	// low-stock alert alongside its own events.
+	require.NoError(t, postgres.DB.Model(product).
+		Update("reorder_point", 90).Error)
+	require.NoError(t, repo.Create(ctx, newOrder(10)))
+
+	var alerts []domain.OutboxEvent
+	require.NoError(t, postgres.DB.
+		Where("event_type = ?", domain.OutboxEventLowStock).
+		Find(&alerts).Error)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, product.ID, alerts[0].AggregateID)
+}
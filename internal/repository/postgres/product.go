@@ -4,26 +4,46 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"html"
+	"strings"
+	"unicode"
 
 	"github.com/google/uuid"
 	"github.com/grocery-service/internal/domain"
 	"github.com/grocery-service/internal/repository/db"
 	customErrors "github.com/grocery-service/utils/errors"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type (
 	ProductRepository interface {
 		Create(ctx context.Context, product *domain.Product) error
 		GetByID(ctx context.Context, id string) (*domain.Product, error)
-		List(ctx context.Context) ([]domain.Product, error)
+		List(
+			ctx context.Context,
+			query domain.ListQuery,
+		) (*domain.Page[domain.Product], error)
 		ListByCategoryID(
 			ctx context.Context,
 			categoryID string,
-		) ([]domain.Product, error)
+			query domain.ListQuery,
+		) (*domain.Page[domain.Product], error)
+		Search(
+			ctx context.Context,
+			term string,
+			query domain.ListQuery,
+		) (*domain.Page[domain.ProductSearchResult], error)
 		Update(ctx context.Context, product *domain.Product) error
 		Delete(ctx context.Context, id string) error
-		UpdateStock(ctx context.Context, id string, quantity int) error
+		// ListLowStock lists products at or below their reorder point.
+		ListLowStock(
+			ctx context.Context,
+			query domain.ListQuery,
+		) (*domain.Page[domain.Product], error)
+		// UpdateStock applies movement to the product's stock and records
+		// it in the ledger. Stock never goes below zero.
+		UpdateStock(ctx context.Context, movement *domain.StockMovement) error
 	}
 
 	ProductRepositoryImpl struct {
@@ -31,6 +51,49 @@ type (
 	}
 )
 
+const (
+	productColumns = "products.id, products.name, products.description, " +
+		"products.price, products.stock, products.reorder_point, " +
+		"products.reorder_quantity, products.category_id, " +
+		"products.created_at, products.updated_at"
+
+	// ts_headline marks matches with private-use sentinels rather than
+	// tags, so the product text can be HTML-escaped before the sentinels
+	// are turned into <mark> tags.
+	highlightStart  = "\ue000"
+	highlightStop   = "\ue001"
+	headlineOptions = `StartSel="` + highlightStart + `", StopSel="` +
+		highlightStop + `", HighlightAll=true`
+	snippetOptions = `StartSel="` + highlightStart + `", StopSel="` +
+		highlightStop + `", MaxWords=30, MinWords=10, MaxFragments=2`
+
+	fuzzySnippetLength = 160
+)
+
+var productListSpec = listSpec[domain.Product]{
+	table:       "products",
+	defaultSort: "created_at",
+	sortFields: map[string]sortField[domain.Product]{
+		"name": {
+			column: "products.name",
+			value:  func(p domain.Product) any { return p.Name },
+		},
+		"price": {
+			column: "products.price",
+			value:  func(p domain.Product) any { return p.Price },
+		},
+		"stock": {
+			column: "products.stock",
+			value:  func(p domain.Product) any { return p.Stock },
+		},
+		"created_at": {
+			column: "products.created_at",
+			value:  func(p domain.Product) any { return p.CreatedAt },
+		},
+	},
+	id: func(p domain.Product) uuid.UUID { return p.ID },
+}
+
 func NewProductRepository(
 	postgres *db.PostgresDB,
 ) *ProductRepositoryImpl {
@@ -69,7 +132,18 @@ func (r *ProductRepositoryImpl) Create(
 				)
 			}
 
-			return nil
+			if product.Stock == 0 {
+				return nil
+			}
+			return recordStockMovements(
+				ctx,
+				txRepo.GetDB(),
+				[]*domain.StockMovement{domain.NewStockMovement(
+					product.ID,
+					domain.StockMovementRestock,
+					product.Stock,
+				)},
+			)
 		},
 	)
 }
@@ -100,39 +174,243 @@ func (r *ProductRepositoryImpl) GetByID(
 
 func (r *ProductRepositoryImpl) List(
 	ctx context.Context,
-) ([]domain.Product, error) {
-	var products []domain.Product
-	err := r.BaseRepository.GetDB().WithContext(ctx).
-		Find(&products).
-		Error
-	if err != nil {
-		return nil, fmt.Errorf(
-			"%w: %v",
-			customErrors.ErrDBQuery,
-			err,
-		)
+	query domain.ListQuery,
+) (*domain.Page[domain.Product], error) {
+	db := r.BaseRepository.GetDB().WithContext(ctx).
+		Model(&domain.Product{})
+
+	filter := query.Filter
+	if filter.CategoryID != nil {
+		db = db.Where("products.category_id = ?", *filter.CategoryID)
+	}
+	if filter.MinPrice != nil {
+		db = db.Where("products.price >= ?", *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		db = db.Where("products.price <= ?", *filter.MaxPrice)
+	}
+	db = applyCreatedRange(db, "products", filter)
+
+	return listPage(db, query, productListSpec)
+}
+
+func (r *ProductRepositoryImpl) ListLowStock(
+	ctx context.Context,
+	query domain.ListQuery,
+) (*domain.Page[domain.Product], error) {
+	db := r.BaseRepository.GetDB().WithContext(ctx).
+		Model(&domain.Product{}).
+		Where("products.reorder_point > 0").
+		Where("products.stock <= products.reorder_point")
+	if query.Filter.CategoryID != nil {
+		db = db.Where("products.category_id = ?", *query.Filter.CategoryID)
 	}
 
-	return products, nil
+	if query.SortBy == "" {
+		query.SortBy = "stock"
+	}
+	return listPage(db, query, productListSpec)
 }
 
 func (r *ProductRepositoryImpl) ListByCategoryID(
 	ctx context.Context,
 	categoryID string,
-) ([]domain.Product, error) {
-	var products []domain.Product
-	err := r.BaseRepository.GetDB().WithContext(ctx).
-		Where("category_id = ?", categoryID).
-		Find(&products).Error
+	query domain.ListQuery,
+) (*domain.Page[domain.Product], error) {
+	id, err := uuid.Parse(categoryID)
 	if err != nil {
+		return nil, fmt.Errorf(
+			"%w: invalid category ID",
+			customErrors.ErrInvalidProductData,
+		)
+	}
+
+	query.Filter.CategoryID = &id
+	return r.List(ctx, query)
+}
+
+// Search ranks products against term using the search_vector column.
+// Every word is matched as a prefix. When nothing matches, it falls back
+// to trigram similarity on the product name to tolerate typos. A
+// category filter covers the whole subtree below that category.
+func (r *ProductRepositoryImpl) Search(
+	ctx context.Context,
+	term string,
+	query domain.ListQuery,
+) (*domain.Page[domain.ProductSearchResult], error) {
+	if err := query.Normalize(); err != nil {
 		return nil, fmt.Errorf(
 			"%w: %v",
-			customErrors.ErrDBQuery,
+			customErrors.ErrInvalidListQuery,
 			err,
 		)
 	}
+	if query.Cursor != "" {
+		return nil, fmt.Errorf(
+			"%w: search results are paged by page number",
+			customErrors.ErrInvalidListQuery,
+		)
+	}
+
+	if tsQuery := prefixTSQuery(term); tsQuery != "" {
+		page, err := r.fullTextSearch(ctx, tsQuery, query)
+		if err != nil || page.Total > 0 {
+			return page, err
+		}
+	}
 
-	return products, nil
+	return r.fuzzySearch(ctx, term, query)
+}
+
+func (r *ProductRepositoryImpl) fullTextSearch(
+	ctx context.Context,
+	tsQuery string,
+	query domain.ListQuery,
+) (*domain.Page[domain.ProductSearchResult], error) {
+	db := r.searchScope(ctx, query.Filter).
+		Where("products.search_vector @@ to_tsquery('english', ?)", tsQuery).
+		Session(&gorm.Session{})
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+	}
+
+	results := []domain.ProductSearchResult{}
+	err := db.
+		Select(
+			productColumns+", "+
+				"ts_rank_cd(products.search_vector, to_tsquery('english', ?)) AS rank, "+
+				"ts_headline('english', products.name, to_tsquery('english', ?), ?) AS name_highlight, "+
+				"ts_headline('english', coalesce(products.description, ''), to_tsquery('english', ?), ?) AS snippet",
+			tsQuery,
+			tsQuery,
+			headlineOptions,
+			tsQuery,
+			snippetOptions,
+		).
+		Order("rank DESC, products.id").
+		Limit(query.Limit).
+		Offset(query.Offset()).
+		Scan(&results).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+	}
+
+	markHighlights(results)
+	return &domain.Page[domain.ProductSearchResult]{
+		Items: results,
+		Total: total,
+		Limit: query.Limit,
+		Page:  query.Page,
+	}, nil
+}
+
+func (r *ProductRepositoryImpl) fuzzySearch(
+	ctx context.Context,
+	term string,
+	query domain.ListQuery,
+) (*domain.Page[domain.ProductSearchResult], error) {
+	db := r.searchScope(ctx, query.Filter).
+		Where("? <% products.name", term).
+		Session(&gorm.Session{})
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+	}
+
+	results := []domain.ProductSearchResult{}
+	err := db.
+		Select(
+			productColumns+", "+
+				"word_similarity(?, products.name) AS rank, "+
+				"products.name AS name_highlight, "+
+				"left(coalesce(products.description, ''), ?) AS snippet",
+			term,
+			fuzzySnippetLength,
+		).
+		Order("rank DESC, products.id").
+		Limit(query.Limit).
+		Offset(query.Offset()).
+		Scan(&results).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+	}
+
+	for i := range results {
+		results[i].Fuzzy = true
+	}
+
+	markHighlights(results)
+	return &domain.Page[domain.ProductSearchResult]{
+		Items: results,
+		Total: total,
+		Limit: query.Limit,
+		Page:  query.Page,
+	}, nil
+}
+
+// markHighlights HTML-escapes the highlighted name and snippet of every
+// result and only then turns the ts_headline sentinels into <mark> tags,
+// so product text can never inject markup of its own.
+func markHighlights(results []domain.ProductSearchResult) {
+	marks := strings.NewReplacer(
+		highlightStart, "<mark>",
+		highlightStop, "</mark>",
+	)
+	for i := range results {
+		results[i].NameHighlight = marks.Replace(
+			html.EscapeString(results[i].NameHighlight),
+		)
+		results[i].Snippet = marks.Replace(
+			html.EscapeString(results[i].Snippet),
+		)
+	}
+}
+
+// searchScope applies the list filters shared by both search strategies.
+func (r *ProductRepositoryImpl) searchScope(
+	ctx context.Context,
+	filter domain.ListFilter,
+) *gorm.DB {
+	db := r.BaseRepository.GetDB().WithContext(ctx).Table("products")
+
+	if filter.CategoryID != nil {
+		db = db.Where(
+			`products.category_id IN (
+				SELECT c.id FROM categories c
+				JOIN categories root ON root.id = ?
+				WHERE c.path = root.path
+					OR starts_with(c.path, root.path || '/')
+			)`,
+			*filter.CategoryID,
+		)
+	}
+	if filter.MinPrice != nil {
+		db = db.Where("products.price >= ?", *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		db = db.Where("products.price <= ?", *filter.MaxPrice)
+	}
+
+	return applyCreatedRange(db, "products", filter)
+}
+
+// prefixTSQuery turns free text into a tsquery that matches every word
+// as a prefix. Anything but letters and digits is dropped, so user input
+// cannot inject tsquery operators.
+func prefixTSQuery(term string) string {
+	words := strings.FieldsFunc(
+		strings.ToLower(term),
+		func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		},
+	)
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+	return strings.Join(words, " & ")
 }
 
 func (r *ProductRepositoryImpl) Update(
@@ -151,9 +429,12 @@ func (r *ProductRepositoryImpl) Update(
 				}
 			}
 
+			// Stock only changes through UpdateStock so that every change
+			// is in the ledger.
 			result := txRepo.GetDB().WithContext(ctx).
 				Model(&domain.Product{}).
 				Where("id = ?", product.ID).
+				Omit("stock").
 				Updates(product)
 			if result.Error != nil {
 				return fmt.Errorf(
@@ -201,33 +482,86 @@ func (r *ProductRepositoryImpl) Delete(
 
 func (r *ProductRepositoryImpl) UpdateStock(
 	ctx context.Context,
-	id string,
-	quantity int,
+	movement *domain.StockMovement,
 ) error {
+	if err := movement.Validate(); err != nil {
+		return fmt.Errorf(
+			"%w: %v",
+			customErrors.ErrInvalidProductData,
+			err,
+		)
+	}
+
 	return r.BaseRepository.WithTransaction(
 		ctx,
 		func(txRepo *db.BaseRepository[domain.Product]) error {
-			result := txRepo.GetDB().WithContext(ctx).
+			var product domain.Product
+			if err := txRepo.GetDB().WithContext(ctx).
+				Clauses(clause.Locking{Strength: "UPDATE"}).
+				First(&product, "id = ?", movement.ProductID).
+				Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return customErrors.ErrProductNotFound
+				}
+				return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+			}
+
+			stock := product.Stock + movement.Quantity
+			if stock < 0 {
+				return &customErrors.InsufficientStockError{
+					Shortfalls: []customErrors.StockShortfall{{
+						ProductID: product.ID.String(),
+						Requested: -movement.Quantity,
+						Available: product.Stock,
+					}},
+				}
+			}
+
+			if err := txRepo.GetDB().WithContext(ctx).
 				Model(&domain.Product{}).
-				Where("id = ?", id).
-				Update("stock", gorm.Expr("stock + ?", quantity))
-			if result.Error != nil {
+				Where("id = ?", product.ID).
+				Update("stock", stock).Error; err != nil {
 				return fmt.Errorf(
 					"%w: %v",
 					customErrors.ErrInvalidProductData,
-					result.Error,
+					err,
 				)
 			}
 
-			if result.RowsAffected == 0 {
-				return customErrors.ErrProductNotFound
+			if err := recordStockMovements(
+				ctx,
+				txRepo.GetDB(),
+				[]*domain.StockMovement{movement},
+			); err != nil {
+				return err
 			}
 
-			return nil
+			alerts, err := lowStockEvents(&product, stock)
+			if err != nil {
+				return err
+			}
+			return insertOutboxEvents(ctx, txRepo.GetDB(), alerts)
 		},
 	)
 }
 
+// lowStockEvents returns a low-stock outbox event if lowering product to
+// stock reaches its reorder point.
+func lowStockEvents(
+	product *domain.Product,
+	stock int,
+) ([]*domain.OutboxEvent, error) {
+	if !product.ReachesReorderPoint(stock) {
+		return nil, nil
+	}
+
+	event, err := domain.NewLowStockEvent(product.LowStockAlert(stock))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+	}
+	return []*domain.OutboxEvent{event}, nil
+}
+
 func (r *ProductRepositoryImpl) validateCategoryIsLeaf(
 	ctx context.Context,
 	categoryID string,
@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/google/uuid"
@@ -25,7 +26,7 @@ func TestProductRepository_Create(t *testing.T) {
 					ID:          uuid.New(),
 					Name:        "Test Product",
 					Description: "Test Description",
-					Price:       9.99,
+					Price:       domain.NewMoney(999, domain.DefaultCurrency),
 					Stock:       100,
 					CategoryID:  categoryID,
 				}
@@ -38,7 +39,7 @@ func TestProductRepository_Create(t *testing.T) {
 				return &domain.Product{
 					ID:          uuid.New(),
 					Description: "Test Description",
-					Price:       9.99,
+					Price:       domain.NewMoney(999, domain.DefaultCurrency),
 					Stock:       100,
 					CategoryID:  categoryID,
 				}
@@ -52,7 +53,7 @@ func TestProductRepository_Create(t *testing.T) {
 					ID:          uuid.New(),
 					Name:        "Test Product",
 					Description: "Test Description",
-					Price:       -9.99,
+					Price:       domain.NewMoney(-999, domain.DefaultCurrency),
 					Stock:       100,
 					CategoryID:  categoryID,
 				}
@@ -66,7 +67,7 @@ func TestProductRepository_Create(t *testing.T) {
 					ID:          uuid.New(),
 					Name:        "Test Product",
 					Description: "Test Description",
-					Price:       9.99,
+					Price:       domain.NewMoney(999, domain.DefaultCurrency),
 					Stock:       -100,
 					CategoryID:  categoryID,
 				}
@@ -77,7 +78,12 @@ func TestProductRepository_Create(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			postgres := setupTestDB(t, &domain.Product{}, &domain.Category{})
+			postgres := setupTestDB(
+				t,
+				&domain.Product{},
+				&domain.Category{},
+				&domain.StockMovement{},
+			)
 			repo := NewProductRepository(postgres)
 			ctx := context.Background()
 
@@ -117,7 +123,7 @@ func TestProductRepository_GetByID(t *testing.T) {
 					ID:          uuid.New(),
 					Name:        "Test Product",
 					Description: "Test Description",
-					Price:       9.99,
+					Price:       domain.NewMoney(999, domain.DefaultCurrency),
 					Stock:       100,
 					CategoryID:  categoryID,
 				}
@@ -178,14 +184,14 @@ func TestProductRepository_List(t *testing.T) {
 					{
 						ID:         uuid.New(),
 						Name:       "Product 1",
-						Price:      9.99,
+						Price:      domain.NewMoney(999, domain.DefaultCurrency),
 						Stock:      100,
 						CategoryID: categoryID,
 					},
 					{
 						ID:         uuid.New(),
 						Name:       "Product 2",
-						Price:      19.99,
+						Price:      domain.NewMoney(1999, domain.DefaultCurrency),
 						Stock:      200,
 						CategoryID: categoryID,
 					},
@@ -215,12 +221,12 @@ func TestProductRepository_List(t *testing.T) {
 			ctx := context.Background()
 
 			expected := tt.setupTest(postgres.DB)
-			found, err := repo.List(ctx)
+			found, err := repo.List(ctx, domain.ListQuery{})
 
 			assert.NoError(t, err)
-			assert.Len(t, found, tt.expectedCount)
+			assert.Len(t, found.Items, tt.expectedCount)
 			if tt.expectedCount > 0 {
-				for i, p := range found {
+				for i, p := range found.Items {
 					assert.Equal(t, expected[i].ID, p.ID)
 					assert.Equal(t, expected[i].Name, p.Name)
 					assert.Equal(t, expected[i].CategoryID, p.CategoryID)
@@ -230,6 +236,202 @@ func TestProductRepository_List(t *testing.T) {
 	}
 }
 
+func TestProductRepository_ListPagination(t *testing.T) {
+	postgres := setupTestDB(t, &domain.Product{}, &domain.Category{})
+	repo := NewProductRepository(postgres)
+	ctx := context.Background()
+
+	categoryID := createTestCategory(t, postgres.DB).ID
+	prices := []int64{500, 100, 400, 200, 300}
+	for i, price := range prices {
+		product := domain.NewProduct(
+			fmt.Sprintf("Product %d", i),
+			"",
+			domain.NewMoney(price, domain.DefaultCurrency),
+			10,
+			categoryID,
+		)
+		require.NoError(t, postgres.DB.Create(product).Error)
+	}
+
+	pricesOf := func(products []domain.Product) []string {
+		result := make([]string, 0, len(products))
+		for _, p := range products {
+			result = append(result, p.Price.Decimal())
+		}
+		return result
+	}
+
+	t.Run("Success - Page Based", func(t *testing.T) {
+		page, err := repo.List(ctx, domain.ListQuery{
+			Limit:  2,
+			Page:   2,
+			SortBy: "price",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), page.Total)
+		assert.Equal(t, []string{"3.00", "4.00"}, pricesOf(page.Items))
+		assert.NotEmpty(t, page.NextCursor)
+	})
+
+	t.Run("Success - Cursor Walks All Rows", func(t *testing.T) {
+		query := domain.ListQuery{
+			Limit:   2,
+			SortBy:  "price",
+			SortDir: domain.SortDesc,
+		}
+
+		var seen []string
+		for {
+			page, err := repo.List(ctx, query)
+			require.NoError(t, err)
+			seen = append(seen, pricesOf(page.Items)...)
+			if page.NextCursor == "" {
+				break
+			}
+			query.Cursor = page.NextCursor
+		}
+
+		assert.Equal(t, []string{"5.00", "4.00", "3.00", "2.00", "1.00"}, seen)
+	})
+
+	t.Run("Success - Price Filter", func(t *testing.T) {
+		minPrice := domain.NewMoney(200, domain.DefaultCurrency)
+		maxPrice := domain.NewMoney(400, domain.DefaultCurrency)
+		page, err := repo.List(ctx, domain.ListQuery{
+			SortBy: "price",
+			Filter: domain.ListFilter{
+				MinPrice: &minPrice,
+				MaxPrice: &maxPrice,
+			},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), page.Total)
+		assert.Equal(t, []string{"2.00", "3.00", "4.00"}, pricesOf(page.Items))
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("Error - Unsupported Sort Field", func(t *testing.T) {
+		_, err := repo.List(ctx, domain.ListQuery{SortBy: "description"})
+		assert.ErrorIs(t, err, customErrors.ErrInvalidListQuery)
+	})
+
+	t.Run("Error - Malformed Cursor", func(t *testing.T) {
+		_, err := repo.List(ctx, domain.ListQuery{Cursor: "not-a-cursor"})
+		assert.ErrorIs(t, err, customErrors.ErrInvalidListQuery)
+	})
+}
+
+func TestProductRepository_Search(t *testing.T) {
+	postgres := setupTestDB(t, &domain.Product{}, &domain.Category{})
+	repo := NewProductRepository(postgres)
+	ctx := context.Background()
+
+	// AutoMigrate does not know about the generated search column, so
+	// apply the relevant part of the search migration by hand.
+	for _, stmt := range []string{
+		"CREATE EXTENSION IF NOT EXISTS pg_trgm",
+		`ALTER TABLE products ADD COLUMN search_vector tsvector
+			GENERATED ALWAYS AS (
+				setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+				setweight(to_tsvector('english', coalesce(description, '')), 'B')
+			) STORED`,
+	} {
+		require.NoError(t, postgres.DB.Exec(stmt).Error)
+	}
+
+	newCategory := func(name string, parent *domain.Category) *domain.Category {
+		category := domain.NewCategory(name, "", nil)
+		category.Path = name
+		if parent != nil {
+			category.ParentID = &parent.ID
+			category.Level = parent.Level + 1
+			category.Path = parent.Path + "/" + name
+		}
+		require.NoError(t, postgres.DB.Create(category).Error)
+		return category
+	}
+	food := newCategory("Food", nil)
+	fruit := newCategory("Fruit", food)
+	drinks := newCategory("Drinks", food)
+	cleaning := newCategory("Cleaning", newCategory("Household", nil))
+
+	for _, p := range []*domain.Product{
+		domain.NewProduct("Green Apples", "Crisp and sour", domain.NewMoney(250, domain.DefaultCurrency), 10, fruit.ID),
+		domain.NewProduct("Banana", "Ripe yellow bananas", domain.NewMoney(120, domain.DefaultCurrency), 10, fruit.ID),
+		domain.NewProduct("Orange Juice", "Pressed from fresh apples and oranges", domain.NewMoney(300, domain.DefaultCurrency), 10, drinks.ID),
+		domain.NewProduct("Apple Scented Soap", "Hand soap", domain.NewMoney(400, domain.DefaultCurrency), 10, cleaning.ID),
+	} {
+		require.NoError(t, postgres.DB.Create(p).Error)
+	}
+
+	t.Run("Success - Prefix Match With Highlights", func(t *testing.T) {
+		page, err := repo.Search(ctx, "appl", domain.ListQuery{})
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), page.Total)
+		require.Len(t, page.Items, 3)
+		// Name matches outrank description matches
+		assert.NotEqual(t, "Orange Juice", page.Items[0].Name)
+		assert.Equal(t, "Orange Juice", page.Items[2].Name)
+		for _, result := range page.Items {
+			assert.False(t, result.Fuzzy)
+		}
+		assert.Contains(t, page.Items[2].Snippet, "<mark>apples</mark>")
+	})
+
+	t.Run("Success - Category Subtree", func(t *testing.T) {
+		page, err := repo.Search(ctx, "appl", domain.ListQuery{
+			Filter: domain.ListFilter{CategoryID: &food.ID},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), page.Total)
+		for _, result := range page.Items {
+			assert.Contains(
+				t,
+				[]uuid.UUID{fruit.ID, drinks.ID},
+				result.CategoryID,
+			)
+		}
+	})
+
+	t.Run("Success - Trigram Fallback", func(t *testing.T) {
+		page, err := repo.Search(ctx, "bananna", domain.ListQuery{})
+
+		require.NoError(t, err)
+		require.Len(t, page.Items, 1)
+		assert.Equal(t, "Banana", page.Items[0].Name)
+		assert.True(t, page.Items[0].Fuzzy)
+	})
+
+	t.Run("Success - No Match", func(t *testing.T) {
+		page, err := repo.Search(ctx, "xyzzy", domain.ListQuery{})
+
+		require.NoError(t, err)
+		assert.Zero(t, page.Total)
+		assert.Empty(t, page.Items)
+	})
+}
+
+func TestMarkHighlights(t *testing.T) {
+	results := []domain.ProductSearchResult{{
+		NameHighlight: "<b>" + highlightStart + "Apple" + highlightStop + "</b>",
+		Snippet:       `<img src=x onerror="alert(1)"> ` + highlightStart + "apples" + highlightStop,
+	}}
+
+	markHighlights(results)
+
+	assert.Equal(t, "&lt;b&gt;<mark>Apple</mark>&lt;/b&gt;", results[0].NameHighlight)
+	assert.Equal(
+		t,
+		"&lt;img src=x onerror=&#34;alert(1)&#34;&gt; <mark>apples</mark>",
+		results[0].Snippet,
+	)
+}
+
 func TestProductRepository_ListByCategoryID(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -244,21 +446,21 @@ func TestProductRepository_ListByCategoryID(t *testing.T) {
 					{
 						ID:         uuid.New(),
 						Name:       "Product 1",
-						Price:      9.99,
+						Price:      domain.NewMoney(999, domain.DefaultCurrency),
 						Stock:      100,
 						CategoryID: categoryID,
 					},
 					{
 						ID:         uuid.New(),
 						Name:       "Product 2",
-						Price:      19.99,
+						Price:      domain.NewMoney(1999, domain.DefaultCurrency),
 						Stock:      200,
 						CategoryID: categoryID,
 					},
 					{
 						ID:         uuid.New(),
 						Name:       "Product 3",
-						Price:      29.99,
+						Price:      domain.NewMoney(2999, domain.DefaultCurrency),
 						Stock:      300,
 						CategoryID: createTestCategory(t, db).ID,
 					},
@@ -281,11 +483,11 @@ func TestProductRepository_ListByCategoryID(t *testing.T) {
 			ctx := context.Background()
 
 			categoryID, expectedProducts := tt.setupTest(postgres.DB)
-			found, err := repo.ListByCategoryID(ctx, categoryID)
+			found, err := repo.ListByCategoryID(ctx, categoryID, domain.ListQuery{})
 
 			assert.NoError(t, err)
-			assert.Len(t, found, tt.expectedCount)
-			for i, p := range found {
+			assert.Len(t, found.Items, tt.expectedCount)
+			for i, p := range found.Items {
 				assert.Equal(t, expectedProducts[i].ID, p.ID)
 				assert.Equal(t, categoryID, p.CategoryID.String())
 			}
@@ -308,7 +510,7 @@ func TestProductRepository_Update(t *testing.T) {
 					ID:          uuid.New(),
 					Name:        "Test Product",
 					Description: "Test Description",
-					Price:       9.99,
+					Price:       domain.NewMoney(999, domain.DefaultCurrency),
 					Stock:       100,
 					CategoryID:  categoryID,
 				}
@@ -317,7 +519,7 @@ func TestProductRepository_Update(t *testing.T) {
 			},
 			updateFunc: func(p *domain.Product) {
 				p.Name = "Updated Product"
-				p.Price = 19.99
+				p.Price = domain.NewMoney(1999, domain.DefaultCurrency)
 			},
 			expectedError: nil,
 		},
@@ -329,7 +531,7 @@ func TestProductRepository_Update(t *testing.T) {
 					ID:         uuid.New(),
 					Name:       "Non-existent Product",
 					CategoryID: categoryID,
-					Price:      9.99,
+					Price:      domain.NewMoney(999, domain.DefaultCurrency),
 					Stock:      100,
 				}
 			},
@@ -377,7 +579,7 @@ func TestProductRepository_Delete(t *testing.T) {
 					ID:          uuid.New(),
 					Name:        "Test Product",
 					Description: "Test Description",
-					Price:       9.99,
+					Price:       domain.NewMoney(999, domain.DefaultCurrency),
 					Stock:       100,
 					CategoryID:  categoryID,
 				}
@@ -419,53 +621,161 @@ func TestProductRepository_Delete(t *testing.T) {
 func TestProductRepository_UpdateStock(t *testing.T) {
 	tests := []struct {
 		name          string
-		setupTest     func(*gorm.DB) (string, int)
+		quantity      int
+		movementType  domain.StockMovementType
+		missing       bool
 		expectedError error
+		expectedStock int
 	}{
 		{
-			name: "Success - Update Stock",
-			setupTest: func(db *gorm.DB) (string, int) {
-				categoryID := createTestCategory(t, db).ID
-				product := &domain.Product{
-					ID:          uuid.New(),
-					Name:        "Test Product",
-					Description: "Test Description",
-					Price:       9.99,
-					Stock:       100,
-					CategoryID:  categoryID,
-				}
-				require.NoError(t, db.Create(product).Error)
-				return product.ID.String(), 50
-			},
-			expectedError: nil,
+			name:          "Success - Restock",
+			quantity:      50,
+			movementType:  domain.StockMovementRestock,
+			expectedStock: 150,
 		},
 		{
-			name: "Error - Product Not Found",
-			setupTest: func(_ *gorm.DB) (string, int) {
-				return uuid.New().String(), 50
-			},
+			name:          "Success - Spoilage",
+			quantity:      -30,
+			movementType:  domain.StockMovementSpoilage,
+			expectedStock: 70,
+		},
+		{
+			name:          "Error - Insufficient Stock",
+			quantity:      -101,
+			movementType:  domain.StockMovementAdjustment,
+			expectedError: customErrors.ErrInsufficientStock,
+			expectedStock: 100,
+		},
+		{
+			name:          "Error - Sign Does Not Match Type",
+			quantity:      -5,
+			movementType:  domain.StockMovementRestock,
+			expectedError: customErrors.ErrInvalidProductData,
+			expectedStock: 100,
+		},
+		{
+			name:          "Error - Product Not Found",
+			quantity:      50,
+			movementType:  domain.StockMovementRestock,
+			missing:       true,
 			expectedError: customErrors.ErrProductNotFound,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			postgres := setupTestDB(t, &domain.Product{}, &domain.Category{})
+			postgres := setupTestDB(
+				t,
+				&domain.Product{},
+				&domain.Category{},
+				&domain.StockMovement{},
+			)
 			repo := NewProductRepository(postgres)
 			ctx := context.Background()
 
-			id, quantity := tt.setupTest(postgres.DB)
-			err := repo.UpdateStock(ctx, id, quantity)
+			categoryID := createTestCategory(t, postgres.DB).ID
+			product := &domain.Product{
+				ID:          uuid.New(),
+				Name:        "Test Product",
+				Description: "Test Description",
+				Price:       domain.NewMoney(999, domain.DefaultCurrency),
+				Stock:       100,
+				CategoryID:  categoryID,
+			}
+			require.NoError(t, postgres.DB.Create(product).Error)
+
+			productID := product.ID
+			if tt.missing {
+				productID = uuid.New()
+			}
+			movement := domain.NewStockMovement(
+				productID,
+				tt.movementType,
+				tt.quantity,
+			)
+			err := repo.UpdateStock(ctx, movement)
+
+			var movements []domain.StockMovement
+			require.NoError(t, postgres.DB.
+				Where("product_id = ?", productID).
+				Find(&movements).Error)
 
 			if tt.expectedError != nil {
 				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Empty(t, movements)
 			} else {
 				assert.NoError(t, err)
+				require.Len(t, movements, 1)
+				assert.Equal(t, tt.movementType, movements[0].Type)
+				assert.Equal(t, tt.quantity, movements[0].Quantity)
+			}
+
+			if !tt.missing {
 				var found domain.Product
-				err = postgres.DB.First(&found, "id = ?", id).Error
+				err = postgres.DB.First(&found, "id = ?", product.ID).Error
 				assert.NoError(t, err)
-				assert.Equal(t, 150, found.Stock)
+				assert.Equal(t, tt.expectedStock, found.Stock)
 			}
 		})
 	}
 }
+
+func TestProductRepository_LowStock(t *testing.T) {
+	postgres := setupTestDB(
+		t,
+		&domain.Product{},
+		&domain.Category{},
+		&domain.StockMovement{},
+		&domain.OutboxEvent{},
+	)
+	repo := NewProductRepository(postgres)
+	ctx := context.Background()
+
+	product := createTestProduct(t, postgres.DB)
+	require.NoError(t, postgres.DB.Model(product).Updates(map[string]any{
+		"reorder_point":    10,
+		"reorder_quantity": 50,
+	}).Error)
+	untracked := createTestProduct(t, postgres.DB)
+
+	lowStockEvents := func() []domain.OutboxEvent {
+		var events []domain.OutboxEvent
+		require.NoError(t, postgres.DB.
+			Where("event_type = ?", domain.OutboxEventLowStock).
+			Find(&events).Error)
+		return events
+	}
+	spoil := func(id uuid.UUID, quantity int) {
+		require.NoError(t, repo.UpdateStock(ctx, domain.NewStockMovement(
+			id,
+			domain.StockMovementSpoilage,
+			-quantity,
+		)))
+	}
+
+	// Above the reorder point nothing is reported.
+	spoil(product.ID, 50)
+	assert.Empty(t, lowStockEvents())
+
+	// Reaching it records one alert with the new stock level.
+	spoil(product.ID, 41)
+	events := lowStockEvents()
+	require.Len(t, events, 1)
+	alert, err := events[0].LowStockPayload()
+	require.NoError(t, err)
+	assert.Equal(t, product.ID, alert.ProductID)
+	assert.Equal(t, 9, alert.Stock)
+	assert.Equal(t, 50, alert.ReorderQuantity)
+
+	// Dropping further while already low does not alert again, and
+	// products without a reorder point never alert.
+	spoil(product.ID, 5)
+	spoil(untracked.ID, 99)
+	assert.Len(t, lowStockEvents(), 1)
+
+	page, err := repo.ListLowStock(ctx, domain.ListQuery{})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, product.ID, page.Items[0].ID)
+	assert.Equal(t, 4, page.Items[0].Stock)
+}
@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grocery-service/internal/repository/db"
+	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/grocery-service/utils/ratelimit"
+	"gorm.io/gorm"
+)
+
+// RateLimitRepositoryImpl is a ratelimit.Store that keeps buckets in
+// Postgres, so that every replica enforces the same limits.
+type RateLimitRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// rateLimitBucket is a row of rate_limit_buckets. FullAt is when the
+// bucket will have refilled, after which it can be pruned.
+type rateLimitBucket struct {
+	Key       string    `gorm:"type:varchar(255);primaryKey"`
+	Tokens    float64   `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"not null"`
+	FullAt    time.Time `gorm:"not null;index"`
+}
+
+func (rateLimitBucket) TableName() string {
+	return "rate_limit_buckets"
+}
+
+func NewRateLimitRepository(
+	postgres *db.PostgresDB,
+) *RateLimitRepositoryImpl {
+	return &RateLimitRepositoryImpl{db: postgres.DB}
+}
+
+func (r *RateLimitRepositoryImpl) Take(
+	ctx context.Context,
+	key string,
+	policy ratelimit.Policy,
+	now time.Time,
+) (ratelimit.Result, error) {
+	var result ratelimit.Result
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Creates the bucket full when it is missing and locks it
+		// either way, so that concurrent requests queue up on it.
+		var row rateLimitBucket
+		if err := tx.Raw(
+			`INSERT INTO rate_limit_buckets
+				(key, tokens, updated_at, full_at)
+			VALUES (?, ?, ?, ?)
+			ON CONFLICT (key) DO UPDATE SET key = EXCLUDED.key
+			RETURNING key, tokens, updated_at, full_at`,
+			key,
+			float64(policy.Limit),
+			now,
+			now,
+		).Scan(&row).Error; err != nil {
+			return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+		}
+
+		var next ratelimit.Bucket
+		next, result = ratelimit.Take(&ratelimit.Bucket{
+			Tokens:    row.Tokens,
+			UpdatedAt: row.UpdatedAt,
+		}, policy, now)
+
+		if err := tx.Model(&rateLimitBucket{}).
+			Where("key = ?", key).
+			Updates(map[string]interface{}{
+				"tokens":     next.Tokens,
+				"updated_at": next.UpdatedAt,
+				"full_at":    now.Add(result.ResetAfter),
+			}).Error; err != nil {
+			return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return ratelimit.Result{}, err
+	}
+
+	return result, nil
+}
+
+func (r *RateLimitRepositoryImpl) Prune(
+	ctx context.Context,
+	now time.Time,
+) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("full_at <= ?", now).
+		Delete(&rateLimitBucket{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("%w: %v", customErrors.ErrDBQuery, result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grocery-service/utils/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitRepositoryImpl(t *testing.T) {
+	postgres := setupTestDB(t, &rateLimitBucket{})
+	repo := NewRateLimitRepository(postgres)
+	ctx := context.Background()
+	policy := ratelimit.Policy{Name: "auth", Limit: 3, Window: time.Minute}
+
+	t.Run("Take spends and refills tokens", func(t *testing.T) {
+		now := time.Now()
+
+		for i := 2; i >= 0; i-- {
+			result, err := repo.Take(ctx, "auth:192.0.2.1", policy, now)
+			require.NoError(t, err)
+			assert.True(t, result.Allowed)
+			assert.Equal(t, i, result.Remaining)
+		}
+
+		result, err := repo.Take(ctx, "auth:192.0.2.1", policy, now)
+		require.NoError(t, err)
+		assert.False(t, result.Allowed)
+		assert.Equal(t, 20*time.Second, result.RetryAfter.Round(time.Second))
+
+		result, err = repo.Take(
+			ctx,
+			"auth:192.0.2.1",
+			policy,
+			now.Add(20*time.Second),
+		)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+
+		result, err = repo.Take(ctx, "auth:192.0.2.2", policy, now)
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Remaining)
+	})
+
+	t.Run("Concurrent takes share one bucket", func(t *testing.T) {
+		now := time.Now()
+		var (
+			wg      sync.WaitGroup
+			mu      sync.Mutex
+			allowed int
+		)
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				result, err := repo.Take(ctx, "auth:198.51.100.1", policy, now)
+				assert.NoError(t, err)
+				if result.Allowed {
+					mu.Lock()
+					allowed++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, policy.Limit, allowed)
+	})
+
+	t.Run("Prune deletes full buckets", func(t *testing.T) {
+		now := time.Now()
+		_, err := repo.Take(ctx, "auth:203.0.113.1", policy, now)
+		require.NoError(t, err)
+
+		pruned, err := repo.Prune(ctx, now)
+		require.NoError(t, err)
+		assert.Zero(t, pruned)
+
+		pruned, err = repo.Prune(ctx, now.Add(time.Minute))
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, pruned, int64(1))
+	})
+}
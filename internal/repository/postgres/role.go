@@ -0,0 +1,290 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grocery-service/internal/domain"
+	"github.com/grocery-service/internal/repository/db"
+	customErrors "github.com/grocery-service/utils/errors"
+	"gorm.io/gorm"
+)
+
+type (
+	// RoleRepository stores roles with the permissions granted to them.
+	// Roles are returned with Permissions filled in.
+	RoleRepository interface {
+		List(ctx context.Context) ([]domain.Role, error)
+		GetByName(ctx context.Context, name domain.UserRole) (*domain.Role, error)
+		Create(ctx context.Context, role *domain.Role) error
+		// Update replaces the description and permissions of a role.
+		Update(ctx context.Context, role *domain.Role) error
+		// Delete removes a role that no user holds.
+		Delete(ctx context.Context, name domain.UserRole) error
+		// AssignRole gives a user a role.
+		AssignRole(
+			ctx context.Context,
+			userID string,
+			role domain.UserRole,
+		) error
+	}
+
+	RoleRepositoryImpl struct {
+		*db.BaseRepository[domain.Role]
+	}
+)
+
+func NewRoleRepository(
+	postgres *db.PostgresDB,
+) *RoleRepositoryImpl {
+	return &RoleRepositoryImpl{
+		BaseRepository: db.NewBaseRepository[domain.Role](
+			postgres,
+		),
+	}
+}
+
+func (r *RoleRepositoryImpl) List(
+	ctx context.Context,
+) ([]domain.Role, error) {
+	var roles []domain.Role
+	if err := r.BaseRepository.GetDB().WithContext(ctx).
+		Order("name").
+		Find(&roles).Error; err != nil {
+		return nil, fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+	}
+
+	var grants []domain.RolePermission
+	if err := r.BaseRepository.GetDB().WithContext(ctx).
+		Order("permission").
+		Find(&grants).Error; err != nil {
+		return nil, fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+	}
+
+	byRole := make(map[domain.UserRole][]domain.Permission, len(roles))
+	for _, g := range grants {
+		byRole[g.Role] = append(byRole[g.Role], g.Permission)
+	}
+	for i := range roles {
+		roles[i].Permissions = rolePermissions(roles[i].Name, byRole[roles[i].Name])
+	}
+
+	return roles, nil
+}
+
+func (r *RoleRepositoryImpl) GetByName(
+	ctx context.Context,
+	name domain.UserRole,
+) (*domain.Role, error) {
+	var role domain.Role
+	err := r.BaseRepository.GetDB().WithContext(ctx).
+		First(&role, "name = ?", name).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, customErrors.ErrRoleNotFound
+		}
+		return nil, fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+	}
+
+	var permissions []domain.Permission
+	if err := r.BaseRepository.GetDB().WithContext(ctx).
+		Model(&domain.RolePermission{}).
+		Where("role = ?", name).
+		Order("permission").
+		Pluck("permission", &permissions).Error; err != nil {
+		return nil, fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+	}
+	role.Permissions = rolePermissions(role.Name, permissions)
+
+	return &role, nil
+}
+
+func (r *RoleRepositoryImpl) Create(
+	ctx context.Context,
+	role *domain.Role,
+) error {
+	return r.BaseRepository.WithTransaction(
+		ctx,
+		func(txRepo *db.BaseRepository[domain.Role]) error {
+			var exists bool
+			if err := txRepo.GetDB().WithContext(ctx).
+				Model(&domain.Role{}).
+				Select("count(*) > 0").
+				Where("name = ?", role.Name).
+				Find(&exists).Error; err != nil {
+				return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+			}
+			if exists {
+				return customErrors.ErrRoleExists
+			}
+
+			if err := txRepo.GetDB().WithContext(ctx).
+				Create(role).Error; err != nil {
+				return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+			}
+
+			return grantPermissions(ctx, txRepo.GetDB(), role)
+		},
+	)
+}
+
+func (r *RoleRepositoryImpl) Update(
+	ctx context.Context,
+	role *domain.Role,
+) error {
+	return r.BaseRepository.WithTransaction(
+		ctx,
+		func(txRepo *db.BaseRepository[domain.Role]) error {
+			result := txRepo.GetDB().WithContext(ctx).
+				Model(&domain.Role{}).
+				Where("name = ?", role.Name).
+				Updates(map[string]interface{}{
+					"description": role.Description,
+					"updated_at":  gorm.Expr("CURRENT_TIMESTAMP"),
+				})
+			if result.Error != nil {
+				return fmt.Errorf(
+					"%w: %v",
+					customErrors.ErrDBQuery,
+					result.Error,
+				)
+			}
+			if result.RowsAffected == 0 {
+				return customErrors.ErrRoleNotFound
+			}
+
+			if err := txRepo.GetDB().WithContext(ctx).
+				Where("role = ?", role.Name).
+				Delete(&domain.RolePermission{}).Error; err != nil {
+				return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+			}
+
+			return grantPermissions(ctx, txRepo.GetDB(), role)
+		},
+	)
+}
+
+func (r *RoleRepositoryImpl) Delete(
+	ctx context.Context,
+	name domain.UserRole,
+) error {
+	return r.BaseRepository.WithTransaction(
+		ctx,
+		func(txRepo *db.BaseRepository[domain.Role]) error {
+			var inUse bool
+			if err := txRepo.GetDB().WithContext(ctx).
+				Model(&domain.User{}).
+				Select("count(*) > 0").
+				Where("role = ?", name).
+				Find(&inUse).Error; err != nil {
+				return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+			}
+			if inUse {
+				return customErrors.ErrRoleInUse
+			}
+
+			result := txRepo.GetDB().WithContext(ctx).
+				Delete(&domain.Role{}, "name = ?", name)
+			if result.Error != nil {
+				return fmt.Errorf(
+					"%w: %v",
+					customErrors.ErrDBQuery,
+					result.Error,
+				)
+			}
+			if result.RowsAffected == 0 {
+				return customErrors.ErrRoleNotFound
+			}
+
+			return nil
+		},
+	)
+}
+
+func (r *RoleRepositoryImpl) AssignRole(
+	ctx context.Context,
+	userID string,
+	role domain.UserRole,
+) error {
+	return r.BaseRepository.WithTransaction(
+		ctx,
+		func(txRepo *db.BaseRepository[domain.Role]) error {
+			var exists bool
+			if err := txRepo.GetDB().WithContext(ctx).
+				Model(&domain.Role{}).
+				Select("count(*) > 0").
+				Where("name = ?", role).
+				Find(&exists).Error; err != nil {
+				return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+			}
+			if !exists {
+				return customErrors.ErrRoleNotFound
+			}
+
+			result := txRepo.GetDB().WithContext(ctx).
+				Model(&domain.User{}).
+				Where("id = ?", userID).
+				Updates(map[string]interface{}{
+					"role":       role,
+					"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+				})
+			if result.Error != nil {
+				return fmt.Errorf(
+					"%w: %v",
+					customErrors.ErrDBQuery,
+					result.Error,
+				)
+			}
+			if result.RowsAffected == 0 {
+				return customErrors.ErrUserNotFound
+			}
+
+			return nil
+		},
+	)
+}
+
+func grantPermissions(
+	ctx context.Context,
+	tx *gorm.DB,
+	role *domain.Role,
+) error {
+	if len(role.Permissions) == 0 {
+		return nil
+	}
+
+	grants := make([]domain.RolePermission, 0, len(role.Permissions))
+	for _, p := range role.Permissions {
+		grants = append(grants, domain.RolePermission{
+			Role:       role.Name,
+			Permission: p,
+		})
+	}
+
+	if err := tx.WithContext(ctx).Create(&grants).Error; err != nil {
+		return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+	}
+	return nil
+}
+
+// rolePermissions fills in the admin role, which holds every permission
+// without listing them.
+func rolePermissions(
+	name domain.UserRole,
+	granted []domain.Permission,
+) []domain.Permission {
+	if name != domain.AdminRole {
+		if granted == nil {
+			return []domain.Permission{}
+		}
+		return granted
+	}
+
+	all := domain.AllPermissions()
+	permissions := make([]domain.Permission, 0, len(all))
+	for _, p := range all {
+		permissions = append(permissions, p.Name)
+	}
+	return permissions
+}
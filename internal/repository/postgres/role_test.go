@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/grocery-service/internal/domain"
+	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoleRepositoryImpl(t *testing.T) {
+	postgres := setupTestDB(t, &domain.Role{}, &domain.RolePermission{})
+	repo := NewRoleRepository(postgres)
+	ctx := context.Background()
+
+	require.NoError(t, postgres.DB.Create(&[]domain.Role{
+		{Name: domain.AdminRole, BuiltIn: true},
+		{Name: domain.CustomerRole, BuiltIn: true},
+	}).Error)
+
+	t.Run("Create and GetByName", func(t *testing.T) {
+		role := &domain.Role{
+			Name:        "stock-clerk",
+			Description: "Counts stock",
+			Permissions: []domain.Permission{
+				domain.PermissionInventoryWrite,
+				domain.PermissionInventoryRead,
+			},
+		}
+		require.NoError(t, repo.Create(ctx, role))
+
+		got, err := repo.GetByName(ctx, "stock-clerk")
+		require.NoError(t, err)
+		assert.Equal(t, "Counts stock", got.Description)
+		assert.Equal(t, []domain.Permission{
+			domain.PermissionInventoryRead,
+			domain.PermissionInventoryWrite,
+		}, got.Permissions)
+
+		err = repo.Create(ctx, &domain.Role{Name: "stock-clerk"})
+		assert.ErrorIs(t, err, customErrors.ErrRoleExists)
+	})
+
+	t.Run("Admin holds every permission", func(t *testing.T) {
+		got, err := repo.GetByName(ctx, domain.AdminRole)
+		require.NoError(t, err)
+		assert.Len(t, got.Permissions, len(domain.Permissions))
+	})
+
+	t.Run("Update replaces permissions", func(t *testing.T) {
+		require.NoError(t, repo.Update(ctx, &domain.Role{
+			Name:        "stock-clerk",
+			Permissions: []domain.Permission{domain.PermissionOrdersRead},
+		}))
+
+		got, err := repo.GetByName(ctx, "stock-clerk")
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			[]domain.Permission{domain.PermissionOrdersRead},
+			got.Permissions,
+		)
+
+		err = repo.Update(ctx, &domain.Role{Name: "ghost"})
+		assert.ErrorIs(t, err, customErrors.ErrRoleNotFound)
+	})
+
+	t.Run("AssignRole and Delete", func(t *testing.T) {
+		user := createTestUser(t, postgres.DB)
+
+		require.NoError(t, repo.AssignRole(ctx, user.ID.String(), "stock-clerk"))
+		assert.ErrorIs(
+			t,
+			repo.Delete(ctx, "stock-clerk"),
+			customErrors.ErrRoleInUse,
+		)
+
+		assert.ErrorIs(
+			t,
+			repo.AssignRole(ctx, user.ID.String(), "ghost"),
+			customErrors.ErrRoleNotFound,
+		)
+		assert.ErrorIs(
+			t,
+			repo.AssignRole(ctx, uuid.NewString(), domain.CustomerRole),
+			customErrors.ErrUserNotFound,
+		)
+
+		require.NoError(t, repo.AssignRole(ctx, user.ID.String(), domain.CustomerRole))
+		require.NoError(t, repo.Delete(ctx, "stock-clerk"))
+
+		_, err := repo.GetByName(ctx, "stock-clerk")
+		assert.ErrorIs(t, err, customErrors.ErrRoleNotFound)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		roles, err := repo.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, roles, 2)
+		assert.Equal(t, domain.AdminRole, roles[0].Name)
+		assert.Equal(t, domain.CustomerRole, roles[1].Name)
+		assert.Empty(t, roles[1].Permissions)
+	})
+}
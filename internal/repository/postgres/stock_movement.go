@@ -0,0 +1,152 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/grocery-service/internal/domain"
+	"github.com/grocery-service/internal/repository/db"
+	customErrors "github.com/grocery-service/utils/errors"
+	"gorm.io/gorm"
+)
+
+type (
+	StockMovementRepository interface {
+		ListByProductID(
+			ctx context.Context,
+			productID string,
+			query domain.ListQuery,
+		) (*domain.Page[domain.StockMovement], error)
+		// LedgerStock returns the sum of a product's movements.
+		LedgerStock(ctx context.Context, productID string) (int, error)
+		// FindDrift returns every product whose cached stock differs from
+		// its ledger.
+		FindDrift(ctx context.Context) ([]domain.StockDrift, error)
+		// SyncStock overwrites the cached stock of a product with its
+		// ledger sum.
+		SyncStock(ctx context.Context, productID string) error
+	}
+
+	StockMovementRepositoryImpl struct {
+		*db.BaseRepository[domain.StockMovement]
+	}
+)
+
+var stockMovementListSpec = listSpec[domain.StockMovement]{
+	table:       "stock_movements",
+	defaultSort: "created_at",
+	sortFields: map[string]sortField[domain.StockMovement]{
+		"created_at": {
+			column: "stock_movements.created_at",
+			value:  func(m domain.StockMovement) any { return m.CreatedAt },
+		},
+		"quantity": {
+			column: "stock_movements.quantity",
+			value:  func(m domain.StockMovement) any { return m.Quantity },
+		},
+	},
+	id: func(m domain.StockMovement) uuid.UUID { return m.ID },
+}
+
+// ledgerSum is the stock implied by the ledger for the product in the
+// outer query.
+const ledgerSum = "COALESCE((SELECT SUM(stock_movements.quantity) " +
+	"FROM stock_movements " +
+	"WHERE stock_movements.product_id = products.id), 0)"
+
+func NewStockMovementRepository(
+	postgres *db.PostgresDB,
+) *StockMovementRepositoryImpl {
+	return &StockMovementRepositoryImpl{
+		BaseRepository: db.NewBaseRepository[domain.StockMovement](
+			postgres,
+		),
+	}
+}
+
+// recordStockMovements appends movements to the ledger inside the
+// caller's transaction, which must also apply them to products.stock.
+func recordStockMovements(
+	ctx context.Context,
+	tx *gorm.DB,
+	movements []*domain.StockMovement,
+) error {
+	if len(movements) == 0 {
+		return nil
+	}
+
+	if err := tx.WithContext(ctx).Create(movements).Error; err != nil {
+		return fmt.Errorf(
+			"%w: failed to record stock movements: %v",
+			customErrors.ErrDBQuery,
+			err,
+		)
+	}
+	return nil
+}
+
+func (r *StockMovementRepositoryImpl) ListByProductID(
+	ctx context.Context,
+	productID string,
+	query domain.ListQuery,
+) (*domain.Page[domain.StockMovement], error) {
+	db := r.BaseRepository.GetDB().WithContext(ctx).
+		Model(&domain.StockMovement{}).
+		Where("stock_movements.product_id = ?", productID)
+	db = applyCreatedRange(db, "stock_movements", query.Filter)
+
+	return listPage(db, query, stockMovementListSpec)
+}
+
+func (r *StockMovementRepositoryImpl) LedgerStock(
+	ctx context.Context,
+	productID string,
+) (int, error) {
+	var stock int
+	if err := r.BaseRepository.GetDB().WithContext(ctx).
+		Model(&domain.StockMovement{}).
+		Where("product_id = ?", productID).
+		Select("COALESCE(SUM(quantity), 0)").
+		Scan(&stock).Error; err != nil {
+		return 0, fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+	}
+	return stock, nil
+}
+
+func (r *StockMovementRepositoryImpl) FindDrift(
+	ctx context.Context,
+) ([]domain.StockDrift, error) {
+	var drift []domain.StockDrift
+	if err := r.BaseRepository.GetDB().WithContext(ctx).
+		Table("products").
+		Select(
+			"products.id AS product_id, products.name, " +
+				"products.stock AS cached_stock, " +
+				ledgerSum + " AS ledger_stock",
+		).
+		Where("products.stock <> " + ledgerSum).
+		Order("products.name").
+		Scan(&drift).Error; err != nil {
+		return nil, fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+	}
+	return drift, nil
+}
+
+func (r *StockMovementRepositoryImpl) SyncStock(
+	ctx context.Context,
+	productID string,
+) error {
+	result := r.BaseRepository.GetDB().WithContext(ctx).
+		Model(&domain.Product{}).
+		Where("id = ?", productID).
+		Update("stock", gorm.Expr(ledgerSum))
+	if result.Error != nil {
+		return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return customErrors.ErrProductNotFound
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/grocery-service/internal/domain"
+	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStockMovementRepository(t *testing.T) {
+	postgres := setupTestDB(
+		t,
+		&domain.Product{},
+		&domain.Category{},
+		&domain.StockMovement{},
+	)
+	repo := NewStockMovementRepository(postgres)
+	productRepo := NewProductRepository(postgres)
+	ctx := context.Background()
+
+	// The helper writes the product directly, so its 100 units have no
+	// movement behind them yet.
+	product := createTestProduct(t, postgres.DB)
+
+	t.Run("FindDrift - Unrecorded Stock", func(t *testing.T) {
+		drift, err := repo.FindDrift(ctx)
+
+		require.NoError(t, err)
+		require.Len(t, drift, 1)
+		assert.Equal(t, product.ID, drift[0].ProductID)
+		assert.Equal(t, 100, drift[0].CachedStock)
+		assert.Equal(t, 0, drift[0].LedgerStock)
+	})
+
+	t.Run("Movements Keep Stock In Sync", func(t *testing.T) {
+		require.NoError(t, postgres.DB.Create(domain.NewStockMovement(
+			product.ID,
+			domain.StockMovementAdjustment,
+			100,
+		)).Error)
+		require.NoError(t, productRepo.UpdateStock(ctx, domain.NewStockMovement(
+			product.ID,
+			domain.StockMovementSpoilage,
+			-4,
+		)))
+
+		ledger, err := repo.LedgerStock(ctx, product.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, 96, ledger)
+
+		drift, err := repo.FindDrift(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, drift)
+	})
+
+	t.Run("ListByProductID", func(t *testing.T) {
+		page, err := repo.ListByProductID(
+			ctx,
+			product.ID.String(),
+			domain.ListQuery{SortBy: "created_at", SortDir: domain.SortDesc},
+		)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), page.Total)
+		require.Len(t, page.Items, 2)
+		assert.Equal(t, domain.StockMovementSpoilage, page.Items[0].Type)
+	})
+
+	t.Run("SyncStock", func(t *testing.T) {
+		require.NoError(t, postgres.DB.Model(product).
+			Update("stock", 7).Error)
+
+		drift, err := repo.FindDrift(ctx)
+		require.NoError(t, err)
+		require.Len(t, drift, 1)
+		assert.Equal(t, 7, drift[0].CachedStock)
+		assert.Equal(t, 96, drift[0].LedgerStock)
+
+		require.NoError(t, repo.SyncStock(ctx, product.ID.String()))
+
+		found, err := productRepo.GetByID(ctx, product.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, 96, found.Stock)
+	})
+
+	t.Run("SyncStock - Not Found", func(t *testing.T) {
+		err := repo.SyncStock(ctx, uuid.New().String())
+
+		assert.ErrorIs(t, err, customErrors.ErrProductNotFound)
+	})
+}
@@ -94,7 +94,7 @@ func createTestProduct(
 		ID:          uuid.New(),
 		Name:        "Test Product",
 		Description: "Test Description",
-		Price:       10.00,
+		Price:       domain.NewMoney(1000, domain.DefaultCurrency),
 		Stock:       100,
 		CategoryID:  category.ID,
 	}
@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/grocery-service/internal/domain"
 	"github.com/grocery-service/internal/repository/db"
 	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/grocery-service/utils/hash"
 	"gorm.io/gorm"
 )
 
@@ -16,35 +18,81 @@ type (
 	TokenRepository interface {
 		Create(ctx context.Context, token *domain.Token) error
 		GetByToken(ctx context.Context, token string) (*domain.Token, error)
+		// GetByTokenIncludingRevoked also finds revoked tokens, so that
+		// reuse of a rotated refresh token can be detected.
+		GetByTokenIncludingRevoked(
+			ctx context.Context,
+			token string,
+		) (*domain.Token, error)
+		// GetByUserAndType lists the user's live tokens of a type, most
+		// recently used first.
 		GetByUserAndType(
 			ctx context.Context,
 			userID string,
 			tokenType domain.TokenType,
-		) (*domain.Token, error)
+		) ([]domain.Token, error)
 		GetByProviderID(
 			ctx context.Context,
 			providerID string,
 		) (*domain.Token, error)
 		RevokeToken(ctx context.Context, token string) error
-		DeleteExpiredTokens(ctx context.Context) error
+		// ConsumeToken revokes a live token of tokenType and returns
+		// it, so that it can be used only once. It fails with
+		// ErrTokenNotFound when there is no such token.
+		ConsumeToken(
+			ctx context.Context,
+			token string,
+			tokenType domain.TokenType,
+		) (*domain.Token, error)
+		// RotateToken revokes current and creates next in one
+		// transaction. It fails with ErrTokenNotFound when current was
+		// already revoked.
+		RotateToken(
+			ctx context.Context,
+			current *domain.Token,
+			next *domain.Token,
+		) error
+		// RevokeFamily revokes every live token of a token family and
+		// returns how many were revoked.
+		RevokeFamily(ctx context.Context, familyID string) (int64, error)
+		// RevokeUserTokens revokes every live token of a user and
+		// returns how many were revoked.
+		RevokeUserTokens(ctx context.Context, userID string) (int64, error)
+		// DeleteExpiredTokens deletes up to limit tokens that have
+		// expired or were revoked before revokedBefore, and returns how
+		// many were deleted.
+		DeleteExpiredTokens(
+			ctx context.Context,
+			revokedBefore time.Time,
+			limit int,
+		) (int64, error)
 		IsValid(ctx context.Context, token string) bool
 	}
 
+	// TokenRepositoryImpl keeps only an HMAC of each token, keyed with
+	// hashKey, so a leaked tokens table does not hand out live sessions.
 	TokenRepositoryImpl struct {
 		*db.BaseRepository[domain.Token]
+		hashKey []byte
 	}
 )
 
 func NewTokenRepository(
 	postgres *db.PostgresDB,
+	hashKey string,
 ) *TokenRepositoryImpl {
 	return &TokenRepositoryImpl{
 		BaseRepository: db.NewBaseRepository[domain.Token](
 			postgres,
 		),
+		hashKey: []byte(hashKey),
 	}
 }
 
+func (r *TokenRepositoryImpl) hash(token string) string {
+	return hash.Token(r.hashKey, token)
+}
+
 func (r *TokenRepositoryImpl) Create(
 	ctx context.Context,
 	token *domain.Token,
@@ -52,6 +100,7 @@ func (r *TokenRepositoryImpl) Create(
 	return r.BaseRepository.WithTransaction(
 		ctx,
 		func(txRepo *db.BaseRepository[domain.Token]) error {
+			token.TokenHash = r.hash(token.Token)
 			if err := txRepo.GetDB().WithContext(ctx).Create(token).Error; err != nil {
 				return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
 			}
@@ -67,7 +116,7 @@ func (r *TokenRepositoryImpl) GetByToken(
 ) (*domain.Token, error) {
 	var t domain.Token
 	err := r.BaseRepository.GetDB().WithContext(ctx).
-		Where("token = ? AND revoked_at IS NULL", token).
+		Where("token_hash = ? AND revoked_at IS NULL", r.hash(token)).
 		First(&t).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -80,15 +129,13 @@ func (r *TokenRepositoryImpl) GetByToken(
 	return &t, nil
 }
 
-func (r *TokenRepositoryImpl) GetByUserAndType(
+func (r *TokenRepositoryImpl) GetByTokenIncludingRevoked(
 	ctx context.Context,
-	userID string,
-	tokenType domain.TokenType,
+	token string,
 ) (*domain.Token, error) {
 	var t domain.Token
 	err := r.BaseRepository.GetDB().WithContext(ctx).
-		Where("user_id = ? AND type = ? AND expires_at > ? AND revoked_at IS NULL",
-			userID, tokenType, time.Now()).
+		Where("token_hash = ?", r.hash(token)).
 		First(&t).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -101,6 +148,24 @@ func (r *TokenRepositoryImpl) GetByUserAndType(
 	return &t, nil
 }
 
+func (r *TokenRepositoryImpl) GetByUserAndType(
+	ctx context.Context,
+	userID string,
+	tokenType domain.TokenType,
+) ([]domain.Token, error) {
+	var tokens []domain.Token
+	err := r.BaseRepository.GetDB().WithContext(ctx).
+		Where("user_id = ? AND type = ? AND expires_at > ? AND revoked_at IS NULL",
+			userID, tokenType, time.Now()).
+		Order("last_used_at DESC").
+		Find(&tokens).Error
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+	}
+
+	return tokens, nil
+}
+
 func (r *TokenRepositoryImpl) GetByProviderID(
 	ctx context.Context,
 	providerID string,
@@ -129,7 +194,7 @@ func (r *TokenRepositoryImpl) RevokeToken(
 		func(txRepo *db.BaseRepository[domain.Token]) error {
 			result := txRepo.GetDB().WithContext(ctx).
 				Model(&domain.Token{}).
-				Where("token = ? AND revoked_at IS NULL", token).
+				Where("token_hash = ? AND revoked_at IS NULL", r.hash(token)).
 				Update("revoked_at", now)
 
 			if result.Error != nil {
@@ -149,16 +214,62 @@ func (r *TokenRepositoryImpl) RevokeToken(
 	)
 }
 
-func (r *TokenRepositoryImpl) DeleteExpiredTokens(
+func (r *TokenRepositoryImpl) ConsumeToken(
 	ctx context.Context,
+	token string,
+	tokenType domain.TokenType,
+) (*domain.Token, error) {
+	now := time.Now()
+	var t domain.Token
+	result := r.BaseRepository.GetDB().WithContext(ctx).Raw(
+		`UPDATE tokens SET revoked_at = ?
+		WHERE token_hash = ? AND type = ?
+			AND revoked_at IS NULL AND expires_at > ?
+		RETURNING *`,
+		now,
+		r.hash(token),
+		tokenType,
+		now,
+	).Scan(&t)
+	if result.Error != nil {
+		return nil, fmt.Errorf("%w: %v", customErrors.ErrDBQuery, result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, customErrors.ErrTokenNotFound
+	}
+
+	return &t, nil
+}
+
+func (r *TokenRepositoryImpl) RotateToken(
+	ctx context.Context,
+	current *domain.Token,
+	next *domain.Token,
 ) error {
+	now := time.Now()
 	return r.BaseRepository.WithTransaction(
 		ctx,
 		func(txRepo *db.BaseRepository[domain.Token]) error {
-			if err := txRepo.GetDB().WithContext(ctx).
-				Where("expires_at < ? OR revoked_at IS NOT NULL",
-					time.Now()).
-				Delete(&domain.Token{}).Error; err != nil {
+			result := txRepo.GetDB().WithContext(ctx).
+				Model(&domain.Token{}).
+				Where("id = ? AND revoked_at IS NULL", current.ID).
+				Update("revoked_at", now)
+
+			if result.Error != nil {
+				return fmt.Errorf(
+					"%w: %v",
+					customErrors.ErrDBQuery,
+					result.Error,
+				)
+			}
+
+			if result.RowsAffected == 0 {
+				return customErrors.ErrTokenNotFound
+			}
+
+			next.TokenHash = r.hash(next.Token)
+			if err := txRepo.GetDB().WithContext(ctx).Create(next).Error; err != nil {
 				return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
 			}
 
@@ -167,15 +278,141 @@ func (r *TokenRepositoryImpl) DeleteExpiredTokens(
 	)
 }
 
+func (r *TokenRepositoryImpl) RevokeFamily(
+	ctx context.Context,
+	familyID string,
+) (int64, error) {
+	result := r.BaseRepository.GetDB().WithContext(ctx).
+		Model(&domain.Token{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return 0, fmt.Errorf(
+			"%w: %v",
+			customErrors.ErrDBQuery,
+			result.Error,
+		)
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (r *TokenRepositoryImpl) RevokeUserTokens(
+	ctx context.Context,
+	userID string,
+) (int64, error) {
+	result := r.BaseRepository.GetDB().WithContext(ctx).
+		Model(&domain.Token{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return 0, fmt.Errorf(
+			"%w: %v",
+			customErrors.ErrDBQuery,
+			result.Error,
+		)
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (r *TokenRepositoryImpl) DeleteExpiredTokens(
+	ctx context.Context,
+	revokedBefore time.Time,
+	limit int,
+) (int64, error) {
+	// Deleting a bounded batch keeps each statement short, so it neither
+	// holds row locks for long nor blocks logins and refreshes.
+	result := r.BaseRepository.GetDB().WithContext(ctx).Exec(
+		`DELETE FROM tokens WHERE id IN (
+			SELECT id FROM tokens
+			WHERE expires_at < ? OR revoked_at < ?
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)`,
+		time.Now(),
+		revokedBefore,
+		limit,
+	)
+	if result.Error != nil {
+		return 0, fmt.Errorf("%w: %v", customErrors.ErrDBQuery, result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
 func (r *TokenRepositoryImpl) IsValid(
 	ctx context.Context,
 	token string,
 ) bool {
 	var t domain.Token
 	err := r.BaseRepository.GetDB().WithContext(ctx).
-		Where("token = ? AND expires_at > ? AND revoked_at IS NULL",
-			token, time.Now()).
+		Where("token_hash = ? AND expires_at > ? AND revoked_at IS NULL",
+			r.hash(token), time.Now()).
 		First(&t).Error
 
 	return err == nil
 }
+
+// HashLegacyTokens hashes tokens stored in plaintext before tokens were
+// hashed at rest, batchSize rows per transaction, and clears the
+// plaintext. It returns the number of tokens hashed.
+func (r *TokenRepositoryImpl) HashLegacyTokens(
+	ctx context.Context,
+	batchSize int,
+) (int, error) {
+	// Nothing is left to hash once the plaintext column is dropped
+	if !r.BaseRepository.GetDB().Migrator().
+		HasColumn(&domain.Token{}, "legacy_token") {
+		return 0, nil
+	}
+
+	hashed := 0
+	for {
+		var rows []struct {
+			ID    uuid.UUID
+			Token string
+		}
+
+		err := r.BaseRepository.WithTransaction(
+			ctx,
+			func(txRepo *db.BaseRepository[domain.Token]) error {
+				tx := txRepo.GetDB().WithContext(ctx)
+				if err := tx.Raw(
+					`SELECT id, legacy_token AS token FROM tokens
+					WHERE legacy_token IS NOT NULL
+					LIMIT ? FOR UPDATE SKIP LOCKED`,
+					batchSize,
+				).Scan(&rows).Error; err != nil {
+					return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+				}
+
+				for _, row := range rows {
+					if err := tx.Exec(
+						`UPDATE tokens
+						SET token_hash = ?, legacy_token = NULL
+						WHERE id = ?`,
+						r.hash(row.Token),
+						row.ID,
+					).Error; err != nil {
+						return fmt.Errorf(
+							"%w: %v",
+							customErrors.ErrDBQuery,
+							err,
+						)
+					}
+				}
+
+				return nil
+			},
+		)
+		if err != nil {
+			return hashed, err
+		}
+
+		hashed += len(rows)
+		if len(rows) < batchSize {
+			return hashed, nil
+		}
+	}
+}
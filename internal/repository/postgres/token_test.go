@@ -2,18 +2,20 @@ package postgres
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/grocery-service/internal/domain"
 	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/grocery-service/utils/hash"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestTokenRepositoryImpl(t *testing.T) {
 	postgres := setupTestDB(t, &domain.Token{})
-	repo := NewTokenRepository(postgres)
+	repo := NewTokenRepository(postgres, "test-token-hash-key")
 	ctx := context.Background()
 	testUser := createTestUser(t, postgres.DB)
 
@@ -34,7 +36,58 @@ func TestTokenRepositoryImpl(t *testing.T) {
 
 		assert.Equal(t, token.ID, retrieved.ID)
 		assert.Equal(t, token.UserID, retrieved.UserID)
-		assert.Equal(t, token.Token, retrieved.Token)
+		assert.Equal(t, token.TokenHash, retrieved.TokenHash)
+	})
+
+	t.Run("Create stores only the token hash", func(t *testing.T) {
+		plaintext := "secret-token-" + uuid.NewString()
+		token := &domain.Token{
+			ID:        uuid.New(),
+			UserID:    testUser.ID,
+			Token:     plaintext,
+			Type:      domain.TokenTypeRefresh,
+			ExpiresAt: time.Now().Add(time.Hour),
+		}
+
+		err := repo.Create(ctx, token)
+		assert.NoError(t, err)
+		assert.Equal(
+			t,
+			hash.Token([]byte("test-token-hash-key"), plaintext),
+			token.TokenHash,
+		)
+
+		var row map[string]interface{}
+		err = postgres.DB.Raw(
+			"SELECT * FROM tokens WHERE id = ?",
+			token.ID,
+		).Scan(&row).Error
+		assert.NoError(t, err)
+		assert.NotEmpty(t, row)
+		for column, value := range row {
+			assert.NotContains(
+				t,
+				fmt.Sprint(value),
+				plaintext,
+				"column %s holds the plaintext token",
+				column,
+			)
+		}
+
+		retrieved, err := repo.GetByToken(ctx, plaintext)
+		assert.NoError(t, err)
+		assert.Equal(t, token.ID, retrieved.ID)
+		assert.Empty(t, retrieved.Token)
+
+		// The stored hash is not itself a usable token
+		_, err = repo.GetByToken(ctx, token.TokenHash)
+		assert.ErrorIs(t, err, customErrors.ErrTokenNotFound)
+
+		// Nor does the token verify under another key
+		otherRepo := NewTokenRepository(postgres, "other-token-hash-key")
+		_, err = otherRepo.GetByToken(ctx, plaintext)
+		assert.ErrorIs(t, err, customErrors.ErrTokenNotFound)
+		assert.False(t, otherRepo.IsValid(ctx, plaintext))
 	})
 
 	t.Run("GetByUserAndType", func(t *testing.T) {
@@ -48,14 +101,35 @@ func TestTokenRepositoryImpl(t *testing.T) {
 		assert.NoError(t, err)
 
 		token := &domain.Token{
+			ID:         uuid.New(),
+			UserID:     testUser.ID,
+			Token:      "test-token-" + uuid.NewString(),
+			Type:       domain.TokenTypeAccess,
+			ExpiresAt:  time.Now().Add(time.Hour),
+			LastUsedAt: time.Now().Add(-time.Hour),
+		}
+		err = repo.Create(ctx, token)
+		assert.NoError(t, err)
+
+		recent := &domain.Token{
+			ID:         uuid.New(),
+			UserID:     testUser.ID,
+			Token:      "test-token-" + uuid.NewString(),
+			Type:       domain.TokenTypeAccess,
+			ExpiresAt:  time.Now().Add(time.Hour),
+			LastUsedAt: time.Now(),
+		}
+		err = repo.Create(ctx, recent)
+		assert.NoError(t, err)
+
+		expired := &domain.Token{
 			ID:        uuid.New(),
 			UserID:    testUser.ID,
 			Token:     "test-token-" + uuid.NewString(),
 			Type:      domain.TokenTypeAccess,
-			ExpiresAt: time.Now().Add(time.Hour),
+			ExpiresAt: time.Now().Add(-time.Hour),
 		}
-
-		err = repo.Create(ctx, token)
+		err = repo.Create(ctx, expired)
 		assert.NoError(t, err)
 
 		retrieved, err := repo.GetByUserAndType(
@@ -64,10 +138,39 @@ func TestTokenRepositoryImpl(t *testing.T) {
 			domain.TokenTypeAccess,
 		)
 		assert.NoError(t, err)
-		assert.Equal(t, token.ID, retrieved.ID)
-		assert.Equal(t, token.Token, retrieved.Token)
-		assert.Equal(t, token.Type, retrieved.Type)
-		assert.Equal(t, token.UserID, retrieved.UserID)
+		assert.Len(t, retrieved, 2)
+		assert.Equal(t, recent.ID, retrieved[0].ID)
+		assert.Equal(t, token.ID, retrieved[1].ID)
+		assert.Equal(t, token.TokenHash, retrieved[1].TokenHash)
+		assert.Equal(t, token.Type, retrieved[1].Type)
+		assert.Equal(t, token.UserID, retrieved[1].UserID)
+	})
+
+	t.Run("RevokeUserTokens", func(t *testing.T) {
+		user := createTestUser(t, postgres.DB)
+		for i := 0; i < 2; i++ {
+			err := repo.Create(ctx, &domain.Token{
+				ID:        uuid.New(),
+				UserID:    user.ID,
+				Token:     "user-token-" + uuid.NewString(),
+				Type:      domain.TokenTypeRefresh,
+				FamilyID:  uuid.New(),
+				ExpiresAt: time.Now().Add(time.Hour),
+			})
+			assert.NoError(t, err)
+		}
+
+		revoked, err := repo.RevokeUserTokens(ctx, user.ID.String())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), revoked)
+
+		remaining, err := repo.GetByUserAndType(
+			ctx,
+			user.ID.String(),
+			domain.TokenTypeRefresh,
+		)
+		assert.NoError(t, err)
+		assert.Empty(t, remaining)
 	})
 
 	t.Run("GetByProviderID", func(t *testing.T) {
@@ -107,28 +210,138 @@ func TestTokenRepositoryImpl(t *testing.T) {
 		// Try to get revoked token
 		_, err = repo.GetByToken(ctx, token.Token)
 		assert.ErrorIs(t, err, customErrors.ErrTokenNotFound)
+		assert.False(t, repo.IsValid(ctx, token.Token))
+
+		// Revoking by the stored hash does not match anything
+		err = repo.RevokeToken(ctx, token.TokenHash)
+		assert.ErrorIs(t, err, customErrors.ErrTokenNotFound)
 	})
 
-	t.Run("DeleteExpiredTokens", func(t *testing.T) {
-		expiredToken := &domain.Token{
+	t.Run("RotateToken", func(t *testing.T) {
+		familyID := uuid.New()
+		current := &domain.Token{
 			ID:        uuid.New(),
 			UserID:    testUser.ID,
-			Token:     "expired-token-" + uuid.NewString(),
-			Type:      domain.TokenTypeAccess,
-			ExpiresAt: time.Now().Add(-time.Hour), // Expired
+			Token:     "refresh-token-" + uuid.NewString(),
+			Type:      domain.TokenTypeRefresh,
+			FamilyID:  familyID,
+			ExpiresAt: time.Now().Add(time.Hour),
 		}
+		err := repo.Create(ctx, current)
+		assert.NoError(t, err)
 
-		err := repo.Create(ctx, expiredToken)
+		next := &domain.Token{
+			ID:        uuid.New(),
+			UserID:    testUser.ID,
+			Token:     "refresh-token-" + uuid.NewString(),
+			Type:      domain.TokenTypeRefresh,
+			FamilyID:  familyID,
+			ExpiresAt: time.Now().Add(time.Hour),
+		}
+		err = repo.RotateToken(ctx, current, next)
 		assert.NoError(t, err)
 
-		err = repo.DeleteExpiredTokens(ctx)
+		assert.False(t, repo.IsValid(ctx, current.Token))
+		assert.True(t, repo.IsValid(ctx, next.Token))
+
+		revoked, err := repo.GetByTokenIncludingRevoked(ctx, current.Token)
 		assert.NoError(t, err)
+		assert.NotNil(t, revoked.RevokedAt)
 
-		// Try to get deleted token
-		_, err = repo.GetByToken(ctx, expiredToken.Token)
+		// A token can be rotated only once
+		err = repo.RotateToken(ctx, current, &domain.Token{
+			ID:        uuid.New(),
+			UserID:    testUser.ID,
+			Token:     "refresh-token-" + uuid.NewString(),
+			Type:      domain.TokenTypeRefresh,
+			FamilyID:  familyID,
+			ExpiresAt: time.Now().Add(time.Hour),
+		})
 		assert.ErrorIs(t, err, customErrors.ErrTokenNotFound)
 	})
 
+	t.Run("RevokeFamily", func(t *testing.T) {
+		familyID := uuid.New()
+		var family []*domain.Token
+		for _, tokenType := range []domain.TokenType{
+			domain.TokenTypeAccess,
+			domain.TokenTypeRefresh,
+		} {
+			token := &domain.Token{
+				ID:        uuid.New(),
+				UserID:    testUser.ID,
+				Token:     "family-token-" + uuid.NewString(),
+				Type:      tokenType,
+				FamilyID:  familyID,
+				ExpiresAt: time.Now().Add(time.Hour),
+			}
+			assert.NoError(t, repo.Create(ctx, token))
+			family = append(family, token)
+		}
+
+		other := &domain.Token{
+			ID:        uuid.New(),
+			UserID:    testUser.ID,
+			Token:     "other-token-" + uuid.NewString(),
+			Type:      domain.TokenTypeRefresh,
+			FamilyID:  uuid.New(),
+			ExpiresAt: time.Now().Add(time.Hour),
+		}
+		assert.NoError(t, repo.Create(ctx, other))
+
+		revoked, err := repo.RevokeFamily(ctx, familyID.String())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), revoked)
+
+		for _, token := range family {
+			assert.False(t, repo.IsValid(ctx, token.Token))
+		}
+		assert.True(t, repo.IsValid(ctx, other.Token))
+	})
+
+	t.Run("DeleteExpiredTokens", func(t *testing.T) {
+		newToken := func(expiresAt time.Time, revokedAt *time.Time) *domain.Token {
+			token := &domain.Token{
+				ID:        uuid.New(),
+				UserID:    testUser.ID,
+				Token:     "cleanup-token-" + uuid.NewString(),
+				Type:      domain.TokenTypeRefresh,
+				ExpiresAt: expiresAt,
+			}
+			assert.NoError(t, repo.Create(ctx, token))
+			if revokedAt != nil {
+				assert.NoError(t, postgres.DB.Model(token).
+					Update("revoked_at", *revokedAt).Error)
+			}
+			return token
+		}
+
+		longAgo := time.Now().Add(-48 * time.Hour)
+		recently := time.Now().Add(-time.Hour)
+		expired := newToken(time.Now().Add(-time.Hour), nil)
+		longRevoked := newToken(time.Now().Add(time.Hour), &longAgo)
+		recentlyRevoked := newToken(time.Now().Add(time.Hour), &recently)
+		live := newToken(time.Now().Add(time.Hour), nil)
+
+		revokedBefore := time.Now().Add(-24 * time.Hour)
+		deleted, err := repo.DeleteExpiredTokens(ctx, revokedBefore, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+
+		deleted, err = repo.DeleteExpiredTokens(ctx, revokedBefore, 100)
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, deleted, int64(1))
+
+		for _, token := range []*domain.Token{expired, longRevoked} {
+			_, err = repo.GetByTokenIncludingRevoked(ctx, token.Token)
+			assert.ErrorIs(t, err, customErrors.ErrTokenNotFound)
+		}
+		for _, token := range []*domain.Token{recentlyRevoked, live} {
+			_, err = repo.GetByTokenIncludingRevoked(ctx, token.Token)
+			assert.NoError(t, err)
+		}
+	})
+
 	t.Run("IsValid", func(t *testing.T) {
 		validToken := &domain.Token{
 			ID:        uuid.New(),
@@ -148,4 +361,49 @@ func TestTokenRepositoryImpl(t *testing.T) {
 		isValid = repo.IsValid(ctx, "non-existent-token")
 		assert.False(t, isValid)
 	})
+
+	t.Run("ConsumeToken", func(t *testing.T) {
+		token := &domain.Token{
+			ID:        uuid.New(),
+			UserID:    testUser.ID,
+			Token:     "reset-token-" + uuid.NewString(),
+			Type:      domain.TokenTypePasswordReset,
+			FamilyID:  uuid.New(),
+			ExpiresAt: time.Now().Add(time.Hour),
+		}
+		expired := &domain.Token{
+			ID:        uuid.New(),
+			UserID:    testUser.ID,
+			Token:     "reset-token-" + uuid.NewString(),
+			Type:      domain.TokenTypePasswordReset,
+			FamilyID:  uuid.New(),
+			ExpiresAt: time.Now().Add(-time.Minute),
+		}
+		for _, tok := range []*domain.Token{token, expired} {
+			assert.NoError(t, repo.Create(ctx, tok))
+		}
+
+		_, err := repo.ConsumeToken(
+			ctx,
+			token.Token,
+			domain.TokenTypeEmailVerification,
+		)
+		assert.ErrorIs(t, err, customErrors.ErrTokenNotFound)
+
+		consumed, err := repo.ConsumeToken(
+			ctx,
+			token.Token,
+			domain.TokenTypePasswordReset,
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, token.ID, consumed.ID)
+		assert.Equal(t, testUser.ID, consumed.UserID)
+
+		// Single use
+		_, err = repo.ConsumeToken(ctx, token.Token, domain.TokenTypePasswordReset)
+		assert.ErrorIs(t, err, customErrors.ErrTokenNotFound)
+
+		_, err = repo.ConsumeToken(ctx, expired.Token, domain.TokenTypePasswordReset)
+		assert.ErrorIs(t, err, customErrors.ErrTokenNotFound)
+	})
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/grocery-service/internal/domain"
 	"github.com/grocery-service/internal/repository/db"
@@ -13,15 +14,54 @@ import (
 
 type (
 	UserRepository interface {
-		Create(ctx context.Context, user *domain.User) error
+		// Create stores the user and links identities to it in one
+		// transaction.
+		Create(
+			ctx context.Context,
+			user *domain.User,
+			identities ...*domain.UserIdentity,
+		) error
 		GetByID(ctx context.Context, id string) (*domain.User, error)
 		GetByEmail(ctx context.Context, email string) (*domain.User, error)
 		GetByProviderID(
 			ctx context.Context,
 			providerID string,
 		) (*domain.User, error)
+		// GetByIdentity finds the user a provider login is linked to.
+		GetByIdentity(
+			ctx context.Context,
+			provider, subject string,
+		) (*domain.User, error)
+		// AddIdentity links another provider login to an existing user.
+		AddIdentity(ctx context.Context, identity *domain.UserIdentity) error
+		// ClaimAccount hands the account of identity.UserID to a provider
+		// login that verified the account's email address. The account's
+		// other logins and password are removed and their tokens revoked,
+		// identity is linked and the address is marked verified, all in
+		// one transaction.
+		ClaimAccount(ctx context.Context, identity *domain.UserIdentity) error
 		Update(ctx context.Context, user *domain.User) error
 		Delete(ctx context.Context, id string) error
+		// RecordFailedLogin counts a wrong password against the user.
+		// The attempt that reaches maxAttempts locks the account until
+		// lockUntil and starts the count again. It returns the updated
+		// user.
+		RecordFailedLogin(
+			ctx context.Context,
+			id string,
+			maxAttempts int,
+			lockUntil time.Time,
+		) (*domain.User, error)
+		// ResetFailedLogins clears the user's failed login count and
+		// lockout.
+		ResetFailedLogins(ctx context.Context, id string) error
+		// SetPassword replaces the user's password hash, clears any
+		// lockout and marks the email address verified, since only its
+		// owner could have received the reset link. Provider logins
+		// linked while the address was unverified are unlinked, as
+		// ClaimAccount does.
+		SetPassword(ctx context.Context, id, passwordHash string) error
+		MarkEmailVerified(ctx context.Context, id string) error
 	}
 
 	UserRepositoryImpl struct {
@@ -42,6 +82,7 @@ func NewUserRepository(
 func (r *UserRepositoryImpl) Create(
 	ctx context.Context,
 	user *domain.User,
+	identities ...*domain.UserIdentity,
 ) error {
 	return r.BaseRepository.WithTransaction(
 		ctx,
@@ -53,6 +94,14 @@ func (r *UserRepositoryImpl) Create(
 					err,
 				)
 			}
+
+			if len(identities) == 0 {
+				return nil
+			}
+			if err := txRepo.GetDB().WithContext(ctx).
+				Create(identities).Error; err != nil {
+				return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+			}
 			return nil
 		},
 	)
@@ -114,6 +163,85 @@ func (r *UserRepositoryImpl) GetByProviderID(
 	return &user, nil
 }
 
+func (r *UserRepositoryImpl) GetByIdentity(
+	ctx context.Context,
+	provider, subject string,
+) (*domain.User, error) {
+	var user domain.User
+	err := r.BaseRepository.GetDB().WithContext(ctx).
+		Joins("JOIN user_identities ON user_identities.user_id = users.id").
+		Where(
+			"user_identities.provider = ? AND user_identities.subject = ?",
+			provider,
+			subject,
+		).
+		First(&user).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, customErrors.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+	}
+
+	return &user, nil
+}
+
+func (r *UserRepositoryImpl) AddIdentity(
+	ctx context.Context,
+	identity *domain.UserIdentity,
+) error {
+	if err := r.BaseRepository.GetDB().WithContext(ctx).
+		Create(identity).Error; err != nil {
+		return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+	}
+	return nil
+}
+
+func (r *UserRepositoryImpl) ClaimAccount(
+	ctx context.Context,
+	identity *domain.UserIdentity,
+) error {
+	return r.BaseRepository.WithTransaction(
+		ctx,
+		func(txRepo *db.BaseRepository[domain.User]) error {
+			tx := txRepo.GetDB().WithContext(ctx)
+			now := time.Now()
+
+			if err := tx.Where("user_id = ?", identity.UserID).
+				Delete(&domain.UserIdentity{}).Error; err != nil {
+				return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+			}
+
+			if err := tx.Model(&domain.Token{}).
+				Where("user_id = ? AND revoked_at IS NULL", identity.UserID).
+				Update("revoked_at", now).Error; err != nil {
+				return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+			}
+
+			result := tx.Model(&domain.User{}).
+				Where("id = ?", identity.UserID).
+				Updates(map[string]interface{}{
+					"password":              "",
+					"failed_login_attempts": 0,
+					"locked_until":          nil,
+					"email_verified_at":     now,
+					"updated_at":            now,
+				})
+			if result.Error != nil {
+				return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, result.Error)
+			}
+			if result.RowsAffected == 0 {
+				return customErrors.ErrUserNotFound
+			}
+
+			if err := tx.Create(identity).Error; err != nil {
+				return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+			}
+			return nil
+		},
+	)
+}
+
 func (r *UserRepositoryImpl) Update(
 	ctx context.Context,
 	user *domain.User,
@@ -174,3 +302,127 @@ func (r *UserRepositoryImpl) Delete(
 		},
 	)
 }
+
+func (r *UserRepositoryImpl) RecordFailedLogin(
+	ctx context.Context,
+	id string,
+	maxAttempts int,
+	lockUntil time.Time,
+) (*domain.User, error) {
+	// A single statement keeps concurrent guesses from racing past the
+	// limit.
+	var user domain.User
+	result := r.BaseRepository.GetDB().WithContext(ctx).Raw(
+		`UPDATE users SET
+			locked_until = CASE WHEN failed_login_attempts + 1 >= @max
+				THEN @until ELSE locked_until END,
+			failed_login_attempts = CASE WHEN failed_login_attempts + 1 >= @max
+				THEN 0 ELSE failed_login_attempts + 1 END,
+			updated_at = @now
+		WHERE id = @id
+		RETURNING *`,
+		map[string]interface{}{
+			"max":   maxAttempts,
+			"until": lockUntil,
+			"now":   time.Now(),
+			"id":    id,
+		},
+	).Scan(&user)
+	if result.Error != nil {
+		return nil, fmt.Errorf("%w: %v", customErrors.ErrDBQuery, result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, customErrors.ErrUserNotFound
+	}
+
+	return &user, nil
+}
+
+func (r *UserRepositoryImpl) ResetFailedLogins(
+	ctx context.Context,
+	id string,
+) error {
+	return r.updateColumns(ctx, id, map[string]interface{}{
+		"failed_login_attempts": 0,
+		"locked_until":          nil,
+	})
+}
+
+func (r *UserRepositoryImpl) SetPassword(
+	ctx context.Context,
+	id, passwordHash string,
+) error {
+	return r.BaseRepository.WithTransaction(
+		ctx,
+		func(txRepo *db.BaseRepository[domain.User]) error {
+			tx := txRepo.GetDB().WithContext(ctx)
+			now := time.Now()
+
+			if err := tx.Where(
+				`user_id = ? AND EXISTS (
+					SELECT 1 FROM users WHERE id = ? AND email_verified_at IS NULL
+				)`,
+				id,
+				id,
+			).Delete(&domain.UserIdentity{}).Error; err != nil {
+				return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, err)
+			}
+
+			result := tx.Model(&domain.User{}).
+				Where("id = ?", id).
+				Updates(map[string]interface{}{
+					"password":              passwordHash,
+					"failed_login_attempts": 0,
+					"locked_until":          nil,
+					"email_verified_at": gorm.Expr(
+						"COALESCE(email_verified_at, ?)",
+						now,
+					),
+					"updated_at": now,
+				})
+			if result.Error != nil {
+				return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, result.Error)
+			}
+			if result.RowsAffected == 0 {
+				return customErrors.ErrUserNotFound
+			}
+			return nil
+		},
+	)
+}
+
+func (r *UserRepositoryImpl) MarkEmailVerified(
+	ctx context.Context,
+	id string,
+) error {
+	return r.updateColumns(ctx, id, map[string]interface{}{
+		"email_verified_at": gorm.Expr(
+			"COALESCE(email_verified_at, ?)",
+			time.Now(),
+		),
+	})
+}
+
+// updateColumns sets columns of one user without touching the rest of
+// the row.
+func (r *UserRepositoryImpl) updateColumns(
+	ctx context.Context,
+	id string,
+	columns map[string]interface{},
+) error {
+	columns["updated_at"] = time.Now()
+	result := r.BaseRepository.GetDB().WithContext(ctx).
+		Model(&domain.User{}).
+		Where("id = ?", id).
+		Updates(columns)
+	if result.Error != nil {
+		return fmt.Errorf("%w: %v", customErrors.ErrDBQuery, result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return customErrors.ErrUserNotFound
+	}
+
+	return nil
+}
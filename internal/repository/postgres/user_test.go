@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/grocery-service/internal/domain"
@@ -12,7 +13,12 @@ import (
 )
 
 func TestUserRepositoryImpl(t *testing.T) {
-	postgres := setupTestDB(t, &domain.User{}, &domain.Token{})
+	postgres := setupTestDB(
+		t,
+		&domain.User{},
+		&domain.Token{},
+		&domain.UserIdentity{},
+	)
 	repo := NewUserRepository(postgres)
 	ctx := context.Background()
 
@@ -79,7 +85,7 @@ func TestUserRepositoryImpl(t *testing.T) {
 			ProviderID: providerID,
 		}
 
-		tokenRepo := NewTokenRepository(postgres)
+		tokenRepo := NewTokenRepository(postgres, "test-token-hash-key")
 
 		err = tokenRepo.Create(ctx, token)
 		require.NoError(t, err)
@@ -92,6 +98,112 @@ func TestUserRepositoryImpl(t *testing.T) {
 		assert.ErrorIs(t, err, customErrors.ErrUserNotFound)
 	})
 
+	t.Run("GetByIdentity", func(t *testing.T) {
+		user := &domain.User{
+			ID:    uuid.New(),
+			Email: "test-" + uuid.NewString() + "@example.com",
+			Name:  "Test User",
+			Role:  domain.CustomerRole,
+		}
+		subject := "subject-" + uuid.NewString()
+
+		err := repo.Create(ctx, user, &domain.UserIdentity{
+			UserID:   user.ID,
+			Provider: "keycloak",
+			Subject:  subject,
+		})
+		require.NoError(t, err)
+
+		retrieved, err := repo.GetByIdentity(ctx, "keycloak", subject)
+		assert.NoError(t, err)
+		assert.Equal(t, user.ID, retrieved.ID)
+
+		_, err = repo.GetByIdentity(ctx, "google", subject)
+		assert.ErrorIs(t, err, customErrors.ErrUserNotFound)
+	})
+
+	t.Run("AddIdentity", func(t *testing.T) {
+		user := &domain.User{
+			ID:    uuid.New(),
+			Email: "test-" + uuid.NewString() + "@example.com",
+			Name:  "Test User",
+			Role:  domain.CustomerRole,
+		}
+		require.NoError(t, repo.Create(ctx, user))
+
+		identity := &domain.UserIdentity{
+			UserID:   user.ID,
+			Provider: "google",
+			Subject:  "subject-" + uuid.NewString(),
+		}
+		require.NoError(t, repo.AddIdentity(ctx, identity))
+
+		retrieved, err := repo.GetByIdentity(ctx, "google", identity.Subject)
+		assert.NoError(t, err)
+		assert.Equal(t, user.ID, retrieved.ID)
+
+		duplicate := &domain.UserIdentity{
+			UserID:   user.ID,
+			Provider: "google",
+			Subject:  identity.Subject,
+		}
+		assert.ErrorIs(
+			t,
+			repo.AddIdentity(ctx, duplicate),
+			customErrors.ErrDBQuery,
+		)
+	})
+
+	t.Run("ClaimAccount", func(t *testing.T) {
+		user := &domain.User{
+			ID:       uuid.New(),
+			Email:    "test-" + uuid.NewString() + "@example.com",
+			Password: "squatter-hash",
+			Name:     "Test User",
+			Role:     domain.CustomerRole,
+		}
+		oldSubject := "subject-" + uuid.NewString()
+		require.NoError(t, repo.Create(ctx, user, &domain.UserIdentity{
+			UserID:   user.ID,
+			Provider: "google",
+			Subject:  oldSubject,
+		}))
+
+		tokenRepo := NewTokenRepository(postgres, "test-token-hash-key")
+		token := &domain.Token{
+			ID:        uuid.New(),
+			UserID:    user.ID,
+			Token:     "test-token-" + uuid.NewString(),
+			Type:      domain.TokenTypeRefresh,
+			ExpiresAt: time.Now().Add(time.Hour),
+		}
+		require.NoError(t, tokenRepo.Create(ctx, token))
+
+		identity := &domain.UserIdentity{
+			UserID:   user.ID,
+			Provider: "keycloak",
+			Subject:  "subject-" + uuid.NewString(),
+		}
+		require.NoError(t, repo.ClaimAccount(ctx, identity))
+
+		retrieved, err := repo.GetByIdentity(ctx, "keycloak", identity.Subject)
+		require.NoError(t, err)
+		assert.NotNil(t, retrieved.EmailVerifiedAt)
+		assert.Empty(t, retrieved.Password)
+
+		_, err = repo.GetByIdentity(ctx, "google", oldSubject)
+		assert.ErrorIs(t, err, customErrors.ErrUserNotFound)
+
+		assert.False(t, tokenRepo.IsValid(ctx, token.Token))
+
+		err = repo.ClaimAccount(ctx, &domain.UserIdentity{
+			UserID:   uuid.New(),
+			Provider: "keycloak",
+			Subject:  "subject-" + uuid.NewString(),
+		})
+		assert.ErrorIs(t, err, customErrors.ErrUserNotFound)
+	})
+
 	t.Run("Update", func(t *testing.T) {
 		user := &domain.User{
 			ID:      uuid.New(),
@@ -144,4 +256,123 @@ func TestUserRepositoryImpl(t *testing.T) {
 		err = repo.Delete(ctx, uuid.New().String())
 		assert.ErrorIs(t, err, customErrors.ErrUserNotFound)
 	})
+
+	t.Run("RecordFailedLogin locks at the limit", func(t *testing.T) {
+		user := &domain.User{
+			ID:    uuid.New(),
+			Email: "test-" + uuid.NewString() + "@example.com",
+			Name:  "Test User",
+			Role:  domain.CustomerRole,
+		}
+		require.NoError(t, repo.Create(ctx, user))
+		lockUntil := time.Now().Add(15 * time.Minute).UTC().Truncate(time.Second)
+
+		for attempt := 1; attempt < 3; attempt++ {
+			updated, err := repo.RecordFailedLogin(
+				ctx,
+				user.ID.String(),
+				3,
+				lockUntil,
+			)
+			require.NoError(t, err)
+			assert.Equal(t, attempt, updated.FailedLoginAttempts)
+			assert.Nil(t, updated.LockedUntil)
+		}
+
+		updated, err := repo.RecordFailedLogin(ctx, user.ID.String(), 3, lockUntil)
+		require.NoError(t, err)
+		assert.Equal(t, 0, updated.FailedLoginAttempts)
+		require.NotNil(t, updated.LockedUntil)
+		assert.True(t, updated.LockedUntil.Equal(lockUntil))
+
+		_, err = repo.RecordFailedLogin(ctx, uuid.NewString(), 3, lockUntil)
+		assert.ErrorIs(t, err, customErrors.ErrUserNotFound)
+	})
+
+	t.Run("SetPassword clears lockout and verifies email", func(t *testing.T) {
+		lockedUntil := time.Now().Add(time.Hour)
+		user := &domain.User{
+			ID:                  uuid.New(),
+			Email:               "test-" + uuid.NewString() + "@example.com",
+			Name:                "Test User",
+			Role:                domain.CustomerRole,
+			FailedLoginAttempts: 2,
+			LockedUntil:         &lockedUntil,
+		}
+		require.NoError(t, repo.Create(ctx, user))
+
+		require.NoError(t, repo.SetPassword(ctx, user.ID.String(), "new-hash"))
+
+		retrieved, err := repo.GetByID(ctx, user.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, "new-hash", retrieved.Password)
+		assert.Zero(t, retrieved.FailedLoginAttempts)
+		assert.Nil(t, retrieved.LockedUntil)
+		assert.NotNil(t, retrieved.EmailVerifiedAt)
+	})
+
+	t.Run("SetPassword unlinks logins added before verification", func(t *testing.T) {
+		unverified := &domain.User{
+			ID:    uuid.New(),
+			Email: "test-" + uuid.NewString() + "@example.com",
+			Name:  "Test User",
+			Role:  domain.CustomerRole,
+		}
+		unverifiedSubject := "subject-" + uuid.NewString()
+		require.NoError(t, repo.Create(ctx, unverified, &domain.UserIdentity{
+			UserID:   unverified.ID,
+			Provider: "google",
+			Subject:  unverifiedSubject,
+		}))
+
+		verifiedAt := time.Now()
+		verified := &domain.User{
+			ID:              uuid.New(),
+			Email:           "test-" + uuid.NewString() + "@example.com",
+			Name:            "Test User",
+			Role:            domain.CustomerRole,
+			EmailVerifiedAt: &verifiedAt,
+		}
+		verifiedSubject := "subject-" + uuid.NewString()
+		require.NoError(t, repo.Create(ctx, verified, &domain.UserIdentity{
+			UserID:   verified.ID,
+			Provider: "google",
+			Subject:  verifiedSubject,
+		}))
+
+		require.NoError(t, repo.SetPassword(ctx, unverified.ID.String(), "new-hash"))
+		require.NoError(t, repo.SetPassword(ctx, verified.ID.String(), "new-hash"))
+
+		_, err := repo.GetByIdentity(ctx, "google", unverifiedSubject)
+		assert.ErrorIs(t, err, customErrors.ErrUserNotFound)
+
+		retrieved, err := repo.GetByIdentity(ctx, "google", verifiedSubject)
+		require.NoError(t, err)
+		assert.Equal(t, verified.ID, retrieved.ID)
+	})
+
+	t.Run("MarkEmailVerified and ResetFailedLogins", func(t *testing.T) {
+		user := &domain.User{
+			ID:                  uuid.New(),
+			Email:               "test-" + uuid.NewString() + "@example.com",
+			Name:                "Test User",
+			Role:                domain.CustomerRole,
+			FailedLoginAttempts: 2,
+		}
+		require.NoError(t, repo.Create(ctx, user))
+
+		require.NoError(t, repo.MarkEmailVerified(ctx, user.ID.String()))
+		require.NoError(t, repo.ResetFailedLogins(ctx, user.ID.String()))
+
+		retrieved, err := repo.GetByID(ctx, user.ID.String())
+		require.NoError(t, err)
+		assert.NotNil(t, retrieved.EmailVerifiedAt)
+		assert.Zero(t, retrieved.FailedLoginAttempts)
+
+		assert.ErrorIs(
+			t,
+			repo.MarkEmailVerified(ctx, uuid.NewString()),
+			customErrors.ErrUserNotFound,
+		)
+	})
 }
@@ -0,0 +1,308 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grocery-service/internal/domain"
+	repository "github.com/grocery-service/internal/repository/postgres"
+	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/grocery-service/utils/logger"
+)
+
+// apiKeyTouchInterval limits how often the last use of a key is written,
+// so that a busy scanner does not cost a write per request.
+const apiKeyTouchInterval = time.Minute
+
+type (
+	// APIKeyService manages API keys for machine clients and
+	// authenticates their requests. A key holds only the permissions
+	// it was created with and never acts as a user.
+	APIKeyService interface {
+		List(ctx context.Context) ([]domain.APIKey, error)
+		GetByID(ctx context.Context, id string) (*domain.APIKey, error)
+		// Create issues a key on behalf of createdBy, the acting
+		// user's ID, who must hold every permission it grants. The
+		// plaintext key is returned only here.
+		Create(
+			ctx context.Context,
+			createdBy string,
+			request domain.APIKeyRequest,
+		) (*domain.NewAPIKey, error)
+		// Update replaces a key's name, expiry and permissions on
+		// behalf of updatedBy, who must hold every permission the key
+		// has before and after the change.
+		Update(
+			ctx context.Context,
+			updatedBy string,
+			id string,
+			request domain.APIKeyRequest,
+		) (*domain.APIKey, error)
+		Delete(ctx context.Context, id string) error
+		// Authenticate returns the live key matching key and records
+		// its use. It fails with ErrInvalidAPIKey when the key is
+		// unknown or expired.
+		Authenticate(ctx context.Context, key string) (*domain.APIKey, error)
+	}
+
+	APIKeyServiceImpl struct {
+		repo     repository.APIKeyRepository
+		userRepo repository.UserRepository
+		roleRepo repository.RoleRepository
+	}
+)
+
+func NewAPIKeyService(
+	repo repository.APIKeyRepository,
+	userRepo repository.UserRepository,
+	roleRepo repository.RoleRepository,
+) APIKeyService {
+	return &APIKeyServiceImpl{
+		repo:     repo,
+		userRepo: userRepo,
+		roleRepo: roleRepo,
+	}
+}
+
+func (s *APIKeyServiceImpl) List(
+	ctx context.Context,
+) ([]domain.APIKey, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *APIKeyServiceImpl) GetByID(
+	ctx context.Context,
+	id string,
+) (*domain.APIKey, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, customErrors.ErrAPIKeyNotFound
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *APIKeyServiceImpl) Create(
+	ctx context.Context,
+	createdBy string,
+	request domain.APIKeyRequest,
+) (*domain.NewAPIKey, error) {
+	key, err := newAPIKey(request)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, createdBy, key.Permissions); err != nil {
+		return nil, err
+	}
+	creator := uuid.MustParse(createdBy)
+	key.CreatedBy = &creator
+
+	key.Prefix, key.Key, err = generateAPIKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+
+	if err := s.repo.Create(ctx, key); err != nil {
+		return nil, err
+	}
+
+	return &domain.NewAPIKey{APIKey: key, Key: key.Key}, nil
+}
+
+func (s *APIKeyServiceImpl) Update(
+	ctx context.Context,
+	updatedBy string,
+	id string,
+	request domain.APIKeyRequest,
+) (*domain.APIKey, error) {
+	keyID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, customErrors.ErrAPIKeyNotFound
+	}
+
+	key, err := newAPIKey(request)
+	if err != nil {
+		return nil, err
+	}
+	key.ID = keyID
+
+	current, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(
+		ctx,
+		updatedBy,
+		append(current.Permissions, key.Permissions...),
+	); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, key); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *APIKeyServiceImpl) Delete(ctx context.Context, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return customErrors.ErrAPIKeyNotFound
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *APIKeyServiceImpl) Authenticate(
+	ctx context.Context,
+	key string,
+) (*domain.APIKey, error) {
+	if !strings.HasPrefix(key, domain.APIKeyPrefix) {
+		return nil, customErrors.ErrInvalidAPIKey
+	}
+
+	apiKey, err := s.repo.GetByKey(ctx, key)
+	if err != nil {
+		if errors.Is(err, customErrors.ErrAPIKeyNotFound) {
+			return nil, customErrors.ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if apiKey.Expired(now) {
+		return nil, customErrors.ErrInvalidAPIKey
+	}
+
+	if apiKey.LastUsedAt == nil ||
+		now.Sub(*apiKey.LastUsedAt) >= apiKeyTouchInterval {
+		// A failed write only loses usage tracking, not the request.
+		if err := s.repo.Touch(ctx, apiKey.ID.String(), now); err != nil {
+			logger.Warn(
+				"failed to record API key use",
+				logger.String("api_key_id", apiKey.ID.String()),
+				logger.Error64("error", err),
+			)
+		} else {
+			apiKey.LastUsedAt = &now
+		}
+	}
+
+	return apiKey, nil
+}
+
+// authorize fails with ErrAPIKeyPermissionDenied unless the user with
+// userID holds every one of permissions, so that managing keys cannot
+// widen anyone's access. The user's role is read from the database
+// rather than the access token, so a role that was just narrowed counts
+// at once. Admins hold every permission.
+func (s *APIKeyServiceImpl) authorize(
+	ctx context.Context,
+	userID string,
+	permissions []domain.Permission,
+) error {
+	if _, err := uuid.Parse(userID); err != nil {
+		return customErrors.ErrAPIKeyPermissionDenied
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, customErrors.ErrUserNotFound) {
+			return customErrors.ErrAPIKeyPermissionDenied
+		}
+		return err
+	}
+	if user.Role == domain.AdminRole {
+		return nil
+	}
+
+	var held []domain.Permission
+	role, err := s.roleRepo.GetByName(ctx, user.Role)
+	switch {
+	case err == nil:
+		held = role.Permissions
+	case !errors.Is(err, customErrors.ErrRoleNotFound):
+		return err
+	}
+
+	for _, p := range permissions {
+		if !slices.Contains(held, p) {
+			return fmt.Errorf(
+				"%w: you do not hold %s",
+				customErrors.ErrAPIKeyPermissionDenied,
+				p,
+			)
+		}
+	}
+	return nil
+}
+
+// newAPIKey validates request and builds the key it describes.
+func newAPIKey(request domain.APIKeyRequest) (*domain.APIKey, error) {
+	name := strings.TrimSpace(request.Name)
+	if name == "" || len(name) > 100 {
+		return nil, fmt.Errorf(
+			"%w: name must be 1 to 100 characters",
+			customErrors.ErrInvalidAPIKeyData,
+		)
+	}
+
+	if len(request.Permissions) == 0 {
+		return nil, fmt.Errorf(
+			"%w: at least one permission is required",
+			customErrors.ErrInvalidAPIKeyData,
+		)
+	}
+	permissions, err := uniquePermissions(
+		request.Permissions,
+		customErrors.ErrInvalidAPIKeyData,
+	)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range permissions {
+		if slices.Contains(domain.APIKeyDeniedPermissions, p) {
+			return nil, fmt.Errorf(
+				"%w: API keys cannot be granted %s",
+				customErrors.ErrInvalidAPIKeyData,
+				p,
+			)
+		}
+	}
+
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf(
+			"%w: expires_at must be in the future",
+			customErrors.ErrInvalidAPIKeyData,
+		)
+	}
+
+	return &domain.APIKey{
+		ID:          uuid.New(),
+		Name:        name,
+		Permissions: permissions,
+		ExpiresAt:   request.ExpiresAt,
+	}, nil
+}
+
+// generateAPIKey returns a new key, "gsk_<id>_<secret>", and its prefix
+// "gsk_<id>". The id only tells keys apart; the secret carries 256
+// random bits.
+func generateAPIKey() (prefix, key string, err error) {
+	id := make([]byte, 6)
+	if _, err := rand.Read(id); err != nil {
+		return "", "", err
+	}
+
+	secret, err := randomToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	prefix = domain.APIKeyPrefix + hex.EncodeToString(id)
+	return prefix, prefix + "_" + secret, nil
+}
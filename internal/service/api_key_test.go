@@ -0,0 +1,383 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grocery-service/internal/domain"
+	repoMocks "github.com/grocery-service/tests/mocks/repository"
+	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// apiKeyCaller returns user and role repositories in which the user
+// with id holds role, and role grants permissions.
+func apiKeyCaller(
+	t *testing.T,
+	id uuid.UUID,
+	role domain.UserRole,
+	permissions ...domain.Permission,
+) (*repoMocks.UserRepository, *repoMocks.RoleRepository) {
+	userRepo := repoMocks.NewUserRepository(t)
+	userRepo.On("GetByID", mock.Anything, id.String()).
+		Return(&domain.User{ID: id, Role: role}, nil).Once()
+
+	roleRepo := repoMocks.NewRoleRepository(t)
+	roleRepo.On("GetByName", mock.Anything, role).
+		Return(&domain.Role{Name: role, Permissions: permissions}, nil).
+		Maybe()
+
+	return userRepo, roleRepo
+}
+
+func TestAPIKeyService_Create(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		repo := repoMocks.NewAPIKeyRepository(t)
+		creator := uuid.New()
+		userRepo, roleRepo := apiKeyCaller(
+			t,
+			creator,
+			domain.StoreManagerRole,
+			domain.PermissionAPIKeysManage,
+			domain.PermissionInventoryWrite,
+		)
+		service := NewAPIKeyService(repo, userRepo, roleRepo)
+
+		var stored *domain.APIKey
+		repo.On("Create", ctx, mock.AnythingOfType("*domain.APIKey")).
+			Run(func(args mock.Arguments) {
+				stored = args.Get(1).(*domain.APIKey)
+			}).
+			Return(nil).Once()
+
+		created, err := service.Create(
+			ctx,
+			creator.String(),
+			domain.APIKeyRequest{
+				Name: " Scanner 1 ",
+				Permissions: []domain.Permission{
+					domain.PermissionInventoryWrite,
+					domain.PermissionInventoryWrite,
+				},
+			},
+		)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Scanner 1", stored.Name)
+		assert.Equal(
+			t,
+			[]domain.Permission{domain.PermissionInventoryWrite},
+			stored.Permissions,
+		)
+		assert.Equal(t, &creator, stored.CreatedBy)
+		assert.True(t, strings.HasPrefix(created.Key, stored.Prefix+"_"))
+		assert.True(t, strings.HasPrefix(stored.Prefix, domain.APIKeyPrefix))
+		assert.Equal(t, stored.Key, created.Key)
+		assert.Same(t, stored, created.APIKey)
+	})
+
+	t.Run("Success - Admin Holds Every Permission", func(t *testing.T) {
+		repo := repoMocks.NewAPIKeyRepository(t)
+		creator := uuid.New()
+		userRepo, roleRepo := apiKeyCaller(t, creator, domain.AdminRole)
+		service := NewAPIKeyService(repo, userRepo, roleRepo)
+
+		repo.On("Create", ctx, mock.AnythingOfType("*domain.APIKey")).
+			Return(nil).Once()
+
+		_, err := service.Create(ctx, creator.String(), domain.APIKeyRequest{
+			Name:        "POS terminal",
+			Permissions: []domain.Permission{domain.PermissionOrdersManage},
+		})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("Error - Permission Caller Lacks", func(t *testing.T) {
+		creator := uuid.New()
+		userRepo, roleRepo := apiKeyCaller(
+			t,
+			creator,
+			domain.StoreManagerRole,
+			domain.PermissionAPIKeysManage,
+			domain.PermissionInventoryRead,
+		)
+		service := NewAPIKeyService(
+			repoMocks.NewAPIKeyRepository(t),
+			userRepo,
+			roleRepo,
+		)
+
+		_, err := service.Create(ctx, creator.String(), domain.APIKeyRequest{
+			Name: "Scanner",
+			Permissions: []domain.Permission{
+				domain.PermissionInventoryRead,
+				domain.PermissionInventoryWrite,
+			},
+		})
+
+		assert.ErrorIs(t, err, customErrors.ErrAPIKeyPermissionDenied)
+		assert.ErrorContains(t, err, string(domain.PermissionInventoryWrite))
+	})
+
+	past := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name    string
+		request domain.APIKeyRequest
+	}{
+		{
+			name: "Error - Empty Name",
+			request: domain.APIKeyRequest{
+				Name:        "  ",
+				Permissions: []domain.Permission{domain.PermissionOrdersRead},
+			},
+		},
+		{
+			name:    "Error - No Permissions",
+			request: domain.APIKeyRequest{Name: "Scanner"},
+		},
+		{
+			name: "Error - Unknown Permission",
+			request: domain.APIKeyRequest{
+				Name:        "Scanner",
+				Permissions: []domain.Permission{"stock:steal"},
+			},
+		},
+		{
+			name: "Error - Admin Permission",
+			request: domain.APIKeyRequest{
+				Name: "Scanner",
+				Permissions: []domain.Permission{
+					domain.PermissionInventoryRead,
+					domain.PermissionAPIKeysManage,
+				},
+			},
+		},
+		{
+			name: "Error - Expiry In The Past",
+			request: domain.APIKeyRequest{
+				Name:        "Scanner",
+				Permissions: []domain.Permission{domain.PermissionOrdersRead},
+				ExpiresAt:   &past,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewAPIKeyService(
+				repoMocks.NewAPIKeyRepository(t),
+				repoMocks.NewUserRepository(t),
+				repoMocks.NewRoleRepository(t),
+			)
+
+			_, err := service.Create(ctx, uuid.NewString(), tt.request)
+
+			assert.ErrorIs(t, err, customErrors.ErrInvalidAPIKeyData)
+		})
+	}
+}
+
+func TestAPIKeyService_Update(t *testing.T) {
+	ctx := context.Background()
+
+	request := domain.APIKeyRequest{
+		Name:        "POS terminal",
+		Permissions: []domain.Permission{domain.PermissionOrdersCreate},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		repo := repoMocks.NewAPIKeyRepository(t)
+		caller := uuid.New()
+		userRepo, roleRepo := apiKeyCaller(
+			t,
+			caller,
+			domain.StoreManagerRole,
+			domain.PermissionOrdersCreate,
+		)
+		service := NewAPIKeyService(repo, userRepo, roleRepo)
+		id := uuid.New()
+		current := &domain.APIKey{
+			ID:          id,
+			Name:        "POS",
+			Permissions: []domain.Permission{domain.PermissionOrdersCreate},
+		}
+		want := &domain.APIKey{ID: id, Name: "POS terminal"}
+
+		repo.On("GetByID", ctx, id.String()).Return(current, nil).Once()
+		repo.On("Update", ctx, mock.MatchedBy(func(k *domain.APIKey) bool {
+			return k.ID == id && k.Name == "POS terminal"
+		})).Return(nil).Once()
+		repo.On("GetByID", ctx, id.String()).Return(want, nil).Once()
+
+		key, err := service.Update(ctx, caller.String(), id.String(), request)
+
+		require.NoError(t, err)
+		assert.Equal(t, want, key)
+	})
+
+	t.Run("Error - Key Holds Permission Caller Lacks", func(t *testing.T) {
+		repo := repoMocks.NewAPIKeyRepository(t)
+		caller := uuid.New()
+		userRepo, roleRepo := apiKeyCaller(
+			t,
+			caller,
+			domain.StoreManagerRole,
+			domain.PermissionOrdersCreate,
+		)
+		service := NewAPIKeyService(repo, userRepo, roleRepo)
+		id := uuid.New()
+
+		repo.On("GetByID", ctx, id.String()).Return(&domain.APIKey{
+			ID:          id,
+			Name:        "POS",
+			Permissions: []domain.Permission{domain.PermissionOrdersManage},
+		}, nil).Once()
+
+		_, err := service.Update(ctx, caller.String(), id.String(), request)
+
+		assert.ErrorIs(t, err, customErrors.ErrAPIKeyPermissionDenied)
+	})
+
+	t.Run("Error - Invalid ID", func(t *testing.T) {
+		service := NewAPIKeyService(
+			repoMocks.NewAPIKeyRepository(t),
+			repoMocks.NewUserRepository(t),
+			repoMocks.NewRoleRepository(t),
+		)
+
+		_, err := service.Update(
+			ctx,
+			uuid.NewString(),
+			"not-a-uuid",
+			domain.APIKeyRequest{},
+		)
+
+		assert.ErrorIs(t, err, customErrors.ErrAPIKeyNotFound)
+	})
+}
+
+func TestAPIKeyService_Authenticate(t *testing.T) {
+	ctx := context.Background()
+	key := "gsk_0123456789ab_secret"
+	recently := time.Now().Add(-10 * time.Second)
+	expired := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name      string
+		key       string
+		setupMock func(repo *repoMocks.APIKeyRepository, apiKey *domain.APIKey)
+		apiKey    domain.APIKey
+		wantErr   error
+	}{
+		{
+			name: "Success - First Use Recorded",
+			key:  key,
+			setupMock: func(
+				repo *repoMocks.APIKeyRepository,
+				apiKey *domain.APIKey,
+			) {
+				repo.On("GetByKey", ctx, key).Return(apiKey, nil).Once()
+				repo.On(
+					"Touch",
+					ctx,
+					apiKey.ID.String(),
+					mock.AnythingOfType("time.Time"),
+				).Return(nil).Once()
+			},
+		},
+		{
+			name:   "Success - Recent Use Not Rewritten",
+			key:    key,
+			apiKey: domain.APIKey{LastUsedAt: &recently},
+			setupMock: func(
+				repo *repoMocks.APIKeyRepository,
+				apiKey *domain.APIKey,
+			) {
+				repo.On("GetByKey", ctx, key).Return(apiKey, nil).Once()
+			},
+		},
+		{
+			name: "Success - Tracking Failure Ignored",
+			key:  key,
+			setupMock: func(
+				repo *repoMocks.APIKeyRepository,
+				apiKey *domain.APIKey,
+			) {
+				repo.On("GetByKey", ctx, key).Return(apiKey, nil).Once()
+				repo.On(
+					"Touch",
+					ctx,
+					apiKey.ID.String(),
+					mock.AnythingOfType("time.Time"),
+				).Return(customErrors.ErrDBQuery).Once()
+			},
+		},
+		{
+			name:   "Error - Expired",
+			key:    key,
+			apiKey: domain.APIKey{ExpiresAt: &expired},
+			setupMock: func(
+				repo *repoMocks.APIKeyRepository,
+				apiKey *domain.APIKey,
+			) {
+				repo.On("GetByKey", ctx, key).Return(apiKey, nil).Once()
+			},
+			wantErr: customErrors.ErrInvalidAPIKey,
+		},
+		{
+			name: "Error - Unknown",
+			key:  key,
+			setupMock: func(repo *repoMocks.APIKeyRepository, _ *domain.APIKey) {
+				repo.On("GetByKey", ctx, key).
+					Return(nil, customErrors.ErrAPIKeyNotFound).Once()
+			},
+			wantErr: customErrors.ErrInvalidAPIKey,
+		},
+		{
+			name:      "Error - Not An API Key",
+			key:       "Bearer something",
+			setupMock: func(_ *repoMocks.APIKeyRepository, _ *domain.APIKey) {},
+			wantErr:   customErrors.ErrInvalidAPIKey,
+		},
+		{
+			name: "Error - Database",
+			key:  key,
+			setupMock: func(repo *repoMocks.APIKeyRepository, _ *domain.APIKey) {
+				repo.On("GetByKey", ctx, key).
+					Return(nil, customErrors.ErrDBQuery).Once()
+			},
+			wantErr: customErrors.ErrDBQuery,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repoMocks.NewAPIKeyRepository(t)
+			service := NewAPIKeyService(
+				repo,
+				repoMocks.NewUserRepository(t),
+				repoMocks.NewRoleRepository(t),
+			)
+			apiKey := tt.apiKey
+			apiKey.ID = uuid.New()
+			tt.setupMock(repo, &apiKey)
+
+			got, err := service.Authenticate(ctx, tt.key)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, apiKey.ID, got.ID)
+		})
+	}
+}
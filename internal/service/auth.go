@@ -2,44 +2,164 @@ package service
 
 import (
 	"context"
-	"encoding/json"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/grocery-service/internal/config"
 	"github.com/grocery-service/internal/domain"
 	repository "github.com/grocery-service/internal/repository/postgres"
+	"github.com/grocery-service/internal/service/notification"
+	"github.com/grocery-service/internal/service/oidc"
 	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/grocery-service/utils/jwt"
+	"github.com/grocery-service/utils/logger"
 	"golang.org/x/oauth2"
 )
 
 type (
 	AuthService interface {
-		GetAuthURL() string
+		// GetAuthURL starts a login with the named provider, or the
+		// default provider when none is named. The login is bound to a
+		// random state, nonce and PKCE verifier kept in the returned
+		// session.
+		GetAuthURL(
+			ctx context.Context,
+			login domain.LoginRequest,
+		) (*domain.AuthRequest, error)
+		// HandleCallback checks the callback against the login session,
+		// exchanges the authorization code and signs the user in once
+		// the provider's id_token has been verified.
 		HandleCallback(
 			ctx context.Context,
-			code string,
+			callback domain.AuthCallback,
 		) (*domain.AuthResponse, error)
 		RefreshToken(
 			ctx context.Context,
 			refreshToken string,
+			client domain.ClientInfo,
 		) (*domain.AuthResponse, error)
 		RevokeToken(ctx context.Context, token string) error
+		// ListSessions lists the user's signed-in sessions, most recently
+		// used first.
+		ListSessions(
+			ctx context.Context,
+			userID string,
+		) ([]domain.Session, error)
+		// RevokeSession signs one of the user's sessions out.
+		RevokeSession(ctx context.Context, userID, sessionID string) error
+		// RevokeAllSessions signs the user out everywhere and returns how
+		// many tokens were revoked.
+		RevokeAllSessions(ctx context.Context, userID string) (int64, error)
 		ValidateToken(ctx context.Context, token string) (*domain.User, error)
-		GetUserInfo(
+		// VerifyAccessToken checks the signature and expiry of an access
+		// token issued by this service, and that it has not been revoked
+		// since, by signing out or resetting the password.
+		VerifyAccessToken(
 			ctx context.Context,
 			token string,
-		) (*domain.UserInfo, error)
+		) (*domain.AccessTokenClaims, error)
+		// GetJWKS returns the public keys that verify access tokens.
+		GetJWKS() jwt.JWKSet
+		// Register creates a password account and emails a link to
+		// verify its address. The account cannot sign in until then. If
+		// the address already has an account its owner is emailed
+		// instead, and Register succeeds all the same.
+		Register(ctx context.Context, request domain.RegisterRequest) error
+		// LoginWithPassword signs a verified user in with their email
+		// and password. Repeated failures lock the account for a while.
+		LoginWithPassword(
+			ctx context.Context,
+			request domain.PasswordLoginRequest,
+			client domain.ClientInfo,
+		) (*domain.AuthResponse, error)
+		// VerifyEmail consumes an email verification token.
+		VerifyEmail(ctx context.Context, token string) error
+		// ResendVerification emails a new verification link if email
+		// belongs to an unverified account, and does nothing otherwise.
+		ResendVerification(ctx context.Context, email string) error
+		// RequestPasswordReset emails a reset link if email belongs to
+		// an account, and does nothing otherwise.
+		RequestPasswordReset(ctx context.Context, email string) error
+		// ResetPassword consumes a reset token, sets the new password
+		// and signs the user out everywhere.
+		ResetPassword(
+			ctx context.Context,
+			request domain.ResetPasswordRequest,
+		) error
+		// VerifyMFA completes a login that returned an MFA token with a
+		// TOTP or recovery code. A login that enrolls the user returns
+		// their recovery codes along with the tokens.
+		VerifyMFA(
+			ctx context.Context,
+			request domain.MFAVerifyRequest,
+			client domain.ClientInfo,
+		) (*domain.AuthResponse, error)
+		GetMFAStatus(
+			ctx context.Context,
+			userID string,
+		) (*domain.MFAStatus, error)
+		// EnrollMFA offers the user a new TOTP secret. MFA is enabled
+		// once ConfirmMFA receives a code for it.
+		EnrollMFA(
+			ctx context.Context,
+			userID string,
+		) (*domain.MFAEnrollment, error)
+		ConfirmMFA(
+			ctx context.Context,
+			userID string,
+			request domain.ConfirmMFARequest,
+		) (*domain.MFARecoveryCodes, error)
+		// DisableMFA turns MFA off after checking a code. Users required
+		// to use MFA cannot.
+		DisableMFA(ctx context.Context, userID, code string) error
+		// RegenerateRecoveryCodes replaces the user's recovery codes
+		// after checking a code.
+		RegenerateRecoveryCodes(
+			ctx context.Context,
+			userID string,
+			code string,
+		) (*domain.MFARecoveryCodes, error)
+		// StepUp checks a code and issues a new access token for the
+		// session that records the check, for routes that demand a
+		// recent one.
+		StepUp(
+			ctx context.Context,
+			userID string,
+			sessionID string,
+			code string,
+			client domain.ClientInfo,
+		) (*domain.AuthResponse, error)
+		// SetMFARequired sets whether a user must sign in with MFA.
+		SetMFARequired(ctx context.Context, userID string, required bool) error
+		// ResetMFA disables a user's MFA for one who has lost both their
+		// authenticator and their recovery codes.
+		ResetMFA(ctx context.Context, userID string) error
 	}
 
 	authService struct {
-		oauth2Config *oauth2.Config
-		userRepo     repository.UserRepository
-		tokenRepo    repository.TokenRepository
-		providerURL  string
-		allowedUsers []string
+		providers     *oidc.Registry
+		userRepo      repository.UserRepository
+		tokenRepo     repository.TokenRepository
+		roleRepo      repository.RoleRepository
+		mfaRepo       repository.MFARepository
+		mailer        notification.AccountMailer
+		allowedUsers  []string
+		redirects     []*url.URL
+		stateKey      []byte
+		keys          *jwt.KeySet
+		issuer        string
+		tokenDuration time.Duration
+		passwordAuth  config.PasswordAuthConfig
+		mfa           config.MFAConfig
+		mfaKey        []byte
+		secretKey     []byte
 	}
 )
 
@@ -47,185 +167,523 @@ func NewAuthService(
 	cfg config.Config,
 	userRepo repository.UserRepository,
 	tokenRepo repository.TokenRepository,
+	roleRepo repository.RoleRepository,
+	mfaRepo repository.MFARepository,
+	mailer notification.AccountMailer,
 	allowedUsers []string,
-) AuthService {
-	endpoint := oauth2.Endpoint{
-		AuthURL:  cfg.OAuth.ProviderURL + cfg.OAuth.AuthorizeEndpoint,
-		TokenURL: cfg.OAuth.ProviderURL + cfg.OAuth.TokenEndpoint,
+) (AuthService, error) {
+	retired := make([]jwt.Key, 0, len(cfg.JWT.RetiredKeys))
+	for _, entry := range cfg.JWT.RetiredKeys {
+		key, err := jwt.ParseKey(entry)
+		if err != nil {
+			return nil, err
+		}
+		retired = append(retired, key)
 	}
 
-	oauthConfig := &oauth2.Config{
-		ClientID:     cfg.OAuth.ClientID,
-		ClientSecret: cfg.OAuth.ClientSecret,
-		RedirectURL:  cfg.OAuth.RedirectURL,
-		Scopes:       cfg.OAuth.Scopes,
-		Endpoint:     endpoint,
+	keys, err := jwt.NewKeySet(
+		jwt.Key{ID: cfg.JWT.KeyID, Secret: cfg.JWT.Secret},
+		retired...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load JWT signing keys: %w", err)
 	}
 
-	return &authService{
-		oauth2Config: oauthConfig,
-		userRepo:     userRepo,
-		tokenRepo:    tokenRepo,
-		providerURL:  cfg.OAuth.ProviderURL,
-		allowedUsers: allowedUsers,
+	redirects, err := parseAllowedRedirects(cfg.OAuth.AllowedRedirects)
+	if err != nil {
+		return nil, err
 	}
+
+	providers := oidc.NewRegistry(
+		cfg.OAuth,
+		&http.Client{Timeout: 10 * time.Second},
+	)
+
+	return &authService{
+		providers:     providers,
+		userRepo:      userRepo,
+		tokenRepo:     tokenRepo,
+		roleRepo:      roleRepo,
+		mfaRepo:       mfaRepo,
+		mailer:        mailer,
+		allowedUsers:  allowedUsers,
+		redirects:     redirects,
+		stateKey:      loginStateKey(cfg.JWT.Secret),
+		keys:          keys,
+		issuer:        cfg.JWT.Issuer,
+		tokenDuration: cfg.JWT.TokenDuration,
+		passwordAuth:  cfg.PasswordAuth,
+		mfa:           cfg.MFA,
+		mfaKey:        mfaChallengeKey(cfg.JWT.Secret),
+		secretKey:     mfaSecretKey(cfg.JWT.TokenHashKey),
+	}, nil
 }
 
-func (s *authService) GetAuthURL() string {
-	return s.oauth2Config.AuthCodeURL(
-		"state",
-		oauth2.SetAuthURLParam("response_type", "code"),
-		oauth2.SetAuthURLParam("audience", s.providerURL+"/api/v2/"),
+func (s *authService) GetAuthURL(
+	ctx context.Context,
+	login domain.LoginRequest,
+) (*domain.AuthRequest, error) {
+	p, err := s.providers.Get(login.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkRedirect(login.RedirectTo, s.redirects); err != nil {
+		return nil, err
+	}
+
+	state, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(loginStateTTL)
+	verifier := oauth2.GenerateVerifier()
+	session, err := sealLoginState(s.stateKey, loginState{
+		Provider:     p.Name(),
+		State:        state,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		RedirectTo:   login.RedirectTo,
+		ExpiresAt:    expiresAt.Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	url, err := p.AuthCodeURL(
+		ctx,
+		state,
+		nonce,
+		oauth2.S256ChallengeOption(verifier),
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.AuthRequest{
+		URL:       url,
+		Session:   session,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// randomToken returns 256 random bits, base64url encoded.
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
 }
 
-func (s *authService) createUserAndTokens(
+// createUser signs up the user behind a provider login seen for the
+// first time. The address only counts as verified when the provider says
+// so.
+func (s *authService) createUser(
 	ctx context.Context,
 	userInfo *domain.UserInfo,
-	oauth2Token *oauth2.Token,
+	identity *domain.UserIdentity,
 ) (*domain.User, error) {
+	now := time.Now()
 	user := &domain.User{
 		ID:        uuid.New(),
 		Email:     userInfo.Email,
 		Name:      userInfo.Name,
 		Picture:   userInfo.Picture,
 		Role:      domain.CustomerRole,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if userInfo.VerifiedEmail {
+		user.EmailVerifiedAt = &now
 	}
 
-	if err := s.userRepo.Create(ctx, user); err != nil {
+	identity.UserID = user.ID
+	if err := s.userRepo.Create(ctx, user, identity); err != nil {
 		return nil, fmt.Errorf(
 			"failed to create user: %w",
 			err,
 		)
 	}
 
-	if err := s.storeTokens(ctx, user.ID, userInfo.ID, oauth2Token); err != nil {
+	return user, nil
+}
+
+// linkUser signs in a provider login seen for the first time. It only
+// joins an existing account with the same email address when the provider
+// asserts the address is verified; otherwise anyone who can put that
+// address on a provider account could sign in as its owner. An account
+// whose own address was never verified is claimed by the verified login,
+// which unlinks whoever registered it.
+func (s *authService) linkUser(
+	ctx context.Context,
+	provider string,
+	userInfo *domain.UserInfo,
+) (*domain.User, error) {
+	identity := &domain.UserIdentity{
+		ID:        uuid.New(),
+		Provider:  provider,
+		Subject:   userInfo.ID,
+		CreatedAt: time.Now(),
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, userInfo.Email)
+	if errors.Is(err, customErrors.ErrUserNotFound) {
+		return s.createUser(ctx, userInfo, identity)
+	}
+	if err != nil {
 		return nil, err
 	}
 
+	if !userInfo.VerifiedEmail {
+		return nil, fmt.Errorf(
+			"%w: email address is not verified",
+			customErrors.ErrUnauthorized,
+		)
+	}
+
+	identity.UserID = user.ID
+	if user.EmailVerifiedAt != nil {
+		if err := s.userRepo.AddIdentity(ctx, identity); err != nil {
+			return nil, err
+		}
+		return user, nil
+	}
+
+	if err := s.userRepo.ClaimAccount(ctx, identity); err != nil {
+		return nil, err
+	}
+	user.EmailVerifiedAt = &identity.CreatedAt
 	return user, nil
 }
 
-func (s *authService) storeTokens(
+// refreshTokenDuration is how long a refresh token stays usable. Every
+// refresh rotates the token, so an active session never expires.
+const refreshTokenDuration = 30 * 24 * time.Hour
+
+// grant describes the session tokens are issued for.
+type grant struct {
+	provider   string
+	providerID string
+	familyID   uuid.UUID
+	client     domain.ClientInfo
+	// rotated is the refresh token being exchanged, if any. It is
+	// revoked in the same transaction, so it can be used only once.
+	rotated *domain.Token
+	// mfaAt is when the user last passed MFA in this session, if ever.
+	mfaAt time.Time
+}
+
+// issueTokens signs a new access token for the user and issues a refresh
+// token in the grant's token family.
+func (s *authService) issueTokens(
 	ctx context.Context,
-	userID uuid.UUID,
-	providerID string,
-	oauth2Token *oauth2.Token,
-) error {
-	accessToken := &domain.Token{
-		UserID:     userID,
-		Token:      oauth2Token.AccessToken,
-		Type:       domain.TokenTypeAccess,
-		ExpiresAt:  oauth2Token.Expiry,
-		Provider:   "google",
-		ProviderID: providerID,
-		CreatedAt:  time.Now(),
-		UpdatedAt:  time.Now(),
+	user *domain.User,
+	g grant,
+) (*domain.AuthResponse, error) {
+	accessToken, expiresAt, err := s.signAccessToken(ctx, user, g)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := s.tokenRepo.Create(ctx, accessToken); err != nil {
-		return fmt.Errorf(
-			"failed to store access token: %w",
+	now := time.Now()
+	refreshToken, err := randomToken()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to generate refresh token: %w",
 			err,
 		)
 	}
 
-	if oauth2Token.RefreshToken != "" {
-		refreshToken := &domain.Token{
-			UserID:     userID,
-			Token:      oauth2Token.RefreshToken,
-			Type:       domain.TokenTypeRefresh,
-			ExpiresAt:  time.Now().AddDate(0, 1, 0),
-			Provider:   "google",
-			ProviderID: providerID,
-			CreatedAt:  time.Now(),
-			UpdatedAt:  time.Now(),
-		}
-		if err := s.tokenRepo.Create(ctx, refreshToken); err != nil {
-			return fmt.Errorf(
-				"failed to store refresh token: %w",
-				err,
-			)
+	refresh := &domain.Token{
+		UserID:     user.ID,
+		Token:      refreshToken,
+		Type:       domain.TokenTypeRefresh,
+		FamilyID:   g.familyID,
+		ExpiresAt:  now.Add(refreshTokenDuration),
+		Provider:   g.provider,
+		ProviderID: g.providerID,
+		IPAddress:  g.client.IPAddress,
+		UserAgent:  g.client.UserAgent,
+		Device:     describeDevice(g.client.UserAgent),
+		LastUsedAt: now,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	if g.rotated == nil {
+		err = s.tokenRepo.Create(ctx, refresh)
+	} else {
+		err = s.tokenRepo.RotateToken(ctx, g.rotated, refresh)
+		if errors.Is(err, customErrors.ErrTokenNotFound) {
+			// Another request exchanged the token first
+			s.revokeFamily(ctx, g.rotated)
+			return nil, customErrors.ErrInvalidToken
 		}
 	}
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to store refresh token: %w",
+			err,
+		)
+	}
+
+	if err := s.storeAccessToken(
+		ctx,
+		user,
+		g,
+		accessToken,
+		expiresAt,
+	); err != nil {
+		return nil, err
+	}
+
+	return &domain.AuthResponse{
+		AccessToken:  accessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(s.tokenDuration.Seconds()),
+		RefreshToken: refreshToken,
+		User:         user,
+	}, nil
+}
+
+// signAccessToken signs an access token for the user in the grant's
+// session.
+func (s *authService) signAccessToken(
+	ctx context.Context,
+	user *domain.User,
+	g grant,
+) (string, time.Time, error) {
+	permissions, err := s.permissions(ctx, user.Role)
+	if err != nil {
+		return "", time.Time{}, err
+	}
 
+	now := time.Now()
+	expiresAt := now.Add(s.tokenDuration)
+
+	claims := jwt.Claims{
+		Issuer:      s.issuer,
+		Subject:     user.ID.String(),
+		ID:          uuid.NewString(),
+		IssuedAt:    now.Unix(),
+		ExpiresAt:   expiresAt.Unix(),
+		Email:       user.Email,
+		Role:        string(user.Role),
+		SessionID:   g.familyID.String(),
+		Permissions: permissions,
+	}
+	if !g.mfaAt.IsZero() {
+		claims.MFAAt = g.mfaAt.Unix()
+	}
+
+	accessToken, err := s.keys.Sign(claims)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf(
+			"failed to sign access token: %w",
+			err,
+		)
+	}
+
+	return accessToken, expiresAt, nil
+}
+
+func (s *authService) storeAccessToken(
+	ctx context.Context,
+	user *domain.User,
+	g grant,
+	accessToken string,
+	expiresAt time.Time,
+) error {
+	now := time.Now()
+	if err := s.tokenRepo.Create(ctx, &domain.Token{
+		UserID:     user.ID,
+		Token:      accessToken,
+		Type:       domain.TokenTypeAccess,
+		FamilyID:   g.familyID,
+		ExpiresAt:  expiresAt,
+		Provider:   g.provider,
+		ProviderID: g.providerID,
+		IPAddress:  g.client.IPAddress,
+		UserAgent:  g.client.UserAgent,
+		Device:     describeDevice(g.client.UserAgent),
+		LastUsedAt: now,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}); err != nil {
+		return fmt.Errorf("failed to store access token: %w", err)
+	}
 	return nil
 }
 
+// revokeFamily revokes every token issued in the session of a refresh
+// token that was presented after it had been used. Either the user or an
+// attacker holds a stolen copy, and there is no telling which.
+func (s *authService) revokeFamily(
+	ctx context.Context,
+	reused *domain.Token,
+) {
+	revoked, err := s.tokenRepo.RevokeFamily(ctx, reused.FamilyID.String())
+	if err != nil {
+		logger.Error(
+			"security: failed to revoke token family after refresh token reuse",
+			logger.String("user_id", reused.UserID.String()),
+			logger.String("family_id", reused.FamilyID.String()),
+			logger.Error64("error", err),
+		)
+		return
+	}
+
+	logger.Warn(
+		"security: refresh token reuse detected, token family revoked",
+		logger.String("user_id", reused.UserID.String()),
+		logger.String("family_id", reused.FamilyID.String()),
+		logger.String("token_id", reused.ID.String()),
+		logger.Int("revoked", int(revoked)),
+	)
+}
+
 func (s *authService) HandleCallback(
 	ctx context.Context,
-	code string,
+	callback domain.AuthCallback,
 ) (*domain.AuthResponse, error) {
-	fmt.Printf("Exchanging code for token: %s\n", code)
+	login, err := openLoginState(
+		s.stateKey,
+		callback.Session,
+		callback.State,
+		time.Now(),
+	)
+	if err != nil {
+		return nil, err
+	}
 
-	oauth2Token, err := s.oauth2Config.Exchange(ctx, code)
+	p, err := s.providers.Get(login.Provider)
 	if err != nil {
-		fmt.Printf("Token exchange error: %v\n", err)
-		return nil, fmt.Errorf("failed to exchange token: %w", err)
+		return nil, err
 	}
 
-	fmt.Printf("Token exchange successful, getting user info\n")
+	oauth2Token, err := p.Exchange(
+		ctx,
+		callback.Code,
+		oauth2.VerifierOption(login.CodeVerifier),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rawIDToken, _ := oauth2Token.Extra("id_token").(string)
+	if rawIDToken == "" {
+		return nil, fmt.Errorf(
+			"%w: provider %s returned no id_token",
+			customErrors.ErrInvalidIDToken,
+			p.Name(),
+		)
+	}
 
-	userInfo, err := s.GetUserInfo(ctx, oauth2Token.AccessToken)
+	claims, err := p.VerifyIDToken(ctx, rawIDToken, login.Nonce)
 	if err != nil {
-		fmt.Printf("User info error: %v\n", err)
 		return nil, err
 	}
 
-	if len(s.allowedUsers) > 0 {
-		isAllowed := false
-		for _, allowedEmail := range s.allowedUsers {
-			if userInfo.Email == allowedEmail {
-				isAllowed = true
-				break
-			}
-		}
-		if !isAllowed {
-			return nil, customErrors.ErrUnauthorized
-		}
+	userInfo, err := s.userInfo(ctx, p, claims, oauth2Token.AccessToken)
+	if err != nil {
+		return nil, err
 	}
 
-	user, err := s.userRepo.GetByEmail(ctx, userInfo.Email)
+	if !s.isAllowed(userInfo.Email) {
+		return nil, customErrors.ErrUnauthorized
+	}
+
+	user, err := s.userRepo.GetByIdentity(ctx, p.Name(), claims.Subject)
+	if errors.Is(err, customErrors.ErrUserNotFound) {
+		user, err = s.linkUser(ctx, p.Name(), userInfo)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.completeLogin(ctx, user, grant{
+		provider:   p.Name(),
+		providerID: claims.Subject,
+		familyID:   uuid.New(),
+		client:     callback.Client,
+	}, login.RedirectTo)
+}
+
+// userInfo builds the user's profile from verified id_token claims,
+// falling back to the userinfo endpoint for providers that leave the
+// email out of the id_token. Either way the email address only counts as
+// verified when the provider asserts email_verified.
+func (s *authService) userInfo(
+	ctx context.Context,
+	p *oidc.Provider,
+	claims *oidc.IDTokenClaims,
+	accessToken string,
+) (*domain.UserInfo, error) {
+	userInfo := &domain.UserInfo{
+		ID:            claims.Subject,
+		Email:         normalizeEmail(claims.Email),
+		VerifiedEmail: claims.EmailVerified != nil && *claims.EmailVerified,
+		Name:          claims.Name,
+		Picture:       claims.Picture,
+	}
+	if userInfo.Email != "" {
+		return userInfo, nil
+	}
+
+	fetched, err := p.UserInfo(ctx, accessToken)
 	if err != nil {
-		user, err = s.createUserAndTokens(
-			ctx,
-			userInfo,
-			oauth2Token,
+		return nil, err
+	}
+	if fetched.ID != claims.Subject {
+		return nil, fmt.Errorf(
+			"%w: userinfo subject does not match id_token",
+			customErrors.ErrInvalidIDToken,
+		)
+	}
+	fetched.Email = normalizeEmail(fetched.Email)
+	if fetched.Email == "" {
+		return nil, fmt.Errorf(
+			"%w: provider %s did not return an email address",
+			customErrors.ErrUnauthorized,
+			p.Name(),
 		)
-		if err != nil {
-			return nil, err
-		}
-	} else {
-		if err := s.storeTokens(ctx, user.ID, userInfo.ID, oauth2Token); err != nil {
-			return nil, err
-		}
 	}
 
-	return &domain.AuthResponse{
-		AccessToken: oauth2Token.AccessToken,
-		TokenType:   oauth2Token.TokenType,
-		ExpiresIn: int(
-			time.Until(oauth2Token.Expiry).Seconds(),
-		),
-		RefreshToken: oauth2Token.RefreshToken,
-		User:         user,
-	}, nil
+	return fetched, nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
 }
 
+// RefreshToken exchanges a refresh token for a new access token and a
+// new refresh token in the same family. A refresh token that has already
+// been exchanged revokes its whole family.
 func (s *authService) RefreshToken(
 	ctx context.Context,
 	refreshToken string,
+	client domain.ClientInfo,
 ) (*domain.AuthResponse, error) {
-	token, err := s.tokenRepo.GetByToken(ctx, refreshToken)
-	if err != nil {
+	token, err := s.tokenRepo.GetByTokenIncludingRevoked(ctx, refreshToken)
+	if err != nil || token.Type != domain.TokenTypeRefresh {
 		return nil, customErrors.ErrInvalidToken
 	}
 
-	if !s.tokenRepo.IsValid(ctx, refreshToken) {
+	if token.RevokedAt != nil {
+		s.revokeFamily(ctx, token)
 		return nil, customErrors.ErrInvalidToken
 	}
+
+	if !token.ExpiresAt.After(time.Now()) {
+		return nil, customErrors.ErrInvalidToken
+	}
+
 	user, err := s.userRepo.GetByID(
 		ctx,
 		token.UserID.String(),
@@ -234,31 +692,13 @@ func (s *authService) RefreshToken(
 		return nil, customErrors.ErrUserNotFound
 	}
 
-	oauth2Token := &oauth2.Token{
-		RefreshToken: refreshToken,
-	}
-	newToken, err := s.oauth2Config.TokenSource(ctx, oauth2Token).
-		Token()
-	if err != nil {
-		return nil, fmt.Errorf(
-			"failed to refresh token: %w",
-			err,
-		)
-	}
-
-	if err := s.storeTokens(ctx, user.ID, token.ProviderID, newToken); err != nil {
-		return nil, err
-	}
-
-	return &domain.AuthResponse{
-		AccessToken: newToken.AccessToken,
-		TokenType:   newToken.TokenType,
-		ExpiresIn: int(
-			time.Until(newToken.Expiry).Seconds(),
-		),
-		RefreshToken: refreshToken,
-		User:         user,
-	}, nil
+	return s.issueTokens(ctx, user, grant{
+		provider:   token.Provider,
+		providerID: token.ProviderID,
+		familyID:   token.FamilyID,
+		client:     client,
+		rotated:    token,
+	})
 }
 
 func (s *authService) RevokeToken(
@@ -291,32 +731,78 @@ func (s *authService) ValidateToken(
 	return user, nil
 }
 
-func (s *authService) GetUserInfo(
+func (s *authService) VerifyAccessToken(
 	ctx context.Context,
 	token string,
-) (*domain.UserInfo, error) {
-	client := &http.Client{}
-	req, err := http.NewRequest("GET", s.providerURL+"/userinfo", nil)
+) (*domain.AccessTokenClaims, error) {
+	claims, err := s.keys.Verify(token, s.issuer, time.Now())
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, customErrors.ErrTokenExpired
+		}
+		return nil, customErrors.ErrInvalidToken
+	}
+
+	// The signature is checked first so forged tokens never reach the
+	// database
+	if !s.tokenRepo.IsValid(ctx, token) {
+		return nil, customErrors.ErrInvalidToken
 	}
 
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Accept", "application/json")
-	resp, err := client.Do(req)
+	userID, err := uuid.Parse(claims.Subject)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch user info: %w", err)
+		return nil, customErrors.ErrInvalidToken
+	}
+
+	// Tokens issued before sessions carry no session ID
+	sessionID, _ := uuid.Parse(claims.SessionID)
+
+	var mfaVerifiedAt time.Time
+	if claims.MFAAt != 0 {
+		mfaVerifiedAt = time.Unix(claims.MFAAt, 0)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to fetch user info: %d", resp.StatusCode)
+	permissions := make([]domain.Permission, 0, len(claims.Permissions))
+	for _, p := range claims.Permissions {
+		permissions = append(permissions, domain.Permission(p))
+	}
+
+	return &domain.AccessTokenClaims{
+		UserID:        userID,
+		SessionID:     sessionID,
+		Email:         claims.Email,
+		Role:          domain.UserRole(claims.Role),
+		Permissions:   permissions,
+		MFAVerifiedAt: mfaVerifiedAt,
+		ExpiresAt:     time.Unix(claims.ExpiresAt, 0),
+	}, nil
+}
+
+// permissions looks up the permissions of a role for an access token. A
+// role that no longer exists grants nothing.
+func (s *authService) permissions(
+	ctx context.Context,
+	role domain.UserRole,
+) ([]string, error) {
+	r, err := s.roleRepo.GetByName(ctx, role)
+	if errors.Is(err, customErrors.ErrRoleNotFound) {
+		logger.Warn(
+			"user has an unknown role",
+			logger.String("role", string(role)),
+		)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load role permissions: %w", err)
 	}
 
-	var userInfo domain.UserInfo
-	if err := json.NewDecoder(resp.Body).Decode(&userInfo); err != nil {
-		return nil, fmt.Errorf("failed to decode user info: %w", err)
+	permissions := make([]string, 0, len(r.Permissions))
+	for _, p := range r.Permissions {
+		permissions = append(permissions, string(p))
 	}
+	return permissions, nil
+}
 
-	return &userInfo, nil
+func (s *authService) GetJWKS() jwt.JWKSet {
+	return s.keys.JWKS()
 }
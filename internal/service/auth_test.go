@@ -2,63 +2,161 @@ package service
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/grocery-service/internal/config"
 	"github.com/grocery-service/internal/domain"
 	repoMocks "github.com/grocery-service/tests/mocks/repository"
 	serviceMocks "github.com/grocery-service/tests/mocks/service"
+	"github.com/grocery-service/tests/oidctest"
 	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/grocery-service/utils/jwt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
 )
 
+var testJWTConfig = config.JWTConfig{
+	Secret:        "test-jwt-secret",
+	KeyID:         "2",
+	Issuer:        "grocery-service",
+	TokenDuration: 15 * time.Minute,
+	RetiredKeys:   []string{"1:old-jwt-secret"},
+}
+
+// testOAuthConfig configures a single provider named keycloak backed by
+// the fake provider.
+func testOAuthConfig(provider *oidctest.Provider) config.OAuthConfig {
+	return config.OAuthConfig{
+		RedirectURL: "http://localhost:8080/callback",
+		Providers: []config.OIDCProviderConfig{{
+			Name:         "keycloak",
+			IssuerURL:    provider.Issuer(),
+			ClientID:     provider.ClientID,
+			ClientSecret: "test-secret",
+			Scopes:       []string{"openid", "email", "profile"},
+		}},
+	}
+}
+
+// testRoleRepo grants every role the orders:create permission.
+func testRoleRepo(t *testing.T) *repoMocks.RoleRepository {
+	roleRepo := repoMocks.NewRoleRepository(t)
+	roleRepo.On("GetByName", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, name domain.UserRole) (*domain.Role, error) {
+			return &domain.Role{
+				Name:        name,
+				Permissions: []domain.Permission{domain.PermissionOrdersCreate},
+			}, nil
+		}).
+		Maybe()
+	return roleRepo
+}
+
 func TestNewAuthService(t *testing.T) {
 	mockUserRepo := repoMocks.NewUserRepository(t)
 	mockTokenRepo := repoMocks.NewTokenRepository(t)
 	cfg := config.Config{
-		OAuth: config.OAuthConfig{
-			ClientID:     "test-client-id",
-			ClientSecret: "test-secret",
-			RedirectURL:  "http://localhost:8080/callback",
-			Scopes:       []string{"email", "profile"},
-		},
+		JWT:   testJWTConfig,
+		OAuth: testOAuthConfig(oidctest.New(t, "test-client-id")),
 	}
-	service := NewAuthService(
+	service, err := NewAuthService(
 		cfg,
 		mockUserRepo,
 		mockTokenRepo,
+		testRoleRepo(t),
+		repoMocks.NewMFARepository(t),
+		serviceMocks.NewAccountMailer(t),
 		[]string{"test@example.com"},
 	)
+	require.NoError(t, err)
 	assert.NotNil(t, service)
 }
 
 func TestGetAuthURL(t *testing.T) {
 	mockUserRepo := repoMocks.NewUserRepository(t)
 	mockTokenRepo := repoMocks.NewTokenRepository(t)
+	provider := oidctest.New(t, "test-client-id")
+	oauthCfg := testOAuthConfig(provider)
+	oauthCfg.AllowedRedirects = []string{"https://shop.example.com/app"}
 	cfg := config.Config{
-		OAuth: config.OAuthConfig{
-			ClientID:     "test-client-id",
-			ClientSecret: "test-secret",
-			RedirectURL:  "http://localhost:8080/callback",
-			Scopes:       []string{"email", "profile"},
-		},
+		JWT:   testJWTConfig,
+		OAuth: oauthCfg,
 	}
-	service := NewAuthService(
+	service, err := NewAuthService(
 		cfg,
 		mockUserRepo,
 		mockTokenRepo,
+		testRoleRepo(t),
+		repoMocks.NewMFARepository(t),
+		serviceMocks.NewAccountMailer(t),
 		[]string{},
 	)
-	url := service.GetAuthURL()
-	assert.Contains(t, url, "client_id=test-client-id")
-	assert.Contains(
+	require.NoError(t, err)
+
+	authRequest, err := service.GetAuthURL(
+		context.Background(),
+		domain.LoginRequest{},
+	)
+	require.NoError(t, err)
+
+	authURL, err := url.Parse(authRequest.URL)
+	require.NoError(t, err)
+	query := authURL.Query()
+	assert.True(t, strings.HasPrefix(authRequest.URL, provider.URL+"/authorize?"))
+	assert.Equal(t, "test-client-id", query.Get("client_id"))
+	assert.Equal(t, "http://localhost:8080/callback", query.Get("redirect_uri"))
+	assert.NotEmpty(t, query.Get("state"))
+	assert.NotEmpty(t, query.Get("nonce"))
+	assert.NotEmpty(t, query.Get("code_challenge"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+	assert.NotEmpty(t, authRequest.Session)
+	assert.WithinDuration(
+		t,
+		time.Now().Add(loginStateTTL),
+		authRequest.ExpiresAt,
+		time.Minute,
+	)
+
+	next, err := service.GetAuthURL(
+		context.Background(),
+		domain.LoginRequest{Provider: "keycloak"},
+	)
+	require.NoError(t, err)
+	nextURL, err := url.Parse(next.URL)
+	require.NoError(t, err)
+	assert.NotEqual(t, query.Get("state"), nextURL.Query().Get("state"))
+	assert.NotEqual(t, query.Get("nonce"), nextURL.Query().Get("nonce"))
+	assert.NotEqual(
 		t,
-		url,
-		"redirect_uri=http%3A%2F%2Flocalhost%3A8080%2Fcallback",
+		query.Get("code_challenge"),
+		nextURL.Query().Get("code_challenge"),
+	)
+
+	_, err = service.GetAuthURL(
+		context.Background(),
+		domain.LoginRequest{Provider: "google"},
+	)
+	assert.ErrorIs(t, err, customErrors.ErrUnknownProvider)
+
+	_, err = service.GetAuthURL(
+		context.Background(),
+		domain.LoginRequest{RedirectTo: "https://shop.example.com/app/orders"},
+	)
+	assert.NoError(t, err)
+
+	_, err = service.GetAuthURL(
+		context.Background(),
+		domain.LoginRequest{RedirectTo: "https://evil.example.com/app"},
 	)
+	assert.ErrorIs(t, err, customErrors.ErrInvalidRedirect)
 }
 
 func TestHandleCallback(t *testing.T) {
@@ -80,7 +178,7 @@ func TestHandleCallback(t *testing.T) {
 			name: "successful new user authentication",
 			code: "valid-code",
 			setupMocks: func() {
-				mockAuthService.On("HandleCallback", mock.Anything, "valid-code").
+				mockAuthService.On("HandleCallback", mock.Anything, domain.AuthCallback{Code: "valid-code", State: "state-1", Session: "session"}).
 					Return(&domain.AuthResponse{
 						AccessToken:  "new-access-token",
 						TokenType:    "Bearer",
@@ -95,7 +193,7 @@ func TestHandleCallback(t *testing.T) {
 			name: "existing user authentication",
 			code: "valid-code",
 			setupMocks: func() {
-				mockAuthService.On("HandleCallback", mock.Anything, "valid-code").
+				mockAuthService.On("HandleCallback", mock.Anything, domain.AuthCallback{Code: "valid-code", State: "state-1", Session: "session"}).
 					Return(&domain.AuthResponse{
 						AccessToken:  "new-access-token",
 						TokenType:    "Bearer",
@@ -115,7 +213,11 @@ func TestHandleCallback(t *testing.T) {
 
 			resp, err := service.HandleCallback(
 				ctx,
-				tt.code,
+				domain.AuthCallback{
+					Code:    tt.code,
+					State:   "state-1",
+					Session: "session",
+				},
 			)
 
 			if tt.expectedError != nil {
@@ -152,7 +254,7 @@ func TestRefreshToken(t *testing.T) {
 			name:         "successful token refresh",
 			refreshToken: "valid-refresh-token",
 			setupMocks: func() {
-				mockAuthService.On("RefreshToken", mock.Anything, "valid-refresh-token").
+				mockAuthService.On("RefreshToken", mock.Anything, "valid-refresh-token", domain.ClientInfo{}).
 					Return(&domain.AuthResponse{
 						AccessToken:  "new-access-token",
 						TokenType:    "Bearer",
@@ -167,7 +269,7 @@ func TestRefreshToken(t *testing.T) {
 			name:         "invalid token",
 			refreshToken: "invalid-token",
 			setupMocks: func() {
-				mockAuthService.On("RefreshToken", mock.Anything, "invalid-token").
+				mockAuthService.On("RefreshToken", mock.Anything, "invalid-token", domain.ClientInfo{}).
 					Return(nil, customErrors.ErrInvalidToken)
 			},
 			expectedError: customErrors.ErrInvalidToken,
@@ -182,6 +284,7 @@ func TestRefreshToken(t *testing.T) {
 			resp, err := service.RefreshToken(
 				ctx,
 				tt.refreshToken,
+				domain.ClientInfo{},
 			)
 			if tt.expectedError != nil {
 				assert.Error(t, err)
@@ -246,15 +349,20 @@ func TestValidateToken(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setupMocks()
 			cfg := config.Config{
+				JWT:   testJWTConfig,
 				OAuth: config.OAuthConfig{},
 			}
 
-			service := NewAuthService(
+			service, err := NewAuthService(
 				cfg,
 				mockUserRepo,
 				mockTokenRepo,
+				testRoleRepo(t),
+				repoMocks.NewMFARepository(t),
+				serviceMocks.NewAccountMailer(t),
 				[]string{},
 			)
+			require.NoError(t, err)
 
 			user, err := service.ValidateToken(
 				ctx,
@@ -308,17 +416,22 @@ func TestRevokeToken(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setupMocks()
 			cfg := config.Config{
+				JWT:   testJWTConfig,
 				OAuth: config.OAuthConfig{},
 			}
 
-			service := NewAuthService(
+			service, err := NewAuthService(
 				cfg,
 				mockUserRepo,
 				mockTokenRepo,
+				testRoleRepo(t),
+				repoMocks.NewMFARepository(t),
+				serviceMocks.NewAccountMailer(t),
 				[]string{},
 			)
+			require.NoError(t, err)
 
-			err := service.RevokeToken(ctx, tt.token)
+			err = service.RevokeToken(ctx, tt.token)
 
 			if tt.expectedError != nil {
 				assert.Error(t, err)
@@ -328,3 +441,1049 @@ func TestRevokeToken(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleCallbackIssuesAccessToken(t *testing.T) {
+	provider := oidctest.New(t, "test-client-id")
+	verifiedAt := time.Now()
+	user := &domain.User{
+		ID:              uuid.New(),
+		Email:           "admin@example.com",
+		Role:            domain.AdminRole,
+		EmailVerifiedAt: &verifiedAt,
+	}
+
+	tests := []struct {
+		name          string
+		idToken       map[string]any
+		userInfo      map[string]any
+		nonce         string
+		setupMocks    func(*repoMocks.UserRepository, *repoMocks.TokenRepository)
+		expectedError error
+	}{
+		{
+			name:    "verified id_token",
+			idToken: provider.Claims("kc|123", user.Email, "nonce-1"),
+			nonce:   "nonce-1",
+			setupMocks: func(
+				userRepo *repoMocks.UserRepository,
+				tokenRepo *repoMocks.TokenRepository,
+			) {
+				userRepo.On("GetByIdentity", mock.Anything, "keycloak", "kc|123").
+					Return(user, nil)
+				tokenRepo.On("Create", mock.Anything, mock.MatchedBy(
+					func(token *domain.Token) bool {
+						return token.Type == domain.TokenTypeAccess &&
+							token.Provider == "keycloak" &&
+							token.ProviderID == "kc|123" &&
+							token.FamilyID != uuid.Nil
+					},
+				)).Return(nil).Once()
+				tokenRepo.On("Create", mock.Anything, mock.MatchedBy(
+					func(token *domain.Token) bool {
+						return token.Type == domain.TokenTypeRefresh &&
+							token.Provider == "keycloak" &&
+							token.Token != "provider-refresh-token" &&
+							token.FamilyID != uuid.Nil
+					},
+				)).Return(nil).Once()
+			},
+		},
+		{
+			name: "email from userinfo",
+			idToken: func() map[string]any {
+				claims := provider.Claims("kc|123", "", "nonce-1")
+				delete(claims, "email")
+				return claims
+			}(),
+			userInfo: map[string]any{
+				"sub":            "kc|123",
+				"email":          "Admin@Example.com",
+				"email_verified": true,
+			},
+			nonce: "nonce-1",
+			setupMocks: func(
+				userRepo *repoMocks.UserRepository,
+				tokenRepo *repoMocks.TokenRepository,
+			) {
+				userRepo.On("GetByIdentity", mock.Anything, "keycloak", "kc|123").
+					Return(nil, customErrors.ErrUserNotFound)
+				userRepo.On("GetByEmail", mock.Anything, user.Email).
+					Return(user, nil)
+				userRepo.On("AddIdentity", mock.Anything, mock.MatchedBy(
+					func(identity *domain.UserIdentity) bool {
+						return identity.UserID == user.ID &&
+							identity.Provider == "keycloak" &&
+							identity.Subject == "kc|123"
+					},
+				)).Return(nil)
+				tokenRepo.On("Create", mock.Anything, mock.Anything).
+					Return(nil).Twice()
+			},
+		},
+		{
+			name: "unverified email from userinfo",
+			idToken: func() map[string]any {
+				claims := provider.Claims("kc|123", "", "nonce-1")
+				delete(claims, "email")
+				delete(claims, "email_verified")
+				return claims
+			}(),
+			userInfo: map[string]any{"sub": "kc|123", "email": user.Email},
+			nonce:    "nonce-1",
+			setupMocks: func(
+				userRepo *repoMocks.UserRepository,
+				_ *repoMocks.TokenRepository,
+			) {
+				userRepo.On("GetByIdentity", mock.Anything, "keycloak", "kc|123").
+					Return(nil, customErrors.ErrUserNotFound)
+				userRepo.On("GetByEmail", mock.Anything, user.Email).
+					Return(user, nil)
+			},
+			expectedError: customErrors.ErrUnauthorized,
+		},
+		{
+			name: "userinfo for another subject",
+			idToken: func() map[string]any {
+				claims := provider.Claims("kc|123", "", "nonce-1")
+				delete(claims, "email")
+				return claims
+			}(),
+			userInfo:      map[string]any{"sub": "kc|999", "email": user.Email},
+			nonce:         "nonce-1",
+			expectedError: customErrors.ErrInvalidIDToken,
+		},
+		{
+			name:          "nonce from another login",
+			idToken:       provider.Claims("kc|123", user.Email, "nonce-1"),
+			nonce:         "nonce-2",
+			expectedError: customErrors.ErrInvalidIDToken,
+		},
+		{
+			name: "id_token without nonce",
+			idToken: func() map[string]any {
+				claims := provider.Claims("kc|123", user.Email, "")
+				delete(claims, "nonce")
+				return claims
+			}(),
+			nonce:         "nonce-1",
+			expectedError: customErrors.ErrInvalidIDToken,
+		},
+		{
+			name:          "no id_token",
+			nonce:         "nonce-1",
+			expectedError: customErrors.ErrInvalidIDToken,
+		},
+		{
+			name: "unverified email",
+			idToken: func() map[string]any {
+				claims := provider.Claims("kc|123", user.Email, "nonce-1")
+				claims["email_verified"] = false
+				return claims
+			}(),
+			nonce: "nonce-1",
+			setupMocks: func(
+				userRepo *repoMocks.UserRepository,
+				_ *repoMocks.TokenRepository,
+			) {
+				userRepo.On("GetByIdentity", mock.Anything, "keycloak", "kc|123").
+					Return(nil, customErrors.ErrUserNotFound)
+				userRepo.On("GetByEmail", mock.Anything, user.Email).
+					Return(user, nil)
+			},
+			expectedError: customErrors.ErrUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserRepo := repoMocks.NewUserRepository(t)
+			mockTokenRepo := repoMocks.NewTokenRepository(t)
+			if tt.setupMocks != nil {
+				tt.setupMocks(mockUserRepo, mockTokenRepo)
+			}
+			provider.SetIDTokenClaims(tt.idToken)
+			provider.SetUserInfo(tt.userInfo)
+
+			service, err := NewAuthService(
+				config.Config{
+					JWT:   testJWTConfig,
+					OAuth: testOAuthConfig(provider),
+				},
+				mockUserRepo,
+				mockTokenRepo,
+				testRoleRepo(t),
+				repoMocks.NewMFARepository(t),
+				serviceMocks.NewAccountMailer(t),
+				[]string{},
+			)
+			require.NoError(t, err)
+
+			session, err := sealLoginState(
+				service.(*authService).stateKey,
+				loginState{
+					Provider:     "keycloak",
+					State:        "state-1",
+					Nonce:        tt.nonce,
+					CodeVerifier: "verifier",
+					ExpiresAt:    time.Now().Add(loginStateTTL).Unix(),
+				},
+			)
+			require.NoError(t, err)
+
+			resp, err := service.HandleCallback(
+				context.Background(),
+				domain.AuthCallback{
+					Code:    "valid-code",
+					State:   "state-1",
+					Session: session,
+				},
+			)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, resp)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.NotEqual(t, "provider-access-token", resp.AccessToken)
+			assert.Equal(t, "Bearer", resp.TokenType)
+			assert.Equal(t, 900, resp.ExpiresIn)
+			assert.NotEmpty(t, resp.RefreshToken)
+			assert.NotEqual(t, "provider-refresh-token", resp.RefreshToken)
+
+			mockTokenRepo.On("IsValid", mock.Anything, resp.AccessToken).
+				Return(true).Once()
+			claims, err := service.VerifyAccessToken(
+				context.Background(),
+				resp.AccessToken,
+			)
+			require.NoError(t, err)
+			assert.Equal(t, user.ID, claims.UserID)
+			assert.Equal(t, user.Email, claims.Email)
+			assert.Equal(t, domain.AdminRole, claims.Role)
+			assert.Equal(
+				t,
+				[]domain.Permission{domain.PermissionOrdersCreate},
+				claims.Permissions,
+			)
+		})
+	}
+}
+
+func TestHandleCallbackCompletesLogin(t *testing.T) {
+	provider := oidctest.New(t, "test-client-id")
+	mockUserRepo := repoMocks.NewUserRepository(t)
+	mockTokenRepo := repoMocks.NewTokenRepository(t)
+	user := &domain.User{
+		ID:    uuid.New(),
+		Email: "user@example.com",
+		Role:  domain.CustomerRole,
+	}
+	mockUserRepo.On("GetByIdentity", mock.Anything, "keycloak", "kc|123").
+		Return(user, nil)
+	mockTokenRepo.On("Create", mock.Anything, mock.Anything).
+		Return(nil).Twice()
+
+	service, err := NewAuthService(
+		config.Config{
+			JWT:   testJWTConfig,
+			OAuth: testOAuthConfig(provider),
+		},
+		mockUserRepo,
+		mockTokenRepo,
+		testRoleRepo(t),
+		repoMocks.NewMFARepository(t),
+		serviceMocks.NewAccountMailer(t),
+		[]string{},
+	)
+	require.NoError(t, err)
+
+	authRequest, err := service.GetAuthURL(
+		context.Background(),
+		domain.LoginRequest{RedirectTo: "/orders?tab=open"},
+	)
+	require.NoError(t, err)
+	authURL, err := url.Parse(authRequest.URL)
+	require.NoError(t, err)
+	query := authURL.Query()
+
+	provider.SetIDTokenClaims(
+		provider.Claims("kc|123", user.Email, query.Get("nonce")),
+	)
+
+	resp, err := service.HandleCallback(
+		context.Background(),
+		domain.AuthCallback{
+			Code:    "valid-code",
+			State:   query.Get("state"),
+			Session: authRequest.Session,
+		},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "/orders?tab=open", resp.RedirectTo)
+
+	// The code was redeemed with the verifier behind the challenge
+	verifier := provider.TokenRequest().Get("code_verifier")
+	require.NotEmpty(t, verifier)
+	assert.Equal(
+		t,
+		oauth2.S256ChallengeFromVerifier(verifier),
+		query.Get("code_challenge"),
+	)
+}
+
+func TestHandleCallbackRejectsLoginState(t *testing.T) {
+	provider := oidctest.New(t, "test-client-id")
+	provider.SetIDTokenClaims(
+		provider.Claims("kc|123", "user@example.com", "nonce-1"),
+	)
+
+	service, err := NewAuthService(
+		config.Config{
+			JWT:   testJWTConfig,
+			OAuth: testOAuthConfig(provider),
+		},
+		repoMocks.NewUserRepository(t),
+		repoMocks.NewTokenRepository(t),
+		testRoleRepo(t),
+		repoMocks.NewMFARepository(t),
+		serviceMocks.NewAccountMailer(t),
+		[]string{},
+	)
+	require.NoError(t, err)
+	key := service.(*authService).stateKey
+
+	seal := func(key []byte, expiresAt time.Time) string {
+		session, err := sealLoginState(key, loginState{
+			Provider:     "keycloak",
+			State:        "state-1",
+			Nonce:        "nonce-1",
+			CodeVerifier: "verifier",
+			ExpiresAt:    expiresAt.Unix(),
+		})
+		require.NoError(t, err)
+		return session
+	}
+	valid := seal(key, time.Now().Add(loginStateTTL))
+	payload, signature, _ := strings.Cut(valid, ".")
+
+	tests := []struct {
+		name    string
+		state   string
+		session string
+	}{
+		{
+			name:    "missing session",
+			state:   "state-1",
+			session: "",
+		},
+		{
+			name:    "state from another login",
+			state:   "state-2",
+			session: valid,
+		},
+		{
+			name:    "missing state",
+			state:   "",
+			session: valid,
+		},
+		{
+			name:    "expired",
+			state:   "state-1",
+			session: seal(key, time.Now().Add(-time.Second)),
+		},
+		{
+			name:    "signed with another key",
+			state:   "state-1",
+			session: seal(loginStateKey("other-secret"), time.Now().Add(loginStateTTL)),
+		},
+		{
+			name:  "tampered",
+			state: "state-1",
+			session: base64.RawURLEncoding.EncodeToString(
+				[]byte(`{"p":"keycloak","s":"state-1","n":"nonce-1","e":9999999999}`),
+			) + "." + signature,
+		},
+		{
+			name:    "unsigned",
+			state:   "state-1",
+			session: payload,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := service.HandleCallback(
+				context.Background(),
+				domain.AuthCallback{
+					Code:    "valid-code",
+					State:   tt.state,
+					Session: tt.session,
+				},
+			)
+			assert.ErrorIs(t, err, customErrors.ErrInvalidLoginState)
+			assert.Nil(t, resp)
+		})
+	}
+}
+
+func TestCheckRedirect(t *testing.T) {
+	allowed, err := parseAllowedRedirects([]string{
+		"https://shop.example.com/app/",
+		"http://localhost:3000",
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		redirectTo string
+		wantErr    bool
+	}{
+		{redirectTo: ""},
+		{redirectTo: "/orders"},
+		{redirectTo: "/orders?tab=open#top"},
+		{redirectTo: "https://shop.example.com/app"},
+		{redirectTo: "https://SHOP.example.com/app/cart"},
+		{redirectTo: "http://localhost:3000/anything"},
+		{redirectTo: "//evil.example.com", wantErr: true},
+		{redirectTo: "/\\evil.example.com", wantErr: true},
+		{redirectTo: "orders", wantErr: true},
+		{redirectTo: "javascript:alert(1)", wantErr: true},
+		{redirectTo: "https://evil.example.com/app", wantErr: true},
+		{redirectTo: "http://shop.example.com/app", wantErr: true},
+		{redirectTo: "https://shop.example.com/application", wantErr: true},
+		{redirectTo: "https://shop.example.com/", wantErr: true},
+		{redirectTo: "https://user@shop.example.com/app", wantErr: true},
+		{redirectTo: "https://shop.example.com.evil.com/app", wantErr: true},
+		{redirectTo: "http://localhost:3001/", wantErr: true},
+		{redirectTo: "/orders\r\nSet-Cookie: a=b", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.redirectTo, func(t *testing.T) {
+			err := checkRedirect(tt.redirectTo, allowed)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, customErrors.ErrInvalidRedirect)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestHandleCallbackLinksFirstLogin(t *testing.T) {
+	provider := oidctest.New(t, "test-client-id")
+	unverified := &domain.User{
+		ID:    uuid.New(),
+		Email: "shopper@example.com",
+		Role:  domain.CustomerRole,
+	}
+
+	tests := []struct {
+		name          string
+		verified      bool
+		setupMocks    func(*repoMocks.UserRepository)
+		expectedError error
+	}{
+		{
+			name:     "verified email signs up verified",
+			verified: true,
+			setupMocks: func(userRepo *repoMocks.UserRepository) {
+				userRepo.On("GetByEmail", mock.Anything, unverified.Email).
+					Return(nil, customErrors.ErrUserNotFound)
+				userRepo.On("Create", mock.Anything, mock.MatchedBy(
+					func(user *domain.User) bool {
+						return user.EmailVerifiedAt != nil
+					},
+				), mock.Anything).Return(nil)
+			},
+		},
+		{
+			name: "unverified email signs up unverified",
+			setupMocks: func(userRepo *repoMocks.UserRepository) {
+				userRepo.On("GetByEmail", mock.Anything, unverified.Email).
+					Return(nil, customErrors.ErrUserNotFound)
+				userRepo.On("Create", mock.Anything, mock.MatchedBy(
+					func(user *domain.User) bool {
+						return user.EmailVerifiedAt == nil
+					},
+				), mock.MatchedBy(func(identity *domain.UserIdentity) bool {
+					return identity.Subject == "kc|123"
+				})).Return(nil)
+			},
+		},
+		{
+			name:     "verified email claims an unverified account",
+			verified: true,
+			setupMocks: func(userRepo *repoMocks.UserRepository) {
+				userRepo.On("GetByEmail", mock.Anything, unverified.Email).
+					Return(unverified, nil)
+				userRepo.On("ClaimAccount", mock.Anything, mock.MatchedBy(
+					func(identity *domain.UserIdentity) bool {
+						return identity.UserID == unverified.ID
+					},
+				)).Return(nil)
+			},
+		},
+		{
+			name: "unverified email cannot join an account",
+			setupMocks: func(userRepo *repoMocks.UserRepository) {
+				userRepo.On("GetByEmail", mock.Anything, unverified.Email).
+					Return(unverified, nil)
+			},
+			expectedError: customErrors.ErrUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserRepo := repoMocks.NewUserRepository(t)
+			mockTokenRepo := repoMocks.NewTokenRepository(t)
+			mockUserRepo.On("GetByIdentity", mock.Anything, "keycloak", "kc|123").
+				Return(nil, customErrors.ErrUserNotFound)
+			tt.setupMocks(mockUserRepo)
+			if tt.expectedError == nil {
+				mockTokenRepo.On("Create", mock.Anything, mock.Anything).
+					Return(nil)
+			}
+
+			claims := provider.Claims("kc|123", unverified.Email, "nonce-1")
+			claims["email_verified"] = tt.verified
+			provider.SetIDTokenClaims(claims)
+
+			service, err := NewAuthService(
+				config.Config{
+					JWT:   testJWTConfig,
+					OAuth: testOAuthConfig(provider),
+				},
+				mockUserRepo,
+				mockTokenRepo,
+				testRoleRepo(t),
+				repoMocks.NewMFARepository(t),
+				serviceMocks.NewAccountMailer(t),
+				[]string{},
+			)
+			require.NoError(t, err)
+
+			session, err := sealLoginState(
+				service.(*authService).stateKey,
+				loginState{
+					Provider:     "keycloak",
+					State:        "state-1",
+					Nonce:        "nonce-1",
+					CodeVerifier: "verifier",
+					ExpiresAt:    time.Now().Add(loginStateTTL).Unix(),
+				},
+			)
+			require.NoError(t, err)
+
+			_, err = service.HandleCallback(
+				context.Background(),
+				domain.AuthCallback{
+					Code:    "valid-code",
+					State:   "state-1",
+					Session: session,
+				},
+			)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestVerifyAccessToken(t *testing.T) {
+	mockTokenRepo := repoMocks.NewTokenRepository(t)
+	service, err := NewAuthService(
+		config.Config{JWT: testJWTConfig},
+		repoMocks.NewUserRepository(t),
+		mockTokenRepo,
+		testRoleRepo(t),
+		repoMocks.NewMFARepository(t),
+		serviceMocks.NewAccountMailer(t),
+		[]string{},
+	)
+	require.NoError(t, err)
+
+	userID := uuid.New()
+	now := time.Now()
+	validClaims := jwt.Claims{
+		Issuer:    "grocery-service",
+		Subject:   userID.String(),
+		ID:        uuid.NewString(),
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(time.Minute).Unix(),
+		Role:      string(domain.CustomerRole),
+	}
+
+	sign := func(key jwt.Key, claims jwt.Claims) string {
+		keys, err := jwt.NewKeySet(key)
+		require.NoError(t, err)
+		token, err := keys.Sign(claims)
+		require.NoError(t, err)
+		return token
+	}
+	activeKey := jwt.Key{ID: "2", Secret: "test-jwt-secret"}
+	revoked := func() string {
+		claims := validClaims
+		claims.ID = uuid.NewString()
+		return sign(activeKey, claims)
+	}()
+	mockTokenRepo.On("IsValid", mock.Anything, revoked).Return(false)
+	mockTokenRepo.On("IsValid", mock.Anything, mock.Anything).Return(true)
+
+	tests := []struct {
+		name          string
+		token         string
+		expectedError error
+	}{
+		{
+			name:  "signed by active key",
+			token: sign(activeKey, validClaims),
+		},
+		{
+			name:  "signed by retired key",
+			token: sign(jwt.Key{ID: "1", Secret: "old-jwt-secret"}, validClaims),
+		},
+		{
+			name:          "unknown key ID",
+			token:         sign(jwt.Key{ID: "3", Secret: "test-jwt-secret"}, validClaims),
+			expectedError: customErrors.ErrInvalidToken,
+		},
+		{
+			name:          "forged with another secret",
+			token:         sign(jwt.Key{ID: "2", Secret: "forged"}, validClaims),
+			expectedError: customErrors.ErrInvalidToken,
+		},
+		{
+			name: "expired",
+			token: func() string {
+				claims := validClaims
+				claims.ExpiresAt = now.Add(-time.Second).Unix()
+				return sign(activeKey, claims)
+			}(),
+			expectedError: customErrors.ErrTokenExpired,
+		},
+		{
+			name: "other issuer",
+			token: func() string {
+				claims := validClaims
+				claims.Issuer = "someone-else"
+				return sign(activeKey, claims)
+			}(),
+			expectedError: customErrors.ErrInvalidToken,
+		},
+		{
+			name: "subject is not a user ID",
+			token: func() string {
+				claims := validClaims
+				claims.Subject = "provider|123"
+				return sign(activeKey, claims)
+			}(),
+			expectedError: customErrors.ErrInvalidToken,
+		},
+		{
+			name:          "provider access token",
+			token:         "opaque-provider-token",
+			expectedError: customErrors.ErrInvalidToken,
+		},
+		{
+			name:          "revoked",
+			token:         revoked,
+			expectedError: customErrors.ErrInvalidToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := service.VerifyAccessToken(
+				context.Background(),
+				tt.token,
+			)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, claims)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, userID, claims.UserID)
+				assert.Equal(t, domain.CustomerRole, claims.Role)
+			}
+		})
+	}
+}
+
+func TestGetJWKS(t *testing.T) {
+	service, err := NewAuthService(
+		config.Config{JWT: testJWTConfig},
+		repoMocks.NewUserRepository(t),
+		repoMocks.NewTokenRepository(t),
+		testRoleRepo(t),
+		repoMocks.NewMFARepository(t),
+		serviceMocks.NewAccountMailer(t),
+		[]string{},
+	)
+	require.NoError(t, err)
+
+	jwks := service.GetJWKS()
+
+	require.Len(t, jwks.Keys, 2)
+	assert.Equal(t, "2", jwks.Keys[0].KeyID)
+	assert.Equal(t, "1", jwks.Keys[1].KeyID)
+	for _, key := range jwks.Keys {
+		assert.Equal(t, "OKP", key.KeyType)
+		assert.Equal(t, "Ed25519", key.Curve)
+		assert.Equal(t, jwt.Algorithm, key.Algorithm)
+		assert.NotEmpty(t, key.X)
+	}
+}
+
+func TestNewAuthServiceRejectsInvalidKeys(t *testing.T) {
+	cfg := config.Config{JWT: testJWTConfig}
+	cfg.JWT.RetiredKeys = []string{"missing-secret"}
+
+	_, err := NewAuthService(
+		cfg,
+		repoMocks.NewUserRepository(t),
+		repoMocks.NewTokenRepository(t),
+		testRoleRepo(t),
+		repoMocks.NewMFARepository(t),
+		serviceMocks.NewAccountMailer(t),
+		[]string{},
+	)
+	assert.Error(t, err)
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Email: "user@example.com"}
+	familyID := uuid.New()
+	revokedAt := time.Now().Add(-time.Minute)
+
+	refreshToken := func(changes func(*domain.Token)) *domain.Token {
+		token := &domain.Token{
+			ID:         uuid.New(),
+			UserID:     user.ID,
+			Type:       domain.TokenTypeRefresh,
+			FamilyID:   familyID,
+			ExpiresAt:  time.Now().Add(time.Hour),
+			Provider:   "keycloak",
+			ProviderID: "kc|123",
+		}
+		if changes != nil {
+			changes(token)
+		}
+		return token
+	}
+	client := domain.ClientInfo{
+		IPAddress: "203.0.113.7",
+		UserAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_0) Firefox/128.0",
+	}
+	inFamily := func(tokenType domain.TokenType) interface{} {
+		return mock.MatchedBy(func(token *domain.Token) bool {
+			return token.Type == tokenType &&
+				token.FamilyID == familyID &&
+				token.UserID == user.ID &&
+				token.Provider == "keycloak" &&
+				token.Token != "refresh-token" &&
+				token.IPAddress == client.IPAddress &&
+				token.Device == "Firefox on macOS"
+		})
+	}
+
+	tests := []struct {
+		name          string
+		setupMocks    func(*repoMocks.UserRepository, *repoMocks.TokenRepository)
+		expectedError error
+	}{
+		{
+			name: "rotates within the family",
+			setupMocks: func(
+				userRepo *repoMocks.UserRepository,
+				tokenRepo *repoMocks.TokenRepository,
+			) {
+				stored := refreshToken(nil)
+				tokenRepo.On("GetByTokenIncludingRevoked", mock.Anything, "refresh-token").
+					Return(stored, nil)
+				userRepo.On("GetByID", mock.Anything, user.ID.String()).
+					Return(user, nil)
+				tokenRepo.On("RotateToken", mock.Anything, stored, inFamily(domain.TokenTypeRefresh)).
+					Return(nil)
+				tokenRepo.On("Create", mock.Anything, inFamily(domain.TokenTypeAccess)).
+					Return(nil)
+			},
+		},
+		{
+			name: "reused token revokes the family",
+			setupMocks: func(
+				_ *repoMocks.UserRepository,
+				tokenRepo *repoMocks.TokenRepository,
+			) {
+				tokenRepo.On("GetByTokenIncludingRevoked", mock.Anything, "refresh-token").
+					Return(refreshToken(func(token *domain.Token) {
+						token.RevokedAt = &revokedAt
+					}), nil)
+				tokenRepo.On("RevokeFamily", mock.Anything, familyID.String()).
+					Return(int64(2), nil)
+			},
+			expectedError: customErrors.ErrInvalidToken,
+		},
+		{
+			name: "token exchanged concurrently revokes the family",
+			setupMocks: func(
+				userRepo *repoMocks.UserRepository,
+				tokenRepo *repoMocks.TokenRepository,
+			) {
+				stored := refreshToken(nil)
+				tokenRepo.On("GetByTokenIncludingRevoked", mock.Anything, "refresh-token").
+					Return(stored, nil)
+				userRepo.On("GetByID", mock.Anything, user.ID.String()).
+					Return(user, nil)
+				tokenRepo.On("RotateToken", mock.Anything, stored, inFamily(domain.TokenTypeRefresh)).
+					Return(customErrors.ErrTokenNotFound)
+				tokenRepo.On("RevokeFamily", mock.Anything, familyID.String()).
+					Return(int64(2), nil)
+			},
+			expectedError: customErrors.ErrInvalidToken,
+		},
+		{
+			name: "expired",
+			setupMocks: func(
+				_ *repoMocks.UserRepository,
+				tokenRepo *repoMocks.TokenRepository,
+			) {
+				tokenRepo.On("GetByTokenIncludingRevoked", mock.Anything, "refresh-token").
+					Return(refreshToken(func(token *domain.Token) {
+						token.ExpiresAt = time.Now().Add(-time.Minute)
+					}), nil)
+			},
+			expectedError: customErrors.ErrInvalidToken,
+		},
+		{
+			name: "access token",
+			setupMocks: func(
+				_ *repoMocks.UserRepository,
+				tokenRepo *repoMocks.TokenRepository,
+			) {
+				tokenRepo.On("GetByTokenIncludingRevoked", mock.Anything, "refresh-token").
+					Return(refreshToken(func(token *domain.Token) {
+						token.Type = domain.TokenTypeAccess
+					}), nil)
+			},
+			expectedError: customErrors.ErrInvalidToken,
+		},
+		{
+			name: "unknown token",
+			setupMocks: func(
+				_ *repoMocks.UserRepository,
+				tokenRepo *repoMocks.TokenRepository,
+			) {
+				tokenRepo.On("GetByTokenIncludingRevoked", mock.Anything, "refresh-token").
+					Return(nil, customErrors.ErrTokenNotFound)
+			},
+			expectedError: customErrors.ErrInvalidToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserRepo := repoMocks.NewUserRepository(t)
+			mockTokenRepo := repoMocks.NewTokenRepository(t)
+			tt.setupMocks(mockUserRepo, mockTokenRepo)
+
+			service, err := NewAuthService(
+				config.Config{JWT: testJWTConfig},
+				mockUserRepo,
+				mockTokenRepo,
+				testRoleRepo(t),
+				repoMocks.NewMFARepository(t),
+				serviceMocks.NewAccountMailer(t),
+				[]string{},
+			)
+			require.NoError(t, err)
+
+			resp, err := service.RefreshToken(
+				context.Background(),
+				"refresh-token",
+				client,
+			)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, resp)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, resp.RefreshToken)
+			assert.NotEqual(t, "refresh-token", resp.RefreshToken)
+
+			mockTokenRepo.On("IsValid", mock.Anything, resp.AccessToken).
+				Return(true).Once()
+			claims, err := service.VerifyAccessToken(
+				context.Background(),
+				resp.AccessToken,
+			)
+			require.NoError(t, err)
+			assert.Equal(t, user.ID, claims.UserID)
+			assert.Equal(t, familyID, claims.SessionID)
+		})
+	}
+}
+
+func TestListSessions(t *testing.T) {
+	mockTokenRepo := repoMocks.NewTokenRepository(t)
+	userID := uuid.New()
+	lastUsed := time.Now().Add(-time.Hour)
+	tokens := []domain.Token{
+		{
+			ID:         uuid.New(),
+			UserID:     userID,
+			Type:       domain.TokenTypeRefresh,
+			FamilyID:   uuid.New(),
+			Provider:   "keycloak",
+			Device:     "Safari on iPhone",
+			IPAddress:  "203.0.113.7",
+			UserAgent:  "Mozilla/5.0 (iPhone)",
+			LastUsedAt: lastUsed,
+			ExpiresAt:  lastUsed.Add(refreshTokenDuration),
+		},
+	}
+	mockTokenRepo.On(
+		"GetByUserAndType",
+		mock.Anything,
+		userID.String(),
+		domain.TokenTypeRefresh,
+	).Return(tokens, nil)
+
+	service, err := NewAuthService(
+		config.Config{JWT: testJWTConfig},
+		repoMocks.NewUserRepository(t),
+		mockTokenRepo,
+		testRoleRepo(t),
+		repoMocks.NewMFARepository(t),
+		serviceMocks.NewAccountMailer(t),
+		[]string{},
+	)
+	require.NoError(t, err)
+
+	sessions, err := service.ListSessions(context.Background(), userID.String())
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, domain.Session{
+		ID:         tokens[0].FamilyID,
+		UserID:     userID,
+		Provider:   "keycloak",
+		Device:     "Safari on iPhone",
+		IPAddress:  "203.0.113.7",
+		UserAgent:  "Mozilla/5.0 (iPhone)",
+		LastUsedAt: lastUsed,
+		ExpiresAt:  tokens[0].ExpiresAt,
+	}, sessions[0])
+}
+
+func TestRevokeSession(t *testing.T) {
+	userID := uuid.New()
+	familyID := uuid.New()
+
+	tests := []struct {
+		name          string
+		sessionID     string
+		revoked       bool
+		expectedError error
+	}{
+		{
+			name:      "own session",
+			sessionID: familyID.String(),
+			revoked:   true,
+		},
+		{
+			name:          "another user's session",
+			sessionID:     uuid.NewString(),
+			expectedError: customErrors.ErrSessionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTokenRepo := repoMocks.NewTokenRepository(t)
+			mockTokenRepo.On(
+				"GetByUserAndType",
+				mock.Anything,
+				userID.String(),
+				domain.TokenTypeRefresh,
+			).Return([]domain.Token{
+				{UserID: userID, FamilyID: familyID},
+			}, nil)
+			if tt.revoked {
+				mockTokenRepo.On("RevokeFamily", mock.Anything, tt.sessionID).
+					Return(int64(2), nil)
+			}
+
+			service, err := NewAuthService(
+				config.Config{JWT: testJWTConfig},
+				repoMocks.NewUserRepository(t),
+				mockTokenRepo,
+				testRoleRepo(t),
+				repoMocks.NewMFARepository(t),
+				serviceMocks.NewAccountMailer(t),
+				[]string{},
+			)
+			require.NoError(t, err)
+
+			err = service.RevokeSession(
+				context.Background(),
+				userID.String(),
+				tt.sessionID,
+			)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDescribeDevice(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		want      string
+	}{
+		{
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36 Edg/126.0.0.0",
+			want:      "Edge on Windows",
+		},
+		{
+			userAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36",
+			want:      "Chrome on macOS",
+		},
+		{
+			userAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.5 Mobile/15E148 Safari/604.1",
+			want:      "Safari on iPhone",
+		},
+		{
+			userAgent: "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Mobile Safari/537.36",
+			want:      "Chrome on Android",
+		},
+		{
+			userAgent: "Mozilla/5.0 (X11; Linux x86_64; rv:128.0) Gecko/20100101 Firefox/128.0",
+			want:      "Firefox on Linux",
+		},
+		{
+			userAgent: "curl/8.7.1",
+			want:      "curl",
+		},
+		{
+			userAgent: "",
+			want:      "Unknown device",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, describeDevice(tt.userAgent))
+		})
+	}
+}
@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/grocery-service/internal/domain"
+	repository "github.com/grocery-service/internal/repository/postgres"
+	customErrors "github.com/grocery-service/utils/errors"
+)
+
+type (
+	CartService interface {
+		GetCart(ctx context.Context, userID string) (*domain.Cart, error)
+		AddItem(
+			ctx context.Context,
+			userID, productID string,
+			quantity int,
+		) (*domain.Cart, error)
+		UpdateItem(
+			ctx context.Context,
+			userID, itemID string,
+			quantity int,
+		) (*domain.Cart, error)
+		RemoveItem(
+			ctx context.Context,
+			userID, itemID string,
+		) (*domain.Cart, error)
+		Clear(ctx context.Context, userID string) error
+		Checkout(
+			ctx context.Context,
+			userID string,
+			request domain.CheckoutRequest,
+		) (*domain.CheckoutResult, error)
+	}
+
+	CartServiceImpl struct {
+		repo         repository.CartRepository
+		productRepo  repository.ProductRepository
+		customerRepo repository.CustomerRepository
+		orderService OrderService
+	}
+)
+
+func NewCartService(
+	repo repository.CartRepository,
+	productRepo repository.ProductRepository,
+	customerRepo repository.CustomerRepository,
+	orderService OrderService,
+) CartService {
+	return &CartServiceImpl{
+		repo:         repo,
+		productRepo:  productRepo,
+		customerRepo: customerRepo,
+		orderService: orderService,
+	}
+}
+
+func (s *CartServiceImpl) GetCart(
+	ctx context.Context,
+	userID string,
+) (*domain.Cart, error) {
+	return s.getOrCreateCart(ctx, userID)
+}
+
+func (s *CartServiceImpl) AddItem(
+	ctx context.Context,
+	userID, productID string,
+	quantity int,
+) (*domain.Cart, error) {
+	if quantity <= 0 {
+		return nil, fmt.Errorf(
+			"%w: quantity must be greater than zero",
+			customErrors.ErrInvalidCartData,
+		)
+	}
+
+	cart, err := s.getOrCreateCart(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	inCart := 0
+	for _, item := range cart.Items {
+		if item.ProductID == product.ID {
+			inCart = item.Quantity
+			break
+		}
+	}
+
+	if err := checkStock(product, inCart+quantity); err != nil {
+		return nil, err
+	}
+
+	item := domain.NewCartItem(cart.ID, product.ID, quantity, product.Price)
+	if err := s.repo.AddItem(ctx, item); err != nil {
+		return nil, err
+	}
+
+	return s.getOrCreateCart(ctx, userID)
+}
+
+func (s *CartServiceImpl) UpdateItem(
+	ctx context.Context,
+	userID, itemID string,
+	quantity int,
+) (*domain.Cart, error) {
+	if quantity <= 0 {
+		return nil, fmt.Errorf(
+			"%w: quantity must be greater than zero",
+			customErrors.ErrInvalidCartData,
+		)
+	}
+
+	cart, err := s.getOrCreateCart(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	item := findCartItem(cart, itemID)
+	if item == nil {
+		return nil, customErrors.ErrCartItemNotFound
+	}
+
+	if item.Product != nil {
+		if err := checkStock(item.Product, quantity); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.UpdateItemQuantity(
+		ctx,
+		cart.ID.String(),
+		itemID,
+		quantity,
+	); err != nil {
+		return nil, err
+	}
+
+	return s.getOrCreateCart(ctx, userID)
+}
+
+func (s *CartServiceImpl) RemoveItem(
+	ctx context.Context,
+	userID, itemID string,
+) (*domain.Cart, error) {
+	cart, err := s.getOrCreateCart(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.RemoveItem(ctx, cart.ID.String(), itemID); err != nil {
+		return nil, err
+	}
+
+	return s.getOrCreateCart(ctx, userID)
+}
+
+func (s *CartServiceImpl) Clear(
+	ctx context.Context,
+	userID string,
+) error {
+	cart, err := s.getOrCreateCart(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Clear(ctx, cart.ID.String())
+}
+
+// Checkout turns the caller's cart into an order and empties the cart in
+// the same transaction, so a retried checkout finds an empty cart rather
+// than ordering twice. If any price changed since an item was added the
+// checkout is refused with ErrCartPriceChanged unless the request accepts
+// the new prices; the order is charged exactly the prices the caller saw,
+// and a price that moves again before the order commits refuses the
+// checkout the same way. Stock shortfalls surface as the
+// *InsufficientStockError from order creation.
+func (s *CartServiceImpl) Checkout(
+	ctx context.Context,
+	userID string,
+	request domain.CheckoutRequest,
+) (*domain.CheckoutResult, error) {
+	cart, err := s.getOrCreateCart(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cart.Items) == 0 {
+		return nil, customErrors.ErrEmptyCart
+	}
+
+	result := &domain.CheckoutResult{}
+	for _, item := range cart.Items {
+		if !item.UnitPrice.Equal(item.CurrentPrice) {
+			result.PriceChanges = append(
+				result.PriceChanges,
+				domain.CartPriceChange{
+					ProductID: item.ProductID,
+					OldPrice:  item.UnitPrice,
+					NewPrice:  item.CurrentPrice,
+				},
+			)
+		}
+	}
+
+	if len(result.PriceChanges) > 0 && !request.AcceptPriceChanges {
+		return result, customErrors.ErrCartPriceChanged
+	}
+
+	order := domain.NewOrder(cart.CustomerID)
+	for _, item := range cart.Items {
+		order.Items = append(order.Items, *domain.NewOrderItem(
+			order.ID,
+			item.ProductID,
+			item.Quantity,
+			item.CurrentPrice,
+		))
+	}
+
+	err = s.orderService.Checkout(ctx, order, cart.ID.String())
+	if errors.Is(err, customErrors.ErrOrderPriceChanged) {
+		return result, fmt.Errorf("%w: %v", customErrors.ErrCartPriceChanged, err)
+	}
+	if err != nil {
+		return result, err
+	}
+	result.Order = order
+
+	return result, nil
+}
+
+// getOrCreateCart resolves the caller's customer profile and returns its
+// cart priced at current product prices, creating an empty cart on first
+// use.
+func (s *CartServiceImpl) getOrCreateCart(
+	ctx context.Context,
+	userID string,
+) (*domain.Cart, error) {
+	if userID == "" {
+		return nil, fmt.Errorf(
+			"%w: user ID is required",
+			customErrors.ErrInvalidCartData,
+		)
+	}
+
+	customer, err := s.customerRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find customer: %w", err)
+	}
+
+	cart, err := s.repo.GetByCustomerID(ctx, customer.ID.String())
+	if errors.Is(err, customErrors.ErrCartNotFound) {
+		cart = domain.NewCart(customer.ID)
+		if err := s.repo.Create(ctx, cart); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	priceCart(cart)
+	return cart, nil
+}
+
+func priceCart(cart *domain.Cart) {
+	cart.TotalPrice = domain.NewMoney(0, domain.DefaultCurrency)
+	for i := range cart.Items {
+		item := &cart.Items[i]
+		item.CurrentPrice = item.UnitPrice
+		if item.Product != nil {
+			item.CurrentPrice = item.Product.Price
+		}
+		item.LineTotal = item.CurrentPrice.Mul(item.Quantity)
+		cart.TotalPrice = cart.TotalPrice.Add(item.LineTotal)
+	}
+}
+
+func findCartItem(cart *domain.Cart, itemID string) *domain.CartItem {
+	id, err := uuid.Parse(itemID)
+	if err != nil {
+		return nil
+	}
+
+	for i := range cart.Items {
+		if cart.Items[i].ID == id {
+			return &cart.Items[i]
+		}
+	}
+	return nil
+}
+
+func checkStock(product *domain.Product, quantity int) error {
+	if product.Stock < quantity {
+		return &customErrors.InsufficientStockError{
+			Shortfalls: []customErrors.StockShortfall{{
+				ProductID: product.ID.String(),
+				Requested: quantity,
+				Available: product.Stock,
+			}},
+		}
+	}
+	return nil
+}
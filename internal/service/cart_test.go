@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/grocery-service/internal/domain"
+	repoMocks "github.com/grocery-service/tests/mocks/repository"
+	serviceMock "github.com/grocery-service/tests/mocks/service"
+	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type cartTestDeps struct {
+	cartRepo     *repoMocks.CartRepository
+	productRepo  *repoMocks.ProductRepository
+	customerRepo *repoMocks.CustomerRepository
+	orderService *serviceMock.OrderService
+}
+
+func setupCartTest(t *testing.T) (CartService, *cartTestDeps) {
+	deps := &cartTestDeps{
+		cartRepo:     repoMocks.NewCartRepository(t),
+		productRepo:  repoMocks.NewProductRepository(t),
+		customerRepo: repoMocks.NewCustomerRepository(t),
+		orderService: serviceMock.NewOrderService(t),
+	}
+
+	service := NewCartService(
+		deps.cartRepo,
+		deps.productRepo,
+		deps.customerRepo,
+		deps.orderService,
+	)
+
+	return service, deps
+}
+
+// cartCallerID is the caller's user ID, as Authentication puts it in
+// the request context.
+var cartCallerID = uuid.NewString()
+
+// expectCaller wires the customer lookup for the caller and returns the
+// resolved customer.
+func expectCaller(deps *cartTestDeps) *domain.Customer {
+	customer, user := createTestCustomer()
+	user.ID = uuid.MustParse(cartCallerID)
+	customer.UserID = user.ID
+	deps.customerRepo.On("GetByUserID", mock.Anything, cartCallerID).
+		Return(customer, nil)
+	return customer
+}
+
+func createTestCart(
+	customerID uuid.UUID,
+	product *domain.Product,
+	unitPrice domain.Money,
+) *domain.Cart {
+	cart := domain.NewCart(customerID)
+	item := domain.NewCartItem(cart.ID, product.ID, 2, unitPrice)
+	item.Product = product
+	cart.Items = []domain.CartItem{*item}
+	return cart
+}
+
+func TestCartService_GetCart(t *testing.T) {
+	t.Run("Success - Creates Empty Cart On First Use", func(t *testing.T) {
+		service, deps := setupCartTest(t)
+		customer := expectCaller(deps)
+
+		deps.cartRepo.On("GetByCustomerID", mock.Anything, customer.ID.String()).
+			Return(nil, customErrors.ErrCartNotFound)
+		deps.cartRepo.On("Create", mock.Anything, mock.MatchedBy(func(c *domain.Cart) bool {
+			return c.CustomerID == customer.ID
+		})).Return(nil)
+
+		cart, err := service.GetCart(context.Background(), cartCallerID)
+
+		require.NoError(t, err)
+		assert.Equal(t, customer.ID, cart.CustomerID)
+		assert.Empty(t, cart.Items)
+	})
+
+	t.Run("Success - Prices Items At Current Product Price", func(t *testing.T) {
+		service, deps := setupCartTest(t)
+		customer := expectCaller(deps)
+		product := createTestProduct()
+		product.Price = domain.NewMoney(1250, domain.DefaultCurrency)
+
+		deps.cartRepo.On("GetByCustomerID", mock.Anything, customer.ID.String()).
+			Return(createTestCart(customer.ID, product, domain.NewMoney(1000, domain.DefaultCurrency)), nil)
+
+		cart, err := service.GetCart(context.Background(), cartCallerID)
+
+		require.NoError(t, err)
+		require.Len(t, cart.Items, 1)
+		assert.Equal(t, domain.NewMoney(1000, domain.DefaultCurrency), cart.Items[0].UnitPrice)
+		assert.Equal(t, domain.NewMoney(1250, domain.DefaultCurrency), cart.Items[0].CurrentPrice)
+		assert.Equal(t, domain.NewMoney(2500, domain.DefaultCurrency), cart.Items[0].LineTotal)
+		assert.Equal(t, domain.NewMoney(2500, domain.DefaultCurrency), cart.TotalPrice)
+	})
+
+	t.Run("Error - Customer Not Found", func(t *testing.T) {
+		service, deps := setupCartTest(t)
+		deps.customerRepo.On("GetByUserID", mock.Anything, cartCallerID).
+			Return(nil, customErrors.ErrCustomerNotFound)
+
+		_, err := service.GetCart(context.Background(), cartCallerID)
+
+		assert.ErrorIs(t, err, customErrors.ErrCustomerNotFound)
+	})
+}
+
+func TestCartService_AddItem(t *testing.T) {
+	tests := []struct {
+		name          string
+		quantity      int
+		setupMocks    func(deps *cartTestDeps, product *domain.Product)
+		expectedError error
+	}{
+		{
+			name:     "Success - Add Item",
+			quantity: 2,
+			setupMocks: func(deps *cartTestDeps, product *domain.Product) {
+				customer := expectCaller(deps)
+				cart := domain.NewCart(customer.ID)
+				deps.cartRepo.On("GetByCustomerID", mock.Anything, customer.ID.String()).
+					Return(cart, nil)
+				deps.productRepo.On("GetByID", mock.Anything, product.ID.String()).
+					Return(product, nil)
+				deps.cartRepo.On("AddItem", mock.Anything, mock.MatchedBy(func(i *domain.CartItem) bool {
+					return i.CartID == cart.ID &&
+						i.ProductID == product.ID &&
+						i.Quantity == 2 &&
+						i.UnitPrice == product.Price
+				})).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:     "Error - Exceeds Stock Including Cart Quantity",
+			quantity: 4,
+			setupMocks: func(deps *cartTestDeps, product *domain.Product) {
+				customer := expectCaller(deps)
+				deps.cartRepo.On("GetByCustomerID", mock.Anything, customer.ID.String()).
+					Return(createTestCart(customer.ID, product, product.Price), nil)
+				deps.productRepo.On("GetByID", mock.Anything, product.ID.String()).
+					Return(product, nil)
+			},
+			expectedError: customErrors.ErrInsufficientStock,
+		},
+		{
+			name:          "Error - Invalid Quantity",
+			quantity:      0,
+			setupMocks:    func(_ *cartTestDeps, _ *domain.Product) {},
+			expectedError: customErrors.ErrInvalidCartData,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, deps := setupCartTest(t)
+			product := createTestProduct()
+			tt.setupMocks(deps, product)
+
+			cart, err := service.AddItem(
+				context.Background(),
+				cartCallerID,
+				product.ID.String(),
+				tt.quantity,
+			)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, cart)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, cart)
+			}
+		})
+	}
+}
+
+func TestCartService_Checkout(t *testing.T) {
+	tests := []struct {
+		name           string
+		request        domain.CheckoutRequest
+		setupMocks     func(deps *cartTestDeps)
+		expectedError  error
+		expectOrder    bool
+		expectedChange int
+	}{
+		{
+			name: "Success - Creates Order And Clears Cart",
+			setupMocks: func(deps *cartTestDeps) {
+				customer := expectCaller(deps)
+				product := createTestProduct()
+				cart := createTestCart(customer.ID, product, product.Price)
+				deps.cartRepo.On("GetByCustomerID", mock.Anything, customer.ID.String()).
+					Return(cart, nil)
+				deps.orderService.On("Checkout", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
+					return o.CustomerID == customer.ID &&
+						len(o.Items) == 1 &&
+						o.Items[0].ProductID == product.ID &&
+						o.Items[0].Quantity == 2
+				}), cart.ID.String()).Return(nil)
+			},
+			expectOrder: true,
+		},
+		{
+			name: "Error - Price Changed Without Acceptance",
+			setupMocks: func(deps *cartTestDeps) {
+				customer := expectCaller(deps)
+				product := createTestProduct()
+				deps.cartRepo.On("GetByCustomerID", mock.Anything, customer.ID.String()).
+					Return(createTestCart(customer.ID, product, domain.NewMoney(800, domain.DefaultCurrency)), nil)
+			},
+			expectedError:  customErrors.ErrCartPriceChanged,
+			expectedChange: 1,
+		},
+		{
+			name:    "Success - Price Changed With Acceptance",
+			request: domain.CheckoutRequest{AcceptPriceChanges: true},
+			setupMocks: func(deps *cartTestDeps) {
+				customer := expectCaller(deps)
+				product := createTestProduct()
+				cart := createTestCart(customer.ID, product, domain.NewMoney(800, domain.DefaultCurrency))
+				deps.cartRepo.On("GetByCustomerID", mock.Anything, customer.ID.String()).
+					Return(cart, nil)
+				deps.orderService.On("Checkout", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
+					return o.Items[0].Price == product.Price
+				}), cart.ID.String()).Return(nil)
+			},
+			expectOrder:    true,
+			expectedChange: 1,
+		},
+		{
+			name: "Error - Price Changed Before Order Commits",
+			setupMocks: func(deps *cartTestDeps) {
+				customer := expectCaller(deps)
+				product := createTestProduct()
+				cart := createTestCart(customer.ID, product, product.Price)
+				deps.cartRepo.On("GetByCustomerID", mock.Anything, customer.ID.String()).
+					Return(cart, nil)
+				deps.orderService.On("Checkout", mock.Anything, mock.Anything, cart.ID.String()).
+					Return(customErrors.ErrOrderPriceChanged)
+			},
+			expectedError: customErrors.ErrCartPriceChanged,
+		},
+		{
+			name: "Error - Stock Shortfall",
+			setupMocks: func(deps *cartTestDeps) {
+				customer := expectCaller(deps)
+				product := createTestProduct()
+				deps.cartRepo.On("GetByCustomerID", mock.Anything, customer.ID.String()).
+					Return(createTestCart(customer.ID, product, product.Price), nil)
+				deps.orderService.On("Checkout", mock.Anything, mock.Anything, mock.Anything).
+					Return(&customErrors.InsufficientStockError{
+						Shortfalls: []customErrors.StockShortfall{{
+							ProductID: product.ID.String(),
+							Requested: 2,
+							Available: 1,
+						}},
+					})
+			},
+			expectedError: customErrors.ErrInsufficientStock,
+		},
+		{
+			name: "Error - Empty Cart",
+			setupMocks: func(deps *cartTestDeps) {
+				customer := expectCaller(deps)
+				deps.cartRepo.On("GetByCustomerID", mock.Anything, customer.ID.String()).
+					Return(domain.NewCart(customer.ID), nil)
+			},
+			expectedError: customErrors.ErrEmptyCart,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, deps := setupCartTest(t)
+			tt.setupMocks(deps)
+
+			result, err := service.Checkout(
+				context.Background(),
+				cartCallerID,
+				tt.request,
+			)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			if tt.expectOrder {
+				require.NotNil(t, result)
+				assert.NotNil(t, result.Order)
+			}
+
+			if tt.expectedChange > 0 {
+				require.NotNil(t, result)
+				assert.Len(t, result.PriceChanges, tt.expectedChange)
+			}
+		})
+	}
+}
@@ -14,7 +14,10 @@ type (
 	CategoryService interface {
 		Create(ctx context.Context, category *domain.Category) error
 		GetByID(ctx context.Context, id string) (*domain.Category, error)
-		List(ctx context.Context) ([]domain.Category, error)
+		List(
+			ctx context.Context,
+			query domain.ListQuery,
+		) (*domain.Page[domain.Category], error)
 		ListByParentID(
 			ctx context.Context,
 			parentID string,
@@ -80,8 +83,9 @@ func (s *CategoryServiceImpl) GetByID(
 
 func (s *CategoryServiceImpl) List(
 	ctx context.Context,
-) ([]domain.Category, error) {
-	return s.repo.List(ctx)
+	query domain.ListQuery,
+) (*domain.Page[domain.Category], error) {
+	return s.repo.List(ctx, query)
 }
 
 func (s *CategoryServiceImpl) ListByParentID(
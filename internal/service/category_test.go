@@ -95,11 +95,18 @@ func TestCategoryService_List(t *testing.T) {
 		{ID: uuid.New(), Name: "Category 2"},
 	}
 
-	mockRepo.On("List", ctx).Return(categories, nil)
-
-	found, err := service.List(ctx)
+	query := domain.ListQuery{Limit: 10, SortBy: "name"}
+	mockRepo.On("List", ctx, query).Return(&domain.Page[domain.Category]{
+		Items: categories,
+		Total: int64(len(categories)),
+		Limit: 10,
+		Page:  1,
+	}, nil)
+
+	found, err := service.List(ctx, query)
 	assert.NoError(t, err)
-	assert.Len(t, found, len(categories))
+	assert.Len(t, found.Items, len(categories))
+	assert.Equal(t, int64(len(categories)), found.Total)
 }
 
 func TestCategoryService_ListByParentID(t *testing.T) {
@@ -18,7 +18,10 @@ type (
 			ctx context.Context,
 			userID string,
 		) (*domain.Customer, error)
-		List(ctx context.Context) ([]domain.Customer, error)
+		List(
+			ctx context.Context,
+			query domain.ListQuery,
+		) (*domain.Page[domain.Customer], error)
 		Delete(ctx context.Context, id string) error
 	}
 
@@ -49,7 +52,7 @@ func (s *CustomerServiceImpl) Create(
 		)
 	}
 
-	user, err := s.userRepo.GetByProviderID(ctx, userID)
+	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf(
 			"failed to find user: %w",
@@ -106,8 +109,9 @@ func (s *CustomerServiceImpl) GetByUserID(
 
 func (s *CustomerServiceImpl) List(
 	ctx context.Context,
-) ([]domain.Customer, error) {
-	return s.customerRepo.List(ctx)
+	query domain.ListQuery,
+) (*domain.Page[domain.Customer], error) {
+	return s.customerRepo.List(ctx, query)
 }
 
 func (s *CustomerServiceImpl) Delete(
@@ -44,10 +44,10 @@ func TestCustomerService_Create(t *testing.T) {
 	}{
 		{
 			name:   "Success - Create New Customer",
-			userID: "google-oauth2|123456789",
+			userID: uuid.NewString(),
 			setupMocks: func(cr *mocks.CustomerRepository, ur *mocks.UserRepository, userID string) {
 				user := createTestUser()
-				ur.On("GetByProviderID", mock.Anything, userID).Return(user, nil)
+				ur.On("GetByID", mock.Anything, userID).Return(user, nil)
 				cr.On("GetByUserID", mock.Anything, user.ID.String()).
 					Return(nil, customErrors.ErrCustomerNotFound)
 				cr.On(
@@ -67,19 +67,19 @@ func TestCustomerService_Create(t *testing.T) {
 		},
 		{
 			name:   "Error - User Not Found",
-			userID: "google-oauth2|nonexistent",
+			userID: uuid.NewString(),
 			setupMocks: func(_ *mocks.CustomerRepository, ur *mocks.UserRepository, userID string) {
-				ur.On("GetByProviderID", mock.Anything, userID).
+				ur.On("GetByID", mock.Anything, userID).
 					Return(nil, customErrors.ErrUserNotFound)
 			},
 			expectedError: customErrors.ErrUserNotFound,
 		},
 		{
 			name:   "Error - Customer Already Exists",
-			userID: "google-oauth2|existing",
+			userID: uuid.NewString(),
 			setupMocks: func(cr *mocks.CustomerRepository, ur *mocks.UserRepository, userID string) {
 				user := createTestUser()
-				ur.On("GetByProviderID", mock.Anything, userID).Return(user, nil)
+				ur.On("GetByID", mock.Anything, userID).Return(user, nil)
 				cr.On("GetByUserID", mock.Anything, user.ID.String()).
 					Return(&domain.Customer{
 						ID:     uuid.New(),
@@ -257,7 +257,8 @@ func TestCustomerService_List(t *testing.T) {
 						User:   createTestUser(),
 					},
 				}
-				cr.On("List", mock.Anything).Return(customers, nil)
+				cr.On("List", mock.Anything, domain.ListQuery{}).
+					Return(&domain.Page[domain.Customer]{Items: customers}, nil)
 			},
 			expectedCount: 2,
 			expectedError: nil,
@@ -265,7 +266,8 @@ func TestCustomerService_List(t *testing.T) {
 		{
 			name: "Success - Empty List",
 			setupMocks: func(cr *mocks.CustomerRepository) {
-				cr.On("List", mock.Anything).Return([]domain.Customer{}, nil)
+				cr.On("List", mock.Anything, domain.ListQuery{}).
+					Return(&domain.Page[domain.Customer]{Items: []domain.Customer{}}, nil)
 			},
 			expectedCount: 0,
 			expectedError: nil,
@@ -277,16 +279,19 @@ func TestCustomerService_List(t *testing.T) {
 			service, customerRepo, _ := setupCustomerTest(t)
 			tt.setupMocks(customerRepo)
 
-			customers, err := service.List(context.Background())
+			customers, err := service.List(
+				context.Background(),
+				domain.ListQuery{},
+			)
 
 			if tt.expectedError != nil {
 				assert.ErrorIs(t, err, tt.expectedError)
 				assert.Nil(t, customers)
 			} else {
 				assert.NoError(t, err)
-				assert.Len(t, customers, tt.expectedCount)
+				assert.Len(t, customers.Items, tt.expectedCount)
 				if tt.expectedCount > 0 {
-					for _, c := range customers {
+					for _, c := range customers.Items {
 						assert.NotNil(t, c.User)
 					}
 				}
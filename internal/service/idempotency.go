@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grocery-service/internal/config"
+	"github.com/grocery-service/internal/domain"
+	repository "github.com/grocery-service/internal/repository/postgres"
+	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/grocery-service/utils/logger"
+)
+
+// maxIdempotencyKeyLength is the size of the column keys are stored in.
+const maxIdempotencyKeyLength = 255
+
+type (
+	// IdempotencyService lets clients retry requests safely. The first
+	// request sent with a key runs and has its response recorded;
+	// retries with the same key get that response instead of running
+	// again.
+	IdempotencyService interface {
+		// Begin claims key for a request by client with fingerprint.
+		// A key that is not completed was claimed for this request,
+		// which must then be passed to Complete or Release. A
+		// completed key holds the response to replay. Begin fails with
+		// ErrIdempotencyKeyMismatch when the key was used for a
+		// different request and ErrIdempotencyKeyInUse while the first
+		// request is still running. A key whose first request has not
+		// finished within the lock timeout is claimed again.
+		Begin(
+			ctx context.Context,
+			client string,
+			key string,
+			fingerprint string,
+		) (*domain.IdempotencyKey, error)
+		// Complete records the response set on a key claimed by Begin.
+		Complete(ctx context.Context, key *domain.IdempotencyKey) error
+		// Release gives up a key claimed by Begin without recording a
+		// response, so that the request can be retried.
+		Release(ctx context.Context, key *domain.IdempotencyKey) error
+	}
+
+	IdempotencyServiceImpl struct {
+		repo        repository.IdempotencyRepository
+		ttl         time.Duration
+		lockTimeout time.Duration
+	}
+)
+
+func NewIdempotencyService(
+	repo repository.IdempotencyRepository,
+	cfg config.IdempotencyConfig,
+) IdempotencyService {
+	return &IdempotencyServiceImpl{
+		repo:        repo,
+		ttl:         cfg.TTL,
+		lockTimeout: cfg.LockTimeout,
+	}
+}
+
+func (s *IdempotencyServiceImpl) Begin(
+	ctx context.Context,
+	client string,
+	key string,
+	fingerprint string,
+) (*domain.IdempotencyKey, error) {
+	if key == "" || len(key) > maxIdempotencyKeyLength {
+		return nil, fmt.Errorf(
+			"%w: key must be 1 to %d characters",
+			customErrors.ErrInvalidIdempotencyKey,
+			maxIdempotencyKeyLength,
+		)
+	}
+
+	now := time.Now()
+	stored, claimed, err := s.repo.Claim(ctx, &domain.IdempotencyKey{
+		Client:      client,
+		Key:         key,
+		Fingerprint: fingerprint,
+		ClaimID:     uuid.New(),
+		CreatedAt:   now,
+		LockedUntil: now.Add(s.lockTimeout),
+		ExpiresAt:   now.Add(s.ttl),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if claimed {
+		return stored, nil
+	}
+
+	if stored.Fingerprint != fingerprint {
+		return nil, customErrors.ErrIdempotencyKeyMismatch
+	}
+	if !stored.Completed() {
+		return nil, customErrors.ErrIdempotencyKeyInUse
+	}
+
+	return stored, nil
+}
+
+func (s *IdempotencyServiceImpl) Complete(
+	ctx context.Context,
+	key *domain.IdempotencyKey,
+) error {
+	now := time.Now()
+	key.CompletedAt = &now
+
+	return s.repo.Complete(ctx, key)
+}
+
+func (s *IdempotencyServiceImpl) Release(
+	ctx context.Context,
+	key *domain.IdempotencyKey,
+) error {
+	return s.repo.Release(ctx, key)
+}
+
+// IdempotencyCleanup periodically deletes expired idempotency keys. It
+// needs no lock: replicas deleting the same expired keys do no harm.
+type IdempotencyCleanup struct {
+	repo     repository.IdempotencyRepository
+	interval time.Duration
+}
+
+func NewIdempotencyCleanup(
+	repo repository.IdempotencyRepository,
+	cfg config.IdempotencyConfig,
+) *IdempotencyCleanup {
+	return &IdempotencyCleanup{repo: repo, interval: cfg.CleanupInterval}
+}
+
+// Run deletes expired keys every interval until ctx is cancelled.
+func (c *IdempotencyCleanup) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := c.repo.DeleteExpired(ctx, time.Now()); err != nil &&
+				ctx.Err() == nil {
+				logger.Error(
+					"idempotency key cleanup failed",
+					logger.Error64("error", err),
+				)
+			}
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grocery-service/internal/config"
+	"github.com/grocery-service/internal/domain"
+	repoMocks "github.com/grocery-service/tests/mocks/repository"
+	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyService_Begin(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.IdempotencyConfig{TTL: time.Hour, LockTimeout: time.Minute}
+	completedAt := time.Now()
+
+	tests := []struct {
+		name      string
+		key       string
+		stored    *domain.IdempotencyKey
+		claimed   bool
+		wantErr   error
+		wantReply bool
+	}{
+		{
+			name:    "Claims New Key",
+			key:     "order-1",
+			claimed: true,
+		},
+		{
+			name: "Replays Completed Key",
+			key:  "order-1",
+			stored: &domain.IdempotencyKey{
+				Fingerprint: "fingerprint",
+				StatusCode:  201,
+				CompletedAt: &completedAt,
+			},
+			wantReply: true,
+		},
+		{
+			name:    "Error - In Progress",
+			key:     "order-1",
+			stored:  &domain.IdempotencyKey{Fingerprint: "fingerprint"},
+			wantErr: customErrors.ErrIdempotencyKeyInUse,
+		},
+		{
+			name: "Error - Different Request",
+			key:  "order-1",
+			stored: &domain.IdempotencyKey{
+				Fingerprint: "other",
+				CompletedAt: &completedAt,
+			},
+			wantErr: customErrors.ErrIdempotencyKeyMismatch,
+		},
+		{
+			name:    "Error - Empty Key",
+			wantErr: customErrors.ErrInvalidIdempotencyKey,
+		},
+		{
+			name:    "Error - Long Key",
+			key:     strings.Repeat("k", 256),
+			wantErr: customErrors.ErrInvalidIdempotencyKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repoMocks.NewIdempotencyRepository(t)
+			service := NewIdempotencyService(repo, cfg)
+
+			if tt.key != "" && len(tt.key) <= maxIdempotencyKeyLength {
+				repo.On(
+					"Claim",
+					ctx,
+					mock.MatchedBy(func(k *domain.IdempotencyKey) bool {
+						return k.Client == "user:1" &&
+							k.Key == tt.key &&
+							k.Fingerprint == "fingerprint" &&
+							k.ClaimID != uuid.Nil &&
+							k.LockedUntil.Sub(k.CreatedAt) == time.Minute &&
+							k.ExpiresAt.Sub(k.CreatedAt) == time.Hour
+					}),
+				).Return(
+					func(
+						_ context.Context,
+						k *domain.IdempotencyKey,
+					) (*domain.IdempotencyKey, bool, error) {
+						if tt.claimed {
+							return k, true, nil
+						}
+						return tt.stored, false, nil
+					},
+				).Once()
+			}
+
+			key, err := service.Begin(ctx, "user:1", tt.key, "fingerprint")
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantReply, key.Completed())
+		})
+	}
+}
+
+func TestIdempotencyService_Complete(t *testing.T) {
+	ctx := context.Background()
+	repo := repoMocks.NewIdempotencyRepository(t)
+	service := NewIdempotencyService(repo, config.IdempotencyConfig{})
+
+	key := &domain.IdempotencyKey{
+		Client:     "user:1",
+		Key:        "order-1",
+		StatusCode: 201,
+	}
+	repo.On("Complete", ctx, key).Return(nil).Once()
+	repo.On("Release", ctx, key).
+		Return(customErrors.ErrIdempotencyKeyNotFound).Once()
+
+	require.NoError(t, service.Complete(ctx, key))
+	assert.True(t, key.Completed())
+
+	err := service.Release(ctx, key)
+	assert.ErrorIs(t, err, customErrors.ErrIdempotencyKeyNotFound)
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grocery-service/internal/domain"
+	repository "github.com/grocery-service/internal/repository/postgres"
+)
+
+type (
+	// InventoryService reads the stock ledger. products.stock is a cache
+	// of the ledger sum; Reconcile finds and repairs products where the
+	// two disagree.
+	InventoryService interface {
+		GetStockHistory(
+			ctx context.Context,
+			productID string,
+			query domain.ListQuery,
+		) (*domain.Page[domain.StockMovement], error)
+		GetLedgerStock(ctx context.Context, productID string) (int, error)
+		// ListLowStock lists products at or below their reorder point,
+		// lowest stock first unless the query sorts otherwise.
+		ListLowStock(
+			ctx context.Context,
+			query domain.ListQuery,
+		) (*domain.Page[domain.Product], error)
+		// Reconcile returns every product whose cached stock drifted from
+		// its ledger. With fix set, the cached stock of those products is
+		// reset to the ledger sum.
+		Reconcile(ctx context.Context, fix bool) ([]domain.StockDrift, error)
+	}
+
+	InventoryServiceImpl struct {
+		repo        repository.StockMovementRepository
+		productRepo repository.ProductRepository
+	}
+)
+
+func NewInventoryService(
+	repo repository.StockMovementRepository,
+	productRepo repository.ProductRepository,
+) InventoryService {
+	return &InventoryServiceImpl{
+		repo:        repo,
+		productRepo: productRepo,
+	}
+}
+
+func (s *InventoryServiceImpl) GetStockHistory(
+	ctx context.Context,
+	productID string,
+	query domain.ListQuery,
+) (*domain.Page[domain.StockMovement], error) {
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByProductID(ctx, productID, query)
+}
+
+func (s *InventoryServiceImpl) GetLedgerStock(
+	ctx context.Context,
+	productID string,
+) (int, error) {
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		return 0, err
+	}
+	return s.repo.LedgerStock(ctx, productID)
+}
+
+func (s *InventoryServiceImpl) ListLowStock(
+	ctx context.Context,
+	query domain.ListQuery,
+) (*domain.Page[domain.Product], error) {
+	return s.productRepo.ListLowStock(ctx, query)
+}
+
+func (s *InventoryServiceImpl) Reconcile(
+	ctx context.Context,
+	fix bool,
+) ([]domain.StockDrift, error) {
+	drift, err := s.repo.FindDrift(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find stock drift: %w", err)
+	}
+
+	if !fix {
+		return drift, nil
+	}
+
+	for _, d := range drift {
+		if err := s.repo.SyncStock(ctx, d.ProductID.String()); err != nil {
+			return drift, fmt.Errorf(
+				"failed to reconcile product %s: %w",
+				d.ProductID,
+				err,
+			)
+		}
+	}
+	return drift, nil
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/grocery-service/internal/domain"
+	repoMocks "github.com/grocery-service/tests/mocks/repository"
+	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupInventoryTest(t *testing.T) (
+	InventoryService,
+	*repoMocks.StockMovementRepository,
+	*repoMocks.ProductRepository,
+) {
+	repo := repoMocks.NewStockMovementRepository(t)
+	productRepo := repoMocks.NewProductRepository(t)
+	return NewInventoryService(repo, productRepo), repo, productRepo
+}
+
+func TestInventoryService_GetStockHistory(t *testing.T) {
+	service, repo, productRepo := setupInventoryTest(t)
+	ctx := context.Background()
+	productID := uuid.New().String()
+
+	t.Run("Success", func(t *testing.T) {
+		page := &domain.Page[domain.StockMovement]{Total: 2}
+		productRepo.On("GetByID", ctx, productID).
+			Return(&domain.Product{}, nil).Once()
+		repo.On("ListByProductID", ctx, productID, domain.ListQuery{}).
+			Return(page, nil).Once()
+
+		result, err := service.GetStockHistory(ctx, productID, domain.ListQuery{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, page, result)
+	})
+
+	t.Run("Error - Product Not Found", func(t *testing.T) {
+		productRepo.On("GetByID", ctx, productID).
+			Return(nil, customErrors.ErrProductNotFound).Once()
+
+		_, err := service.GetStockHistory(ctx, productID, domain.ListQuery{})
+
+		assert.ErrorIs(t, err, customErrors.ErrProductNotFound)
+	})
+}
+
+func TestInventoryService_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	drift := []domain.StockDrift{
+		{ProductID: uuid.New(), CachedStock: 10, LedgerStock: 8},
+		{ProductID: uuid.New(), CachedStock: 0, LedgerStock: 3},
+	}
+
+	t.Run("Report Only", func(t *testing.T) {
+		service, repo, _ := setupInventoryTest(t)
+		repo.On("FindDrift", ctx).Return(drift, nil).Once()
+
+		result, err := service.Reconcile(ctx, false)
+
+		assert.NoError(t, err)
+		assert.Equal(t, drift, result)
+		repo.AssertNotCalled(t, "SyncStock")
+	})
+
+	t.Run("Fix", func(t *testing.T) {
+		service, repo, _ := setupInventoryTest(t)
+		repo.On("FindDrift", ctx).Return(drift, nil).Once()
+		for _, d := range drift {
+			repo.On("SyncStock", ctx, d.ProductID.String()).Return(nil).Once()
+		}
+
+		result, err := service.Reconcile(ctx, true)
+
+		assert.NoError(t, err)
+		assert.Equal(t, drift, result)
+	})
+
+	t.Run("Error - Fix Fails", func(t *testing.T) {
+		service, repo, _ := setupInventoryTest(t)
+		repo.On("FindDrift", ctx).Return(drift, nil).Once()
+		repo.On("SyncStock", ctx, drift[0].ProductID.String()).
+			Return(errors.New("connection reset")).Once()
+
+		_, err := service.Reconcile(ctx, true)
+
+		assert.ErrorContains(t, err, drift[0].ProductID.String())
+	})
+}
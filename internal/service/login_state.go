@@ -0,0 +1,187 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	customErrors "github.com/grocery-service/utils/errors"
+)
+
+// loginStateTTL bounds how long a user may take at the provider's login
+// page.
+const loginStateTTL = 10 * time.Minute
+
+// loginState is everything a login needs to remember between
+// GetAuthURL and HandleCallback. It travels with the client, sealed with
+// an HMAC so it cannot be forged or altered.
+type loginState struct {
+	Provider     string `json:"p"`
+	State        string `json:"s"`
+	Nonce        string `json:"n"`
+	CodeVerifier string `json:"v"`
+	RedirectTo   string `json:"r,omitempty"`
+	ExpiresAt    int64  `json:"e"`
+}
+
+// loginStateKey derives the login state MAC key from the JWT secret.
+func loginStateKey(secret string) []byte {
+	return deriveKey(secret, "grocery-service login state")
+}
+
+// deriveKey derives a key for one purpose from a configured secret, so
+// that a value sealed for one purpose is never accepted for another.
+func deriveKey(secret, purpose string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
+
+func sealLoginState(key []byte, state loginState) (string, error) {
+	sealed, err := seal(key, state)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode login state: %w", err)
+	}
+	return sealed, nil
+}
+
+// openLoginState verifies a sealed login state, that it has not expired
+// and that it belongs to the state the provider sent back.
+func openLoginState(
+	key []byte,
+	sealed string,
+	returnedState string,
+	now time.Time,
+) (*loginState, error) {
+	var state loginState
+	if err := unseal(key, sealed, &state); err != nil {
+		return nil, fmt.Errorf(
+			"%w: %v",
+			customErrors.ErrInvalidLoginState,
+			err,
+		)
+	}
+
+	if now.Unix() >= state.ExpiresAt {
+		return nil, fmt.Errorf(
+			"%w: expired",
+			customErrors.ErrInvalidLoginState,
+		)
+	}
+
+	if returnedState == "" || !hmac.Equal(
+		[]byte(state.State),
+		[]byte(returnedState),
+	) {
+		return nil, fmt.Errorf(
+			"%w: state mismatch",
+			customErrors.ErrInvalidLoginState,
+		)
+	}
+
+	return &state, nil
+}
+
+var (
+	errBadSignature = errors.New("bad signature")
+	errMalformed    = errors.New("malformed")
+)
+
+// seal encodes v as JSON followed by an HMAC under key.
+func seal(key []byte, v interface{}) (string, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + sign(key, encoded), nil
+}
+
+// unseal verifies a value sealed under key and decodes it into v.
+func unseal(key []byte, sealed string, v interface{}) error {
+	encoded, signature, ok := strings.Cut(sealed, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(sign(key, encoded))) {
+		return errBadSignature
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return errMalformed
+	}
+	if err := json.Unmarshal(payload, v); err != nil {
+		return errMalformed
+	}
+	return nil
+}
+
+func sign(key []byte, encoded string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func parseAllowedRedirects(entries []string) ([]*url.URL, error) {
+	allowed := make([]*url.URL, 0, len(entries))
+	for _, entry := range entries {
+		u, err := url.Parse(strings.TrimSpace(entry))
+		if err != nil || u.Host == "" ||
+			(u.Scheme != "http" && u.Scheme != "https") {
+			return nil, fmt.Errorf(
+				"invalid allowed redirect %q: must be an http(s) URL",
+				entry,
+			)
+		}
+		allowed = append(allowed, u)
+	}
+	return allowed, nil
+}
+
+// checkRedirect accepts relative paths on the same site and absolute
+// URLs on an allowed origin under the allowed path prefix.
+func checkRedirect(redirectTo string, allowed []*url.URL) error {
+	if redirectTo == "" {
+		return nil
+	}
+
+	// Backslashes are treated as slashes by browsers, which would turn
+	// "/\evil.com" into a protocol-relative URL.
+	if strings.ContainsAny(redirectTo, "\\\r\n\t") {
+		return customErrors.ErrInvalidRedirect
+	}
+
+	target, err := url.Parse(redirectTo)
+	if err != nil {
+		return customErrors.ErrInvalidRedirect
+	}
+
+	if target.Scheme == "" && target.Host == "" {
+		if strings.HasPrefix(redirectTo, "/") &&
+			!strings.HasPrefix(redirectTo, "//") {
+			return nil
+		}
+		return customErrors.ErrInvalidRedirect
+	}
+
+	for _, a := range allowed {
+		if !strings.EqualFold(target.Scheme, a.Scheme) ||
+			!strings.EqualFold(target.Host, a.Host) ||
+			target.User != nil {
+			continue
+		}
+
+		prefix := strings.TrimSuffix(a.Path, "/")
+		if prefix == "" || target.Path == prefix ||
+			strings.HasPrefix(target.Path, prefix+"/") {
+			return nil
+		}
+	}
+
+	return customErrors.ErrInvalidRedirect
+}
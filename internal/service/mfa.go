@@ -0,0 +1,639 @@
+package service
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grocery-service/internal/domain"
+	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/grocery-service/utils/logger"
+	"github.com/grocery-service/utils/totp"
+)
+
+// MFA adds a TOTP code, or a single-use recovery code, to the first
+// factor of a login. A login that needs one returns an MFA token instead
+// of tokens; the token is sealed like the login state, so no challenge
+// is stored. Users that an admin requires to use MFA but have not
+// enrolled are enrolled as part of the same login.
+//
+// TOTP secrets are encrypted with a key derived from TOKEN_HASH_KEY, and
+// changing that key drops every enrollment along with the stored tokens.
+
+const (
+	mfaPurposeLogin  = "login"
+	mfaPurposeEnroll = "enroll"
+
+	// recoveryCodeCount is how many recovery codes a user gets at once.
+	recoveryCodeCount = 10
+	// mfaSkew is how many time steps either side of now a TOTP code is
+	// accepted for.
+	mfaSkew = 1
+)
+
+// mfaChallenge is a login or enrollment waiting for a TOTP code. Secret
+// is the secret being enrolled, which the user has been shown already.
+type mfaChallenge struct {
+	Purpose    string `json:"u"`
+	UserID     string `json:"i"`
+	Provider   string `json:"p,omitempty"`
+	ProviderID string `json:"s,omitempty"`
+	RedirectTo string `json:"r,omitempty"`
+	Secret     string `json:"k,omitempty"`
+	ExpiresAt  int64  `json:"e"`
+}
+
+// mfaChallengeKey derives the MFA challenge MAC key from the JWT secret.
+func mfaChallengeKey(secret string) []byte {
+	return deriveKey(secret, "grocery-service mfa challenge")
+}
+
+// mfaSecretKey derives the AES-256 key that TOTP secrets are encrypted
+// with from the token hash key.
+func mfaSecretKey(tokenHashKey string) []byte {
+	return deriveKey(tokenHashKey, "grocery-service mfa secret")
+}
+
+// completeLogin finishes a login once the first factor has been checked,
+// or returns an MFA challenge when the user has a second one to pass.
+func (s *authService) completeLogin(
+	ctx context.Context,
+	user *domain.User,
+	g grant,
+	redirectTo string,
+) (*domain.AuthResponse, error) {
+	if user.MFAEnabled() || user.MFARequired {
+		return s.challengeMFA(user, g, redirectTo)
+	}
+
+	// Failed attempts are only forgotten once every factor has passed
+	if user.FailedLoginAttempts > 0 {
+		if err := s.userRepo.ResetFailedLogins(
+			ctx,
+			user.ID.String(),
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := s.issueTokens(ctx, user, g)
+	if err != nil {
+		return nil, err
+	}
+	resp.RedirectTo = redirectTo
+
+	return resp, nil
+}
+
+func (s *authService) challengeMFA(
+	user *domain.User,
+	g grant,
+	redirectTo string,
+) (*domain.AuthResponse, error) {
+	challenge := mfaChallenge{
+		Purpose:    mfaPurposeLogin,
+		UserID:     user.ID.String(),
+		Provider:   g.provider,
+		ProviderID: g.providerID,
+		RedirectTo: redirectTo,
+		ExpiresAt:  time.Now().Add(s.mfa.ChallengeTTL).Unix(),
+	}
+
+	var enrollment *domain.MFAEnrollment
+	if !user.MFAEnabled() {
+		var err error
+		enrollment, err = s.newEnrollment(user)
+		if err != nil {
+			return nil, err
+		}
+		challenge.Secret = enrollment.Secret
+	}
+
+	token, err := seal(s.mfaKey, challenge)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode MFA challenge: %w", err)
+	}
+
+	return &domain.AuthResponse{
+		User:          user,
+		MFARequired:   true,
+		MFAToken:      token,
+		MFAEnrollment: enrollment,
+	}, nil
+}
+
+func (s *authService) VerifyMFA(
+	ctx context.Context,
+	request domain.MFAVerifyRequest,
+	client domain.ClientInfo,
+) (*domain.AuthResponse, error) {
+	challenge, err := s.openChallenge(request.MFAToken, mfaPurposeLogin)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, challenge.UserID)
+	if err != nil {
+		if errors.Is(err, customErrors.ErrUserNotFound) {
+			return nil, customErrors.ErrInvalidMFAChallenge
+		}
+		return nil, err
+	}
+
+	var codes []string
+	if challenge.Secret != "" {
+		codes, err = s.enableMFA(ctx, user, challenge.Secret, request.Code)
+	} else if !user.MFAEnabled() {
+		// MFA was reset while the challenge was outstanding
+		return nil, customErrors.ErrInvalidMFAChallenge
+	} else {
+		err = s.passMFA(ctx, user, request.Code)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if user.FailedLoginAttempts > 0 {
+		if err := s.userRepo.ResetFailedLogins(
+			ctx,
+			user.ID.String(),
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := s.issueTokens(ctx, user, grant{
+		provider:   challenge.Provider,
+		providerID: challenge.ProviderID,
+		familyID:   uuid.New(),
+		client:     client,
+		mfaAt:      time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp.RedirectTo = challenge.RedirectTo
+	resp.RecoveryCodes = codes
+
+	return resp, nil
+}
+
+func (s *authService) GetMFAStatus(
+	ctx context.Context,
+	userID string,
+) (*domain.MFAStatus, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &domain.MFAStatus{
+		Enabled:   user.MFAEnabled(),
+		EnabledAt: user.MFAEnabledAt,
+		Required:  user.MFARequired,
+	}
+	if status.Enabled {
+		status.RecoveryCodesRemaining, err = s.mfaRepo.CountRecoveryCodes(
+			ctx,
+			userID,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return status, nil
+}
+
+func (s *authService) EnrollMFA(
+	ctx context.Context,
+	userID string,
+) (*domain.MFAEnrollment, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFAEnabled() {
+		return nil, customErrors.ErrMFAAlreadyEnabled
+	}
+
+	enrollment, err := s.newEnrollment(user)
+	if err != nil {
+		return nil, err
+	}
+
+	enrollment.EnrollmentToken, err = seal(s.mfaKey, mfaChallenge{
+		Purpose:   mfaPurposeEnroll,
+		UserID:    userID,
+		Secret:    enrollment.Secret,
+		ExpiresAt: time.Now().Add(s.mfa.ChallengeTTL).Unix(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode MFA enrollment: %w", err)
+	}
+
+	return enrollment, nil
+}
+
+func (s *authService) ConfirmMFA(
+	ctx context.Context,
+	userID string,
+	request domain.ConfirmMFARequest,
+) (*domain.MFARecoveryCodes, error) {
+	challenge, err := s.openChallenge(
+		request.EnrollmentToken,
+		mfaPurposeEnroll,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if challenge.UserID != userID {
+		return nil, customErrors.ErrInvalidMFAChallenge
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, err := s.enableMFA(ctx, user, challenge.Secret, request.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.MFARecoveryCodes{Codes: codes}, nil
+}
+
+func (s *authService) DisableMFA(
+	ctx context.Context,
+	userID string,
+	code string,
+) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.MFAEnabled() {
+		return customErrors.ErrMFANotEnabled
+	}
+	if user.MFARequired {
+		return customErrors.ErrMFARequired
+	}
+
+	if err := s.passMFA(ctx, user, code); err != nil {
+		return err
+	}
+
+	return s.mfaRepo.Disable(ctx, userID)
+}
+
+func (s *authService) RegenerateRecoveryCodes(
+	ctx context.Context,
+	userID string,
+	code string,
+) (*domain.MFARecoveryCodes, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.MFAEnabled() {
+		return nil, customErrors.ErrMFANotEnabled
+	}
+
+	if err := s.passMFA(ctx, user, code); err != nil {
+		return nil, err
+	}
+
+	codes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.mfaRepo.ReplaceRecoveryCodes(
+		ctx,
+		userID,
+		normalizeRecoveryCodes(codes),
+	); err != nil {
+		return nil, err
+	}
+
+	return &domain.MFARecoveryCodes{Codes: codes}, nil
+}
+
+func (s *authService) StepUp(
+	ctx context.Context,
+	userID string,
+	sessionID string,
+	code string,
+	client domain.ClientInfo,
+) (*domain.AuthResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.MFAEnabled() {
+		return nil, customErrors.ErrMFANotEnabled
+	}
+
+	// Only a live session is stepped up, and its refresh token says which
+	// provider it signed in with.
+	sessions, err := s.ListSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	var session *domain.Session
+	for i := range sessions {
+		if sessions[i].ID.String() == sessionID {
+			session = &sessions[i]
+			break
+		}
+	}
+	if session == nil {
+		return nil, customErrors.ErrSessionNotFound
+	}
+
+	if err := s.passMFA(ctx, user, code); err != nil {
+		return nil, err
+	}
+
+	g := grant{
+		provider: session.Provider,
+		familyID: session.ID,
+		client:   client,
+		mfaAt:    time.Now(),
+	}
+	accessToken, expiresAt, err := s.signAccessToken(ctx, user, g)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.storeAccessToken(
+		ctx,
+		user,
+		g,
+		accessToken,
+		expiresAt,
+	); err != nil {
+		return nil, err
+	}
+
+	return &domain.AuthResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(s.tokenDuration.Seconds()),
+		User:        user,
+	}, nil
+}
+
+func (s *authService) SetMFARequired(
+	ctx context.Context,
+	userID string,
+	required bool,
+) error {
+	return s.mfaRepo.SetRequired(ctx, userID, required)
+}
+
+func (s *authService) ResetMFA(ctx context.Context, userID string) error {
+	if err := s.mfaRepo.Disable(ctx, userID); err != nil {
+		return err
+	}
+
+	logger.Warn(
+		"security: MFA reset by an administrator",
+		logger.String("user_id", userID),
+	)
+	return nil
+}
+
+// passMFA checks a TOTP or recovery code of the user. Wrong codes count
+// toward the same lockout as wrong passwords.
+func (s *authService) passMFA(
+	ctx context.Context,
+	user *domain.User,
+	code string,
+) error {
+	now := time.Now()
+	if user.IsLocked(now) {
+		return &customErrors.AccountLockedError{Until: *user.LockedUntil}
+	}
+
+	err := s.checkMFACode(ctx, user, code, now)
+	if errors.Is(err, customErrors.ErrInvalidMFACode) {
+		return s.failMFA(ctx, user, now)
+	}
+	return err
+}
+
+func (s *authService) checkMFACode(
+	ctx context.Context,
+	user *domain.User,
+	code string,
+	now time.Time,
+) error {
+	code = strings.ReplaceAll(strings.TrimSpace(code), " ", "")
+	if !isTOTPCode(code) {
+		return s.mfaRepo.UseRecoveryCode(
+			ctx,
+			user.ID.String(),
+			normalizeRecoveryCode(code),
+		)
+	}
+
+	secret, err := s.openSecret(user.MFASecret)
+	if err != nil {
+		return err
+	}
+
+	step, ok := totp.Validate(code, secret, now, mfaSkew)
+	if !ok {
+		return customErrors.ErrInvalidMFACode
+	}
+	return s.mfaRepo.UseTOTPStep(ctx, user.ID.String(), step)
+}
+
+// enableMFA enables MFA with the secret of an enrollment once code shows
+// that the user's authenticator has it, and returns the user's recovery
+// codes.
+func (s *authService) enableMFA(
+	ctx context.Context,
+	user *domain.User,
+	secret string,
+	code string,
+) ([]string, error) {
+	now := time.Now()
+	if user.IsLocked(now) {
+		return nil, &customErrors.AccountLockedError{Until: *user.LockedUntil}
+	}
+
+	step, ok := totp.Validate(strings.TrimSpace(code), secret, now, mfaSkew)
+	if !ok {
+		return nil, s.failMFA(ctx, user, now)
+	}
+
+	encrypted, err := s.sealSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	userID := user.ID.String()
+	if err := s.mfaRepo.Enable(
+		ctx,
+		userID,
+		encrypted,
+		normalizeRecoveryCodes(codes),
+	); err != nil {
+		return nil, err
+	}
+
+	// The code that confirmed the enrollment cannot be used again
+	if err := s.mfaRepo.UseTOTPStep(ctx, userID, step); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// failMFA records a wrong code like a wrong password and returns the
+// error for it.
+func (s *authService) failMFA(
+	ctx context.Context,
+	user *domain.User,
+	now time.Time,
+) error {
+	err := s.failLogin(ctx, user, now)
+	if errors.Is(err, customErrors.ErrInvalidCredentials) {
+		return customErrors.ErrInvalidMFACode
+	}
+	return err
+}
+
+func (s *authService) openChallenge(
+	token string,
+	purpose string,
+) (*mfaChallenge, error) {
+	var challenge mfaChallenge
+	if err := unseal(s.mfaKey, token, &challenge); err != nil {
+		return nil, customErrors.ErrInvalidMFAChallenge
+	}
+
+	if challenge.Purpose != purpose ||
+		time.Now().Unix() >= challenge.ExpiresAt {
+		return nil, customErrors.ErrInvalidMFAChallenge
+	}
+
+	return &challenge, nil
+}
+
+func (s *authService) newEnrollment(
+	user *domain.User,
+) (*domain.MFAEnrollment, error) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.MFAEnrollment{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(s.mfa.Issuer, user.Email, secret),
+	}, nil
+}
+
+// sealSecret encrypts a TOTP secret for storage with AES-GCM.
+func (s *authService) sealSecret(secret string) (string, error) {
+	gcm, err := s.secretCipher()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(secret), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (s *authService) openSecret(sealed string) (string, error) {
+	gcm, err := s.secretCipher()
+	if err != nil {
+		return "", err
+	}
+
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil || len(data) < gcm.NonceSize() {
+		return "", fmt.Errorf("failed to decrypt TOTP secret: malformed")
+	}
+
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	secret, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt TOTP secret: %w", err)
+	}
+	return string(secret), nil
+}
+
+func (s *authService) secretCipher() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(s.secretKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// generateRecoveryCodes returns new recovery codes formatted for display,
+// such as "k7xq-mfp3".
+func generateRecoveryCodes() ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, fmt.Errorf(
+				"failed to generate recovery code: %w",
+				err,
+			)
+		}
+		code := strings.ToLower(recoveryCodeEncoding.EncodeToString(b))
+		codes = append(codes, code[:4]+"-"+code[4:])
+	}
+	return codes, nil
+}
+
+// normalizeRecoveryCode drops the formatting of a recovery code, so that
+// it matches however the user typed it.
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
+
+func normalizeRecoveryCodes(codes []string) []string {
+	normalized := make([]string, 0, len(codes))
+	for _, code := range codes {
+		normalized = append(normalized, normalizeRecoveryCode(code))
+	}
+	return normalized
+}
+
+func isTOTPCode(code string) bool {
+	if len(code) != totp.Digits {
+		return false
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
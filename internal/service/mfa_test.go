@@ -0,0 +1,569 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grocery-service/internal/config"
+	"github.com/grocery-service/internal/domain"
+	repoMocks "github.com/grocery-service/tests/mocks/repository"
+	serviceMocks "github.com/grocery-service/tests/mocks/service"
+	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/grocery-service/utils/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testMFAConfig = config.MFAConfig{
+	Issuer:       "Grocery Test",
+	ChallengeTTL: 5 * time.Minute,
+	StepUpMaxAge: 10 * time.Minute,
+}
+
+func newMFAAuthService(t *testing.T) (
+	*authService,
+	*repoMocks.UserRepository,
+	*repoMocks.TokenRepository,
+	*repoMocks.MFARepository,
+) {
+	userRepo := repoMocks.NewUserRepository(t)
+	tokenRepo := repoMocks.NewTokenRepository(t)
+	mfaRepo := repoMocks.NewMFARepository(t)
+
+	jwtConfig := testJWTConfig
+	jwtConfig.TokenHashKey = "test-token-hash-key"
+
+	service, err := NewAuthService(
+		config.Config{
+			JWT:          jwtConfig,
+			PasswordAuth: testPasswordAuthConfig,
+			MFA:          testMFAConfig,
+		},
+		userRepo,
+		tokenRepo,
+		testRoleRepo(t),
+		mfaRepo,
+		serviceMocks.NewAccountMailer(t),
+		nil,
+	)
+	require.NoError(t, err)
+
+	return service.(*authService), userRepo, tokenRepo, mfaRepo
+}
+
+// mfaUser returns a password user with MFA enabled and the secret of
+// their authenticator.
+func mfaUser(t *testing.T, s *authService) (*domain.User, string) {
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	sealed, err := s.sealSecret(secret)
+	require.NoError(t, err)
+
+	user := passwordUser(t, "correct-horse")
+	enabledAt := time.Now().Add(-24 * time.Hour)
+	user.MFASecret = sealed
+	user.MFAEnabledAt = &enabledAt
+	return user, secret
+}
+
+func currentCode(t *testing.T, secret string) string {
+	code, err := totp.Code(secret, totp.Step(time.Now()))
+	require.NoError(t, err)
+	return code
+}
+
+// expectTokens expects a refresh and an access token to be stored.
+func expectTokens(ctx context.Context, tokenRepo *repoMocks.TokenRepository) {
+	tokenRepo.On("Create", ctx, mock.MatchedBy(func(tok *domain.Token) bool {
+		return tok.Type == domain.TokenTypeRefresh
+	})).Return(nil).Once()
+	tokenRepo.On("Create", ctx, mock.MatchedBy(func(tok *domain.Token) bool {
+		return tok.Type == domain.TokenTypeAccess
+	})).Return(nil).Once()
+}
+
+func TestLoginWithPassword_MFA(t *testing.T) {
+	ctx := context.Background()
+	client := domain.ClientInfo{IPAddress: "203.0.113.7", UserAgent: "test"}
+	login := domain.PasswordLoginRequest{
+		Email:    "jane@example.com",
+		Password: "correct-horse",
+	}
+
+	t.Run("MFA Enabled Returns Challenge", func(t *testing.T) {
+		service, userRepo, _, _ := newMFAAuthService(t)
+		user, _ := mfaUser(t, service)
+		// Failed attempts survive until the second factor passes
+		user.FailedLoginAttempts = 2
+
+		userRepo.On("GetByEmail", ctx, "jane@example.com").
+			Return(user, nil).Once()
+
+		response, err := service.LoginWithPassword(ctx, login, client)
+
+		require.NoError(t, err)
+		assert.True(t, response.MFARequired)
+		assert.NotEmpty(t, response.MFAToken)
+		assert.Nil(t, response.MFAEnrollment)
+		assert.Empty(t, response.AccessToken)
+		assert.Empty(t, response.RefreshToken)
+	})
+
+	t.Run("MFA Required Offers Enrollment", func(t *testing.T) {
+		service, userRepo, _, _ := newMFAAuthService(t)
+		user := passwordUser(t, "correct-horse")
+		user.MFARequired = true
+
+		userRepo.On("GetByEmail", ctx, "jane@example.com").
+			Return(user, nil).Once()
+
+		response, err := service.LoginWithPassword(ctx, login, client)
+
+		require.NoError(t, err)
+		assert.True(t, response.MFARequired)
+		require.NotNil(t, response.MFAEnrollment)
+		assert.NotEmpty(t, response.MFAEnrollment.Secret)
+		assert.True(t, strings.HasPrefix(
+			response.MFAEnrollment.ProvisioningURI,
+			"otpauth://totp/Grocery%20Test:jane@example.com?",
+		))
+		assert.Empty(t, response.AccessToken)
+	})
+}
+
+func TestVerifyMFA(t *testing.T) {
+	ctx := context.Background()
+	client := domain.ClientInfo{IPAddress: "203.0.113.7", UserAgent: "test"}
+
+	challenge := func(
+		t *testing.T,
+		s *authService,
+		user *domain.User,
+	) string {
+		response, err := s.challengeMFA(user, grant{
+			provider:   passwordProvider,
+			providerID: user.ID.String(),
+		}, "/orders")
+		require.NoError(t, err)
+		return response.MFAToken
+	}
+
+	t.Run("Success - TOTP Code", func(t *testing.T) {
+		service, userRepo, tokenRepo, mfaRepo := newMFAAuthService(t)
+		user, secret := mfaUser(t, service)
+		user.FailedLoginAttempts = 1
+
+		userRepo.On("GetByID", ctx, user.ID.String()).Return(user, nil).Once()
+		mfaRepo.On(
+			"UseTOTPStep",
+			ctx,
+			user.ID.String(),
+			mock.AnythingOfType("int64"),
+		).Return(nil).Once()
+		userRepo.On("ResetFailedLogins", ctx, user.ID.String()).
+			Return(nil).Once()
+		expectTokens(ctx, tokenRepo)
+
+		response, err := service.VerifyMFA(ctx, domain.MFAVerifyRequest{
+			MFAToken: challenge(t, service, user),
+			Code:     currentCode(t, secret),
+		}, client)
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, response.RefreshToken)
+		assert.Equal(t, "/orders", response.RedirectTo)
+		assert.Empty(t, response.RecoveryCodes)
+
+		tokenRepo.On("IsValid", ctx, response.AccessToken).Return(true).Once()
+		claims, err := service.VerifyAccessToken(ctx, response.AccessToken)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now(), claims.MFAVerifiedAt, time.Minute)
+	})
+
+	t.Run("Success - Recovery Code", func(t *testing.T) {
+		service, userRepo, tokenRepo, mfaRepo := newMFAAuthService(t)
+		user, _ := mfaUser(t, service)
+
+		userRepo.On("GetByID", ctx, user.ID.String()).Return(user, nil).Once()
+		mfaRepo.On("UseRecoveryCode", ctx, user.ID.String(), "abcd2345").
+			Return(nil).Once()
+		expectTokens(ctx, tokenRepo)
+
+		_, err := service.VerifyMFA(ctx, domain.MFAVerifyRequest{
+			MFAToken: challenge(t, service, user),
+			Code:     " ABCD-2345 ",
+		}, client)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Success - Enrolls Required User", func(t *testing.T) {
+		service, userRepo, tokenRepo, mfaRepo := newMFAAuthService(t)
+		user := passwordUser(t, "correct-horse")
+		user.MFARequired = true
+
+		response, err := service.challengeMFA(user, grant{
+			provider: passwordProvider,
+		}, "")
+		require.NoError(t, err)
+		secret := response.MFAEnrollment.Secret
+
+		userRepo.On("GetByID", ctx, user.ID.String()).Return(user, nil).Once()
+		mfaRepo.On(
+			"Enable",
+			ctx,
+			user.ID.String(),
+			mock.MatchedBy(func(sealed string) bool {
+				opened, err := service.openSecret(sealed)
+				return err == nil && opened == secret && sealed != secret
+			}),
+			mock.MatchedBy(func(codes []string) bool {
+				return len(codes) == recoveryCodeCount
+			}),
+		).Return(nil).Once()
+		mfaRepo.On(
+			"UseTOTPStep",
+			ctx,
+			user.ID.String(),
+			mock.AnythingOfType("int64"),
+		).Return(nil).Once()
+		expectTokens(ctx, tokenRepo)
+
+		verified, err := service.VerifyMFA(ctx, domain.MFAVerifyRequest{
+			MFAToken: response.MFAToken,
+			Code:     currentCode(t, secret),
+		}, client)
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, verified.AccessToken)
+		assert.Len(t, verified.RecoveryCodes, recoveryCodeCount)
+	})
+
+	t.Run("Error - Wrong Code Counts As Failed Login", func(t *testing.T) {
+		service, userRepo, _, mfaRepo := newMFAAuthService(t)
+		user, _ := mfaUser(t, service)
+
+		userRepo.On("GetByID", ctx, user.ID.String()).Return(user, nil).Once()
+		mfaRepo.On("UseRecoveryCode", ctx, user.ID.String(), "wrongcode").
+			Return(customErrors.ErrInvalidMFACode).Once()
+		userRepo.On(
+			"RecordFailedLogin",
+			ctx,
+			user.ID.String(),
+			3,
+			mock.AnythingOfType("time.Time"),
+		).Return(&domain.User{ID: user.ID, FailedLoginAttempts: 1}, nil).Once()
+
+		_, err := service.VerifyMFA(ctx, domain.MFAVerifyRequest{
+			MFAToken: challenge(t, service, user),
+			Code:     "wrong-code",
+		}, client)
+
+		assert.ErrorIs(t, err, customErrors.ErrInvalidMFACode)
+	})
+
+	t.Run("Error - Replayed Code Locks Account", func(t *testing.T) {
+		service, userRepo, _, mfaRepo := newMFAAuthService(t)
+		user, secret := mfaUser(t, service)
+		until := time.Now().Add(testPasswordAuthConfig.LockoutDuration)
+
+		userRepo.On("GetByID", ctx, user.ID.String()).Return(user, nil).Once()
+		mfaRepo.On(
+			"UseTOTPStep",
+			ctx,
+			user.ID.String(),
+			mock.AnythingOfType("int64"),
+		).Return(customErrors.ErrInvalidMFACode).Once()
+		userRepo.On(
+			"RecordFailedLogin",
+			ctx,
+			user.ID.String(),
+			3,
+			mock.AnythingOfType("time.Time"),
+		).Return(&domain.User{ID: user.ID, LockedUntil: &until}, nil).Once()
+
+		_, err := service.VerifyMFA(ctx, domain.MFAVerifyRequest{
+			MFAToken: challenge(t, service, user),
+			Code:     currentCode(t, secret),
+		}, client)
+
+		var locked *customErrors.AccountLockedError
+		assert.ErrorAs(t, err, &locked)
+	})
+
+	t.Run("Error - Locked Account", func(t *testing.T) {
+		service, userRepo, _, _ := newMFAAuthService(t)
+		user, secret := mfaUser(t, service)
+		until := time.Now().Add(time.Minute)
+		user.LockedUntil = &until
+
+		userRepo.On("GetByID", ctx, user.ID.String()).Return(user, nil).Once()
+
+		_, err := service.VerifyMFA(ctx, domain.MFAVerifyRequest{
+			MFAToken: challenge(t, service, user),
+			Code:     currentCode(t, secret),
+		}, client)
+
+		var locked *customErrors.AccountLockedError
+		assert.ErrorAs(t, err, &locked)
+	})
+
+	t.Run("Error - Expired Challenge", func(t *testing.T) {
+		service, _, _, _ := newMFAAuthService(t)
+
+		token, err := seal(service.mfaKey, mfaChallenge{
+			Purpose:   mfaPurposeLogin,
+			UserID:    uuid.NewString(),
+			ExpiresAt: time.Now().Add(-time.Second).Unix(),
+		})
+		require.NoError(t, err)
+
+		_, err = service.VerifyMFA(ctx, domain.MFAVerifyRequest{
+			MFAToken: token,
+			Code:     "123456",
+		}, client)
+
+		assert.ErrorIs(t, err, customErrors.ErrInvalidMFAChallenge)
+	})
+
+	t.Run("Error - Enrollment Token", func(t *testing.T) {
+		service, userRepo, _, _ := newMFAAuthService(t)
+		user := passwordUser(t, "correct-horse")
+
+		userRepo.On("GetByID", ctx, user.ID.String()).Return(user, nil).Once()
+		enrollment, err := service.EnrollMFA(ctx, user.ID.String())
+		require.NoError(t, err)
+
+		_, err = service.VerifyMFA(ctx, domain.MFAVerifyRequest{
+			MFAToken: enrollment.EnrollmentToken,
+			Code:     currentCode(t, enrollment.Secret),
+		}, client)
+
+		assert.ErrorIs(t, err, customErrors.ErrInvalidMFAChallenge)
+	})
+
+	t.Run("Error - Tampered Challenge", func(t *testing.T) {
+		service, _, _, _ := newMFAAuthService(t)
+
+		_, err := service.VerifyMFA(ctx, domain.MFAVerifyRequest{
+			MFAToken: "e30.forged",
+			Code:     "123456",
+		}, client)
+
+		assert.ErrorIs(t, err, customErrors.ErrInvalidMFAChallenge)
+	})
+}
+
+func TestEnrollMFA(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		service, userRepo, _, mfaRepo := newMFAAuthService(t)
+		user := passwordUser(t, "correct-horse")
+		userID := user.ID.String()
+
+		userRepo.On("GetByID", ctx, userID).Return(user, nil).Twice()
+		mfaRepo.On("Enable", ctx, userID, mock.Anything, mock.Anything).
+			Return(nil).Once()
+		mfaRepo.On("UseTOTPStep", ctx, userID, mock.AnythingOfType("int64")).
+			Return(nil).Once()
+
+		enrollment, err := service.EnrollMFA(ctx, userID)
+		require.NoError(t, err)
+		assert.NotEmpty(t, enrollment.EnrollmentToken)
+
+		codes, err := service.ConfirmMFA(ctx, userID, domain.ConfirmMFARequest{
+			EnrollmentToken: enrollment.EnrollmentToken,
+			Code:            currentCode(t, enrollment.Secret),
+		})
+
+		require.NoError(t, err)
+		assert.Len(t, codes.Codes, recoveryCodeCount)
+		for _, code := range codes.Codes {
+			assert.Regexp(t, `^[a-z2-7]{4}-[a-z2-7]{4}$`, code)
+		}
+	})
+
+	t.Run("Error - Already Enabled", func(t *testing.T) {
+		service, userRepo, _, _ := newMFAAuthService(t)
+		user, _ := mfaUser(t, service)
+
+		userRepo.On("GetByID", ctx, user.ID.String()).Return(user, nil).Once()
+
+		_, err := service.EnrollMFA(ctx, user.ID.String())
+
+		assert.ErrorIs(t, err, customErrors.ErrMFAAlreadyEnabled)
+	})
+
+	t.Run("Error - Another User's Enrollment", func(t *testing.T) {
+		service, userRepo, _, _ := newMFAAuthService(t)
+		user := passwordUser(t, "correct-horse")
+
+		userRepo.On("GetByID", ctx, user.ID.String()).Return(user, nil).Once()
+		enrollment, err := service.EnrollMFA(ctx, user.ID.String())
+		require.NoError(t, err)
+
+		_, err = service.ConfirmMFA(
+			ctx,
+			uuid.NewString(),
+			domain.ConfirmMFARequest{
+				EnrollmentToken: enrollment.EnrollmentToken,
+				Code:            currentCode(t, enrollment.Secret),
+			},
+		)
+
+		assert.ErrorIs(t, err, customErrors.ErrInvalidMFAChallenge)
+	})
+}
+
+func TestDisableMFA(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		service, userRepo, _, mfaRepo := newMFAAuthService(t)
+		user, secret := mfaUser(t, service)
+		userID := user.ID.String()
+
+		userRepo.On("GetByID", ctx, userID).Return(user, nil).Once()
+		mfaRepo.On("UseTOTPStep", ctx, userID, mock.AnythingOfType("int64")).
+			Return(nil).Once()
+		mfaRepo.On("Disable", ctx, userID).Return(nil).Once()
+
+		err := service.DisableMFA(ctx, userID, currentCode(t, secret))
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Error - Required", func(t *testing.T) {
+		service, userRepo, _, _ := newMFAAuthService(t)
+		user, secret := mfaUser(t, service)
+		user.MFARequired = true
+
+		userRepo.On("GetByID", ctx, user.ID.String()).Return(user, nil).Once()
+
+		err := service.DisableMFA(
+			ctx,
+			user.ID.String(),
+			currentCode(t, secret),
+		)
+
+		assert.ErrorIs(t, err, customErrors.ErrMFARequired)
+	})
+
+	t.Run("Error - Not Enabled", func(t *testing.T) {
+		service, userRepo, _, _ := newMFAAuthService(t)
+		user := passwordUser(t, "correct-horse")
+
+		userRepo.On("GetByID", ctx, user.ID.String()).Return(user, nil).Once()
+
+		err := service.DisableMFA(ctx, user.ID.String(), "123456")
+
+		assert.ErrorIs(t, err, customErrors.ErrMFANotEnabled)
+	})
+}
+
+func TestStepUp(t *testing.T) {
+	ctx := context.Background()
+	client := domain.ClientInfo{IPAddress: "203.0.113.7", UserAgent: "test"}
+
+	t.Run("Success", func(t *testing.T) {
+		service, userRepo, tokenRepo, mfaRepo := newMFAAuthService(t)
+		user, secret := mfaUser(t, service)
+		userID := user.ID.String()
+		familyID := uuid.New()
+
+		userRepo.On("GetByID", ctx, userID).Return(user, nil).Once()
+		tokenRepo.On("GetByUserAndType", ctx, userID, domain.TokenTypeRefresh).
+			Return([]domain.Token{{
+				UserID:   user.ID,
+				FamilyID: familyID,
+				Provider: "google",
+			}}, nil).Once()
+		mfaRepo.On("UseTOTPStep", ctx, userID, mock.AnythingOfType("int64")).
+			Return(nil).Once()
+		tokenRepo.On("Create", ctx, mock.MatchedBy(func(tok *domain.Token) bool {
+			return tok.Type == domain.TokenTypeAccess &&
+				tok.FamilyID == familyID &&
+				tok.Provider == "google"
+		})).Return(nil).Once()
+
+		response, err := service.StepUp(
+			ctx,
+			userID,
+			familyID.String(),
+			currentCode(t, secret),
+			client,
+		)
+
+		require.NoError(t, err)
+		assert.Empty(t, response.RefreshToken)
+
+		tokenRepo.On("IsValid", ctx, response.AccessToken).Return(true).Once()
+		claims, err := service.VerifyAccessToken(ctx, response.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, familyID, claims.SessionID)
+		assert.WithinDuration(t, time.Now(), claims.MFAVerifiedAt, time.Minute)
+	})
+
+	t.Run("Error - Revoked Session", func(t *testing.T) {
+		service, userRepo, tokenRepo, _ := newMFAAuthService(t)
+		user, secret := mfaUser(t, service)
+		userID := user.ID.String()
+
+		userRepo.On("GetByID", ctx, userID).Return(user, nil).Once()
+		tokenRepo.On("GetByUserAndType", ctx, userID, domain.TokenTypeRefresh).
+			Return([]domain.Token{}, nil).Once()
+
+		_, err := service.StepUp(
+			ctx,
+			userID,
+			uuid.NewString(),
+			currentCode(t, secret),
+			client,
+		)
+
+		assert.ErrorIs(t, err, customErrors.ErrSessionNotFound)
+	})
+
+	t.Run("Error - MFA Not Enabled", func(t *testing.T) {
+		service, userRepo, _, _ := newMFAAuthService(t)
+		user := passwordUser(t, "correct-horse")
+
+		userRepo.On("GetByID", ctx, user.ID.String()).Return(user, nil).Once()
+
+		_, err := service.StepUp(
+			ctx,
+			user.ID.String(),
+			uuid.NewString(),
+			"123456",
+			client,
+		)
+
+		assert.ErrorIs(t, err, customErrors.ErrMFANotEnabled)
+	})
+}
+
+func TestRegenerateRecoveryCodes(t *testing.T) {
+	ctx := context.Background()
+	service, userRepo, _, mfaRepo := newMFAAuthService(t)
+	user, _ := mfaUser(t, service)
+	userID := user.ID.String()
+
+	var stored []string
+	userRepo.On("GetByID", ctx, userID).Return(user, nil).Once()
+	mfaRepo.On("UseRecoveryCode", ctx, userID, "abcd2345").Return(nil).Once()
+	mfaRepo.On("ReplaceRecoveryCodes", ctx, userID, mock.Anything).
+		Run(func(args mock.Arguments) {
+			stored = args.Get(2).([]string)
+		}).Return(nil).Once()
+
+	codes, err := service.RegenerateRecoveryCodes(ctx, userID, "abcd-2345")
+
+	require.NoError(t, err)
+	require.Len(t, codes.Codes, recoveryCodeCount)
+	assert.Equal(t, normalizeRecoveryCodes(codes.Codes), stored)
+}
@@ -3,6 +3,7 @@ package notification
 import (
 	"context"
 	"fmt"
+	"html"
 	"net/smtp"
 	"time"
 
@@ -11,14 +12,19 @@ import (
 )
 
 type EmailService struct {
-	config config.SMTPConfig
+	config      config.SMTPConfig
+	adminEmails []string
 }
 
+// NewEmailService returns an email notifier. Admin alerts are sent to
+// adminEmails; with none configured they are skipped.
 func NewEmailService(
 	config config.SMTPConfig,
+	adminEmails []string,
 ) *EmailService {
 	return &EmailService{
-		config: config,
+		config:      config,
+		adminEmails: adminEmails,
 	}
 }
 
@@ -60,6 +66,77 @@ func (s *EmailService) SendOrderStatusUpdate(
 	)
 }
 
+func (s *EmailService) SendLowStockAlert(
+	ctx context.Context,
+	alerts []domain.LowStockAlert,
+) error {
+	if len(alerts) == 0 || len(s.adminEmails) == 0 {
+		return nil
+	}
+
+	subject := fmt.Sprintf("Low stock: %d product(s) to reorder", len(alerts))
+	body := s.generateLowStockDigestEmail(alerts)
+
+	for _, to := range s.adminEmails {
+		if err := s.SendEmail(ctx, to, subject, body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *EmailService) SendEmailVerification(
+	ctx context.Context,
+	user *domain.User,
+	link string,
+) error {
+	body := fmt.Sprintf(`
+		<h2>Confirm your email address</h2>
+		<p>Dear %s,</p>
+		<p>Please confirm your email address to finish signing up:</p>
+		<p><a href="%s">Confirm email address</a></p>
+		<p>If you did not sign up, you can ignore this email.</p>
+		<p>Best regards,<br>Grocery Service Team</p>
+	`, html.EscapeString(user.Name), html.EscapeString(link))
+
+	return s.SendEmail(ctx, user.Email, "Confirm your email address", body)
+}
+
+func (s *EmailService) SendPasswordReset(
+	ctx context.Context,
+	user *domain.User,
+	link string,
+) error {
+	body := fmt.Sprintf(`
+		<h2>Reset your password</h2>
+		<p>Dear %s,</p>
+		<p>Use the link below to choose a new password. It works once.</p>
+		<p><a href="%s">Reset password</a></p>
+		<p>If you did not ask to reset your password, you can ignore this email.</p>
+		<p>Best regards,<br>Grocery Service Team</p>
+	`, html.EscapeString(user.Name), html.EscapeString(link))
+
+	return s.SendEmail(ctx, user.Email, "Reset your password", body)
+}
+
+func (s *EmailService) SendAccountExists(
+	ctx context.Context,
+	user *domain.User,
+	link string,
+) error {
+	body := fmt.Sprintf(`
+		<h2>You already have an account</h2>
+		<p>Dear %s,</p>
+		<p>Someone tried to sign up with this email address, which already has an account.</p>
+		<p>If it was you, sign in as usual or use the link below to choose a new password. It works once.</p>
+		<p><a href="%s">Reset password</a></p>
+		<p>If it was not you, you can ignore this email.</p>
+		<p>Best regards,<br>Grocery Service Team</p>
+	`, html.EscapeString(user.Name), html.EscapeString(link))
+
+	return s.SendEmail(ctx, user.Email, "You already have an account", body)
+}
+
 func (s *EmailService) SendEmail(
 	ctx context.Context,
 	to, subject, body string,
@@ -115,7 +192,7 @@ func (s *EmailService) generateOrderConfirmationEmail(
 		<p>Dear %s,</p>
 		<p>Thank you for your order. Here are your order details:</p>
 		<p><strong>Order ID:</strong> %s</p>
-		<p><strong>Total Amount:</strong> %.2f</p>
+		<p><strong>Total Amount:</strong> %s</p>
 		<p><strong>Delivery Address:</strong> %s</p>
 		<p><strong>Contact Phone:</strong> %s</p>
 		<h3>Order Items:</h3>
@@ -125,7 +202,7 @@ func (s *EmailService) generateOrderConfirmationEmail(
 
 	for _, item := range order.Items {
 		itemsList += fmt.Sprintf(
-			"<li>%s - Quantity: %d - Price: %.2f</li>",
+			"<li>%s - Quantity: %d - Price: %s</li>",
 			item.Product.Name,
 			item.Quantity,
 			item.Price,
@@ -170,3 +247,32 @@ func (s *EmailService) generateOrderStatusUpdateEmail(
 		order.Customer.User.Phone,
 	)
 }
+
+func (s *EmailService) generateLowStockDigestEmail(
+	alerts []domain.LowStockAlert,
+) string {
+	header := `
+		<h2>Low Stock Alert</h2>
+		<p>The following products are at or below their reorder point:</p>
+		<table>
+		<tr><th>Product</th><th>Stock</th><th>Reorder Point</th><th>Reorder Quantity</th></tr>
+	`
+	rows := ""
+
+	for _, alert := range alerts {
+		rows += fmt.Sprintf(
+			"<tr><td>%s</td><td>%d</td><td>%d</td><td>%d</td></tr>",
+			html.EscapeString(alert.Name),
+			alert.Stock,
+			alert.ReorderPoint,
+			alert.ReorderQuantity,
+		)
+	}
+
+	footer := `
+		</table>
+		<p>Grocery Service</p>
+	`
+
+	return header + rows + footer
+}
@@ -10,6 +10,23 @@ import (
 type NotificationService interface {
 	SendOrderConfirmation(ctx context.Context, order *domain.Order) error
 	SendOrderStatusUpdate(ctx context.Context, order *domain.Order) error
+	// SendLowStockAlert notifies the store admins of products that fell
+	// to their reorder point, as a single digest.
+	SendLowStockAlert(ctx context.Context, alerts []domain.LowStockAlert) error
+}
+
+// AccountMailer emails the single-use links of password sign-in. link
+// carries the token; it is sent to the user's address only.
+type AccountMailer interface {
+	SendEmailVerification(
+		ctx context.Context,
+		user *domain.User,
+		link string,
+	) error
+	SendPasswordReset(ctx context.Context, user *domain.User, link string) error
+	// SendAccountExists tells the user someone tried to sign up with
+	// their address; link resets the password.
+	SendAccountExists(ctx context.Context, user *domain.User, link string) error
 }
 
 type CompositeNotificationService struct {
@@ -55,3 +72,20 @@ func (s *CompositeNotificationService) SendOrderStatusUpdate(
 
 	return lastError
 }
+
+func (s *CompositeNotificationService) SendLowStockAlert(
+	ctx context.Context,
+	alerts []domain.LowStockAlert,
+) error {
+	var lastError error
+
+	for _, service := range s.services {
+		if err := service.SendLowStockAlert(ctx, alerts); err != nil {
+			lastError = errors.LogError(err,
+				"Failed to send low stock alert",
+				errors.ErrCodeEmailSendFailed).Error
+		}
+	}
+
+	return lastError
+}
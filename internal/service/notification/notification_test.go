@@ -21,7 +21,7 @@ func createTestOrder() *domain.Order {
 	customerID := uuid.New()
 	return &domain.Order{
 		ID:         uuid.New(),
-		TotalPrice: 100.50,
+		TotalPrice: domain.NewMoney(10050, domain.DefaultCurrency),
 		Customer: &domain.Customer{
 			ID:     customerID,
 			UserID: userID,
@@ -39,7 +39,7 @@ func createTestOrder() *domain.Order {
 					Name: "Test Product",
 				},
 				Quantity: 2,
-				Price:    50.25,
+				Price:    domain.NewMoney(5025, domain.DefaultCurrency),
 			},
 		},
 	}
@@ -66,6 +66,7 @@ func TestSMSService_SendOrderConfirmation(t *testing.T) {
 				assert.Equal(t, "+1234567890", payload["to"])
 				assert.Contains(t, payload["message"], "John Doe")
 				assert.Contains(t, payload["message"], "123 Test St")
+				assert.Contains(t, payload["message"], "Total: USD 100.50")
 
 				response := map[string]interface{}{
 					"SMSMessageData": map[string]interface{}{
@@ -119,7 +120,7 @@ func TestEmailService_SendOrderConfirmation(t *testing.T) {
 		FromName: "Test Service",
 	}
 
-	service := NewEmailService(config)
+	service := NewEmailService(config, nil)
 	order := createTestOrder()
 
 	err = service.SendOrderConfirmation(context.Background(), order)
@@ -130,6 +131,8 @@ func TestEmailService_SendOrderConfirmation(t *testing.T) {
 	assert.True(t, strings.Contains(lastMsg, "John Doe"))
 	assert.True(t, strings.Contains(lastMsg, "Test Product"))
 	assert.True(t, strings.Contains(lastMsg, "123 Test St"))
+	assert.True(t, strings.Contains(lastMsg, "USD 100.50"))
+	assert.True(t, strings.Contains(lastMsg, "Price: USD 50.25"))
 	assert.True(t, strings.Contains(lastMsg, "+1234567890"))
 }
 
@@ -254,3 +257,128 @@ func (m *mockNotificationService) SendOrderStatusUpdate(
 	}
 	return nil
 }
+
+func (m *mockNotificationService) SendLowStockAlert(
+	_ context.Context,
+	_ []domain.LowStockAlert,
+) error {
+	if m.shouldFail {
+		return fmt.Errorf("mock error")
+	}
+	return nil
+}
+
+func createTestLowStockAlerts() []domain.LowStockAlert {
+	return []domain.LowStockAlert{
+		{
+			ProductID:       uuid.New(),
+			Name:            "Whole Milk",
+			Stock:           3,
+			ReorderPoint:    5,
+			ReorderQuantity: 40,
+		},
+		{
+			ProductID:       uuid.New(),
+			Name:            "Eggs <Free Range>",
+			Stock:           0,
+			ReorderPoint:    12,
+			ReorderQuantity: 120,
+		},
+	}
+}
+
+func TestEmailService_SendLowStockAlert(t *testing.T) {
+	mockSMTP := &mockSMTPServer{t: t}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	defer listener.Close()
+
+	go mockSMTP.Start(listener)
+
+	config := config.SMTPConfig{
+		Host:     "127.0.0.1",
+		Port:     listener.Addr().(*net.TCPAddr).Port,
+		Username: "test@test.com",
+		Password: "password",
+		From:     "noreply@test.com",
+		FromName: "Test Service",
+	}
+
+	service := NewEmailService(config, []string{"admin@test.com"})
+
+	err = service.SendLowStockAlert(
+		context.Background(),
+		createTestLowStockAlerts(),
+	)
+	assert.NoError(t, err)
+
+	lastMsg := mockSMTP.LastMessage()
+	assert.Contains(t, lastMsg, "Low stock: 2 product(s) to reorder")
+	assert.Contains(t, lastMsg, "Whole Milk")
+	assert.Contains(t, lastMsg, "Eggs &lt;Free Range&gt;")
+	assert.Contains(t, lastMsg, "<td>120</td>")
+}
+
+func TestEmailService_SendLowStockAlertWithoutRecipients(t *testing.T) {
+	// No SMTP server is listening, so sending anything would fail.
+	service := NewEmailService(config.SMTPConfig{Host: "127.0.0.1"}, nil)
+
+	err := service.SendLowStockAlert(
+		context.Background(),
+		createTestLowStockAlerts(),
+	)
+	assert.NoError(t, err)
+}
+
+func TestWebhookService_SendLowStockAlert(t *testing.T) {
+	alerts := createTestLowStockAlerts()
+
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(
+					t,
+					"application/json",
+					r.Header.Get("Content-Type"),
+				)
+
+				var payload lowStockWebhookPayload
+				if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+					t.Errorf("failed to decode request body: %v", err)
+					return
+				}
+
+				assert.Equal(t, domain.OutboxEventLowStock, payload.Event)
+				assert.Equal(t, alerts, payload.Products)
+				w.WriteHeader(http.StatusNoContent)
+			},
+		),
+	)
+	defer server.Close()
+
+	service := NewWebhookService(server.URL)
+
+	err := service.SendLowStockAlert(context.Background(), alerts)
+	assert.NoError(t, err)
+}
+
+func TestWebhookService_SendLowStockAlertFailure(t *testing.T) {
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusBadGateway)
+			},
+		),
+	)
+	defer server.Close()
+
+	service := NewWebhookService(server.URL)
+
+	err := service.SendLowStockAlert(
+		context.Background(),
+		createTestLowStockAlerts(),
+	)
+	assert.ErrorContains(t, err, "502")
+}
@@ -30,7 +30,7 @@ func (s *SMSService) SendOrderConfirmation(
 	order *domain.Order,
 ) error {
 	message := fmt.Sprintf(
-		"Hi %s, Order #%s confirmed. Total: %.2f. Delivery to: %s. Thank you for your order!",
+		"Hi %s, Order #%s confirmed. Total: %s. Delivery to: %s. Thank you for your order!",
 		order.Customer.User.Name,
 		order.ID,
 		order.TotalPrice,
@@ -63,6 +63,14 @@ func (s *SMSService) SendOrderStatusUpdate(
 	)
 }
 
+// SendLowStockAlert does nothing; admin alerts are not sent by SMS.
+func (s *SMSService) SendLowStockAlert(
+	_ context.Context,
+	_ []domain.LowStockAlert,
+) error {
+	return nil
+}
+
 func (s *SMSService) sendSMS(
 	ctx context.Context,
 	phone, message string,
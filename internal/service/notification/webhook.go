@@ -0,0 +1,110 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/grocery-service/internal/domain"
+	"github.com/grocery-service/utils/errors"
+)
+
+// WebhookService posts admin alerts as JSON to a webhook URL, such as a
+// chat or incident tool integration. Customer order notifications are
+// not sent to the webhook.
+type WebhookService struct {
+	url    string
+	client *http.Client
+}
+
+type lowStockWebhookPayload struct {
+	Event    domain.OutboxEventType `json:"event"`
+	Products []domain.LowStockAlert `json:"products"`
+	SentAt   time.Time              `json:"sent_at"`
+}
+
+func NewWebhookService(url string) *WebhookService {
+	return &WebhookService{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// SendOrderConfirmation does nothing; the webhook only receives admin
+// alerts.
+func (s *WebhookService) SendOrderConfirmation(
+	_ context.Context,
+	_ *domain.Order,
+) error {
+	return nil
+}
+
+// SendOrderStatusUpdate does nothing; the webhook only receives admin
+// alerts.
+func (s *WebhookService) SendOrderStatusUpdate(
+	_ context.Context,
+	_ *domain.Order,
+) error {
+	return nil
+}
+
+func (s *WebhookService) SendLowStockAlert(
+	ctx context.Context,
+	alerts []domain.LowStockAlert,
+) error {
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	return s.post(ctx, lowStockWebhookPayload{
+		Event:    domain.OutboxEventLowStock,
+		Products: alerts,
+		SentAt:   time.Now().UTC(),
+	})
+}
+
+func (s *WebhookService) post(ctx context.Context, payload any) error {
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return errors.WrapError(
+			err,
+			"failed to marshal webhook payload",
+		)
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		s.url,
+		bytes.NewBuffer(payloadBytes),
+	)
+	if err != nil {
+		return errors.WrapError(
+			err,
+			"failed to create webhook request",
+		)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return errors.WrapError(
+			err,
+			"failed to send webhook request",
+		)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf(
+			"failed to send webhook: received status %s",
+			resp.Status,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,212 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	customErrors "github.com/grocery-service/utils/errors"
+)
+
+// clockSkew is the leeway allowed between our clock and the provider's
+// when checking exp and iat.
+const clockSkew = time.Minute
+
+type (
+	// IDTokenClaims are the verified claims of an id_token.
+	IDTokenClaims struct {
+		Issuer          string   `json:"iss"`
+		Subject         string   `json:"sub"`
+		Audience        audience `json:"aud"`
+		AuthorizedParty string   `json:"azp"`
+		ExpiresAt       int64    `json:"exp"`
+		IssuedAt        int64    `json:"iat"`
+		Nonce           string   `json:"nonce"`
+		Email           string   `json:"email"`
+		EmailVerified   *bool    `json:"email_verified"`
+		Name            string   `json:"name"`
+		Picture         string   `json:"picture"`
+	}
+
+	// audience accepts both forms of the aud claim: a single string or
+	// an array of strings.
+	audience []string
+
+	idTokenHeader struct {
+		Algorithm string `json:"alg"`
+		KeyID     string `json:"kid"`
+	}
+)
+
+func (a *audience) UnmarshalJSON(b []byte) error {
+	var single string
+	if err := json.Unmarshal(b, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+
+	var many []string
+	if err := json.Unmarshal(b, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+// VerifyIDToken checks the signature of an id_token against the
+// provider's published keys, then its issuer, audience, expiry and, when
+// nonce is not empty, that it was issued for this login.
+func (p *Provider) VerifyIDToken(
+	ctx context.Context,
+	rawIDToken string,
+	nonce string,
+) (*IDTokenClaims, error) {
+	discovery, err := p.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.Split(rawIDToken, ".")
+	if len(parts) != 3 {
+		return nil, invalidIDToken("malformed token")
+	}
+
+	var header idTokenHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, invalidIDToken("malformed header")
+	}
+	if len(discovery.SigningAlgorithms) > 0 &&
+		!slices.Contains(discovery.SigningAlgorithms, header.Algorithm) {
+		return nil, invalidIDToken(
+			fmt.Sprintf("algorithm %q not offered by provider", header.Algorithm),
+		)
+	}
+
+	key, err := p.keys.get(ctx, discovery.JWKSURI, header.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, invalidIDToken("malformed signature")
+	}
+	if err := verifySignature(
+		header.Algorithm,
+		key,
+		[]byte(parts[0]+"."+parts[1]),
+		signature,
+	); err != nil {
+		return nil, err
+	}
+
+	var claims IDTokenClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, invalidIDToken("malformed claims")
+	}
+
+	if err := p.validateClaims(&claims, discovery.Issuer, nonce); err != nil {
+		return nil, err
+	}
+
+	return &claims, nil
+}
+
+func (p *Provider) validateClaims(
+	claims *IDTokenClaims,
+	issuer string,
+	nonce string,
+) error {
+	now := time.Now()
+
+	switch {
+	case claims.Issuer != issuer:
+		return invalidIDToken(fmt.Sprintf("issuer %q", claims.Issuer))
+	case claims.Subject == "":
+		return invalidIDToken("missing subject")
+	case !slices.Contains(claims.Audience, p.config.ClientID):
+		return invalidIDToken("audience does not include client")
+	case len(claims.Audience) > 1 &&
+		claims.AuthorizedParty != p.config.ClientID:
+		return invalidIDToken("authorized party is not client")
+	case now.Add(-clockSkew).Unix() >= claims.ExpiresAt:
+		return invalidIDToken("expired")
+	case claims.IssuedAt > now.Add(clockSkew).Unix():
+		return invalidIDToken("issued in the future")
+	case nonce != "" && subtle.ConstantTimeCompare(
+		[]byte(claims.Nonce),
+		[]byte(nonce),
+	) != 1:
+		return invalidIDToken("nonce mismatch")
+	}
+
+	return nil
+}
+
+func verifySignature(
+	algorithm string,
+	key crypto.PublicKey,
+	signed []byte,
+	signature []byte,
+) error {
+	digest := sha256.Sum256(signed)
+
+	switch algorithm {
+	case "RS256":
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return invalidIDToken("key does not match algorithm")
+		}
+		if err := rsa.VerifyPKCS1v15(
+			rsaKey,
+			crypto.SHA256,
+			digest[:],
+			signature,
+		); err != nil {
+			return invalidIDToken("bad signature")
+		}
+
+	case "ES256":
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return invalidIDToken("key does not match algorithm")
+		}
+		if len(signature) != 64 {
+			return invalidIDToken("bad signature")
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(ecKey, digest[:], r, s) {
+			return invalidIDToken("bad signature")
+		}
+
+	default:
+		return invalidIDToken(
+			fmt.Sprintf("unsupported algorithm %q", algorithm),
+		)
+	}
+
+	return nil
+}
+
+func invalidIDToken(reason string) error {
+	return fmt.Errorf("%w: %s", customErrors.ErrInvalidIDToken, reason)
+}
+
+func decodeSegment(segment string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
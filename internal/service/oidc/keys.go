@@ -0,0 +1,185 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	customErrors "github.com/grocery-service/utils/errors"
+)
+
+const (
+	// jwksTTL is how long fetched signing keys are trusted before the
+	// JWKS is fetched again.
+	jwksTTL = time.Hour
+	// jwksMinRefresh limits refetches triggered by an unknown kid, so
+	// forged tokens cannot make us hammer the provider.
+	jwksMinRefresh = time.Minute
+)
+
+type (
+	jwk struct {
+		KeyType string `json:"kty"`
+		KeyID   string `json:"kid"`
+		Use     string `json:"use"`
+		N       string `json:"n"`
+		E       string `json:"e"`
+		Curve   string `json:"crv"`
+		X       string `json:"x"`
+		Y       string `json:"y"`
+	}
+
+	jwkSet struct {
+		Keys []jwk `json:"keys"`
+	}
+
+	// keyCache holds a provider's signing keys by kid. Keys are refetched
+	// when they are older than jwksTTL or a token names a kid the cache
+	// does not know, which is how provider key rotation is picked up.
+	keyCache struct {
+		client *http.Client
+
+		mu        sync.Mutex
+		keys      map[string]crypto.PublicKey
+		fetchedAt time.Time
+	}
+)
+
+func newKeyCache(client *http.Client) *keyCache {
+	return &keyCache{client: client}
+}
+
+func (c *keyCache) get(
+	ctx context.Context,
+	uri string,
+	kid string,
+) (crypto.PublicKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key, ok := c.lookup(kid)
+	age := time.Since(c.fetchedAt)
+	if (ok && age < jwksTTL) ||
+		(!c.fetchedAt.IsZero() && age < jwksMinRefresh) {
+		if !ok {
+			return nil, fmt.Errorf(
+				"%w: unknown signing key %q",
+				customErrors.ErrInvalidIDToken,
+				kid,
+			)
+		}
+		return key, nil
+	}
+
+	keys, err := c.fetch(ctx, uri)
+	if err != nil {
+		if ok {
+			// Keep verifying with the stale key while the provider
+			// is unreachable.
+			return key, nil
+		}
+		return nil, err
+	}
+	c.keys = keys
+	c.fetchedAt = time.Now()
+
+	if key, ok = c.lookup(kid); !ok {
+		return nil, fmt.Errorf(
+			"%w: unknown signing key %q",
+			customErrors.ErrInvalidIDToken,
+			kid,
+		)
+	}
+	return key, nil
+}
+
+func (c *keyCache) lookup(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(c.keys) == 1 {
+		for _, key := range c.keys {
+			return key, true
+		}
+	}
+	key, ok := c.keys[kid]
+	return key, ok
+}
+
+func (c *keyCache) fetch(
+	ctx context.Context,
+	uri string,
+) (map[string]crypto.PublicKey, error) {
+	var set jwkSet
+	if err := getJSON(ctx, c.client, uri, &set); err != nil {
+		return nil, fmt.Errorf("failed to fetch signing keys: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		key, err := k.publicKey()
+		if err != nil {
+			// Skip key types we cannot verify with rather than
+			// failing every login.
+			continue
+		}
+		keys[k.KeyID] = key
+	}
+	return keys, nil
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.KeyType {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		if k.Curve != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Curve)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}
+		if !key.Curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("invalid EC key")
+		}
+		return key, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.KeyType)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
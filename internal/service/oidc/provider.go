@@ -0,0 +1,238 @@
+// Package oidc signs users in with OpenID Connect providers. Each
+// provider's endpoints are discovered from its issuer, its signing keys
+// are fetched and cached from the advertised JWKS, and id_tokens are
+// verified locally before their claims are trusted.
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/grocery-service/internal/config"
+	"github.com/grocery-service/internal/domain"
+	"golang.org/x/oauth2"
+)
+
+const discoveryPath = "/.well-known/openid-configuration"
+
+type (
+	// Discovery is the subset of the provider metadata document used to
+	// sign users in.
+	Discovery struct {
+		Issuer                string   `json:"issuer"`
+		AuthorizationEndpoint string   `json:"authorization_endpoint"`
+		TokenEndpoint         string   `json:"token_endpoint"`
+		UserInfoEndpoint      string   `json:"userinfo_endpoint"`
+		JWKSURI               string   `json:"jwks_uri"`
+		SigningAlgorithms     []string `json:"id_token_signing_alg_values_supported"`
+	}
+
+	// Provider is a configured OpenID Connect provider. Its metadata is
+	// discovered on first use and kept for the life of the process.
+	Provider struct {
+		config      config.OIDCProviderConfig
+		redirectURL string
+		client      *http.Client
+		keys        *keyCache
+
+		mu        sync.Mutex
+		discovery *Discovery
+		oauth2    *oauth2.Config
+	}
+)
+
+func NewProvider(
+	cfg config.OIDCProviderConfig,
+	redirectURL string,
+	client *http.Client,
+) *Provider {
+	return &Provider{
+		config:      cfg,
+		redirectURL: redirectURL,
+		client:      client,
+		keys:        newKeyCache(client),
+	}
+}
+
+// Name is the configured provider name, recorded as tokens.provider.
+func (p *Provider) Name() string {
+	return p.config.Name
+}
+
+// Discover fetches the provider metadata, or returns it from cache.
+func (p *Provider) Discover(ctx context.Context) (*Discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	url := strings.TrimSuffix(p.config.IssuerURL, "/") + discoveryPath
+	var discovery Discovery
+	if err := getJSON(ctx, p.client, url, &discovery); err != nil {
+		return nil, fmt.Errorf(
+			"failed to discover provider %s: %w",
+			p.config.Name,
+			err,
+		)
+	}
+
+	if strings.TrimSuffix(discovery.Issuer, "/") !=
+		strings.TrimSuffix(p.config.IssuerURL, "/") {
+		return nil, fmt.Errorf(
+			"failed to discover provider %s: issuer %q does not match %q",
+			p.config.Name,
+			discovery.Issuer,
+			p.config.IssuerURL,
+		)
+	}
+
+	if discovery.AuthorizationEndpoint == "" ||
+		discovery.TokenEndpoint == "" ||
+		discovery.JWKSURI == "" {
+		return nil, fmt.Errorf(
+			"failed to discover provider %s: incomplete metadata",
+			p.config.Name,
+		)
+	}
+
+	p.discovery = &discovery
+	p.oauth2 = &oauth2.Config{
+		ClientID:     p.config.ClientID,
+		ClientSecret: p.config.ClientSecret,
+		RedirectURL:  p.redirectURL,
+		Scopes:       p.config.Scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  discovery.AuthorizationEndpoint,
+			TokenURL: discovery.TokenEndpoint,
+		},
+	}
+
+	return p.discovery, nil
+}
+
+func (p *Provider) oauth2Config(ctx context.Context) (*oauth2.Config, error) {
+	if _, err := p.Discover(ctx); err != nil {
+		return nil, err
+	}
+	return p.oauth2, nil
+}
+
+// AuthCodeURL returns the provider's login page URL for an
+// authorization code flow bound to state and nonce.
+func (p *Provider) AuthCodeURL(
+	ctx context.Context,
+	state string,
+	nonce string,
+	opts ...oauth2.AuthCodeOption,
+) (string, error) {
+	cfg, err := p.oauth2Config(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	opts = append(opts, oauth2.SetAuthURLParam("nonce", nonce))
+	return cfg.AuthCodeURL(state, opts...), nil
+}
+
+func (p *Provider) Exchange(
+	ctx context.Context,
+	code string,
+	opts ...oauth2.AuthCodeOption,
+) (*oauth2.Token, error) {
+	cfg, err := p.oauth2Config(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := cfg.Exchange(p.clientContext(ctx), code, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange token: %w", err)
+	}
+	return token, nil
+}
+
+// UserInfo fetches the profile of the user the access token was issued
+// to from the provider's userinfo endpoint.
+func (p *Provider) UserInfo(
+	ctx context.Context,
+	accessToken string,
+) (*domain.UserInfo, error) {
+	discovery, err := p.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if discovery.UserInfoEndpoint == "" {
+		return nil, fmt.Errorf(
+			"failed to fetch user info: provider %s has no userinfo endpoint",
+			p.config.Name,
+		)
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		discovery.UserInfoEndpoint,
+		nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch user info: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf(
+			"failed to fetch user info: %d",
+			resp.StatusCode,
+		)
+	}
+
+	var userInfo domain.UserInfo
+	if err := json.NewDecoder(resp.Body).Decode(&userInfo); err != nil {
+		return nil, fmt.Errorf("failed to decode user info: %w", err)
+	}
+
+	return &userInfo, nil
+}
+
+// clientContext makes the oauth2 package use the provider's HTTP client.
+func (p *Provider) clientContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, oauth2.HTTPClient, p.client)
+}
+
+func getJSON(
+	ctx context.Context,
+	client *http.Client,
+	url string,
+	v any,
+) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: received status %s", url, resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,225 @@
+package oidc
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grocery-service/internal/config"
+	"github.com/grocery-service/tests/oidctest"
+	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestProvider(t *testing.T) (*oidctest.Provider, *Provider) {
+	fake := oidctest.New(t, "test-client-id")
+	provider := NewProvider(
+		config.OIDCProviderConfig{
+			Name:         "keycloak",
+			IssuerURL:    fake.Issuer(),
+			ClientID:     "test-client-id",
+			ClientSecret: "test-secret",
+			Scopes:       []string{"openid", "email"},
+		},
+		"http://localhost:8080/api/v1/auth/callback",
+		http.DefaultClient,
+	)
+	return fake, provider
+}
+
+func TestProvider_AuthCodeURL(t *testing.T) {
+	fake, provider := newTestProvider(t)
+
+	url, err := provider.AuthCodeURL(context.Background(), "state", "n-0S6")
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(url, fake.URL+"/authorize?"))
+	assert.Contains(t, url, "client_id=test-client-id")
+	assert.Contains(t, url, "nonce=n-0S6")
+	assert.Contains(t, url, "scope=openid+email")
+}
+
+func TestProvider_DiscoverRejectsIssuerMismatch(t *testing.T) {
+	fake := oidctest.New(t, "test-client-id")
+	provider := NewProvider(
+		config.OIDCProviderConfig{
+			Name:      "keycloak",
+			IssuerURL: fake.URL + "/realms/other",
+			ClientID:  "test-client-id",
+		},
+		"",
+		http.DefaultClient,
+	)
+
+	_, err := provider.Discover(context.Background())
+	assert.Error(t, err)
+}
+
+func TestProvider_VerifyIDToken(t *testing.T) {
+	fake, provider := newTestProvider(t)
+	ctx := context.Background()
+
+	with := func(changes map[string]any) map[string]any {
+		claims := fake.Claims("user-1", "user@example.com", "nonce-1")
+		for k, v := range changes {
+			claims[k] = v
+		}
+		return claims
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		nonce   string
+		wantErr bool
+	}{
+		{
+			name:  "valid",
+			token: fake.Sign(t, with(nil)),
+			nonce: "nonce-1",
+		},
+		{
+			name:  "nonce not checked on refresh",
+			token: fake.Sign(t, with(map[string]any{"nonce": nil})),
+			nonce: "",
+		},
+		{
+			name:    "nonce mismatch",
+			token:   fake.Sign(t, with(nil)),
+			nonce:   "nonce-2",
+			wantErr: true,
+		},
+		{
+			name:    "other audience",
+			token:   fake.Sign(t, with(map[string]any{"aud": "other-client"})),
+			nonce:   "nonce-1",
+			wantErr: true,
+		},
+		{
+			name: "several audiences with client as authorized party",
+			token: fake.Sign(t, with(map[string]any{
+				"aud": []string{"test-client-id", "api"},
+				"azp": "test-client-id",
+			})),
+			nonce: "nonce-1",
+		},
+		{
+			name: "several audiences without authorized party",
+			token: fake.Sign(t, with(map[string]any{
+				"aud": []string{"test-client-id", "api"},
+			})),
+			nonce:   "nonce-1",
+			wantErr: true,
+		},
+		{
+			name: "expired",
+			token: fake.Sign(t, with(map[string]any{
+				"exp": time.Now().Add(-2 * clockSkew).Unix(),
+			})),
+			nonce:   "nonce-1",
+			wantErr: true,
+		},
+		{
+			name: "other issuer",
+			token: fake.Sign(t, with(map[string]any{
+				"iss": "https://evil.example.com",
+			})),
+			nonce:   "nonce-1",
+			wantErr: true,
+		},
+		{
+			name: "tampered claims",
+			token: func() string {
+				parts := strings.Split(fake.Sign(t, with(nil)), ".")
+				parts[1] = base64.RawURLEncoding.EncodeToString(
+					[]byte(`{"sub":"admin"}`),
+				)
+				return strings.Join(parts, ".")
+			}(),
+			nonce:   "nonce-1",
+			wantErr: true,
+		},
+		{
+			name: "unsigned",
+			token: base64.RawURLEncoding.EncodeToString(
+				[]byte(`{"alg":"none"}`),
+			) + "." + strings.Split(fake.Sign(t, with(nil)), ".")[1] + ".",
+			nonce:   "nonce-1",
+			wantErr: true,
+		},
+		{
+			name:    "malformed",
+			token:   "not-a-jwt",
+			nonce:   "nonce-1",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := provider.VerifyIDToken(ctx, tt.token, tt.nonce)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, customErrors.ErrInvalidIDToken)
+				assert.Nil(t, claims)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "user-1", claims.Subject)
+				assert.Equal(t, "user@example.com", claims.Email)
+			}
+		})
+	}
+}
+
+func TestProvider_VerifyIDTokenCachesAndRotatesKeys(t *testing.T) {
+	fake, provider := newTestProvider(t)
+	ctx := context.Background()
+	claims := fake.Claims("user-1", "user@example.com", "")
+
+	_, err := provider.VerifyIDToken(ctx, fake.Sign(t, claims), "")
+	require.NoError(t, err)
+	_, err = provider.VerifyIDToken(ctx, fake.Sign(t, claims), "")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), fake.JWKSRequests.Load())
+
+	// A token under a new kid right after a fetch is rejected without
+	// asking the provider again.
+	fake.RotateKey(t)
+	rotated := fake.Sign(t, claims)
+	_, err = provider.VerifyIDToken(ctx, rotated, "")
+	assert.ErrorIs(t, err, customErrors.ErrInvalidIDToken)
+	assert.Equal(t, int32(1), fake.JWKSRequests.Load())
+
+	// Once the refresh interval has passed the new key is fetched.
+	provider.keys.fetchedAt = time.Now().Add(-2 * jwksMinRefresh)
+	_, err = provider.VerifyIDToken(ctx, rotated, "")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), fake.JWKSRequests.Load())
+}
+
+func TestRegistry_Get(t *testing.T) {
+	registry := NewRegistry(config.OAuthConfig{
+		DefaultProvider: "keycloak",
+		Providers: []config.OIDCProviderConfig{
+			{Name: "google", IssuerURL: "https://accounts.google.com"},
+			{Name: "keycloak", IssuerURL: "https://sso.example.com/realms/shop"},
+		},
+	}, http.DefaultClient)
+
+	p, err := registry.Get("")
+	require.NoError(t, err)
+	assert.Equal(t, "keycloak", p.Name())
+
+	p, err = registry.Get("google")
+	require.NoError(t, err)
+	assert.Equal(t, "google", p.Name())
+
+	_, err = registry.Get("auth0")
+	assert.ErrorIs(t, err, customErrors.ErrUnknownProvider)
+
+	assert.Equal(t, []string{"google", "keycloak"}, registry.Names())
+}
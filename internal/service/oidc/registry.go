@@ -0,0 +1,53 @@
+package oidc
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/grocery-service/internal/config"
+	customErrors "github.com/grocery-service/utils/errors"
+)
+
+// Registry holds the configured providers by name.
+type Registry struct {
+	providers   map[string]*Provider
+	names       []string
+	defaultName string
+}
+
+func NewRegistry(cfg config.OAuthConfig, client *http.Client) *Registry {
+	r := &Registry{
+		providers:   make(map[string]*Provider, len(cfg.Providers)),
+		defaultName: cfg.DefaultProvider,
+	}
+
+	for _, p := range cfg.Providers {
+		r.providers[p.Name] = NewProvider(p, cfg.RedirectURL, client)
+		r.names = append(r.names, p.Name)
+	}
+
+	if r.defaultName == "" && len(r.names) > 0 {
+		r.defaultName = r.names[0]
+	}
+
+	return r
+}
+
+// Get returns the named provider, or the default provider when name is
+// empty.
+func (r *Registry) Get(name string) (*Provider, error) {
+	if name == "" {
+		name = r.defaultName
+	}
+
+	p, ok := r.providers[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", customErrors.ErrUnknownProvider, name)
+	}
+	return p, nil
+}
+
+// Names lists the configured providers in configuration order.
+func (r *Registry) Names() []string {
+	return r.names
+}
@@ -2,30 +2,58 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 
 	"github.com/google/uuid"
+	"github.com/grocery-service/internal/config"
 	"github.com/grocery-service/internal/domain"
 	repository "github.com/grocery-service/internal/repository/postgres"
-	"github.com/grocery-service/internal/service/notification"
 	customErrors "github.com/grocery-service/utils/errors"
 )
 
 type (
 	OrderService interface {
 		Create(ctx context.Context, order *domain.Order) error
+		Checkout(
+			ctx context.Context,
+			order *domain.Order,
+			cartID string,
+		) error
 		GetByID(ctx context.Context, id string) (*domain.Order, error)
-		List(ctx context.Context) ([]domain.Order, error)
+		List(
+			ctx context.Context,
+			query domain.ListQuery,
+		) (*domain.Page[domain.Order], error)
 		ListByCustomerID(
 			ctx context.Context,
 			customerID string,
-		) ([]domain.Order, error)
+			query domain.ListQuery,
+		) (*domain.Page[domain.Order], error)
 		Update(ctx context.Context, order *domain.Order) error
+		// UpdateStatus moves the order to status and records the change
+		// in its history. actorID is the acting user's ID, empty for
+		// system changes. Orders are refunded through Refund only.
 		UpdateStatus(
 			ctx context.Context,
 			id string,
 			status domain.OrderStatus,
+			actorID, reason string,
+		) error
+		// Refund moves the order to REFUNDED. For delivered orders each
+		// line restocks or discards its goods as decided by lines; items
+		// without a line are discarded.
+		Refund(
+			ctx context.Context,
+			id string,
+			actorID, reason string,
+			lines []domain.RefundLine,
 		) error
+		GetStatusHistory(
+			ctx context.Context,
+			id string,
+		) ([]domain.OrderStatusChange, error)
 		AddOrderItem(
 			ctx context.Context,
 			orderID string,
@@ -38,7 +66,7 @@ type (
 		repo         repository.OrderRepository
 		productRepo  repository.ProductRepository
 		customerRepo repository.CustomerRepository
-		notifier     notification.NotificationService
+		machine      *domain.OrderStateMachine
 	}
 )
 
@@ -46,14 +74,82 @@ func NewOrderService(
 	repo repository.OrderRepository,
 	productRepo repository.ProductRepository,
 	customerRepo repository.CustomerRepository,
-	notifier notification.NotificationService,
+	machine *domain.OrderStateMachine,
 ) OrderService {
+	machine.OnEnter(domain.OrderStatusCancelled, restockUnshippedOrder)
+	machine.OnEnter(domain.OrderStatusFailed, restockUnshippedOrder)
+	machine.OnEnter(domain.OrderStatusRefunded, restockRefundLines)
+
 	return &OrderServiceImpl{
 		repo:         repo,
 		productRepo:  productRepo,
 		customerRepo: customerRepo,
-		notifier:     notifier,
+		machine:      machine,
+	}
+}
+
+// restockUnshippedOrder returns every item of an order that is cancelled
+// or fails before it ships. Goods that already left the store are not
+// restocked.
+func restockUnshippedOrder(
+	_ context.Context,
+	t *domain.OrderTransition,
+) error {
+	if t.From.IsShipped() {
+		return nil
+	}
+
+	for _, item := range t.Order.Items {
+		t.Restock = append(t.Restock, domain.StockAdjustment{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		})
+	}
+	return nil
+}
+
+// restockRefundLines returns the items of a refunded order whose refund
+// line asks for a restock.
+func restockRefundLines(
+	_ context.Context,
+	t *domain.OrderTransition,
+) error {
+	restock := make(map[uuid.UUID]bool, len(t.RefundLines))
+	for _, line := range t.RefundLines {
+		restock[line.ItemID] = line.Action == domain.RefundActionRestock
 	}
+
+	for _, item := range t.Order.Items {
+		if restock[item.ID] {
+			t.Restock = append(t.Restock, domain.StockAdjustment{
+				ProductID: item.ProductID,
+				Quantity:  item.Quantity,
+			})
+		}
+	}
+	return nil
+}
+
+// LoadOrderStateMachine builds the order state machine from the
+// transitions file named in cfg, or from the default transitions when
+// none is configured.
+func LoadOrderStateMachine(
+	cfg config.OrderConfig,
+) (*domain.OrderStateMachine, error) {
+	if cfg.TransitionsFile == "" {
+		return domain.NewDefaultOrderStateMachine(), nil
+	}
+
+	data, err := os.ReadFile(cfg.TransitionsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read order transitions: %w", err)
+	}
+
+	transitions, err := domain.ParseOrderTransitions(data)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewOrderStateMachine(transitions)
 }
 
 func (s *OrderServiceImpl) Create(
@@ -72,8 +168,8 @@ func (s *OrderServiceImpl) Create(
 		return fmt.Errorf("invalid customer: %w", err)
 	}
 
-	var totalPrice float64
-	for _, item := range order.Items {
+	totalPrice := domain.NewMoney(0, domain.DefaultCurrency)
+	for i, item := range order.Items {
 		product, err := s.productRepo.GetByID(
 			ctx,
 			item.ProductID.String(),
@@ -82,52 +178,72 @@ func (s *OrderServiceImpl) Create(
 			return fmt.Errorf("failed to get product: %w", err)
 		}
 
-		if product.Stock < item.Quantity {
-			return fmt.Errorf(
-				"%w: product %s - requested %d, available %d",
-				customErrors.ErrInsufficientStock,
-				product.Name,
-				item.Quantity,
-				product.Stock,
-			)
-		}
-
-		totalPrice += product.Price * float64(item.Quantity)
+		order.Items[i].Price = product.Price
+		totalPrice = totalPrice.Add(product.Price.Mul(item.Quantity))
 	}
 
 	order.TotalPrice = totalPrice
 	order.Status = domain.OrderStatusPending
 	if order.ID == uuid.Nil {
 		order.ID = uuid.New()
-	}	
+	}
 
-	err := s.repo.Create(
-		ctx,
+	event, err := domain.NewOrderEvent(
+		domain.OutboxEventOrderConfirmation,
 		order,
-		func(ctx context.Context, productID string, quantity int) error {
-			product, err := s.productRepo.GetByID(ctx, productID)
-			if err != nil {
-				return err
-			}
-
-			newStock := product.Stock - quantity
-			return s.productRepo.UpdateStock(ctx, productID, newStock)
-		},
 	)
 	if err != nil {
 		return err
 	}
 
-	go func() {
-		if err := s.notifier.SendOrderConfirmation(context.Background(), order); err != nil {
-			fmt.Printf(
-				"failed to send order confirmation: %v\n",
-				err,
-			)
-		}
-	}()
+	// Stock is checked and decremented atomically inside the order
+	// transaction; an *InsufficientStockError is returned on shortfall.
+	// The confirmation is recorded in the same transaction and sent by
+	// the outbox dispatcher.
+	return s.repo.Create(ctx, order, event)
+}
 
-	return nil
+// Checkout places order at the item prices it already carries, the ones
+// the customer accepted, and empties the cart identified by cartID in the
+// same transaction. If a product's price moved since, nothing is written
+// and ErrOrderPriceChanged is returned.
+func (s *OrderServiceImpl) Checkout(
+	ctx context.Context,
+	order *domain.Order,
+	cartID string,
+) error {
+	if err := order.Validate(); err != nil {
+		return fmt.Errorf(
+			"%w: %v",
+			customErrors.ErrInvalidOrderData,
+			err,
+		)
+	}
+
+	if _, err := s.customerRepo.GetByID(ctx, order.CustomerID.String()); err != nil {
+		return fmt.Errorf("invalid customer: %w", err)
+	}
+
+	totalPrice := domain.NewMoney(0, domain.DefaultCurrency)
+	for _, item := range order.Items {
+		totalPrice = totalPrice.Add(item.Price.Mul(item.Quantity))
+	}
+
+	order.TotalPrice = totalPrice
+	order.Status = domain.OrderStatusPending
+	if order.ID == uuid.Nil {
+		order.ID = uuid.New()
+	}
+
+	event, err := domain.NewOrderEvent(
+		domain.OutboxEventOrderConfirmation,
+		order,
+	)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Checkout(ctx, order, cartID, event)
 }
 
 func (s *OrderServiceImpl) GetByID(
@@ -145,21 +261,23 @@ func (s *OrderServiceImpl) GetByID(
 
 func (s *OrderServiceImpl) List(
 	ctx context.Context,
-) ([]domain.Order, error) {
-	return s.repo.List(ctx)
+	query domain.ListQuery,
+) (*domain.Page[domain.Order], error) {
+	return s.repo.List(ctx, query)
 }
 
 func (s *OrderServiceImpl) ListByCustomerID(
 	ctx context.Context,
 	customerID string,
-) ([]domain.Order, error) {
+	query domain.ListQuery,
+) (*domain.Page[domain.Order], error) {
 	if customerID == "" {
 		return nil, fmt.Errorf(
 			"%w: customer ID is required",
 			customErrors.ErrInvalidOrderData,
 		)
 	}
-	return s.repo.ListByCustomerID(ctx, customerID)
+	return s.repo.ListByCustomerID(ctx, customerID, query)
 }
 
 func (s *OrderServiceImpl) Update(
@@ -199,6 +317,7 @@ func (s *OrderServiceImpl) UpdateStatus(
 	ctx context.Context,
 	id string,
 	status domain.OrderStatus,
+	actorID, reason string,
 ) error {
 	if id == "" {
 		return fmt.Errorf(
@@ -215,40 +334,137 @@ func (s *OrderServiceImpl) UpdateStatus(
 		)
 	}
 
+	// Refunds decide what happens to the goods, so they have their own
+	// endpoint and permission.
+	if status == domain.OrderStatusRefunded {
+		return fmt.Errorf(
+			"%w: orders are refunded through the refund endpoint",
+			customErrors.ErrOrderStatusInvalid,
+		)
+	}
+
 	order, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return fmt.Errorf("failed to get order: %w", err)
 	}
 
-	if !domain.IsValidStatusTransition(
-		order.Status,
-		status,
-	) {
+	return s.transition(ctx, order, status, actorID, reason, nil)
+}
+
+func (s *OrderServiceImpl) Refund(
+	ctx context.Context,
+	id string,
+	actorID, reason string,
+	lines []domain.RefundLine,
+) error {
+	if id == "" {
 		return fmt.Errorf(
-			"%w: invalid transition from %s to %s",
-			customErrors.ErrOrderStatusInvalid,
-			order.Status,
-			status,
+			"%w: order ID is required",
+			customErrors.ErrInvalidOrderData,
 		)
 	}
 
-	err = s.repo.UpdateStatus(ctx, id, status)
+	order, err := s.repo.GetByID(ctx, id)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to get order: %w", err)
+	}
+
+	if len(lines) > 0 && order.Status != domain.OrderStatusDelivered {
+		return fmt.Errorf(
+			"%w: only delivered orders can restock refunded items",
+			customErrors.ErrInvalidOrderData,
+		)
 	}
 
-	order.Status = status
+	if err := order.ValidateRefundLines(lines); err != nil {
+		return fmt.Errorf(
+			"%w: %v",
+			customErrors.ErrInvalidOrderData,
+			err,
+		)
+	}
+
+	return s.transition(
+		ctx,
+		order,
+		domain.OrderStatusRefunded,
+		actorID,
+		reason,
+		lines,
+	)
+}
+
+// transition moves order to status through the state machine and stores
+// the result, including whatever the transition hooks attached.
+func (s *OrderServiceImpl) transition(
+	ctx context.Context,
+	order *domain.Order,
+	status domain.OrderStatus,
+	actorID, reason string,
+	lines []domain.RefundLine,
+) error {
+	change, err := domain.NewOrderStatusChange(
+		order,
+		status,
+		actorID,
+		reason,
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"%w: %v",
+			customErrors.ErrOrderStatusInvalid,
+			err,
+		)
+	}
 
-	go func() {
-		if err := s.notifier.SendOrderStatusUpdate(context.Background(), order); err != nil {
-			fmt.Printf(
-				"failed to send order status update: %v\n",
+	transition := &domain.OrderTransition{
+		Order:       order,
+		From:        order.Status,
+		To:          status,
+		Change:      change,
+		RefundLines: lines,
+	}
+	if err := s.machine.Transition(ctx, transition); err != nil {
+		if errors.Is(err, domain.ErrTransitionRejected) {
+			return fmt.Errorf(
+				"%w: %v",
+				customErrors.ErrOrderStatusInvalid,
 				err,
 			)
 		}
-	}()
+		return fmt.Errorf("failed to apply status transition: %w", err)
+	}
 
-	return nil
+	event, err := domain.NewOrderEvent(
+		domain.OutboxEventOrderStatusUpdate,
+		order,
+	)
+	if err != nil {
+		return err
+	}
+	transition.Events = append(transition.Events, event)
+
+	// Stock returned by the hooks is restored in the same transaction as
+	// the status change.
+	return s.repo.UpdateStatus(ctx, transition)
+}
+
+func (s *OrderServiceImpl) GetStatusHistory(
+	ctx context.Context,
+	id string,
+) ([]domain.OrderStatusChange, error) {
+	if id == "" {
+		return nil, fmt.Errorf(
+			"%w: order ID is required",
+			customErrors.ErrInvalidOrderData,
+		)
+	}
+
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListStatusHistory(ctx, id)
 }
 
 func (s *OrderServiceImpl) AddOrderItem(
@@ -284,35 +500,10 @@ func (s *OrderServiceImpl) AddOrderItem(
 		return fmt.Errorf("failed to get product: %w", err)
 	}
 
-	if product.Stock < item.Quantity {
-		return fmt.Errorf(
-			"%w: product %s - requested %d, available %d",
-			customErrors.ErrInsufficientStock,
-			product.Name,
-			item.Quantity,
-			product.Stock,
-		)
-	}
-
+	// Stock is checked and reserved under a row lock inside the order
+	// transaction; an *InsufficientStockError is returned on shortfall.
 	item.Price = product.Price
-	item.OrderID = uuid.MustParse(orderID)
-
-	return s.repo.AddOrderItem(
-		ctx,
-		orderID,
-		item,
-		func(ctx context.Context, productID string, newStock int) error {
-			return s.productRepo.UpdateStock(
-				ctx,
-				productID,
-				newStock,
-			)
-		},
-		func(ctx context.Context, order *domain.Order, price float64) error {
-			order.TotalPrice += price
-			return s.repo.Update(ctx, order)
-		},
-	)
+	return s.repo.AddOrderItem(ctx, orderID, item)
 }
 
 func (s *OrderServiceImpl) RemoveOrderItem(
@@ -337,22 +528,5 @@ func (s *OrderServiceImpl) RemoveOrderItem(
 		)
 	}
 
-	return s.repo.RemoveOrderItem(
-		ctx,
-		orderID,
-		itemID,
-		func(ctx context.Context, productID string, quantity int) error {
-			product, err := s.productRepo.GetByID(ctx, productID)
-			if err != nil {
-				return err
-			}
-
-			newStock := product.Stock + quantity
-			return s.productRepo.UpdateStock(ctx, productID, newStock)
-		},
-		func(ctx context.Context, order *domain.Order, price float64) error {
-			order.TotalPrice += price
-			return s.repo.Update(ctx, order)
-		},
-	)
+	return s.repo.RemoveOrderItem(ctx, orderID, itemID)
 }
@@ -2,13 +2,13 @@ package service
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
-	"time"
 
 	"github.com/google/uuid"
 	"github.com/grocery-service/internal/domain"
 	repoMocks "github.com/grocery-service/tests/mocks/repository"
-	serviceMock "github.com/grocery-service/tests/mocks/service"
 	customErrors "github.com/grocery-service/utils/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -21,20 +21,18 @@ func setupOrderTest(
 	*repoMocks.OrderRepository,
 	*repoMocks.ProductRepository,
 	*repoMocks.CustomerRepository,
-	*serviceMock.NotificationService,
 ) {
 	orderRepo := repoMocks.NewOrderRepository(t)
 	productRepo := repoMocks.NewProductRepository(t)
 	customerRepo := repoMocks.NewCustomerRepository(t)
-	notifier := serviceMock.NewNotificationService(t)
 	service := NewOrderService(
 		orderRepo,
 		productRepo,
 		customerRepo,
-		notifier,
+		domain.NewDefaultOrderStateMachine(),
 	)
 
-	return service, orderRepo, productRepo, customerRepo, notifier
+	return service, orderRepo, productRepo, customerRepo
 }
 
 func createTestCustomer() (*domain.Customer, *domain.User) {
@@ -63,7 +61,7 @@ func createTestProduct() *domain.Product {
 		ID:          uuid.New(),
 		Name:        "Test Product",
 		Description: "Test Description",
-		Price:       10.0,
+		Price:       domain.NewMoney(1000, domain.DefaultCurrency),
 		Stock:       5,
 	}
 }
@@ -78,7 +76,7 @@ func createTestOrder(customerID uuid.UUID) *domain.Order {
 				ID:        uuid.New(),
 				ProductID: uuid.New(),
 				Quantity:  2,
-				Price:     10.0,
+				Price:     domain.NewMoney(1000, domain.DefaultCurrency),
 			},
 		},
 	}
@@ -92,7 +90,6 @@ func TestOrderService_Create(t *testing.T) {
 			or *repoMocks.OrderRepository,
 			pr *repoMocks.ProductRepository,
 			cr *repoMocks.CustomerRepository,
-			ns *serviceMock.NotificationService,
 			order *domain.Order,
 		)
 		expectedError error
@@ -111,7 +108,6 @@ func TestOrderService_Create(t *testing.T) {
 				or *repoMocks.OrderRepository,
 				pr *repoMocks.ProductRepository,
 				cr *repoMocks.CustomerRepository,
-				ns *serviceMock.NotificationService,
 				order *domain.Order,
 			) {
 				customer := &domain.Customer{
@@ -119,7 +115,7 @@ func TestOrderService_Create(t *testing.T) {
 				}
 				product := &domain.Product{
 					ID:    order.Items[0].ProductID,
-					Price: 10.0,
+					Price: domain.NewMoney(1000, domain.DefaultCurrency),
 					Stock: 5,
 				}
 
@@ -128,36 +124,17 @@ func TestOrderService_Create(t *testing.T) {
 				pr.On("GetByID", mock.Anything, order.Items[0].ProductID.String()).
 					Return(product, nil)
 
-				// Update stock expectation
-				pr.On(
-					"UpdateStock",
-					mock.Anything,
-					order.Items[0].ProductID.String(),
-					product.Stock-order.Items[0].Quantity).Return(nil)
-
 				or.On("Create", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
 					return o.CustomerID == order.CustomerID &&
-						o.Status == domain.OrderStatusPending
-				}), mock.AnythingOfType("func(context.Context, string, int) error")).
-					Run(func(args mock.Arguments) {
-						callback := args.Get(2).(func(context.Context, string, int) error)
-						err := callback(
-							context.Background(),
-							order.Items[0].ProductID.String(),
-							order.Items[0].Quantity)
-						if err != nil {
-							t.Errorf("callback error: %v", err)
-						}
-					}).
-					Return(nil)
-
-				ns.On(
-					"SendOrderConfirmation",
-					mock.Anything,
-					mock.MatchedBy(func(o *domain.Order) bool {
-						return o.CustomerID == order.CustomerID &&
-							o.Status == domain.OrderStatusPending
-					})).Return(nil)
+						o.Status == domain.OrderStatusPending &&
+						o.Items[0].Price == product.Price
+				}), mock.MatchedBy(func(e *domain.OutboxEvent) bool {
+					payload, err := e.OrderPayload()
+					return err == nil &&
+						e.EventType == domain.OutboxEventOrderConfirmation &&
+						e.Status == domain.OutboxStatusPending &&
+						payload.OrderID == order.ID
+				})).Return(nil)
 			},
 			expectedError: nil,
 		},
@@ -170,7 +147,6 @@ func TestOrderService_Create(t *testing.T) {
 				_ *repoMocks.OrderRepository,
 				_ *repoMocks.ProductRepository,
 				_ *repoMocks.CustomerRepository,
-				_ *serviceMock.NotificationService,
 				_ *domain.Order,
 			) {
 			},
@@ -186,7 +162,6 @@ func TestOrderService_Create(t *testing.T) {
 			setupMocks: func(_ *repoMocks.OrderRepository,
 				_ *repoMocks.ProductRepository,
 				cr *repoMocks.CustomerRepository,
-				_ *serviceMock.NotificationService,
 				order *domain.Order,
 			) {
 				cr.On("GetByID", mock.Anything, order.CustomerID.String()).
@@ -209,7 +184,6 @@ func TestOrderService_Create(t *testing.T) {
 				or *repoMocks.OrderRepository,
 				pr *repoMocks.ProductRepository,
 				cr *repoMocks.CustomerRepository,
-				_ *serviceMock.NotificationService,
 				order *domain.Order,
 			) {
 				customer := &domain.Customer{
@@ -217,7 +191,7 @@ func TestOrderService_Create(t *testing.T) {
 				}
 				product := &domain.Product{
 					ID:    order.Items[0].ProductID,
-					Price: 10.0,
+					Price: domain.NewMoney(1000, domain.DefaultCurrency),
 					Stock: 1,
 				}
 
@@ -226,7 +200,16 @@ func TestOrderService_Create(t *testing.T) {
 				pr.On("GetByID", mock.Anything, order.Items[0].ProductID.String()).
 					Return(product, nil)
 
-				// Don't expect Create or UpdateStock calls for insufficient stock
+				or.On("Create", mock.Anything, mock.Anything, mock.Anything).
+					Return(&customErrors.InsufficientStockError{
+						Shortfalls: []customErrors.StockShortfall{{
+							ProductID: product.ID.String(),
+							Requested: order.Items[0].Quantity,
+							Available: product.Stock,
+						}},
+					})
+
+				// Don't expect a confirmation for a rejected order
 			},
 			expectedError: customErrors.ErrInsufficientStock,
 		},
@@ -234,16 +217,13 @@ func TestOrderService_Create(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			service, orderRepo, productRepo, customerRepo, notifier := setupOrderTest(
-				t,
-			)
+			service, orderRepo, productRepo, customerRepo := setupOrderTest(t)
 			order := tt.setupOrder()
 
 			tt.setupMocks(
 				orderRepo,
 				productRepo,
 				customerRepo,
-				notifier,
 				order,
 			)
 
@@ -254,14 +234,78 @@ func TestOrderService_Create(t *testing.T) {
 			} else {
 				assert.NoError(t, err)
 				assert.Equal(t, domain.OrderStatusPending, order.Status)
-				assert.Greater(t, order.TotalPrice, 0.0)
-				time.Sleep(100 * time.Millisecond)
+				assert.True(t, order.TotalPrice.IsPositive())
 			}
 
 			orderRepo.AssertExpectations(t)
 			productRepo.AssertExpectations(t)
 			customerRepo.AssertExpectations(t)
-			notifier.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOrderService_Checkout(t *testing.T) {
+	cartID := uuid.New().String()
+
+	tests := []struct {
+		name       string
+		setupMocks func(
+			or *repoMocks.OrderRepository,
+			cr *repoMocks.CustomerRepository,
+			order *domain.Order,
+		)
+		expectedError error
+	}{
+		{
+			name: "Success - Keeps Accepted Prices",
+			setupMocks: func(
+				or *repoMocks.OrderRepository,
+				cr *repoMocks.CustomerRepository,
+				order *domain.Order,
+			) {
+				cr.On("GetByID", mock.Anything, order.CustomerID.String()).
+					Return(&domain.Customer{ID: order.CustomerID}, nil)
+				or.On("Checkout", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
+					return o.Items[0].Price.Amount == 750 &&
+						o.TotalPrice.Amount == 1500 &&
+						o.Status == domain.OrderStatusPending
+				}), cartID, mock.MatchedBy(func(e *domain.OutboxEvent) bool {
+					return e.EventType == domain.OutboxEventOrderConfirmation
+				})).Return(nil)
+			},
+		},
+		{
+			name: "Error - Price Changed",
+			setupMocks: func(
+				or *repoMocks.OrderRepository,
+				cr *repoMocks.CustomerRepository,
+				order *domain.Order,
+			) {
+				cr.On("GetByID", mock.Anything, order.CustomerID.String()).
+					Return(&domain.Customer{ID: order.CustomerID}, nil)
+				or.On("Checkout", mock.Anything, mock.Anything, cartID, mock.Anything).
+					Return(customErrors.ErrOrderPriceChanged)
+			},
+			expectedError: customErrors.ErrOrderPriceChanged,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, orderRepo, _, customerRepo := setupOrderTest(t)
+			customer, _ := createTestCustomer()
+			order := createTestOrder(customer.ID)
+			order.Items[0].Price = domain.NewMoney(750, domain.DefaultCurrency)
+
+			tt.setupMocks(orderRepo, customerRepo, order)
+
+			err := service.Checkout(context.Background(), order, cartID)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
 		})
 	}
 }
@@ -303,7 +347,7 @@ func TestOrderService_GetByID(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			service, orderRepo, _, _, _ := setupOrderTest(t)
+			service, orderRepo, _, _ := setupOrderTest(t)
 			tt.setupMocks(orderRepo, tt.orderID)
 
 			order, err := service.GetByID(context.Background(), tt.orderID)
@@ -335,7 +379,8 @@ func TestOrderService_List(t *testing.T) {
 					*createTestOrder(customer.ID),
 					*createTestOrder(customer.ID),
 				}
-				or.On("List", mock.Anything).Return(orders, nil)
+				or.On("List", mock.Anything, domain.ListQuery{}).
+					Return(&domain.Page[domain.Order]{Items: orders}, nil)
 			},
 			expectedCount: 2,
 			expectedError: nil,
@@ -343,7 +388,8 @@ func TestOrderService_List(t *testing.T) {
 		{
 			name: "Success - Empty List",
 			setupMocks: func(or *repoMocks.OrderRepository) {
-				or.On("List", mock.Anything).Return([]domain.Order{}, nil)
+				or.On("List", mock.Anything, domain.ListQuery{}).
+					Return(&domain.Page[domain.Order]{Items: []domain.Order{}}, nil)
 			},
 			expectedCount: 0,
 			expectedError: nil,
@@ -352,17 +398,20 @@ func TestOrderService_List(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			service, orderRepo, _, _, _ := setupOrderTest(t)
+			service, orderRepo, _, _ := setupOrderTest(t)
 			tt.setupMocks(orderRepo)
 
-			orders, err := service.List(context.Background())
+			orders, err := service.List(
+				context.Background(),
+				domain.ListQuery{},
+			)
 
 			if tt.expectedError != nil {
 				assert.ErrorIs(t, err, tt.expectedError)
 				assert.Nil(t, orders)
 			} else {
 				assert.NoError(t, err)
-				assert.Len(t, orders, tt.expectedCount)
+				assert.Len(t, orders.Items, tt.expectedCount)
 			}
 		})
 	}
@@ -384,8 +433,8 @@ func TestOrderService_ListByCustomerID(t *testing.T) {
 					*createTestOrder(uuid.MustParse(customerID)),
 					*createTestOrder(uuid.MustParse(customerID)),
 				}
-				or.On("ListByCustomerID", mock.Anything, customerID).
-					Return(orders, nil)
+				or.On("ListByCustomerID", mock.Anything, customerID, domain.ListQuery{}).
+					Return(&domain.Page[domain.Order]{Items: orders}, nil)
 			},
 			expectedCount: 2,
 			expectedError: nil,
@@ -401,12 +450,13 @@ func TestOrderService_ListByCustomerID(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			service, orderRepo, _, customerRepo, _ := setupOrderTest(t)
+			service, orderRepo, _, customerRepo := setupOrderTest(t)
 			tt.setupMocks(orderRepo, customerRepo, tt.customerID)
 
 			orders, err := service.ListByCustomerID(
 				context.Background(),
 				tt.customerID,
+				domain.ListQuery{},
 			)
 
 			if tt.expectedError != nil {
@@ -414,7 +464,7 @@ func TestOrderService_ListByCustomerID(t *testing.T) {
 				assert.Nil(t, orders)
 			} else {
 				assert.NoError(t, err)
-				assert.Len(t, orders, tt.expectedCount)
+				assert.Len(t, orders.Items, tt.expectedCount)
 			}
 
 			orderRepo.AssertExpectations(t)
@@ -467,7 +517,7 @@ func TestOrderService_Update(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			service, orderRepo, _, _, _ := setupOrderTest(t)
+			service, orderRepo, _, _ := setupOrderTest(t)
 			order := tt.setupOrder()
 			tt.setupMocks(orderRepo, order)
 
@@ -483,12 +533,15 @@ func TestOrderService_Update(t *testing.T) {
 }
 
 func TestOrderService_UpdateStatus(t *testing.T) {
+	actorID := uuid.New().String()
+
 	tests := []struct {
 		name          string
 		orderID       string
 		fromStatus    domain.OrderStatus
 		toStatus      domain.OrderStatus
-		setupMocks    func(*repoMocks.OrderRepository, *serviceMock.NotificationService, string, domain.OrderStatus)
+		reason        string
+		setupMocks    func(*repoMocks.OrderRepository, string, domain.OrderStatus)
 		expectedError error
 	}{
 		{
@@ -496,9 +549,9 @@ func TestOrderService_UpdateStatus(t *testing.T) {
 			orderID:    uuid.New().String(),
 			fromStatus: domain.OrderStatusPending,
 			toStatus:   domain.OrderStatusConfirmed,
+			reason:     "payment received",
 			setupMocks: func(
 				or *repoMocks.OrderRepository,
-				ns *serviceMock.NotificationService,
 				orderID string,
 				toStatus domain.OrderStatus,
 			) {
@@ -508,16 +561,22 @@ func TestOrderService_UpdateStatus(t *testing.T) {
 				}
 
 				or.On("GetByID", mock.Anything, orderID).Return(order, nil)
-				or.On("UpdateStatus", mock.Anything, orderID, toStatus).
-					Run(func(_ mock.Arguments) {
-						order.Status = toStatus
-					}).Return(nil)
-				ns.On("SendOrderStatusUpdate", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
-					return o.ID.String() == orderID &&
-						o.Status == toStatus
-				})).
-					Return(nil).
-					Once()
+				or.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(tr *domain.OrderTransition) bool {
+					c := tr.Change
+					if c.OrderID.String() != orderID ||
+						c.FromStatus != domain.OrderStatusPending ||
+						c.ToStatus != toStatus ||
+						c.ActorID == nil || c.ActorID.String() != actorID ||
+						c.Reason != "payment received" ||
+						len(tr.Events) != 1 {
+						return false
+					}
+					payload, err := tr.Events[0].OrderPayload()
+					return err == nil &&
+						tr.Events[0].EventType == domain.OutboxEventOrderStatusUpdate &&
+						payload.OrderID.String() == orderID &&
+						payload.Status == toStatus
+				})).Return(nil).Once()
 			},
 			expectedError: nil,
 		},
@@ -528,7 +587,6 @@ func TestOrderService_UpdateStatus(t *testing.T) {
 			toStatus:   domain.OrderStatusDelivered,
 			setupMocks: func(
 				or *repoMocks.OrderRepository,
-				_ *serviceMock.NotificationService,
 				orderID string,
 				_ domain.OrderStatus,
 			) {
@@ -540,6 +598,38 @@ func TestOrderService_UpdateStatus(t *testing.T) {
 			},
 			expectedError: customErrors.ErrOrderStatusInvalid,
 		},
+		{
+			name:       "Error - Reason Too Long",
+			orderID:    uuid.New().String(),
+			fromStatus: domain.OrderStatusPending,
+			toStatus:   domain.OrderStatusCancelled,
+			reason:     strings.Repeat("x", domain.MaxStatusReasonLength+1),
+			setupMocks: func(
+				or *repoMocks.OrderRepository,
+				orderID string,
+				_ domain.OrderStatus,
+			) {
+				order := &domain.Order{
+					ID:     uuid.MustParse(orderID),
+					Status: domain.OrderStatusPending,
+				}
+				or.On("GetByID", mock.Anything, orderID).Return(order, nil)
+			},
+			expectedError: customErrors.ErrOrderStatusInvalid,
+		},
+		{
+			name:       "Error - Refund Outside Refund",
+			orderID:    uuid.New().String(),
+			fromStatus: domain.OrderStatusDelivered,
+			toStatus:   domain.OrderStatusRefunded,
+			setupMocks: func(
+				_ *repoMocks.OrderRepository,
+				_ string,
+				_ domain.OrderStatus,
+			) {
+			},
+			expectedError: customErrors.ErrOrderStatusInvalid,
+		},
 		{
 			name:       "Error - Empty OrderID",
 			orderID:    "",
@@ -547,7 +637,6 @@ func TestOrderService_UpdateStatus(t *testing.T) {
 			toStatus:   domain.OrderStatusConfirmed,
 			setupMocks: func(
 				_ *repoMocks.OrderRepository,
-				_ *serviceMock.NotificationService,
 				_ string,
 				_ domain.OrderStatus,
 			) {
@@ -561,7 +650,6 @@ func TestOrderService_UpdateStatus(t *testing.T) {
 			toStatus:   domain.OrderStatusConfirmed,
 			setupMocks: func(
 				or *repoMocks.OrderRepository,
-				_ *serviceMock.NotificationService,
 				orderID string,
 				_ domain.OrderStatus,
 			) {
@@ -574,35 +662,334 @@ func TestOrderService_UpdateStatus(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			service, orderRepo, _, _, notifier := setupOrderTest(t)
+			service, orderRepo, _, _ := setupOrderTest(t)
 			if tt.orderID != "" &&
 				tt.expectedError != customErrors.ErrInvalidOrderData {
-				tt.setupMocks(orderRepo, notifier, tt.orderID, tt.toStatus)
+				tt.setupMocks(orderRepo, tt.orderID, tt.toStatus)
 			}
 
 			err := service.UpdateStatus(
 				context.Background(),
 				tt.orderID,
 				tt.toStatus,
+				actorID,
+				tt.reason,
 			)
 
 			if tt.expectedError != nil {
 				assert.ErrorIs(t, err, tt.expectedError)
 			} else {
 				assert.NoError(t, err)
-				time.Sleep(100 * time.Millisecond)
 			}
 
 			orderRepo.AssertExpectations(t)
-			notifier.AssertExpectations(t)
 		})
 	}
 }
 
+func TestOrderService_UpdateStatusTransitionHooks(t *testing.T) {
+	ctx := context.Background()
+	orderID := uuid.New()
+	hookErr := errors.New("payment gateway unavailable")
+
+	tests := []struct {
+		name          string
+		setupMachine  func(*domain.OrderStateMachine)
+		expectStore   func(*repoMocks.OrderRepository)
+		expectedError error
+	}{
+		{
+			name: "Success - Hook Events Stored With Status Change",
+			setupMachine: func(m *domain.OrderStateMachine) {
+				m.OnEnter(
+					domain.OrderStatusCancelled,
+					func(_ context.Context, tr *domain.OrderTransition) error {
+						event, err := domain.NewOrderEvent(
+							domain.OutboxEventOrderConfirmation,
+							tr.Order,
+						)
+						tr.Events = append(tr.Events, event)
+						return err
+					},
+				)
+			},
+			expectStore: func(or *repoMocks.OrderRepository) {
+				or.On(
+					"UpdateStatus",
+					ctx,
+					mock.MatchedBy(func(tr *domain.OrderTransition) bool {
+						return len(tr.Events) == 2 &&
+							tr.Events[0].EventType == domain.OutboxEventOrderConfirmation &&
+							tr.Events[1].EventType == domain.OutboxEventOrderStatusUpdate
+					}),
+				).Return(nil).Once()
+			},
+		},
+		{
+			name: "Error - Guard Rejects Transition",
+			setupMachine: func(m *domain.OrderStateMachine) {
+				m.Guard(
+					domain.OrderStatusCancelled,
+					func(_ context.Context, _ *domain.OrderTransition) error {
+						return errors.New("order is already being picked")
+					},
+				)
+			},
+			expectStore:   func(_ *repoMocks.OrderRepository) {},
+			expectedError: customErrors.ErrOrderStatusInvalid,
+		},
+		{
+			name: "Error - Hook Failure Aborts Transition",
+			setupMachine: func(m *domain.OrderStateMachine) {
+				m.OnExit(
+					domain.OrderStatusConfirmed,
+					func(_ context.Context, _ *domain.OrderTransition) error {
+						return hookErr
+					},
+				)
+			},
+			expectStore:   func(_ *repoMocks.OrderRepository) {},
+			expectedError: hookErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orderRepo := repoMocks.NewOrderRepository(t)
+			machine := domain.NewDefaultOrderStateMachine()
+			tt.setupMachine(machine)
+			service := NewOrderService(
+				orderRepo,
+				repoMocks.NewProductRepository(t),
+				repoMocks.NewCustomerRepository(t),
+				machine,
+			)
+
+			orderRepo.On("GetByID", ctx, orderID.String()).
+				Return(&domain.Order{
+					ID:     orderID,
+					Status: domain.OrderStatusConfirmed,
+				}, nil).Once()
+			tt.expectStore(orderRepo)
+
+			err := service.UpdateStatus(
+				ctx,
+				orderID.String(),
+				domain.OrderStatusCancelled,
+				"",
+				"",
+			)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestOrderService_UpdateStatusRestoresStock(t *testing.T) {
+	ctx := context.Background()
+	productA, productB := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name        string
+		from        domain.OrderStatus
+		to          domain.OrderStatus
+		wantRestock []domain.StockAdjustment
+	}{
+		{
+			name: "Cancel Before Shipping Restocks Every Item",
+			from: domain.OrderStatusReady,
+			to:   domain.OrderStatusCancelled,
+			wantRestock: []domain.StockAdjustment{
+				{ProductID: productA, Quantity: 2},
+				{ProductID: productB, Quantity: 3},
+			},
+		},
+		{
+			name: "Failure Before Shipping Restocks Every Item",
+			from: domain.OrderStatusPending,
+			to:   domain.OrderStatusFailed,
+			wantRestock: []domain.StockAdjustment{
+				{ProductID: productA, Quantity: 2},
+				{ProductID: productB, Quantity: 3},
+			},
+		},
+		{
+			name: "Failure After Shipping Does Not Restock",
+			from: domain.OrderStatusShipped,
+			to:   domain.OrderStatusFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, orderRepo, _, _ := setupOrderTest(t)
+			order := &domain.Order{
+				ID:     uuid.New(),
+				Status: tt.from,
+				Items: []domain.OrderItem{
+					{ID: uuid.New(), ProductID: productA, Quantity: 2},
+					{ID: uuid.New(), ProductID: productB, Quantity: 3},
+				},
+			}
+
+			orderRepo.On("GetByID", ctx, order.ID.String()).
+				Return(order, nil).Once()
+			orderRepo.On("UpdateStatus", ctx, mock.MatchedBy(
+				func(tr *domain.OrderTransition) bool {
+					return assert.ObjectsAreEqual(tt.wantRestock, tr.Restock)
+				},
+			)).Return(nil).Once()
+
+			err := service.UpdateStatus(ctx, order.ID.String(), tt.to, "", "")
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestOrderService_Refund(t *testing.T) {
+	ctx := context.Background()
+	restockedItem := domain.OrderItem{
+		ID:        uuid.New(),
+		ProductID: uuid.New(),
+		Quantity:  2,
+	}
+	discardedItem := domain.OrderItem{
+		ID:        uuid.New(),
+		ProductID: uuid.New(),
+		Quantity:  1,
+	}
+
+	newOrder := func(status domain.OrderStatus) *domain.Order {
+		return &domain.Order{
+			ID:     uuid.New(),
+			Status: status,
+			Items:  []domain.OrderItem{restockedItem, discardedItem},
+		}
+	}
+
+	t.Run("Success - Restocks Selected Lines", func(t *testing.T) {
+		service, orderRepo, _, _ := setupOrderTest(t)
+		order := newOrder(domain.OrderStatusDelivered)
+
+		orderRepo.On("GetByID", ctx, order.ID.String()).
+			Return(order, nil).Once()
+		orderRepo.On("UpdateStatus", ctx, mock.MatchedBy(
+			func(tr *domain.OrderTransition) bool {
+				return tr.To == domain.OrderStatusRefunded &&
+					tr.Change.Reason == "damaged box" &&
+					assert.ObjectsAreEqual([]domain.StockAdjustment{{
+						ProductID: restockedItem.ProductID,
+						Quantity:  restockedItem.Quantity,
+					}}, tr.Restock)
+			},
+		)).Return(nil).Once()
+
+		err := service.Refund(ctx, order.ID.String(), "", "damaged box", []domain.RefundLine{
+			{ItemID: restockedItem.ID, Action: domain.RefundActionRestock},
+			{ItemID: discardedItem.ID, Action: domain.RefundActionDiscard},
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Success - Without Lines Discards Everything", func(t *testing.T) {
+		service, orderRepo, _, _ := setupOrderTest(t)
+		order := newOrder(domain.OrderStatusDelivered)
+
+		orderRepo.On("GetByID", ctx, order.ID.String()).
+			Return(order, nil).Once()
+		orderRepo.On("UpdateStatus", ctx, mock.MatchedBy(
+			func(tr *domain.OrderTransition) bool {
+				return tr.To == domain.OrderStatusRefunded &&
+					len(tr.Restock) == 0
+			},
+		)).Return(nil).Once()
+
+		err := service.Refund(ctx, order.ID.String(), "", "", nil)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Error - Unknown Item", func(t *testing.T) {
+		service, orderRepo, _, _ := setupOrderTest(t)
+		order := newOrder(domain.OrderStatusDelivered)
+		orderRepo.On("GetByID", ctx, order.ID.String()).
+			Return(order, nil).Once()
+
+		err := service.Refund(ctx, order.ID.String(), "", "", []domain.RefundLine{
+			{ItemID: uuid.New(), Action: domain.RefundActionRestock},
+		})
+
+		assert.ErrorIs(t, err, customErrors.ErrInvalidOrderData)
+	})
+
+	t.Run("Error - Lines On Cancelled Order", func(t *testing.T) {
+		service, orderRepo, _, _ := setupOrderTest(t)
+		order := newOrder(domain.OrderStatusCancelled)
+		orderRepo.On("GetByID", ctx, order.ID.String()).
+			Return(order, nil).Once()
+
+		err := service.Refund(ctx, order.ID.String(), "", "", []domain.RefundLine{
+			{ItemID: restockedItem.ID, Action: domain.RefundActionRestock},
+		})
+
+		assert.ErrorIs(t, err, customErrors.ErrInvalidOrderData)
+	})
+
+	t.Run("Error - Not Refundable", func(t *testing.T) {
+		service, orderRepo, _, _ := setupOrderTest(t)
+		order := newOrder(domain.OrderStatusPending)
+		orderRepo.On("GetByID", ctx, order.ID.String()).
+			Return(order, nil).Once()
+
+		err := service.Refund(ctx, order.ID.String(), "", "", nil)
+
+		assert.ErrorIs(t, err, customErrors.ErrOrderStatusInvalid)
+	})
+}
+
+func TestOrderService_GetStatusHistory(t *testing.T) {
+	service, orderRepo, _, _ := setupOrderTest(t)
+	ctx := context.Background()
+	orderID := uuid.New().String()
+
+	t.Run("Success", func(t *testing.T) {
+		history := []domain.OrderStatusChange{{
+			ID:         uuid.New(),
+			OrderID:    uuid.MustParse(orderID),
+			FromStatus: domain.OrderStatusPending,
+			ToStatus:   domain.OrderStatusConfirmed,
+		}}
+		orderRepo.On("GetByID", ctx, orderID).
+			Return(&domain.Order{ID: uuid.MustParse(orderID)}, nil).Once()
+		orderRepo.On("ListStatusHistory", ctx, orderID).
+			Return(history, nil).Once()
+
+		result, err := service.GetStatusHistory(ctx, orderID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, history, result)
+	})
+
+	t.Run("Error - Order Not Found", func(t *testing.T) {
+		orderRepo.On("GetByID", ctx, orderID).
+			Return(nil, customErrors.ErrOrderNotFound).Once()
+
+		_, err := service.GetStatusHistory(ctx, orderID)
+
+		assert.ErrorIs(t, err, customErrors.ErrOrderNotFound)
+	})
+}
+
 func TestOrderService_RemoveOrderItemFromNonPendingOrder(
 	t *testing.T,
 ) {
-	service, orderRepo, _, _, _ := setupOrderTest(t)
+	service, orderRepo, _, _ := setupOrderTest(t)
 	ctx := context.Background()
 	tests := []struct {
 		name     string
@@ -638,7 +1025,7 @@ func TestOrderService_RemoveOrderItemFromNonPendingOrder(
 func TestOrderService_RemoveOrderItemNotFound(
 	t *testing.T,
 ) {
-	service, orderRepo, _, _, _ := setupOrderTest(t)
+	service, orderRepo, _, _ := setupOrderTest(t)
 	ctx := context.Background()
 
 	customer, _ := createTestCustomer()
@@ -653,16 +1040,8 @@ func TestOrderService_RemoveOrderItemNotFound(
 	}
 
 	orderRepo.On("GetByID", ctx, orderID.String()).Return(order, nil)
-	orderRepo.On(
-		"RemoveOrderItem",
-		ctx,
-		orderID.String(),
-		itemID.String(),
-		mock.AnythingOfType("func(context.Context, string, int) error"),
-		mock.AnythingOfType(
-			"func(context.Context, *domain.Order, float64) error",
-		),
-	).Return(customErrors.ErrOrderItemNotFound)
+	orderRepo.On("RemoveOrderItem", ctx, orderID.String(), itemID.String()).
+		Return(customErrors.ErrOrderItemNotFound)
 
 	err := service.RemoveOrderItem(ctx, orderID.String(), itemID.String())
 	assert.Error(t, err)
@@ -672,7 +1051,7 @@ func TestOrderService_RemoveOrderItemNotFound(
 func TestOrderService_RemoveOrderItemInvalidInput(
 	t *testing.T,
 ) {
-	service, orderRepo, _, _, _ := setupOrderTest(t)
+	service, orderRepo, _, _ := setupOrderTest(t)
 	ctx := context.Background()
 
 	tests := []struct {
@@ -0,0 +1,304 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grocery-service/internal/config"
+	"github.com/grocery-service/internal/domain"
+	repository "github.com/grocery-service/internal/repository/postgres"
+	"github.com/grocery-service/internal/service/notification"
+	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/grocery-service/utils/logger"
+)
+
+// errUndeliverable marks failures that retrying cannot fix, such as an
+// unknown event type. Such events are dead-lettered immediately.
+var errUndeliverable = errors.New("event cannot be delivered")
+
+type (
+	OutboxService interface {
+		List(
+			ctx context.Context,
+			status domain.OutboxStatus,
+			query domain.ListQuery,
+		) (*domain.Page[domain.OutboxEvent], error)
+		GetByID(ctx context.Context, id string) (*domain.OutboxEvent, error)
+		Replay(ctx context.Context, id string) (*domain.OutboxEvent, error)
+	}
+
+	OutboxServiceImpl struct {
+		repo repository.OutboxRepository
+	}
+
+	// OutboxDispatcher delivers pending outbox events through the
+	// notification service. Delivery is at least once: an event whose
+	// outcome could not be stored is sent again after its lease expires.
+	OutboxDispatcher struct {
+		repo         repository.OutboxRepository
+		orderRepo    repository.OrderRepository
+		customerRepo repository.CustomerRepository
+		notifier     notification.NotificationService
+		cfg          config.OutboxConfig
+	}
+)
+
+func NewOutboxService(repo repository.OutboxRepository) OutboxService {
+	return &OutboxServiceImpl{repo: repo}
+}
+
+func (s *OutboxServiceImpl) List(
+	ctx context.Context,
+	status domain.OutboxStatus,
+	query domain.ListQuery,
+) (*domain.Page[domain.OutboxEvent], error) {
+	if status != "" {
+		if err := domain.ValidateOutboxStatus(status); err != nil {
+			return nil, fmt.Errorf(
+				"%w: %v",
+				customErrors.ErrInvalidListQuery,
+				err,
+			)
+		}
+	}
+	return s.repo.List(ctx, status, query)
+}
+
+func (s *OutboxServiceImpl) GetByID(
+	ctx context.Context,
+	id string,
+) (*domain.OutboxEvent, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, customErrors.ErrOutboxEventNotFound
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *OutboxServiceImpl) Replay(
+	ctx context.Context,
+	id string,
+) (*domain.OutboxEvent, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, customErrors.ErrOutboxEventNotFound
+	}
+	return s.repo.Replay(ctx, id)
+}
+
+func NewOutboxDispatcher(
+	repo repository.OutboxRepository,
+	orderRepo repository.OrderRepository,
+	customerRepo repository.CustomerRepository,
+	notifier notification.NotificationService,
+	cfg config.OutboxConfig,
+) *OutboxDispatcher {
+	return &OutboxDispatcher{
+		repo:         repo,
+		orderRepo:    orderRepo,
+		customerRepo: customerRepo,
+		notifier:     notifier,
+		cfg:          cfg,
+	}
+}
+
+// Run dispatches due events every poll interval until ctx is cancelled.
+func (d *OutboxDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.DispatchDue(ctx); err != nil && ctx.Err() == nil {
+			logger.Error(
+				"outbox dispatch failed",
+				logger.Error64("error", err),
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchDue claims one batch of due events, attempts to deliver each
+// and records the outcome. Low-stock events in the batch are sent
+// together as one digest. It returns the number of events attempted.
+func (d *OutboxDispatcher) DispatchDue(ctx context.Context) (int, error) {
+	events, err := d.repo.ClaimDue(ctx, d.cfg.BatchSize, d.cfg.Lease)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim outbox events: %w", err)
+	}
+
+	attempted := 0
+	var lowStock []*domain.OutboxEvent
+	for i := range events {
+		event := &events[i]
+		if event.EventType == domain.OutboxEventLowStock {
+			lowStock = append(lowStock, event)
+			continue
+		}
+
+		deliveryErr := d.deliver(ctx, event)
+		if deliveryErr != nil && ctx.Err() != nil {
+			// Shutting down; the lease makes the event due again.
+			return attempted, ctx.Err()
+		}
+
+		attempted++
+		if err := d.record(ctx, event, deliveryErr); err != nil {
+			return attempted, fmt.Errorf(
+				"failed to record outbox event %s: %w",
+				event.ID,
+				err,
+			)
+		}
+	}
+
+	if len(lowStock) == 0 {
+		return attempted, nil
+	}
+
+	n, err := d.deliverLowStockDigest(ctx, lowStock)
+	return attempted + n, err
+}
+
+// deliverLowStockDigest sends one alert covering every low-stock event
+// and records the shared outcome on each of them. A product listed more
+// than once is reported at its latest stock level.
+func (d *OutboxDispatcher) deliverLowStockDigest(
+	ctx context.Context,
+	events []*domain.OutboxEvent,
+) (int, error) {
+	var (
+		alerts    []domain.LowStockAlert
+		delivered []*domain.OutboxEvent
+		attempted int
+	)
+	index := make(map[uuid.UUID]int)
+
+	for _, event := range events {
+		alert, err := event.LowStockPayload()
+		if err != nil {
+			attempted++
+			deliveryErr := fmt.Errorf("%w: %v", errUndeliverable, err)
+			if err := d.record(ctx, event, deliveryErr); err != nil {
+				return attempted, fmt.Errorf(
+					"failed to record outbox event %s: %w",
+					event.ID,
+					err,
+				)
+			}
+			continue
+		}
+
+		if i, ok := index[alert.ProductID]; ok {
+			alerts[i] = alert
+		} else {
+			index[alert.ProductID] = len(alerts)
+			alerts = append(alerts, alert)
+		}
+		delivered = append(delivered, event)
+	}
+
+	if len(delivered) == 0 {
+		return attempted, nil
+	}
+
+	deliveryErr := d.notifier.SendLowStockAlert(ctx, alerts)
+	if deliveryErr != nil && ctx.Err() != nil {
+		return attempted, ctx.Err()
+	}
+
+	for _, event := range delivered {
+		attempted++
+		if err := d.record(ctx, event, deliveryErr); err != nil {
+			return attempted, fmt.Errorf(
+				"failed to record outbox event %s: %w",
+				event.ID,
+				err,
+			)
+		}
+	}
+	return attempted, nil
+}
+
+func (d *OutboxDispatcher) deliver(
+	ctx context.Context,
+	event *domain.OutboxEvent,
+) error {
+	payload, err := event.OrderPayload()
+	if err != nil {
+		return fmt.Errorf("%w: %v", errUndeliverable, err)
+	}
+
+	order, err := d.orderRepo.GetByID(ctx, payload.OrderID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get order: %w", err)
+	}
+
+	customer, err := d.customerRepo.GetByID(ctx, order.CustomerID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get customer: %w", err)
+	}
+	order.Customer = customer
+	order.Status = payload.Status
+
+	switch event.EventType {
+	case domain.OutboxEventOrderConfirmation:
+		return d.notifier.SendOrderConfirmation(ctx, order)
+	case domain.OutboxEventOrderStatusUpdate:
+		return d.notifier.SendOrderStatusUpdate(ctx, order)
+	default:
+		return fmt.Errorf(
+			"%w: unknown event type %q",
+			errUndeliverable,
+			event.EventType,
+		)
+	}
+}
+
+// record applies the outcome of a delivery attempt to event. Failed
+// events are retried with exponential backoff until MaxAttempts is
+// reached and are then dead-lettered.
+func (d *OutboxDispatcher) record(
+	ctx context.Context,
+	event *domain.OutboxEvent,
+	deliveryErr error,
+) error {
+	now := time.Now()
+	event.Attempts++
+
+	switch {
+	case deliveryErr == nil:
+		event.Status = domain.OutboxStatusDelivered
+		event.DeliveredAt = &now
+		event.LastError = ""
+	case errors.Is(deliveryErr, errUndeliverable),
+		event.Attempts >= d.cfg.MaxAttempts:
+		event.Status = domain.OutboxStatusDead
+		event.LastError = deliveryErr.Error()
+	default:
+		event.LastError = deliveryErr.Error()
+		event.NextAttemptAt = now.Add(d.backoff(event.Attempts))
+	}
+
+	return d.repo.SaveAttempt(ctx, event)
+}
+
+// backoff returns the delay before the next attempt after the given
+// number of failed attempts: BaseBackoff doubled per attempt and capped
+// at MaxBackoff.
+func (d *OutboxDispatcher) backoff(attempts int) time.Duration {
+	delay := d.cfg.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= d.cfg.MaxBackoff {
+			return d.cfg.MaxBackoff
+		}
+	}
+	return min(delay, d.cfg.MaxBackoff)
+}
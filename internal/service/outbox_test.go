@@ -0,0 +1,364 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grocery-service/internal/config"
+	"github.com/grocery-service/internal/domain"
+	repoMocks "github.com/grocery-service/tests/mocks/repository"
+	serviceMock "github.com/grocery-service/tests/mocks/service"
+	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type outboxTestDeps struct {
+	outboxRepo   *repoMocks.OutboxRepository
+	orderRepo    *repoMocks.OrderRepository
+	customerRepo *repoMocks.CustomerRepository
+	notifier     *serviceMock.NotificationService
+}
+
+var testOutboxConfig = config.OutboxConfig{
+	PollInterval: time.Second,
+	BatchSize:    10,
+	MaxAttempts:  3,
+	BaseBackoff:  10 * time.Second,
+	MaxBackoff:   time.Minute,
+	Lease:        time.Minute,
+}
+
+func setupOutboxDispatcherTest(
+	t *testing.T,
+) (*OutboxDispatcher, outboxTestDeps) {
+	deps := outboxTestDeps{
+		outboxRepo:   repoMocks.NewOutboxRepository(t),
+		orderRepo:    repoMocks.NewOrderRepository(t),
+		customerRepo: repoMocks.NewCustomerRepository(t),
+		notifier:     serviceMock.NewNotificationService(t),
+	}
+
+	dispatcher := NewOutboxDispatcher(
+		deps.outboxRepo,
+		deps.orderRepo,
+		deps.customerRepo,
+		deps.notifier,
+		testOutboxConfig,
+	)
+	return dispatcher, deps
+}
+
+func createTestOutboxEvent(
+	t *testing.T,
+	eventType domain.OutboxEventType,
+	order *domain.Order,
+) domain.OutboxEvent {
+	event, err := domain.NewOrderEvent(eventType, order)
+	require.NoError(t, err)
+	return *event
+}
+
+func TestOutboxDispatcher_DispatchDue(t *testing.T) {
+	customer, _ := createTestCustomer()
+	order := createTestOrder(customer.ID)
+	order.Status = domain.OrderStatusConfirmed
+
+	expectLoadOrder := func(deps outboxTestDeps) {
+		stored := *order
+		stored.Status = domain.OrderStatusPreparing
+		deps.orderRepo.On("GetByID", mock.Anything, order.ID.String()).
+			Return(&stored, nil).Once()
+		deps.customerRepo.On("GetByID", mock.Anything, customer.ID.String()).
+			Return(customer, nil).Once()
+	}
+
+	tests := []struct {
+		name       string
+		event      func() domain.OutboxEvent
+		setupMocks func(deps outboxTestDeps)
+		check      func(t *testing.T, event *domain.OutboxEvent)
+	}{
+		{
+			name: "Success - Delivered",
+			event: func() domain.OutboxEvent {
+				return createTestOutboxEvent(
+					t,
+					domain.OutboxEventOrderStatusUpdate,
+					order,
+				)
+			},
+			setupMocks: func(deps outboxTestDeps) {
+				expectLoadOrder(deps)
+				deps.notifier.On(
+					"SendOrderStatusUpdate",
+					mock.Anything,
+					mock.MatchedBy(func(o *domain.Order) bool {
+						// The status recorded with the event is sent, not
+						// the order's current status.
+						return o.ID == order.ID &&
+							o.Status == domain.OrderStatusConfirmed &&
+							o.Customer == customer
+					}),
+				).Return(nil).Once()
+			},
+			check: func(t *testing.T, event *domain.OutboxEvent) {
+				assert.Equal(t, domain.OutboxStatusDelivered, event.Status)
+				assert.Equal(t, 1, event.Attempts)
+				assert.NotNil(t, event.DeliveredAt)
+				assert.Empty(t, event.LastError)
+			},
+		},
+		{
+			name: "Failure - Retried With Backoff",
+			event: func() domain.OutboxEvent {
+				event := createTestOutboxEvent(
+					t,
+					domain.OutboxEventOrderConfirmation,
+					order,
+				)
+				event.Attempts = 1
+				return event
+			},
+			setupMocks: func(deps outboxTestDeps) {
+				expectLoadOrder(deps)
+				deps.notifier.On("SendOrderConfirmation", mock.Anything, mock.Anything).
+					Return(errors.New("smtp: connection refused")).Once()
+			},
+			check: func(t *testing.T, event *domain.OutboxEvent) {
+				assert.Equal(t, domain.OutboxStatusPending, event.Status)
+				assert.Equal(t, 2, event.Attempts)
+				assert.Equal(t, "smtp: connection refused", event.LastError)
+				assert.WithinDuration(
+					t,
+					time.Now().Add(20*time.Second),
+					event.NextAttemptAt,
+					time.Second,
+				)
+			},
+		},
+		{
+			name: "Failure - Dead After Max Attempts",
+			event: func() domain.OutboxEvent {
+				event := createTestOutboxEvent(
+					t,
+					domain.OutboxEventOrderConfirmation,
+					order,
+				)
+				event.Attempts = testOutboxConfig.MaxAttempts - 1
+				return event
+			},
+			setupMocks: func(deps outboxTestDeps) {
+				expectLoadOrder(deps)
+				deps.notifier.On("SendOrderConfirmation", mock.Anything, mock.Anything).
+					Return(errors.New("smtp: connection refused")).Once()
+			},
+			check: func(t *testing.T, event *domain.OutboxEvent) {
+				assert.Equal(t, domain.OutboxStatusDead, event.Status)
+				assert.Equal(t, testOutboxConfig.MaxAttempts, event.Attempts)
+			},
+		},
+		{
+			name: "Failure - Unknown Event Type Is Dead Immediately",
+			event: func() domain.OutboxEvent {
+				return createTestOutboxEvent(t, "order.unknown", order)
+			},
+			setupMocks: func(deps outboxTestDeps) {
+				expectLoadOrder(deps)
+			},
+			check: func(t *testing.T, event *domain.OutboxEvent) {
+				assert.Equal(t, domain.OutboxStatusDead, event.Status)
+				assert.Equal(t, 1, event.Attempts)
+				assert.Contains(t, event.LastError, "unknown event type")
+			},
+		},
+		{
+			name: "Failure - Missing Order Is Retried",
+			event: func() domain.OutboxEvent {
+				return createTestOutboxEvent(
+					t,
+					domain.OutboxEventOrderConfirmation,
+					order,
+				)
+			},
+			setupMocks: func(deps outboxTestDeps) {
+				deps.orderRepo.On("GetByID", mock.Anything, order.ID.String()).
+					Return(nil, customErrors.ErrOrderNotFound).Once()
+			},
+			check: func(t *testing.T, event *domain.OutboxEvent) {
+				assert.Equal(t, domain.OutboxStatusPending, event.Status)
+				assert.Contains(t, event.LastError, "order not found")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dispatcher, deps := setupOutboxDispatcherTest(t)
+			event := tt.event()
+
+			deps.outboxRepo.On(
+				"ClaimDue",
+				mock.Anything,
+				testOutboxConfig.BatchSize,
+				testOutboxConfig.Lease,
+			).Return([]domain.OutboxEvent{event}, nil).Once()
+			tt.setupMocks(deps)
+
+			var saved *domain.OutboxEvent
+			deps.outboxRepo.On("SaveAttempt", mock.Anything, mock.Anything).
+				Run(func(args mock.Arguments) {
+					saved = args.Get(1).(*domain.OutboxEvent)
+				}).
+				Return(nil).Once()
+
+			count, err := dispatcher.DispatchDue(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, 1, count)
+			require.NotNil(t, saved)
+			assert.Equal(t, event.ID, saved.ID)
+			tt.check(t, saved)
+		})
+	}
+}
+
+func TestOutboxDispatcher_DispatchDueStopsOnShutdown(t *testing.T) {
+	dispatcher, deps := setupOutboxDispatcherTest(t)
+	customer, _ := createTestCustomer()
+	order := createTestOrder(customer.ID)
+	event := createTestOutboxEvent(
+		t,
+		domain.OutboxEventOrderConfirmation,
+		order,
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	deps.outboxRepo.On("ClaimDue", mock.Anything, mock.Anything, mock.Anything).
+		Return([]domain.OutboxEvent{event}, nil).Once()
+	deps.orderRepo.On("GetByID", mock.Anything, order.ID.String()).
+		Run(func(_ mock.Arguments) { cancel() }).
+		Return(nil, context.Canceled).Once()
+
+	count, err := dispatcher.DispatchDue(ctx)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 0, count)
+	deps.outboxRepo.AssertNotCalled(t, "SaveAttempt", mock.Anything, mock.Anything)
+}
+
+func TestOutboxDispatcher_DispatchDueSendsLowStockDigest(t *testing.T) {
+	dispatcher, deps := setupOutboxDispatcherTest(t)
+
+	milk := domain.LowStockAlert{
+		ProductID:    uuid.New(),
+		Name:         "Whole Milk",
+		Stock:        4,
+		ReorderPoint: 5,
+	}
+	eggs := domain.LowStockAlert{
+		ProductID:    uuid.New(),
+		Name:         "Eggs",
+		Stock:        10,
+		ReorderPoint: 12,
+	}
+	laterMilk := milk
+	laterMilk.Stock = 1
+
+	var events []domain.OutboxEvent
+	for _, alert := range []domain.LowStockAlert{milk, eggs, laterMilk} {
+		event, err := domain.NewLowStockEvent(alert)
+		require.NoError(t, err)
+		events = append(events, *event)
+	}
+	corrupt := events[1]
+	corrupt.ID = uuid.New()
+	corrupt.Payload = []byte(`"not an alert"`)
+	events = append(events, corrupt)
+
+	deps.outboxRepo.On("ClaimDue", mock.Anything, mock.Anything, mock.Anything).
+		Return(events, nil).Once()
+	deps.notifier.On(
+		"SendLowStockAlert",
+		mock.Anything,
+		[]domain.LowStockAlert{laterMilk, eggs},
+	).Return(nil).Once()
+
+	saved := make(map[uuid.UUID]domain.OutboxStatus)
+	deps.outboxRepo.On("SaveAttempt", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			event := args.Get(1).(*domain.OutboxEvent)
+			saved[event.ID] = event.Status
+		}).
+		Return(nil).Times(4)
+
+	count, err := dispatcher.DispatchDue(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, count)
+	for _, event := range events[:3] {
+		assert.Equal(t, domain.OutboxStatusDelivered, saved[event.ID])
+	}
+	assert.Equal(t, domain.OutboxStatusDead, saved[corrupt.ID])
+}
+
+func TestOutboxDispatcher_Backoff(t *testing.T) {
+	dispatcher, _ := setupOutboxDispatcherTest(t)
+
+	assert.Equal(t, 10*time.Second, dispatcher.backoff(1))
+	assert.Equal(t, 20*time.Second, dispatcher.backoff(2))
+	assert.Equal(t, 40*time.Second, dispatcher.backoff(3))
+	assert.Equal(t, time.Minute, dispatcher.backoff(4))
+	assert.Equal(t, time.Minute, dispatcher.backoff(50))
+}
+
+func TestOutboxService_List(t *testing.T) {
+	repo := repoMocks.NewOutboxRepository(t)
+	service := NewOutboxService(repo)
+	ctx := context.Background()
+
+	t.Run("Success - Filter By Status", func(t *testing.T) {
+		page := &domain.Page[domain.OutboxEvent]{Total: 1}
+		repo.On("List", ctx, domain.OutboxStatusDead, domain.ListQuery{}).
+			Return(page, nil).Once()
+
+		result, err := service.List(ctx, domain.OutboxStatusDead, domain.ListQuery{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, page, result)
+	})
+
+	t.Run("Error - Invalid Status", func(t *testing.T) {
+		_, err := service.List(ctx, "LOST", domain.ListQuery{})
+
+		assert.ErrorIs(t, err, customErrors.ErrInvalidListQuery)
+	})
+}
+
+func TestOutboxService_Replay(t *testing.T) {
+	repo := repoMocks.NewOutboxRepository(t)
+	service := NewOutboxService(repo)
+	ctx := context.Background()
+	eventID := uuid.New().String()
+
+	t.Run("Success", func(t *testing.T) {
+		repo.On("Replay", ctx, eventID).
+			Return(&domain.OutboxEvent{Status: domain.OutboxStatusPending}, nil).
+			Once()
+
+		event, err := service.Replay(ctx, eventID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.OutboxStatusPending, event.Status)
+	})
+
+	t.Run("Error - Invalid ID", func(t *testing.T) {
+		_, err := service.Replay(ctx, "invalid-uuid")
+
+		assert.ErrorIs(t, err, customErrors.ErrOutboxEventNotFound)
+	})
+}
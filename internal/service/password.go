@@ -0,0 +1,391 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grocery-service/internal/domain"
+	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/grocery-service/utils/hash"
+	"github.com/grocery-service/utils/logger"
+	validator "github.com/grocery-service/utils/validation"
+)
+
+// Password sign-in runs alongside the identity providers and issues the
+// same tokens. Addresses are proved with single-use tokens stored in the
+// tokens table like any other, and sent straight to the user rather than
+// through the outbox, which would keep them in plaintext.
+
+// passwordProvider is recorded as the provider of password logins.
+const passwordProvider = "password"
+
+// dummyPasswordHash is compared against when no account matches a login,
+// so that unknown addresses take as long to reject as wrong passwords.
+var dummyPasswordHash = sync.OnceValue(func() string {
+	h, _ := hash.Generate("grocery-service-dummy-password")
+	return h
+})
+
+func (s *authService) Register(
+	ctx context.Context,
+	request domain.RegisterRequest,
+) error {
+	request.Email = normalizeEmail(request.Email)
+	request.Name = strings.TrimSpace(request.Name)
+	if err := validator.Struct(request); err != nil {
+		return validationError(err)
+	}
+
+	if !s.isAllowed(request.Email) {
+		return customErrors.ErrUnauthorized
+	}
+
+	// Hashing first keeps taken addresses from answering faster
+	passwordHash, err := hash.Generate(request.Password)
+	if err != nil {
+		return err
+	}
+
+	existing, err := s.userRepo.GetByEmail(ctx, request.Email)
+	if err == nil {
+		s.notifyAccountExists(ctx, existing)
+		return nil
+	}
+	if !errors.Is(err, customErrors.ErrUserNotFound) {
+		return err
+	}
+
+	now := time.Now()
+	user := &domain.User{
+		ID:        uuid.New(),
+		Email:     request.Email,
+		Password:  passwordHash,
+		Name:      request.Name,
+		Phone:     request.Phone,
+		Role:      domain.CustomerRole,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+
+	// The account exists either way; a lost email can be sent again.
+	if err := s.sendVerification(ctx, user); err != nil {
+		logger.Error(
+			"failed to send email verification",
+			logger.String("user_id", user.ID.String()),
+			logger.Error64("error", err),
+		)
+	}
+
+	return nil
+}
+
+// notifyAccountExists tells the owner of an address that someone tried
+// to sign up with it, with a link to set a password in case it was them.
+// Register answers the same as for a new account, so failures are only
+// logged.
+func (s *authService) notifyAccountExists(
+	ctx context.Context,
+	user *domain.User,
+) {
+	token, err := s.issueEmailToken(
+		ctx,
+		user,
+		domain.TokenTypePasswordReset,
+		s.passwordAuth.ResetTTL,
+	)
+	if err == nil {
+		err = s.mailer.SendAccountExists(
+			ctx,
+			user,
+			s.emailLink("reset-password", token),
+		)
+	}
+	if err != nil {
+		logger.Error(
+			"failed to send account exists email",
+			logger.String("user_id", user.ID.String()),
+			logger.Error64("error", err),
+		)
+	}
+}
+
+func (s *authService) LoginWithPassword(
+	ctx context.Context,
+	request domain.PasswordLoginRequest,
+	client domain.ClientInfo,
+) (*domain.AuthResponse, error) {
+	request.Email = normalizeEmail(request.Email)
+	if err := validator.Struct(request); err != nil {
+		return nil, customErrors.ErrInvalidCredentials
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, request.Email)
+	if err != nil {
+		if errors.Is(err, customErrors.ErrUserNotFound) {
+			hash.Verify(request.Password, dummyPasswordHash())
+			return nil, customErrors.ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if user.IsLocked(now) {
+		return nil, &customErrors.AccountLockedError{Until: *user.LockedUntil}
+	}
+
+	// Users of an identity provider have no password until they reset it
+	if user.Password == "" || !hash.Verify(request.Password, user.Password) {
+		return nil, s.failLogin(ctx, user, now)
+	}
+
+	if user.EmailVerifiedAt == nil {
+		return nil, customErrors.ErrEmailNotVerified
+	}
+
+	if !s.isAllowed(user.Email) {
+		return nil, customErrors.ErrUnauthorized
+	}
+
+	return s.completeLogin(ctx, user, grant{
+		provider:   passwordProvider,
+		providerID: user.ID.String(),
+		familyID:   uuid.New(),
+		client:     client,
+	}, "")
+}
+
+// failLogin records a wrong password and returns the error for it: the
+// attempt that locks the account reports the lockout.
+func (s *authService) failLogin(
+	ctx context.Context,
+	user *domain.User,
+	now time.Time,
+) error {
+	updated, err := s.userRepo.RecordFailedLogin(
+		ctx,
+		user.ID.String(),
+		s.passwordAuth.MaxFailedLogins,
+		now.Add(s.passwordAuth.LockoutDuration),
+	)
+	if err != nil {
+		return err
+	}
+
+	if updated.IsLocked(now) {
+		logger.Warn(
+			"security: account locked after repeated failed logins",
+			logger.String("user_id", user.ID.String()),
+		)
+		return &customErrors.AccountLockedError{Until: *updated.LockedUntil}
+	}
+	return customErrors.ErrInvalidCredentials
+}
+
+func (s *authService) VerifyEmail(ctx context.Context, token string) error {
+	t, err := s.tokenRepo.ConsumeToken(
+		ctx,
+		token,
+		domain.TokenTypeEmailVerification,
+	)
+	if err != nil {
+		if errors.Is(err, customErrors.ErrTokenNotFound) {
+			return customErrors.ErrInvalidToken
+		}
+		return err
+	}
+
+	return s.userRepo.MarkEmailVerified(ctx, t.UserID.String())
+}
+
+func (s *authService) ResendVerification(
+	ctx context.Context,
+	email string,
+) error {
+	user, err := s.userRepo.GetByEmail(ctx, normalizeEmail(email))
+	if err != nil {
+		if errors.Is(err, customErrors.ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if user.EmailVerifiedAt != nil {
+		return nil
+	}
+	return s.sendVerification(ctx, user)
+}
+
+func (s *authService) RequestPasswordReset(
+	ctx context.Context,
+	email string,
+) error {
+	user, err := s.userRepo.GetByEmail(ctx, normalizeEmail(email))
+	if err != nil {
+		if errors.Is(err, customErrors.ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	token, err := s.issueEmailToken(
+		ctx,
+		user,
+		domain.TokenTypePasswordReset,
+		s.passwordAuth.ResetTTL,
+	)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.SendPasswordReset(
+		ctx,
+		user,
+		s.emailLink("reset-password", token),
+	)
+}
+
+func (s *authService) ResetPassword(
+	ctx context.Context,
+	request domain.ResetPasswordRequest,
+) error {
+	if err := validator.Struct(request); err != nil {
+		return validationError(err)
+	}
+
+	passwordHash, err := hash.Generate(request.Password)
+	if err != nil {
+		return err
+	}
+
+	t, err := s.tokenRepo.ConsumeToken(
+		ctx,
+		request.Token,
+		domain.TokenTypePasswordReset,
+	)
+	if err != nil {
+		if errors.Is(err, customErrors.ErrTokenNotFound) {
+			return customErrors.ErrInvalidToken
+		}
+		return err
+	}
+
+	userID := t.UserID.String()
+	if err := s.userRepo.SetPassword(ctx, userID, passwordHash); err != nil {
+		return err
+	}
+
+	// Whoever knew the old password is signed out, along with any other
+	// outstanding reset links.
+	if _, err := s.tokenRepo.RevokeUserTokens(ctx, userID); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	return nil
+}
+
+func (s *authService) sendVerification(
+	ctx context.Context,
+	user *domain.User,
+) error {
+	token, err := s.issueEmailToken(
+		ctx,
+		user,
+		domain.TokenTypeEmailVerification,
+		s.passwordAuth.VerificationTTL,
+	)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.SendEmailVerification(
+		ctx,
+		user,
+		s.emailLink("verify-email", token),
+	)
+}
+
+// issueEmailToken stores a new single-use token of tokenType for the
+// user and returns it.
+func (s *authService) issueEmailToken(
+	ctx context.Context,
+	user *domain.User,
+	tokenType domain.TokenType,
+	ttl time.Duration,
+) (string, error) {
+	token, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	if err := s.tokenRepo.Create(ctx, &domain.Token{
+		UserID:     user.ID,
+		Token:      token,
+		Type:       tokenType,
+		FamilyID:   uuid.New(),
+		ExpiresAt:  now.Add(ttl),
+		Provider:   passwordProvider,
+		LastUsedAt: now,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}); err != nil {
+		return "", fmt.Errorf("failed to store %s token: %w", tokenType, err)
+	}
+
+	return token, nil
+}
+
+// emailLink points to the client app page at path that posts token back
+// to the API.
+func (s *authService) emailLink(path, token string) string {
+	return strings.TrimRight(s.passwordAuth.LinkBaseURL, "/") + "/" + path +
+		"?token=" + url.QueryEscape(token)
+}
+
+// isAllowed reports whether email may sign in. Any address may when no
+// allow list is configured.
+func (s *authService) isAllowed(email string) bool {
+	if len(s.allowedUsers) == 0 {
+		return true
+	}
+	for _, allowed := range s.allowedUsers {
+		if strings.EqualFold(email, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// validationError describes the fields of a request that failed
+// validation, in a stable order.
+func validationError(err error) error {
+	fields := validator.FormatError(err)
+	if len(fields) == 0 {
+		return fmt.Errorf("%w: %v", customErrors.ErrInvalidUserData, err)
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, name+": "+fields[name])
+	}
+	return fmt.Errorf(
+		"%w: %s",
+		customErrors.ErrInvalidUserData,
+		strings.Join(parts, "; "),
+	)
+}
@@ -0,0 +1,545 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grocery-service/internal/config"
+	"github.com/grocery-service/internal/domain"
+	repoMocks "github.com/grocery-service/tests/mocks/repository"
+	serviceMocks "github.com/grocery-service/tests/mocks/service"
+	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/grocery-service/utils/hash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testPasswordAuthConfig = config.PasswordAuthConfig{
+	MaxFailedLogins: 3,
+	LockoutDuration: 15 * time.Minute,
+	VerificationTTL: 48 * time.Hour,
+	ResetTTL:        time.Hour,
+	LinkBaseURL:     "https://shop.example.com/",
+}
+
+func newPasswordAuthService(
+	t *testing.T,
+	allowedUsers ...string,
+) (
+	AuthService,
+	*repoMocks.UserRepository,
+	*repoMocks.TokenRepository,
+	*serviceMocks.AccountMailer,
+) {
+	userRepo := repoMocks.NewUserRepository(t)
+	tokenRepo := repoMocks.NewTokenRepository(t)
+	mailer := serviceMocks.NewAccountMailer(t)
+
+	service, err := NewAuthService(
+		config.Config{JWT: testJWTConfig, PasswordAuth: testPasswordAuthConfig},
+		userRepo,
+		tokenRepo,
+		testRoleRepo(t),
+		repoMocks.NewMFARepository(t),
+		mailer,
+		allowedUsers,
+	)
+	require.NoError(t, err)
+
+	return service, userRepo, tokenRepo, mailer
+}
+
+// linkToken returns the token carried by an emailed link.
+func linkToken(t *testing.T, link string) string {
+	u, err := url.Parse(link)
+	require.NoError(t, err)
+	return u.Query().Get("token")
+}
+
+func passwordUser(t *testing.T, password string) *domain.User {
+	passwordHash, err := hash.Generate(password)
+	require.NoError(t, err)
+
+	verified := time.Now().Add(-time.Hour)
+	return &domain.User{
+		ID:              uuid.New(),
+		Email:           "jane@example.com",
+		Name:            "Jane",
+		Password:        passwordHash,
+		Role:            domain.CustomerRole,
+		EmailVerifiedAt: &verified,
+	}
+}
+
+func TestRegister(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		service, userRepo, tokenRepo, mailer := newPasswordAuthService(t)
+
+		userRepo.On("GetByEmail", ctx, "jane@example.com").
+			Return(nil, customErrors.ErrUserNotFound).Once()
+		userRepo.On("Create", ctx, mock.MatchedBy(func(u *domain.User) bool {
+			return u.Email == "jane@example.com" &&
+				u.Role == domain.CustomerRole &&
+				u.EmailVerifiedAt == nil &&
+				hash.Verify("correct-horse", u.Password)
+		})).Return(nil).Once()
+
+		var stored *domain.Token
+		tokenRepo.On("Create", ctx, mock.MatchedBy(func(tok *domain.Token) bool {
+			return tok.Type == domain.TokenTypeEmailVerification
+		})).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*domain.Token)
+		}).Return(nil).Once()
+
+		var user *domain.User
+		var link string
+		mailer.On("SendEmailVerification", ctx, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				user = args.Get(1).(*domain.User)
+				link = args.String(2)
+			}).Return(nil).Once()
+
+		err := service.Register(ctx, domain.RegisterRequest{
+			Email:    "  Jane@Example.com ",
+			Password: "correct-horse",
+			Name:     "Jane",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "jane@example.com", user.Email)
+		assert.True(t, strings.HasPrefix(
+			link,
+			"https://shop.example.com/verify-email?token=",
+		))
+		assert.Equal(t, stored.Token, linkToken(t, link))
+		assert.Equal(t, user.ID, stored.UserID)
+		assert.WithinDuration(
+			t,
+			time.Now().Add(testPasswordAuthConfig.VerificationTTL),
+			stored.ExpiresAt,
+			time.Minute,
+		)
+	})
+
+	t.Run("Success - Email Failure Is Not Fatal", func(t *testing.T) {
+		service, userRepo, tokenRepo, mailer := newPasswordAuthService(t)
+
+		userRepo.On("GetByEmail", ctx, "jane@example.com").
+			Return(nil, customErrors.ErrUserNotFound).Once()
+		userRepo.On("Create", ctx, mock.Anything).Return(nil).Once()
+		tokenRepo.On("Create", ctx, mock.Anything).Return(nil).Once()
+		mailer.On("SendEmailVerification", ctx, mock.Anything, mock.Anything).
+			Return(errors.New("smtp unavailable")).Once()
+
+		err := service.Register(ctx, domain.RegisterRequest{
+			Email:    "jane@example.com",
+			Password: "correct-horse",
+			Name:     "Jane",
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Success - Existing Account Emails Its Owner", func(t *testing.T) {
+		service, userRepo, tokenRepo, mailer := newPasswordAuthService(t)
+		owner := passwordUser(t, "old-password")
+
+		userRepo.On("GetByEmail", ctx, "jane@example.com").
+			Return(owner, nil).Once()
+
+		var stored *domain.Token
+		tokenRepo.On("Create", ctx, mock.MatchedBy(func(tok *domain.Token) bool {
+			return tok.Type == domain.TokenTypePasswordReset
+		})).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*domain.Token)
+		}).Return(nil).Once()
+
+		var link string
+		mailer.On("SendAccountExists", ctx, owner, mock.Anything).
+			Run(func(args mock.Arguments) {
+				link = args.String(2)
+			}).Return(nil).Once()
+
+		err := service.Register(ctx, domain.RegisterRequest{
+			Email:    "jane@example.com",
+			Password: "correct-horse",
+			Name:     "Mallory",
+		})
+
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(
+			link,
+			"https://shop.example.com/reset-password?token=",
+		))
+		assert.Equal(t, stored.Token, linkToken(t, link))
+		assert.Equal(t, owner.ID, stored.UserID)
+	})
+
+	t.Run("Success - Existing Account Email Failure Is Not Fatal", func(t *testing.T) {
+		service, userRepo, tokenRepo, mailer := newPasswordAuthService(t)
+
+		userRepo.On("GetByEmail", ctx, "jane@example.com").
+			Return(passwordUser(t, "old-password"), nil).Once()
+		tokenRepo.On("Create", ctx, mock.Anything).Return(nil).Once()
+		mailer.On("SendAccountExists", ctx, mock.Anything, mock.Anything).
+			Return(errors.New("smtp unavailable")).Once()
+
+		err := service.Register(ctx, domain.RegisterRequest{
+			Email:    "jane@example.com",
+			Password: "correct-horse",
+			Name:     "Jane",
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Error - Short Password", func(t *testing.T) {
+		service, _, _, _ := newPasswordAuthService(t)
+
+		err := service.Register(ctx, domain.RegisterRequest{
+			Email:    "jane@example.com",
+			Password: "short",
+			Name:     "Jane",
+		})
+
+		assert.ErrorIs(t, err, customErrors.ErrInvalidUserData)
+		assert.Contains(t, err.Error(), "password")
+	})
+
+	t.Run("Error - Not Allowed", func(t *testing.T) {
+		service, _, _, _ := newPasswordAuthService(t, "boss@example.com")
+
+		err := service.Register(ctx, domain.RegisterRequest{
+			Email:    "jane@example.com",
+			Password: "correct-horse",
+			Name:     "Jane",
+		})
+
+		assert.ErrorIs(t, err, customErrors.ErrUnauthorized)
+	})
+}
+
+func TestLoginWithPassword(t *testing.T) {
+	ctx := context.Background()
+	client := domain.ClientInfo{IPAddress: "203.0.113.7", UserAgent: "test"}
+	login := domain.PasswordLoginRequest{
+		Email:    "jane@example.com",
+		Password: "correct-horse",
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		service, userRepo, tokenRepo, _ := newPasswordAuthService(t)
+		user := passwordUser(t, "correct-horse")
+		user.FailedLoginAttempts = 2
+
+		userRepo.On("GetByEmail", ctx, "jane@example.com").
+			Return(user, nil).Once()
+		userRepo.On("ResetFailedLogins", ctx, user.ID.String()).
+			Return(nil).Once()
+		tokenRepo.On("Create", ctx, mock.MatchedBy(func(tok *domain.Token) bool {
+			return tok.Type == domain.TokenTypeRefresh &&
+				tok.Provider == "password" &&
+				tok.IPAddress == client.IPAddress
+		})).Return(nil).Once()
+		tokenRepo.On("Create", ctx, mock.MatchedBy(func(tok *domain.Token) bool {
+			return tok.Type == domain.TokenTypeAccess
+		})).Return(nil).Once()
+
+		response, err := service.LoginWithPassword(ctx, login, client)
+
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, response.User.ID)
+		assert.NotEmpty(t, response.AccessToken)
+		assert.NotEmpty(t, response.RefreshToken)
+	})
+
+	t.Run("Error - Wrong Password", func(t *testing.T) {
+		service, userRepo, _, _ := newPasswordAuthService(t)
+		user := passwordUser(t, "correct-horse")
+
+		userRepo.On("GetByEmail", ctx, "jane@example.com").
+			Return(user, nil).Once()
+		userRepo.On(
+			"RecordFailedLogin",
+			ctx,
+			user.ID.String(),
+			3,
+			mock.AnythingOfType("time.Time"),
+		).Return(&domain.User{ID: user.ID, FailedLoginAttempts: 1}, nil).Once()
+
+		_, err := service.LoginWithPassword(ctx, domain.PasswordLoginRequest{
+			Email:    "jane@example.com",
+			Password: "wrong-horse",
+		}, client)
+
+		assert.ErrorIs(t, err, customErrors.ErrInvalidCredentials)
+	})
+
+	t.Run("Error - Wrong Password Locks Account", func(t *testing.T) {
+		service, userRepo, _, _ := newPasswordAuthService(t)
+		user := passwordUser(t, "correct-horse")
+		until := time.Now().Add(testPasswordAuthConfig.LockoutDuration)
+
+		userRepo.On("GetByEmail", ctx, "jane@example.com").
+			Return(user, nil).Once()
+		userRepo.On(
+			"RecordFailedLogin",
+			ctx,
+			user.ID.String(),
+			3,
+			mock.AnythingOfType("time.Time"),
+		).Return(&domain.User{ID: user.ID, LockedUntil: &until}, nil).Once()
+
+		_, err := service.LoginWithPassword(ctx, domain.PasswordLoginRequest{
+			Email:    "jane@example.com",
+			Password: "wrong-horse",
+		}, client)
+
+		var locked *customErrors.AccountLockedError
+		require.ErrorAs(t, err, &locked)
+		assert.Equal(t, until, locked.Until)
+		assert.ErrorIs(t, err, customErrors.ErrAccountLocked)
+	})
+
+	t.Run("Error - Locked", func(t *testing.T) {
+		service, userRepo, _, _ := newPasswordAuthService(t)
+		user := passwordUser(t, "correct-horse")
+		until := time.Now().Add(time.Minute)
+		user.LockedUntil = &until
+
+		userRepo.On("GetByEmail", ctx, "jane@example.com").
+			Return(user, nil).Once()
+
+		_, err := service.LoginWithPassword(ctx, login, client)
+
+		assert.ErrorIs(t, err, customErrors.ErrAccountLocked)
+	})
+
+	t.Run("Error - Not Verified", func(t *testing.T) {
+		service, userRepo, _, _ := newPasswordAuthService(t)
+		user := passwordUser(t, "correct-horse")
+		user.EmailVerifiedAt = nil
+
+		userRepo.On("GetByEmail", ctx, "jane@example.com").
+			Return(user, nil).Once()
+
+		_, err := service.LoginWithPassword(ctx, login, client)
+
+		assert.ErrorIs(t, err, customErrors.ErrEmailNotVerified)
+	})
+
+	t.Run("Error - No Password Set", func(t *testing.T) {
+		service, userRepo, _, _ := newPasswordAuthService(t)
+		user := passwordUser(t, "correct-horse")
+		user.Password = ""
+
+		userRepo.On("GetByEmail", ctx, "jane@example.com").
+			Return(user, nil).Once()
+		userRepo.On(
+			"RecordFailedLogin",
+			ctx,
+			user.ID.String(),
+			3,
+			mock.AnythingOfType("time.Time"),
+		).Return(&domain.User{ID: user.ID}, nil).Once()
+
+		_, err := service.LoginWithPassword(ctx, login, client)
+
+		assert.ErrorIs(t, err, customErrors.ErrInvalidCredentials)
+	})
+
+	t.Run("Error - Unknown Email", func(t *testing.T) {
+		service, userRepo, _, _ := newPasswordAuthService(t)
+
+		userRepo.On("GetByEmail", ctx, "jane@example.com").
+			Return(nil, customErrors.ErrUserNotFound).Once()
+
+		_, err := service.LoginWithPassword(ctx, login, client)
+
+		assert.ErrorIs(t, err, customErrors.ErrInvalidCredentials)
+	})
+}
+
+func TestVerifyEmail(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		service, userRepo, tokenRepo, _ := newPasswordAuthService(t)
+		userID := uuid.New()
+
+		tokenRepo.On(
+			"ConsumeToken",
+			ctx,
+			"verify-token",
+			domain.TokenTypeEmailVerification,
+		).Return(&domain.Token{UserID: userID}, nil).Once()
+		userRepo.On("MarkEmailVerified", ctx, userID.String()).
+			Return(nil).Once()
+
+		assert.NoError(t, service.VerifyEmail(ctx, "verify-token"))
+	})
+
+	t.Run("Error - Used Or Expired", func(t *testing.T) {
+		service, _, tokenRepo, _ := newPasswordAuthService(t)
+
+		tokenRepo.On(
+			"ConsumeToken",
+			ctx,
+			"verify-token",
+			domain.TokenTypeEmailVerification,
+		).Return(nil, customErrors.ErrTokenNotFound).Once()
+
+		err := service.VerifyEmail(ctx, "verify-token")
+
+		assert.ErrorIs(t, err, customErrors.ErrInvalidToken)
+	})
+}
+
+func TestResendVerification(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Unverified", func(t *testing.T) {
+		service, userRepo, tokenRepo, mailer := newPasswordAuthService(t)
+		user := passwordUser(t, "correct-horse")
+		user.EmailVerifiedAt = nil
+
+		userRepo.On("GetByEmail", ctx, "jane@example.com").
+			Return(user, nil).Once()
+		tokenRepo.On("Create", ctx, mock.Anything).Return(nil).Once()
+		mailer.On("SendEmailVerification", ctx, user, mock.Anything).
+			Return(nil).Once()
+
+		assert.NoError(t, service.ResendVerification(ctx, "jane@example.com"))
+	})
+
+	t.Run("Already Verified", func(t *testing.T) {
+		service, userRepo, _, _ := newPasswordAuthService(t)
+
+		userRepo.On("GetByEmail", ctx, "jane@example.com").
+			Return(passwordUser(t, "correct-horse"), nil).Once()
+
+		assert.NoError(t, service.ResendVerification(ctx, "jane@example.com"))
+	})
+
+	t.Run("Unknown Email", func(t *testing.T) {
+		service, userRepo, _, _ := newPasswordAuthService(t)
+
+		userRepo.On("GetByEmail", ctx, "ghost@example.com").
+			Return(nil, customErrors.ErrUserNotFound).Once()
+
+		assert.NoError(t, service.ResendVerification(ctx, "ghost@example.com"))
+	})
+}
+
+func TestPasswordReset(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Request", func(t *testing.T) {
+		service, userRepo, tokenRepo, mailer := newPasswordAuthService(t)
+		user := passwordUser(t, "correct-horse")
+
+		userRepo.On("GetByEmail", ctx, "jane@example.com").
+			Return(user, nil).Once()
+
+		var stored *domain.Token
+		tokenRepo.On("Create", ctx, mock.MatchedBy(func(tok *domain.Token) bool {
+			return tok.Type == domain.TokenTypePasswordReset
+		})).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*domain.Token)
+		}).Return(nil).Once()
+
+		var link string
+		mailer.On("SendPasswordReset", ctx, user, mock.Anything).
+			Run(func(args mock.Arguments) {
+				link = args.String(2)
+			}).Return(nil).Once()
+
+		require.NoError(t, service.RequestPasswordReset(ctx, "Jane@example.com"))
+		assert.True(t, strings.HasPrefix(
+			link,
+			"https://shop.example.com/reset-password?token=",
+		))
+		assert.Equal(t, stored.Token, linkToken(t, link))
+		assert.WithinDuration(
+			t,
+			time.Now().Add(testPasswordAuthConfig.ResetTTL),
+			stored.ExpiresAt,
+			time.Minute,
+		)
+	})
+
+	t.Run("Request - Unknown Email", func(t *testing.T) {
+		service, userRepo, _, _ := newPasswordAuthService(t)
+
+		userRepo.On("GetByEmail", ctx, "ghost@example.com").
+			Return(nil, customErrors.ErrUserNotFound).Once()
+
+		assert.NoError(t, service.RequestPasswordReset(ctx, "ghost@example.com"))
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		service, userRepo, tokenRepo, _ := newPasswordAuthService(t)
+		userID := uuid.New()
+
+		tokenRepo.On(
+			"ConsumeToken",
+			ctx,
+			"reset-token",
+			domain.TokenTypePasswordReset,
+		).Return(&domain.Token{UserID: userID}, nil).Once()
+		userRepo.On(
+			"SetPassword",
+			ctx,
+			userID.String(),
+			mock.MatchedBy(func(passwordHash string) bool {
+				return hash.Verify("battery-staple", passwordHash)
+			}),
+		).Return(nil).Once()
+		tokenRepo.On("RevokeUserTokens", ctx, userID.String()).
+			Return(int64(3), nil).Once()
+
+		err := service.ResetPassword(ctx, domain.ResetPasswordRequest{
+			Token:    "reset-token",
+			Password: "battery-staple",
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Reset - Used Or Expired", func(t *testing.T) {
+		service, _, tokenRepo, _ := newPasswordAuthService(t)
+
+		tokenRepo.On(
+			"ConsumeToken",
+			ctx,
+			"reset-token",
+			domain.TokenTypePasswordReset,
+		).Return(nil, customErrors.ErrTokenNotFound).Once()
+
+		err := service.ResetPassword(ctx, domain.ResetPasswordRequest{
+			Token:    "reset-token",
+			Password: "battery-staple",
+		})
+
+		assert.ErrorIs(t, err, customErrors.ErrInvalidToken)
+	})
+
+	t.Run("Reset - Weak Password", func(t *testing.T) {
+		service, _, _, _ := newPasswordAuthService(t)
+
+		err := service.ResetPassword(ctx, domain.ResetPasswordRequest{
+			Token:    "reset-token",
+			Password: "short",
+		})
+
+		assert.ErrorIs(t, err, customErrors.ErrInvalidUserData)
+	})
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/grocery-service/internal/domain"
@@ -14,14 +15,27 @@ type (
 	ProductService interface {
 		Create(ctx context.Context, product *domain.Product) error
 		GetByID(ctx context.Context, id string) (*domain.Product, error)
-		List(ctx context.Context) ([]domain.Product, error)
+		List(
+			ctx context.Context,
+			query domain.ListQuery,
+		) (*domain.Page[domain.Product], error)
 		ListByCategoryID(
 			ctx context.Context,
 			categoryID string,
-		) ([]domain.Product, error)
+			query domain.ListQuery,
+		) (*domain.Page[domain.Product], error)
+		Search(
+			ctx context.Context,
+			term string,
+			query domain.ListQuery,
+		) (*domain.Page[domain.ProductSearchResult], error)
 		Update(ctx context.Context, product *domain.Product) error
 		Delete(ctx context.Context, id string) error
-		UpdateStock(ctx context.Context, id string, quantity int) error
+		// UpdateStock applies a stock movement to a product. The sign of
+		// the quantity follows the movement type: sales and spoilage
+		// remove stock, restocks and returns add it, and adjustments are
+		// taken as given.
+		UpdateStock(ctx context.Context, movement *domain.StockMovement) error
 	}
 
 	ProductServiceImpl struct {
@@ -30,6 +44,8 @@ type (
 	}
 )
 
+const maxSearchTermLength = 200
+
 func NewProductService(
 	repo repository.ProductRepository,
 	categoryRepo repository.CategoryRepository,
@@ -73,21 +89,55 @@ func (s *ProductServiceImpl) GetByID(
 
 func (s *ProductServiceImpl) List(
 	ctx context.Context,
-) ([]domain.Product, error) {
-	return s.repo.List(ctx)
+	query domain.ListQuery,
+) (*domain.Page[domain.Product], error) {
+	return s.repo.List(ctx, query)
 }
 
 func (s *ProductServiceImpl) ListByCategoryID(
 	ctx context.Context,
 	categoryID string,
-) ([]domain.Product, error) {
+	query domain.ListQuery,
+) (*domain.Page[domain.Product], error) {
 	if categoryID == "" {
 		return nil, fmt.Errorf(
 			"%w: category ID is required",
 			customErrors.ErrInvalidProductData,
 		)
 	}
-	return s.repo.ListByCategoryID(ctx, categoryID)
+	return s.repo.ListByCategoryID(ctx, categoryID, query)
+}
+
+func (s *ProductServiceImpl) Search(
+	ctx context.Context,
+	term string,
+	query domain.ListQuery,
+) (*domain.Page[domain.ProductSearchResult], error) {
+	term = strings.TrimSpace(term)
+	if term == "" {
+		return nil, fmt.Errorf(
+			"%w: search query is required",
+			customErrors.ErrInvalidListQuery,
+		)
+	}
+	if len(term) > maxSearchTermLength {
+		return nil, fmt.Errorf(
+			"%w: search query cannot exceed %d characters",
+			customErrors.ErrInvalidListQuery,
+			maxSearchTermLength,
+		)
+	}
+
+	if query.Filter.CategoryID != nil {
+		if _, err := s.categoryRepo.GetByID(
+			ctx,
+			query.Filter.CategoryID.String(),
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repo.Search(ctx, term, query)
 }
 
 func (s *ProductServiceImpl) Update(
@@ -128,26 +178,37 @@ func (s *ProductServiceImpl) Delete(
 
 func (s *ProductServiceImpl) UpdateStock(
 	ctx context.Context,
-	id string,
-	quantity int,
+	movement *domain.StockMovement,
 ) error {
-	if id == "" {
+	if movement.ProductID == uuid.Nil {
 		return fmt.Errorf(
 			"%w: product ID is required",
 			customErrors.ErrInvalidProductData,
 		)
 	}
 
-	if quantity < 0 {
+	switch movement.Type {
+	case domain.StockMovementSale, domain.StockMovementSpoilage:
+		if movement.Quantity > 0 {
+			movement.Quantity = -movement.Quantity
+		}
+	case domain.StockMovementRestock, domain.StockMovementReturn:
+		if movement.Quantity < 0 {
+			movement.Quantity = -movement.Quantity
+		}
+	}
+
+	if err := movement.Validate(); err != nil {
 		return fmt.Errorf(
-			"%w: stock quantity cannot be negative",
+			"%w: %v",
 			customErrors.ErrInvalidProductData,
+			err,
 		)
 	}
 
-	if _, err := s.repo.GetByID(ctx, id); err != nil {
+	if _, err := s.repo.GetByID(ctx, movement.ProductID.String()); err != nil {
 		return fmt.Errorf("failed to get product: %w", err)
 	}
 
-	return s.repo.UpdateStock(ctx, id, quantity)
+	return s.repo.UpdateStock(ctx, movement)
 }
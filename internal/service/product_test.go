@@ -9,6 +9,7 @@ import (
 	mocks "github.com/grocery-service/tests/mocks/repository"
 	customErrors "github.com/grocery-service/utils/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestProductService_Create(t *testing.T) {
@@ -20,7 +21,7 @@ func TestProductService_Create(t *testing.T) {
 	product := &domain.Product{
 		ID:         uuid.New(),
 		Name:       "Test Product",
-		Price:      10.99,
+		Price:      domain.NewMoney(1099, domain.DefaultCurrency),
 		Stock:      100,
 		CategoryID: uuid.New(),
 	}
@@ -44,7 +45,7 @@ func TestProductService_Create(t *testing.T) {
 			name: "Empty name",
 			product: &domain.Product{
 				ID:    uuid.New(),
-				Price: 10.99,
+				Price: domain.NewMoney(1099, domain.DefaultCurrency),
 				Stock: 100,
 			},
 			errMsg: "product name is required",
@@ -54,7 +55,7 @@ func TestProductService_Create(t *testing.T) {
 			product: &domain.Product{
 				ID:    uuid.New(),
 				Name:  "Test",
-				Price: 0,
+				Price: domain.NewMoney(0, domain.DefaultCurrency),
 				Stock: 100,
 			},
 			errMsg: "product price must be greater than zero",
@@ -64,7 +65,7 @@ func TestProductService_Create(t *testing.T) {
 			product: &domain.Product{
 				ID:    uuid.New(),
 				Name:  "Test",
-				Price: -10,
+				Price: domain.NewMoney(-1000, domain.DefaultCurrency),
 				Stock: 100,
 			},
 			errMsg: "product price must be greater than zero",
@@ -74,7 +75,7 @@ func TestProductService_Create(t *testing.T) {
 			product: &domain.Product{
 				ID:    uuid.New(),
 				Name:  "Test",
-				Price: 10.99,
+				Price: domain.NewMoney(1099, domain.DefaultCurrency),
 				Stock: -1,
 			},
 			errMsg: "product stock cannot be negative",
@@ -99,7 +100,7 @@ func TestProductService_GetByID(t *testing.T) {
 	product := &domain.Product{
 		ID:         uuid.New(),
 		Name:       "Test Product",
-		Price:      10.99,
+		Price:      domain.NewMoney(1099, domain.DefaultCurrency),
 		Stock:      100,
 		CategoryID: uuid.New(),
 	}
@@ -127,22 +128,29 @@ func TestProductService_List(t *testing.T) {
 		{
 			ID:    uuid.New(),
 			Name:  "Product 1",
-			Price: 10.99,
+			Price: domain.NewMoney(1099, domain.DefaultCurrency),
 			Stock: 100,
 		},
 		{
 			ID:    uuid.New(),
 			Name:  "Product 2",
-			Price: 20.99,
+			Price: domain.NewMoney(2099, domain.DefaultCurrency),
 			Stock: 200,
 		},
 	}
 
-	mockProductRepo.On("List", ctx).Return(products, nil)
+	query := domain.ListQuery{Limit: 10, SortBy: "price"}
+	mockProductRepo.On("List", ctx, query).Return(&domain.Page[domain.Product]{
+		Items: products,
+		Total: int64(len(products)),
+		Limit: 10,
+		Page:  1,
+	}, nil)
 
-	found, err := service.List(ctx)
+	found, err := service.List(ctx, query)
 	assert.NoError(t, err)
-	assert.Len(t, found, len(products))
+	assert.Len(t, found.Items, len(products))
+	assert.Equal(t, int64(len(products)), found.Total)
 }
 
 func TestProductService_ListByCategoryID(t *testing.T) {
@@ -156,25 +164,98 @@ func TestProductService_ListByCategoryID(t *testing.T) {
 		{
 			ID:         uuid.New(),
 			Name:       "Product 1",
-			Price:      10.99,
+			Price:      domain.NewMoney(1099, domain.DefaultCurrency),
 			Stock:      100,
 			CategoryID: categoryID,
 		},
 		{
 			ID:         uuid.New(),
 			Name:       "Product 2",
-			Price:      20.99,
+			Price:      domain.NewMoney(2099, domain.DefaultCurrency),
 			Stock:      200,
 			CategoryID: categoryID,
 		},
 	}
 
-	mockProductRepo.On("ListByCategoryID", ctx, categoryID.String()).
-		Return(products, nil)
+	query := domain.ListQuery{Limit: 1}
+	mockProductRepo.On("ListByCategoryID", ctx, categoryID.String(), query).
+		Return(&domain.Page[domain.Product]{
+			Items:      products[:1],
+			Total:      int64(len(products)),
+			Limit:      1,
+			Page:       1,
+			NextCursor: "next",
+		}, nil)
 
-	found, err := service.ListByCategoryID(ctx, categoryID.String())
+	found, err := service.ListByCategoryID(ctx, categoryID.String(), query)
 	assert.NoError(t, err)
-	assert.Len(t, found, len(products))
+	assert.Len(t, found.Items, 1)
+	assert.Equal(t, int64(len(products)), found.Total)
+}
+
+func TestProductService_Search(t *testing.T) {
+	categoryID := uuid.New()
+
+	tests := []struct {
+		name          string
+		term          string
+		query         domain.ListQuery
+		setupMocks    func(*mocks.ProductRepository, *mocks.CategoryRepository)
+		expectedError error
+	}{
+		{
+			name:  "Success - Trims Search Term",
+			term:  "  apple  ",
+			query: domain.ListQuery{Filter: domain.ListFilter{CategoryID: &categoryID}},
+			setupMocks: func(pr *mocks.ProductRepository, cr *mocks.CategoryRepository) {
+				cr.On("GetByID", mock.Anything, categoryID.String()).
+					Return(&domain.Category{ID: categoryID}, nil)
+				pr.On("Search", mock.Anything, "apple", mock.Anything).
+					Return(&domain.Page[domain.ProductSearchResult]{
+						Items: []domain.ProductSearchResult{{
+							Product:       domain.Product{ID: uuid.New(), Name: "Apple"},
+							NameHighlight: "<mark>Apple</mark>",
+						}},
+						Total: 1,
+					}, nil)
+			},
+		},
+		{
+			name:          "Error - Empty Search Term",
+			term:          "   ",
+			setupMocks:    func(_ *mocks.ProductRepository, _ *mocks.CategoryRepository) {},
+			expectedError: customErrors.ErrInvalidListQuery,
+		},
+		{
+			name:  "Error - Unknown Category",
+			term:  "apple",
+			query: domain.ListQuery{Filter: domain.ListFilter{CategoryID: &categoryID}},
+			setupMocks: func(_ *mocks.ProductRepository, cr *mocks.CategoryRepository) {
+				cr.On("GetByID", mock.Anything, categoryID.String()).
+					Return(nil, customErrors.ErrCategoryNotFound)
+			},
+			expectedError: customErrors.ErrCategoryNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockProductRepo := mocks.NewProductRepository(t)
+			mockCategoryRepo := mocks.NewCategoryRepository(t)
+			service := NewProductService(mockProductRepo, mockCategoryRepo)
+			tt.setupMocks(mockProductRepo, mockCategoryRepo)
+
+			results, err := service.Search(context.Background(), tt.term, tt.query)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, results)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, results.Items, 1)
+			}
+		})
+	}
 }
 
 func TestProductService_Update(t *testing.T) {
@@ -186,7 +267,7 @@ func TestProductService_Update(t *testing.T) {
 	product := &domain.Product{
 		ID:         uuid.New(),
 		Name:       "Test Product",
-		Price:      10.99,
+		Price:      domain.NewMoney(1099, domain.DefaultCurrency),
 		Stock:      100,
 		CategoryID: uuid.New(),
 	}
@@ -210,20 +291,55 @@ func TestProductService_UpdateStock(t *testing.T) {
 	service := NewProductService(mockProductRepo, mockCategoryRepo)
 	ctx := context.Background()
 
-	productID := uuid.New().String()
+	productID := uuid.New()
 	product := &domain.Product{
-		ID:    uuid.MustParse(productID),
+		ID:    productID,
 		Name:  "Test Product",
 		Stock: 100,
 	}
-	mockProductRepo.On("GetByID", ctx, productID).Return(product, nil)
-	mockProductRepo.On("UpdateStock", ctx, productID, 50).Return(nil)
-	err := service.UpdateStock(ctx, productID, 50)
+	mockProductRepo.On("GetByID", ctx, productID.String()).Return(product, nil)
+	mockProductRepo.On("UpdateStock", ctx, mock.Anything).Return(nil)
+
+	restock := domain.NewStockMovement(
+		productID,
+		domain.StockMovementRestock,
+		50,
+	)
+	err := service.UpdateStock(ctx, restock)
 	assert.NoError(t, err)
-
-	err = service.UpdateStock(ctx, productID, -1)
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "quantity cannot be negative")
+	assert.Equal(t, 50, restock.Quantity)
+
+	spoilage := domain.NewStockMovement(
+		productID,
+		domain.StockMovementSpoilage,
+		5,
+	)
+	err = service.UpdateStock(ctx, spoilage)
+	assert.NoError(t, err)
+	assert.Equal(t, -5, spoilage.Quantity)
+
+	err = service.UpdateStock(ctx, domain.NewStockMovement(
+		productID,
+		domain.StockMovementAdjustment,
+		0,
+	))
+	assert.ErrorIs(t, err, customErrors.ErrInvalidProductData)
+
+	err = service.UpdateStock(ctx, domain.NewStockMovement(
+		productID,
+		"GIFT",
+		1,
+	))
+	assert.ErrorIs(t, err, customErrors.ErrInvalidProductData)
+
+	err = service.UpdateStock(ctx, domain.NewStockMovement(
+		uuid.Nil,
+		domain.StockMovementRestock,
+		1,
+	))
+	assert.ErrorIs(t, err, customErrors.ErrInvalidProductData)
+
+	mockProductRepo.AssertNumberOfCalls(t, "UpdateStock", 2)
 }
 
 func TestProductService_Delete(t *testing.T) {
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/grocery-service/internal/domain"
+	repository "github.com/grocery-service/internal/repository/postgres"
+	customErrors "github.com/grocery-service/utils/errors"
+)
+
+type (
+	// RoleService manages roles and the permissions they grant. A
+	// change reaches a user's access tokens the next time they are
+	// issued, at most JWT_TOKEN_DURATION later.
+	RoleService interface {
+		ListPermissions() []domain.PermissionInfo
+		List(ctx context.Context) ([]domain.Role, error)
+		GetByName(ctx context.Context, name string) (*domain.Role, error)
+		Create(
+			ctx context.Context,
+			request domain.RoleRequest,
+		) (*domain.Role, error)
+		Update(
+			ctx context.Context,
+			name string,
+			request domain.RoleRequest,
+		) (*domain.Role, error)
+		Delete(ctx context.Context, name string) error
+		// AssignRole gives a user a role on behalf of actorID, who may
+		// not change their own role.
+		AssignRole(
+			ctx context.Context,
+			actorID string,
+			userID string,
+			role domain.UserRole,
+		) error
+	}
+
+	RoleServiceImpl struct {
+		repo repository.RoleRepository
+	}
+)
+
+func NewRoleService(repo repository.RoleRepository) RoleService {
+	return &RoleServiceImpl{repo: repo}
+}
+
+func (s *RoleServiceImpl) ListPermissions() []domain.PermissionInfo {
+	return domain.AllPermissions()
+}
+
+func (s *RoleServiceImpl) List(ctx context.Context) ([]domain.Role, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *RoleServiceImpl) GetByName(
+	ctx context.Context,
+	name string,
+) (*domain.Role, error) {
+	return s.repo.GetByName(ctx, domain.UserRole(name))
+}
+
+func (s *RoleServiceImpl) Create(
+	ctx context.Context,
+	request domain.RoleRequest,
+) (*domain.Role, error) {
+	if err := domain.ValidateRoleName(request.Name); err != nil {
+		return nil, fmt.Errorf("%w: %v", customErrors.ErrInvalidRoleData, err)
+	}
+
+	permissions, err := uniquePermissions(
+		request.Permissions,
+		customErrors.ErrInvalidRoleData,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	role := &domain.Role{
+		Name:        request.Name,
+		Description: request.Description,
+		Permissions: permissions,
+	}
+	if err := s.repo.Create(ctx, role); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByName(ctx, role.Name)
+}
+
+func (s *RoleServiceImpl) Update(
+	ctx context.Context,
+	name string,
+	request domain.RoleRequest,
+) (*domain.Role, error) {
+	if domain.UserRole(name) == domain.AdminRole {
+		return nil, fmt.Errorf(
+			"%w: the admin role always holds every permission",
+			customErrors.ErrBuiltInRole,
+		)
+	}
+
+	permissions, err := uniquePermissions(
+		request.Permissions,
+		customErrors.ErrInvalidRoleData,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	role := &domain.Role{
+		Name:        domain.UserRole(name),
+		Description: request.Description,
+		Permissions: permissions,
+	}
+	if err := s.repo.Update(ctx, role); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByName(ctx, role.Name)
+}
+
+func (s *RoleServiceImpl) Delete(ctx context.Context, name string) error {
+	role, err := s.repo.GetByName(ctx, domain.UserRole(name))
+	if err != nil {
+		return err
+	}
+
+	if role.BuiltIn {
+		return fmt.Errorf(
+			"%w: built-in roles cannot be deleted",
+			customErrors.ErrBuiltInRole,
+		)
+	}
+
+	return s.repo.Delete(ctx, role.Name)
+}
+
+func (s *RoleServiceImpl) AssignRole(
+	ctx context.Context,
+	actorID string,
+	userID string,
+	role domain.UserRole,
+) error {
+	if _, err := uuid.Parse(userID); err != nil {
+		return customErrors.ErrUserNotFound
+	}
+
+	// Keeps the last admin from locking everyone out by accident.
+	if actorID == userID {
+		return fmt.Errorf(
+			"%w: you cannot change your own role",
+			customErrors.ErrForbidden,
+		)
+	}
+
+	return s.repo.AssignRole(ctx, userID, role)
+}
+
+// uniquePermissions drops duplicate permissions, failing with invalid
+// when one is unknown.
+func uniquePermissions(
+	permissions []domain.Permission,
+	invalid error,
+) ([]domain.Permission, error) {
+	if err := domain.ValidatePermissions(permissions); err != nil {
+		return nil, fmt.Errorf("%w: %v", invalid, err)
+	}
+
+	seen := make(map[domain.Permission]bool, len(permissions))
+	unique := make([]domain.Permission, 0, len(permissions))
+	for _, p := range permissions {
+		if !seen[p] {
+			seen[p] = true
+			unique = append(unique, p)
+		}
+	}
+	return unique, nil
+}
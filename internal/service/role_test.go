@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/grocery-service/internal/domain"
+	repoMocks "github.com/grocery-service/tests/mocks/repository"
+	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoleService_Create(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success - Duplicate Permissions Dropped", func(t *testing.T) {
+		repo := repoMocks.NewRoleRepository(t)
+		service := NewRoleService(repo)
+
+		want := &domain.Role{
+			Name:        "stock-clerk",
+			Description: "Counts stock",
+			Permissions: []domain.Permission{
+				domain.PermissionInventoryRead,
+				domain.PermissionInventoryWrite,
+			},
+		}
+		repo.On("Create", ctx, want).Return(nil).Once()
+		repo.On("GetByName", ctx, domain.UserRole("stock-clerk")).
+			Return(want, nil).Once()
+
+		role, err := service.Create(ctx, domain.RoleRequest{
+			Name:        "stock-clerk",
+			Description: "Counts stock",
+			Permissions: []domain.Permission{
+				domain.PermissionInventoryRead,
+				domain.PermissionInventoryWrite,
+				domain.PermissionInventoryRead,
+			},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, want, role)
+	})
+
+	tests := []struct {
+		name    string
+		request domain.RoleRequest
+	}{
+		{
+			name:    "Error - Invalid Name",
+			request: domain.RoleRequest{Name: "Stock Clerk"},
+		},
+		{
+			name:    "Error - Empty Name",
+			request: domain.RoleRequest{},
+		},
+		{
+			name: "Error - Unknown Permission",
+			request: domain.RoleRequest{
+				Name:        "stock-clerk",
+				Permissions: []domain.Permission{"stock:steal"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewRoleService(repoMocks.NewRoleRepository(t))
+
+			_, err := service.Create(ctx, tt.request)
+
+			assert.ErrorIs(t, err, customErrors.ErrInvalidRoleData)
+		})
+	}
+}
+
+func TestRoleService_Update(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		repo := repoMocks.NewRoleRepository(t)
+		service := NewRoleService(repo)
+
+		want := &domain.Role{
+			Name:        domain.DriverRole,
+			Permissions: []domain.Permission{domain.PermissionOrdersRead},
+		}
+		repo.On("Update", ctx, want).Return(nil).Once()
+		repo.On("GetByName", ctx, domain.DriverRole).Return(want, nil).Once()
+
+		role, err := service.Update(ctx, "driver", domain.RoleRequest{
+			Permissions: []domain.Permission{domain.PermissionOrdersRead},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, want, role)
+	})
+
+	t.Run("Error - Admin Role", func(t *testing.T) {
+		service := NewRoleService(repoMocks.NewRoleRepository(t))
+
+		_, err := service.Update(ctx, "admin", domain.RoleRequest{})
+
+		assert.ErrorIs(t, err, customErrors.ErrBuiltInRole)
+	})
+}
+
+func TestRoleService_Delete(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		repo := repoMocks.NewRoleRepository(t)
+		service := NewRoleService(repo)
+
+		repo.On("GetByName", ctx, domain.UserRole("stock-clerk")).
+			Return(&domain.Role{Name: "stock-clerk"}, nil).Once()
+		repo.On("Delete", ctx, domain.UserRole("stock-clerk")).
+			Return(nil).Once()
+
+		assert.NoError(t, service.Delete(ctx, "stock-clerk"))
+	})
+
+	t.Run("Error - Built-in Role", func(t *testing.T) {
+		repo := repoMocks.NewRoleRepository(t)
+		service := NewRoleService(repo)
+
+		repo.On("GetByName", ctx, domain.PickerRole).
+			Return(&domain.Role{Name: domain.PickerRole, BuiltIn: true}, nil).
+			Once()
+
+		err := service.Delete(ctx, "picker")
+
+		assert.ErrorIs(t, err, customErrors.ErrBuiltInRole)
+	})
+
+	t.Run("Error - Not Found", func(t *testing.T) {
+		repo := repoMocks.NewRoleRepository(t)
+		service := NewRoleService(repo)
+
+		repo.On("GetByName", ctx, domain.UserRole("ghost")).
+			Return(nil, customErrors.ErrRoleNotFound).Once()
+
+		err := service.Delete(ctx, "ghost")
+
+		assert.ErrorIs(t, err, customErrors.ErrRoleNotFound)
+	})
+}
+
+func TestRoleService_AssignRole(t *testing.T) {
+	ctx := context.Background()
+	adminID := uuid.NewString()
+	userID := uuid.NewString()
+
+	t.Run("Success", func(t *testing.T) {
+		repo := repoMocks.NewRoleRepository(t)
+		service := NewRoleService(repo)
+
+		repo.On("AssignRole", ctx, userID, domain.PickerRole).
+			Return(nil).Once()
+
+		assert.NoError(
+			t,
+			service.AssignRole(ctx, adminID, userID, domain.PickerRole),
+		)
+	})
+
+	t.Run("Error - Own Role", func(t *testing.T) {
+		service := NewRoleService(repoMocks.NewRoleRepository(t))
+
+		err := service.AssignRole(ctx, adminID, adminID, domain.CustomerRole)
+
+		assert.ErrorIs(t, err, customErrors.ErrForbidden)
+	})
+
+	t.Run("Error - Invalid User ID", func(t *testing.T) {
+		service := NewRoleService(repoMocks.NewRoleRepository(t))
+
+		err := service.AssignRole(ctx, adminID, "invalid-uuid", domain.PickerRole)
+
+		assert.ErrorIs(t, err, customErrors.ErrUserNotFound)
+	})
+}
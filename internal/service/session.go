@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/grocery-service/internal/domain"
+	customErrors "github.com/grocery-service/utils/errors"
+)
+
+// Sessions are token families. Each live refresh token stands for one
+// session, since refreshing revokes the previous token of the family.
+// Revoking a session stops it from refreshing; access tokens already
+// issued to it stay valid until they expire.
+
+func (s *authService) ListSessions(
+	ctx context.Context,
+	userID string,
+) ([]domain.Session, error) {
+	tokens, err := s.tokenRepo.GetByUserAndType(
+		ctx,
+		userID,
+		domain.TokenTypeRefresh,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]domain.Session, 0, len(tokens))
+	for _, token := range tokens {
+		sessions = append(sessions, domain.Session{
+			ID:         token.FamilyID,
+			UserID:     token.UserID,
+			Provider:   token.Provider,
+			Device:     token.Device,
+			IPAddress:  token.IPAddress,
+			UserAgent:  token.UserAgent,
+			LastUsedAt: token.LastUsedAt,
+			ExpiresAt:  token.ExpiresAt,
+		})
+	}
+
+	return sessions, nil
+}
+
+func (s *authService) RevokeSession(
+	ctx context.Context,
+	userID string,
+	sessionID string,
+) error {
+	sessions, err := s.ListSessions(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		if session.ID.String() == sessionID {
+			_, err := s.tokenRepo.RevokeFamily(ctx, sessionID)
+			return err
+		}
+	}
+
+	return customErrors.ErrSessionNotFound
+}
+
+func (s *authService) RevokeAllSessions(
+	ctx context.Context,
+	userID string,
+) (int64, error) {
+	return s.tokenRepo.RevokeUserTokens(ctx, userID)
+}
+
+// describeDevice names the browser and platform of a user agent, such as
+// "Firefox on Windows", for display in the session list.
+func describeDevice(userAgent string) string {
+	browser := ""
+	for _, b := range []struct{ marker, name string }{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"FxiOS/", "Firefox"},
+		{"Firefox/", "Firefox"},
+		{"CriOS/", "Chrome"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"curl/", "curl"},
+	} {
+		if strings.Contains(userAgent, b.marker) {
+			browser = b.name
+			break
+		}
+	}
+
+	platform := ""
+	for _, p := range []struct{ marker, name string }{
+		{"iPhone", "iPhone"},
+		{"iPad", "iPad"},
+		{"Android", "Android"},
+		{"Windows", "Windows"},
+		{"CrOS", "ChromeOS"},
+		{"Macintosh", "macOS"},
+		{"Linux", "Linux"},
+	} {
+		if strings.Contains(userAgent, p.marker) {
+			platform = p.name
+			break
+		}
+	}
+
+	switch {
+	case browser != "" && platform != "":
+		return browser + " on " + platform
+	case browser != "":
+		return browser
+	case platform != "":
+		return platform
+	default:
+		return "Unknown device"
+	}
+}
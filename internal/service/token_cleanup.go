@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"time"
+
+	"github.com/grocery-service/internal/config"
+	repository "github.com/grocery-service/internal/repository/postgres"
+	"github.com/grocery-service/utils/logger"
+)
+
+// tokenCleanupLockKey is the Postgres advisory lock that elects the one
+// replica running the token cleanup.
+const tokenCleanupLockKey int64 = 0x67726f63_746f6b31
+
+// tokenCleanupMetrics is published at /api/v1/admin/metrics as
+// "token_cleanup". "deleted" counts tokens deleted, "runs" completed
+// runs, "skipped" runs left to another replica and "errors" failed runs.
+var tokenCleanupMetrics = expvar.NewMap("token_cleanup")
+
+// TokenCleanup periodically deletes expired and long-revoked tokens so
+// the tokens table does not grow without bound.
+type TokenCleanup struct {
+	repo    repository.TokenRepository
+	locks   repository.LockRepository
+	cfg     config.TokenCleanupConfig
+	metrics *expvar.Map
+}
+
+func NewTokenCleanup(
+	repo repository.TokenRepository,
+	locks repository.LockRepository,
+	cfg config.TokenCleanupConfig,
+) *TokenCleanup {
+	return &TokenCleanup{
+		repo:    repo,
+		locks:   locks,
+		cfg:     cfg,
+		metrics: tokenCleanupMetrics,
+	}
+}
+
+// Run purges tokens every interval until ctx is cancelled.
+func (c *TokenCleanup) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := c.Purge(ctx); err != nil && ctx.Err() == nil {
+			logger.Error("token cleanup failed", logger.Error64("error", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge deletes expired tokens and tokens revoked longer than the
+// retention ago, one batch at a time, until none are left. It does
+// nothing when another replica holds the cleanup lock. It returns the
+// number of tokens deleted.
+func (c *TokenCleanup) Purge(ctx context.Context) (int64, error) {
+	unlock, acquired, err := c.locks.TryLock(ctx, tokenCleanupLockKey)
+	if err != nil {
+		c.metrics.Add("errors", 1)
+		return 0, fmt.Errorf("failed to take token cleanup lock: %w", err)
+	}
+	if !acquired {
+		c.metrics.Add("skipped", 1)
+		return 0, nil
+	}
+	defer unlock()
+
+	revokedBefore := time.Now().Add(-c.cfg.RevokedRetention)
+
+	var total int64
+	for ctx.Err() == nil {
+		deleted, err := c.repo.DeleteExpiredTokens(
+			ctx,
+			revokedBefore,
+			c.cfg.BatchSize,
+		)
+		total += deleted
+		c.metrics.Add("deleted", deleted)
+		if err != nil {
+			c.metrics.Add("errors", 1)
+			return total, fmt.Errorf("failed to delete tokens: %w", err)
+		}
+		if deleted < int64(c.cfg.BatchSize) {
+			break
+		}
+	}
+
+	c.metrics.Add("runs", 1)
+	if total > 0 {
+		logger.Info(
+			"token cleanup deleted tokens",
+			logger.Int("deleted", int(total)),
+		)
+	}
+
+	return total, ctx.Err()
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"expvar"
+	"testing"
+	"time"
+
+	"github.com/grocery-service/internal/config"
+	repoMocks "github.com/grocery-service/tests/mocks/repository"
+	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTokenCleanupTest(t *testing.T) (
+	*TokenCleanup,
+	*repoMocks.TokenRepository,
+	*repoMocks.LockRepository,
+) {
+	tokenRepo := repoMocks.NewTokenRepository(t)
+	lockRepo := repoMocks.NewLockRepository(t)
+
+	cleanup := NewTokenCleanup(tokenRepo, lockRepo, config.TokenCleanupConfig{
+		Enabled:          true,
+		Interval:         time.Hour,
+		BatchSize:        100,
+		RevokedRetention: 24 * time.Hour,
+	})
+	cleanup.metrics = new(expvar.Map)
+
+	return cleanup, tokenRepo, lockRepo
+}
+
+func metric(m *expvar.Map, key string) int64 {
+	if v, ok := m.Get(key).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
+
+func TestTokenCleanup_Purge(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("deletes in batches until a batch is short", func(t *testing.T) {
+		cleanup, tokenRepo, lockRepo := setupTokenCleanupTest(t)
+
+		unlocked := false
+		lockRepo.On("TryLock", ctx, tokenCleanupLockKey).
+			Return(func() { unlocked = true }, true, nil)
+
+		revokedBefore := mock.MatchedBy(func(before time.Time) bool {
+			want := time.Now().Add(-24 * time.Hour)
+			return before.Sub(want).Abs() < time.Minute
+		})
+		tokenRepo.On("DeleteExpiredTokens", ctx, revokedBefore, 100).
+			Return(int64(100), nil).Twice()
+		tokenRepo.On("DeleteExpiredTokens", ctx, revokedBefore, 100).
+			Return(int64(7), nil).Once()
+
+		deleted, err := cleanup.Purge(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(207), deleted)
+		assert.True(t, unlocked)
+		assert.Equal(t, int64(207), metric(cleanup.metrics, "deleted"))
+		assert.Equal(t, int64(1), metric(cleanup.metrics, "runs"))
+	})
+
+	t.Run("skips when another replica holds the lock", func(t *testing.T) {
+		cleanup, _, lockRepo := setupTokenCleanupTest(t)
+
+		lockRepo.On("TryLock", ctx, tokenCleanupLockKey).
+			Return(nil, false, nil)
+
+		deleted, err := cleanup.Purge(ctx)
+
+		require.NoError(t, err)
+		assert.Zero(t, deleted)
+		assert.Equal(t, int64(1), metric(cleanup.metrics, "skipped"))
+		assert.Zero(t, metric(cleanup.metrics, "runs"))
+	})
+
+	t.Run("lock error", func(t *testing.T) {
+		cleanup, _, lockRepo := setupTokenCleanupTest(t)
+
+		lockRepo.On("TryLock", ctx, tokenCleanupLockKey).
+			Return(nil, false, customErrors.ErrDBQuery)
+
+		_, err := cleanup.Purge(ctx)
+
+		assert.ErrorIs(t, err, customErrors.ErrDBQuery)
+		assert.Equal(t, int64(1), metric(cleanup.metrics, "errors"))
+	})
+
+	t.Run("stops and unlocks on a delete error", func(t *testing.T) {
+		cleanup, tokenRepo, lockRepo := setupTokenCleanupTest(t)
+
+		unlocked := false
+		lockRepo.On("TryLock", ctx, tokenCleanupLockKey).
+			Return(func() { unlocked = true }, true, nil)
+		tokenRepo.On("DeleteExpiredTokens", ctx, mock.Anything, 100).
+			Return(int64(100), nil).Once()
+		tokenRepo.On("DeleteExpiredTokens", ctx, mock.Anything, 100).
+			Return(int64(0), customErrors.ErrDBQuery).Once()
+
+		deleted, err := cleanup.Purge(ctx)
+
+		assert.ErrorIs(t, err, customErrors.ErrDBQuery)
+		assert.Equal(t, int64(100), deleted)
+		assert.True(t, unlocked)
+		assert.Equal(t, int64(100), metric(cleanup.metrics, "deleted"))
+		assert.Equal(t, int64(1), metric(cleanup.metrics, "errors"))
+	})
+
+	t.Run("stops on shutdown", func(t *testing.T) {
+		cleanup, tokenRepo, lockRepo := setupTokenCleanupTest(t)
+		ctx, cancel := context.WithCancel(context.Background())
+
+		lockRepo.On("TryLock", ctx, tokenCleanupLockKey).
+			Return(func() {}, true, nil)
+		tokenRepo.On("DeleteExpiredTokens", ctx, mock.Anything, 100).
+			Run(func(mock.Arguments) { cancel() }).
+			Return(int64(100), nil).Once()
+
+		deleted, err := cleanup.Purge(ctx)
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, int64(100), deleted)
+	})
+}
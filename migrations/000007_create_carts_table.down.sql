@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_cart_items_cart_product;
+DROP INDEX IF EXISTS idx_carts_customer_id;
+DROP TABLE IF EXISTS cart_items;
+DROP TABLE IF EXISTS carts;
\ No newline at end of file
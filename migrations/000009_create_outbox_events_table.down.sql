@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_outbox_events_due;
+DROP INDEX IF EXISTS idx_outbox_events_status;
+DROP INDEX IF EXISTS idx_outbox_events_aggregate_id;
+DROP TABLE IF EXISTS outbox_events;
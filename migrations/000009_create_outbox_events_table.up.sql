@@ -0,0 +1,21 @@
+CREATE TABLE outbox_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    aggregate_id UUID NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT valid_outbox_status CHECK (
+        status IN ('PENDING', 'DELIVERED', 'DEAD')
+    )
+);
+
+CREATE INDEX idx_outbox_events_aggregate_id ON outbox_events(aggregate_id);
+CREATE INDEX idx_outbox_events_status ON outbox_events(status);
+CREATE INDEX idx_outbox_events_due ON outbox_events(next_attempt_at)
+    WHERE status = 'PENDING';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_stock_movements_order_id;
+DROP INDEX IF EXISTS idx_stock_movements_product_id;
+DROP TABLE IF EXISTS stock_movements;
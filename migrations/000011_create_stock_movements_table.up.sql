@@ -0,0 +1,24 @@
+CREATE TABLE stock_movements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL,
+    quantity INTEGER NOT NULL,
+    order_id UUID REFERENCES orders(id) ON DELETE SET NULL,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT valid_stock_movement_type CHECK (
+        type IN ('SALE', 'RESTOCK', 'ADJUSTMENT', 'RETURN', 'SPOILAGE')
+    ),
+    CONSTRAINT nonzero_stock_movement CHECK (quantity <> 0)
+);
+
+CREATE INDEX idx_stock_movements_product_id
+    ON stock_movements(product_id, created_at);
+CREATE INDEX idx_stock_movements_order_id ON stock_movements(order_id);
+
+-- Open the ledger with the current stock so it sums to products.stock.
+INSERT INTO stock_movements (product_id, type, quantity, note)
+SELECT id, 'ADJUSTMENT', stock, 'opening balance'
+FROM products
+WHERE stock <> 0;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_products_low_stock;
+
+ALTER TABLE products
+    DROP CONSTRAINT IF EXISTS non_negative_reorder_quantity,
+    DROP CONSTRAINT IF EXISTS non_negative_reorder_point,
+    DROP COLUMN IF EXISTS reorder_quantity,
+    DROP COLUMN IF EXISTS reorder_point;
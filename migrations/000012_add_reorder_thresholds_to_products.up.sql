@@ -0,0 +1,8 @@
+ALTER TABLE products
+    ADD COLUMN reorder_point INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN reorder_quantity INTEGER NOT NULL DEFAULT 0,
+    ADD CONSTRAINT non_negative_reorder_point CHECK (reorder_point >= 0),
+    ADD CONSTRAINT non_negative_reorder_quantity CHECK (reorder_quantity >= 0);
+
+CREATE INDEX idx_products_low_stock ON products(stock)
+    WHERE reorder_point > 0 AND stock <= reorder_point;
@@ -0,0 +1,28 @@
+-- Provider logins are matched on the provider's subject; an email address
+-- only links a login to an existing account when it is verified.
+CREATE TABLE user_identities (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_user_identities_subject UNIQUE (provider, subject)
+);
+
+CREATE INDEX idx_user_identities_user_id ON user_identities(user_id);
+
+-- The logins seen so far are recorded on the tokens issued to them.
+INSERT INTO user_identities (user_id, provider, subject)
+SELECT DISTINCT user_id, provider, provider_id FROM tokens
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMP WITH TIME ZONE;
+
+-- Every existing user signed in through an identity provider, which
+-- vouched for their email address.
+UPDATE users SET email_verified_at = created_at;
+
+-- Email addresses are stored lowercase from now on.
+UPDATE users u SET email = lower(u.email)
+WHERE u.email <> lower(u.email)
+    AND NOT EXISTS (SELECT 1 FROM users o WHERE o.email = lower(u.email));
@@ -0,0 +1,10 @@
+-- Hashed tokens cannot be turned back into plaintext, so their sessions
+-- are dropped.
+DELETE FROM tokens WHERE legacy_token IS NULL;
+
+DROP INDEX IF EXISTS idx_tokens_token_hash;
+ALTER TABLE tokens DROP COLUMN IF EXISTS token_hash;
+
+ALTER TABLE tokens ALTER COLUMN legacy_token SET NOT NULL;
+ALTER TABLE tokens RENAME COLUMN legacy_token TO token;
+CREATE INDEX idx_tokens_token ON tokens(token);
@@ -0,0 +1,8 @@
+-- Tokens are stored as an HMAC in token_hash. Existing plaintext tokens
+-- are kept in legacy_token until `make hash-tokens` hashes them.
+ALTER TABLE tokens RENAME COLUMN token TO legacy_token;
+ALTER TABLE tokens ALTER COLUMN legacy_token DROP NOT NULL;
+DROP INDEX idx_tokens_token;
+
+ALTER TABLE tokens ADD COLUMN token_hash CHAR(64);
+CREATE UNIQUE INDEX idx_tokens_token_hash ON tokens(token_hash);
@@ -0,0 +1,2 @@
+ALTER TABLE tokens ALTER COLUMN token_hash DROP NOT NULL;
+ALTER TABLE tokens ADD COLUMN legacy_token TEXT;
@@ -0,0 +1,6 @@
+-- Tokens that `make hash-tokens` did not hash are dropped; their users
+-- sign in again.
+DELETE FROM tokens WHERE token_hash IS NULL;
+
+ALTER TABLE tokens DROP COLUMN legacy_token;
+ALTER TABLE tokens ALTER COLUMN token_hash SET NOT NULL;
@@ -0,0 +1,7 @@
+-- Tokens issued for one login share a family. Tokens issued before
+-- families existed each form their own.
+ALTER TABLE tokens ADD COLUMN family_id UUID;
+UPDATE tokens SET family_id = id;
+ALTER TABLE tokens ALTER COLUMN family_id SET NOT NULL;
+
+CREATE INDEX idx_tokens_family_id ON tokens(family_id);
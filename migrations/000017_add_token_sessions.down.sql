@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_tokens_user_sessions;
+
+ALTER TABLE tokens
+    DROP COLUMN IF EXISTS last_used_at,
+    DROP COLUMN IF EXISTS device,
+    DROP COLUMN IF EXISTS user_agent,
+    DROP COLUMN IF EXISTS ip_address;
@@ -0,0 +1,11 @@
+-- Refresh tokens describe the session they belong to.
+ALTER TABLE tokens
+    ADD COLUMN ip_address VARCHAR(45),
+    ADD COLUMN user_agent TEXT,
+    ADD COLUMN device VARCHAR(100),
+    ADD COLUMN last_used_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+UPDATE tokens SET last_used_at = created_at;
+
+CREATE INDEX idx_tokens_user_sessions ON tokens(user_id, type, last_used_at DESC)
+    WHERE revoked_at IS NULL;
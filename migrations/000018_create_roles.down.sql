@@ -0,0 +1,8 @@
+ALTER TABLE users DROP CONSTRAINT IF EXISTS fk_users_role;
+
+ALTER TABLE users
+    ALTER COLUMN role DROP NOT NULL,
+    ALTER COLUMN role SET DEFAULT 'user';
+
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS roles;
@@ -0,0 +1,48 @@
+-- Roles and the permissions granted to them. The permission names are
+-- the ones the API checks; see domain.Permissions.
+CREATE TABLE roles (
+    name VARCHAR(50) PRIMARY KEY,
+    description TEXT,
+    built_in BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE role_permissions (
+    role VARCHAR(50) NOT NULL REFERENCES roles(name) ON UPDATE CASCADE ON DELETE CASCADE,
+    permission VARCHAR(100) NOT NULL,
+    PRIMARY KEY (role, permission)
+);
+
+INSERT INTO roles (name, description, built_in) VALUES
+    ('admin', 'Full access to every part of the service', TRUE),
+    ('store-manager', 'Runs the store: catalogue, stock, orders and refunds', TRUE),
+    ('picker', 'Picks and packs orders', TRUE),
+    ('driver', 'Delivers orders', TRUE),
+    ('customer', 'Shops and places orders', TRUE);
+
+-- The admin role is granted every permission in code and needs no rows.
+INSERT INTO role_permissions (role, permission) VALUES
+    ('store-manager', 'products:write'),
+    ('store-manager', 'categories:write'),
+    ('store-manager', 'inventory:read'),
+    ('store-manager', 'inventory:write'),
+    ('store-manager', 'orders:read'),
+    ('store-manager', 'orders:update_status'),
+    ('store-manager', 'orders:refund'),
+    ('store-manager', 'customers:read'),
+    ('picker', 'orders:read'),
+    ('picker', 'orders:update_status'),
+    ('picker', 'inventory:read'),
+    ('driver', 'orders:read'),
+    ('driver', 'orders:update_status'),
+    ('customer', 'orders:create');
+
+-- Users used to default to 'user', which matched no role.
+UPDATE users SET role = 'customer'
+    WHERE role IS NULL OR role NOT IN (SELECT name FROM roles);
+
+ALTER TABLE users
+    ALTER COLUMN role SET DEFAULT 'customer',
+    ALTER COLUMN role SET NOT NULL,
+    ADD CONSTRAINT fk_users_role FOREIGN KEY (role) REFERENCES roles(name) ON UPDATE CASCADE;
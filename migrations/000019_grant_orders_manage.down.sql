@@ -0,0 +1,2 @@
+DELETE FROM role_permissions
+    WHERE role = 'store-manager' AND permission IN ('orders:create', 'orders:manage');
@@ -0,0 +1,6 @@
+-- Placing and changing orders for other customers is its own permission;
+-- orders:read only lets staff look.
+INSERT INTO role_permissions (role, permission) VALUES
+    ('store-manager', 'orders:create'),
+    ('store-manager', 'orders:manage')
+ON CONFLICT DO NOTHING;
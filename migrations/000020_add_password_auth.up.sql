@@ -0,0 +1,5 @@
+-- Email and password sign-in: brute-force lockout. Verified addresses
+-- are tracked since migration 13.
+ALTER TABLE users
+    ADD COLUMN failed_login_attempts INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN locked_until TIMESTAMP WITH TIME ZONE;
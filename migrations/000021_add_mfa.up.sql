@@ -0,0 +1,17 @@
+-- TOTP multi-factor authentication. The secret is encrypted by the
+-- service; recovery codes are stored as keyed hashes.
+ALTER TABLE users
+    ADD COLUMN mfa_secret TEXT,
+    ADD COLUMN mfa_enabled_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN mfa_required BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN mfa_last_step BIGINT;
+
+CREATE TABLE mfa_recovery_codes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash CHAR(64) NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_mfa_recovery_codes_user_id ON mfa_recovery_codes(user_id);
@@ -0,0 +1,11 @@
+-- Token buckets of the Postgres rate limit store, shared by replicas.
+-- Losing them in a crash only resets the limits, so the table is not
+-- written to the WAL.
+CREATE UNLOGGED TABLE rate_limit_buckets (
+    key VARCHAR(255) PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    full_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_rate_limit_buckets_full_at ON rate_limit_buckets(full_at);
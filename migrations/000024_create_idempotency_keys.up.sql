@@ -0,0 +1,23 @@
+-- Requests sent with an Idempotency-Key header and the responses they
+-- got, so that retries are answered without running the request again.
+-- Keys are scoped to the client that sent them. completed_at is NULL
+-- while the first request is still running. A key is held only until
+-- locked_until, so that a key whose request never finished, e.g.
+-- because the server crashed, can be claimed again by a retry; claim_id
+-- tells the request holding it from one it was taken over from.
+CREATE TABLE idempotency_keys (
+    client VARCHAR(255) NOT NULL,
+    key VARCHAR(255) NOT NULL,
+    fingerprint CHAR(64) NOT NULL,
+    claim_id UUID NOT NULL,
+    status_code INTEGER NOT NULL DEFAULT 0,
+    content_type VARCHAR(255) NOT NULL DEFAULT '',
+    body BYTEA,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    locked_until TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (client, key)
+);
+
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
@@ -0,0 +1,210 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	handler "github.com/grocery-service/internal/api/handlers"
+	"github.com/grocery-service/internal/api/middleware"
+	"github.com/grocery-service/internal/domain"
+	serviceMock "github.com/grocery-service/tests/mocks/service"
+	"github.com/grocery-service/utils/api"
+	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupAPIKeyTest() (
+	*serviceMock.APIKeyService,
+	*handler.APIKeyHandler,
+) {
+	mockService := new(serviceMock.APIKeyService)
+	handler := handler.NewAPIKeyHandler(mockService)
+	return mockService, handler
+}
+
+func TestAPIKeyHandler_Create(t *testing.T) {
+	mockService, handler := setupAPIKeyTest()
+	userID := uuid.NewString()
+	body := `{"name": "Scanner", "permissions": ["inventory:write"]}`
+	request := domain.APIKeyRequest{
+		Name:        "Scanner",
+		Permissions: []domain.Permission{domain.PermissionInventoryWrite},
+	}
+
+	tests := []struct {
+		name        string
+		body        string
+		role        string
+		permissions map[domain.Permission]bool
+		setupMock   func()
+		wantStatus  int
+		wantError   string
+	}{
+		{
+			name: "Success",
+			body: body,
+			role: string(domain.StoreManagerRole),
+			permissions: map[domain.Permission]bool{
+				domain.PermissionAPIKeysManage:  true,
+				domain.PermissionInventoryWrite: true,
+			},
+			setupMock: func() {
+				mockService.On("Create", mock.Anything, userID, request).
+					Return(&domain.NewAPIKey{
+						APIKey: &domain.APIKey{ID: uuid.New(), Name: "Scanner"},
+						Key:    "gsk_0123456789ab_secret",
+					}, nil).Once()
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name: "Permission Caller Lacks",
+			body: body,
+			role: string(domain.StoreManagerRole),
+			permissions: map[domain.Permission]bool{
+				domain.PermissionAPIKeysManage: true,
+			},
+			setupMock: func() {
+				mockService.On("Create", mock.Anything, userID, request).
+					Return(nil, fmt.Errorf(
+						"%w: you do not hold inventory:write",
+						customErrors.ErrAPIKeyPermissionDenied,
+					)).Once()
+			},
+			wantStatus: http.StatusForbidden,
+			wantError: "cannot grant a permission you do not hold: " +
+				"you do not hold inventory:write",
+		},
+		{
+			name: "Invalid Data",
+			body: `{"name": "", "permissions": ["orders:read"]}`,
+			role: string(domain.AdminRole),
+			setupMock: func() {
+				mockService.On(
+					"Create",
+					mock.Anything,
+					userID,
+					mock.Anything,
+				).Return(nil, customErrors.ErrInvalidAPIKeyData).Once()
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Invalid Body",
+			body:       `{"name":`,
+			role:       string(domain.AdminRole),
+			setupMock:  func() {},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			req := httptest.NewRequest(
+				http.MethodPost,
+				"/admin/api-keys",
+				strings.NewReader(tt.body),
+			)
+			ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID)
+			ctx = context.WithValue(ctx, middleware.UserRoleKey, tt.role)
+			ctx = context.WithValue(ctx, middleware.PermissionsKey, tt.permissions)
+			w := httptest.NewRecorder()
+
+			handler.Create(w, req.WithContext(ctx))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+
+			if tt.wantError != "" {
+				var response api.Response
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+				assert.Equal(t, tt.wantError, response.Error)
+			}
+		})
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAPIKeyHandler_Delete(t *testing.T) {
+	mockService, handler := setupAPIKeyTest()
+	id := uuid.NewString()
+
+	mockService.On("Delete", mock.Anything, id).
+		Return(customErrors.ErrAPIKeyNotFound).Once()
+
+	r := chi.NewRouter()
+	r.Delete("/admin/api-keys/{id}", handler.Delete)
+
+	req := httptest.NewRequest(http.MethodDelete, "/admin/api-keys/"+id, nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestAuthentication_APIKey(t *testing.T) {
+	authService := new(serviceMock.AuthService)
+	apiKeyService := new(serviceMock.APIKeyService)
+	keyID := uuid.New()
+
+	var ctx context.Context
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx = r.Context()
+		w.WriteHeader(http.StatusNoContent)
+	})
+	guarded := middleware.Authentication(authService, apiKeyService)(next)
+
+	apiKeyService.On("Authenticate", mock.Anything, "gsk_valid").
+		Return(&domain.APIKey{
+			ID:          keyID,
+			Permissions: []domain.Permission{domain.PermissionOrdersCreate},
+		}, nil).Once()
+	apiKeyService.On("Authenticate", mock.Anything, "gsk_revoked").
+		Return(nil, customErrors.ErrInvalidAPIKey).Once()
+
+	t.Run("Valid Key", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+		req.Header.Set(middleware.APIKeyHeader, "gsk_valid")
+		w := httptest.NewRecorder()
+
+		guarded.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, keyID.String(), ctx.Value(middleware.APIKeyIDKey))
+		assert.Equal(t, "", ctx.Value(middleware.UserIDKey))
+		assert.True(
+			t,
+			middleware.HasPermission(ctx, domain.PermissionOrdersCreate),
+		)
+		assert.False(
+			t,
+			middleware.HasPermission(ctx, domain.PermissionOrdersRefund),
+		)
+	})
+
+	t.Run("Invalid Key", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+		req.Header.Set(middleware.APIKeyHeader, "gsk_revoked")
+		w := httptest.NewRecorder()
+
+		guarded.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	apiKeyService.AssertExpectations(t)
+	authService.AssertExpectations(t)
+}
@@ -0,0 +1,345 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	handler "github.com/grocery-service/internal/api/handlers"
+	"github.com/grocery-service/internal/api/middleware"
+	"github.com/grocery-service/internal/domain"
+	"github.com/grocery-service/internal/service"
+	repoMocks "github.com/grocery-service/tests/mocks/repository"
+	serviceMock "github.com/grocery-service/tests/mocks/service"
+	"github.com/grocery-service/utils/api"
+	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupCartTest() (
+	*serviceMock.CartService,
+	*handler.CartHandler,
+) {
+	mockService := new(serviceMock.CartService)
+	handler := handler.NewCartHandler(mockService)
+	return mockService, handler
+}
+
+func TestCartHandler_AddItem(t *testing.T) {
+	mockService, handler := setupCartTest()
+	userID := uuid.New().String()
+	productID := uuid.New()
+
+	tests := []struct {
+		name       string
+		body       interface{}
+		setupMock  func()
+		wantStatus int
+		wantError  string
+	}{
+		{
+			name: "Success",
+			body: domain.AddCartItemRequest{
+				ProductID: productID,
+				Quantity:  2,
+			},
+			setupMock: func() {
+				mockService.On("AddItem", mock.Anything, userID, productID.String(), 2).
+					Return(&domain.Cart{ID: uuid.New()}, nil).Once()
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name: "Insufficient Stock",
+			body: domain.AddCartItemRequest{
+				ProductID: productID,
+				Quantity:  50,
+			},
+			setupMock: func() {
+				mockService.On("AddItem", mock.Anything, userID, productID.String(), 50).
+					Return(nil, &customErrors.InsufficientStockError{
+						Shortfalls: []customErrors.StockShortfall{{
+							ProductID: productID.String(),
+							Requested: 50,
+							Available: 3,
+						}},
+					}).Once()
+			},
+			wantStatus: http.StatusConflict,
+			wantError:  "insufficient stock",
+		},
+		{
+			name:       "Invalid Body",
+			body:       "not-json",
+			setupMock:  func() {},
+			wantStatus: http.StatusBadRequest,
+			wantError:  "Invalid request body",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			jsonBody, _ := json.Marshal(tt.body)
+			req := httptest.NewRequest(
+				http.MethodPost,
+				"/cart/items",
+				bytes.NewBuffer(jsonBody),
+			)
+			req = req.WithContext(
+				context.WithValue(req.Context(), middleware.UserIDKey, userID),
+			)
+			w := httptest.NewRecorder()
+
+			handler.AddItem(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+
+			var response api.Response
+			err := json.NewDecoder(w.Body).Decode(&response)
+			assert.NoError(t, err)
+
+			if tt.wantError != "" {
+				assert.False(t, response.Success)
+				assert.Contains(t, response.Error, tt.wantError)
+			} else {
+				assert.True(t, response.Success)
+			}
+		})
+	}
+}
+
+func TestCartHandler_RemoveItem(t *testing.T) {
+	mockService, handler := setupCartTest()
+	userID := uuid.New().String()
+	itemID := uuid.New().String()
+
+	tests := []struct {
+		name       string
+		itemID     string
+		setupMock  func()
+		wantStatus int
+	}{
+		{
+			name:   "Success",
+			itemID: itemID,
+			setupMock: func() {
+				mockService.On("RemoveItem", mock.Anything, userID, itemID).
+					Return(&domain.Cart{ID: uuid.New()}, nil).Once()
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "Item Not Found",
+			itemID: itemID,
+			setupMock: func() {
+				mockService.On("RemoveItem", mock.Anything, userID, itemID).
+					Return(nil, customErrors.ErrCartItemNotFound).Once()
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "Invalid Item ID",
+			itemID:     "invalid-uuid",
+			setupMock:  func() {},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			req := httptest.NewRequest(
+				http.MethodDelete,
+				"/cart/items/"+tt.itemID,
+				nil,
+			)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("itemID", tt.itemID)
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, middleware.UserIDKey, userID)
+			req = req.WithContext(ctx)
+			w := httptest.NewRecorder()
+
+			handler.RemoveItem(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestCartHandler_Checkout(t *testing.T) {
+	mockService, handler := setupCartTest()
+	userID := uuid.New().String()
+	productID := uuid.New()
+
+	tests := []struct {
+		name       string
+		body       string
+		setupMock  func()
+		wantStatus int
+		wantError  string
+	}{
+		{
+			name: "Success",
+			setupMock: func() {
+				mockService.On("Checkout", mock.Anything, userID, domain.CheckoutRequest{}).
+					Return(&domain.CheckoutResult{
+						Order: &domain.Order{ID: uuid.New()},
+					}, nil).Once()
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name: "Price Changed",
+			setupMock: func() {
+				mockService.On("Checkout", mock.Anything, userID, domain.CheckoutRequest{}).
+					Return(&domain.CheckoutResult{
+						PriceChanges: []domain.CartPriceChange{{
+							ProductID: productID,
+							OldPrice:  domain.NewMoney(199, domain.DefaultCurrency),
+							NewPrice:  domain.NewMoney(249, domain.DefaultCurrency),
+						}},
+					}, customErrors.ErrCartPriceChanged).Once()
+			},
+			wantStatus: http.StatusConflict,
+			wantError:  "cart prices have changed",
+		},
+		{
+			name: "Accepts Price Changes",
+			body: `{"accept_price_changes": true}`,
+			setupMock: func() {
+				mockService.On(
+					"Checkout",
+					mock.Anything,
+					userID,
+					domain.CheckoutRequest{AcceptPriceChanges: true},
+				).
+					Return(&domain.CheckoutResult{
+						Order: &domain.Order{ID: uuid.New()},
+					}, nil).Once()
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name: "Empty Cart",
+			setupMock: func() {
+				mockService.On("Checkout", mock.Anything, userID, domain.CheckoutRequest{}).
+					Return(nil, customErrors.ErrEmptyCart).Once()
+			},
+			wantStatus: http.StatusBadRequest,
+			wantError:  "cart is empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			req := httptest.NewRequest(
+				http.MethodPost,
+				"/cart/checkout",
+				bytes.NewBufferString(tt.body),
+			)
+			req = req.WithContext(
+				context.WithValue(req.Context(), middleware.UserIDKey, userID),
+			)
+			w := httptest.NewRecorder()
+
+			handler.Checkout(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+
+			var response api.Response
+			err := json.NewDecoder(w.Body).Decode(&response)
+			assert.NoError(t, err)
+
+			if tt.wantError != "" {
+				assert.False(t, response.Success)
+				assert.Contains(t, response.Error, tt.wantError)
+			} else {
+				assert.True(t, response.Success)
+			}
+		})
+	}
+}
+
+// TestCallerResolvedByUserID drives the cart and customer services with
+// the user ID Authentication puts in the context, which is the caller's
+// users.id rather than an identity provider subject.
+func TestCallerResolvedByUserID(t *testing.T) {
+	authService := serviceMock.NewAuthService(t)
+	customerRepo := repoMocks.NewCustomerRepository(t)
+	userRepo := repoMocks.NewUserRepository(t)
+	cartRepo := repoMocks.NewCartRepository(t)
+
+	userID := uuid.New()
+	user := &domain.User{ID: userID, Email: "shopper@example.com"}
+	customer := &domain.Customer{ID: uuid.New(), UserID: userID, User: user}
+
+	authService.On("VerifyAccessToken", mock.Anything, "test-token").
+		Return(&domain.AccessTokenClaims{
+			UserID: userID,
+			Role:   domain.CustomerRole,
+		}, nil)
+
+	r := chi.NewRouter()
+	r.Use(middleware.Authentication(
+		authService,
+		serviceMock.NewAPIKeyService(t),
+	))
+	r.Mount("/cart", handler.NewCartHandler(service.NewCartService(
+		cartRepo,
+		repoMocks.NewProductRepository(t),
+		customerRepo,
+		serviceMock.NewOrderService(t),
+	)).Routes())
+	r.Mount("/customers", handler.NewCustomerHandler(
+		service.NewCustomerService(customerRepo, userRepo),
+	).Routes())
+
+	send := func(method, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer test-token")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Cart", func(t *testing.T) {
+		customerRepo.On("GetByUserID", mock.Anything, userID.String()).
+			Return(customer, nil).Once()
+		cartRepo.On("GetByCustomerID", mock.Anything, customer.ID.String()).
+			Return(domain.NewCart(customer.ID), nil).Once()
+
+		assert.Equal(t, http.StatusOK, send(http.MethodGet, "/cart").Code)
+	})
+
+	t.Run("Create Customer", func(t *testing.T) {
+		userRepo.On("GetByID", mock.Anything, userID.String()).
+			Return(user, nil).Once()
+		customerRepo.On("GetByUserID", mock.Anything, userID.String()).
+			Return(nil, customErrors.ErrCustomerNotFound).Once()
+		customerRepo.On(
+			"Create",
+			mock.Anything,
+			mock.MatchedBy(func(c *domain.Customer) bool {
+				return c.UserID == userID
+			}),
+		).Return(nil).Once()
+
+		assert.Equal(
+			t,
+			http.StatusCreated,
+			send(http.MethodPost, "/customers").Code,
+		)
+	})
+}
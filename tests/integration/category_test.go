@@ -201,8 +201,8 @@ func TestCategoryHandler_List(t *testing.T) {
 		{
 			name: "Success",
 			setupMock: func() {
-				mockService.On("List", mock.Anything).
-					Return([]domain.Category{
+				mockService.On("List", mock.Anything, mock.Anything).
+					Return(&domain.Page[domain.Category]{Items: []domain.Category{
 						{
 							ID:          uuid.New(),
 							Name:        "Fruits",
@@ -213,7 +213,7 @@ func TestCategoryHandler_List(t *testing.T) {
 							Name:        "Vegetables",
 							Description: "Fresh vegetables",
 						},
-					}, nil)
+					}}, nil)
 			},
 			wantStatus: http.StatusOK,
 			wantCount:  2,
@@ -221,7 +221,7 @@ func TestCategoryHandler_List(t *testing.T) {
 		{
 			name: "Internal Error",
 			setupMock: func() {
-				mockService.On("List", mock.Anything).
+				mockService.On("List", mock.Anything, mock.Anything).
 					Return(nil, fmt.Errorf("database error")).
 					Once()
 			},
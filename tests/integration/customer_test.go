@@ -232,7 +232,11 @@ func TestCustomerHandler_GetByID(t *testing.T) {
 			rctx.URLParams.Add("id", tt.id)
 			req := httptest.NewRequest(http.MethodGet, "/customers/"+tt.id, nil)
 			req = req.WithContext(
-				context.WithValue(req.Context(), chi.RouteCtxKey, rctx),
+				context.WithValue(
+					withPermissions(req.Context(), domain.PermissionCustomersRead),
+					chi.RouteCtxKey,
+					rctx,
+				),
 			)
 			w := httptest.NewRecorder()
 
@@ -283,8 +287,8 @@ func TestCustomerHandler_List(t *testing.T) {
 						},
 					},
 				}
-				mockService.On("List", mock.Anything).
-					Return(customers, nil)
+				mockService.On("List", mock.Anything, mock.Anything).
+					Return(&domain.Page[domain.Customer]{Items: customers}, nil)
 			},
 			wantStatus: http.StatusOK,
 			wantCount:  2,
@@ -292,7 +296,7 @@ func TestCustomerHandler_List(t *testing.T) {
 		{
 			name: "Internal Error",
 			setupMock: func() {
-				mockService.On("List", mock.Anything).
+				mockService.On("List", mock.Anything, mock.Anything).
 					Return(nil, customErrors.ErrInternalServer).
 					Once()
 			},
@@ -386,7 +390,11 @@ func TestCustomerHandler_Delete(t *testing.T) {
 				nil,
 			)
 			req = req.WithContext(
-				context.WithValue(req.Context(), chi.RouteCtxKey, rctx),
+				context.WithValue(
+					withPermissions(req.Context(), domain.PermissionCustomersDelete),
+					chi.RouteCtxKey,
+					rctx,
+				),
 			)
 			w := httptest.NewRecorder()
 
@@ -435,3 +443,83 @@ func TestCustomerHandler_Routes(t *testing.T) {
 		)
 	}
 }
+
+func TestCustomerHandler_Ownership(t *testing.T) {
+	userID := uuid.New()
+	own := &domain.Customer{ID: uuid.New(), UserID: userID}
+	otherID := uuid.New()
+
+	tests := []struct {
+		name       string
+		id         uuid.UUID
+		method     string
+		setupMock  func(*serviceMock.CustomerService)
+		wantStatus int
+	}{
+		{
+			name:   "Get Own Profile",
+			id:     own.ID,
+			method: http.MethodGet,
+			setupMock: func(m *serviceMock.CustomerService) {
+				m.On("GetByID", mock.Anything, own.ID.String()).
+					Return(own, nil).Once()
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "Get Other Profile",
+			id:         otherID,
+			method:     http.MethodGet,
+			setupMock:  func(*serviceMock.CustomerService) {},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:   "Delete Own Profile",
+			id:     own.ID,
+			method: http.MethodDelete,
+			setupMock: func(m *serviceMock.CustomerService) {
+				m.On("Delete", mock.Anything, own.ID.String()).
+					Return(nil).Once()
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "Delete Other Profile",
+			id:         otherID,
+			method:     http.MethodDelete,
+			setupMock:  func(*serviceMock.CustomerService) {},
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(serviceMock.CustomerService)
+			h := handler.NewCustomerHandler(mockService)
+
+			tt.setupMock(mockService)
+			mockService.On("GetByUserID", mock.Anything, userID.String()).
+				Return(own, nil).Once()
+
+			req := httptest.NewRequest(
+				tt.method,
+				"/customers/"+tt.id.String(),
+				nil,
+			)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id.String())
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, middleware.UserIDKey, userID.String())
+			w := httptest.NewRecorder()
+
+			if tt.method == http.MethodDelete {
+				h.Delete(w, req.WithContext(ctx))
+			} else {
+				h.GetByID(w, req.WithContext(ctx))
+			}
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
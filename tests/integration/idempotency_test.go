@@ -0,0 +1,268 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grocery-service/internal/api/middleware"
+	"github.com/grocery-service/internal/domain"
+	serviceMock "github.com/grocery-service/tests/mocks/service"
+	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestIdempotency(t *testing.T) {
+	completedAt := time.Now()
+	fingerprint := mock.AnythingOfType("string")
+
+	tests := []struct {
+		name       string
+		method     string
+		key        string
+		status     int
+		setupMock  func(*serviceMock.IdempotencyService)
+		wantStatus int
+		wantRuns   int
+		wantBody   string
+		wantReplay bool
+	}{
+		{
+			name:       "Without Key",
+			method:     http.MethodPost,
+			setupMock:  func(*serviceMock.IdempotencyService) {},
+			wantStatus: http.StatusCreated,
+			wantRuns:   1,
+		},
+		{
+			name:       "Ignored On GET",
+			method:     http.MethodGet,
+			key:        "order-1",
+			setupMock:  func(*serviceMock.IdempotencyService) {},
+			wantStatus: http.StatusCreated,
+			wantRuns:   1,
+		},
+		{
+			name:   "Records First Response",
+			method: http.MethodPost,
+			key:    "order-1",
+			setupMock: func(m *serviceMock.IdempotencyService) {
+				key := &domain.IdempotencyKey{Client: "user:user-1"}
+				m.On(
+					"Begin",
+					mock.Anything,
+					"user:user-1",
+					"order-1",
+					fingerprint,
+				).Return(key, nil).Once()
+				m.On(
+					"Complete",
+					mock.Anything,
+					mock.MatchedBy(func(k *domain.IdempotencyKey) bool {
+						return k.StatusCode == http.StatusCreated &&
+							k.ContentType == "application/json" &&
+							string(k.Body) == `{"id":"order-1"}`
+					}),
+				).Return(nil).Once()
+			},
+			wantStatus: http.StatusCreated,
+			wantRuns:   1,
+			wantBody:   `{"id":"order-1"}`,
+		},
+		{
+			name:   "Replays Recorded Response",
+			method: http.MethodPost,
+			key:    "order-1",
+			setupMock: func(m *serviceMock.IdempotencyService) {
+				m.On(
+					"Begin",
+					mock.Anything,
+					"user:user-1",
+					"order-1",
+					fingerprint,
+				).Return(&domain.IdempotencyKey{
+					StatusCode:  http.StatusCreated,
+					ContentType: "application/json",
+					Body:        []byte(`{"id":"order-1"}`),
+					CompletedAt: &completedAt,
+				}, nil).Once()
+			},
+			wantStatus: http.StatusCreated,
+			wantBody:   `{"id":"order-1"}`,
+			wantReplay: true,
+		},
+		{
+			name:   "Releases Key On Server Error",
+			method: http.MethodPost,
+			key:    "order-1",
+			status: http.StatusInternalServerError,
+			setupMock: func(m *serviceMock.IdempotencyService) {
+				key := &domain.IdempotencyKey{Client: "user:user-1"}
+				m.On(
+					"Begin",
+					mock.Anything,
+					"user:user-1",
+					"order-1",
+					fingerprint,
+				).Return(key, nil).Once()
+				m.On("Release", mock.Anything, key).Return(nil).Once()
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantRuns:   1,
+		},
+		{
+			name:   "Different Request",
+			method: http.MethodPost,
+			key:    "order-1",
+			setupMock: func(m *serviceMock.IdempotencyService) {
+				m.On(
+					"Begin",
+					mock.Anything,
+					"user:user-1",
+					"order-1",
+					fingerprint,
+				).Return(nil, customErrors.ErrIdempotencyKeyMismatch).Once()
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "Still In Progress",
+			method: http.MethodPost,
+			key:    "order-1",
+			setupMock: func(m *serviceMock.IdempotencyService) {
+				m.On(
+					"Begin",
+					mock.Anything,
+					"user:user-1",
+					"order-1",
+					fingerprint,
+				).Return(nil, customErrors.ErrIdempotencyKeyInUse).Once()
+			},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:   "Invalid Key",
+			method: http.MethodPost,
+			key:    strings.Repeat("k", 256),
+			setupMock: func(m *serviceMock.IdempotencyService) {
+				m.On(
+					"Begin",
+					mock.Anything,
+					"user:user-1",
+					strings.Repeat("k", 256),
+					fingerprint,
+				).Return(nil, customErrors.ErrInvalidIdempotencyKey).Once()
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := serviceMock.NewIdempotencyService(t)
+			tt.setupMock(mockService)
+
+			runs := 0
+			next := http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					runs++
+					// The body is still there for the handler
+					body := make([]byte, 64)
+					n, _ := r.Body.Read(body)
+					assert.Equal(t, `{"items":[]}`, string(body[:n]))
+
+					status := tt.status
+					if status == 0 {
+						status = http.StatusCreated
+					}
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(status)
+					_, _ = w.Write([]byte(`{"id":"order-1"}`))
+				},
+			)
+			handler := middleware.Idempotency(mockService)(next)
+
+			req := httptest.NewRequest(
+				tt.method,
+				"/orders",
+				strings.NewReader(`{"items":[]}`),
+			)
+			if tt.key != "" {
+				req.Header.Set(middleware.IdempotencyKeyHeader, tt.key)
+			}
+			ctx := context.WithValue(
+				req.Context(),
+				middleware.UserIDKey,
+				"user-1",
+			)
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req.WithContext(ctx))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantRuns, runs)
+			if tt.wantBody != "" {
+				assert.Equal(t, tt.wantBody, w.Body.String())
+			}
+			if tt.wantReplay {
+				assert.Equal(
+					t,
+					"true",
+					w.Header().Get(middleware.IdempotentReplayedHeader),
+				)
+				assert.Equal(
+					t,
+					"application/json",
+					w.Header().Get("Content-Type"),
+				)
+			} else {
+				assert.Empty(
+					t,
+					w.Header().Get(middleware.IdempotentReplayedHeader),
+				)
+			}
+		})
+	}
+}
+
+func TestIdempotency_SameRequestSameFingerprint(t *testing.T) {
+	mockService := serviceMock.NewIdempotencyService(t)
+
+	var fingerprints []string
+	mockService.On(
+		"Begin",
+		mock.Anything,
+		mock.Anything,
+		"order-1",
+		mock.AnythingOfType("string"),
+	).Run(func(args mock.Arguments) {
+		fingerprints = append(fingerprints, args.String(3))
+	}).Return(nil, customErrors.ErrIdempotencyKeyInUse)
+
+	handler := middleware.Idempotency(mockService)(
+		http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}),
+	)
+	send := func(path, body string) {
+		req := httptest.NewRequest(
+			http.MethodPost,
+			path,
+			strings.NewReader(body),
+		)
+		req.Header.Set(middleware.IdempotencyKeyHeader, "order-1")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	send("/orders", `{"items":[]}`)
+	send("/orders", `{"items":[]}`)
+	send("/orders", `{"items":[1]}`)
+	send("/orders/me", `{"items":[]}`)
+
+	assert.Len(t, fingerprints, 4)
+	assert.Equal(t, fingerprints[0], fingerprints[1])
+	assert.NotEqual(t, fingerprints[0], fingerprints[2])
+	assert.NotEqual(t, fingerprints[0], fingerprints[3])
+}
@@ -0,0 +1,202 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	handler "github.com/grocery-service/internal/api/handlers"
+	"github.com/grocery-service/internal/domain"
+	serviceMock "github.com/grocery-service/tests/mocks/service"
+	"github.com/grocery-service/utils/api"
+	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupInventoryTest() (
+	*serviceMock.InventoryService,
+	*handler.InventoryHandler,
+) {
+	mockService := new(serviceMock.InventoryService)
+	handler := handler.NewInventoryHandler(mockService)
+	return mockService, handler
+}
+
+func TestInventoryHandler_GetStockHistory(t *testing.T) {
+	mockService, handler := setupInventoryTest()
+	productID := uuid.New()
+	orderID := uuid.New()
+
+	tests := []struct {
+		name       string
+		productID  string
+		query      string
+		setupMock  func()
+		wantStatus int
+		wantTotal  int64
+	}{
+		{
+			name:      "Success",
+			productID: productID.String(),
+			query:     "?sort=created_at&order=desc",
+			setupMock: func() {
+				mockService.On(
+					"GetStockHistory",
+					mock.Anything,
+					productID.String(),
+					mock.MatchedBy(func(q domain.ListQuery) bool {
+						return q.SortBy == "created_at" &&
+							q.SortDir == domain.SortDesc
+					}),
+				).Return(&domain.Page[domain.StockMovement]{
+					Items: []domain.StockMovement{
+						*domain.NewOrderStockMovement(
+							productID,
+							domain.StockMovementSale,
+							-2,
+							orderID,
+						),
+						*domain.NewStockMovement(
+							productID,
+							domain.StockMovementRestock,
+							20,
+						),
+					},
+					Total: 2,
+					Limit: 20,
+					Page:  1,
+				}, nil).Once()
+			},
+			wantStatus: http.StatusOK,
+			wantTotal:  2,
+		},
+		{
+			name:       "Invalid Product ID",
+			productID:  "invalid-uuid",
+			setupMock:  func() {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:      "Product Not Found",
+			productID: productID.String(),
+			setupMock: func() {
+				mockService.On(
+					"GetStockHistory",
+					mock.Anything,
+					productID.String(),
+					mock.Anything,
+				).Return(nil, customErrors.ErrProductNotFound).Once()
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			req := httptest.NewRequest(
+				http.MethodGet,
+				"/products/"+tt.productID+"/stock/history"+tt.query,
+				nil,
+			)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.productID)
+			req = req.WithContext(
+				context.WithValue(req.Context(), chi.RouteCtxKey, rctx),
+			)
+			w := httptest.NewRecorder()
+
+			handler.GetStockHistory(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusOK {
+				var response api.Response
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+				require.NotNil(t, response.Meta)
+				assert.Equal(t, tt.wantTotal, response.Meta.Total)
+			}
+		})
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestInventoryHandler_ListLowStock(t *testing.T) {
+	mockService, handler := setupInventoryTest()
+
+	tests := []struct {
+		name       string
+		query      string
+		setupMock  func()
+		wantStatus int
+		wantTotal  int64
+	}{
+		{
+			name:  "Success",
+			query: "?limit=5",
+			setupMock: func() {
+				mockService.On(
+					"ListLowStock",
+					mock.Anything,
+					mock.MatchedBy(func(q domain.ListQuery) bool {
+						return q.Limit == 5
+					}),
+				).Return(&domain.Page[domain.Product]{
+					Items: []domain.Product{{
+						ID:              uuid.New(),
+						Name:            "Whole Milk",
+						Stock:           3,
+						ReorderPoint:    5,
+						ReorderQuantity: 40,
+					}},
+					Total: 1,
+					Limit: 5,
+					Page:  1,
+				}, nil).Once()
+			},
+			wantStatus: http.StatusOK,
+			wantTotal:  1,
+		},
+		{
+			name:  "Invalid Sort",
+			query: "?sort=rank",
+			setupMock: func() {
+				mockService.On("ListLowStock", mock.Anything, mock.Anything).
+					Return(nil, customErrors.ErrInvalidListQuery).Once()
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			req := httptest.NewRequest(
+				http.MethodGet,
+				"/products/low-stock"+tt.query,
+				nil,
+			)
+			w := httptest.NewRecorder()
+
+			handler.ListLowStock(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusOK {
+				var response api.Response
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+				require.NotNil(t, response.Meta)
+				assert.Equal(t, tt.wantTotal, response.Meta.Total)
+			}
+		})
+	}
+
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,305 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/grocery-service/internal/api/middleware"
+	"github.com/grocery-service/internal/domain"
+	"github.com/grocery-service/utils/api"
+	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthHandler_VerifyMFA(t *testing.T) {
+	mockService, handler := setupSessionTest()
+	request := domain.MFAVerifyRequest{MFAToken: "mfa-token", Code: "123456"}
+	body := `{"mfa_token": "mfa-token", "code": "123456"}`
+
+	tests := []struct {
+		name       string
+		body       string
+		setupMock  func()
+		wantStatus int
+		wantError  string
+	}{
+		{
+			name: "Success",
+			body: body,
+			setupMock: func() {
+				mockService.On(
+					"VerifyMFA",
+					mock.Anything,
+					request,
+					mock.Anything,
+				).Return(&domain.AuthResponse{
+					AccessToken:   "access-token",
+					RefreshToken:  "refresh-token",
+					RecoveryCodes: []string{"abcd-efgh"},
+				}, nil).Once()
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Wrong Code",
+			body: body,
+			setupMock: func() {
+				mockService.On(
+					"VerifyMFA",
+					mock.Anything,
+					request,
+					mock.Anything,
+				).Return(nil, customErrors.ErrInvalidMFACode).Once()
+			},
+			wantStatus: http.StatusUnauthorized,
+			wantError:  "Invalid MFA code",
+		},
+		{
+			name: "Expired Challenge",
+			body: body,
+			setupMock: func() {
+				mockService.On(
+					"VerifyMFA",
+					mock.Anything,
+					request,
+					mock.Anything,
+				).Return(nil, customErrors.ErrInvalidMFAChallenge).Once()
+			},
+			wantStatus: http.StatusBadRequest,
+			wantError:  "MFA session not found or expired",
+		},
+		{
+			name: "Locked",
+			body: body,
+			setupMock: func() {
+				mockService.On(
+					"VerifyMFA",
+					mock.Anything,
+					request,
+					mock.Anything,
+				).Return(nil, &customErrors.AccountLockedError{
+					Until: time.Now().Add(time.Minute),
+				}).Once()
+			},
+			wantStatus: http.StatusTooManyRequests,
+		},
+		{
+			name:       "Invalid Body",
+			body:       `{"code":`,
+			setupMock:  func() {},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			req := httptest.NewRequest(
+				http.MethodPost,
+				"/auth/mfa/verify",
+				strings.NewReader(tt.body),
+			)
+			w := httptest.NewRecorder()
+
+			handler.VerifyMFA(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+
+			if tt.wantError != "" {
+				var response api.Response
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+				assert.Equal(t, tt.wantError, response.Error)
+			}
+		})
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAuthHandler_StepUp(t *testing.T) {
+	mockService, handler := setupSessionTest()
+	userID := uuid.NewString()
+	sessionID := uuid.NewString()
+
+	tests := []struct {
+		name       string
+		setupMock  func()
+		wantStatus int
+	}{
+		{
+			name: "Success",
+			setupMock: func() {
+				mockService.On(
+					"StepUp",
+					mock.Anything,
+					userID,
+					sessionID,
+					"123456",
+					mock.Anything,
+				).Return(&domain.AuthResponse{
+					AccessToken: "stepped-up-token",
+				}, nil).Once()
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "MFA Not Enabled",
+			setupMock: func() {
+				mockService.On(
+					"StepUp",
+					mock.Anything,
+					userID,
+					sessionID,
+					"123456",
+					mock.Anything,
+				).Return(nil, customErrors.ErrMFANotEnabled).Once()
+			},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name: "Session Revoked",
+			setupMock: func() {
+				mockService.On(
+					"StepUp",
+					mock.Anything,
+					userID,
+					sessionID,
+					"123456",
+					mock.Anything,
+				).Return(nil, customErrors.ErrSessionNotFound).Once()
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			req := httptest.NewRequest(
+				http.MethodPost,
+				"/auth/mfa/step-up",
+				strings.NewReader(`{"code": "123456"}`),
+			)
+			ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID)
+			ctx = context.WithValue(ctx, middleware.SessionIDKey, sessionID)
+			w := httptest.NewRecorder()
+
+			handler.StepUp(w, req.WithContext(ctx))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAuthHandler_DisableMFA(t *testing.T) {
+	mockService, handler := setupSessionTest()
+	userID := uuid.NewString()
+
+	mockService.On("DisableMFA", mock.Anything, userID, "123456").
+		Return(customErrors.ErrMFARequired).Once()
+
+	req := httptest.NewRequest(
+		http.MethodPost,
+		"/auth/mfa/disable",
+		strings.NewReader(`{"code": "123456"}`),
+	)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID)
+	w := httptest.NewRecorder()
+
+	handler.DisableMFA(w, req.WithContext(ctx))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestAuthHandler_SetMFARequired(t *testing.T) {
+	mockService, handler := setupSessionTest()
+	userID := uuid.NewString()
+
+	mockService.On("SetMFARequired", mock.Anything, userID, true).
+		Return(nil).Once()
+
+	r := chi.NewRouter()
+	r.Put("/admin/users/{id}/mfa", handler.SetMFARequired)
+
+	req := httptest.NewRequest(
+		http.MethodPut,
+		"/admin/users/"+userID+"/mfa",
+		strings.NewReader(`{"required": true}`),
+	)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestRequireStepUp(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	guarded := middleware.RequireStepUp(10 * time.Minute)(next)
+
+	tests := []struct {
+		name       string
+		verifiedAt time.Time
+		wantStatus int
+	}{
+		{
+			name:       "Recent",
+			verifiedAt: time.Now().Add(-time.Minute),
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "Stale",
+			verifiedAt: time.Now().Add(-time.Hour),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "Never",
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodDelete, "/products/1", nil)
+			ctx := context.WithValue(
+				req.Context(),
+				middleware.MFAVerifiedAtKey,
+				tt.verifiedAt,
+			)
+			w := httptest.NewRecorder()
+
+			guarded.ServeHTTP(w, req.WithContext(ctx))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusUnauthorized {
+				assert.Contains(
+					t,
+					w.Header().Get("WWW-Authenticate"),
+					`error="insufficient_user_authentication"`,
+				)
+				assert.Contains(
+					t,
+					w.Header().Get("WWW-Authenticate"),
+					"max_age=600",
+				)
+			}
+		})
+	}
+}
@@ -12,12 +12,14 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	handler "github.com/grocery-service/internal/api/handlers"
+	"github.com/grocery-service/internal/api/middleware"
 	"github.com/grocery-service/internal/domain"
 	serviceMock "github.com/grocery-service/tests/mocks/service"
 	"github.com/grocery-service/utils/api"
 	customErrors "github.com/grocery-service/utils/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func setupOrderTest() (
@@ -25,10 +27,31 @@ func setupOrderTest() (
 	*handler.OrderHandler,
 ) {
 	mockService := new(serviceMock.OrderService)
-	handler := handler.NewOrderHandler(mockService)
+	handler := handler.NewOrderHandler(
+		mockService,
+		new(serviceMock.CustomerService),
+	)
 	return mockService, handler
 }
 
+// withPermissions returns ctx for a signed-in staff caller holding
+// permissions.
+func withPermissions(
+	ctx context.Context,
+	permissions ...domain.Permission,
+) context.Context {
+	granted := make(map[domain.Permission]bool, len(permissions))
+	for _, p := range permissions {
+		granted[p] = true
+	}
+	ctx = context.WithValue(
+		ctx,
+		middleware.UserRoleKey,
+		string(domain.StoreManagerRole),
+	)
+	return context.WithValue(ctx, middleware.PermissionsKey, granted)
+}
+
 func TestOrderHandler_Create(t *testing.T) {
 	mockService, handler := setupOrderTest()
 
@@ -46,12 +69,12 @@ func TestOrderHandler_Create(t *testing.T) {
 			order: &domain.Order{
 				CustomerID: customerID,
 				Status:     domain.OrderStatusPending,
-				TotalPrice: 29.99,
+				TotalPrice: domain.NewMoney(2999, domain.DefaultCurrency),
 				Items: []domain.OrderItem{
 					{
 						ProductID: productID,
 						Quantity:  2,
-						Price:     14.99,
+						Price:     domain.NewMoney(1499, domain.DefaultCurrency),
 					},
 				},
 			},
@@ -69,7 +92,7 @@ func TestOrderHandler_Create(t *testing.T) {
 			order: &domain.Order{
 				CustomerID: customerID,
 				Status:     domain.OrderStatusPending,
-				TotalPrice: -1, // Invalid price
+				TotalPrice: domain.NewMoney(-100, domain.DefaultCurrency), // Invalid price
 			},
 			setupMock: func(o *domain.Order) {
 				mockService.On("Create", mock.Anything, mock.MatchedBy(func(order *domain.Order) bool {
@@ -81,6 +104,36 @@ func TestOrderHandler_Create(t *testing.T) {
 			wantStatus: http.StatusBadRequest,
 			wantError:  "invalid order data",
 		},
+		{
+			name: "Insufficient Stock",
+			order: &domain.Order{
+				CustomerID: customerID,
+				Status:     domain.OrderStatusPending,
+				TotalPrice: domain.NewMoney(9999, domain.DefaultCurrency),
+				Items: []domain.OrderItem{
+					{
+						ProductID: productID,
+						Quantity:  5,
+					},
+				},
+			},
+			setupMock: func(o *domain.Order) {
+				mockService.On("Create", mock.Anything, mock.MatchedBy(func(order *domain.Order) bool {
+					return order.CustomerID == o.CustomerID &&
+						order.TotalPrice == o.TotalPrice
+				})).
+					Return(fmt.Errorf("failed to create order: %w",
+						&customErrors.InsufficientStockError{
+							Shortfalls: []customErrors.StockShortfall{{
+								ProductID: productID.String(),
+								Requested: 5,
+								Available: 2,
+							}},
+						}))
+			},
+			wantStatus: http.StatusConflict,
+			wantError:  "insufficient stock",
+		},
 	}
 
 	for _, tt := range tests {
@@ -94,6 +147,9 @@ func TestOrderHandler_Create(t *testing.T) {
 				bytes.NewBuffer(jsonBody),
 			)
 			req.Header.Set("Content-Type", "application/json")
+			req = req.WithContext(
+				withPermissions(req.Context(), domain.PermissionOrdersManage),
+			)
 			w := httptest.NewRecorder()
 
 			handler.Create(w, req)
@@ -141,7 +197,7 @@ func TestOrderHandler_GetByID(t *testing.T) {
 					Return(&domain.Order{
 						ID:         testID,
 						CustomerID: customerID,
-						TotalPrice: 49.98,
+						TotalPrice: domain.NewMoney(4998, domain.DefaultCurrency),
 						Status:     domain.OrderStatusPreparing,
 					}, nil)
 			},
@@ -176,7 +232,11 @@ func TestOrderHandler_GetByID(t *testing.T) {
 			rctx.URLParams.Add("id", tt.id)
 			req := httptest.NewRequest(http.MethodGet, "/orders/"+tt.id, nil)
 			req = req.WithContext(
-				context.WithValue(req.Context(), chi.RouteCtxKey, rctx),
+				context.WithValue(
+					withPermissions(req.Context(), domain.PermissionOrdersRead),
+					chi.RouteCtxKey,
+					rctx,
+				),
 			)
 			w := httptest.NewRecorder()
 
@@ -210,6 +270,7 @@ func TestOrderHandler_List(t *testing.T) {
 
 	tests := []struct {
 		name       string
+		query      string
 		setupMock  func()
 		wantStatus int
 		wantCount  int
@@ -218,21 +279,21 @@ func TestOrderHandler_List(t *testing.T) {
 		{
 			name: "Success",
 			setupMock: func() {
-				mockService.On("List", mock.Anything).
-					Return([]domain.Order{
+				mockService.On("List", mock.Anything, mock.Anything).
+					Return(&domain.Page[domain.Order]{Items: []domain.Order{
 						{
 							ID:         uuid.New(),
 							CustomerID: uuid.New(),
-							TotalPrice: 29.99,
+							TotalPrice: domain.NewMoney(2999, domain.DefaultCurrency),
 							Status:     domain.OrderStatusPending,
 						},
 						{
 							ID:         uuid.New(),
 							CustomerID: uuid.New(),
-							TotalPrice: 49.98,
+							TotalPrice: domain.NewMoney(4998, domain.DefaultCurrency),
 							Status:     domain.OrderStatusConfirmed,
 						},
-					}, nil)
+					}}, nil)
 			},
 			wantStatus: http.StatusOK,
 			wantCount:  2,
@@ -240,13 +301,33 @@ func TestOrderHandler_List(t *testing.T) {
 		{
 			name: "Internal Error",
 			setupMock: func() {
-				mockService.On("List", mock.Anything).
-					Return([]domain.Order{}, fmt.Errorf("database error")).
+				mockService.On("List", mock.Anything, mock.Anything).
+					Return(&domain.Page[domain.Order]{Items: []domain.Order{}}, fmt.Errorf("database error")).
 					Once()
 			},
 			wantStatus: http.StatusInternalServerError,
 			wantError:  "Failed to list orders",
 		},
+		{
+			name:  "Success - Status Filter",
+			query: "?status=delivered",
+			setupMock: func() {
+				mockService.On("List", mock.Anything, mock.MatchedBy(
+					func(q domain.ListQuery) bool {
+						return q.Filter.Status == domain.OrderStatusDelivered
+					},
+				)).Return(&domain.Page[domain.Order]{Items: []domain.Order{}}, nil).
+					Once()
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "Invalid Status Filter",
+			query:      "?status=lost",
+			setupMock:  func() {},
+			wantStatus: http.StatusBadRequest,
+			wantError:  "invalid status",
+		},
 	}
 
 	for _, tt := range tests {
@@ -254,7 +335,7 @@ func TestOrderHandler_List(t *testing.T) {
 			mockService.ExpectedCalls = nil
 			tt.setupMock()
 
-			req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+			req := httptest.NewRequest(http.MethodGet, "/orders"+tt.query, nil)
 			w := httptest.NewRecorder()
 
 			handler.List(w, req)
@@ -301,21 +382,21 @@ func TestOrderHandler_ListByCustomerID(t *testing.T) {
 			name:       "Success",
 			customerID: customerID.String(),
 			setupMock: func() {
-				mockService.On("ListByCustomerID", mock.Anything, customerID.String()).
-					Return([]domain.Order{
+				mockService.On("ListByCustomerID", mock.Anything, customerID.String(), mock.Anything).
+					Return(&domain.Page[domain.Order]{Items: []domain.Order{
 						{
 							ID:         uuid.New(),
 							CustomerID: customerID,
-							TotalPrice: 29.99,
+							TotalPrice: domain.NewMoney(2999, domain.DefaultCurrency),
 							Status:     domain.OrderStatusPending,
 						},
 						{
 							ID:         uuid.New(),
 							CustomerID: customerID,
-							TotalPrice: 49.98,
+							TotalPrice: domain.NewMoney(4998, domain.DefaultCurrency),
 							Status:     domain.OrderStatusShipped,
 						},
-					}, nil)
+					}}, nil)
 			},
 			wantStatus: http.StatusOK,
 			wantCount:  2,
@@ -331,8 +412,8 @@ func TestOrderHandler_ListByCustomerID(t *testing.T) {
 			name:       "No Orders Found",
 			customerID: customerID.String(),
 			setupMock: func() {
-				mockService.On("ListByCustomerID", mock.Anything, customerID.String()).
-					Return(make([]domain.Order, 0), nil).
+				mockService.On("ListByCustomerID", mock.Anything, customerID.String(), mock.Anything).
+					Return(&domain.Page[domain.Order]{Items: make([]domain.Order, 0)}, nil).
 					Once()
 			},
 			wantStatus: http.StatusOK,
@@ -353,7 +434,11 @@ func TestOrderHandler_ListByCustomerID(t *testing.T) {
 				nil,
 			)
 			req = req.WithContext(
-				context.WithValue(req.Context(), chi.RouteCtxKey, rctx),
+				context.WithValue(
+					withPermissions(req.Context(), domain.PermissionOrdersRead),
+					chi.RouteCtxKey,
+					rctx,
+				),
 			)
 			w := httptest.NewRecorder()
 
@@ -388,6 +473,7 @@ func TestOrderHandler_UpdateStatus(t *testing.T) {
 	mockService, handler := setupOrderTest()
 
 	testID := uuid.New()
+	actorID := uuid.New().String()
 	tests := []struct {
 		name       string
 		id         string
@@ -401,7 +487,7 @@ func TestOrderHandler_UpdateStatus(t *testing.T) {
 			id:     testID.String(),
 			status: domain.OrderStatusDelivered,
 			setupMock: func() {
-				mockService.On("UpdateStatus", mock.Anything, testID.String(), domain.OrderStatusDelivered).
+				mockService.On("UpdateStatus", mock.Anything, testID.String(), domain.OrderStatusDelivered, actorID, "left at the door").
 					Return(nil).
 					Once()
 			},
@@ -412,7 +498,7 @@ func TestOrderHandler_UpdateStatus(t *testing.T) {
 			id:     testID.String(),
 			status: "invalid_status",
 			setupMock: func() {
-				mockService.On("UpdateStatus", mock.Anything, testID.String(), domain.OrderStatus("invalid_status")).
+				mockService.On("UpdateStatus", mock.Anything, testID.String(), domain.OrderStatus("invalid_status"), actorID, "left at the door").
 					Return(customErrors.ErrOrderStatusInvalid).
 					Once()
 			},
@@ -424,7 +510,7 @@ func TestOrderHandler_UpdateStatus(t *testing.T) {
 			id:     testID.String(),
 			status: domain.OrderStatusDelivered,
 			setupMock: func() {
-				mockService.On("UpdateStatus", mock.Anything, testID.String(), domain.OrderStatusDelivered).
+				mockService.On("UpdateStatus", mock.Anything, testID.String(), domain.OrderStatusDelivered, actorID, "left at the door").
 					Return(customErrors.ErrOrderNotFound).
 					Once()
 			},
@@ -440,8 +526,10 @@ func TestOrderHandler_UpdateStatus(t *testing.T) {
 
 			statusUpdate := struct {
 				Status domain.OrderStatus `json:"status"`
+				Reason string             `json:"reason"`
 			}{
 				Status: tt.status,
+				Reason: "left at the door",
 			}
 
 			jsonBody, _ := json.Marshal(statusUpdate)
@@ -454,9 +542,9 @@ func TestOrderHandler_UpdateStatus(t *testing.T) {
 
 			rctx := chi.NewRouteContext()
 			rctx.URLParams.Add("id", tt.id)
-			req = req.WithContext(
-				context.WithValue(req.Context(), chi.RouteCtxKey, rctx),
-			)
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, middleware.UserIDKey, actorID)
+			req = req.WithContext(ctx)
 
 			w := httptest.NewRecorder()
 
@@ -478,6 +566,179 @@ func TestOrderHandler_UpdateStatus(t *testing.T) {
 	}
 }
 
+func TestOrderHandler_Refund(t *testing.T) {
+	mockService, handler := setupOrderTest()
+
+	testID := uuid.New()
+	itemID := uuid.New()
+	lines := []domain.RefundLine{
+		{ItemID: itemID, Action: domain.RefundActionRestock},
+	}
+	tests := []struct {
+		name       string
+		id         string
+		body       string
+		setupMock  func()
+		wantStatus int
+	}{
+		{
+			name: "Success",
+			id:   testID.String(),
+			body: `{"reason": "wrong item", "items": [{"item_id": "` +
+				itemID.String() + `", "action": "RESTOCK"}]}`,
+			setupMock: func() {
+				mockService.On("Refund", mock.Anything, testID.String(), "", "wrong item", lines).
+					Return(nil).
+					Once()
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Invalid Refund Lines",
+			id:   testID.String(),
+			body: `{"items": [{"item_id": "` + itemID.String() + `", "action": "RESTOCK"}]}`,
+			setupMock: func() {
+				mockService.On("Refund", mock.Anything, testID.String(), "", "", lines).
+					Return(customErrors.ErrInvalidOrderData).
+					Once()
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Order Not Found",
+			id:   testID.String(),
+			body: `{}`,
+			setupMock: func() {
+				mockService.On("Refund", mock.Anything, testID.String(), "", "", []domain.RefundLine(nil)).
+					Return(customErrors.ErrOrderNotFound).
+					Once()
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "Invalid Body",
+			id:         testID.String(),
+			body:       `{`,
+			setupMock:  func() {},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			req := httptest.NewRequest(
+				http.MethodPost,
+				"/orders/"+tt.id+"/refund",
+				bytes.NewBufferString(tt.body),
+			)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+			req = req.WithContext(
+				context.WithValue(req.Context(), chi.RouteCtxKey, rctx),
+			)
+			w := httptest.NewRecorder()
+
+			handler.Refund(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestOrderHandler_GetStatusHistory(t *testing.T) {
+	mockService, handler := setupOrderTest()
+
+	testID := uuid.New()
+	tests := []struct {
+		name       string
+		id         string
+		setupMock  func()
+		wantStatus int
+		wantCount  int
+	}{
+		{
+			name: "Success",
+			id:   testID.String(),
+			setupMock: func() {
+				mockService.On("GetStatusHistory", mock.Anything, testID.String()).
+					Return([]domain.OrderStatusChange{
+						{
+							ID:         uuid.New(),
+							OrderID:    testID,
+							FromStatus: domain.OrderStatusPending,
+							ToStatus:   domain.OrderStatusConfirmed,
+						},
+						{
+							ID:         uuid.New(),
+							OrderID:    testID,
+							FromStatus: domain.OrderStatusConfirmed,
+							ToStatus:   domain.OrderStatusCancelled,
+							Reason:     "customer request",
+						},
+					}, nil).
+					Once()
+			},
+			wantStatus: http.StatusOK,
+			wantCount:  2,
+		},
+		{
+			name: "Order Not Found",
+			id:   testID.String(),
+			setupMock: func() {
+				mockService.On("GetStatusHistory", mock.Anything, testID.String()).
+					Return(nil, customErrors.ErrOrderNotFound).
+					Once()
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "Invalid Order ID",
+			id:         "invalid-uuid",
+			setupMock:  func() {},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			req := httptest.NewRequest(
+				http.MethodGet,
+				"/orders/"+tt.id+"/history",
+				nil,
+			)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+			req = req.WithContext(
+				context.WithValue(
+					withPermissions(req.Context(), domain.PermissionOrdersRead),
+					chi.RouteCtxKey,
+					rctx,
+				),
+			)
+			w := httptest.NewRecorder()
+
+			handler.GetStatusHistory(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusOK {
+				var response struct {
+					Data []domain.OrderStatusChange `json:"data"`
+				}
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+				assert.Len(t, response.Data, tt.wantCount)
+			}
+		})
+	}
+
+	mockService.AssertExpectations(t)
+}
+
 func TestOrderHandler_AddOrderItem(t *testing.T) {
 	mockService, handler := setupOrderTest()
 
@@ -498,7 +759,7 @@ func TestOrderHandler_AddOrderItem(t *testing.T) {
 				ProductID: productID,
 				OrderID:   orderID,
 				Quantity:  3,
-				Price:     9.99,
+				Price:     domain.NewMoney(999, domain.DefaultCurrency),
 			},
 			setupMock: func() {
 				mockService.On(
@@ -516,7 +777,7 @@ func TestOrderHandler_AddOrderItem(t *testing.T) {
 			item: &domain.OrderItem{
 				ProductID: productID,
 				Quantity:  3,
-				Price:     9.99,
+				Price:     domain.NewMoney(999, domain.DefaultCurrency),
 			},
 			setupMock:  func() {},
 			wantStatus: http.StatusBadRequest,
@@ -529,7 +790,7 @@ func TestOrderHandler_AddOrderItem(t *testing.T) {
 				ProductID: productID,
 				OrderID:   orderID,
 				Quantity:  3,
-				Price:     9.99,
+				Price:     domain.NewMoney(999, domain.DefaultCurrency),
 			},
 			setupMock: func() {
 				mockService.On(
@@ -549,7 +810,7 @@ func TestOrderHandler_AddOrderItem(t *testing.T) {
 				ProductID: productID,
 				OrderID:   orderID,
 				Quantity:  0,
-				Price:     9.99,
+				Price:     domain.NewMoney(999, domain.DefaultCurrency),
 			},
 			setupMock: func() {
 				mockService.On(
@@ -580,7 +841,11 @@ func TestOrderHandler_AddOrderItem(t *testing.T) {
 			rctx := chi.NewRouteContext()
 			rctx.URLParams.Add("id", tt.orderID)
 			req = req.WithContext(
-				context.WithValue(req.Context(), chi.RouteCtxKey, rctx),
+				context.WithValue(
+					withPermissions(req.Context(), domain.PermissionOrdersManage),
+					chi.RouteCtxKey,
+					rctx,
+				),
 			)
 
 			w := httptest.NewRecorder()
@@ -692,7 +957,11 @@ func TestOrderHandler_RemoveOrderItem(t *testing.T) {
 				nil,
 			)
 			req = req.WithContext(
-				context.WithValue(req.Context(), chi.RouteCtxKey, rctx),
+				context.WithValue(
+					withPermissions(req.Context(), domain.PermissionOrdersManage),
+					chi.RouteCtxKey,
+					rctx,
+				),
 			)
 			w := httptest.NewRecorder()
 
@@ -714,3 +983,263 @@ func TestOrderHandler_RemoveOrderItem(t *testing.T) {
 		})
 	}
 }
+
+func TestOrderHandler_Ownership(t *testing.T) {
+	userID := uuid.New()
+	own := &domain.Customer{ID: uuid.New(), UserID: userID}
+	otherID := uuid.New()
+	ownOrder := &domain.Order{ID: uuid.New(), CustomerID: own.ID}
+	otherOrder := &domain.Order{ID: uuid.New(), CustomerID: otherID}
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		params      map[string]string
+		body        string
+		serve       func(*handler.OrderHandler) http.HandlerFunc
+		setupMock   func(*serviceMock.OrderService)
+		noProfile   bool
+		permissions []domain.Permission
+		wantStatus  int
+	}{
+		{
+			name:   "Own Order",
+			method: http.MethodGet,
+			path:   "/orders/" + ownOrder.ID.String(),
+			params: map[string]string{"id": ownOrder.ID.String()},
+			serve: func(h *handler.OrderHandler) http.HandlerFunc {
+				return h.GetByID
+			},
+			setupMock: func(m *serviceMock.OrderService) {
+				m.On("GetByID", mock.Anything, ownOrder.ID.String()).
+					Return(ownOrder, nil).Once()
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "Other Customer's Order",
+			method: http.MethodGet,
+			path:   "/orders/" + otherOrder.ID.String(),
+			params: map[string]string{"id": otherOrder.ID.String()},
+			serve: func(h *handler.OrderHandler) http.HandlerFunc {
+				return h.GetByID
+			},
+			setupMock: func(m *serviceMock.OrderService) {
+				m.On("GetByID", mock.Anything, otherOrder.ID.String()).
+					Return(otherOrder, nil).Once()
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:   "Other Customer's History",
+			method: http.MethodGet,
+			path:   "/orders/" + otherOrder.ID.String() + "/history",
+			params: map[string]string{"id": otherOrder.ID.String()},
+			serve: func(h *handler.OrderHandler) http.HandlerFunc {
+				return h.GetStatusHistory
+			},
+			setupMock: func(m *serviceMock.OrderService) {
+				m.On("GetByID", mock.Anything, otherOrder.ID.String()).
+					Return(otherOrder, nil).Once()
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:   "Other Customer's Orders",
+			method: http.MethodGet,
+			path:   "/orders/customer/" + otherID.String(),
+			params: map[string]string{"customerID": otherID.String()},
+			serve: func(h *handler.OrderHandler) http.HandlerFunc {
+				return h.ListByCustomerID
+			},
+			setupMock:  func(*serviceMock.OrderService) {},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:   "Order For Other Customer",
+			method: http.MethodPost,
+			path:   "/orders",
+			body: fmt.Sprintf(
+				`{"customer_id": %q, "items": []}`,
+				otherID,
+			),
+			serve: func(h *handler.OrderHandler) http.HandlerFunc {
+				return h.Create
+			},
+			setupMock:  func(*serviceMock.OrderService) {},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:   "Order For Other Customer With orders:read",
+			method: http.MethodPost,
+			path:   "/orders",
+			body: fmt.Sprintf(
+				`{"customer_id": %q, "items": []}`,
+				otherID,
+			),
+			serve: func(h *handler.OrderHandler) http.HandlerFunc {
+				return h.Create
+			},
+			setupMock:   func(*serviceMock.OrderService) {},
+			permissions: []domain.Permission{domain.PermissionOrdersRead},
+			wantStatus:  http.StatusForbidden,
+		},
+		{
+			name:   "Add Item To Own Order",
+			method: http.MethodPost,
+			path:   "/orders/" + ownOrder.ID.String() + "/items",
+			params: map[string]string{"id": ownOrder.ID.String()},
+			body: fmt.Sprintf(
+				`{"product_id": %q, "quantity": 1}`,
+				uuid.New(),
+			),
+			serve: func(h *handler.OrderHandler) http.HandlerFunc {
+				return h.AddOrderItem
+			},
+			setupMock: func(m *serviceMock.OrderService) {
+				m.On("GetByID", mock.Anything, ownOrder.ID.String()).
+					Return(ownOrder, nil).Once()
+				m.On(
+					"AddOrderItem",
+					mock.Anything,
+					ownOrder.ID.String(),
+					mock.Anything,
+				).Return(nil).Once()
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:   "Add Item To Other Customer's Order",
+			method: http.MethodPost,
+			path:   "/orders/" + otherOrder.ID.String() + "/items",
+			params: map[string]string{"id": otherOrder.ID.String()},
+			body: fmt.Sprintf(
+				`{"product_id": %q, "quantity": 1}`,
+				uuid.New(),
+			),
+			serve: func(h *handler.OrderHandler) http.HandlerFunc {
+				return h.AddOrderItem
+			},
+			setupMock: func(m *serviceMock.OrderService) {
+				m.On("GetByID", mock.Anything, otherOrder.ID.String()).
+					Return(otherOrder, nil).Once()
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:   "Remove Item From Other Customer's Order With orders:read",
+			method: http.MethodDelete,
+			path: "/orders/" + otherOrder.ID.String() +
+				"/items/" + uuid.NewString(),
+			params: map[string]string{
+				"id":     otherOrder.ID.String(),
+				"itemID": uuid.NewString(),
+			},
+			serve: func(h *handler.OrderHandler) http.HandlerFunc {
+				return h.RemoveOrderItem
+			},
+			setupMock: func(m *serviceMock.OrderService) {
+				m.On("GetByID", mock.Anything, otherOrder.ID.String()).
+					Return(otherOrder, nil).Once()
+			},
+			permissions: []domain.Permission{domain.PermissionOrdersRead},
+			wantStatus:  http.StatusNotFound,
+		},
+		{
+			name:   "List Mine",
+			method: http.MethodGet,
+			path:   "/orders/me",
+			serve: func(h *handler.OrderHandler) http.HandlerFunc {
+				return h.ListMine
+			},
+			setupMock: func(m *serviceMock.OrderService) {
+				m.On(
+					"ListByCustomerID",
+					mock.Anything,
+					own.ID.String(),
+					mock.Anything,
+				).Return(&domain.Page[domain.Order]{
+					Items: []domain.Order{*ownOrder},
+				}, nil).Once()
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "List Mine Without Profile",
+			method: http.MethodGet,
+			path:   "/orders/me",
+			serve: func(h *handler.OrderHandler) http.HandlerFunc {
+				return h.ListMine
+			},
+			setupMock:  func(*serviceMock.OrderService) {},
+			noProfile:  true,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:   "Create Mine",
+			method: http.MethodPost,
+			path:   "/orders/me",
+			body: fmt.Sprintf(
+				`{"customer_id": %q, "items": []}`,
+				otherID,
+			),
+			serve: func(h *handler.OrderHandler) http.HandlerFunc {
+				return h.CreateMine
+			},
+			setupMock: func(m *serviceMock.OrderService) {
+				m.On(
+					"Create",
+					mock.Anything,
+					mock.MatchedBy(func(o *domain.Order) bool {
+						return o.CustomerID == own.ID
+					}),
+				).Return(nil).Once()
+			},
+			wantStatus: http.StatusCreated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders := new(serviceMock.OrderService)
+			customers := new(serviceMock.CustomerService)
+			h := handler.NewOrderHandler(orders, customers)
+
+			tt.setupMock(orders)
+			if tt.noProfile {
+				customers.On("GetByUserID", mock.Anything, userID.String()).
+					Return(nil, customErrors.ErrCustomerNotFound).Maybe()
+			} else {
+				customers.On("GetByUserID", mock.Anything, userID.String()).
+					Return(own, nil).Maybe()
+			}
+
+			req := httptest.NewRequest(
+				tt.method,
+				tt.path,
+				bytes.NewBufferString(tt.body),
+			)
+			rctx := chi.NewRouteContext()
+			for key, value := range tt.params {
+				rctx.URLParams.Add(key, value)
+			}
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, middleware.UserIDKey, userID.String())
+			ctx = context.WithValue(
+				ctx,
+				middleware.UserRoleKey,
+				string(domain.CustomerRole),
+			)
+			if tt.permissions != nil {
+				ctx = withPermissions(ctx, tt.permissions...)
+			}
+			w := httptest.NewRecorder()
+
+			tt.serve(h)(w, req.WithContext(ctx))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			orders.AssertExpectations(t)
+		})
+	}
+}
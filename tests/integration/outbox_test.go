@@ -0,0 +1,186 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	handler "github.com/grocery-service/internal/api/handlers"
+	"github.com/grocery-service/internal/domain"
+	serviceMock "github.com/grocery-service/tests/mocks/service"
+	"github.com/grocery-service/utils/api"
+	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupOutboxTest() (
+	*serviceMock.OutboxService,
+	*handler.OutboxHandler,
+) {
+	mockService := new(serviceMock.OutboxService)
+	handler := handler.NewOutboxHandler(mockService)
+	return mockService, handler
+}
+
+func TestOutboxHandler_List(t *testing.T) {
+	mockService, handler := setupOutboxTest()
+
+	tests := []struct {
+		name       string
+		query      string
+		setupMock  func()
+		wantStatus int
+		wantTotal  int64
+	}{
+		{
+			name:  "Success - Dead Events",
+			query: "?status=dead&limit=10",
+			setupMock: func() {
+				mockService.On(
+					"List",
+					mock.Anything,
+					domain.OutboxStatusDead,
+					mock.MatchedBy(func(q domain.ListQuery) bool {
+						return q.Limit == 10 && q.Filter.Status == ""
+					}),
+				).Return(&domain.Page[domain.OutboxEvent]{
+					Items: []domain.OutboxEvent{{
+						ID:        uuid.New(),
+						EventType: domain.OutboxEventOrderConfirmation,
+						Status:    domain.OutboxStatusDead,
+						Attempts:  8,
+						LastError: "smtp: connection refused",
+					}},
+					Total: 1,
+					Limit: 10,
+					Page:  1,
+				}, nil).Once()
+			},
+			wantStatus: http.StatusOK,
+			wantTotal:  1,
+		},
+		{
+			name:  "Invalid Status",
+			query: "?status=unknown",
+			setupMock: func() {
+				mockService.On(
+					"List",
+					mock.Anything,
+					domain.OutboxStatus("UNKNOWN"),
+					mock.Anything,
+				).Return(nil, customErrors.ErrInvalidListQuery).Once()
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Invalid Limit",
+			query:      "?limit=abc",
+			setupMock:  func() {},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			req := httptest.NewRequest(
+				http.MethodGet,
+				"/admin/outbox"+tt.query,
+				nil,
+			)
+			w := httptest.NewRecorder()
+
+			handler.List(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusOK {
+				var response api.Response
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+				require.NotNil(t, response.Meta)
+				assert.Equal(t, tt.wantTotal, response.Meta.Total)
+			}
+		})
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestOutboxHandler_Replay(t *testing.T) {
+	mockService, handler := setupOutboxTest()
+	eventID := uuid.New().String()
+
+	tests := []struct {
+		name       string
+		eventID    string
+		setupMock  func()
+		wantStatus int
+	}{
+		{
+			name:    "Success",
+			eventID: eventID,
+			setupMock: func() {
+				mockService.On("Replay", mock.Anything, eventID).
+					Return(&domain.OutboxEvent{
+						ID:     uuid.MustParse(eventID),
+						Status: domain.OutboxStatusPending,
+					}, nil).Once()
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:    "Not Dead",
+			eventID: eventID,
+			setupMock: func() {
+				mockService.On("Replay", mock.Anything, eventID).
+					Return(nil, customErrors.ErrOutboxEventNotReplayable).Once()
+			},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:    "Not Found",
+			eventID: eventID,
+			setupMock: func() {
+				mockService.On("Replay", mock.Anything, eventID).
+					Return(nil, customErrors.ErrOutboxEventNotFound).Once()
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "Invalid Event ID",
+			eventID:    "invalid-uuid",
+			setupMock:  func() {},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			req := httptest.NewRequest(
+				http.MethodPost,
+				"/admin/outbox/"+tt.eventID+"/replay",
+				nil,
+			)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.eventID)
+			req = req.WithContext(
+				context.WithValue(req.Context(), chi.RouteCtxKey, rctx),
+			)
+			w := httptest.NewRecorder()
+
+			handler.Replay(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,263 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grocery-service/internal/domain"
+	"github.com/grocery-service/utils/api"
+	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthHandler_Register(t *testing.T) {
+	mockService, handler := setupSessionTest()
+	request := domain.RegisterRequest{
+		Email:    "jane@example.com",
+		Password: "correct-horse",
+		Name:     "Jane",
+	}
+	body := `{"email": "jane@example.com", "password": "correct-horse", "name": "Jane"}`
+
+	tests := []struct {
+		name       string
+		body       string
+		setupMock  func()
+		wantStatus int
+		wantError  string
+	}{
+		{
+			name: "Success",
+			body: body,
+			setupMock: func() {
+				mockService.On("Register", mock.Anything, request).
+					Return(nil).Once()
+			},
+			wantStatus: http.StatusAccepted,
+		},
+		{
+			name: "Weak Password",
+			body: body,
+			setupMock: func() {
+				mockService.On("Register", mock.Anything, request).
+					Return(fmt.Errorf(
+						"%w: password: Password must be at least 8 characters",
+						customErrors.ErrInvalidUserData,
+					)).Once()
+			},
+			wantStatus: http.StatusBadRequest,
+			wantError:  "password: Password must be at least 8 characters",
+		},
+		{
+			name:       "Invalid Body",
+			body:       `{"email":`,
+			setupMock:  func() {},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			req := httptest.NewRequest(
+				http.MethodPost,
+				"/auth/register",
+				strings.NewReader(tt.body),
+			)
+			w := httptest.NewRecorder()
+
+			handler.Register(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+
+			if tt.wantError != "" {
+				var response api.Response
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+				assert.Equal(t, tt.wantError, response.Error)
+			}
+		})
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAuthHandler_PasswordLogin(t *testing.T) {
+	mockService, handler := setupSessionTest()
+	request := domain.PasswordLoginRequest{
+		Email:    "jane@example.com",
+		Password: "correct-horse",
+	}
+	client := domain.ClientInfo{IPAddress: "203.0.113.7"}
+
+	tests := []struct {
+		name           string
+		err            error
+		wantStatus     int
+		wantRetryAfter int
+	}{
+		{name: "Success", wantStatus: http.StatusOK},
+		{
+			name:       "Wrong Password",
+			err:        customErrors.ErrInvalidCredentials,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "Not Verified",
+			err:        customErrors.ErrEmailNotVerified,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name: "Locked",
+			err: &customErrors.AccountLockedError{
+				Until: time.Now().Add(90 * time.Second),
+			},
+			wantStatus:     http.StatusTooManyRequests,
+			wantRetryAfter: 90,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var response *domain.AuthResponse
+			if tt.err == nil {
+				response = &domain.AuthResponse{AccessToken: "access-token"}
+			}
+			mockService.On("LoginWithPassword", mock.Anything, request, client).
+				Return(response, tt.err).Once()
+
+			req := httptest.NewRequest(
+				http.MethodPost,
+				"/auth/login/password",
+				strings.NewReader(
+					`{"email": "jane@example.com", "password": "correct-horse"}`,
+				),
+			)
+			req.RemoteAddr = "203.0.113.7:52100"
+			w := httptest.NewRecorder()
+
+			handler.PasswordLogin(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantRetryAfter > 0 {
+				retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After"))
+				require.NoError(t, err)
+				assert.InDelta(t, tt.wantRetryAfter, retryAfter, 1)
+			}
+		})
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAuthHandler_VerifyEmail(t *testing.T) {
+	mockService, handler := setupSessionTest()
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "Success", wantStatus: http.StatusOK},
+		{
+			name:       "Used Or Expired",
+			err:        customErrors.ErrInvalidToken,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService.On("VerifyEmail", mock.Anything, "verify-token").
+				Return(tt.err).Once()
+
+			req := httptest.NewRequest(
+				http.MethodPost,
+				"/auth/verify-email",
+				strings.NewReader(`{"token": "verify-token"}`),
+			)
+			w := httptest.NewRecorder()
+
+			handler.VerifyEmail(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAuthHandler_ForgotPassword(t *testing.T) {
+	mockService, handler := setupSessionTest()
+
+	mockService.On("RequestPasswordReset", mock.Anything, "ghost@example.com").
+		Return(nil).Once()
+
+	req := httptest.NewRequest(
+		http.MethodPost,
+		"/auth/password/forgot",
+		strings.NewReader(`{"email": "ghost@example.com"}`),
+	)
+	w := httptest.NewRecorder()
+
+	handler.ForgotPassword(w, req)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestAuthHandler_ResetPassword(t *testing.T) {
+	mockService, handler := setupSessionTest()
+	request := domain.ResetPasswordRequest{
+		Token:    "reset-token",
+		Password: "battery-staple",
+	}
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "Success", wantStatus: http.StatusOK},
+		{
+			name:       "Used Or Expired",
+			err:        customErrors.ErrInvalidToken,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Server Error",
+			err:        customErrors.ErrDBQuery,
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService.On("ResetPassword", mock.Anything, request).
+				Return(tt.err).Once()
+
+			req := httptest.NewRequest(
+				http.MethodPost,
+				"/auth/password/reset",
+				strings.NewReader(
+					`{"token": "reset-token", "password": "battery-staple"}`,
+				),
+			)
+			w := httptest.NewRecorder()
+
+			handler.ResetPassword(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+
+	mockService.AssertExpectations(t)
+}
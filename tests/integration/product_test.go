@@ -45,7 +45,7 @@ func TestProductHandler_Create(t *testing.T) {
 			product: &domain.Product{
 				Name:        "Apple",
 				Description: "Fresh red apple",
-				Price:       1.99,
+				Price:       domain.NewMoney(199, domain.DefaultCurrency),
 				CategoryID:  categoryID,
 				Stock:       100,
 			},
@@ -62,7 +62,7 @@ func TestProductHandler_Create(t *testing.T) {
 			name: "Invalid Product",
 			product: &domain.Product{
 				Name:  "",
-				Price: -1,
+				Price: domain.NewMoney(-100, domain.DefaultCurrency),
 			},
 			setupMock: func(p *domain.Product) {
 				mockService.On("Create", mock.Anything, mock.MatchedBy(func(product *domain.Product) bool {
@@ -135,7 +135,7 @@ func TestProductHandler_GetByID(t *testing.T) {
 					Return(&domain.Product{
 						ID:    testID,
 						Name:  "Banana",
-						Price: 0.99,
+						Price: domain.NewMoney(99, domain.DefaultCurrency),
 						Stock: 50,
 					}, nil)
 			},
@@ -214,19 +214,19 @@ func TestProductHandler_List(t *testing.T) {
 		{
 			name: "Success",
 			setupMock: func() {
-				mockService.On("List", mock.Anything).
-					Return([]domain.Product{
+				mockService.On("List", mock.Anything, mock.Anything).
+					Return(&domain.Page[domain.Product]{Items: []domain.Product{
 						{
 							ID:    uuid.New(),
 							Name:  "Apple",
-							Price: 1.99,
+							Price: domain.NewMoney(199, domain.DefaultCurrency),
 						},
 						{
 							ID:    uuid.New(),
 							Name:  "Banana",
-							Price: 0.99,
+							Price: domain.NewMoney(99, domain.DefaultCurrency),
 						},
-					}, nil)
+					}}, nil)
 			},
 			wantStatus: http.StatusOK,
 			wantCount:  2,
@@ -234,7 +234,7 @@ func TestProductHandler_List(t *testing.T) {
 		{
 			name: "Internal Error",
 			setupMock: func() {
-				mockService.On("List", mock.Anything).
+				mockService.On("List", mock.Anything, mock.Anything).
 					Return(nil, fmt.Errorf("database error"))
 			},
 			wantStatus: http.StatusInternalServerError,
@@ -278,6 +278,231 @@ func TestProductHandler_List(t *testing.T) {
 	}
 }
 
+func TestProductHandler_ListPagination(t *testing.T) {
+	mockService, handler := setupProductTest()
+	categoryID := uuid.New()
+	minPrice := domain.NewMoney(150, domain.DefaultCurrency)
+
+	tests := []struct {
+		name       string
+		query      string
+		setupMock  func()
+		wantStatus int
+		wantMeta   *api.ListMeta
+		wantLinks  []string
+		wantError  string
+	}{
+		{
+			name:  "Success - Page With Filters",
+			query: "?limit=2&page=2&sort=price&order=desc&min_price=1.5&category_id=" + categoryID.String(),
+			setupMock: func() {
+				mockService.On("List", mock.Anything, domain.ListQuery{
+					Limit:   2,
+					Page:    2,
+					SortBy:  "price",
+					SortDir: domain.SortDesc,
+					Filter: domain.ListFilter{
+						MinPrice:   &minPrice,
+						CategoryID: &categoryID,
+					},
+				}).Return(&domain.Page[domain.Product]{
+					Items:      []domain.Product{{ID: uuid.New()}, {ID: uuid.New()}},
+					Total:      7,
+					Limit:      2,
+					Page:       2,
+					NextCursor: "abc",
+				}, nil).Once()
+			},
+			wantStatus: http.StatusOK,
+			wantMeta: &api.ListMeta{
+				Total:      7,
+				Limit:      2,
+				Page:       2,
+				NextCursor: "abc",
+			},
+			wantLinks: []string{`page=3`, `rel="next"`, `page=1`, `rel="prev"`, `page=4`, `rel="last"`},
+		},
+		{
+			name:  "Success - Cursor",
+			query: "?cursor=abc",
+			setupMock: func() {
+				mockService.On("List", mock.Anything, domain.ListQuery{
+					Limit:   domain.DefaultListLimit,
+					Cursor:  "abc",
+					SortDir: domain.SortAsc,
+				}).Return(&domain.Page[domain.Product]{
+					Items:      []domain.Product{{ID: uuid.New()}},
+					Total:      30,
+					Limit:      domain.DefaultListLimit,
+					NextCursor: "def",
+				}, nil).Once()
+			},
+			wantStatus: http.StatusOK,
+			wantMeta: &api.ListMeta{
+				Total:      30,
+				Limit:      domain.DefaultListLimit,
+				NextCursor: "def",
+			},
+			wantLinks: []string{`cursor=def`, `rel="next"`},
+		},
+		{
+			name:       "Invalid Limit",
+			query:      "?limit=abc",
+			setupMock:  func() {},
+			wantStatus: http.StatusBadRequest,
+			wantError:  "invalid limit",
+		},
+		{
+			name:       "Invalid Sort Direction",
+			query:      "?order=sideways",
+			setupMock:  func() {},
+			wantStatus: http.StatusBadRequest,
+			wantError:  "sort direction",
+		},
+		{
+			name:  "Unsupported Sort Field",
+			query: "?sort=secret",
+			setupMock: func() {
+				mockService.On("List", mock.Anything, mock.Anything).
+					Return(nil, fmt.Errorf(
+						"%w: unsupported sort field",
+						customErrors.ErrInvalidListQuery,
+					)).Once()
+			},
+			wantStatus: http.StatusBadRequest,
+			wantError:  "unsupported sort field",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService.ExpectedCalls = nil
+			tt.setupMock()
+
+			req := httptest.NewRequest(http.MethodGet, "/products"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			handler.List(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+
+			var response api.Response
+			err := json.NewDecoder(w.Body).Decode(&response)
+			assert.NoError(t, err)
+
+			if tt.wantError != "" {
+				assert.False(t, response.Success)
+				assert.Contains(t, response.Error, tt.wantError)
+				return
+			}
+
+			assert.True(t, response.Success)
+			assert.Equal(t, tt.wantMeta, response.Meta)
+			link := w.Header().Get("Link")
+			for _, want := range tt.wantLinks {
+				assert.Contains(t, link, want)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProductHandler_Search(t *testing.T) {
+	mockService, handler := setupProductTest()
+	categoryID := uuid.New()
+
+	tests := []struct {
+		name       string
+		query      string
+		setupMock  func()
+		wantStatus int
+		wantCount  int
+		wantError  string
+	}{
+		{
+			name:  "Success",
+			query: "?q=appl&category_id=" + categoryID.String(),
+			setupMock: func() {
+				mockService.On("Search", mock.Anything, "appl", mock.MatchedBy(func(q domain.ListQuery) bool {
+					return q.Filter.CategoryID != nil && *q.Filter.CategoryID == categoryID
+				})).Return(&domain.Page[domain.ProductSearchResult]{
+					Items: []domain.ProductSearchResult{{
+						Product:       domain.Product{ID: uuid.New(), Name: "Apple Juice"},
+						NameHighlight: "<mark>Apple</mark> Juice",
+						Snippet:       "Fresh <mark>apple</mark> juice",
+					}},
+					Total: 1,
+					Limit: domain.DefaultListLimit,
+					Page:  1,
+				}, nil).Once()
+			},
+			wantStatus: http.StatusOK,
+			wantCount:  1,
+		},
+		{
+			name:  "Missing Query",
+			query: "",
+			setupMock: func() {
+				mockService.On("Search", mock.Anything, "", mock.Anything).
+					Return(nil, fmt.Errorf(
+						"%w: search query is required",
+						customErrors.ErrInvalidListQuery,
+					)).Once()
+			},
+			wantStatus: http.StatusBadRequest,
+			wantError:  "search query is required",
+		},
+		{
+			name:  "Category Not Found",
+			query: "?q=apple&category_id=" + categoryID.String(),
+			setupMock: func() {
+				mockService.On("Search", mock.Anything, "apple", mock.Anything).
+					Return(nil, customErrors.ErrCategoryNotFound).Once()
+			},
+			wantStatus: http.StatusNotFound,
+			wantError:  "Category not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService.ExpectedCalls = nil
+			tt.setupMock()
+
+			req := httptest.NewRequest(
+				http.MethodGet,
+				"/products/search"+tt.query,
+				nil,
+			)
+			w := httptest.NewRecorder()
+
+			handler.Search(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+
+			var response api.Response
+			err := json.NewDecoder(w.Body).Decode(&response)
+			assert.NoError(t, err)
+
+			if tt.wantError != "" {
+				assert.False(t, response.Success)
+				assert.Contains(t, response.Error, tt.wantError)
+				return
+			}
+
+			assert.True(t, response.Success)
+			var results []domain.ProductSearchResult
+			data, err := json.Marshal(response.Data)
+			assert.NoError(t, err)
+			err = json.Unmarshal(data, &results)
+			assert.NoError(t, err)
+			assert.Len(t, results, tt.wantCount)
+			assert.Contains(t, results[0].NameHighlight, "<mark>")
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
 func TestProductHandler_Update(t *testing.T) {
 	mockService, handler := setupProductTest()
 
@@ -296,7 +521,7 @@ func TestProductHandler_Update(t *testing.T) {
 			product: &domain.Product{
 				ID:    testID,
 				Name:  "Updated Apple",
-				Price: 2.49,
+				Price: domain.NewMoney(249, domain.DefaultCurrency),
 				Stock: 75,
 			},
 			setupMock: func() {
@@ -311,7 +536,7 @@ func TestProductHandler_Update(t *testing.T) {
 			product: &domain.Product{
 				ID:    testID,
 				Name:  "Updated Apple",
-				Price: 2.49,
+				Price: domain.NewMoney(249, domain.DefaultCurrency),
 			},
 			setupMock: func() {
 				mockService.On("Update", mock.Anything, mock.AnythingOfType("*domain.Product")).
@@ -326,7 +551,7 @@ func TestProductHandler_Update(t *testing.T) {
 			product: &domain.Product{
 				ID:    testID,
 				Name:  "",
-				Price: -1,
+				Price: domain.NewMoney(-100, domain.DefaultCurrency),
 			},
 			setupMock: func() {
 				mockService.On("Update", mock.Anything, mock.AnythingOfType("*domain.Product")).
@@ -469,8 +694,8 @@ func TestProductHandler_ListByCategoryID(t *testing.T) {
 			name:       "Success",
 			categoryID: categoryID.String(),
 			setupMock: func() {
-				mockService.On("ListByCategoryID", mock.Anything, categoryID.String()).
-					Return([]domain.Product{
+				mockService.On("ListByCategoryID", mock.Anything, categoryID.String(), mock.Anything).
+					Return(&domain.Page[domain.Product]{Items: []domain.Product{
 						{
 							ID:         uuid.New(),
 							Name:       "Red Apple",
@@ -481,7 +706,7 @@ func TestProductHandler_ListByCategoryID(t *testing.T) {
 							Name:       "Green Apple",
 							CategoryID: categoryID,
 						},
-					}, nil)
+					}}, nil)
 			},
 			wantStatus: http.StatusOK,
 			wantCount:  2,
@@ -497,7 +722,7 @@ func TestProductHandler_ListByCategoryID(t *testing.T) {
 			name:       "Category Not Found",
 			categoryID: categoryID.String(),
 			setupMock: func() {
-				mockService.On("ListByCategoryID", mock.Anything, categoryID.String()).
+				mockService.On("ListByCategoryID", mock.Anything, categoryID.String(), mock.Anything).
 					Return(nil, customErrors.ErrCategoryNotFound)
 			},
 			wantStatus: http.StatusNotFound,
@@ -571,8 +796,15 @@ func TestProductHandler_UpdateStock(t *testing.T) {
 			id:       testID.String(),
 			quantity: 50,
 			setupMock: func() {
-				mockService.On("UpdateStock", mock.Anything, testID.String(), 50).
-					Return(nil)
+				mockService.On(
+					"UpdateStock",
+					mock.Anything,
+					mock.MatchedBy(func(m *domain.StockMovement) bool {
+						return m.ProductID == testID &&
+							m.Type == domain.StockMovementRestock &&
+							m.Quantity == 50
+					}),
+				).Return(nil)
 			},
 			wantStatus: http.StatusOK,
 		},
@@ -589,7 +821,7 @@ func TestProductHandler_UpdateStock(t *testing.T) {
 			id:       testID.String(),
 			quantity: -1,
 			setupMock: func() {
-				mockService.On("UpdateStock", mock.Anything, testID.String(), -1).
+				mockService.On("UpdateStock", mock.Anything, mock.Anything).
 					Return(customErrors.ErrInvalidProductData)
 			},
 			wantStatus: http.StatusBadRequest,
@@ -600,15 +832,23 @@ func TestProductHandler_UpdateStock(t *testing.T) {
 			id:       testID.String(),
 			quantity: 50,
 			setupMock: func() {
-				mockService.On("UpdateStock",
-					mock.Anything,
-					testID.String(),
-					50,
-				).Return(customErrors.ErrProductNotFound)
+				mockService.On("UpdateStock", mock.Anything, mock.Anything).
+					Return(customErrors.ErrProductNotFound)
 			},
 			wantStatus: http.StatusNotFound,
 			wantError:  "Product not found",
 		},
+		{
+			name:     "Insufficient Stock",
+			id:       testID.String(),
+			quantity: -500,
+			setupMock: func() {
+				mockService.On("UpdateStock", mock.Anything, mock.Anything).
+					Return(customErrors.ErrInsufficientStock)
+			},
+			wantStatus: http.StatusConflict,
+			wantError:  customErrors.ErrInsufficientStock.Error(),
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,116 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/grocery-service/internal/api/middleware"
+	"github.com/grocery-service/utils/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingStore is a rate limit store that is down.
+type failingStore struct{}
+
+func (failingStore) Take(
+	context.Context,
+	string,
+	ratelimit.Policy,
+	time.Time,
+) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("connection refused")
+}
+
+func (failingStore) Prune(context.Context, time.Time) (int64, error) {
+	return 0, errors.New("connection refused")
+}
+
+func TestRateLimit(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	policy := ratelimit.Policy{Name: "default", Limit: 1, Window: time.Second}
+
+	send := func(
+		handler http.Handler,
+		remoteAddr string,
+		key interface{},
+		value string,
+	) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/products", nil)
+		req.RemoteAddr = remoteAddr
+		if key != nil {
+			req = req.WithContext(
+				context.WithValue(req.Context(), key, value),
+			)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Limits Each Client", func(t *testing.T) {
+		limited := middleware.RateLimit(ratelimit.NewMemoryStore(), policy)(next)
+
+		w := send(limited, "192.0.2.1:1000", nil, "")
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, "1", w.Header().Get("RateLimit-Reset"))
+
+		// Another port is the same client
+		w = send(limited, "192.0.2.1:2000", nil, "")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "1", w.Header().Get("Retry-After"))
+
+		// Users and API keys behind the same address are not
+		w = send(limited, "192.0.2.1:3000", middleware.UserIDKey, "user-1")
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		w = send(limited, "192.0.2.1:4000", middleware.APIKeyIDKey, "key-1")
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		w = send(limited, "192.0.2.1:5000", middleware.APIKeyIDKey, "key-1")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	})
+
+	t.Run("Store Failure Lets Requests Through", func(t *testing.T) {
+		limited := middleware.RateLimit(failingStore{}, policy)(next)
+
+		w := send(limited, "192.0.2.2:1000", nil, "")
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+	})
+
+	t.Run("No Store Turns Limiting Off", func(t *testing.T) {
+		limited := middleware.RateLimit(nil, policy)(next)
+
+		for i := 0; i < 3; i++ {
+			assert.Equal(
+				t,
+				http.StatusNoContent,
+				send(limited, "192.0.2.3:1000", nil, "").Code,
+			)
+		}
+	})
+}
+
+func TestMemoryStore_Prune(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	policy := ratelimit.Policy{Name: "auth", Limit: 2, Window: time.Minute}
+	now := time.Now()
+
+	_, err := store.Take(context.Background(), "auth:a", policy, now)
+	require.NoError(t, err)
+
+	pruned, err := store.Prune(context.Background(), now)
+	require.NoError(t, err)
+	assert.Zero(t, pruned)
+
+	// One token refills in half the window
+	pruned, err = store.Prune(context.Background(), now.Add(30*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), pruned)
+}
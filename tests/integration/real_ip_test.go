@@ -0,0 +1,94 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grocery-service/internal/api/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRealIP(t *testing.T) {
+	proxies, err := middleware.ParseTrustedProxies(
+		[]string{"10.0.0.0/8", " 192.0.2.1", "2001:db8::1"},
+	)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor []string
+		wantRemoteIP string
+	}{
+		{
+			name:         "Direct Client",
+			remoteAddr:   "198.51.100.7:5000",
+			wantRemoteIP: "198.51.100.7:5000",
+		},
+		{
+			name:         "Header From Untrusted Client Ignored",
+			remoteAddr:   "198.51.100.7:5000",
+			forwardedFor: []string{"203.0.113.9"},
+			wantRemoteIP: "198.51.100.7:5000",
+		},
+		{
+			name:         "Client Behind Trusted Proxy",
+			remoteAddr:   "10.1.2.3:5000",
+			forwardedFor: []string{"203.0.113.9"},
+			wantRemoteIP: "203.0.113.9",
+		},
+		{
+			name:         "Spoofed Hops Left Of The Client Ignored",
+			remoteAddr:   "10.1.2.3:5000",
+			forwardedFor: []string{"1.1.1.1, 203.0.113.9", "192.0.2.1"},
+			wantRemoteIP: "203.0.113.9",
+		},
+		{
+			name:         "Only Trusted Hops",
+			remoteAddr:   "[2001:db8::1]:5000",
+			forwardedFor: []string{"10.0.0.2"},
+			wantRemoteIP: "10.0.0.2",
+		},
+		{
+			name:         "Malformed Hop Stops The Walk",
+			remoteAddr:   "10.1.2.3:5000",
+			forwardedFor: []string{"203.0.113.9, unknown"},
+			wantRemoteIP: "10.1.2.3",
+		},
+		{
+			name:         "Trusted Proxy Without Header",
+			remoteAddr:   "192.0.2.1:5000",
+			wantRemoteIP: "192.0.2.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := middleware.RealIP(proxies)(http.HandlerFunc(
+				func(_ http.ResponseWriter, r *http.Request) {
+					got = r.RemoteAddr
+				},
+			))
+
+			req := httptest.NewRequest(http.MethodGet, "/products", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.forwardedFor {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tt.wantRemoteIP, got)
+		})
+	}
+
+	t.Run("Invalid Trusted Proxy", func(t *testing.T) {
+		_, err := middleware.ParseTrustedProxies([]string{"10.0.0.0/33"})
+		assert.Error(t, err)
+
+		_, err = middleware.ParseTrustedProxies([]string{"proxy.local"})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,323 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	handler "github.com/grocery-service/internal/api/handlers"
+	"github.com/grocery-service/internal/api/middleware"
+	"github.com/grocery-service/internal/domain"
+	serviceMock "github.com/grocery-service/tests/mocks/service"
+	"github.com/grocery-service/utils/api"
+	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupRoleTest() (
+	*serviceMock.RoleService,
+	*handler.RoleHandler,
+) {
+	mockService := new(serviceMock.RoleService)
+	handler := handler.NewRoleHandler(mockService)
+	return mockService, handler
+}
+
+func TestRoleHandler_Create(t *testing.T) {
+	mockService, handler := setupRoleTest()
+
+	tests := []struct {
+		name       string
+		body       string
+		setupMock  func()
+		wantStatus int
+	}{
+		{
+			name: "Success",
+			body: `{"name": "stock-clerk", "permissions": ["inventory:write"]}`,
+			setupMock: func() {
+				mockService.On("Create", mock.Anything, domain.RoleRequest{
+					Name:        "stock-clerk",
+					Permissions: []domain.Permission{"inventory:write"},
+				}).Return(&domain.Role{
+					Name:        "stock-clerk",
+					Permissions: []domain.Permission{"inventory:write"},
+				}, nil).Once()
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name: "Unknown Permission",
+			body: `{"name": "stock-clerk", "permissions": ["stock:steal"]}`,
+			setupMock: func() {
+				mockService.On("Create", mock.Anything, domain.RoleRequest{
+					Name:        "stock-clerk",
+					Permissions: []domain.Permission{"stock:steal"},
+				}).Return(nil, customErrors.ErrInvalidRoleData).Once()
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Role Exists",
+			body: `{"name": "picker"}`,
+			setupMock: func() {
+				mockService.On("Create", mock.Anything, domain.RoleRequest{
+					Name: "picker",
+				}).Return(nil, customErrors.ErrRoleExists).Once()
+			},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "Invalid Body",
+			body:       `{"name":`,
+			setupMock:  func() {},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			req := httptest.NewRequest(
+				http.MethodPost,
+				"/admin/roles",
+				strings.NewReader(tt.body),
+			)
+			w := httptest.NewRecorder()
+
+			handler.Create(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestRoleHandler_Delete(t *testing.T) {
+	mockService, handler := setupRoleTest()
+
+	tests := []struct {
+		name       string
+		role       string
+		err        error
+		wantStatus int
+	}{
+		{name: "Success", role: "stock-clerk", wantStatus: http.StatusOK},
+		{
+			name:       "Built-in Role",
+			role:       "customer",
+			err:        customErrors.ErrBuiltInRole,
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "Role In Use",
+			role:       "night-shift",
+			err:        customErrors.ErrRoleInUse,
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "Not Found",
+			role:       "ghost",
+			err:        customErrors.ErrRoleNotFound,
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService.On("Delete", mock.Anything, tt.role).
+				Return(tt.err).Once()
+
+			req := httptest.NewRequest(
+				http.MethodDelete,
+				"/admin/roles/"+tt.role,
+				nil,
+			)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("name", tt.role)
+			req = req.WithContext(
+				context.WithValue(req.Context(), chi.RouteCtxKey, rctx),
+			)
+			w := httptest.NewRecorder()
+
+			handler.Delete(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestRoleHandler_AssignRole(t *testing.T) {
+	mockService, handler := setupRoleTest()
+	adminID := uuid.NewString()
+	userID := uuid.NewString()
+
+	tests := []struct {
+		name       string
+		userID     string
+		body       string
+		setupMock  func()
+		wantStatus int
+		wantError  string
+	}{
+		{
+			name:   "Success",
+			userID: userID,
+			body:   `{"role": "picker"}`,
+			setupMock: func() {
+				mockService.On(
+					"AssignRole",
+					mock.Anything,
+					adminID,
+					userID,
+					domain.PickerRole,
+				).Return(nil).Once()
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "Unknown Role",
+			userID: userID,
+			body:   `{"role": "ghost"}`,
+			setupMock: func() {
+				mockService.On(
+					"AssignRole",
+					mock.Anything,
+					adminID,
+					userID,
+					domain.UserRole("ghost"),
+				).Return(customErrors.ErrRoleNotFound).Once()
+			},
+			wantStatus: http.StatusNotFound,
+			wantError:  "Role not found",
+		},
+		{
+			name:   "Own Role",
+			userID: adminID,
+			body:   `{"role": "customer"}`,
+			setupMock: func() {
+				mockService.On(
+					"AssignRole",
+					mock.Anything,
+					adminID,
+					adminID,
+					domain.CustomerRole,
+				).Return(customErrors.ErrForbidden).Once()
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "Invalid User ID",
+			userID:     "invalid-uuid",
+			body:       `{"role": "picker"}`,
+			setupMock:  func() {},
+			wantStatus: http.StatusBadRequest,
+			wantError:  "Invalid user ID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			req := httptest.NewRequest(
+				http.MethodPut,
+				"/admin/users/"+tt.userID+"/role",
+				strings.NewReader(tt.body),
+			)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.userID)
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, middleware.UserIDKey, adminID)
+			req = req.WithContext(ctx)
+			w := httptest.NewRecorder()
+
+			handler.AssignRole(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+
+			if tt.wantError != "" {
+				var response api.Response
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+				assert.False(t, response.Success)
+				assert.Contains(t, response.Error, tt.wantError)
+			}
+		})
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestRequirePermission(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	guarded := middleware.RequirePermission(
+		domain.PermissionOrdersUpdateStatus,
+	)(next)
+
+	tests := []struct {
+		name        string
+		role        string
+		permissions map[domain.Permission]bool
+		wantStatus  int
+	}{
+		{
+			name: "Granted",
+			role: string(domain.DriverRole),
+			permissions: map[domain.Permission]bool{
+				domain.PermissionOrdersRead:         true,
+				domain.PermissionOrdersUpdateStatus: true,
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name: "Not Granted",
+			role: string(domain.StoreManagerRole),
+			permissions: map[domain.Permission]bool{
+				domain.PermissionOrdersRead: true,
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "Admin",
+			role:       string(domain.AdminRole),
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "Unauthenticated",
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/orders/1/status", nil)
+			ctx := req.Context()
+			if tt.role != "" {
+				ctx = context.WithValue(ctx, middleware.UserRoleKey, tt.role)
+				ctx = context.WithValue(
+					ctx,
+					middleware.PermissionsKey,
+					tt.permissions,
+				)
+			}
+			w := httptest.NewRecorder()
+
+			guarded.ServeHTTP(w, req.WithContext(ctx))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
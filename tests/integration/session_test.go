@@ -0,0 +1,201 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	handler "github.com/grocery-service/internal/api/handlers"
+	"github.com/grocery-service/internal/api/middleware"
+	"github.com/grocery-service/internal/domain"
+	serviceMock "github.com/grocery-service/tests/mocks/service"
+	customErrors "github.com/grocery-service/utils/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupSessionTest() (
+	*serviceMock.AuthService,
+	*handler.AuthHandler,
+) {
+	mockService := new(serviceMock.AuthService)
+	handler := handler.NewAuthHandler(mockService)
+	return mockService, handler
+}
+
+func TestAuthHandler_ListSessions(t *testing.T) {
+	mockService, handler := setupSessionTest()
+	userID := uuid.New().String()
+	current := uuid.New()
+	other := uuid.New()
+
+	mockService.On("ListSessions", mock.Anything, userID).
+		Return([]domain.Session{
+			{ID: other, Device: "Chrome on Windows", LastUsedAt: time.Now()},
+			{ID: current, Device: "Safari on iPhone", LastUsedAt: time.Now()},
+		}, nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/auth/sessions", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, userID)
+	ctx = context.WithValue(ctx, middleware.SessionIDKey, current.String())
+	w := httptest.NewRecorder()
+
+	handler.ListSessions(w, req.WithContext(ctx))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Data []domain.Session `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	require.Len(t, response.Data, 2)
+	assert.False(t, response.Data[0].Current)
+	assert.True(t, response.Data[1].Current)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAuthHandler_RevokeSession(t *testing.T) {
+	mockService, handler := setupSessionTest()
+	userID := uuid.New().String()
+	sessionID := uuid.New().String()
+
+	tests := []struct {
+		name       string
+		sessionID  string
+		setupMock  func()
+		wantStatus int
+	}{
+		{
+			name:      "Success",
+			sessionID: sessionID,
+			setupMock: func() {
+				mockService.On("RevokeSession", mock.Anything, userID, sessionID).
+					Return(nil).Once()
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:      "Not Found",
+			sessionID: sessionID,
+			setupMock: func() {
+				mockService.On("RevokeSession", mock.Anything, userID, sessionID).
+					Return(customErrors.ErrSessionNotFound).Once()
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "Invalid Session ID",
+			sessionID:  "invalid-uuid",
+			setupMock:  func() {},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			req := httptest.NewRequest(
+				http.MethodDelete,
+				"/auth/sessions/"+tt.sessionID,
+				nil,
+			)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.sessionID)
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, middleware.UserIDKey, userID)
+			w := httptest.NewRecorder()
+
+			handler.RevokeSession(w, req.WithContext(ctx))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAuthHandler_LogoutAll(t *testing.T) {
+	mockService, handler := setupSessionTest()
+	userID := uuid.New().String()
+
+	mockService.On("RevokeAllSessions", mock.Anything, userID).
+		Return(int64(4), nil).Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/logout-all", nil)
+	req = req.WithContext(
+		context.WithValue(req.Context(), middleware.UserIDKey, userID),
+	)
+	w := httptest.NewRecorder()
+
+	handler.LogoutAll(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Data domain.LogoutResult `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, int64(4), response.Data.RevokedTokens)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAuthHandler_ForceLogout(t *testing.T) {
+	mockService, handler := setupSessionTest()
+	adminID := uuid.New().String()
+	userID := uuid.New().String()
+
+	mockService.On("RevokeAllSessions", mock.Anything, userID).
+		Return(int64(2), nil).Once()
+
+	req := httptest.NewRequest(
+		http.MethodPost,
+		"/admin/users/"+userID+"/logout",
+		nil,
+	)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", userID)
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	ctx = context.WithValue(ctx, middleware.UserIDKey, adminID)
+	w := httptest.NewRecorder()
+
+	handler.ForceLogout(w, req.WithContext(ctx))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestAuthHandler_RefreshTokenRecordsClient(t *testing.T) {
+	mockService, handler := setupSessionTest()
+
+	mockService.On(
+		"RefreshToken",
+		mock.Anything,
+		"refresh-token",
+		domain.ClientInfo{
+			IPAddress: "203.0.113.7",
+			UserAgent: "test-agent/1.0",
+		},
+	).Return(&domain.AuthResponse{AccessToken: "access-token"}, nil).Once()
+
+	req := httptest.NewRequest(
+		http.MethodPost,
+		"/auth/refresh",
+		strings.NewReader(`{"refresh_token": "refresh-token"}`),
+	)
+	req.RemoteAddr = "203.0.113.7:52100"
+	req.Header.Set("User-Agent", "test-agent/1.0")
+	w := httptest.NewRecorder()
+
+	handler.RefreshToken(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
@@ -14,17 +14,17 @@ type OrderRepository struct {
 	mock.Mock
 }
 
-// AddOrderItem provides a mock function with given fields: ctx, orderID, item, updateStockFunc, updateOrderTotalFunc
-func (_m *OrderRepository) AddOrderItem(ctx context.Context, orderID string, item *domain.OrderItem, updateStockFunc func(context.Context, string, int) error, updateOrderTotalFunc func(context.Context, *domain.Order, float64) error) error {
-	ret := _m.Called(ctx, orderID, item, updateStockFunc, updateOrderTotalFunc)
+// AddOrderItem provides a mock function with given fields: ctx, orderID, item
+func (_m *OrderRepository) AddOrderItem(ctx context.Context, orderID string, item *domain.OrderItem) error {
+	ret := _m.Called(ctx, orderID, item)
 
 	if len(ret) == 0 {
 		panic("no return value specified for AddOrderItem")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.OrderItem, func(context.Context, string, int) error, func(context.Context, *domain.Order, float64) error) error); ok {
-		r0 = rf(ctx, orderID, item, updateStockFunc, updateOrderTotalFunc)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.OrderItem) error); ok {
+		r0 = rf(ctx, orderID, item)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -32,17 +32,49 @@ func (_m *OrderRepository) AddOrderItem(ctx context.Context, orderID string, ite
 	return r0
 }
 
-// Create provides a mock function with given fields: ctx, order, updateStockFunc
-func (_m *OrderRepository) Create(ctx context.Context, order *domain.Order, updateStockFunc func(context.Context, string, int) error) error {
-	ret := _m.Called(ctx, order, updateStockFunc)
+// Checkout provides a mock function with given fields: ctx, order, cartID, events
+func (_m *OrderRepository) Checkout(ctx context.Context, order *domain.Order, cartID string, events ...*domain.OutboxEvent) error {
+	_va := make([]interface{}, len(events))
+	for _i := range events {
+		_va[_i] = events[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, order, cartID)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Checkout")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Order, string, ...*domain.OutboxEvent) error); ok {
+		r0 = rf(ctx, order, cartID, events...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Create provides a mock function with given fields: ctx, order, events
+func (_m *OrderRepository) Create(ctx context.Context, order *domain.Order, events ...*domain.OutboxEvent) error {
+	_va := make([]interface{}, len(events))
+	for _i := range events {
+		_va[_i] = events[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, order)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Create")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *domain.Order, func(context.Context, string, int) error) error); ok {
-		r0 = rf(ctx, order, updateStockFunc)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Order, ...*domain.OutboxEvent) error); ok {
+		r0 = rf(ctx, order, events...)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -80,29 +112,29 @@ func (_m *OrderRepository) GetByID(ctx context.Context, id string) (*domain.Orde
 	return r0, r1
 }
 
-// List provides a mock function with given fields: ctx
-func (_m *OrderRepository) List(ctx context.Context) ([]domain.Order, error) {
-	ret := _m.Called(ctx)
+// List provides a mock function with given fields: ctx, query
+func (_m *OrderRepository) List(ctx context.Context, query domain.ListQuery) (*domain.Page[domain.Order], error) {
+	ret := _m.Called(ctx, query)
 
 	if len(ret) == 0 {
 		panic("no return value specified for List")
 	}
 
-	var r0 []domain.Order
+	var r0 *domain.Page[domain.Order]
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.Order, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ListQuery) (*domain.Page[domain.Order], error)); ok {
+		return rf(ctx, query)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []domain.Order); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ListQuery) *domain.Page[domain.Order]); ok {
+		r0 = rf(ctx, query)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]domain.Order)
+			r0 = ret.Get(0).(*domain.Page[domain.Order])
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, domain.ListQuery) error); ok {
+		r1 = rf(ctx, query)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -110,29 +142,59 @@ func (_m *OrderRepository) List(ctx context.Context) ([]domain.Order, error) {
 	return r0, r1
 }
 
-// ListByCustomerID provides a mock function with given fields: ctx, customerID
-func (_m *OrderRepository) ListByCustomerID(ctx context.Context, customerID string) ([]domain.Order, error) {
-	ret := _m.Called(ctx, customerID)
+// ListByCustomerID provides a mock function with given fields: ctx, customerID, query
+func (_m *OrderRepository) ListByCustomerID(ctx context.Context, customerID string, query domain.ListQuery) (*domain.Page[domain.Order], error) {
+	ret := _m.Called(ctx, customerID, query)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ListByCustomerID")
 	}
 
-	var r0 []domain.Order
+	var r0 *domain.Page[domain.Order]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.ListQuery) (*domain.Page[domain.Order], error)); ok {
+		return rf(ctx, customerID, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.ListQuery) *domain.Page[domain.Order]); ok {
+		r0 = rf(ctx, customerID, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Page[domain.Order])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.ListQuery) error); ok {
+		r1 = rf(ctx, customerID, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListStatusHistory provides a mock function with given fields: ctx, orderID
+func (_m *OrderRepository) ListStatusHistory(ctx context.Context, orderID string) ([]domain.OrderStatusChange, error) {
+	ret := _m.Called(ctx, orderID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListStatusHistory")
+	}
+
+	var r0 []domain.OrderStatusChange
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.Order, error)); ok {
-		return rf(ctx, customerID)
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.OrderStatusChange, error)); ok {
+		return rf(ctx, orderID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.Order); ok {
-		r0 = rf(ctx, customerID)
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.OrderStatusChange); ok {
+		r0 = rf(ctx, orderID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]domain.Order)
+			r0 = ret.Get(0).([]domain.OrderStatusChange)
 		}
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, customerID)
+		r1 = rf(ctx, orderID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -140,17 +202,17 @@ func (_m *OrderRepository) ListByCustomerID(ctx context.Context, customerID stri
 	return r0, r1
 }
 
-// RemoveOrderItem provides a mock function with given fields: ctx, orderID, itemID, restoreStockFunc, updateOrderTotalFunc
-func (_m *OrderRepository) RemoveOrderItem(ctx context.Context, orderID string, itemID string, restoreStockFunc func(context.Context, string, int) error, updateOrderTotalFunc func(context.Context, *domain.Order, float64) error) error {
-	ret := _m.Called(ctx, orderID, itemID, restoreStockFunc, updateOrderTotalFunc)
+// RemoveOrderItem provides a mock function with given fields: ctx, orderID, itemID
+func (_m *OrderRepository) RemoveOrderItem(ctx context.Context, orderID string, itemID string) error {
+	ret := _m.Called(ctx, orderID, itemID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for RemoveOrderItem")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, func(context.Context, string, int) error, func(context.Context, *domain.Order, float64) error) error); ok {
-		r0 = rf(ctx, orderID, itemID, restoreStockFunc, updateOrderTotalFunc)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, orderID, itemID)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -176,17 +238,17 @@ func (_m *OrderRepository) Update(ctx context.Context, order *domain.Order) erro
 	return r0
 }
 
-// UpdateStatus provides a mock function with given fields: ctx, id, status
-func (_m *OrderRepository) UpdateStatus(ctx context.Context, id string, status domain.OrderStatus) error {
-	ret := _m.Called(ctx, id, status)
+// UpdateStatus provides a mock function with given fields: ctx, transition
+func (_m *OrderRepository) UpdateStatus(ctx context.Context, transition *domain.OrderTransition) error {
+	ret := _m.Called(ctx, transition)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateStatus")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, domain.OrderStatus) error); ok {
-		r0 = rf(ctx, id, status)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.OrderTransition) error); ok {
+		r0 = rf(ctx, transition)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -4,6 +4,7 @@ package mocks
 
 import (
 	context "context"
+	time "time"
 
 	domain "github.com/grocery-service/internal/domain"
 	mock "github.com/stretchr/testify/mock"
@@ -14,6 +15,36 @@ type TokenRepository struct {
 	mock.Mock
 }
 
+// ConsumeToken provides a mock function with given fields: ctx, token, tokenType
+func (_m *TokenRepository) ConsumeToken(ctx context.Context, token string, tokenType domain.TokenType) (*domain.Token, error) {
+	ret := _m.Called(ctx, token, tokenType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConsumeToken")
+	}
+
+	var r0 *domain.Token
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.TokenType) (*domain.Token, error)); ok {
+		return rf(ctx, token, tokenType)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.TokenType) *domain.Token); ok {
+		r0 = rf(ctx, token, tokenType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Token)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.TokenType) error); ok {
+		r1 = rf(ctx, token, tokenType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: ctx, token
 func (_m *TokenRepository) Create(ctx context.Context, token *domain.Token) error {
 	ret := _m.Called(ctx, token)
@@ -32,22 +63,32 @@ func (_m *TokenRepository) Create(ctx context.Context, token *domain.Token) erro
 	return r0
 }
 
-// DeleteExpiredTokens provides a mock function with given fields: ctx
-func (_m *TokenRepository) DeleteExpiredTokens(ctx context.Context) error {
-	ret := _m.Called(ctx)
+// DeleteExpiredTokens provides a mock function with given fields: ctx, revokedBefore, limit
+func (_m *TokenRepository) DeleteExpiredTokens(ctx context.Context, revokedBefore time.Time, limit int) (int64, error) {
+	ret := _m.Called(ctx, revokedBefore, limit)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteExpiredTokens")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
-		r0 = rf(ctx)
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) (int64, error)); ok {
+		return rf(ctx, revokedBefore, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) int64); ok {
+		r0 = rf(ctx, revokedBefore, limit)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(int64)
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, revokedBefore, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // GetByProviderID provides a mock function with given fields: ctx, providerID
@@ -110,24 +151,54 @@ func (_m *TokenRepository) GetByToken(ctx context.Context, token string) (*domai
 	return r0, r1
 }
 
+// GetByTokenIncludingRevoked provides a mock function with given fields: ctx, token
+func (_m *TokenRepository) GetByTokenIncludingRevoked(ctx context.Context, token string) (*domain.Token, error) {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByTokenIncludingRevoked")
+	}
+
+	var r0 *domain.Token
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.Token, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.Token); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Token)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetByUserAndType provides a mock function with given fields: ctx, userID, tokenType
-func (_m *TokenRepository) GetByUserAndType(ctx context.Context, userID string, tokenType domain.TokenType) (*domain.Token, error) {
+func (_m *TokenRepository) GetByUserAndType(ctx context.Context, userID string, tokenType domain.TokenType) ([]domain.Token, error) {
 	ret := _m.Called(ctx, userID, tokenType)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetByUserAndType")
 	}
 
-	var r0 *domain.Token
+	var r0 []domain.Token
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, domain.TokenType) (*domain.Token, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.TokenType) ([]domain.Token, error)); ok {
 		return rf(ctx, userID, tokenType)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, domain.TokenType) *domain.Token); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.TokenType) []domain.Token); ok {
 		r0 = rf(ctx, userID, tokenType)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*domain.Token)
+			r0 = ret.Get(0).([]domain.Token)
 		}
 	}
 
@@ -158,6 +229,34 @@ func (_m *TokenRepository) IsValid(ctx context.Context, token string) bool {
 	return r0
 }
 
+// RevokeFamily provides a mock function with given fields: ctx, familyID
+func (_m *TokenRepository) RevokeFamily(ctx context.Context, familyID string) (int64, error) {
+	ret := _m.Called(ctx, familyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeFamily")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int64, error)); ok {
+		return rf(ctx, familyID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(ctx, familyID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, familyID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RevokeToken provides a mock function with given fields: ctx, token
 func (_m *TokenRepository) RevokeToken(ctx context.Context, token string) error {
 	ret := _m.Called(ctx, token)
@@ -176,6 +275,52 @@ func (_m *TokenRepository) RevokeToken(ctx context.Context, token string) error
 	return r0
 }
 
+// RevokeUserTokens provides a mock function with given fields: ctx, userID
+func (_m *TokenRepository) RevokeUserTokens(ctx context.Context, userID string) (int64, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeUserTokens")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int64, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RotateToken provides a mock function with given fields: ctx, current, next
+func (_m *TokenRepository) RotateToken(ctx context.Context, current *domain.Token, next *domain.Token) error {
+	ret := _m.Called(ctx, current, next)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RotateToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Token, *domain.Token) error); ok {
+		r0 = rf(ctx, current, next)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewTokenRepository creates a new instance of TokenRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewTokenRepository(t interface {
@@ -4,6 +4,7 @@ package mocks
 
 import (
 	context "context"
+	time "time"
 
 	domain "github.com/grocery-service/internal/domain"
 	mock "github.com/stretchr/testify/mock"
@@ -14,17 +15,60 @@ type UserRepository struct {
 	mock.Mock
 }
 
-// Create provides a mock function with given fields: ctx, user
-func (_m *UserRepository) Create(ctx context.Context, user *domain.User) error {
-	ret := _m.Called(ctx, user)
+// AddIdentity provides a mock function with given fields: ctx, identity
+func (_m *UserRepository) AddIdentity(ctx context.Context, identity *domain.UserIdentity) error {
+	ret := _m.Called(ctx, identity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddIdentity")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.UserIdentity) error); ok {
+		r0 = rf(ctx, identity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ClaimAccount provides a mock function with given fields: ctx, identity
+func (_m *UserRepository) ClaimAccount(ctx context.Context, identity *domain.UserIdentity) error {
+	ret := _m.Called(ctx, identity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimAccount")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.UserIdentity) error); ok {
+		r0 = rf(ctx, identity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Create provides a mock function with given fields: ctx, user, identities
+func (_m *UserRepository) Create(ctx context.Context, user *domain.User, identities ...*domain.UserIdentity) error {
+	_va := make([]interface{}, len(identities))
+	for _i := range identities {
+		_va[_i] = identities[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, user)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Create")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *domain.User) error); ok {
-		r0 = rf(ctx, user)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.User, ...*domain.UserIdentity) error); ok {
+		r0 = rf(ctx, user, identities...)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -110,6 +154,36 @@ func (_m *UserRepository) GetByID(ctx context.Context, id string) (*domain.User,
 	return r0, r1
 }
 
+// GetByIdentity provides a mock function with given fields: ctx, provider, subject
+func (_m *UserRepository) GetByIdentity(ctx context.Context, provider string, subject string) (*domain.User, error) {
+	ret := _m.Called(ctx, provider, subject)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByIdentity")
+	}
+
+	var r0 *domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*domain.User, error)); ok {
+		return rf(ctx, provider, subject)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domain.User); ok {
+		r0 = rf(ctx, provider, subject)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, provider, subject)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetByProviderID provides a mock function with given fields: ctx, providerID
 func (_m *UserRepository) GetByProviderID(ctx context.Context, providerID string) (*domain.User, error) {
 	ret := _m.Called(ctx, providerID)
@@ -140,6 +214,90 @@ func (_m *UserRepository) GetByProviderID(ctx context.Context, providerID string
 	return r0, r1
 }
 
+// MarkEmailVerified provides a mock function with given fields: ctx, id
+func (_m *UserRepository) MarkEmailVerified(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkEmailVerified")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RecordFailedLogin provides a mock function with given fields: ctx, id, maxAttempts, lockUntil
+func (_m *UserRepository) RecordFailedLogin(ctx context.Context, id string, maxAttempts int, lockUntil time.Time) (*domain.User, error) {
+	ret := _m.Called(ctx, id, maxAttempts, lockUntil)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordFailedLogin")
+	}
+
+	var r0 *domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, time.Time) (*domain.User, error)); ok {
+		return rf(ctx, id, maxAttempts, lockUntil)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, time.Time) *domain.User); ok {
+		r0 = rf(ctx, id, maxAttempts, lockUntil)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, time.Time) error); ok {
+		r1 = rf(ctx, id, maxAttempts, lockUntil)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResetFailedLogins provides a mock function with given fields: ctx, id
+func (_m *UserRepository) ResetFailedLogins(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetFailedLogins")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetPassword provides a mock function with given fields: ctx, id, passwordHash
+func (_m *UserRepository) SetPassword(ctx context.Context, id string, passwordHash string) error {
+	ret := _m.Called(ctx, id, passwordHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetPassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, id, passwordHash)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, user
 func (_m *UserRepository) Update(ctx context.Context, user *domain.User) error {
 	ret := _m.Called(ctx, user)
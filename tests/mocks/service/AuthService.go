@@ -6,6 +6,7 @@ import (
 	context "context"
 
 	domain "github.com/grocery-service/internal/domain"
+	jwt "github.com/grocery-service/utils/jwt"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -14,47 +15,155 @@ type AuthService struct {
 	mock.Mock
 }
 
-// GetAuthURL provides a mock function with no fields
-func (_m *AuthService) GetAuthURL() string {
-	ret := _m.Called()
+// ConfirmMFA provides a mock function with given fields: ctx, userID, request
+func (_m *AuthService) ConfirmMFA(ctx context.Context, userID string, request domain.ConfirmMFARequest) (*domain.MFARecoveryCodes, error) {
+	ret := _m.Called(ctx, userID, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConfirmMFA")
+	}
+
+	var r0 *domain.MFARecoveryCodes
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.ConfirmMFARequest) (*domain.MFARecoveryCodes, error)); ok {
+		return rf(ctx, userID, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.ConfirmMFARequest) *domain.MFARecoveryCodes); ok {
+		r0 = rf(ctx, userID, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.MFARecoveryCodes)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.ConfirmMFARequest) error); ok {
+		r1 = rf(ctx, userID, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DisableMFA provides a mock function with given fields: ctx, userID, code
+func (_m *AuthService) DisableMFA(ctx context.Context, userID string, code string) error {
+	ret := _m.Called(ctx, userID, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DisableMFA")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userID, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnrollMFA provides a mock function with given fields: ctx, userID
+func (_m *AuthService) EnrollMFA(ctx context.Context, userID string) (*domain.MFAEnrollment, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnrollMFA")
+	}
+
+	var r0 *domain.MFAEnrollment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.MFAEnrollment, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.MFAEnrollment); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.MFAEnrollment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAuthURL provides a mock function with given fields: ctx, login
+func (_m *AuthService) GetAuthURL(ctx context.Context, login domain.LoginRequest) (*domain.AuthRequest, error) {
+	ret := _m.Called(ctx, login)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetAuthURL")
 	}
 
-	var r0 string
-	if rf, ok := ret.Get(0).(func() string); ok {
+	var r0 *domain.AuthRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.LoginRequest) (*domain.AuthRequest, error)); ok {
+		return rf(ctx, login)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.LoginRequest) *domain.AuthRequest); ok {
+		r0 = rf(ctx, login)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AuthRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.LoginRequest) error); ok {
+		r1 = rf(ctx, login)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetJWKS provides a mock function with no fields
+func (_m *AuthService) GetJWKS() jwt.JWKSet {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetJWKS")
+	}
+
+	var r0 jwt.JWKSet
+	if rf, ok := ret.Get(0).(func() jwt.JWKSet); ok {
 		r0 = rf()
 	} else {
-		r0 = ret.Get(0).(string)
+		r0 = ret.Get(0).(jwt.JWKSet)
 	}
 
 	return r0
 }
 
-// GetUserInfo provides a mock function with given fields: ctx, token
-func (_m *AuthService) GetUserInfo(ctx context.Context, token string) (*domain.UserInfo, error) {
-	ret := _m.Called(ctx, token)
+// GetMFAStatus provides a mock function with given fields: ctx, userID
+func (_m *AuthService) GetMFAStatus(ctx context.Context, userID string) (*domain.MFAStatus, error) {
+	ret := _m.Called(ctx, userID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetUserInfo")
+		panic("no return value specified for GetMFAStatus")
 	}
 
-	var r0 *domain.UserInfo
+	var r0 *domain.MFAStatus
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.UserInfo, error)); ok {
-		return rf(ctx, token)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.MFAStatus, error)); ok {
+		return rf(ctx, userID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.UserInfo); ok {
-		r0 = rf(ctx, token)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.MFAStatus); ok {
+		r0 = rf(ctx, userID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*domain.UserInfo)
+			r0 = ret.Get(0).(*domain.MFAStatus)
 		}
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, token)
+		r1 = rf(ctx, userID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -62,9 +171,9 @@ func (_m *AuthService) GetUserInfo(ctx context.Context, token string) (*domain.U
 	return r0, r1
 }
 
-// HandleCallback provides a mock function with given fields: ctx, code
-func (_m *AuthService) HandleCallback(ctx context.Context, code string) (*domain.AuthResponse, error) {
-	ret := _m.Called(ctx, code)
+// HandleCallback provides a mock function with given fields: ctx, callback
+func (_m *AuthService) HandleCallback(ctx context.Context, callback domain.AuthCallback) (*domain.AuthResponse, error) {
+	ret := _m.Called(ctx, callback)
 
 	if len(ret) == 0 {
 		panic("no return value specified for HandleCallback")
@@ -72,19 +181,49 @@ func (_m *AuthService) HandleCallback(ctx context.Context, code string) (*domain
 
 	var r0 *domain.AuthResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.AuthResponse, error)); ok {
-		return rf(ctx, code)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.AuthCallback) (*domain.AuthResponse, error)); ok {
+		return rf(ctx, callback)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.AuthResponse); ok {
-		r0 = rf(ctx, code)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.AuthCallback) *domain.AuthResponse); ok {
+		r0 = rf(ctx, callback)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.AuthResponse)
 		}
 	}
 
+	if rf, ok := ret.Get(1).(func(context.Context, domain.AuthCallback) error); ok {
+		r1 = rf(ctx, callback)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListSessions provides a mock function with given fields: ctx, userID
+func (_m *AuthService) ListSessions(ctx context.Context, userID string) ([]domain.Session, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSessions")
+	}
+
+	var r0 []domain.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.Session, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.Session); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Session)
+		}
+	}
+
 	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, code)
+		r1 = rf(ctx, userID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -92,9 +231,39 @@ func (_m *AuthService) HandleCallback(ctx context.Context, code string) (*domain
 	return r0, r1
 }
 
-// RefreshToken provides a mock function with given fields: ctx, refreshToken
-func (_m *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*domain.AuthResponse, error) {
-	ret := _m.Called(ctx, refreshToken)
+// LoginWithPassword provides a mock function with given fields: ctx, request, client
+func (_m *AuthService) LoginWithPassword(ctx context.Context, request domain.PasswordLoginRequest, client domain.ClientInfo) (*domain.AuthResponse, error) {
+	ret := _m.Called(ctx, request, client)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LoginWithPassword")
+	}
+
+	var r0 *domain.AuthResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PasswordLoginRequest, domain.ClientInfo) (*domain.AuthResponse, error)); ok {
+		return rf(ctx, request, client)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PasswordLoginRequest, domain.ClientInfo) *domain.AuthResponse); ok {
+		r0 = rf(ctx, request, client)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AuthResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.PasswordLoginRequest, domain.ClientInfo) error); ok {
+		r1 = rf(ctx, request, client)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RefreshToken provides a mock function with given fields: ctx, refreshToken, client
+func (_m *AuthService) RefreshToken(ctx context.Context, refreshToken string, client domain.ClientInfo) (*domain.AuthResponse, error) {
+	ret := _m.Called(ctx, refreshToken, client)
 
 	if len(ret) == 0 {
 		panic("no return value specified for RefreshToken")
@@ -102,19 +271,167 @@ func (_m *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*
 
 	var r0 *domain.AuthResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.AuthResponse, error)); ok {
-		return rf(ctx, refreshToken)
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.ClientInfo) (*domain.AuthResponse, error)); ok {
+		return rf(ctx, refreshToken, client)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.AuthResponse); ok {
-		r0 = rf(ctx, refreshToken)
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.ClientInfo) *domain.AuthResponse); ok {
+		r0 = rf(ctx, refreshToken, client)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.AuthResponse)
 		}
 	}
 
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.ClientInfo) error); ok {
+		r1 = rf(ctx, refreshToken, client)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RegenerateRecoveryCodes provides a mock function with given fields: ctx, userID, code
+func (_m *AuthService) RegenerateRecoveryCodes(ctx context.Context, userID string, code string) (*domain.MFARecoveryCodes, error) {
+	ret := _m.Called(ctx, userID, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RegenerateRecoveryCodes")
+	}
+
+	var r0 *domain.MFARecoveryCodes
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*domain.MFARecoveryCodes, error)); ok {
+		return rf(ctx, userID, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domain.MFARecoveryCodes); ok {
+		r0 = rf(ctx, userID, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.MFARecoveryCodes)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Register provides a mock function with given fields: ctx, request
+func (_m *AuthService) Register(ctx context.Context, request domain.RegisterRequest) error {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Register")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.RegisterRequest) error); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RequestPasswordReset provides a mock function with given fields: ctx, email
+func (_m *AuthService) RequestPasswordReset(ctx context.Context, email string) error {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequestPasswordReset")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResendVerification provides a mock function with given fields: ctx, email
+func (_m *AuthService) ResendVerification(ctx context.Context, email string) error {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResendVerification")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResetMFA provides a mock function with given fields: ctx, userID
+func (_m *AuthService) ResetMFA(ctx context.Context, userID string) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetMFA")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResetPassword provides a mock function with given fields: ctx, request
+func (_m *AuthService) ResetPassword(ctx context.Context, request domain.ResetPasswordRequest) error {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetPassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ResetPasswordRequest) error); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeAllSessions provides a mock function with given fields: ctx, userID
+func (_m *AuthService) RevokeAllSessions(ctx context.Context, userID string) (int64, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeAllSessions")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int64, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
 	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, refreshToken)
+		r1 = rf(ctx, userID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -122,6 +439,24 @@ func (_m *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*
 	return r0, r1
 }
 
+// RevokeSession provides a mock function with given fields: ctx, userID, sessionID
+func (_m *AuthService) RevokeSession(ctx context.Context, userID string, sessionID string) error {
+	ret := _m.Called(ctx, userID, sessionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userID, sessionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // RevokeToken provides a mock function with given fields: ctx, token
 func (_m *AuthService) RevokeToken(ctx context.Context, token string) error {
 	ret := _m.Called(ctx, token)
@@ -140,6 +475,54 @@ func (_m *AuthService) RevokeToken(ctx context.Context, token string) error {
 	return r0
 }
 
+// SetMFARequired provides a mock function with given fields: ctx, userID, required
+func (_m *AuthService) SetMFARequired(ctx context.Context, userID string, required bool) error {
+	ret := _m.Called(ctx, userID, required)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetMFARequired")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) error); ok {
+		r0 = rf(ctx, userID, required)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StepUp provides a mock function with given fields: ctx, userID, sessionID, code, client
+func (_m *AuthService) StepUp(ctx context.Context, userID string, sessionID string, code string, client domain.ClientInfo) (*domain.AuthResponse, error) {
+	ret := _m.Called(ctx, userID, sessionID, code, client)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StepUp")
+	}
+
+	var r0 *domain.AuthResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, domain.ClientInfo) (*domain.AuthResponse, error)); ok {
+		return rf(ctx, userID, sessionID, code, client)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, domain.ClientInfo) *domain.AuthResponse); ok {
+		r0 = rf(ctx, userID, sessionID, code, client)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AuthResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, domain.ClientInfo) error); ok {
+		r1 = rf(ctx, userID, sessionID, code, client)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ValidateToken provides a mock function with given fields: ctx, token
 func (_m *AuthService) ValidateToken(ctx context.Context, token string) (*domain.User, error) {
 	ret := _m.Called(ctx, token)
@@ -170,6 +553,84 @@ func (_m *AuthService) ValidateToken(ctx context.Context, token string) (*domain
 	return r0, r1
 }
 
+// VerifyAccessToken provides a mock function with given fields: ctx, token
+func (_m *AuthService) VerifyAccessToken(ctx context.Context, token string) (*domain.AccessTokenClaims, error) {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyAccessToken")
+	}
+
+	var r0 *domain.AccessTokenClaims
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.AccessTokenClaims, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.AccessTokenClaims); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AccessTokenClaims)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerifyEmail provides a mock function with given fields: ctx, token
+func (_m *AuthService) VerifyEmail(ctx context.Context, token string) error {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyEmail")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// VerifyMFA provides a mock function with given fields: ctx, request, client
+func (_m *AuthService) VerifyMFA(ctx context.Context, request domain.MFAVerifyRequest, client domain.ClientInfo) (*domain.AuthResponse, error) {
+	ret := _m.Called(ctx, request, client)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyMFA")
+	}
+
+	var r0 *domain.AuthResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.MFAVerifyRequest, domain.ClientInfo) (*domain.AuthResponse, error)); ok {
+		return rf(ctx, request, client)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.MFAVerifyRequest, domain.ClientInfo) *domain.AuthResponse); ok {
+		r0 = rf(ctx, request, client)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AuthResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.MFAVerifyRequest, domain.ClientInfo) error); ok {
+		r1 = rf(ctx, request, client)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewAuthService creates a new instance of AuthService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewAuthService(t interface {
@@ -122,29 +122,29 @@ func (_m *CustomerService) GetByUserID(ctx context.Context, userID string) (*dom
 	return r0, r1
 }
 
-// List provides a mock function with given fields: ctx
-func (_m *CustomerService) List(ctx context.Context) ([]domain.Customer, error) {
-	ret := _m.Called(ctx)
+// List provides a mock function with given fields: ctx, query
+func (_m *CustomerService) List(ctx context.Context, query domain.ListQuery) (*domain.Page[domain.Customer], error) {
+	ret := _m.Called(ctx, query)
 
 	if len(ret) == 0 {
 		panic("no return value specified for List")
 	}
 
-	var r0 []domain.Customer
+	var r0 *domain.Page[domain.Customer]
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.Customer, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ListQuery) (*domain.Page[domain.Customer], error)); ok {
+		return rf(ctx, query)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []domain.Customer); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ListQuery) *domain.Page[domain.Customer]); ok {
+		r0 = rf(ctx, query)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]domain.Customer)
+			r0 = ret.Get(0).(*domain.Page[domain.Customer])
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, domain.ListQuery) error); ok {
+		r1 = rf(ctx, query)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -14,6 +14,24 @@ type NotificationService struct {
 	mock.Mock
 }
 
+// SendLowStockAlert provides a mock function with given fields: ctx, alerts
+func (_m *NotificationService) SendLowStockAlert(ctx context.Context, alerts []domain.LowStockAlert) error {
+	ret := _m.Called(ctx, alerts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendLowStockAlert")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.LowStockAlert) error); ok {
+		r0 = rf(ctx, alerts)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SendOrderConfirmation provides a mock function with given fields: ctx, order
 func (_m *NotificationService) SendOrderConfirmation(ctx context.Context, order *domain.Order) error {
 	ret := _m.Called(ctx, order)
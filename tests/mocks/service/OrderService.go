@@ -32,6 +32,24 @@ func (_m *OrderService) AddOrderItem(ctx context.Context, orderID string, item *
 	return r0
 }
 
+// Checkout provides a mock function with given fields: ctx, order, cartID
+func (_m *OrderService) Checkout(ctx context.Context, order *domain.Order, cartID string) error {
+	ret := _m.Called(ctx, order, cartID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Checkout")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Order, string) error); ok {
+		r0 = rf(ctx, order, cartID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Create provides a mock function with given fields: ctx, order
 func (_m *OrderService) Create(ctx context.Context, order *domain.Order) error {
 	ret := _m.Called(ctx, order)
@@ -80,29 +98,59 @@ func (_m *OrderService) GetByID(ctx context.Context, id string) (*domain.Order,
 	return r0, r1
 }
 
-// List provides a mock function with given fields: ctx
-func (_m *OrderService) List(ctx context.Context) ([]domain.Order, error) {
-	ret := _m.Called(ctx)
+// GetStatusHistory provides a mock function with given fields: ctx, id
+func (_m *OrderService) GetStatusHistory(ctx context.Context, id string) ([]domain.OrderStatusChange, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStatusHistory")
+	}
+
+	var r0 []domain.OrderStatusChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.OrderStatusChange, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.OrderStatusChange); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.OrderStatusChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, query
+func (_m *OrderService) List(ctx context.Context, query domain.ListQuery) (*domain.Page[domain.Order], error) {
+	ret := _m.Called(ctx, query)
 
 	if len(ret) == 0 {
 		panic("no return value specified for List")
 	}
 
-	var r0 []domain.Order
+	var r0 *domain.Page[domain.Order]
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.Order, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ListQuery) (*domain.Page[domain.Order], error)); ok {
+		return rf(ctx, query)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []domain.Order); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ListQuery) *domain.Page[domain.Order]); ok {
+		r0 = rf(ctx, query)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]domain.Order)
+			r0 = ret.Get(0).(*domain.Page[domain.Order])
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, domain.ListQuery) error); ok {
+		r1 = rf(ctx, query)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -110,29 +158,29 @@ func (_m *OrderService) List(ctx context.Context) ([]domain.Order, error) {
 	return r0, r1
 }
 
-// ListByCustomerID provides a mock function with given fields: ctx, customerID
-func (_m *OrderService) ListByCustomerID(ctx context.Context, customerID string) ([]domain.Order, error) {
-	ret := _m.Called(ctx, customerID)
+// ListByCustomerID provides a mock function with given fields: ctx, customerID, query
+func (_m *OrderService) ListByCustomerID(ctx context.Context, customerID string, query domain.ListQuery) (*domain.Page[domain.Order], error) {
+	ret := _m.Called(ctx, customerID, query)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ListByCustomerID")
 	}
 
-	var r0 []domain.Order
+	var r0 *domain.Page[domain.Order]
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.Order, error)); ok {
-		return rf(ctx, customerID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.ListQuery) (*domain.Page[domain.Order], error)); ok {
+		return rf(ctx, customerID, query)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.Order); ok {
-		r0 = rf(ctx, customerID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.ListQuery) *domain.Page[domain.Order]); ok {
+		r0 = rf(ctx, customerID, query)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]domain.Order)
+			r0 = ret.Get(0).(*domain.Page[domain.Order])
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, customerID)
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.ListQuery) error); ok {
+		r1 = rf(ctx, customerID, query)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -140,6 +188,24 @@ func (_m *OrderService) ListByCustomerID(ctx context.Context, customerID string)
 	return r0, r1
 }
 
+// Refund provides a mock function with given fields: ctx, id, actorID, reason, lines
+func (_m *OrderService) Refund(ctx context.Context, id string, actorID string, reason string, lines []domain.RefundLine) error {
+	ret := _m.Called(ctx, id, actorID, reason, lines)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Refund")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, []domain.RefundLine) error); ok {
+		r0 = rf(ctx, id, actorID, reason, lines)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // RemoveOrderItem provides a mock function with given fields: ctx, orderID, itemID
 func (_m *OrderService) RemoveOrderItem(ctx context.Context, orderID string, itemID string) error {
 	ret := _m.Called(ctx, orderID, itemID)
@@ -176,17 +242,17 @@ func (_m *OrderService) Update(ctx context.Context, order *domain.Order) error {
 	return r0
 }
 
-// UpdateStatus provides a mock function with given fields: ctx, id, status
-func (_m *OrderService) UpdateStatus(ctx context.Context, id string, status domain.OrderStatus) error {
-	ret := _m.Called(ctx, id, status)
+// UpdateStatus provides a mock function with given fields: ctx, id, status, actorID, reason
+func (_m *OrderService) UpdateStatus(ctx context.Context, id string, status domain.OrderStatus, actorID string, reason string) error {
+	ret := _m.Called(ctx, id, status, actorID, reason)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateStatus")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, domain.OrderStatus) error); ok {
-		r0 = rf(ctx, id, status)
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.OrderStatus, string, string) error); ok {
+		r0 = rf(ctx, id, status, actorID, reason)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -80,29 +80,29 @@ func (_m *ProductService) GetByID(ctx context.Context, id string) (*domain.Produ
 	return r0, r1
 }
 
-// List provides a mock function with given fields: ctx
-func (_m *ProductService) List(ctx context.Context) ([]domain.Product, error) {
-	ret := _m.Called(ctx)
+// List provides a mock function with given fields: ctx, query
+func (_m *ProductService) List(ctx context.Context, query domain.ListQuery) (*domain.Page[domain.Product], error) {
+	ret := _m.Called(ctx, query)
 
 	if len(ret) == 0 {
 		panic("no return value specified for List")
 	}
 
-	var r0 []domain.Product
+	var r0 *domain.Page[domain.Product]
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.Product, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ListQuery) (*domain.Page[domain.Product], error)); ok {
+		return rf(ctx, query)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []domain.Product); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ListQuery) *domain.Page[domain.Product]); ok {
+		r0 = rf(ctx, query)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]domain.Product)
+			r0 = ret.Get(0).(*domain.Page[domain.Product])
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, domain.ListQuery) error); ok {
+		r1 = rf(ctx, query)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -110,29 +110,59 @@ func (_m *ProductService) List(ctx context.Context) ([]domain.Product, error) {
 	return r0, r1
 }
 
-// ListByCategoryID provides a mock function with given fields: ctx, categoryID
-func (_m *ProductService) ListByCategoryID(ctx context.Context, categoryID string) ([]domain.Product, error) {
-	ret := _m.Called(ctx, categoryID)
+// ListByCategoryID provides a mock function with given fields: ctx, categoryID, query
+func (_m *ProductService) ListByCategoryID(ctx context.Context, categoryID string, query domain.ListQuery) (*domain.Page[domain.Product], error) {
+	ret := _m.Called(ctx, categoryID, query)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ListByCategoryID")
 	}
 
-	var r0 []domain.Product
+	var r0 *domain.Page[domain.Product]
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.Product, error)); ok {
-		return rf(ctx, categoryID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.ListQuery) (*domain.Page[domain.Product], error)); ok {
+		return rf(ctx, categoryID, query)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.Product); ok {
-		r0 = rf(ctx, categoryID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.ListQuery) *domain.Page[domain.Product]); ok {
+		r0 = rf(ctx, categoryID, query)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]domain.Product)
+			r0 = ret.Get(0).(*domain.Page[domain.Product])
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, categoryID)
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.ListQuery) error); ok {
+		r1 = rf(ctx, categoryID, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Search provides a mock function with given fields: ctx, term, query
+func (_m *ProductService) Search(ctx context.Context, term string, query domain.ListQuery) (*domain.Page[domain.ProductSearchResult], error) {
+	ret := _m.Called(ctx, term, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Search")
+	}
+
+	var r0 *domain.Page[domain.ProductSearchResult]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.ListQuery) (*domain.Page[domain.ProductSearchResult], error)); ok {
+		return rf(ctx, term, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.ListQuery) *domain.Page[domain.ProductSearchResult]); ok {
+		r0 = rf(ctx, term, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Page[domain.ProductSearchResult])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.ListQuery) error); ok {
+		r1 = rf(ctx, term, query)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -158,17 +188,17 @@ func (_m *ProductService) Update(ctx context.Context, product *domain.Product) e
 	return r0
 }
 
-// UpdateStock provides a mock function with given fields: ctx, id, quantity
-func (_m *ProductService) UpdateStock(ctx context.Context, id string, quantity int) error {
-	ret := _m.Called(ctx, id, quantity)
+// UpdateStock provides a mock function with given fields: ctx, movement
+func (_m *ProductService) UpdateStock(ctx context.Context, movement *domain.StockMovement) error {
+	ret := _m.Called(ctx, movement)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateStock")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, int) error); ok {
-		r0 = rf(ctx, id, quantity)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.StockMovement) error); ok {
+		r0 = rf(ctx, movement)
 	} else {
 		r0 = ret.Error(0)
 	}
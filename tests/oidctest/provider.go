@@ -0,0 +1,240 @@
+// Package oidctest runs a fake OpenID Connect provider for tests. It
+// serves discovery metadata, a JWKS, a token endpoint and a userinfo
+// endpoint, and signs id_tokens with an RSA key that can be rotated.
+package oidctest
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type Provider struct {
+	*httptest.Server
+	ClientID string
+
+	// JWKSRequests counts fetches of the key set.
+	JWKSRequests atomic.Int32
+
+	mu sync.Mutex
+	// idTokenClaims are returned in the id_token of the next code
+	// exchange or refresh; nil leaves the id_token out.
+	idTokenClaims map[string]any
+	userInfo      map[string]any
+	refreshToken  string
+	tokenRequest  url.Values
+	key           *rsa.PrivateKey
+	keyID         string
+	keyCount      int
+}
+
+func New(t *testing.T, clientID string) *Provider {
+	t.Helper()
+
+	p := &Provider{
+		ClientID:     clientID,
+		refreshToken: "provider-refresh-token",
+	}
+	p.RotateKey(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", p.discovery)
+	mux.HandleFunc("/jwks", p.jwks)
+	mux.HandleFunc("/token", p.token)
+	mux.HandleFunc("/userinfo", p.userinfo)
+	p.Server = httptest.NewServer(mux)
+	t.Cleanup(p.Close)
+
+	return p
+}
+
+// Issuer is the provider's issuer identifier.
+func (p *Provider) Issuer() string {
+	return p.URL
+}
+
+// Claims returns valid id_token claims for subject, bound to nonce.
+func (p *Provider) Claims(subject, email, nonce string) map[string]any {
+	now := time.Now()
+	return map[string]any{
+		"iss":            p.Issuer(),
+		"sub":            subject,
+		"aud":            p.ClientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+		"nonce":          nonce,
+		"email":          email,
+		"email_verified": true,
+		"name":           "Test User",
+	}
+}
+
+// SetIDTokenClaims sets the claims of the id_token the token endpoint
+// returns next.
+func (p *Provider) SetIDTokenClaims(claims map[string]any) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.idTokenClaims = claims
+}
+
+// SetUserInfo sets the profile returned by the userinfo endpoint.
+func (p *Provider) SetUserInfo(userInfo map[string]any) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.userInfo = userInfo
+}
+
+// TokenRequest returns the form of the last token endpoint request.
+func (p *Provider) TokenRequest() url.Values {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.tokenRequest
+}
+
+// RotateKey replaces the signing key with a new one under a new kid.
+// Tokens signed before the rotation no longer verify.
+func (p *Provider) RotateKey(t *testing.T) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate RSA key: %v", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.keyCount++
+	p.key = key
+	p.keyID = fmt.Sprintf("key-%d", p.keyCount)
+}
+
+// Sign signs claims as an RS256 id_token with the current key.
+func (p *Provider) Sign(t *testing.T, claims map[string]any) string {
+	t.Helper()
+
+	p.mu.Lock()
+	key, keyID := p.key, p.keyID
+	p.mu.Unlock()
+
+	token, err := sign(key, keyID, claims)
+	if err != nil {
+		t.Fatalf("failed to sign id_token: %v", err)
+	}
+	return token
+}
+
+func sign(key *rsa.PrivateKey, keyID string, claims map[string]any) (string, error) {
+	header, err := json.Marshal(map[string]string{
+		"alg": "RS256",
+		"typ": "JWT",
+		"kid": keyID,
+	})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := encode(header) + "." + encode(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return signingInput + "." + encode(signature), nil
+}
+
+func (p *Provider) discovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, map[string]any{
+		"issuer":                                p.Issuer(),
+		"authorization_endpoint":                p.URL + "/authorize",
+		"token_endpoint":                        p.URL + "/token",
+		"userinfo_endpoint":                     p.URL + "/userinfo",
+		"jwks_uri":                              p.URL + "/jwks",
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (p *Provider) jwks(w http.ResponseWriter, _ *http.Request) {
+	p.JWKSRequests.Add(1)
+
+	p.mu.Lock()
+	key, keyID := p.key, p.keyID
+	p.mu.Unlock()
+
+	writeJSON(w, map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": keyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   encode(key.N.Bytes()),
+			"e":   encode(big.NewInt(int64(key.E)).Bytes()),
+		}},
+	})
+}
+
+func (p *Provider) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	p.mu.Lock()
+	p.tokenRequest = r.PostForm
+	claims, key, keyID := p.idTokenClaims, p.key, p.keyID
+	refreshToken := p.refreshToken
+	p.mu.Unlock()
+
+	resp := map[string]any{
+		"access_token":  "provider-access-token",
+		"token_type":    "Bearer",
+		"expires_in":    3600,
+		"refresh_token": refreshToken,
+	}
+	if claims != nil {
+		idToken, err := sign(key, keyID, claims)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		resp["id_token"] = idToken
+	}
+
+	writeJSON(w, resp)
+}
+
+func (p *Provider) userinfo(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	userInfo := p.userInfo
+	p.mu.Unlock()
+
+	if userInfo == nil {
+		http.NotFound(w, r)
+		return
+	}
+	writeJSON(w, userInfo)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -5,6 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 )
 
 // Response represents the standard API response format
@@ -12,9 +15,19 @@ import (
 type Response struct {
 	Success bool        `json:"success"         example:"true"`
 	Data    interface{} `json:"data,omitempty"`
+	Meta    *ListMeta   `json:"meta,omitempty"`
 	Error   string      `json:"error,omitempty" example:"Invalid request parameters"`
 }
 
+// ListMeta carries pagination details for list responses
+// @Description Pagination metadata for list responses
+type ListMeta struct {
+	Total      int64  `json:"total"                 example:"120"`
+	Limit      int    `json:"limit"                 example:"20"`
+	Page       int    `json:"page,omitempty"        example:"1"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
 var ErrResponseEncoding = errors.New("failed to encode response")
 
 func SuccessResponse(
@@ -38,6 +51,81 @@ func SuccessResponse(
 	return nil
 }
 
+// ListResponse writes one page of a list together with its metadata
+// and advertises the neighbouring pages in a Link header (RFC 8288).
+func ListResponse(
+	w http.ResponseWriter,
+	r *http.Request,
+	data interface{},
+	meta ListMeta,
+	status int,
+) error {
+	if link := linkHeader(r.URL, meta); link != "" {
+		w.Header().Set("Link", link)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(Response{
+		Success: true,
+		Data:    data,
+		Meta:    &meta,
+	}); err != nil {
+		return fmt.Errorf(
+			"%w: %v",
+			ErrResponseEncoding,
+			err,
+		)
+	}
+	return nil
+}
+
+func linkHeader(current *url.URL, meta ListMeta) string {
+	pageURL := func(set map[string]string) string {
+		u := *current
+		query := u.Query()
+		query.Del("page")
+		query.Del("cursor")
+		for key, value := range set {
+			query.Set(key, value)
+		}
+		u.RawQuery = query.Encode()
+		return u.RequestURI()
+	}
+
+	var links []string
+	add := func(rel string, set map[string]string) {
+		links = append(
+			links,
+			fmt.Sprintf("<%s>; rel=\"%s\"", pageURL(set), rel),
+		)
+	}
+
+	if meta.NextCursor != "" {
+		if meta.Page > 0 {
+			add("next", map[string]string{
+				"page": strconv.Itoa(meta.Page + 1),
+			})
+		} else {
+			add("next", map[string]string{"cursor": meta.NextCursor})
+		}
+	}
+	if meta.Page > 1 {
+		add("prev", map[string]string{
+			"page": strconv.Itoa(meta.Page - 1),
+		})
+	}
+	add("first", map[string]string{"page": "1"})
+	if meta.Page > 0 && meta.Limit > 0 && meta.Total > 0 {
+		last := (meta.Total + int64(meta.Limit) - 1) / int64(meta.Limit)
+		add("last", map[string]string{
+			"page": strconv.FormatInt(last, 10),
+		})
+	}
+
+	return strings.Join(links, ", ")
+}
+
 func ErrorResponse(
 	w http.ResponseWriter,
 	message string,
@@ -58,3 +146,28 @@ func ErrorResponse(
 	}
 	return nil
 }
+
+// ErrorResponseWithData writes an error response that also carries
+// structured details about the failure in the data field.
+func ErrorResponseWithData(
+	w http.ResponseWriter,
+	message string,
+	data interface{},
+	status int,
+) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(Response{
+		Success: false,
+		Data:    data,
+		Error:   message,
+	}); err != nil {
+		return fmt.Errorf(
+			"%w: %v",
+			ErrResponseEncoding,
+			err,
+		)
+	}
+	return nil
+}
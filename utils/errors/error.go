@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strings"
+	"time"
 )
 
 type ErrorResponse struct {
@@ -14,10 +16,22 @@ type ErrorResponse struct {
 
 const (
 	// Authentication Errors
-	ErrCodeInvalidCredentials = "AUTH001"
-	ErrCodeTokenExpired       = "AUTH002"
-	ErrCodeInvalidToken       = "AUTH003"
-	ErrCodeUnauthorized       = "AUTH004"
+	ErrCodeInvalidCredentials  = "AUTH001"
+	ErrCodeTokenExpired        = "AUTH002"
+	ErrCodeInvalidToken        = "AUTH003"
+	ErrCodeUnauthorized        = "AUTH004"
+	ErrCodeUnknownProvider     = "AUTH005"
+	ErrCodeInvalidIDToken      = "AUTH006"
+	ErrCodeInvalidLoginState   = "AUTH007"
+	ErrCodeInvalidRedirect     = "AUTH008"
+	ErrCodeSessionNotFound     = "AUTH009"
+	ErrCodeAccountLocked       = "AUTH010"
+	ErrCodeEmailNotVerified    = "AUTH011"
+	ErrCodeInvalidMFACode      = "AUTH012"
+	ErrCodeInvalidMFAChallenge = "AUTH013"
+	ErrCodeMFANotEnabled       = "AUTH014"
+	ErrCodeMFAAlreadyEnabled   = "AUTH015"
+	ErrCodeMFARequired         = "AUTH016"
 
 	// Customer Errors
 	ErrCodeCustomerNotFound    = "CUST001"
@@ -36,6 +50,35 @@ const (
 	ErrCodeOrderStatusInvalid = "ORD003"
 	ErrCodeEmptyOrder         = "ORD004"
 
+	// Cart Errors
+	ErrCodeCartNotFound     = "CART001"
+	ErrCodeInvalidCartData  = "CART002"
+	ErrCodeEmptyCart        = "CART003"
+	ErrCodeCartPriceChanged = "CART004"
+
+	// Outbox Errors
+	ErrCodeOutboxEventNotFound = "OUTBOX001"
+	ErrCodeOutboxReplayInvalid = "OUTBOX002"
+
+	// Role Errors
+	ErrCodeRoleNotFound    = "ROLE001"
+	ErrCodeInvalidRoleData = "ROLE002"
+	ErrCodeRoleExists      = "ROLE003"
+	ErrCodeRoleInUse       = "ROLE004"
+	ErrCodeBuiltInRole     = "ROLE005"
+
+	// API key Errors
+	ErrCodeAPIKeyNotFound         = "APIKEY001"
+	ErrCodeInvalidAPIKeyData      = "APIKEY002"
+	ErrCodeInvalidAPIKey          = "APIKEY003"
+	ErrCodeAPIKeyPermissionDenied = "APIKEY004"
+
+	// Idempotency Errors
+	ErrCodeIdempotencyKeyNotFound = "IDEM001"
+	ErrCodeInvalidIdempotencyKey  = "IDEM002"
+	ErrCodeIdempotencyKeyInUse    = "IDEM003"
+	ErrCodeIdempotencyKeyMismatch = "IDEM004"
+
 	// Category Errors
 	ErrCodeCategoryNotFound    = "CAT001"
 	ErrCodeInvalidCategoryData = "CAT002"
@@ -53,6 +96,7 @@ const (
 	ErrCodeInvalidInput  = "VAL001"
 	ErrCodeRequired      = "VAL002"
 	ErrCodeInvalidFormat = "VAL003"
+	ErrCodeInvalidQuery  = "VAL004"
 )
 
 // Pre-defined application errors
@@ -68,6 +112,25 @@ var (
 	ErrTokenNotFound = errors.New("token not found")
 	ErrTokenRevoked  = errors.New("token has been revoked")
 
+	ErrUnknownProvider   = errors.New("unknown identity provider")
+	ErrInvalidIDToken    = errors.New("invalid id token")
+	ErrInvalidLoginState = errors.New(
+		"login session is invalid or has expired",
+	)
+	ErrInvalidRedirect  = errors.New("redirect target is not allowed")
+	ErrSessionNotFound  = errors.New("session not found")
+	ErrAccountLocked    = errors.New("account is temporarily locked")
+	ErrEmailNotVerified = errors.New(
+		"email address is not verified",
+	)
+	ErrInvalidMFACode      = errors.New("invalid MFA code")
+	ErrInvalidMFAChallenge = errors.New(
+		"MFA challenge is invalid or has expired",
+	)
+	ErrMFANotEnabled     = errors.New("MFA is not enabled")
+	ErrMFAAlreadyEnabled = errors.New("MFA is already enabled")
+	ErrMFARequired       = errors.New("MFA is required for this account")
+
 	// Customer Errors
 	ErrCustomerNotFound    = errors.New("customer not found")
 	ErrInvalidCustomerData = errors.New("invalid customer data")
@@ -87,6 +150,45 @@ var (
 	ErrEmptyOrder           = errors.New("order is empty")
 	ErrOrderItemNotFound    = errors.New("order item not found")
 	ErrInvalidOrderItemData = errors.New("invalid order item data")
+	ErrOrderPriceChanged    = errors.New("order prices have changed")
+
+	// Cart Errors
+	ErrCartNotFound     = errors.New("cart not found")
+	ErrCartItemNotFound = errors.New("cart item not found")
+	ErrInvalidCartData  = errors.New("invalid cart data")
+	ErrEmptyCart        = errors.New("cart is empty")
+	ErrCartPriceChanged = errors.New("cart prices have changed")
+
+	// Outbox Errors
+	ErrOutboxEventNotFound      = errors.New("outbox event not found")
+	ErrOutboxEventNotReplayable = errors.New(
+		"only dead-lettered outbox events can be replayed",
+	)
+
+	// Role Errors
+	ErrRoleNotFound    = errors.New("role not found")
+	ErrInvalidRoleData = errors.New("invalid role data")
+	ErrRoleExists      = errors.New("role already exists")
+	ErrRoleInUse       = errors.New("role is assigned to users")
+	ErrBuiltInRole     = errors.New("built-in role cannot be changed this way")
+
+	// API key Errors
+	ErrAPIKeyNotFound         = errors.New("API key not found")
+	ErrInvalidAPIKeyData      = errors.New("invalid API key data")
+	ErrInvalidAPIKey          = errors.New("invalid or expired API key")
+	ErrAPIKeyPermissionDenied = errors.New(
+		"cannot grant a permission you do not hold",
+	)
+
+	// Idempotency Errors
+	ErrIdempotencyKeyNotFound = errors.New("idempotency key not found")
+	ErrInvalidIdempotencyKey  = errors.New("invalid idempotency key")
+	ErrIdempotencyKeyInUse    = errors.New(
+		"a request with this idempotency key is still in progress",
+	)
+	ErrIdempotencyKeyMismatch = errors.New(
+		"idempotency key was used for a different request",
+	)
 
 	// Category Errors
 	ErrCategoryNotFound    = errors.New("category not found")
@@ -102,8 +204,64 @@ var (
 	ErrDBQuery        = errors.New("database query error")
 	ErrDBDuplicate    = errors.New("duplicate entry in database")
 	ErrInternalServer = errors.New("internal server error")
+
+	// Validation Errors
+	ErrInvalidListQuery = errors.New("invalid list query")
 )
 
+// StockShortfall describes a single product that cannot cover the
+// requested quantity.
+type StockShortfall struct {
+	ProductID string `json:"product_id"`
+	Requested int    `json:"requested"`
+	Available int    `json:"available"`
+}
+
+// InsufficientStockError is returned when one or more items of an order
+// cannot be reserved. It matches ErrInsufficientStock with errors.Is.
+type InsufficientStockError struct {
+	Shortfalls []StockShortfall
+}
+
+func (e *InsufficientStockError) Error() string {
+	parts := make([]string, 0, len(e.Shortfalls))
+	for _, s := range e.Shortfalls {
+		parts = append(parts, fmt.Sprintf(
+			"product %s - requested %d, available %d",
+			s.ProductID,
+			s.Requested,
+			s.Available,
+		))
+	}
+	return fmt.Sprintf(
+		"%s: %s",
+		ErrInsufficientStock,
+		strings.Join(parts, "; "),
+	)
+}
+
+func (e *InsufficientStockError) Unwrap() error {
+	return ErrInsufficientStock
+}
+
+// AccountLockedError is returned for a login to an account locked after
+// too many failed attempts. It matches ErrAccountLocked with errors.Is.
+type AccountLockedError struct {
+	Until time.Time
+}
+
+func (e *AccountLockedError) Error() string {
+	return fmt.Sprintf(
+		"%s until %s",
+		ErrAccountLocked,
+		e.Until.UTC().Format(time.RFC3339),
+	)
+}
+
+func (e *AccountLockedError) Unwrap() error {
+	return ErrAccountLocked
+}
+
 func LogError(
 	err error,
 	message string,
@@ -127,7 +285,13 @@ func IsNotFound(err error) bool {
 		errors.Is(err, ErrOrderNotFound) ||
 		errors.Is(err, ErrCategoryNotFound) ||
 		errors.Is(err, ErrOrderItemNotFound) ||
-		errors.Is(err, ErrUserNotFound)
+		errors.Is(err, ErrCartNotFound) ||
+		errors.Is(err, ErrCartItemNotFound) ||
+		errors.Is(err, ErrUserNotFound) ||
+		errors.Is(err, ErrSessionNotFound) ||
+		errors.Is(err, ErrRoleNotFound) ||
+		errors.Is(err, ErrAPIKeyNotFound) ||
+		errors.Is(err, ErrIdempotencyKeyNotFound)
 }
 
 func IsDuplicate(err error) bool {
@@ -140,12 +304,23 @@ func IsValidationError(err error) bool {
 		errors.Is(err, ErrInvalidProductData) ||
 		errors.Is(err, ErrInvalidOrderData) ||
 		errors.Is(err, ErrInvalidCategoryData) ||
-		errors.Is(err, ErrInvalidUserData)
+		errors.Is(err, ErrInvalidCartData) ||
+		errors.Is(err, ErrInvalidUserData) ||
+		errors.Is(err, ErrInvalidRoleData) ||
+		errors.Is(err, ErrInvalidAPIKeyData) ||
+		errors.Is(err, ErrInvalidIdempotencyKey) ||
+		errors.Is(err, ErrInvalidListQuery)
 }
 
 func IsAuthenticationError(err error) bool {
 	return errors.Is(err, ErrInvalidCredentials) ||
 		errors.Is(err, ErrTokenExpired) ||
 		errors.Is(err, ErrInvalidToken) ||
-		errors.Is(err, ErrUnauthorized)
+		errors.Is(err, ErrInvalidIDToken) ||
+		errors.Is(err, ErrUnauthorized) ||
+		errors.Is(err, ErrAccountLocked) ||
+		errors.Is(err, ErrEmailNotVerified) ||
+		errors.Is(err, ErrInvalidMFACode) ||
+		errors.Is(err, ErrInvalidMFAChallenge) ||
+		errors.Is(err, ErrInvalidAPIKey)
 }
@@ -1,6 +1,9 @@
 package hash
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 
 	"golang.org/x/crypto/bcrypt"
@@ -29,3 +32,12 @@ func Verify(password, hashedPassword string) bool {
 	)
 	return err == nil
 }
+
+// Token returns the hex encoded HMAC-SHA256 of token under key. Unlike
+// Generate the result is deterministic, so stored tokens can be looked up
+// by it.
+func Token(key []byte, token string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(token))
+	return hex.EncodeToString(mac.Sum(nil))
+}
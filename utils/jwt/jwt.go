@@ -0,0 +1,247 @@
+// Package jwt issues and verifies the service's own access tokens.
+//
+// Tokens are signed with Ed25519 (alg EdDSA). Every signing key is
+// identified by a key ID that is written to the token header as kid, so
+// keys can be rotated: the active key signs new tokens while retired keys
+// keep verifying tokens issued before the rotation. The public half of
+// every key is published as a JSON Web Key Set for other services.
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	Algorithm = "EdDSA"
+
+	// keyDerivationLabel separates signing key derivation from any other
+	// use of the configured secrets.
+	keyDerivationLabel = "grocery-service jwt signing key "
+)
+
+var (
+	ErrMalformedToken   = errors.New("malformed token")
+	ErrUnknownKey       = errors.New("unknown signing key")
+	ErrInvalidSignature = errors.New("invalid token signature")
+	ErrTokenExpired     = errors.New("token has expired")
+	ErrTokenNotYetValid = errors.New("token is not valid yet")
+	ErrInvalidIssuer    = errors.New("invalid token issuer")
+)
+
+type (
+	// Key is a signing key source: the Ed25519 key pair is derived from
+	// Secret and ID, so the same configuration yields the same keys on
+	// every replica.
+	Key struct {
+		ID     string
+		Secret string
+	}
+
+	Claims struct {
+		Issuer    string `json:"iss"`
+		Subject   string `json:"sub"`
+		ID        string `json:"jti"`
+		IssuedAt  int64  `json:"iat"`
+		NotBefore int64  `json:"nbf,omitempty"`
+		ExpiresAt int64  `json:"exp"`
+		Email     string `json:"email,omitempty"`
+		Role      string `json:"role,omitempty"`
+		// SessionID identifies the login the token was issued for.
+		SessionID string `json:"sid,omitempty"`
+		// Permissions are those of Role when the token was issued.
+		Permissions []string `json:"perms,omitempty"`
+		// MFAAt is when the user passed MFA, if the token was issued
+		// right after they did.
+		MFAAt int64 `json:"mfa_at,omitempty"`
+	}
+
+	// JWK is the public half of a signing key (RFC 8037 OKP key).
+	JWK struct {
+		KeyType   string `json:"kty"`
+		Curve     string `json:"crv"`
+		X         string `json:"x"`
+		KeyID     string `json:"kid"`
+		Use       string `json:"use"`
+		Algorithm string `json:"alg"`
+	}
+
+	JWKSet struct {
+		Keys []JWK `json:"keys"`
+	}
+
+	// KeySet signs with its active key and verifies with any of its keys.
+	KeySet struct {
+		active  string
+		private ed25519.PrivateKey
+		public  map[string]ed25519.PublicKey
+		order   []string
+	}
+
+	header struct {
+		Algorithm string `json:"alg"`
+		Type      string `json:"typ"`
+		KeyID     string `json:"kid"`
+	}
+)
+
+// ParseKey parses a retired key written as "kid:secret".
+func ParseKey(entry string) (Key, error) {
+	id, secret, ok := strings.Cut(strings.TrimSpace(entry), ":")
+	if !ok || id == "" || secret == "" {
+		return Key{}, fmt.Errorf(
+			"invalid signing key %q: expected kid:secret",
+			entry,
+		)
+	}
+	return Key{ID: id, Secret: secret}, nil
+}
+
+func NewKeySet(active Key, retired ...Key) (*KeySet, error) {
+	if active.ID == "" {
+		return nil, errors.New("active signing key ID is required")
+	}
+
+	set := &KeySet{
+		active: active.ID,
+		public: make(map[string]ed25519.PublicKey, len(retired)+1),
+	}
+	set.private = deriveKey(active)
+	set.add(active.ID, set.private.Public().(ed25519.PublicKey))
+
+	for _, key := range retired {
+		if _, exists := set.public[key.ID]; exists {
+			return nil, fmt.Errorf("duplicate signing key ID %q", key.ID)
+		}
+		set.add(key.ID, deriveKey(key).Public().(ed25519.PublicKey))
+	}
+
+	return set, nil
+}
+
+func deriveKey(key Key) ed25519.PrivateKey {
+	mac := hmac.New(sha256.New, []byte(key.Secret))
+	mac.Write([]byte(keyDerivationLabel + key.ID))
+	return ed25519.NewKeyFromSeed(mac.Sum(nil))
+}
+
+func (s *KeySet) add(id string, key ed25519.PublicKey) {
+	s.public[id] = key
+	s.order = append(s.order, id)
+}
+
+// ActiveKeyID returns the ID of the key that signs new tokens.
+func (s *KeySet) ActiveKeyID() string {
+	return s.active
+}
+
+func (s *KeySet) Sign(claims Claims) (string, error) {
+	headerJSON, err := json.Marshal(header{
+		Algorithm: Algorithm,
+		Type:      "JWT",
+		KeyID:     s.active,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode token header: %w", err)
+	}
+
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode token claims: %w", err)
+	}
+
+	signingInput := encode(headerJSON) + "." + encode(claimsJSON)
+	signature := ed25519.Sign(s.private, []byte(signingInput))
+
+	return signingInput + "." + encode(signature), nil
+}
+
+// Verify checks the signature, issuer and validity window of a token and
+// returns its claims.
+func (s *KeySet) Verify(
+	token string,
+	issuer string,
+	now time.Time,
+) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformedToken
+	}
+
+	var h header
+	if err := decodeJSON(parts[0], &h); err != nil {
+		return nil, ErrMalformedToken
+	}
+	if h.Algorithm != Algorithm {
+		return nil, fmt.Errorf(
+			"%w: unsupported algorithm %q",
+			ErrMalformedToken,
+			h.Algorithm,
+		)
+	}
+
+	key, ok := s.public[h.KeyID]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+	if !ed25519.Verify(key, []byte(parts[0]+"."+parts[1]), signature) {
+		return nil, ErrInvalidSignature
+	}
+
+	var claims Claims
+	if err := decodeJSON(parts[1], &claims); err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	if claims.Issuer != issuer {
+		return nil, ErrInvalidIssuer
+	}
+	if now.Unix() >= claims.ExpiresAt {
+		return nil, ErrTokenExpired
+	}
+	if claims.NotBefore != 0 && now.Unix() < claims.NotBefore {
+		return nil, ErrTokenNotYetValid
+	}
+
+	return &claims, nil
+}
+
+// JWKS returns the public keys of the set, active key first.
+func (s *KeySet) JWKS() JWKSet {
+	keys := make([]JWK, 0, len(s.order))
+	for _, id := range s.order {
+		keys = append(keys, JWK{
+			KeyType:   "OKP",
+			Curve:     "Ed25519",
+			X:         encode(s.public[id]),
+			KeyID:     id,
+			Use:       "sig",
+			Algorithm: Algorithm,
+		})
+	}
+	return JWKSet{Keys: keys}
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeJSON(segment string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,178 @@
+// Package ratelimit implements token bucket rate limiting with
+// pluggable bucket storage.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/grocery-service/utils/logger"
+)
+
+// Policy allows Limit requests per Window. A client's bucket holds up to
+// Limit tokens and refills evenly over Window, so bursts of up to Limit
+// requests are allowed. Name keeps the buckets of different policies
+// apart.
+type Policy struct {
+	Name   string
+	Limit  int
+	Window time.Duration
+}
+
+// rate is the number of tokens the policy refills per second.
+func (p Policy) rate() float64 {
+	return float64(p.Limit) / p.Window.Seconds()
+}
+
+// Bucket is the state of one client's token bucket.
+type Bucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+// Result is the outcome of taking a token. Remaining is the whole
+// number of tokens left, ResetAfter how long until the bucket is full
+// again and RetryAfter, for a denied request, how long until a token is
+// available.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	ResetAfter time.Duration
+	RetryAfter time.Duration
+}
+
+// Store keeps the buckets of every client. Stores must be safe for
+// concurrent use.
+type Store interface {
+	// Take spends a token from the bucket key under policy at now,
+	// creating the bucket full if it does not exist.
+	Take(
+		ctx context.Context,
+		key string,
+		policy Policy,
+		now time.Time,
+	) (Result, error)
+	// Prune deletes the buckets that are full again at now, which
+	// behave like missing ones, and returns how many were deleted.
+	Prune(ctx context.Context, now time.Time) (int64, error)
+}
+
+// Take refills bucket up to now and spends one token from it if there
+// is one. A nil bucket is a new, full one. It returns the updated bucket
+// with the result.
+func Take(bucket *Bucket, policy Policy, now time.Time) (Bucket, Result) {
+	limit := float64(policy.Limit)
+	rate := policy.rate()
+
+	next := Bucket{Tokens: limit, UpdatedAt: now}
+	if bucket != nil {
+		next = *bucket
+		// Clocks of different replicas may disagree slightly; never
+		// move a bucket back in time.
+		if elapsed := now.Sub(bucket.UpdatedAt); elapsed > 0 {
+			next.Tokens = math.Min(
+				limit,
+				bucket.Tokens+elapsed.Seconds()*rate,
+			)
+			next.UpdatedAt = now
+		}
+	}
+
+	result := Result{Limit: policy.Limit}
+	if next.Tokens >= 1 {
+		next.Tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - next.Tokens) / rate)
+	}
+	result.Remaining = int(next.Tokens)
+	result.ResetAfter = seconds((limit - next.Tokens) / rate)
+
+	return next, result
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// MemoryStore keeps buckets in memory. Each replica limits clients on
+// its own.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+}
+
+type memoryBucket struct {
+	Bucket
+	fullAt time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*memoryBucket)}
+}
+
+func (s *MemoryStore) Take(
+	_ context.Context,
+	key string,
+	policy Policy,
+	now time.Time,
+) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var current *Bucket
+	if b, ok := s.buckets[key]; ok {
+		current = &b.Bucket
+	}
+
+	next, result := Take(current, policy, now)
+	s.buckets[key] = &memoryBucket{
+		Bucket: next,
+		fullAt: now.Add(result.ResetAfter),
+	}
+
+	return result, nil
+}
+
+func (s *MemoryStore) Prune(_ context.Context, now time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var pruned int64
+	for key, b := range s.buckets {
+		if !now.Before(b.fullAt) {
+			delete(s.buckets, key)
+			pruned++
+		}
+	}
+	return pruned, nil
+}
+
+// RunPruner prunes store every interval until ctx is cancelled, so that
+// clients seen once do not keep buckets forever.
+func RunPruner(
+	ctx context.Context,
+	store Store,
+	interval time.Duration,
+) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := store.Prune(ctx, time.Now()); err != nil &&
+				ctx.Err() == nil {
+				logger.Warn(
+					"rate limit prune failed",
+					logger.Error64("error", err),
+				)
+			}
+		}
+	}
+}